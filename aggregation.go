@@ -0,0 +1,121 @@
+package polaris
+
+import (
+	"net"
+	"slices"
+	"strconv"
+
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Aggregation module
+// Responsibility: merging the instances of a service registered in several namespaces into
+// one list, labelling every instance with the namespace it was discovered in.
+
+// sourceNamespaceMetadataKey is the metadata key carrying the namespace an aggregated
+// instance was discovered in.
+const sourceNamespaceMetadataKey = "source_namespace"
+
+// aggregatedInstance is an instance discovered by aggregated discovery. Its metadata carries
+// the namespace it was discovered in.
+type aggregatedInstance struct {
+	model.Instance
+	metadata map[string]string
+}
+
+// GetMetadata returns the metadata of the instance with its source namespace.
+func (i *aggregatedInstance) GetMetadata() map[string]string {
+	return i.metadata
+}
+
+// withSourceNamespace returns instance with namespace set as its source_namespace metadata.
+func withSourceNamespace(instance model.Instance, namespace string) model.Instance {
+	metadata := instance.GetMetadata()
+	labelled := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		labelled[k] = v
+	}
+	labelled[sourceNamespaceMetadataKey] = namespace
+	return &aggregatedInstance{Instance: instance, metadata: labelled}
+}
+
+// WithNamespaces makes GetServiceInstances merge the instances of the service in namespaces
+// with those in the plugin namespace, overriding discovery_aggregation for the call. Every
+// instance gets a source_namespace metadata entry naming the namespace it was found in.
+func WithNamespaces(namespaces ...string) InstanceOption {
+	return func(o *instanceOptions) {
+		o.namespaces = append(o.namespaces, namespaces...)
+	}
+}
+
+// aggregatedNamespaces returns the namespaces of discovery_aggregation to merge for
+// serviceName, or nil when the service is not aggregated.
+func (p *PlugPolaris) aggregatedNamespaces(serviceName string) []string {
+	p.mu.RLock()
+	cfg := p.currentConf().GetDiscoveryAggregation()
+	p.mu.RUnlock()
+	if len(cfg.GetNamespaces()) == 0 {
+		return nil
+	}
+	if services := cfg.GetServices(); len(services) > 0 && !slices.Contains(services, serviceName) {
+		return nil
+	}
+	return cfg.GetNamespaces()
+}
+
+// aggregationNamespaces returns namespace followed by the distinct, non-empty namespaces of
+// extra, in order.
+func aggregationNamespaces(namespace string, extra []string) []string {
+	namespaces := []string{namespace}
+	for _, ns := range extra {
+		if ns != "" && !slices.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// aggregateInstances merges the instances returned by query for every namespace, in order.
+// An instance at a host and port already returned by an earlier namespace is skipped.
+// Namespaces whose query fails are left out; an error is only returned when every query
+// fails.
+func (p *PlugPolaris) aggregateInstances(
+	serviceName string, namespaces []string, query func(namespace string) ([]model.Instance, error),
+) ([]model.Instance, error) {
+	var merged []model.Instance
+	var firstErr error
+	succeeded := false
+	seen := make(map[string]bool)
+	for _, namespace := range namespaces {
+		instances, err := query(namespace)
+		if err != nil {
+			if DefaultErrorClassifier(err) == ErrorClassIgnore {
+				log.Debugf("Skipping namespace %s for service %s: %v", namespace, serviceName, p.redactError(err))
+			} else {
+				log.Warnf("Failed to get instances for service %s in namespace %s: %v",
+					serviceName, namespace, p.redactError(err))
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		succeeded = true
+		for _, inst := range instances {
+			if inst == nil {
+				continue
+			}
+			address := net.JoinHostPort(inst.GetHost(), strconv.Itoa(int(inst.GetPort())))
+			if seen[address] {
+				continue
+			}
+			seen[address] = true
+			merged = append(merged, withSourceNamespace(inst, namespace))
+		}
+	}
+	if !succeeded {
+		return nil, firstErr
+	}
+	return merged, nil
+}
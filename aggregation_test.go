@@ -0,0 +1,77 @@
+package polaris
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateInstances(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	discovered := map[string][]model.Instance{
+		"default": {
+			NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080}),
+		},
+		"legacy": {
+			NewStaticInstance("legacy", "orders", &conf.FallbackInstance{
+				Host: "10.0.0.1", Port: 8080, Metadata: map[string]string{"zone": "a"},
+			}),
+			NewStaticInstance("legacy", "orders", &conf.FallbackInstance{
+				Host: "10.0.1.1", Port: 8080, Metadata: map[string]string{"zone": "b"},
+			}),
+		},
+	}
+	query := func(namespace string) ([]model.Instance, error) {
+		if instances, ok := discovered[namespace]; ok {
+			return instances, nil
+		}
+		return nil, errors.New("unavailable")
+	}
+
+	namespaces := aggregationNamespaces("default", []string{"legacy", "", "default", "archive"})
+	assert.Equal(t, []string{"default", "legacy", "archive"}, namespaces)
+	instances, err := plugin.aggregateInstances("orders", namespaces, query)
+	require.NoError(t, err)
+	require.Len(t, instances, 2, "the duplicate address in legacy is skipped and archive failed")
+	assert.Equal(t, "default", instances[0].GetMetadata()[sourceNamespaceMetadataKey])
+	assert.Equal(t, "legacy", instances[1].GetMetadata()[sourceNamespaceMetadataKey])
+	assert.Equal(t, "b", instances[1].GetMetadata()["zone"])
+	assert.Equal(t, "10.0.1.1", instances[1].GetHost())
+	assert.NotContains(t, discovered["legacy"][1].GetMetadata(), sourceNamespaceMetadataKey,
+		"discovered metadata is copied")
+
+	_, err = plugin.aggregateInstances("orders", []string{"archive"}, query)
+	assert.Error(t, err)
+}
+
+func TestAggregatedNamespaces(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	assert.Nil(t, plugin.aggregatedNamespaces("orders"))
+
+	plugin.currentConf().DiscoveryAggregation = &conf.DiscoveryAggregation{Namespaces: []string{"legacy"}}
+	assert.Equal(t, []string{"legacy"}, plugin.aggregatedNamespaces("orders"))
+	plugin.currentConf().DiscoveryAggregation.Services = []string{"payments"}
+	assert.Nil(t, plugin.aggregatedNamespaces("orders"))
+	assert.Equal(t, []string{"legacy"}, plugin.aggregatedNamespaces("payments"))
+
+	var options instanceOptions
+	WithNamespaces("legacy", "archive")(&options)
+	assert.Equal(t, []string{"legacy", "archive"}, options.namespaces)
+}
+
+func TestValidator_DiscoveryAggregation(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, DiscoveryAggregation: &conf.DiscoveryAggregation{
+		Namespaces: []string{"legacy", " "},
+	}}
+	var fields []string
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.Contains(t, fields, "discovery_aggregation.namespaces[1]")
+	assert.NotContains(t, fields, "discovery_aggregation.namespaces[0]")
+}
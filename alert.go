@@ -0,0 +1,341 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// AlertSeverity is the severity of an alert
+type AlertSeverity string
+
+const (
+	AlertSeverityInfo     AlertSeverity = conf.AlertSeverityInfo
+	AlertSeverityWarning  AlertSeverity = conf.AlertSeverityWarning
+	AlertSeverityCritical AlertSeverity = conf.AlertSeverityCritical
+)
+
+// rank orders severities from info to critical; unknown severities rank as warning
+func (s AlertSeverity) rank() int {
+	if i := slices.Index(conf.SupportedAlertSeverities, string(s)); i >= 0 {
+		return i
+	}
+	return 1
+}
+
+// AlertType identifies what raised an alert
+type AlertType string
+
+const (
+	// AlertServiceWatchError is raised when a service watcher fails
+	AlertServiceWatchError AlertType = "service_watch_error"
+	// AlertConfigWatchError is raised when a config watcher fails
+	AlertConfigWatchError AlertType = "config_watch_error"
+	// AlertDegradation is raised when the plugin enters a fallback mode
+	AlertDegradation AlertType = "degradation"
+	// AlertCircuitBreakerOpen is raised when a circuit breaker of the plugin opens
+	AlertCircuitBreakerOpen AlertType = "circuit_breaker_open"
+	// AlertHeartbeatFailure is raised when the heartbeats of an instance reach
+	// heartbeat.failure_threshold consecutive failures
+	AlertHeartbeatFailure AlertType = "heartbeat_failure"
+	// AlertSDKRecovery is raised when self-healing rebuilds the SDK context
+	AlertSDKRecovery AlertType = "sdk_recovery"
+	// AlertClusterSwitchover is raised when the plugin switches to the standby Polaris
+	// cluster or back to the primary
+	AlertClusterSwitchover AlertType = "cluster_switchover"
+)
+
+// Alert is an alert raised by the plugin
+type Alert struct {
+	Type     AlertType     `json:"type"`
+	Severity AlertSeverity `json:"severity"`
+	// Subject is the service, file:group, circuit breaker or host:port the alert is about
+	Subject   string `json:"subject"`
+	Namespace string `json:"namespace"`
+	Message   string `json:"message"`
+	Error     string `json:"error,omitempty"`
+	// Suppressed is the number of repeats of the alert deduplicated since it was last sent
+	Suppressed int       `json:"suppressed,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Text renders the alert as a single chat message
+func (a Alert) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] Polaris %s: %s (subject %s, namespace %s)",
+		strings.ToUpper(string(a.Severity)), a.Type, a.Message, a.Subject, a.Namespace)
+	if a.Error != "" {
+		fmt.Fprintf(&b, "\nError: %s", a.Error)
+	}
+	if a.Suppressed > 0 {
+		fmt.Fprintf(&b, "\n%d repeats suppressed", a.Suppressed)
+	}
+	return b.String()
+}
+
+// Alerter sends alerts to an on-call or chat system
+type Alerter interface {
+	Alert(ctx context.Context, alert Alert) error
+}
+
+// AlerterFunc adapts a function to an Alerter
+type AlerterFunc func(ctx context.Context, alert Alert) error
+
+// Alert calls f(ctx, alert)
+func (f AlerterFunc) Alert(ctx context.Context, alert Alert) error {
+	return f(ctx, alert)
+}
+
+// webhookAlerter POSTs alerts rendered by payload to a URL
+type webhookAlerter struct {
+	url     string
+	client  *http.Client
+	payload func(Alert) any
+}
+
+// newWebhookAlerter returns an alerter POSTing payload(alert) as JSON to url
+func newWebhookAlerter(url string, client *http.Client, payload func(Alert) any) Alerter {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &webhookAlerter{url: url, client: client, payload: payload}
+}
+
+func (a *webhookAlerter) Alert(ctx context.Context, alert Alert) error {
+	data, err := json.Marshal(a.payload(alert))
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, a.client, a.url, nil, data)
+}
+
+// NewWebhookAlerter returns an alerter POSTing every alert as JSON to url. A nil client
+// uses one with a 5s timeout.
+func NewWebhookAlerter(url string, client *http.Client) Alerter {
+	return newWebhookAlerter(url, client, func(alert Alert) any { return alert })
+}
+
+// NewSlackAlerter returns an alerter posting every alert as a message to a Slack incoming
+// webhook
+func NewSlackAlerter(url string, client *http.Client) Alerter {
+	return newWebhookAlerter(url, client, func(alert Alert) any {
+		return map[string]any{"text": alert.Text()}
+	})
+}
+
+// NewDingTalkAlerter returns an alerter posting every alert as a text message to a DingTalk
+// robot webhook. Robots secured by keyword need the keyword "Polaris".
+func NewDingTalkAlerter(url string, client *http.Client) Alerter {
+	return newWebhookAlerter(url, client, func(alert Alert) any {
+		return map[string]any{"msgtype": "text", "text": map[string]string{"content": alert.Text()}}
+	})
+}
+
+// NewFeishuAlerter returns an alerter posting every alert as a text message to a Feishu
+// (Lark) bot webhook
+func NewFeishuAlerter(url string, client *http.Client) Alerter {
+	return newWebhookAlerter(url, client, func(alert Alert) any {
+		return map[string]any{"msg_type": "text", "content": map[string]string{"text": alert.Text()}}
+	})
+}
+
+// registeredAlerter is an alerter with the lowest severity it receives
+type registeredAlerter struct {
+	alerter     Alerter
+	minSeverity AlertSeverity
+}
+
+// alertDedup tracks when an alert was last sent and how many repeats were suppressed since
+type alertDedup struct {
+	sent       time.Time
+	suppressed int
+}
+
+// AddAlerter registers alerter under name, replacing the alerter registered under the same
+// name. It receives the alerts of minSeverity and above; an empty minSeverity means warning.
+func (p *PlugPolaris) AddAlerter(name string, alerter Alerter, minSeverity AlertSeverity) error {
+	if name == "" || alerter == nil {
+		return NewConfigError("alerter name and alerter are required")
+	}
+	if minSeverity == "" {
+		minSeverity = AlertSeverityWarning
+	}
+	p.alertMutex.Lock()
+	defer p.alertMutex.Unlock()
+	if p.alerters == nil {
+		p.alerters = make(map[string]registeredAlerter)
+	}
+	p.alerters[name] = registeredAlerter{alerter: alerter, minSeverity: minSeverity}
+	return nil
+}
+
+// RemoveAlerter removes the alerter registered under name and reports whether there was one
+func (p *PlugPolaris) RemoveAlerter(name string) bool {
+	p.alertMutex.Lock()
+	defer p.alertMutex.Unlock()
+	_, ok := p.alerters[name]
+	delete(p.alerters, name)
+	return ok
+}
+
+// SetAlertDedupWindow sets how long repeats of an alert for the same subject are
+// suppressed, overriding alerting.dedup_window. Zero restores the configured window.
+func (p *PlugPolaris) SetAlertDedupWindow(window time.Duration) {
+	p.alertMutex.Lock()
+	defer p.alertMutex.Unlock()
+	p.alertDedupWindow = window
+}
+
+// initAlerters registers the webhooks of the alerting config as "alerting-<index>"
+func (p *PlugPolaris) initAlerters() {
+	for i, webhook := range p.currentConf().GetAlerting().GetWebhooks() {
+		var alerter Alerter
+		switch webhook.GetType() {
+		case conf.AlertWebhookSlack:
+			alerter = NewSlackAlerter(webhook.GetUrl(), nil)
+		case conf.AlertWebhookDingTalk:
+			alerter = NewDingTalkAlerter(webhook.GetUrl(), nil)
+		case conf.AlertWebhookFeishu:
+			alerter = NewFeishuAlerter(webhook.GetUrl(), nil)
+		default:
+			alerter = NewWebhookAlerter(webhook.GetUrl(), nil)
+		}
+		_ = p.AddAlerter(fmt.Sprintf("alerting-%d", i), alerter, AlertSeverity(webhook.GetMinSeverity()))
+	}
+}
+
+// alertWindow returns the dedup window, from SetAlertDedupWindow or the alerting config
+func (p *PlugPolaris) alertWindow() time.Duration {
+	if p.alertDedupWindow > 0 {
+		return p.alertDedupWindow
+	}
+	if d := p.currentConf().GetAlerting().GetDedupWindow(); d != nil && d.AsDuration() > 0 {
+		return d.AsDuration()
+	}
+	return conf.DefaultAlertDedupWindow
+}
+
+// raiseAlert logs alert and sends it to the alerters of its severity in the background,
+// unless an alert of the same type and subject was sent within the dedup window
+func (p *PlugPolaris) raiseAlert(alert Alert) {
+	now := time.Now()
+	if alert.Timestamp.IsZero() {
+		alert.Timestamp = now
+	}
+	if alert.Namespace == "" {
+		alert.Namespace = p.currentConf().GetNamespace()
+	}
+	alert.Message, alert.Error = p.redact(alert.Message), p.redact(alert.Error)
+	if alert.Severity == AlertSeverityCritical {
+		log.Errorf("Alert: type=%s subject=%s namespace=%s severity=%s message=%s err=%s",
+			alert.Type, alert.Subject, alert.Namespace, alert.Severity, alert.Message, alert.Error)
+	} else {
+		log.Warnf("Alert: type=%s subject=%s namespace=%s severity=%s message=%s err=%s",
+			alert.Type, alert.Subject, alert.Namespace, alert.Severity, alert.Message, alert.Error)
+	}
+
+	p.alertMutex.Lock()
+	if p.alertDedups == nil {
+		p.alertDedups = make(map[string]alertDedup)
+	}
+	key := string(alert.Type) + "|" + alert.Subject
+	dedup := p.alertDedups[key]
+	if !dedup.sent.IsZero() && now.Sub(dedup.sent) < p.alertWindow() {
+		dedup.suppressed++
+		p.alertDedups[key] = dedup
+		p.alertMutex.Unlock()
+		return
+	}
+	alert.Suppressed = dedup.suppressed
+	p.alertDedups[key] = alertDedup{sent: now}
+	targets := make(map[string]Alerter, len(p.alerters))
+	for name, registered := range p.alerters {
+		if alert.Severity.rank() >= registered.minSeverity.rank() {
+			targets[name] = registered.alerter
+		}
+	}
+	p.alertMutex.Unlock()
+
+	if len(targets) == 0 {
+		return
+	}
+	go func() {
+		for name, alerter := range targets {
+			if err := alerter.Alert(context.Background(), alert); err != nil {
+				log.Warnf("Alerter %s failed to send %s alert for %s: %v", name, alert.Type, alert.Subject, err)
+			}
+		}
+	}()
+}
+
+// sendServiceWatchAlert raises a warning alert for a service-watcher error.
+func (p *PlugPolaris) sendServiceWatchAlert(serviceName string, err error) {
+	p.raiseAlert(Alert{
+		Type:     AlertServiceWatchError,
+		Severity: AlertSeverityWarning,
+		Subject:  serviceName,
+		Message:  "service watch failed",
+		Error:    err.Error(),
+	})
+}
+
+// sendConfigWatchAlert raises a warning alert for a config-watcher error.
+func (p *PlugPolaris) sendConfigWatchAlert(fileName, group string, err error) {
+	p.raiseAlert(Alert{
+		Type:     AlertConfigWatchError,
+		Severity: AlertSeverityWarning,
+		Subject:  configWatcherName(fileName, group),
+		Message:  "config watch failed",
+		Error:    err.Error(),
+	})
+}
+
+// publishDegradation publishes a degradation event and raises a warning alert for it
+func (p *PlugPolaris) publishDegradation(event *DegradationEvent) {
+	p.publishEvent(event)
+	p.raiseAlert(Alert{
+		Type:      AlertDegradation,
+		Severity:  AlertSeverityWarning,
+		Subject:   eventSubject(event),
+		Namespace: event.Namespace,
+		Message:   fmt.Sprintf("%s, falling back to %s", event.DegradationType, event.FallbackStrategy),
+		Error:     event.Error,
+		Timestamp: event.Timestamp,
+	})
+}
+
+// alertOnBreakerOpen raises a critical alert whenever the breaker registered under name opens
+func (p *PlugPolaris) alertOnBreakerOpen(name string, cb *CircuitBreaker) {
+	cb.OnStateChange(func(from, to CircuitState) {
+		if to != CircuitStateOpen {
+			return
+		}
+		p.raiseAlert(Alert{
+			Type:     AlertCircuitBreakerOpen,
+			Severity: AlertSeverityCritical,
+			Subject:  name,
+			Message:  fmt.Sprintf("circuit breaker opened from %s, rejecting calls to Polaris", from),
+		})
+	})
+}
+
+// sendHeartbeatAlert raises a critical alert for an instance whose heartbeats keep failing
+func (p *PlugPolaris) sendHeartbeatAlert(failure HeartbeatFailure) {
+	alert := Alert{
+		Type:     AlertHeartbeatFailure,
+		Severity: AlertSeverityCritical,
+		Subject:  fmt.Sprintf("%s@%s:%d", failure.Service, failure.Host, failure.Port),
+		Message:  fmt.Sprintf("%d consecutive heartbeats failed, the instance may be removed", failure.ConsecutiveFailures),
+	}
+	if failure.Err != nil {
+		alert.Error = failure.Err.Error()
+	}
+	p.raiseAlert(alert)
+}
@@ -0,0 +1,122 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// alertChannel returns an alerter sending the alerts it receives to a channel
+func alertChannel() (Alerter, chan Alert) {
+	alerts := make(chan Alert, 8)
+	return AlerterFunc(func(_ context.Context, alert Alert) error {
+		alerts <- alert
+		return nil
+	}), alerts
+}
+
+func receiveAlert(t *testing.T, alerts chan Alert) Alert {
+	t.Helper()
+	select {
+	case alert := <-alerts:
+		return alert
+	case <-time.After(time.Second):
+		t.Fatal("alert not sent")
+		return Alert{}
+	}
+}
+
+func TestRaiseAlert_Dedup(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	alerter, alerts := alertChannel()
+	require.NoError(t, plugin.AddAlerter("test", alerter, ""))
+	plugin.SetAlertDedupWindow(time.Hour)
+
+	plugin.sendServiceWatchAlert("orders", errors.New("unavailable"))
+	alert := receiveAlert(t, alerts)
+	assert.Equal(t, AlertServiceWatchError, alert.Type)
+	assert.Equal(t, AlertSeverityWarning, alert.Severity)
+	assert.Equal(t, "orders", alert.Subject)
+	assert.Equal(t, "default", alert.Namespace)
+
+	plugin.sendServiceWatchAlert("orders", errors.New("unavailable"))
+	plugin.sendServiceWatchAlert("orders", errors.New("unavailable"))
+	plugin.sendServiceWatchAlert("payments", errors.New("unavailable"))
+	assert.Equal(t, "payments", receiveAlert(t, alerts).Subject)
+
+	// Past the window the alert is sent again with the number of suppressed repeats
+	plugin.alertMutex.Lock()
+	dedup := plugin.alertDedups["service_watch_error|orders"]
+	dedup.sent = time.Now().Add(-2 * time.Hour)
+	plugin.alertDedups["service_watch_error|orders"] = dedup
+	plugin.alertMutex.Unlock()
+	plugin.sendServiceWatchAlert("orders", errors.New("unavailable"))
+	assert.Equal(t, 2, receiveAlert(t, alerts).Suppressed)
+}
+
+func TestRaiseAlert_Severity(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	alerter, alerts := alertChannel()
+	require.NoError(t, plugin.AddAlerter("pager", alerter, AlertSeverityCritical))
+
+	plugin.sendConfigWatchAlert("app.yaml", "orders", errors.New("unavailable"))
+	plugin.sendHeartbeatAlert(HeartbeatFailure{Service: "orders", Host: "10.0.0.1", Port: 8080, ConsecutiveFailures: 3})
+	alert := receiveAlert(t, alerts)
+	assert.Equal(t, AlertHeartbeatFailure, alert.Type, "warnings are not sent to critical alerters")
+	assert.Equal(t, "orders@10.0.0.1:8080", alert.Subject)
+
+	cb := NewCircuitBreaker(0.5, time.Minute)
+	plugin.alertOnBreakerOpen("polaris", cb)
+	cb.ForceOpen()
+	alert = receiveAlert(t, alerts)
+	assert.Equal(t, AlertCircuitBreakerOpen, alert.Type)
+	assert.Equal(t, "polaris", alert.Subject)
+
+	assert.True(t, plugin.RemoveAlerter("pager"))
+	assert.False(t, plugin.RemoveAlerter("pager"))
+}
+
+func TestChatAlerters(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload = nil
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer server.Close()
+
+	alert := Alert{Type: AlertDegradation, Severity: AlertSeverityWarning, Subject: "orders", Message: "service_watch_failure, falling back to cache_only"}
+	require.NoError(t, NewSlackAlerter(server.URL, nil).Alert(context.Background(), alert))
+	assert.Contains(t, payload["text"], "[WARNING] Polaris degradation")
+
+	require.NoError(t, NewDingTalkAlerter(server.URL, nil).Alert(context.Background(), alert))
+	assert.Equal(t, "text", payload["msgtype"])
+	assert.Contains(t, payload["text"].(map[string]any)["content"], "orders")
+
+	require.NoError(t, NewFeishuAlerter(server.URL, nil).Alert(context.Background(), alert))
+	assert.Equal(t, "text", payload["msg_type"])
+
+	require.NoError(t, NewWebhookAlerter(server.URL, nil).Alert(context.Background(), alert))
+	assert.Equal(t, "degradation", payload["type"])
+}
+
+func TestValidator_Alerting(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, Alerting: &conf.Alerting{
+		Webhooks: []*conf.AlertWebhook{{Type: "pagerduty", Url: "https://example.com"}},
+	}}
+	assert.False(t, NewValidator(cfg).Validate().IsValid)
+
+	cfg.Alerting.Webhooks = []*conf.AlertWebhook{{Type: conf.AlertWebhookSlack, Url: "https://hooks.slack.com/x", MinSeverity: "critical"}}
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		assert.NotContains(t, e.Field, "alerting")
+	}
+}
@@ -4,10 +4,18 @@
 package polaris
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-kratos/kratos/contrib/polaris/v2"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/registry"
+	kgrpc "github.com/go-kratos/kratos/v2/transport/grpc"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
 	"github.com/go-lynx/lynx"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
@@ -21,12 +29,12 @@ func GetPolarisPlugin() (*PlugPolaris, error) {
 }
 
 // GetServiceInstances returns service instances.
-func GetServiceInstances(serviceName string) ([]model.Instance, error) {
+func GetServiceInstances(serviceName string, opts ...InstanceOption) ([]model.Instance, error) {
 	p := GetPlugin()
 	if p == nil {
 		return nil, fmt.Errorf("polaris plugin not found")
 	}
-	return p.GetServiceInstances(serviceName)
+	return p.GetServiceInstances(serviceName, opts...)
 }
 
 // GetConfig fetches configuration by file name and group.
@@ -39,6 +47,76 @@ func GetConfig(fileName, group string) (string, error) {
 	return p.GetConfigValue(fileName, group)
 }
 
+// GetConfigValueOrDefault returns the content of a config file, or defaultValue when it is unavailable.
+// Global API: read configuration with a fallback value.
+func GetConfigValueOrDefault(fileName, group, defaultValue string) string {
+	p := GetPlugin()
+	if p == nil {
+		return defaultValue
+	}
+	return p.GetConfigValueOrDefault(fileName, group, defaultValue)
+}
+
+// GetConfigInto fetches configuration by file name and group and unmarshals it into out.
+// Global API: retrieve and decode a JSON or YAML config file.
+func GetConfigInto(fileName, group string, out any) error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.GetConfigInto(fileName, group, out)
+}
+
+// GetMergedConfigSource returns a Kratos config source that merges the main and additional config files.
+// Global API: load service_config files by priority and watch them as one source.
+func GetMergedConfigSource() (config.Source, error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("polaris plugin not found")
+	}
+	return p.GetMergedConfigSource()
+}
+
+// ListConfigFiles lists the config files of a group with their release metadata.
+// Global API: enumerate config files through the Polaris config OpenAPI.
+func ListConfigFiles(group string) ([]ConfigFileInfo, error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("polaris plugin not found")
+	}
+	return p.ListConfigFiles(group)
+}
+
+// RegisterReloadable registers a handler called with the new content whenever a config file changes.
+// Global API: hot-reload application components from Polaris configuration.
+func RegisterReloadable(fileName, group string, fn func(content string) error) error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.RegisterReloadable(fileName, group, fn)
+}
+
+// RegisterConfigValidator registers a validator that must accept a config file change before it is applied.
+// Global API: reject bad configuration before reload handlers see it.
+func RegisterConfigValidator(fileName, group string, fn func(content string) error) error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.RegisterConfigValidator(fileName, group, fn)
+}
+
+// PublishConfig creates or updates a configuration file and releases it.
+// Global API: write config content through the Polaris config OpenAPI.
+func PublishConfig(ctx context.Context, fileName, group, content string) error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.PublishConfig(ctx, fileName, group, content)
+}
+
 // WatchService watches service changes.
 // Global API: watch change events of the specified service.
 func WatchService(serviceName string) (*ServiceWatcher, error) {
@@ -69,6 +147,86 @@ func CheckRateLimit(serviceName string, labels map[string]string) (bool, error)
 	return p.CheckRateLimit(serviceName, labels)
 }
 
+// CheckOutboundRateLimit checks whether this application may call a target service.
+// Global API: respect the quotas assigned to this application as a consumer.
+func CheckOutboundRateLimit(targetService string, labels map[string]string) (bool, error) {
+	p := GetPlugin()
+	if p == nil {
+		return false, fmt.Errorf("polaris plugin not found")
+	}
+	return p.CheckOutboundRateLimit(targetService, labels)
+}
+
+// AcquireQuota requests a rate limit quota and returns the structured result without waiting.
+// Global API: rate limiting with wait-then-proceed semantics.
+func AcquireQuota(serviceName string, labels map[string]string) (*QuotaResult, error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("polaris plugin not found")
+	}
+	return p.AcquireQuota(serviceName, labels)
+}
+
+// AcquireConcurrencySlot takes a concurrency slot for a call and returns its release function.
+// Global API: cap the calls in flight, e.g. of long-running streams.
+func AcquireConcurrencySlot(ctx context.Context, labels map[string]string) (func(), error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("polaris plugin not found")
+	}
+	return p.AcquireConcurrencySlot(ctx, labels)
+}
+
+// GetRateLimitRules returns the rate limit rules of a service, or of the running application.
+// Global API: inspect which rate limit rules are active.
+func GetRateLimitRules(serviceName string) ([]RateLimitRule, error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("polaris plugin not found")
+	}
+	return p.GetRateLimitRules(serviceName)
+}
+
+// ReportCallResult reports the outcome of a call to a Polaris instance for circuit breaking.
+// Global API: feed server-defined circuit breaking rules.
+func ReportCallResult(instance model.Instance, err error, latency time.Duration) error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.ReportCallResult(instance, err, latency)
+}
+
+// GetCircuitBreakers returns the registry of the plugin's circuit breakers.
+// Global API: force breakers open or closed during incidents.
+func GetCircuitBreakers() (*CircuitBreakerRegistry, error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("polaris plugin not found")
+	}
+	return p.CircuitBreakers(), nil
+}
+
+// Isolate takes the instances registered through the plugin out of rotation.
+// Global API: switch the local node into maintenance mode.
+func Isolate() error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.Isolate()
+}
+
+// Unisolate returns the instances registered through the plugin to rotation.
+// Global API: switch the local node out of maintenance mode.
+func Unisolate() error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.Unisolate()
+}
+
 // GetMetrics returns plugin metrics.
 // Global API: get metrics exposed by the plugin.
 func GetMetrics() *Metrics {
@@ -97,6 +255,11 @@ func GetPolaris() *polaris.Polaris {
 	if p == nil {
 		return nil
 	}
+	if p.IsInitialized() {
+		if err := p.ensureConnected(); err != nil {
+			log.Warnf("Failed to connect to Polaris: %v", p.redactError(err))
+		}
+	}
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 	return p.polaris
@@ -118,3 +281,185 @@ func GetPlugin() *PlugPolaris {
 	}
 	return nil
 }
+
+// Subscribe subscribes to typed plugin events.
+// Global API: receive service, config, degradation and health events on a channel.
+func Subscribe(ctx context.Context, eventTypes ...EventType) (<-chan Event, error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("polaris plugin not found")
+	}
+	return p.Subscribe(ctx, eventTypes...)
+}
+
+// CheckHealthReport performs a health check and returns its per-component report.
+// Global API: break down SDK, discovery, config API, registration and heartbeat health.
+func CheckHealthReport(ctx context.Context) (HealthReport, error) {
+	p := GetPlugin()
+	if p == nil {
+		return HealthReport{}, fmt.Errorf("polaris plugin not found")
+	}
+	return p.CheckHealthReport(ctx)
+}
+
+// SubscribeEvents subscribes to the plugin events matching filter, replaying buffered ones first.
+// Global API: catch up on recent instance, config, degradation and breaker events from application code.
+func SubscribeEvents(filter EventFilter) (<-chan Event, func(), error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, nil, fmt.Errorf("polaris plugin not found")
+	}
+	events, unsubscribe := p.SubscribeEvents(filter)
+	return events, unsubscribe, nil
+}
+
+// AddNotifier registers a notifier of plugin events under name.
+// Global API: fan out service and config changes to webhooks, Kafka or the Lynx event bus.
+func AddNotifier(name string, notifier Notifier, eventTypes ...EventType) error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.AddNotifier(name, notifier, eventTypes...)
+}
+
+// RemoveNotifier stops the notifier registered under name.
+// Global API: unregister a notifier added with AddNotifier.
+func RemoveNotifier(name string) (bool, error) {
+	p := GetPlugin()
+	if p == nil {
+		return false, fmt.Errorf("polaris plugin not found")
+	}
+	return p.RemoveNotifier(name), nil
+}
+
+// GetStats returns a snapshot of the runtime state of the plugin.
+// Global API: inspect watchers, caches, circuit breakers and retries of a running plugin.
+func GetStats() (*PluginStats, error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("polaris plugin not found")
+	}
+	return p.GetStats(), nil
+}
+
+// RefreshService fetches the instances of serviceName from Polaris now and caches them.
+// Global API: refresh a service without waiting for its watcher.
+func RefreshService(serviceName string) ([]model.Instance, error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("polaris plugin not found")
+	}
+	return p.RefreshService(serviceName)
+}
+
+// SetTokenProvider loads the Polaris token from provider now and on every refresh interval.
+// Global API: plug in Vault or Kubernetes secrets and rotate the token without a restart.
+func SetTokenProvider(provider TokenProvider) error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.SetTokenProvider(provider)
+}
+
+// SetOperationTokenProvider loads the token of a write operation from provider.
+// Global API: publish config or isolate instances with a token other than the read token.
+func SetOperationTokenProvider(operation TokenOperation, provider TokenProvider) error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.SetOperationTokenProvider(operation, provider)
+}
+
+// UpdatePolarisConfig replaces the plugin configuration at runtime.
+// Global API: tune weights, retries and circuit breaking without a restart.
+func UpdatePolarisConfig(newConf *conf.Polaris) error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.UpdatePolarisConfig(newConf)
+}
+
+// WaitReady blocks until the plugin is registered, required configs are loaded and
+// discovery caches are warm, or readiness.timeout passes.
+// Global API: hold back traffic until the Polaris wiring is ready.
+func WaitReady(ctx context.Context, opts ...ProbeOption) error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.WaitReady(ctx, opts...)
+}
+
+// Restart runs the cleanup of the plugin and initializes it again.
+// Global API: restart the Polaris wiring in place from tests or operator tooling.
+func Restart() error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.Restart()
+}
+
+// RegisterCleanupHook adds a hook run by the plugin cleanup before deregistration.
+// Global API: flush in-flight work on shutdown in a deterministic order.
+func RegisterCleanupHook(name string, fn func(ctx context.Context) error, priority int) error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.RegisterCleanupHook(name, fn, priority)
+}
+
+// BuildRegistrar returns a Kratos registrar backed by the plugin, registering with opts.
+// Global API: pass the plugin to kratos.Registrar before it has started.
+func BuildRegistrar(opts ...RegisterOption) (registry.Registrar, error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("polaris plugin not found")
+	}
+	return p.BuildRegistrar(opts...), nil
+}
+
+// BuildDiscovery returns a Kratos discovery backed by the plugin.
+// Global API: resolve discovery:/// endpoints through the plugin.
+func BuildDiscovery() (registry.Discovery, error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("polaris plugin not found")
+	}
+	return p.BuildDiscovery(), nil
+}
+
+// GRPCClientOptions returns the Kratos gRPC client options for calling targetService.
+// Global API: dial a service with discovery, routing, rate limiting and circuit breaking.
+func GRPCClientOptions(targetService string, opts ...ClientOption) ([]kgrpc.ClientOption, error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("polaris plugin not found")
+	}
+	return p.GRPCClientOptions(targetService, opts...), nil
+}
+
+// HTTPClientOptions returns the Kratos HTTP client options for calling targetService.
+// Global API: create an HTTP client with discovery, routing, rate limiting and circuit breaking.
+func HTTPClientOptions(targetService string, opts ...ClientOption) ([]khttp.ClientOption, error) {
+	p := GetPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("polaris plugin not found")
+	}
+	return p.HTTPClientOptions(targetService, opts...), nil
+}
+
+// ReportServiceContract publishes the API definition of a service to Polaris.
+// Global API: report the HTTP routes or gRPC methods of a service for interface-level governance.
+func ReportServiceContract(contract ServiceContract) error {
+	p := GetPlugin()
+	if p == nil {
+		return fmt.Errorf("polaris plugin not found")
+	}
+	return p.ReportServiceContract(contract)
+}
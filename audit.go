@@ -0,0 +1,411 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// AuditEventType identifies what an audit event records
+type AuditEventType string
+
+const (
+	// AuditServiceChanged records a new instance set of a watched service
+	AuditServiceChanged AuditEventType = "service_changed"
+	// AuditServiceWatchError records an error of a service watcher
+	AuditServiceWatchError AuditEventType = "service_watch_error"
+	// AuditConfigChanged records a new content of a watched config file
+	AuditConfigChanged AuditEventType = "config_changed"
+	// AuditConfigWatchError records an error of a config watcher
+	AuditConfigWatchError AuditEventType = "config_watch_error"
+	// AuditServiceRegistered records an endpoint registered by the plugin's registrar
+	AuditServiceRegistered AuditEventType = "service_registered"
+	// AuditServiceDeregistered records an endpoint deregistered by the plugin's registrar
+	AuditServiceDeregistered AuditEventType = "service_deregistered"
+	// AuditInstancesIsolated records the isolation of the plugin's instances
+	AuditInstancesIsolated AuditEventType = "instances_isolated"
+	// AuditInstancesUnisolated records the plugin's instances returning to rotation
+	AuditInstancesUnisolated AuditEventType = "instances_unisolated"
+	// AuditConfigUpdated records a config file content written through UpdateConfig
+	AuditConfigUpdated AuditEventType = "config_updated"
+	// AuditConfigReleased records a config file released through ReleaseConfig
+	AuditConfigReleased AuditEventType = "config_released"
+	// AuditConfigDeleted records a config file deleted through DeleteConfig
+	AuditConfigDeleted AuditEventType = "config_deleted"
+	// AuditServiceContractReported records a service contract reported through
+	// ReportServiceContract
+	AuditServiceContractReported AuditEventType = "service_contract_reported"
+)
+
+// AuditActorPolaris is the actor of the changes and errors the plugin observes from Polaris.
+// Registration, isolation and config write events are made by the local application and
+// carry its name.
+const AuditActorPolaris = "polaris"
+
+// AuditEvent is a structured audit record. Only the fields of its type are set.
+type AuditEvent struct {
+	Type      AuditEventType `json:"type"`
+	Timestamp time.Time      `json:"timestamp"`
+	Namespace string         `json:"namespace"`
+	Actor     string         `json:"actor"`
+
+	// Service events
+	Service    string             `json:"service,omitempty"`
+	Instances  []InstanceSnapshot `json:"instances,omitempty"`
+	InstanceID string             `json:"instance_id,omitempty"`
+	Endpoints  []string           `json:"endpoints,omitempty"`
+
+	// Config events
+	FileName       string `json:"file_name,omitempty"`
+	Group          string `json:"group,omitempty"`
+	ContentLength  int    `json:"content_length,omitempty"`
+	PreviousLength int    `json:"previous_length,omitempty"`
+	LinesAdded     int    `json:"lines_added,omitempty"`
+	LinesRemoved   int    `json:"lines_removed,omitempty"`
+
+	// Service contract events
+	ContractName     string `json:"contract_name,omitempty"`
+	ContractRevision string `json:"contract_revision,omitempty"`
+
+	// Error is the error of watch error events
+	Error string `json:"error,omitempty"`
+
+	// DryRun marks the changes of the application that were not sent to Polaris
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// subject returns the service, or file:group of config events, the event is about, or
+// its type for the isolation events
+func (e AuditEvent) subject() string {
+	if e.Service != "" {
+		return e.Service
+	}
+	if e.FileName == "" {
+		return string(e.Type)
+	}
+	return configWatcherName(e.FileName, e.Group)
+}
+
+// AuditSink receives the audit events of the plugin. Write is called synchronously from
+// the watcher and registrar goroutines, so sinks should return quickly. Sinks holding
+// resources implement io.Closer; the plugin closes the sink it built from the audit
+// config when it stops.
+type AuditSink interface {
+	Write(ctx context.Context, event AuditEvent) error
+}
+
+// AuditSinkFunc adapts a function to an AuditSink
+type AuditSinkFunc func(ctx context.Context, event AuditEvent) error
+
+// Write calls f(ctx, event)
+func (f AuditSinkFunc) Write(ctx context.Context, event AuditEvent) error {
+	return f(ctx, event)
+}
+
+// loggerAuditSink writes audit events as JSON to the Lynx logger
+type loggerAuditSink struct{}
+
+// NewLoggerAuditSink returns a sink logging every event as JSON through the Lynx logger,
+// watch errors at error level and other events at info level. It is the default sink.
+func NewLoggerAuditSink() AuditSink {
+	return loggerAuditSink{}
+}
+
+func (loggerAuditSink) Write(_ context.Context, event AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if event.Error != "" {
+		log.Errorf("Audit event: %s", data)
+	} else {
+		log.Infof("Audit event: %s", data)
+	}
+	return nil
+}
+
+// JSONLAuditSink appends audit events to a file, one JSON object per line
+type JSONLAuditSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewJSONLAuditSink opens path for appending, creating it if needed, and returns a sink
+// writing every event to it as a JSON line
+func NewJSONLAuditSink(path string) (*JSONLAuditSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file %s: %w", path, err)
+	}
+	return &JSONLAuditSink{file: file}, nil
+}
+
+// Write appends event to the file
+func (s *JSONLAuditSink) Write(_ context.Context, event AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the file
+func (s *JSONLAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// webhookAuditSink POSTs audit events to a URL
+type webhookAuditSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookAuditSink returns a sink POSTing every event as JSON to url. Responses other
+// than 2xx are errors. A nil client uses one with the default 5s timeout.
+func NewWebhookAuditSink(url string, client *http.Client) AuditSink {
+	if client == nil {
+		client = &http.Client{Timeout: conf.DefaultAuditWebhookTimeout}
+	}
+	return &webhookAuditSink{url: url, client: client}
+}
+
+func (s *webhookAuditSink) Write(ctx context.Context, event AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.client, s.url, nil, data)
+}
+
+// NewPublisherAuditSink returns a sink publishing every event as JSON through publish,
+// keyed by the service, or file:group of config events, so that a Kafka producer keeps
+// the events of each service and config file in order
+func NewPublisherAuditSink(publish MessagePublisher) AuditSink {
+	return AuditSinkFunc(func(ctx context.Context, event AuditEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		return publish(ctx, event.subject(), data)
+	})
+}
+
+// discardAuditSink drops every event, for the "none" sink
+var discardAuditSink = AuditSinkFunc(func(context.Context, AuditEvent) error { return nil })
+
+// newConfiguredAuditSink returns the sink of the audit config
+func newConfiguredAuditSink(cfg *conf.Audit) (AuditSink, error) {
+	switch sink := cfg.GetSink(); sink {
+	case conf.AuditSinkFile:
+		return NewJSONLAuditSink(cfg.GetPath())
+	case conf.AuditSinkWebhook:
+		timeout := conf.DefaultAuditWebhookTimeout
+		if d := cfg.GetTimeout(); d != nil && d.AsDuration() > 0 {
+			timeout = d.AsDuration()
+		}
+		return NewWebhookAuditSink(cfg.GetUrl(), &http.Client{Timeout: timeout}), nil
+	case conf.AuditSinkNone:
+		return discardAuditSink, nil
+	case "", conf.AuditSinkLog:
+	default:
+		log.Warnf("Unknown audit sink %q, using %s", sink, conf.AuditSinkLog)
+	}
+	return NewLoggerAuditSink(), nil
+}
+
+// SetAuditSink sets the sink audit events are written to, e.g. NewPublisherAuditSink
+// with a Kafka producer. It takes precedence over the audit config and is not closed by
+// the plugin; nil restores the configured sink.
+func (p *PlugPolaris) SetAuditSink(sink AuditSink) {
+	p.auditMutex.Lock()
+	defer p.auditMutex.Unlock()
+	p.auditSink = sink
+}
+
+// SetAuditSampleRate sets the fraction, in [0, 1], of the events of eventType written,
+// overriding audit.sample_rates. A rate of zero drops every event of the type.
+func (p *PlugPolaris) SetAuditSampleRate(eventType AuditEventType, rate float64) {
+	p.auditMutex.Lock()
+	defer p.auditMutex.Unlock()
+	if p.auditSampleRates == nil {
+		p.auditSampleRates = make(map[AuditEventType]float64)
+	}
+	p.auditSampleRates[eventType] = min(max(rate, 0), 1)
+}
+
+// initAuditSink builds the sink of the audit config, closing the one built before
+func (p *PlugPolaris) initAuditSink() error {
+	sink, err := newConfiguredAuditSink(p.currentConf().GetAudit())
+	if err != nil {
+		return NewInitError(fmt.Sprintf("failed to create audit sink: %v", err))
+	}
+	p.auditMutex.Lock()
+	previous := p.configuredAuditSink
+	p.configuredAuditSink = sink
+	p.auditMutex.Unlock()
+	closeAuditSink(previous)
+	return nil
+}
+
+// closeAuditSink closes the sink built from the audit config
+func (p *PlugPolaris) closeAuditSink() {
+	p.auditMutex.Lock()
+	sink := p.configuredAuditSink
+	p.configuredAuditSink = nil
+	p.auditMutex.Unlock()
+	closeAuditSink(sink)
+}
+
+// closeAuditSink closes sink if it holds resources
+func closeAuditSink(sink AuditSink) {
+	if closer, ok := sink.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Warnf("Failed to close audit sink: %v", err)
+		}
+	}
+}
+
+// auditSampleRate returns the fraction of the events of eventType written: the rate set
+// at runtime, else the configured rate of the type, else audit.sample_rate
+func (p *PlugPolaris) auditSampleRate(eventType AuditEventType) float64 {
+	if rate, ok := p.auditSampleRates[eventType]; ok {
+		return rate
+	}
+	cfg := p.currentConf().GetAudit()
+	if rate, ok := cfg.GetSampleRates()[string(eventType)]; ok {
+		return rate
+	}
+	if rate := cfg.GetSampleRate(); rate > 0 {
+		return rate
+	}
+	return 1
+}
+
+// recordAudit completes event with its timestamp, namespace and actor, samples it and
+// writes it to the audit sink. Sink errors are logged.
+func (p *PlugPolaris) recordAudit(event AuditEvent) {
+	p.auditMutex.RLock()
+	sink := p.auditSink
+	if sink == nil {
+		sink = p.configuredAuditSink
+	}
+	rate := p.auditSampleRate(event.Type)
+	p.auditMutex.RUnlock()
+	if sink == nil {
+		sink = NewLoggerAuditSink()
+	}
+	if rate < 1 && (rate <= 0 || rand.Float64() >= rate) {
+		return
+	}
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if event.Namespace == "" {
+		event.Namespace = p.currentConf().GetNamespace()
+	}
+	if event.Actor == "" {
+		event.Actor = AuditActorPolaris
+	}
+	event.Error = p.redact(event.Error)
+	if err := sink.Write(context.Background(), event); err != nil {
+		log.Warnf("Failed to write %s audit event for %s: %v", event.Type, event.subject(), err)
+	}
+}
+
+// recordServiceChangeAudit records an audit event for a service-instance change event.
+func (p *PlugPolaris) recordServiceChangeAudit(serviceName string, instances []model.Instance) {
+	p.recordAudit(AuditEvent{
+		Type:      AuditServiceChanged,
+		Service:   serviceName,
+		Instances: newInstanceSnapshots(instances),
+	})
+}
+
+// recordServiceWatchErrorAudit records an audit event for a service-watcher error.
+func (p *PlugPolaris) recordServiceWatchErrorAudit(serviceName string, err error) {
+	p.recordAudit(AuditEvent{
+		Type:    AuditServiceWatchError,
+		Service: serviceName,
+		Error:   err.Error(),
+	})
+}
+
+// recordConfigChangeAudit records an audit event for a configuration change event.
+func (p *PlugPolaris) recordConfigChangeAudit(change ConfigChange) {
+	added, removed := change.Counts()
+	p.recordAudit(AuditEvent{
+		Type:           AuditConfigChanged,
+		Namespace:      change.Namespace,
+		FileName:       change.FileName,
+		Group:          change.Group,
+		ContentLength:  len(change.NewContent),
+		PreviousLength: len(change.OldContent),
+		LinesAdded:     added,
+		LinesRemoved:   removed,
+	})
+	if len(change.Diff) > 0 && !p.isSensitiveConfig(change.FileName) {
+		log.Debugf("Config change diff for %s:%s:\n%s", change.FileName, change.Group, p.redact(change.DiffText()))
+	}
+}
+
+// recordConfigWatchErrorAudit records an audit event for a config-watcher error.
+func (p *PlugPolaris) recordConfigWatchErrorAudit(fileName, group string, err error) {
+	p.recordAudit(AuditEvent{
+		Type:     AuditConfigWatchError,
+		FileName: fileName,
+		Group:    group,
+		Error:    err.Error(),
+	})
+}
+
+// applicationActor returns the actor of the changes made by the local application: its
+// name, or the host name
+func applicationActor() string {
+	actor := currentLynxName()
+	if actor == "" {
+		actor, _ = os.Hostname()
+	}
+	return actor
+}
+
+// recordApplicationAudit records event as a change made by the local application, marked
+// when it was not sent to Polaris in dry-run mode
+func (p *PlugPolaris) recordApplicationAudit(event AuditEvent) {
+	if event.Actor == "" {
+		event.Actor = applicationActor()
+	}
+	event.DryRun = p.DryRun()
+	p.recordAudit(event)
+}
+
+// registrationAudit returns the audit function of the plugin's registrar, recording the
+// endpoints it registers and deregisters with the application as actor
+func (p *PlugPolaris) registrationAudit() func(AuditEventType, *registry.ServiceInstance) {
+	actor := applicationActor()
+	return func(eventType AuditEventType, instance *registry.ServiceInstance) {
+		p.recordApplicationAudit(AuditEvent{
+			Type:       eventType,
+			Actor:      actor,
+			Service:    instance.Name,
+			InstanceID: instance.ID,
+			Endpoints:  instance.Endpoints,
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package polaris
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAuditSink keeps the events written to it
+type recordingAuditSink struct {
+	mu     sync.Mutex
+	events []AuditEvent
+}
+
+func (s *recordingAuditSink) Write(_ context.Context, event AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingAuditSink) types() []AuditEventType {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	types := make([]AuditEventType, 0, len(s.events))
+	for _, event := range s.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestRecordAudit(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	sink := &recordingAuditSink{}
+	plugin.SetAuditSink(sink)
+
+	instance := NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})
+	plugin.recordServiceChangeAudit("orders", []model.Instance{instance})
+	plugin.recordServiceWatchErrorAudit("orders", errors.New("watch failed"))
+	plugin.recordConfigChangeAudit(newConfigChange("app.yaml", "orders", "default", "a: 1\n", "a: 2\nb: 3\n"))
+	plugin.recordConfigWatchErrorAudit("app.yaml", "orders", errors.New("unavailable"))
+
+	require.Len(t, sink.events, 4)
+	changed := sink.events[0]
+	assert.Equal(t, AuditServiceChanged, changed.Type)
+	assert.Equal(t, "default", changed.Namespace)
+	assert.Equal(t, AuditActorPolaris, changed.Actor)
+	assert.False(t, changed.Timestamp.IsZero())
+	require.Len(t, changed.Instances, 1)
+	assert.Equal(t, "10.0.0.1", changed.Instances[0].Host)
+
+	assert.Equal(t, "watch failed", sink.events[1].Error)
+
+	config := sink.events[2]
+	assert.Equal(t, AuditConfigChanged, config.Type)
+	assert.Equal(t, "app.yaml", config.FileName)
+	assert.Equal(t, 2, config.LinesAdded)
+	assert.Equal(t, 1, config.LinesRemoved)
+	assert.Equal(t, AuditConfigWatchError, sink.events[3].Type)
+}
+
+func TestRecordAudit_Sampling(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", Audit: &conf.Audit{
+		SampleRate:  1,
+		SampleRates: map[string]float64{string(AuditServiceChanged): 0},
+	}})
+	sink := &recordingAuditSink{}
+	plugin.SetAuditSink(sink)
+
+	plugin.recordServiceChangeAudit("orders", nil)
+	plugin.recordServiceWatchErrorAudit("orders", errors.New("watch failed"))
+	assert.Equal(t, []AuditEventType{AuditServiceWatchError}, sink.types())
+
+	plugin.SetAuditSampleRate(AuditServiceChanged, 1)
+	plugin.SetAuditSampleRate(AuditServiceWatchError, 0)
+	plugin.recordServiceChangeAudit("orders", nil)
+	plugin.recordServiceWatchErrorAudit("orders", errors.New("watch failed"))
+	assert.Equal(t, []AuditEventType{AuditServiceWatchError, AuditServiceChanged}, sink.types())
+}
+
+func TestRegistrarAudit(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	sink := &recordingAuditSink{}
+	plugin.SetAuditSink(sink)
+
+	reg := NewPolarisRegistrar(&fakeClients{}, "default")
+	reg.audit = plugin.registrationAudit()
+	svc := &registry.ServiceInstance{ID: "orders-1", Name: "orders", Endpoints: []string{"http://10.0.0.1:8080"}}
+	require.NoError(t, reg.Register(context.Background(), svc))
+	require.NoError(t, reg.Deregister(context.Background(), svc))
+
+	assert.Equal(t, []AuditEventType{AuditServiceRegistered, AuditServiceDeregistered}, sink.types())
+	assert.Equal(t, "orders-1", sink.events[0].InstanceID)
+	assert.Equal(t, []string{"http://10.0.0.1:8080"}, sink.events[0].Endpoints)
+	assert.NotEqual(t, AuditActorPolaris, sink.events[0].Actor)
+}
+
+func TestJSONLAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", Audit: &conf.Audit{Sink: conf.AuditSinkFile, Path: path}})
+	require.NoError(t, plugin.initAuditSink())
+
+	plugin.recordServiceChangeAudit("orders", nil)
+	plugin.recordConfigWatchErrorAudit("app.yaml", "orders", errors.New("unavailable"))
+	plugin.closeAuditSink()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	var events []AuditEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event AuditEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	require.Len(t, events, 2)
+	assert.Equal(t, "orders", events[0].Service)
+	assert.Equal(t, "unavailable", events[1].Error)
+}
+
+func TestWebhookAuditSink(t *testing.T) {
+	var received AuditEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var event AuditEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil || event.Service == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = event
+	}))
+	defer server.Close()
+
+	sink := NewWebhookAuditSink(server.URL, nil)
+	require.NoError(t, sink.Write(context.Background(), AuditEvent{Type: AuditServiceChanged, Service: "orders"}))
+	assert.Equal(t, "orders", received.Service)
+	assert.Error(t, sink.Write(context.Background(), AuditEvent{Type: AuditConfigChanged}))
+}
+
+func TestPublisherAuditSink(t *testing.T) {
+	var keys []string
+	sink := NewPublisherAuditSink(func(_ context.Context, key string, value []byte) error {
+		keys = append(keys, key)
+		assert.True(t, json.Valid(value))
+		return nil
+	})
+	require.NoError(t, sink.Write(context.Background(), AuditEvent{Type: AuditServiceChanged, Service: "orders"}))
+	require.NoError(t, sink.Write(context.Background(), AuditEvent{Type: AuditConfigChanged, FileName: "app.yaml", Group: "orders"}))
+	assert.Equal(t, []string{"orders", "app.yaml:orders"}, keys)
+}
+
+func TestValidator_Audit(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, Audit: &conf.Audit{Sink: conf.AuditSinkWebhook}}
+	assert.False(t, NewValidator(cfg).Validate().IsValid)
+
+	cfg.Audit = &conf.Audit{Sink: conf.AuditSinkLog, SampleRate: 1.5}
+	assert.False(t, NewValidator(cfg).Validate().IsValid)
+
+	cfg.Audit = &conf.Audit{Sink: conf.AuditSinkFile, Path: "/var/log/audit.jsonl", SampleRate: 0.5}
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		assert.NotContains(t, e.Field, "audit")
+	}
+}
@@ -6,8 +6,8 @@ func (p *PlugPolaris) GetNamespace() string {
 	if p.IsDestroyed() {
 		return "default"
 	}
-	if p.conf != nil {
-		return p.conf.Namespace
+	if cfg := p.currentConf(); cfg != nil {
+		return cfg.Namespace
 	}
 	return "default"
 }
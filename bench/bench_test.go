@@ -0,0 +1,147 @@
+package bench
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	polaris "github.com/go-lynx/lynx-polaris"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newTestStub() *StubBackend {
+	stub := NewStubBackend(1)
+	stub.AddService("default", "svc", 16)
+	stub.PublishConfig("default", "DEFAULT_GROUP", "app.yaml", "key: value")
+	return stub
+}
+
+// newTestPlugin returns a plugin started on stub, stopped at the end of the test.
+func newTestPlugin(tb testing.TB, stub *StubBackend, cfg *conf.Polaris) *polaris.PlugPolaris {
+	tb.Helper()
+	plugin, err := stub.Plugin(cfg)
+	require.NoError(tb, err)
+	tb.Cleanup(func() { _ = plugin.CleanupTasks() })
+	return plugin
+}
+
+func TestRun_MaxOps(t *testing.T) {
+	plugin := newTestPlugin(t, newTestStub(), nil)
+	result, err := Run(context.Background(), "discovery", Profile{Concurrency: 4, MaxOps: 200}, Discovery(plugin, "svc").Op)
+	require.NoError(t, err)
+	assert.Equal(t, 200, result.Ops)
+	assert.Zero(t, result.Errors)
+	assert.LessOrEqual(t, result.Latency.Min, result.Latency.P50)
+	assert.LessOrEqual(t, result.Latency.P50, result.Latency.P99)
+	assert.LessOrEqual(t, result.Latency.P99, result.Latency.Max)
+}
+
+func TestRun_QPSPacing(t *testing.T) {
+	plugin := newTestPlugin(t, newTestStub(), nil)
+	result, err := Run(context.Background(), "config", Profile{QPS: 100, Concurrency: 2, Duration: 200 * time.Millisecond},
+		Config(plugin, "app.yaml", "DEFAULT_GROUP").Op)
+	require.NoError(t, err)
+	// 100 QPS for 200ms is ~20 ops; allow generous slack for slow CI machines.
+	assert.Greater(t, result.Ops, 0)
+	assert.LessOrEqual(t, result.Ops, 30)
+}
+
+func TestRun_CountsErrors(t *testing.T) {
+	stub := newTestStub()
+	stub.InjectFailures(1)
+	plugin := newTestPlugin(t, stub, &conf.Polaris{MaxRetryTimes: 1, RetryInterval: durationpb.New(time.Millisecond)})
+	result, err := Run(context.Background(), "rate_limit", Profile{MaxOps: 10}, RateLimit(plugin, "svc", nil).Op)
+	require.NoError(t, err)
+	assert.Equal(t, 10, result.Errors)
+}
+
+func TestRun_InvalidConfig(t *testing.T) {
+	_, err := Run(context.Background(), "nil", Profile{}, nil)
+	assert.Error(t, err)
+	_, err = Run(context.Background(), "negative", Profile{QPS: -1}, func(context.Context) error { return nil })
+	assert.Error(t, err)
+}
+
+func TestSuite(t *testing.T) {
+	stub := newTestStub()
+	plugin := newTestPlugin(t, stub, nil)
+	discovery := polaris.NewPolarisDiscovery(stub.Consumer(), "default", nil)
+	results, err := Suite(context.Background(), Profile{MaxOps: 20},
+		Discovery(plugin, "svc"),
+		KratosDiscovery(discovery, "svc"),
+		Config(plugin, "app.yaml", "DEFAULT_GROUP"),
+		RateLimit(plugin, "svc", map[string]string{"method": "GET"}),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	for _, r := range results {
+		assert.Equal(t, 20, r.Ops, r.Name)
+		assert.Zero(t, r.Errors, r.Name)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	samples := make([]time.Duration, 100)
+	for i := range samples {
+		samples[i] = time.Duration(i+1) * time.Millisecond
+	}
+	lat := summarize(samples)
+	assert.Equal(t, time.Millisecond, lat.Min)
+	assert.Equal(t, 50*time.Millisecond, lat.P50)
+	assert.Equal(t, 90*time.Millisecond, lat.P90)
+	assert.Equal(t, 99*time.Millisecond, lat.P99)
+	assert.Equal(t, 100*time.Millisecond, lat.Max)
+	assert.Equal(t, Latency{}, summarize(nil))
+}
+
+// BenchmarkKratosDiscovery measures the plugin's Kratos discovery client against the stub.
+func BenchmarkKratosDiscovery(b *testing.B) {
+	stub := newTestStub()
+	discovery := polaris.NewPolarisDiscovery(stub.Consumer(), "default", nil)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := discovery.GetService(ctx, "svc"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPluginDiscovery measures plugin discovery, served from the instance cache
+// after the first call.
+func BenchmarkPluginDiscovery(b *testing.B) {
+	plugin := newTestPlugin(b, newTestStub(), nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := plugin.GetServiceInstances("svc"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPluginConfig measures plugin config reads.
+func BenchmarkPluginConfig(b *testing.B) {
+	plugin := newTestPlugin(b, newTestStub(), nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := plugin.GetConfigValue("app.yaml", "DEFAULT_GROUP"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPluginRateLimit measures plugin rate limit checks.
+func BenchmarkPluginRateLimit(b *testing.B) {
+	plugin := newTestPlugin(b, newTestStub(), nil)
+	labels := map[string]string{"method": "GET"}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := plugin.CheckRateLimit("svc", labels); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Package bench provides a load-test harness for the Polaris plugin.
+//
+// The harness drives discovery, config and rate-limit operations at a configurable
+// rate and concurrency and records latency distributions and allocations, so that
+// performance-affecting changes (cache redesigns, pooling) can be compared with
+// reproducible numbers. Operations run against a plugin connected to Polaris or
+// against one started on the in-memory StubBackend, which needs no Polaris server.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Op is a single benchmarked operation.
+type Op func(ctx context.Context) error
+
+// Profile controls the load of a run.
+type Profile struct {
+	// QPS is the target rate across all workers. Zero means unthrottled.
+	QPS int
+	// Concurrency is the number of workers issuing operations. Defaults to 1.
+	Concurrency int
+	// Duration bounds the run time. Defaults to 10s.
+	Duration time.Duration
+	// MaxOps stops the run after this many operations when positive.
+	MaxOps int
+}
+
+// withDefaults returns a copy of c with zero values replaced by defaults.
+func (c Profile) withDefaults() Profile {
+	if c.Concurrency <= 0 {
+		c.Concurrency = 1
+	}
+	if c.Duration <= 0 {
+		c.Duration = 10 * time.Second
+	}
+	return c
+}
+
+// Latency summarizes the latency distribution of a run.
+type Latency struct {
+	Min  time.Duration `json:"min"`
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+}
+
+// Result is the outcome of a single run.
+type Result struct {
+	Name       string        `json:"name"`
+	Ops        int           `json:"ops"`
+	Errors     int           `json:"errors"`
+	Elapsed    time.Duration `json:"elapsed"`
+	Throughput float64       `json:"throughput"`
+	Latency    Latency       `json:"latency"`
+	// AllocsPerOp and BytesPerOp are process-wide heap allocations divided by Ops,
+	// so they include a small, constant harness overhead.
+	AllocsPerOp float64 `json:"allocs_per_op"`
+	BytesPerOp  float64 `json:"bytes_per_op"`
+}
+
+// String formats the result as a single line suitable for logs and diffs.
+func (r *Result) String() string {
+	return fmt.Sprintf("%s: ops=%d errors=%d qps=%.0f p50=%v p90=%v p99=%v max=%v allocs/op=%.1f B/op=%.0f",
+		r.Name, r.Ops, r.Errors, r.Throughput, r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.Max,
+		r.AllocsPerOp, r.BytesPerOp)
+}
+
+// Run drives op with the given load profile until the duration elapses, MaxOps
+// operations have been issued or ctx is done.
+func Run(ctx context.Context, name string, cfg Profile, op Op) (*Result, error) {
+	if op == nil {
+		return nil, fmt.Errorf("bench %s: op is nil", name)
+	}
+	if cfg.QPS < 0 || cfg.MaxOps < 0 {
+		return nil, fmt.Errorf("bench %s: qps and max ops must not be negative", name)
+	}
+	cfg = cfg.withDefaults()
+
+	runCtx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	// Each worker paces itself so that the workers together approximate cfg.QPS.
+	var interval time.Duration
+	if cfg.QPS > 0 {
+		interval = time.Duration(cfg.Concurrency) * time.Second / time.Duration(cfg.QPS)
+	}
+
+	var (
+		mu      sync.Mutex
+		samples []time.Duration
+		errs    int
+		issued  int
+		wg      sync.WaitGroup
+	)
+	// take reserves the next operation slot, honoring MaxOps.
+	take := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if cfg.MaxOps > 0 && issued >= cfg.MaxOps {
+			return false
+		}
+		issued++
+		return true
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			local := make([]time.Duration, 0, 1024)
+			localErrs := 0
+			next := time.Now()
+			for runCtx.Err() == nil && take() {
+				if interval > 0 {
+					if wait := time.Until(next); wait > 0 {
+						timer := time.NewTimer(wait)
+						select {
+						case <-runCtx.Done():
+							timer.Stop()
+						case <-timer.C:
+						}
+						if runCtx.Err() != nil {
+							break
+						}
+					}
+					next = next.Add(interval)
+				}
+				opStart := time.Now()
+				if err := op(runCtx); err != nil {
+					localErrs++
+				}
+				local = append(local, time.Since(opStart))
+			}
+			mu.Lock()
+			samples = append(samples, local...)
+			errs += localErrs
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	result := &Result{
+		Name:    name,
+		Ops:     len(samples),
+		Errors:  errs,
+		Elapsed: elapsed,
+		Latency: summarize(samples),
+	}
+	if elapsed > 0 {
+		result.Throughput = float64(result.Ops) / elapsed.Seconds()
+	}
+	if result.Ops > 0 {
+		result.AllocsPerOp = float64(after.Mallocs-before.Mallocs) / float64(result.Ops)
+		result.BytesPerOp = float64(after.TotalAlloc-before.TotalAlloc) / float64(result.Ops)
+	}
+	return result, nil
+}
+
+// summarize computes the latency distribution of samples.
+func summarize(samples []time.Duration) Latency {
+	if len(samples) == 0 {
+		return Latency{}
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	return Latency{
+		Min:  sorted[0],
+		Mean: total / time.Duration(len(sorted)),
+		P50:  percentile(sorted, 0.50),
+		P90:  percentile(sorted, 0.90),
+		P99:  percentile(sorted, 0.99),
+		Max:  sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the nearest-rank percentile q (0..1) of sorted samples.
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	idx := int(float64(len(sorted))*q+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,85 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	polaris "github.com/go-lynx/lynx-polaris"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Target is the plugin surface exercised by the scenarios. *polaris.PlugPolaris
+// satisfies it, e.g. a plugin created by StubBackend.Plugin.
+type Target interface {
+	GetServiceInstances(serviceName string, opts ...polaris.InstanceOption) ([]model.Instance, error)
+	GetConfigValue(fileName, group string) (string, error)
+	CheckRateLimit(serviceName string, labels map[string]string) (bool, error)
+}
+
+// Scenario is a named operation run as part of a suite.
+type Scenario struct {
+	Name string
+	Op   Op
+}
+
+// Discovery returns a scenario that looks up the instances of service.
+func Discovery(target Target, service string) Scenario {
+	return Scenario{
+		Name: "discovery/" + service,
+		Op: func(context.Context) error {
+			_, err := target.GetServiceInstances(service)
+			return err
+		},
+	}
+}
+
+// KratosDiscovery returns a scenario that looks up service through a Kratos discovery client.
+func KratosDiscovery(discovery registry.Discovery, service string) Scenario {
+	return Scenario{
+		Name: "kratos_discovery/" + service,
+		Op: func(ctx context.Context) error {
+			_, err := discovery.GetService(ctx, service)
+			return err
+		},
+	}
+}
+
+// Config returns a scenario that reads a config file.
+func Config(target Target, fileName, group string) Scenario {
+	return Scenario{
+		Name: fmt.Sprintf("config/%s:%s", group, fileName),
+		Op: func(context.Context) error {
+			_, err := target.GetConfigValue(fileName, group)
+			return err
+		},
+	}
+}
+
+// RateLimit returns a scenario that checks the rate limit of service. A rejected
+// request is a valid outcome and not counted as an error.
+func RateLimit(target Target, service string, labels map[string]string) Scenario {
+	return Scenario{
+		Name: "rate_limit/" + service,
+		Op: func(context.Context) error {
+			_, err := target.CheckRateLimit(service, labels)
+			return err
+		},
+	}
+}
+
+// Suite runs the scenarios one after another with the same load profile.
+func Suite(ctx context.Context, cfg Profile, scenarios ...Scenario) ([]*Result, error) {
+	results := make([]*Result, 0, len(scenarios))
+	for _, s := range scenarios {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		result, err := Run(ctx, s.Name, cfg, s.Op)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,77 @@
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	polaris "github.com/go-lynx/lynx-polaris"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx-polaris/polaristest"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// ErrStubInjected is returned by the StubBackend clients for injected failures.
+var ErrStubInjected = errors.New("bench: injected stub failure")
+
+// stubOperations are the operations driven by the scenarios, whose latency and failures
+// are injected by InjectLatency and InjectFailures.
+var stubOperations = []polaristest.Operation{
+	polaristest.OpGetInstances,
+	polaristest.OpGetConfigFile,
+	polaristest.OpGetQuota,
+}
+
+// StubBackend is an in-memory Polaris backend used to run the harness without a Polaris
+// server. It is a polaristest.Fake serving the instances, configs and rate-limit decisions
+// through the SDK clients of the plugin, optionally with artificial latency and failures,
+// so that the scenarios measure the plugin's caches, request coalescing, pooling and metrics.
+type StubBackend struct {
+	*polaristest.Fake
+}
+
+// NewStubBackend creates a stub that allows all rate-limited requests. The seed makes
+// injected failures reproducible across runs.
+func NewStubBackend(seed int64) *StubBackend {
+	fake := polaristest.New()
+	fake.Seed(seed)
+	return &StubBackend{Fake: fake}
+}
+
+// AddService registers count gRPC instances for service.
+func (s *StubBackend) AddService(namespace, service string, count int) {
+	provider, protocol := s.Provider(), "grpc"
+	for i := 0; i < count; i++ {
+		req := &api.InstanceRegisterRequest{InstanceRegisterRequest: model.InstanceRegisterRequest{
+			Namespace: namespace,
+			Service:   service,
+			Host:      fmt.Sprintf("10.0.%d.%d", i/250, i%250+1),
+			Port:      8080,
+			Protocol:  &protocol,
+		}}
+		// The fake only fails registrations with injected errors
+		_, _ = provider.Register(req)
+	}
+}
+
+// InjectLatency adds latency to every discovery, config and quota call.
+func (s *StubBackend) InjectLatency(latency time.Duration) {
+	for _, op := range stubOperations {
+		s.SetLatency(op, latency)
+	}
+}
+
+// InjectFailures fails the fraction rate (0..1) of the discovery, config and quota calls
+// with ErrStubInjected.
+func (s *StubBackend) InjectFailures(rate float64) {
+	for _, op := range stubOperations {
+		s.SetErrorRate(op, ErrStubInjected, rate)
+	}
+}
+
+// Plugin creates a plugin with the configuration cfg that is started on the stub clients,
+// see polaris.NewPluginWithClients. A nil cfg uses the defaults.
+func (s *StubBackend) Plugin(cfg *conf.Polaris) (*polaris.PlugPolaris, error) {
+	return polaris.NewPluginWithClients(cfg, s.Options()...)
+}
@@ -1,109 +1,480 @@
 package polaris
 
 import (
+	"container/list"
+	"context"
 	"fmt"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/go-lynx/lynx-polaris/conf"
 	"github.com/go-lynx/lynx/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
-// updateServiceInstanceCache updates the in-memory service-instance cache for the given service.
-func (p *PlugPolaris) updateServiceInstanceCache(serviceName string, instances []model.Instance) {
-	if p.conf == nil {
-		return
+// Instance and config cache module
+// Responsibility: keeping the last instances of services and the last good content of config
+// files, served when discovery fails and used to diff config changes. Entries are spread over
+// shards to reduce lock contention, each shard holding its share of cache.max_entries and
+// evicting its least recently used entries. They become stale after cache.ttl and are then
+// refreshed in the background on their next read, while the stale entry is still served.
+// Refreshes end with the plugin lifecycle, and shutdown waits for those in flight.
+
+// cacheShardCount is the number of shards of a cache
+const cacheShardCount = 16
+
+// Names of the caches in the cache metrics
+const (
+	cacheNameService = "service"
+	cacheNameConfig  = "config"
+)
+
+// Lookup results, refresh results and eviction reasons recorded in the cache metrics
+const (
+	cacheResultHit        = "hit"
+	cacheResultStale      = "stale"
+	cacheResultMiss       = "miss"
+	cacheRefreshSuccess   = "success"
+	cacheRefreshError     = "error"
+	cacheEvictionCapacity = "capacity"
+	cacheEvictionExpired  = "expired"
+)
+
+// cacheSettings are the cache settings with defaults applied
+type cacheSettings struct {
+	ttl        time.Duration
+	maxStale   time.Duration
+	maxEntries int
+}
+
+// cacheSettings returns the cache settings, read on every use so that changes apply right away
+func (p *PlugPolaris) cacheSettings() cacheSettings {
+	cfg := p.currentConf().GetCache()
+	settings := cacheSettings{
+		ttl:        conf.DefaultCacheTTL,
+		maxStale:   cfg.GetMaxStale().AsDuration(),
+		maxEntries: conf.DefaultCacheMaxEntries,
+	}
+	if ttl := cfg.GetTtl().AsDuration(); ttl > 0 {
+		settings.ttl = ttl
 	}
-	cacheKey := fmt.Sprintf("service:%s:%s", p.conf.Namespace, serviceName)
+	if cfg.GetMaxEntries() > 0 {
+		settings.maxEntries = int(cfg.GetMaxEntries())
+	}
+	return settings
+}
+
+// shardCapacity returns the entries each shard of a cache holds
+func (s cacheSettings) shardCapacity() int {
+	return (s.maxEntries + cacheShardCount - 1) / cacheShardCount
+}
+
+// cacheEntry is a cached value with the time it was last set
+type cacheEntry[V any] struct {
+	key         string
+	value       V
+	updatedAt   time.Time
+	nextRefresh time.Time // no refresh is started before, after a failed one
+	refreshing  bool
+	element     *list.Element // of the entry in the LRU list of its shard
+}
+
+// cacheShard holds the entries of a cache whose key hashes to it. Its LRU list has the most
+// recently used entry at the front.
+type cacheShard[V any] struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry[V]
+	lru     list.List
+}
+
+// removeLocked removes entry from s. s.mu must be held.
+func (s *cacheShard[V]) removeLocked(entry *cacheEntry[V]) {
+	delete(s.entries, entry.key)
+	s.lru.Remove(entry.element)
+}
+
+// ttlCache is a sharded cache whose entries become stale after the cache ttl. Stale entries
+// are still returned, and refreshed in the background with refresh, which sets the new value.
+type ttlCache[V any] struct {
+	name    string
+	plugin  *PlugPolaris
+	refresh func(ctx context.Context, key string, value V) error
+	seed    maphash.Seed
+	shards  [cacheShardCount]cacheShard[V]
+
+	// refreshing counts the refreshes in flight; refreshDone, on refreshMu, is broadcast
+	// when it drops to zero
+	refreshMu   sync.Mutex
+	refreshDone sync.Cond
+	refreshing  int
+
+	size      atomic.Int64
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+}
+
+// newTTLCache returns a cache of p named name, refreshing stale entries with refresh when
+// it is not nil. refresh is given the lifecycle context of the plugin, canceled on shutdown.
+func newTTLCache[V any](p *PlugPolaris, name string, refresh func(ctx context.Context, key string, value V) error) *ttlCache[V] {
+	c := &ttlCache[V]{name: name, plugin: p, refresh: refresh, seed: maphash.MakeSeed()}
+	c.refreshDone.L = &c.refreshMu
+	return c
+}
+
+func (c *ttlCache[V]) shard(key string) *cacheShard[V] {
+	return &c.shards[maphash.String(c.seed, key)%cacheShardCount]
+}
+
+// get returns the value of key, making it the most recently used of its shard. A stale value
+// is returned too, and a background refresh is started unless one is running or failed less
+// than a ttl ago, or the plugin is not running. Entries stale for longer than max_stale are
+// evicted instead.
+func (c *ttlCache[V]) get(key string) (V, bool) {
+	settings := c.plugin.cacheSettings()
+	now := c.plugin.clock.Now()
+	s := c.shard(key)
 
-	cacheData := map[string]any{
-		"service_name": serviceName,
-		"namespace":    p.conf.Namespace,
-		"instances":    instances,
-		"updated_at":   time.Now().Unix(),
-		"count":        len(instances),
+	s.mu.Lock()
+	entry, ok := s.entries[key]
+	if ok && settings.maxStale > 0 && now.Sub(entry.updatedAt) > settings.ttl+settings.maxStale {
+		s.removeLocked(entry)
+		ok = false
+		defer c.evicted(cacheEvictionExpired, 1)
 	}
+	if !ok {
+		s.mu.Unlock()
+		c.recordLookup(cacheResultMiss)
+		var zero V
+		return zero, false
+	}
+	s.lru.MoveToFront(entry.element)
+	value, result, refresh := entry.value, cacheResultHit, false
+	if now.Sub(entry.updatedAt) >= settings.ttl {
+		result = cacheResultStale
+		if c.refresh != nil && !entry.refreshing && !now.Before(entry.nextRefresh) {
+			entry.refreshing, entry.nextRefresh = true, now.Add(settings.ttl)
+			refresh = true
+		}
+	}
+	s.mu.Unlock()
+
+	c.recordLookup(result)
+	if refresh && !c.startRefresh(key, value) {
+		s.mu.Lock()
+		entry.refreshing = false
+		s.mu.Unlock()
+	}
+	return value, true
+}
 
-	p.cacheMutex.Lock()
-	defer p.cacheMutex.Unlock()
+// startRefresh starts the background refresh of key, counted in refreshing, unless the plugin
+// is not running. It reports whether the refresh was started.
+func (c *ttlCache[V]) startRefresh(key string, value V) bool {
+	c.plugin.mu.RLock()
+	ctx := c.plugin.lifecycleCtx
+	c.plugin.mu.RUnlock()
 
-	if p.serviceCache == nil {
-		p.serviceCache = make(map[string]any)
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	if ctx == nil || ctx.Err() != nil {
+		return false
 	}
-	p.serviceCache[cacheKey] = cacheData
+	c.refreshing++
+	go func() {
+		defer c.refreshFinished()
+		c.refreshEntry(ctx, key, value)
+	}()
+	return true
+}
 
-	log.Infof("Updated service instance cache for %s: %d instances (cache size: %d)",
-		serviceName, len(instances), len(p.serviceCache))
+func (c *ttlCache[V]) refreshFinished() {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	c.refreshing--
+	if c.refreshing == 0 {
+		c.refreshDone.Broadcast()
+	}
 }
 
-// updateConfigCache updates the in-memory configuration cache for the given file/group.
-func (p *PlugPolaris) updateConfigCache(fileName, group string, config model.ConfigFile) {
-	if p.conf == nil || config == nil {
+// waitRefreshes waits for the refreshes in flight. The lifecycle context must be canceled
+// first, so that no refresh starts meanwhile.
+func (c *ttlCache[V]) waitRefreshes() {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	for c.refreshing > 0 {
+		c.refreshDone.Wait()
+	}
+}
+
+// set stores value for key as the most recently used entry of its shard, evicting the least
+// recently used entries of the shard beyond its share of max_entries
+func (c *ttlCache[V]) set(key string, value V) {
+	capacity := c.plugin.cacheSettings().shardCapacity()
+	now := c.plugin.clock.Now()
+	s := c.shard(key)
+
+	s.mu.Lock()
+	if entry, ok := s.entries[key]; ok {
+		entry.value, entry.updatedAt, entry.nextRefresh = value, now, time.Time{}
+		s.lru.MoveToFront(entry.element)
+		s.mu.Unlock()
 		return
 	}
-	cacheKey := fmt.Sprintf("config:%s:%s:%s", p.conf.Namespace, group, fileName)
+	if s.entries == nil {
+		s.entries = make(map[string]*cacheEntry[V])
+	}
+	entry := &cacheEntry[V]{key: key, value: value, updatedAt: now}
+	entry.element = s.lru.PushFront(entry)
+	s.entries[key] = entry
+	evicted := 0
+	for len(s.entries) > capacity {
+		s.removeLocked(s.lru.Back().Value.(*cacheEntry[V]))
+		evicted++
+	}
+	s.mu.Unlock()
 
-	cacheData := map[string]any{
-		"config_file":    fileName,
-		"group":          group,
-		"namespace":      p.conf.Namespace,
-		"content":        config.GetContent(),
-		"updated_at":     time.Now().Unix(),
-		"content_length": len(config.GetContent()),
+	c.size.Add(1)
+	if evicted > 0 {
+		c.evicted(cacheEvictionCapacity, evicted)
+		return
 	}
+	c.recordSize()
+}
 
-	p.cacheMutex.Lock()
-	defer p.cacheMutex.Unlock()
+// refreshEntry refreshes the stale value of key, which stays in the cache when it fails
+func (c *ttlCache[V]) refreshEntry(ctx context.Context, key string, value V) {
+	err := c.refresh(ctx, key, value)
 
-	if p.configCache == nil {
-		p.configCache = make(map[string]any)
+	s := c.shard(key)
+	s.mu.Lock()
+	if entry, ok := s.entries[key]; ok {
+		entry.refreshing = false
 	}
-	p.configCache[cacheKey] = cacheData
+	s.mu.Unlock()
 
-	log.Infof("Updated config cache for %s:%s, content length: %d (cache size: %d)",
-		fileName, group, len(config.GetContent()), len(p.configCache))
+	result := cacheRefreshSuccess
+	if err != nil {
+		log.Warnf("Failed to refresh %s cache entry %s, serving it stale: %s", c.name, key, c.plugin.redactError(err))
+		result = cacheRefreshError
+	}
+	if metrics := c.plugin.GetMetrics(); metrics != nil {
+		metrics.RecordCacheRefresh(c.name, result)
+	}
 }
 
-// clearServiceCache evicts all service-instance cache entries.
-func (p *PlugPolaris) clearServiceCache() {
-	p.cacheMutex.Lock()
-	defer p.cacheMutex.Unlock()
+// each calls fn with every value and the time it was set, holding the lock of its shard
+func (c *ttlCache[V]) each(fn func(value V, updatedAt time.Time)) {
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		for _, entry := range s.entries {
+			fn(entry.value, entry.updatedAt)
+		}
+		s.mu.Unlock()
+	}
+}
 
-	if p.serviceCache != nil {
-		clearedCount := len(p.serviceCache)
-		p.serviceCache = make(map[string]any)
-		log.Infof("Cleared %d service cache entries", clearedCount)
+// clear removes every entry, returning their number. It takes no plugin lock, as cleanup
+// calls it holding p.mu.
+func (c *ttlCache[V]) clear() int {
+	cleared := 0
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		cleared += len(s.entries)
+		s.entries = nil
+		s.lru.Init()
+		s.mu.Unlock()
 	}
+	c.size.Add(-int64(cleared))
+	return cleared
 }
 
-// clearConfigCache evicts all configuration cache entries.
-func (p *PlugPolaris) clearConfigCache() {
-	p.cacheMutex.Lock()
-	defer p.cacheMutex.Unlock()
+// len returns the number of entries
+func (c *ttlCache[V]) len() int {
+	return int(c.size.Load())
+}
 
-	if p.configCache != nil {
-		clearedCount := len(p.configCache)
-		p.configCache = make(map[string]any)
-		log.Infof("Cleared %d config cache entries", clearedCount)
+// stats returns the size, lookups and evictions of the cache. Stale values count as hits.
+func (c *ttlCache[V]) stats() CacheStats {
+	stats := newCacheStats(c.len(), c.hits.Load(), c.misses.Load())
+	stats.Evictions = c.evictions.Load()
+	return stats
+}
+
+func (c *ttlCache[V]) recordLookup(result string) {
+	if result == cacheResultMiss {
+		c.misses.Add(1)
+	} else {
+		c.hits.Add(1)
+	}
+	if metrics := c.plugin.GetMetrics(); metrics != nil {
+		metrics.RecordCacheRequest(c.name, result)
 	}
 }
 
-// getCacheStats returns a snapshot of current cache sizes.
-func (p *PlugPolaris) getCacheStats() map[string]any {
-	p.cacheMutex.RLock()
-	defer p.cacheMutex.RUnlock()
+func (c *ttlCache[V]) evicted(reason string, n int) {
+	c.size.Add(-int64(n))
+	c.evictions.Add(int64(n))
+	if metrics := c.plugin.GetMetrics(); metrics != nil {
+		for range n {
+			metrics.RecordCacheEviction(c.name, reason)
+		}
+	}
+	c.recordSize()
+}
 
-	stats := map[string]any{
-		"service_cache_size": 0,
-		"config_cache_size":  0,
-		"timestamp":          time.Now().Unix(),
+func (c *ttlCache[V]) recordSize() {
+	if metrics := c.plugin.GetMetrics(); metrics != nil {
+		metrics.SetCacheEntries(c.name, c.len())
 	}
+}
+
+// cachedInstances is an entry of the service instance cache
+type cachedInstances struct {
+	service   string
+	namespace string
+	instances []model.Instance
+}
+
+// cachedConfig is an entry of the config cache, holding the last good content of a file
+type cachedConfig struct {
+	namespace string
+	group     string
+	file      string
+	content   string
+}
+
+// serviceCacheKey returns the key of the cached instances of serviceName in namespace
+func serviceCacheKey(namespace, serviceName string) string {
+	return fmt.Sprintf("service:%s:%s", namespace, serviceName)
+}
+
+// configCacheKey returns the key of the cached content of fileName/group in namespace
+func configCacheKey(namespace, group, fileName string) string {
+	return fmt.Sprintf("config:%s:%s:%s", namespace, group, fileName)
+}
+
+// updateServiceInstanceCache updates the in-memory service-instance cache for the given service.
+func (p *PlugPolaris) updateServiceInstanceCache(serviceName string, instances []model.Instance) {
+	cfg := p.currentConf()
+	if cfg == nil {
+		return
+	}
+	p.serviceCache.set(serviceCacheKey(cfg.Namespace, serviceName),
+		cachedInstances{service: serviceName, namespace: cfg.Namespace, instances: instances})
+
+	log.Infof("Updated service instance cache for %s: %d instances (cache size: %d)",
+		serviceName, len(instances), p.serviceCache.len())
+}
+
+// cachedServiceInstances returns the cached instances of serviceName, or nil if none are cached.
+func (p *PlugPolaris) cachedServiceInstances(serviceName string) []model.Instance {
+	cfg := p.currentConf()
+	if cfg == nil {
+		return nil
+	}
+	cached, ok := p.serviceCache.get(serviceCacheKey(cfg.Namespace, serviceName))
+	if !ok || len(cached.instances) == 0 {
+		return nil
+	}
+	return append([]model.Instance(nil), cached.instances...)
+}
+
+// refreshServiceCache refreshes the stale instances of a service through discovery, without
+// the discovery fallbacks, which would serve the stale instances as fresh ones.
+func (p *PlugPolaris) refreshServiceCache(ctx context.Context, _ string, cached cachedInstances) error {
+	instances, err := p.fetchServiceInstances(cached.service, false)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p.updateServiceInstanceCache(cached.service, instances)
+	return nil
+}
 
+// updateConfigCache updates the in-memory configuration cache for the given file/group.
+func (p *PlugPolaris) updateConfigCache(fileName, group string, config model.ConfigFile) {
+	cfg := p.currentConf()
+	if cfg == nil || config == nil {
+		return
+	}
+	p.configCache.set(configCacheKey(cfg.Namespace, group, fileName),
+		cachedConfig{namespace: cfg.Namespace, group: group, file: fileName, content: config.GetContent()})
+
+	log.Infof("Updated config cache for %s:%s, content length: %d (cache size: %d)",
+		fileName, group, len(config.GetContent()), p.configCache.len())
+}
+
+// cachedConfigContent returns the cached content of fileName/group, if any.
+func (p *PlugPolaris) cachedConfigContent(fileName, group string) (string, bool) {
+	cfg := p.currentConf()
+	if cfg == nil {
+		return "", false
+	}
+	cached, ok := p.configCache.get(configCacheKey(cfg.Namespace, group, fileName))
+	return cached.content, ok
+}
+
+// refreshConfigCache fetches a config file with stale content. Changed content goes through
+// handleConfigChanged, like a watch event, so that it is validated and reloaded before it
+// replaces the cached content.
+func (p *PlugPolaris) refreshConfigCache(ctx context.Context, _ string, cached cachedConfig) error {
+	if err := p.checkInitialized(); err != nil {
+		return err
+	}
+	if err := p.checkSubsystem(SubsystemConfig); err != nil {
+		return err
+	}
+	p.mu.RLock()
+	configAPI := p.configLocked()
+	p.mu.RUnlock()
+	if configAPI == nil {
+		return newDestroyedError()
+	}
+
+	file, err := configAPI.GetConfigFile(cached.namespace, cached.group, cached.file)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if file.GetContent() == cached.content {
+		p.updateConfigCache(cached.file, cached.group, file)
+		return nil
+	}
+	log.Infof("Config %s:%s changed since it was cached", cached.file, cached.group)
+	p.handleConfigChanged(cached.file, cached.group, file)
+	return nil
+}
+
+// waitCacheRefreshes waits for the background refreshes of the caches, once the lifecycle
+// context is canceled.
+func (p *PlugPolaris) waitCacheRefreshes() {
 	if p.serviceCache != nil {
-		stats["service_cache_size"] = len(p.serviceCache)
+		p.serviceCache.waitRefreshes()
 	}
 	if p.configCache != nil {
-		stats["config_cache_size"] = len(p.configCache)
+		p.configCache.waitRefreshes()
 	}
+}
 
-	return stats
+// clearServiceCache evicts all service-instance cache entries.
+func (p *PlugPolaris) clearServiceCache() {
+	if cleared := p.serviceCache.clear(); cleared > 0 {
+		log.Infof("Cleared %d service cache entries", cleared)
+	}
+}
+
+// clearConfigCache evicts all configuration cache entries.
+func (p *PlugPolaris) clearConfigCache() {
+	if cleared := p.configCache.clear(); cleared > 0 {
+		log.Infof("Cleared %d config cache entries", cleared)
+	}
 }
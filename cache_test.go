@@ -0,0 +1,204 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newCacheTestPlugin(cache *conf.Cache) (*PlugPolaris, *manualClock) {
+	clock := newManualClock()
+	plugin := NewPolarisControlPlane(WithClock(clock))
+	plugin.setConf(&conf.Polaris{Namespace: "default", Cache: cache})
+	plugin.mu.Lock()
+	plugin.ensureLifecycleContextLocked()
+	plugin.mu.Unlock()
+	return plugin, clock
+}
+
+func TestTTLCache_StaleWhileRevalidate(t *testing.T) {
+	plugin, clock := newCacheTestPlugin(&conf.Cache{Ttl: durationpb.New(10 * time.Second)})
+	refreshes := make(chan string, 1)
+	var cache *ttlCache[string]
+	refreshErr := errors.New("polaris unavailable")
+	cache = newTTLCache(plugin, "test", func(_ context.Context, key, value string) error {
+		defer func() { refreshes <- value }()
+		if refreshErr != nil {
+			return refreshErr
+		}
+		cache.set(key, "v2")
+		return nil
+	})
+
+	cache.set("a", "v1")
+	value, ok := cache.get("a")
+	require.True(t, ok)
+	assert.Equal(t, "v1", value)
+	assert.Empty(t, refreshes, "fresh entries are not refreshed")
+
+	clock.Advance(11 * time.Second)
+	value, ok = cache.get("a")
+	require.True(t, ok)
+	assert.Equal(t, "v1", value, "stale entries are served during their refresh")
+	assert.Equal(t, "v1", <-refreshes)
+
+	require.Eventually(t, func() bool {
+		value, ok := cache.get("a")
+		return ok && value == "v1"
+	}, time.Second, time.Millisecond, "the entry is served stale after its refresh failed")
+	assert.Empty(t, refreshes, "failed refreshes are retried after a ttl")
+
+	refreshErr = nil
+	clock.Advance(10 * time.Second)
+	_, _ = cache.get("a")
+	<-refreshes
+	value, _ = cache.get("a")
+	assert.Equal(t, "v2", value)
+	assert.Empty(t, refreshes)
+}
+
+func TestTTLCache_MaxStale(t *testing.T) {
+	plugin, clock := newCacheTestPlugin(&conf.Cache{Ttl: durationpb.New(10 * time.Second), MaxStale: durationpb.New(5 * time.Second)})
+	cache := newTTLCache[string](plugin, "test", nil)
+
+	cache.set("a", "v1")
+	clock.Advance(15 * time.Second)
+	_, ok := cache.get("a")
+	assert.True(t, ok, "stale for max_stale")
+
+	clock.Advance(time.Second)
+	_, ok = cache.get("a")
+	assert.False(t, ok)
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1, Evictions: 1, HitRatio: 0.5}, cache.stats())
+}
+
+// sameShardKeys returns n keys of cache that hash to the same shard
+func sameShardKeys(cache *ttlCache[string], n int) []string {
+	var keys []string
+	for i := 0; len(keys) < n; i++ {
+		key := fmt.Sprint("key-", i)
+		if len(keys) == 0 || cache.shard(key) == cache.shard(keys[0]) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func TestTTLCache_MaxEntries(t *testing.T) {
+	plugin, clock := newCacheTestPlugin(&conf.Cache{MaxEntries: 2 * cacheShardCount})
+	cache := newTTLCache[string](plugin, "test", nil)
+	keys := sameShardKeys(cache, 4)
+	a, b, c, d := keys[0], keys[1], keys[2], keys[3]
+
+	for _, key := range []string{a, b, a, c} {
+		cache.set(key, key)
+		clock.Advance(time.Second)
+	}
+	assert.Equal(t, 2, cache.len(), "each shard holds max_entries/16 entries")
+	_, ok := cache.get(b)
+	assert.False(t, ok, "the least recently used entry of the shard is evicted")
+	_, ok = cache.get(a)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), cache.stats().Evictions)
+
+	cache.set(d, d)
+	_, ok = cache.get(c)
+	assert.False(t, ok, "reads make an entry recently used")
+	_, ok = cache.get(a)
+	assert.True(t, ok)
+
+	for i := 0; cache.len() < 3; i++ {
+		if key := fmt.Sprint("other-", i); cache.shard(key) != cache.shard(a) {
+			cache.set(key, key)
+		}
+	}
+	assert.Equal(t, int64(2), cache.stats().Evictions, "other shards do not evict from this one")
+
+	assert.Equal(t, 3, cache.clear())
+	assert.Zero(t, cache.len())
+}
+
+func TestTTLCache_RefreshesEndWithLifecycle(t *testing.T) {
+	plugin, clock := newCacheTestPlugin(nil)
+	started, release := make(chan struct{}), make(chan struct{})
+	var finished atomic.Bool
+	cache := newTTLCache(plugin, "test", func(ctx context.Context, _, _ string) error {
+		close(started)
+		<-release
+		finished.Store(true)
+		return ctx.Err()
+	})
+	cache.set("a", "v1")
+	clock.Advance(conf.DefaultCacheTTL)
+	_, _ = cache.get("a")
+	<-started
+
+	plugin.mu.Lock()
+	plugin.lifecycleStop()
+	plugin.lifecycleCtx = nil
+	plugin.mu.Unlock()
+	waited := make(chan struct{})
+	go func() {
+		cache.waitRefreshes()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+		t.Fatal("waitRefreshes returned while a refresh was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-waited
+	assert.True(t, finished.Load())
+
+	clock.Advance(conf.DefaultCacheTTL)
+	value, ok := cache.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "v1", value, "stale entries are still served after shutdown, without a refresh")
+}
+
+func TestRefreshServiceCache(t *testing.T) {
+	instance := NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.2", Port: 8080})
+	plugin := newBuilderTestPlugin(t, &fakeClients{}, &fakeClients{instances: []model.Instance{instance}})
+	clock := newManualClock()
+	plugin.clock = clock
+
+	stale := NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})
+	plugin.updateServiceInstanceCache("orders", []model.Instance{stale})
+	clock.Advance(conf.DefaultCacheTTL)
+	assert.Equal(t, "10.0.0.1", plugin.cachedServiceInstances("orders")[0].GetHost())
+	require.Eventually(t, func() bool {
+		instances := plugin.cachedServiceInstances("orders")
+		return len(instances) == 1 && instances[0].GetHost() == "10.0.0.2"
+	}, time.Second, time.Millisecond)
+}
+
+func TestRefreshConfigCache(t *testing.T) {
+	clock := newManualClock()
+	plugin := newTestPlugin(t, nil, WithConfigClient(&fakeClients{config: "workers: 8\n"}), WithClock(clock))
+	var reloads atomic.Int32
+	_, err := plugin.addReloadHandler("app.yaml", "orders", func(string) error {
+		reloads.Add(1)
+		return nil
+	})
+	require.NoError(t, err)
+
+	plugin.updateConfigCache("app.yaml", "orders", &fakeConfigFile{content: "workers: 4\n"})
+	clock.Advance(conf.DefaultCacheTTL)
+	content, _ := plugin.cachedConfigContent("app.yaml", "orders")
+	assert.Equal(t, "workers: 4\n", content)
+	require.Eventually(t, func() bool {
+		content, _ := plugin.cachedConfigContent("app.yaml", "orders")
+		return content == "workers: 8\n"
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), reloads.Load(), "changed content is handled like a watch event")
+}
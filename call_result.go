@@ -0,0 +1,133 @@
+package polaris
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// ReportCallResult reports the outcome of a call to instance to Polaris, so that the
+// circuit breaking rules of its service can isolate failing instances. A nil err reports
+// a success; otherwise the Kratos error code of err is reported as the return code.
+// instance has to be one returned by Polaris discovery, e.g. by GetServiceInstances.
+func (p *PlugPolaris) ReportCallResult(instance model.Instance, err error, latency time.Duration) error {
+	if err := p.checkInitialized(); err != nil {
+		return err
+	}
+	if aggregated, ok := instance.(*aggregatedInstance); ok {
+		instance = aggregated.Instance
+	}
+	if _, ok := instance.(*pb.InstanceInProto); !ok {
+		return NewServiceError(ErrCodeCallResultReport, "call results can only be reported for instances returned by Polaris discovery")
+	}
+	p.mu.RLock()
+	consumer := p.consumerLocked()
+	metrics := p.metrics
+	p.mu.RUnlock()
+	if consumer == nil {
+		return newDestroyedError()
+	}
+
+	result := &api.ServiceCallResult{}
+	result.SetCalledInstance(instance)
+	result.SetDelay(latency)
+	if err == nil {
+		result.SetRetStatus(model.RetSuccess)
+		result.SetRetCode(0)
+	} else {
+		result.SetRetStatus(model.RetFail)
+		result.SetRetCode(errors.FromError(err).Code)
+	}
+	if reportErr := consumer.UpdateServiceCallResult(result); reportErr != nil {
+		if metrics != nil {
+			metrics.RecordSDKOperation("report_call_result", "error")
+		}
+		return WrapServiceError(reportErr, ErrCodeCallResultReport, "failed to report call result of service "+instance.GetService())
+	}
+	if metrics != nil {
+		metrics.RecordSDKOperation("report_call_result", "success")
+	}
+	return nil
+}
+
+// lookupInstance returns the Polaris instance of serviceName listening on address
+// (host:port), from the instances cached by the SDK.
+func (p *PlugPolaris) lookupInstance(serviceName, address string) (model.Instance, bool) {
+	host, portText, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, false
+	}
+	port, err := strconv.Atoi(portText)
+	if err != nil {
+		return nil, false
+	}
+	p.mu.RLock()
+	consumer := p.consumerLocked()
+	namespace := p.currentConf().GetNamespace()
+	p.mu.RUnlock()
+	if consumer == nil {
+		return nil, false
+	}
+	resp, err := consumer.GetAllInstances(&api.GetAllInstancesRequest{
+		GetAllInstancesRequest: model.GetAllInstancesRequest{Service: serviceName, Namespace: namespace},
+	})
+	if err != nil {
+		return nil, false
+	}
+	for _, inst := range resp.GetInstances() {
+		if inst.GetHost() == host && int(inst.GetPort()) == port {
+			return inst, true
+		}
+	}
+	return nil, false
+}
+
+// CallResultMiddleware returns Kratos client middleware that reports the result and
+// latency of every call to the instance the selector picked for it, using
+// ReportCallResult. Calls to nodes that Polaris does not know, such as static route
+// fallbacks, are not reported. Reporting never changes the result of a call.
+func (p *PlugPolaris) CallResultMiddleware() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			start := time.Now()
+			reply, err := handler(ctx, req)
+			peer, ok := selector.FromPeerContext(ctx)
+			if !ok || peer.Node == nil {
+				return reply, err
+			}
+			instance, ok := p.lookupInstance(peer.Node.ServiceName(), peer.Node.Address())
+			if !ok {
+				return reply, err
+			}
+			if reportErr := p.ReportCallResult(instance, err, time.Since(start)); reportErr != nil {
+				log.Debugf("Failed to report call result of %s %s: %v", peer.Node.ServiceName(), peer.Node.Address(), reportErr)
+			}
+			return reply, err
+		}
+	}
+}
+
+// withoutOpenCircuits drops the instances whose Polaris circuit breaker is open. When
+// every instance is open, all of them are kept, so that calls can still probe recovery.
+func withoutOpenCircuits(instances []model.Instance) []model.Instance {
+	available := make([]model.Instance, 0, len(instances))
+	for _, inst := range instances {
+		if status := inst.GetCircuitBreakerStatus(); status != nil && status.GetStatus() == model.Open {
+			continue
+		}
+		available = append(available, inst)
+	}
+	if len(available) == 0 {
+		return instances
+	}
+	return available
+}
@@ -0,0 +1,74 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// breakerInstance is an instance with a Polaris circuit breaker status.
+type breakerInstance struct {
+	model.Instance
+	status model.Status
+}
+
+func (i *breakerInstance) GetCircuitBreakerStatus() model.CircuitBreakerStatus {
+	return breakerStatus{status: i.status}
+}
+
+type breakerStatus struct {
+	model.CircuitBreakerStatus
+	status model.Status
+}
+
+func (s breakerStatus) GetStatus() model.Status { return s.status }
+
+func TestWithoutOpenCircuits(t *testing.T) {
+	healthy := NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})
+	halfOpen := &breakerInstance{Instance: healthy, status: model.HalfOpen}
+	open := &breakerInstance{Instance: healthy, status: model.Open}
+
+	assert.Equal(t, []model.Instance{healthy, halfOpen}, withoutOpenCircuits([]model.Instance{healthy, open, halfOpen}))
+	assert.Equal(t, []model.Instance{open}, withoutOpenCircuits([]model.Instance{open}), "all open instances are kept")
+	assert.Empty(t, withoutOpenCircuits(nil))
+}
+
+func TestReportCallResult_Validation(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	instance := NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})
+	err := plugin.ReportCallResult(instance, nil, time.Millisecond)
+	assert.True(t, IsInitError(err))
+
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	atomic.StoreInt32(&plugin.initialized, 1)
+	err = plugin.ReportCallResult(instance, errors.New("boom"), time.Millisecond)
+	assert.True(t, isErrorCode(err, ErrCodeCallResultReport), "static instances cannot be reported")
+}
+
+func TestCallResultMiddleware_PassesResultThrough(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	callErr := errors.New("unavailable")
+	handler := plugin.CallResultMiddleware()(func(ctx context.Context, req any) (any, error) {
+		if peer, ok := selector.FromPeerContext(ctx); ok {
+			peer.Node = selector.NewNode("grpc", "10.0.0.1:9000", nil)
+		}
+		return "reply", callErr
+	})
+
+	reply, err := handler(context.Background(), nil)
+	assert.Equal(t, "reply", reply)
+	assert.Equal(t, callErr, err)
+
+	reply, err = handler(selector.NewPeerContext(context.Background(), &selector.Peer{}), nil)
+	require.Equal(t, "reply", reply)
+	assert.Equal(t, callErr, err, "unknown nodes are not reported")
+}
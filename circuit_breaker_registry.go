@@ -0,0 +1,167 @@
+package polaris
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/go-lynx/lynx/log"
+)
+
+// PluginCircuitBreakerKey is the registry key of the circuit breaker protecting the
+// plugin's own calls to Polaris
+const PluginCircuitBreakerKey = "polaris"
+
+// CircuitBreakerRegistry holds circuit breakers by key, so that they can be inspected and
+// controlled by operators at runtime
+type CircuitBreakerRegistry struct {
+	mu       sync.RWMutex
+	breakers map[string]*CircuitBreaker
+}
+
+// CircuitBreakerStatus is the state of a registered circuit breaker
+type CircuitBreakerStatus struct {
+	Key         string  `json:"key"`
+	State       string  `json:"state"`
+	Forced      bool    `json:"forced"`
+	FailureRate float64 `json:"failure_rate"`
+}
+
+// NewCircuitBreakerRegistry creates an empty circuit breaker registry
+func NewCircuitBreakerRegistry() *CircuitBreakerRegistry {
+	return &CircuitBreakerRegistry{breakers: make(map[string]*CircuitBreaker)}
+}
+
+// Register adds cb under key, replacing any breaker registered under the same key
+func (r *CircuitBreakerRegistry) Register(key string, cb *CircuitBreaker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.breakers[key] = cb
+}
+
+// Unregister removes the breaker registered under key
+func (r *CircuitBreakerRegistry) Unregister(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.breakers, key)
+}
+
+// Get returns the breaker registered under key
+func (r *CircuitBreakerRegistry) Get(key string) (*CircuitBreaker, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cb, ok := r.breakers[key]
+	return cb, ok
+}
+
+// ForceOpen holds the breaker registered under key open until it is closed or reset
+func (r *CircuitBreakerRegistry) ForceOpen(key string) error {
+	cb, ok := r.Get(key)
+	if !ok {
+		return fmt.Errorf("circuit breaker %q not found", key)
+	}
+	cb.ForceOpen()
+	log.Warnf("Circuit breaker %s forced open", key)
+	return nil
+}
+
+// ForceClose holds the breaker registered under key closed until it is reset
+func (r *CircuitBreakerRegistry) ForceClose(key string) error {
+	cb, ok := r.Get(key)
+	if !ok {
+		return fmt.Errorf("circuit breaker %q not found", key)
+	}
+	cb.ForceClose()
+	log.Warnf("Circuit breaker %s forced closed", key)
+	return nil
+}
+
+// Reset returns the breaker registered under key to normal operation
+func (r *CircuitBreakerRegistry) Reset(key string) error {
+	cb, ok := r.Get(key)
+	if !ok {
+		return fmt.Errorf("circuit breaker %q not found", key)
+	}
+	cb.Reset()
+	return nil
+}
+
+// ResetAll returns every registered breaker to normal operation
+func (r *CircuitBreakerRegistry) ResetAll() {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, cb := range r.breakers {
+		cb.Reset()
+	}
+	log.Infof("Reset %d circuit breakers", len(r.breakers))
+}
+
+// Statuses returns the state of every registered breaker, ordered by key
+func (r *CircuitBreakerRegistry) Statuses() []CircuitBreakerStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	statuses := make([]CircuitBreakerStatus, 0, len(r.breakers))
+	for key, cb := range r.breakers {
+		statuses = append(statuses, CircuitBreakerStatus{
+			Key:         key,
+			State:       cb.GetState().String(),
+			Forced:      cb.IsForced(),
+			FailureRate: cb.GetFailureRate(),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Key < statuses[j].Key })
+	return statuses
+}
+
+// CircuitBreakers returns the registry of the plugin's circuit breakers. The breaker of
+// the plugin's own Polaris calls is registered under PluginCircuitBreakerKey; applications
+// can register their own breakers to control them through the same admin surface.
+func (p *PlugPolaris) CircuitBreakers() *CircuitBreakerRegistry {
+	return p.circuitBreakers
+}
+
+// CircuitBreakerAdminHandler returns an HTTP handler to inspect and control the registered
+// circuit breakers. GET lists their states as JSON. POST with the "action" query parameter
+// set to "open", "close" or "reset" forces open, forces closed or resets the breaker named
+// by the "key" parameter; "reset" without a key resets all of them. Mount it on an
+// internal admin listener; it can block or force through all protected traffic.
+func (p *PlugPolaris) CircuitBreakerAdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			key := r.URL.Query().Get("key")
+			var err error
+			switch action := r.URL.Query().Get("action"); {
+			case action == "reset" && key == "":
+				p.circuitBreakers.ResetAll()
+			case key == "":
+				http.Error(w, "missing key", http.StatusBadRequest)
+				return
+			case action == "open":
+				err = p.circuitBreakers.ForceOpen(key)
+			case action == "close":
+				err = p.circuitBreakers.ForceClose(key)
+			case action == "reset":
+				err = p.circuitBreakers.Reset(key)
+			default:
+				http.Error(w, fmt.Sprintf("unknown action %q", action), http.StatusBadRequest)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Breakers []CircuitBreakerStatus `json:"breakers"`
+		}{p.circuitBreakers.Statuses()})
+	})
+}
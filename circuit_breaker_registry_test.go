@@ -0,0 +1,87 @@
+package polaris
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker_ForcedStates(t *testing.T) {
+	clock := newManualClock()
+	circuitBreaker := NewCircuitBreaker(0.5, 10*time.Millisecond, WithCircuitBreakerClock(clock))
+	circuitBreaker.ForceOpen()
+	clock.Advance(15 * time.Millisecond)
+	assert.ErrorContains(t, circuitBreaker.Do(func() error { return nil }), "forced open", "no probes while forced open")
+
+	circuitBreaker.ForceClose()
+	for i := 0; i < 5; i++ {
+		_ = circuitBreaker.Do(func() error { return assert.AnError })
+	}
+	assert.Equal(t, CircuitStateClosed, circuitBreaker.GetState(), "failures do not open a forced-closed breaker")
+	assert.True(t, circuitBreaker.IsForced())
+
+	circuitBreaker.Reset()
+	assert.False(t, circuitBreaker.IsForced())
+	assert.Equal(t, 0.0, circuitBreaker.GetFailureRate())
+	_ = circuitBreaker.Do(func() error { return assert.AnError })
+	assert.Equal(t, CircuitStateOpen, circuitBreaker.GetState())
+}
+
+func TestCircuitBreakerRegistry(t *testing.T) {
+	registry := NewCircuitBreakerRegistry()
+	orders := NewCircuitBreaker(0.5, time.Second)
+	registry.Register("orders", orders)
+	registry.Register("billing", NewCircuitBreaker(0.5, time.Second))
+
+	require.NoError(t, registry.ForceOpen("orders"))
+	assert.Error(t, registry.ForceOpen("unknown"))
+	assert.Equal(t, []CircuitBreakerStatus{
+		{Key: "billing", State: "closed"},
+		{Key: "orders", State: "open", Forced: true},
+	}, registry.Statuses())
+
+	registry.ResetAll()
+	assert.Equal(t, CircuitStateClosed, orders.GetState())
+	assert.False(t, orders.IsForced())
+
+	registry.Unregister("orders")
+	_, ok := registry.Get("orders")
+	assert.False(t, ok)
+}
+
+func TestCircuitBreakerAdminHandler(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.CircuitBreakers().Register("orders", NewCircuitBreaker(0.5, time.Second))
+	handler := plugin.CircuitBreakerAdminHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/?key=orders&action=open", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Breakers []CircuitBreakerStatus `json:"breakers"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, []CircuitBreakerStatus{{Key: "orders", State: "open", Forced: true}}, body.Breakers)
+
+	for target, status := range map[string]int{
+		"/?key=unknown&action=open": http.StatusNotFound,
+		"/?key=orders&action=trip":  http.StatusBadRequest,
+		"/?action=open":             http.StatusBadRequest,
+		"/?action=reset":            http.StatusOK,
+	} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, nil))
+		assert.Equal(t, status, rec.Code, target)
+	}
+	orders, _ := plugin.CircuitBreakers().Get("orders")
+	assert.Equal(t, CircuitStateClosed, orders.GetState())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/go-lynx/lynx"
 	"github.com/go-lynx/lynx-polaris/conf"
 	"github.com/go-lynx/lynx/log"
-	"github.com/polarismesh/polaris-go/api"
 )
 
 // restoreControlPlane sets Lynx control plane back to default so the app no longer uses this plugin.
@@ -39,10 +38,20 @@ func (p *PlugPolaris) stopHealthCheck() {
 func (p *PlugPolaris) cleanupWatchers() {
 	log.Infof("Cleaning up watchers")
 
-	// Clear retry deduplication maps so in-flight retries can finish cleanly
+	// Cancel in-flight retries and clear the deduplication maps
 	p.retryMutex.Lock()
-	p.retryingServiceWatchers = make(map[string]struct{})
-	p.retryingConfigWatchers = make(map[string]struct{})
+	for _, retry := range p.retryingServiceWatchers {
+		retry.cancel()
+	}
+	for _, retry := range p.retryingConfigWatchers {
+		retry.cancel()
+	}
+	if p.retryingServiceWatchers != nil {
+		p.retryingServiceWatchers = make(map[string]*watchRetryEntry)
+	}
+	if p.retryingConfigWatchers != nil {
+		p.retryingConfigWatchers = make(map[string]*watchRetryEntry)
+	}
 	p.retryMutex.Unlock()
 
 	p.watcherMutex.Lock()
@@ -68,21 +77,24 @@ func (p *PlugPolaris) cleanupWatchers() {
 		}
 	}
 
+	// Retry goroutines observe their canceled contexts promptly; wait so none outlive cleanup.
+	p.retryWg.Wait()
+
 	log.Infof("Cleaned up %d service watchers and %d config watchers", serviceWatcherCount, configWatcherCount)
 }
 
 // closeSDKConnection closes SDK connection
 func (p *PlugPolaris) closeSDKConnection() {
 	p.mu.Lock()
-	sdk := p.sdk
-	p.sdk = nil
+	apis := p.apis
+	p.setSDKLocked(nil)
 	namespace := "unknown"
-	if p.conf != nil {
-		namespace = p.conf.Namespace
+	if cfg := p.currentConf(); cfg != nil {
+		namespace = cfg.Namespace
 	}
 	p.mu.Unlock()
 
-	destroySDKResources(sdk, namespace)
+	destroySDKResources(apis, namespace)
 }
 
 // destroyPolarisInstance destroys Polaris instance
@@ -91,8 +103,8 @@ func (p *PlugPolaris) destroyPolarisInstance() {
 	polarisClient := p.polaris
 	p.polaris = nil
 	namespace := "unknown"
-	if p.conf != nil {
-		namespace = p.conf.Namespace
+	if cfg := p.currentConf(); cfg != nil {
+		namespace = cfg.Namespace
 	}
 	p.mu.Unlock()
 
@@ -109,8 +121,8 @@ func (p *PlugPolaris) releaseMemoryResources() {
 		p.serviceInfo = nil
 	}
 
-	// NOTE: p.conf is intentionally NOT cleared here. Public methods snapshot
-	// p.conf under p.mu and may still be reading it (e.g. p.conf.Namespace) when
+	// NOTE: the configuration is intentionally NOT cleared here. Public methods snapshot
+	// it with currentConf and may still be reading it (e.g. its Namespace) when
 	// cleanup runs concurrently with in-flight requests. Nilling it would cause a
 	// data race / nil-pointer panic on shutdown-while-serving.
 
@@ -127,6 +139,7 @@ func (p *PlugPolaris) releaseMemoryResources() {
 
 	if p.circuitBreaker != nil {
 		log.Infof("Clearing circuit breaker")
+		p.circuitBreakers.Unregister(PluginCircuitBreakerKey)
 		p.circuitBreaker = nil
 	}
 
@@ -134,6 +147,14 @@ func (p *PlugPolaris) releaseMemoryResources() {
 	p.clearServiceCache()
 	p.clearConfigCache()
 
+	p.freshnessMutex.Lock()
+	p.configFreshness = nil
+	p.freshnessMutex.Unlock()
+
+	p.heartbeatMutex.Lock()
+	p.heartbeatFailures = nil
+	p.heartbeatMutex.Unlock()
+
 	// Clear retry maps (allow late finishXxx to no-op)
 	p.retryMutex.Lock()
 	p.retryingServiceWatchers = nil
@@ -156,7 +177,7 @@ func (p *PlugPolaris) stopBackgroundTasks() {
 	// Stop circuit breaker tasks
 	if p.circuitBreaker != nil {
 		log.Infof("Stopping circuit breaker background tasks")
-		p.circuitBreaker.ForceClose()
+		p.circuitBreaker.Reset()
 	}
 
 	// Stop metrics collection tasks
@@ -169,14 +190,16 @@ func (p *PlugPolaris) stopBackgroundTasks() {
 	log.Infof("Stopping health check tasks")
 	log.Infof("Stopping monitoring tasks")
 	log.Infof("Stopping audit log tasks")
+	p.closeAuditSink()
 
 	log.Infof("Background tasks stopped")
 }
 
 // getShutdownTimeoutDuration returns configured shutdown timeout for cleanup
 func (p *PlugPolaris) getShutdownTimeoutDuration() time.Duration {
-	if p.conf != nil && p.conf.ShutdownTimeout != nil && p.conf.ShutdownTimeout.AsDuration() > 0 {
-		d := p.conf.ShutdownTimeout.AsDuration()
+	cfg := p.currentConf()
+	if cfg != nil && cfg.ShutdownTimeout != nil && cfg.ShutdownTimeout.AsDuration() > 0 {
+		d := cfg.ShutdownTimeout.AsDuration()
 		d = max(d, conf.MinShutdownTimeout)
 		d = min(d, conf.MaxShutdownTimeout)
 		return d
@@ -184,6 +207,34 @@ func (p *PlugPolaris) getShutdownTimeoutDuration() time.Duration {
 	return conf.DefaultShutdownTimeout
 }
 
+// getDrainDelay returns the configured delay between deregistration and SDK teardown
+func (p *PlugPolaris) getDrainDelay() time.Duration {
+	cfg := p.currentConf()
+	if cfg == nil || cfg.DrainDelay == nil {
+		return 0
+	}
+	d := cfg.DrainDelay.AsDuration()
+	d = max(d, 0)
+	d = min(d, conf.MaxDrainDelay)
+	return d
+}
+
+// drain waits for delay after deregistration so that requests still routed to this
+// instance by stale consumer caches can complete. It returns early when ctx is done.
+func drain(ctx context.Context, delay time.Duration) {
+	if delay <= 0 {
+		return
+	}
+	log.Infof("Draining for %v before tearing down Polaris SDK", delay)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		log.Warnf("Drain interrupted: %v", ctx.Err())
+	}
+}
+
 func (p *PlugPolaris) CleanupTasks() error {
 	return p.cleanupTasksContext(context.Background())
 }
@@ -192,14 +243,21 @@ func (p *PlugPolaris) cleanupTasksContext(parentCtx context.Context) error {
 	if err := parentCtx.Err(); err != nil {
 		return err
 	}
-
-	p.mu.Lock()
+	p.cleanupMutex.Lock()
+	defer p.cleanupMutex.Unlock()
 	if !p.IsInitialized() || p.IsDestroyed() {
-		p.mu.Unlock()
 		return nil
 	}
+
+	// Cleanup hooks run while the plugin is still connected and registered
+	hookCtx, cancelHooks := p.createCleanupContext(parentCtx, p.getShutdownTimeoutDuration())
+	p.runCleanupHooks(hookCtx)
+	cancelHooks()
+
+	p.mu.Lock()
 	p.setDestroyed()
 	timeout := p.getShutdownTimeoutDuration()
+	drainDelay := p.getDrainDelay()
 	metrics := p.metrics
 	if metrics != nil {
 		metrics.RecordSDKOperation("cleanup", "start")
@@ -210,15 +268,16 @@ func (p *PlugPolaris) cleanupTasksContext(parentCtx context.Context) error {
 	}
 	p.lifecycleCtx = nil
 	namespace := "unknown"
-	if p.conf != nil {
-		namespace = p.conf.Namespace
+	if cfg := p.currentConf(); cfg != nil {
+		namespace = cfg.Namespace
 	}
-	sdk := p.sdk
+	apis := p.apis
 	polarisClient := p.polaris
 	registrar := p.registrar
-	p.sdk = nil
+	p.setSDKLocked(nil)
 	p.polaris = nil
 	p.registrar = nil
+	p.discovery = nil
 	p.mu.Unlock()
 
 	defer func() {
@@ -228,29 +287,39 @@ func (p *PlugPolaris) cleanupTasksContext(parentCtx context.Context) error {
 		metrics.RecordSDKOperation("cleanup", "success")
 	}()
 
-	log.Infof("Destroying Polaris plugin (shutdown timeout: %v)", timeout)
-
-	p.restoreControlPlane()
-	p.stopHealthCheck()
-	p.cleanupWatchers()
-
-	cleanupCtx, cancel := p.createCleanupContext(parentCtx, timeout)
-	defer cancel()
+	log.Infof("Destroying Polaris plugin (shutdown timeout: %v, drain delay: %v)", timeout, drainDelay)
 
-	// Deregister handed-out registry adapters BEFORE destroying the SDK context.
+	// Deregister handed-out registry adapters first, BEFORE destroying the SDK context.
 	// These adapters wrap the same SDK; deregistering after sdk.Destroy() would be
-	// a use-after-destroy.
+	// a use-after-destroy. Watchers stay up while draining so outbound calls made by
+	// in-flight requests keep resolving.
 	if registrar != nil {
+		deregistered := registrar.hasInstances()
 		func() {
 			defer func() {
 				if r := recover(); r != nil {
 					log.Errorf("polaris registrar teardown panic: %v", r)
 				}
 			}()
-			registrar.Close(cleanupCtx)
+			registrar.Close(parentCtx)
 		}()
+		if deregistered {
+			drain(parentCtx, drainDelay)
+		}
 	}
 
+	p.restoreControlPlane()
+	p.stopHealthCheck()
+	p.cleanupWatchers()
+	if p.events != nil {
+		p.events.close()
+	}
+	p.stopNotifiers()
+	p.closeOpenAPIHTTPClient()
+
+	cleanupCtx, cancel := p.createCleanupContext(parentCtx, timeout)
+	defer cancel()
+
 	done := make(chan struct{})
 	go func() {
 		defer func() {
@@ -259,7 +328,9 @@ func (p *PlugPolaris) cleanupTasksContext(parentCtx context.Context) error {
 			}
 			close(done)
 		}()
-		destroySDKResources(sdk, namespace)
+		// Cache refreshes call the SDK; the lifecycle context is canceled so none start meanwhile
+		p.waitCacheRefreshes()
+		destroySDKResources(apis, namespace)
 		destroyPolarisClient(polarisClient, namespace)
 	}()
 
@@ -292,21 +363,22 @@ func (p *PlugPolaris) createCleanupContext(parentCtx context.Context, timeout ti
 	return context.WithTimeout(parentCtx, timeout)
 }
 
-func destroySDKResources(sdk api.SDKContext, namespace string) {
-	if sdk == nil {
+// destroySDKResources destroys the APIs created from an SDK context, then the context
+func destroySDKResources(apis *sdkAPIs, namespace string) {
+	if apis == nil {
 		return
 	}
 
 	log.Infof("Closing SDK connection")
+	sdk := apis.sdk
 	sdkInfo := map[string]any{
 		"sdk_type":  fmt.Sprintf("%T", sdk),
 		"namespace": namespace,
 	}
 
-	consumerAPI := api.NewConsumerAPIByContext(sdk)
-	providerAPI := api.NewProviderAPIByContext(sdk)
-	configAPI := api.NewConfigFileAPIBySDKContext(sdk)
-	limitAPI := api.NewLimitAPIByContext(sdk)
+	apis.mu.Lock()
+	consumerAPI, providerAPI, configAPI, limitAPI := apis.consumer, apis.provider, apis.config, apis.limit
+	apis.mu.Unlock()
 
 	if consumerAPI != nil {
 		log.Infof("Closing consumer API")
@@ -0,0 +1,86 @@
+package polaris
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-lynx/lynx/log"
+)
+
+// CleanupHook is run by CleanupTasks before the plugin deregisters its instances. ctx is
+// done when the shutdown timeout expires.
+type CleanupHook func(ctx context.Context) error
+
+// registeredCleanupHook is a cleanup hook with its name and ordering
+type registeredCleanupHook struct {
+	name     string
+	hook     CleanupHook
+	priority int
+}
+
+// RegisterCleanupHook adds a hook run by CleanupTasks while the plugin is still connected,
+// before its instances are deregistered, e.g. to flush in-flight work. Hooks run one at a
+// time by ascending priority, and in registration order for equal priorities. A hook
+// registered under an existing name replaces it. All hooks share shutdown_timeout: their
+// ctx is done when it expires, and cleanup moves on without waiting for a hook still
+// running then, skipping the hooks after it. Hook errors are logged.
+func (p *PlugPolaris) RegisterCleanupHook(name string, fn func(ctx context.Context) error, priority int) error {
+	if name == "" || fn == nil {
+		return NewConfigError("cleanup hook name and function are required")
+	}
+	p.cleanupHookMutex.Lock()
+	defer p.cleanupHookMutex.Unlock()
+	p.cleanupHooks = slices.DeleteFunc(p.cleanupHooks, func(h registeredCleanupHook) bool { return h.name == name })
+	p.cleanupHooks = append(p.cleanupHooks, registeredCleanupHook{name: name, hook: fn, priority: priority})
+	return nil
+}
+
+// RemoveCleanupHook removes the cleanup hook registered under name and reports whether
+// there was one
+func (p *PlugPolaris) RemoveCleanupHook(name string) bool {
+	p.cleanupHookMutex.Lock()
+	defer p.cleanupHookMutex.Unlock()
+	n := len(p.cleanupHooks)
+	p.cleanupHooks = slices.DeleteFunc(p.cleanupHooks, func(h registeredCleanupHook) bool { return h.name == name })
+	return len(p.cleanupHooks) < n
+}
+
+// runCleanupHooks runs the cleanup hooks in order until they are done or ctx is.
+func (p *PlugPolaris) runCleanupHooks(ctx context.Context) {
+	p.cleanupHookMutex.Lock()
+	hooks := slices.Clone(p.cleanupHooks)
+	p.cleanupHookMutex.Unlock()
+	// A stable sort keeps the registration order of equal priorities
+	slices.SortStableFunc(hooks, func(a, b registeredCleanupHook) int { return cmp.Compare(a.priority, b.priority) })
+
+	for i, h := range hooks {
+		start := time.Now()
+		done := make(chan error, 1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					done <- fmt.Errorf("panic: %v", r)
+				}
+			}()
+			done <- h.hook(ctx)
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				log.Warnf("Cleanup hook %s failed: %v", h.name, p.redactError(err))
+				continue
+			}
+			log.Infof("Cleanup hook %s finished in %v", h.name, time.Since(start).Round(time.Millisecond))
+		case <-ctx.Done():
+			skipped := make([]string, 0, len(hooks)-i-1)
+			for _, rest := range hooks[i+1:] {
+				skipped = append(skipped, rest.name)
+			}
+			log.Warnf("Cleanup hook %s did not finish before the shutdown timeout, skipping %v", h.name, skipped)
+			return
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterCleanupHook_Order(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	var order []string
+	hook := func(name string, err error) CleanupHook {
+		return func(context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+	require.NoError(t, plugin.RegisterCleanupHook("metrics", hook("metrics", nil), 10))
+	require.NoError(t, plugin.RegisterCleanupHook("queue", hook("queue", errors.New("flush failed")), 0))
+	require.NoError(t, plugin.RegisterCleanupHook("cache", hook("cache", nil), 10))
+	require.NoError(t, plugin.RegisterCleanupHook("panics", func(context.Context) error { panic("boom") }, 5))
+	require.NoError(t, plugin.RegisterCleanupHook("metrics", hook("metrics-v2", nil), 20))
+	require.NoError(t, plugin.RegisterCleanupHook("removed", hook("removed", nil), 1))
+	assert.True(t, plugin.RemoveCleanupHook("removed"))
+	assert.False(t, plugin.RemoveCleanupHook("removed"))
+	assert.Error(t, plugin.RegisterCleanupHook("", hook("", nil), 0))
+	assert.Error(t, plugin.RegisterCleanupHook("nil", nil, 0))
+
+	plugin.runCleanupHooks(context.Background())
+	assert.Equal(t, []string{"queue", "cache", "metrics-v2"}, order, "errors and panics do not stop the hooks after them")
+}
+
+func TestRunCleanupHooks_Timeout(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	var later atomic.Bool
+	require.NoError(t, plugin.RegisterCleanupHook("stuck", func(context.Context) error {
+		<-release
+		return nil
+	}, 0))
+	require.NoError(t, plugin.RegisterCleanupHook("later", func(context.Context) error {
+		later.Store(true)
+		return nil
+	}, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	plugin.runCleanupHooks(ctx)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, later.Load(), "hooks after the timeout are skipped")
+}
+
+func TestCleanupTasks_RunsHooksBeforeDeregistration(t *testing.T) {
+	provider := &fakeClients{}
+	registrar := NewPolarisRegistrar(provider, "default")
+	require.NoError(t, registrar.Register(context.Background(), &registry.ServiceInstance{
+		Name:      "svc",
+		Endpoints: []string{"grpc://10.0.0.1:9000"},
+	}))
+
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	plugin.registrar = registrar
+	atomic.StoreInt32(&plugin.initialized, 1)
+
+	var deregisteredBefore, runs int
+	require.NoError(t, plugin.RegisterCleanupHook("flush", func(context.Context) error {
+		runs++
+		deregisteredBefore = len(provider.deregistered)
+		assert.NoError(t, plugin.checkLifecycle(), "the plugin is still up while hooks run")
+		return nil
+	}, 0))
+
+	require.NoError(t, plugin.CleanupTasks())
+	require.NoError(t, plugin.CleanupTasks())
+	assert.Equal(t, 1, runs)
+	assert.Zero(t, deregisteredBefore)
+	assert.Len(t, provider.deregistered, 1)
+}
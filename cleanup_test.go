@@ -0,0 +1,86 @@
+package polaris
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestGetDrainDelay(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.Zero(t, plugin.getDrainDelay())
+
+	plugin.setConf(&conf.Polaris{DrainDelay: durationpb.New(5 * time.Second)})
+	assert.Equal(t, 5*time.Second, plugin.getDrainDelay())
+
+	plugin.currentConf().DrainDelay = durationpb.New(time.Hour)
+	assert.Equal(t, conf.MaxDrainDelay, plugin.getDrainDelay())
+}
+
+func TestCleanupTasks_DeregistersThenDrains(t *testing.T) {
+	const drainDelay = 100 * time.Millisecond
+
+	provider := &fakeClients{}
+	registrar := NewPolarisRegistrar(provider, "default")
+	require.NoError(t, registrar.Register(context.Background(), &registry.ServiceInstance{
+		Name:      "svc",
+		Endpoints: []string{"grpc://10.0.0.1:9000"},
+	}))
+
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", DrainDelay: durationpb.New(drainDelay)})
+	plugin.registrar = registrar
+	atomic.StoreInt32(&plugin.initialized, 1)
+
+	start := time.Now()
+	require.NoError(t, plugin.CleanupTasks())
+	assert.GreaterOrEqual(t, time.Since(start), drainDelay)
+	assert.Len(t, provider.deregistered, 1)
+	assert.False(t, plugin.IsInitialized())
+}
+
+func TestCleanupTasks_DrainInterruptedByContext(t *testing.T) {
+	provider := &fakeClients{}
+	registrar := NewPolarisRegistrar(provider, "default")
+	require.NoError(t, registrar.Register(context.Background(), &registry.ServiceInstance{
+		Name:      "svc",
+		Endpoints: []string{"grpc://10.0.0.1:9000"},
+	}))
+
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", DrainDelay: durationpb.New(time.Minute)})
+	plugin.registrar = registrar
+	atomic.StoreInt32(&plugin.initialized, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		_ = plugin.cleanupTasksContext(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cleanup did not stop draining when its context was done")
+	}
+	assert.Len(t, provider.deregistered, 1)
+}
+
+func TestValidator_DrainDelay(t *testing.T) {
+	cfg := &conf.Polaris{
+		Namespace:  "default",
+		Weight:     100,
+		Ttl:        30,
+		DrainDelay: durationpb.New(conf.MaxDrainDelay + time.Second),
+	}
+	result := NewValidator(cfg).Validate()
+	assert.False(t, result.IsValid)
+}
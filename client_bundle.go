@@ -0,0 +1,215 @@
+package polaris
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/middleware/circuitbreaker"
+	"github.com/go-kratos/kratos/v2/selector"
+	kgrpc "github.com/go-kratos/kratos/v2/transport/grpc"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// Client bundle module
+// Responsibility: ready-made Kratos client options wiring Polaris discovery, the node
+// router, outbound rate limiting, call result reporting and circuit breaking together.
+
+// ClientOption customizes the client options built by GRPCClientOptions and
+// HTTPClientOptions.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	middleware            []middleware.Middleware
+	nodeFilters           []selector.NodeFilter
+	withoutRouter         bool
+	withoutRateLimit      bool
+	withoutCircuitBreaker bool
+	withoutCallResult     bool
+	withoutFaultInjection bool
+	mirrorService         string
+	mirrorOptions         []MirrorOption
+}
+
+// WithClientMiddleware adds middleware run after the bundled middleware, closest to the
+// call. Kratos clients take a single WithMiddleware option, so additional middleware has to
+// be passed here rather than next to the bundled options.
+func WithClientMiddleware(m ...middleware.Middleware) ClientOption {
+	return func(o *clientOptions) {
+		o.middleware = append(o.middleware, m...)
+	}
+}
+
+// WithClientNodeFilter adds node filters run after the Polaris node router.
+func WithClientNodeFilter(filters ...selector.NodeFilter) ClientOption {
+	return func(o *clientOptions) {
+		o.nodeFilters = append(o.nodeFilters, filters...)
+	}
+}
+
+// WithoutClientRouter leaves out the Polaris node router.
+func WithoutClientRouter() ClientOption {
+	return func(o *clientOptions) {
+		o.withoutRouter = true
+	}
+}
+
+// WithoutClientRateLimit leaves out outbound rate limiting.
+func WithoutClientRateLimit() ClientOption {
+	return func(o *clientOptions) {
+		o.withoutRateLimit = true
+	}
+}
+
+// WithoutClientCircuitBreaker leaves out the client-side circuit breaker. Call results are
+// still reported, so Polaris circuit breaking rules keep isolating failing instances.
+func WithoutClientCircuitBreaker() ClientOption {
+	return func(o *clientOptions) {
+		o.withoutCircuitBreaker = true
+	}
+}
+
+// WithoutClientCallResult leaves out call result reporting.
+func WithoutClientCallResult() ClientOption {
+	return func(o *clientOptions) {
+		o.withoutCallResult = true
+	}
+}
+
+// WithoutClientFaultInjection leaves out fault injection.
+func WithoutClientFaultInjection() ClientOption {
+	return func(o *clientOptions) {
+		o.withoutFaultInjection = true
+	}
+}
+
+// WithClientMirror mirrors the calls to shadowService, with GRPCMirrorMiddleware or
+// HTTPMirrorMiddleware, after fault injection. It applies to the options built by
+// GRPCClientOptions and HTTPClientOptions, not to ClientMiddleware, which does not know the
+// transport.
+func WithClientMirror(shadowService string, opts ...MirrorOption) ClientOption {
+	return func(o *clientOptions) {
+		o.mirrorService = shadowService
+		o.mirrorOptions = append(o.mirrorOptions, opts...)
+	}
+}
+
+// ClientMiddleware returns the middleware bundled for calls to targetService, in order:
+// lane propagation, outbound rate limiting, a per-operation circuit breaker, fault injection, call result
+// reporting and the middleware added by WithClientMiddleware. Rate limited calls fail
+// before reaching the breaker, injected faults count as failures of the breaker, and calls
+// rejected by the breaker or aborted by fault injection are not reported to Polaris.
+func (p *PlugPolaris) ClientMiddleware(targetService string, opts ...ClientOption) []middleware.Middleware {
+	return p.clientMiddleware(targetService, newClientOptions(opts), nil)
+}
+
+// clientMiddleware returns the middleware bundled with o, with mirror after fault injection
+// when it is not nil.
+func (p *PlugPolaris) clientMiddleware(targetService string, o clientOptions, mirror middleware.Middleware) []middleware.Middleware {
+	chain := make([]middleware.Middleware, 0, 6+len(o.middleware))
+	chain = append(chain, p.LaneClientMiddleware())
+	if !o.withoutRateLimit {
+		chain = append(chain, p.OutboundRateLimitMiddleware(targetService))
+	}
+	if !o.withoutCircuitBreaker {
+		chain = append(chain, circuitbreaker.Client())
+	}
+	if !o.withoutFaultInjection {
+		chain = append(chain, p.FaultInjectionMiddleware(targetService))
+	}
+	if mirror != nil {
+		chain = append(chain, mirror)
+	}
+	if !o.withoutCallResult {
+		chain = append(chain, p.CallResultMiddleware())
+	}
+	return append(chain, o.middleware...)
+}
+
+// ClientNodeFilters returns the node filters bundled for calls: the Polaris node router,
+// with this application as the caller service, and those added by WithClientNodeFilter.
+// The router is resolved on the first call after the plugin is initialized, so the filters
+// can be built before the plugin starts; until then, and while the routing subsystem is
+// disabled, nodes are passed through.
+func (p *PlugPolaris) ClientNodeFilters(opts ...ClientOption) []selector.NodeFilter {
+	o := newClientOptions(opts)
+	filters := make([]selector.NodeFilter, 0, 1+len(o.nodeFilters))
+	if !o.withoutRouter {
+		filters = append(filters, p.lazyNodeRouter())
+	}
+	return append(filters, o.nodeFilters...)
+}
+
+// GRPCClientOptions returns the Kratos gRPC client options for calling targetService
+// through Polaris: its discovery endpoint, the discovery returned by BuildDiscovery, the
+// node filters of ClientNodeFilters and the middleware of ClientMiddleware. Further
+// options, e.g. a timeout or TLS, can be appended, but not another WithMiddleware.
+func (p *PlugPolaris) GRPCClientOptions(targetService string, opts ...ClientOption) []kgrpc.ClientOption {
+	o := newClientOptions(opts)
+	var mirror middleware.Middleware
+	if o.mirrorService != "" {
+		mirror = p.GRPCMirrorMiddleware(o.mirrorService, o.mirrorOptions...)
+	}
+	return []kgrpc.ClientOption{
+		kgrpc.WithEndpoint(discoveryEndpoint(targetService)),
+		kgrpc.WithDiscovery(p.BuildDiscovery()),
+		kgrpc.WithNodeFilter(p.ClientNodeFilters(opts...)...),
+		kgrpc.WithMiddleware(p.clientMiddleware(targetService, o, mirror)...),
+	}
+}
+
+// HTTPClientOptions returns the Kratos HTTP client options for calling targetService
+// through Polaris, like GRPCClientOptions.
+func (p *PlugPolaris) HTTPClientOptions(targetService string, opts ...ClientOption) []khttp.ClientOption {
+	o := newClientOptions(opts)
+	var mirror middleware.Middleware
+	if o.mirrorService != "" {
+		mirror = p.HTTPMirrorMiddleware(o.mirrorService, o.mirrorOptions...)
+	}
+	return []khttp.ClientOption{
+		khttp.WithEndpoint(discoveryEndpoint(targetService)),
+		khttp.WithDiscovery(p.BuildDiscovery()),
+		khttp.WithNodeFilter(p.ClientNodeFilters(opts...)...),
+		khttp.WithMiddleware(p.clientMiddleware(targetService, o, mirror)...),
+	}
+}
+
+// newClientOptions applies opts to the default client options.
+func newClientOptions(opts []ClientOption) clientOptions {
+	var o clientOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// discoveryEndpoint returns the Kratos endpoint resolving service through discovery.
+func discoveryEndpoint(service string) string {
+	return "discovery:///" + service
+}
+
+// lazyNodeRouter returns a node filter applying the Polaris node router once the plugin is
+// initialized with the routing subsystem enabled.
+func (p *PlugPolaris) lazyNodeRouter() selector.NodeFilter {
+	var (
+		mu     sync.Mutex
+		router selector.NodeFilter
+	)
+	return func(ctx context.Context, nodes []selector.Node) []selector.Node {
+		if !p.IsInitialized() || !p.SubsystemEnabled(SubsystemRouting) {
+			return nodes
+		}
+		mu.Lock()
+		if router == nil {
+			router = p.NewNodeRouter(currentLynxName())
+		}
+		filter := router
+		mu.Unlock()
+		if filter == nil {
+			return nodes
+		}
+		return filter(ctx, nodes)
+	}
+}
@@ -0,0 +1,100 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/middleware/circuitbreaker"
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientMiddleware_Bundle(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	var order []string
+	tag := func(name string) middleware.Middleware {
+		return func(next middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				order = append(order, name)
+				return next(ctx, req)
+			}
+		}
+	}
+
+	assert.Len(t, plugin.ClientMiddleware("payments"), 5)
+	chain := plugin.ClientMiddleware("payments", WithoutClientRateLimit(), WithoutClientCircuitBreaker(),
+		WithoutClientCallResult(), WithoutClientFaultInjection(), WithClientMiddleware(tag("first"), tag("second")))
+	require.Len(t, chain, 3, "lane propagation is always included")
+	_, err := middleware.Chain(chain...)(func(context.Context, any) (any, error) { return nil, nil })(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestClientMiddleware_UninitializedPluginSendsCalls(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	ctx := transport.NewClientContext(context.Background(), &testClientTransport{operation: "/payments.v1.Payments/Charge"})
+	handler := middleware.Chain(plugin.ClientMiddleware("payments")...)(func(context.Context, any) (any, error) {
+		return "ok", nil
+	})
+	reply, err := handler(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", reply)
+}
+
+func TestClientMiddleware_CircuitBreaker(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	ctx := transport.NewClientContext(context.Background(), &testClientTransport{operation: "/payments.v1.Payments/Charge"})
+	sent := 0
+	handler := middleware.Chain(plugin.ClientMiddleware("payments", WithoutClientRateLimit())...)(func(context.Context, any) (any, error) {
+		sent++
+		return nil, kerrors.ServiceUnavailable("UNAVAILABLE", "down")
+	})
+
+	var rejected error
+	for range 1000 {
+		if _, err := handler(ctx, nil); errors.Is(err, circuitbreaker.ErrNotAllowed) {
+			rejected = err
+			break
+		}
+	}
+	require.Error(t, rejected, "failing operations trip the breaker")
+	assert.Less(t, sent, 1000)
+}
+
+func TestClientNodeFilters(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	var seen int
+	custom := func(_ context.Context, nodes []selector.Node) []selector.Node {
+		seen = len(nodes)
+		return nodes[:1]
+	}
+	filters := plugin.ClientNodeFilters(WithClientNodeFilter(custom))
+	require.Len(t, filters, 2)
+	assert.Empty(t, plugin.ClientNodeFilters(WithoutClientRouter()))
+
+	nodes := []selector.Node{
+		selector.NewNode("grpc", "10.0.0.1:9000", &registry.ServiceInstance{Name: "payments"}),
+		selector.NewNode("grpc", "10.0.0.2:9000", &registry.ServiceInstance{Name: "payments"}),
+	}
+	assert.Equal(t, nodes, filters[0](context.Background(), nodes), "nodes pass through before initialization")
+
+	plugin.setConf(&conf.Polaris{Namespace: "default", Subsystems: &conf.Subsystems{Discovery: true}})
+	plugin.setInitialized()
+	assert.Equal(t, nodes, filters[0](context.Background(), nodes), "nodes pass through with routing disabled")
+	assert.Len(t, filters[1](context.Background(), nodes), 1)
+	assert.Equal(t, 2, seen)
+}
+
+func TestClientOptions(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.Len(t, plugin.GRPCClientOptions("payments"), 4)
+	assert.Len(t, plugin.HTTPClientOptions("payments", WithoutClientRouter()), 4)
+	assert.Equal(t, "discovery:///payments", discoveryEndpoint("payments"))
+}
@@ -0,0 +1,106 @@
+package polaris
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Clock and randomness module
+// Responsibility: the time and random number sources of retries, circuit breakers,
+// heartbeats and watchers, so that tests can advance time and fix jitter instead of
+// sleeping.
+
+// Clock is a source of time. SystemClock, the default everywhere, uses the time package.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is the part of time.Timer used by the plugin
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker is the part of time.Ticker used by the plugin
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// SystemClock is the wall clock of the time package
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) Timer { return systemTimer{time.NewTimer(d)} }
+
+func (systemClock) NewTicker(d time.Duration) Ticker { return systemTicker{time.NewTicker(d)} }
+
+type systemTimer struct{ *time.Timer }
+
+func (t systemTimer) C() <-chan time.Time { return t.Timer.C }
+
+type systemTicker struct{ *time.Ticker }
+
+func (t systemTicker) C() <-chan time.Time { return t.Ticker.C }
+
+// clockOrSystem returns clock, or SystemClock when it is nil
+func clockOrSystem(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+	return clock
+}
+
+// WithClock makes the retries, circuit breaker, heartbeats and watchers of the plugin use
+// clock instead of SystemClock.
+func WithClock(clock Clock) Option {
+	return func(p *PlugPolaris) { p.clock = clockOrSystem(clock) }
+}
+
+// WithRandSource makes the jittered backoff strategies and heartbeats of the plugin draw
+// from src instead of the global random source, so that their delays are reproducible.
+func WithRandSource(src rand.Source) Option {
+	return func(p *PlugPolaris) { p.rand = newJitterRand(src) }
+}
+
+// jitterRand draws the random numbers of jittered delays from a source shared by
+// concurrent callers. A nil jitterRand draws from the global source.
+type jitterRand struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// newJitterRand returns a jitterRand drawing from src, or nil when src is nil
+func newJitterRand(src rand.Source) *jitterRand {
+	if src == nil {
+		return nil
+	}
+	return &jitterRand{rng: rand.New(src)}
+}
+
+// Int64N returns a random number in [0, n)
+func (j *jitterRand) Int64N(n int64) int64 {
+	if j == nil {
+		return rand.Int64N(n)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.rng.Int64N(n)
+}
+
+// Float64 returns a random number in [0, 1)
+func (j *jitterRand) Float64() float64 {
+	if j == nil {
+		return rand.Float64()
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.rng.Float64()
+}
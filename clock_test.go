@@ -0,0 +1,238 @@
+package polaris
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// manualClock is a Clock that only moves when advanced. Timers and tickers fire during
+// Advance, dropping ticks like time.Ticker does when nobody receives them.
+type manualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+func newManualClock() *manualClock {
+	return &manualClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) NewTimer(d time.Duration) Timer { return c.add(d, 0) }
+
+func (c *manualClock) NewTicker(d time.Duration) Ticker { return manualTicker{c.add(d, d)} }
+
+func (c *manualClock) add(d, period time.Duration) *manualTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &manualTimer{clock: c, c: make(chan time.Time, 1), when: c.now.Add(d), period: period, active: true}
+	c.timers = append(c.timers, t)
+	c.fireLocked()
+	return t
+}
+
+// Advance moves the clock forward by d and fires the timers that are due
+func (c *manualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.fireLocked()
+}
+
+func (c *manualClock) fireLocked() {
+	for _, t := range c.timers {
+		if !t.active || t.when.After(c.now) {
+			continue
+		}
+		select {
+		case t.c <- c.now:
+		default:
+		}
+		if t.period <= 0 {
+			t.active = false
+			continue
+		}
+		for !t.when.After(c.now) {
+			t.when = t.when.Add(t.period)
+		}
+	}
+}
+
+// waitForTimers waits until n timers or tickers are pending, i.e. the code under test
+// is waiting on the clock
+func (c *manualClock) waitForTimers(t *testing.T, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		pending := 0
+		for _, timer := range c.timers {
+			if timer.active {
+				pending++
+			}
+		}
+		return pending == n
+	}, 2*time.Second, time.Millisecond)
+}
+
+type manualTimer struct {
+	clock  *manualClock
+	c      chan time.Time
+	when   time.Time
+	period time.Duration
+	active bool
+}
+
+func (t *manualTimer) C() <-chan time.Time { return t.c }
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.active
+	t.active = false
+	return active
+}
+
+func (t *manualTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.active
+	t.when, t.active = t.clock.now.Add(d), true
+	t.clock.fireLocked()
+	return active
+}
+
+type manualTicker struct{ *manualTimer }
+
+func (t manualTicker) Stop() { t.manualTimer.Stop() }
+
+func TestRetryManager_Clock(t *testing.T) {
+	clock := newManualClock()
+	retryManager := NewRetryManager(2, time.Minute, WithRetryBackoff(FixedBackoff), WithRetryClock(clock))
+	var mu sync.Mutex
+	attempts := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- retryManager.DoWithRetryContext(context.Background(), func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts++
+			return assert.AnError
+		})
+	}()
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		clock.waitForTimers(t, 1)
+		mu.Lock()
+		assert.Equal(t, attempt, attempts, "waits for the backoff")
+		mu.Unlock()
+		clock.Advance(time.Minute)
+	}
+	assert.ErrorIs(t, <-done, assert.AnError)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestDoWithHedging_Clock(t *testing.T) {
+	clock := newManualClock()
+	retryManager := NewRetryManager(0, time.Millisecond, WithHedging(time.Second), WithRetryClock(clock))
+	release := make(chan struct{})
+	var mu sync.Mutex
+	runs := 0
+	done := make(chan string, 1)
+	go func() {
+		value, _ := DoWithHedging(context.Background(), retryManager, func(ctx context.Context) (string, error) {
+			mu.Lock()
+			runs++
+			run := runs
+			mu.Unlock()
+			if run == 1 {
+				<-release
+				return "first", nil
+			}
+			return "hedged", nil
+		})
+		done <- value
+	}()
+
+	clock.waitForTimers(t, 1)
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return runs == 1
+	}, 2*time.Second, time.Millisecond)
+	clock.Advance(time.Second)
+	assert.Equal(t, "hedged", <-done)
+	close(release)
+}
+
+func TestJitterBackoff_RandSource(t *testing.T) {
+	delays := func(strategy BackoffStrategy) []time.Duration {
+		var out []time.Duration
+		var previous time.Duration
+		for attempt := range 5 {
+			previous = strategy(attempt, 100*time.Millisecond, 10*time.Second, previous)
+			out = append(out, previous)
+		}
+		return out
+	}
+	full := delays(NewFullJitterBackoff(rand.NewPCG(1, 2)))
+	assert.Equal(t, full, delays(NewFullJitterBackoff(rand.NewPCG(1, 2))), "same seed, same delays")
+	decorrelated := delays(NewDecorrelatedJitterBackoff(rand.NewPCG(1, 2)))
+	assert.Equal(t, decorrelated, delays(NewDecorrelatedJitterBackoff(rand.NewPCG(1, 2))))
+	for _, delay := range decorrelated {
+		assert.GreaterOrEqual(t, delay, 100*time.Millisecond)
+	}
+
+	strategy, ok := backoffStrategyByName(conf.RetryBackoffFullJitter, newJitterRand(rand.NewPCG(1, 2)))
+	require.True(t, ok)
+	assert.Equal(t, full, delays(strategy))
+}
+
+func TestHeartbeatLoop_Clock(t *testing.T) {
+	clock := newManualClock()
+	plugin := NewPolarisControlPlane(WithClock(clock), WithRandSource(rand.NewPCG(1, 2)))
+	plugin.setConf(&conf.Polaris{Namespace: "default", Ttl: 30, Heartbeat: &conf.Heartbeat{
+		Enabled: true, Interval: durationpb.New(10 * time.Second), Jitter: 0.1,
+	}})
+	plugin.mu.Lock()
+	plugin.ensureLifecycleContextLocked()
+	plugin.mu.Unlock()
+	provider := &fakeClients{}
+	plugin.registrar = NewPolarisRegistrar(provider, "default")
+	require.NoError(t, plugin.registrar.Register(context.Background(), &registry.ServiceInstance{
+		Name: "svc", Endpoints: []string{"http://10.0.0.1:8080"},
+	}))
+
+	plugin.startHeartbeat()
+	defer plugin.lifecycleStop()
+	clock.waitForTimers(t, 1)
+	clock.Advance(8 * time.Second)
+	clock.waitForTimers(t, 1)
+	plugin.heartbeatMutex.Lock()
+	assert.True(t, plugin.lastHeartbeat.IsZero(), "not before the jittered interval")
+	plugin.heartbeatMutex.Unlock()
+
+	// The jittered interval is at most 11s, so 3s more always reach it
+	clock.Advance(3 * time.Second)
+	require.Eventually(t, func() bool {
+		plugin.heartbeatMutex.Lock()
+		defer plugin.heartbeatMutex.Unlock()
+		return plugin.lastHeartbeat.Equal(clock.Now())
+	}, 2*time.Second, time.Millisecond)
+	clock.waitForTimers(t, 1)
+	assert.Len(t, provider.heartbeats, 1)
+}
@@ -0,0 +1,95 @@
+package polaris
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventFormat serializes the events delivered by notifiers
+type EventFormat interface {
+	// ContentType is the media type of the serialized events
+	ContentType() string
+	// Marshal serializes event
+	Marshal(event Event) ([]byte, error)
+}
+
+// JSONEventFormat serializes events as their plain JSON. It is the default format.
+var JSONEventFormat EventFormat = jsonEventFormat{}
+
+type jsonEventFormat struct{}
+
+func (jsonEventFormat) ContentType() string { return "application/json" }
+
+func (jsonEventFormat) Marshal(event Event) ([]byte, error) { return json.Marshal(event) }
+
+const (
+	// cloudEventsSpecVersion is the CloudEvents version of the envelopes
+	cloudEventsSpecVersion = "1.0"
+	// CloudEventsTypePrefix prefixes the event type in the type attribute of CloudEvents,
+	// e.g. com.github.go-lynx.polaris.service_changed
+	CloudEventsTypePrefix = "com.github.go-lynx.polaris."
+)
+
+// CloudEvent is a CloudEvents 1.0 envelope in the JSON event format
+type CloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            Event     `json:"data"`
+}
+
+// cloudEventsFormat wraps events in CloudEvents envelopes
+type cloudEventsFormat struct {
+	source string
+}
+
+// NewCloudEventsFormat returns a format wrapping every event in a CloudEvents 1.0 JSON
+// envelope, for Knative or EventBridge style pipelines. The envelope has a random id, the
+// given source, CloudEventsTypePrefix followed by the event type as type, the service or
+// file:group of config events as subject, and the event as data. An empty source defaults
+// to /lynx-polaris/<application name>.
+func NewCloudEventsFormat(source string) EventFormat {
+	if source == "" {
+		source = "/lynx-polaris"
+		if name := currentLynxName(); name != "" {
+			source += "/" + name
+		}
+	}
+	return cloudEventsFormat{source: source}
+}
+
+// ContentType is the media type of structured mode CloudEvents
+func (f cloudEventsFormat) ContentType() string { return "application/cloudevents+json" }
+
+func (f cloudEventsFormat) Marshal(event Event) ([]byte, error) {
+	return json.Marshal(newCloudEvent(f.source, event))
+}
+
+// newCloudEvent wraps event in a CloudEvents envelope from source
+func newCloudEvent(source string, event Event) CloudEvent {
+	occurredAt := event.OccurredAt()
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+	// Events without a service or config file, e.g. health changes, have no subject
+	subject := eventSubject(event)
+	if subject == string(event.Type()) {
+		subject = ""
+	}
+	return CloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              uuid.NewString(),
+		Source:          source,
+		Type:            CloudEventsTypePrefix + string(event.Type()),
+		Subject:         subject,
+		Time:            occurredAt.UTC(),
+		DataContentType: "application/json",
+		Data:            event,
+	}
+}
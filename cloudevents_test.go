@@ -0,0 +1,61 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudEventsFormat(t *testing.T) {
+	format := NewCloudEventsFormat("/orders-api")
+	assert.Equal(t, "application/cloudevents+json", format.ContentType())
+
+	occurredAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	data, err := format.Marshal(&ConfigChangedEvent{
+		Kind: EventTypeConfigChanged, FileName: "app.yaml", Group: "orders", ContentLength: 12, Timestamp: occurredAt,
+	})
+	require.NoError(t, err)
+
+	var envelope map[string]any
+	require.NoError(t, json.Unmarshal(data, &envelope))
+	assert.Equal(t, "1.0", envelope["specversion"])
+	assert.NotEmpty(t, envelope["id"])
+	assert.Equal(t, "/orders-api", envelope["source"])
+	assert.Equal(t, "com.github.go-lynx.polaris.config_changed", envelope["type"])
+	assert.Equal(t, "app.yaml:orders", envelope["subject"])
+	assert.Equal(t, "2026-01-02T03:04:05Z", envelope["time"])
+	assert.Equal(t, "application/json", envelope["datacontenttype"])
+	assert.Equal(t, "app.yaml", envelope["data"].(map[string]any)["file_name"])
+
+	data, err = format.Marshal(&HealthChangedEvent{Kind: EventTypeHealthChanged, Healthy: true})
+	require.NoError(t, err)
+	envelope = nil
+	require.NoError(t, json.Unmarshal(data, &envelope))
+	assert.NotContains(t, envelope, "subject")
+	assert.Equal(t, "/lynx-polaris", NewCloudEventsFormat("").(cloudEventsFormat).source)
+}
+
+func TestWebhookNotifier_CloudEvents(t *testing.T) {
+	var contentType string
+	var envelope struct {
+		Type    string `json:"type"`
+		Subject string `json:"subject"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&envelope)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, WithNotifierFormat(NewCloudEventsFormat("/orders-api")))
+	require.NoError(t, notifier.Notify(context.Background(), &ServiceChangedEvent{Kind: EventTypeServiceChanged, Service: "orders"}))
+	assert.Equal(t, "application/cloudevents+json", contentType)
+	assert.Equal(t, "com.github.go-lynx.polaris.service_changed", envelope.Type)
+	assert.Equal(t, "orders", envelope.Subject)
+}
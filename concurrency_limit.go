@@ -0,0 +1,394 @@
+package polaris
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// concurrencyRule is an enabled Polaris concurrency rule of a service.
+type concurrencyRule struct {
+	matchers    map[string]func(string) bool
+	maxInFlight int
+}
+
+// matches reports whether labels satisfy every matcher of the rule.
+func (r concurrencyRule) matches(labels map[string]string) bool {
+	for key, match := range r.matchers {
+		if !match(labels[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// concurrencyRulesFrom returns the enabled concurrency rules among rules. The method
+// matcher of a rule applies to the "method" label; the smallest amount is the limit.
+func concurrencyRulesFrom(rules []RateLimitRule) []concurrencyRule {
+	var result []concurrencyRule
+	for _, rule := range rules {
+		if rule.Disabled || rule.Resource != "concurrency" || len(rule.Amounts) == 0 {
+			continue
+		}
+		converted := concurrencyRule{matchers: make(map[string]func(string) bool)}
+		for i, amount := range rule.Amounts {
+			if i == 0 || int(amount.MaxAmount) < converted.maxInFlight {
+				converted.maxInFlight = int(amount.MaxAmount)
+			}
+		}
+		if rule.Method != nil {
+			converted.matchers["method"] = rateLimitMatcher(*rule.Method)
+		}
+		for key, match := range rule.Labels {
+			converted.matchers[key] = rateLimitMatcher(match)
+		}
+		result = append(result, converted)
+	}
+	return result
+}
+
+// rateLimitMatcher returns a matcher of values for match. Empty and "*" values match
+// anything, as in Polaris; an invalid regular expression matches nothing.
+func rateLimitMatcher(match RateLimitMatch) func(string) bool {
+	if match.Value == "" || match.Value == "*" {
+		return func(string) bool { return true }
+	}
+	switch match.Type {
+	case "REGEX":
+		re, err := regexp.Compile(match.Value)
+		if err != nil {
+			return func(string) bool { return false }
+		}
+		return re.MatchString
+	case "NOT_EQUALS":
+		return func(value string) bool { return value != match.Value }
+	case "IN":
+		values := strings.Split(match.Value, ",")
+		return func(value string) bool { return slices.Contains(values, value) }
+	case "NOT_IN":
+		values := strings.Split(match.Value, ",")
+		return func(value string) bool { return !slices.Contains(values, value) }
+	default:
+		return func(value string) bool { return value == match.Value }
+	}
+}
+
+// slotPool holds the slots of one service and label set.
+type slotPool struct {
+	slots   chan struct{}
+	waiters int
+}
+
+// concurrencyLimiter tracks the calls in flight per service and label set. Pools exist
+// only while calls hold or wait for their slots.
+type concurrencyLimiter struct {
+	mu     sync.Mutex
+	pools  map[string]*slotPool
+	rules  map[string][]concurrencyRule
+	synced map[string]time.Time
+}
+
+// newConcurrencyLimiter returns a limiter without calls in flight.
+func newConcurrencyLimiter() *concurrencyLimiter {
+	return &concurrencyLimiter{
+		pools:  make(map[string]*slotPool),
+		rules:  make(map[string][]concurrencyRule),
+		synced: make(map[string]time.Time),
+	}
+}
+
+// limit returns the limit of the strictest rule of serviceName matching labels.
+func (l *concurrencyLimiter) limit(serviceName string, labels map[string]string) (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limit, found := 0, false
+	for _, rule := range l.rules[serviceName] {
+		if rule.matches(labels) && (!found || rule.maxInFlight < limit) {
+			limit, found = rule.maxInFlight, true
+		}
+	}
+	return limit, found
+}
+
+// shouldSync reports whether the rules of serviceName are due for a refresh at now, and
+// if so records the refresh.
+func (l *concurrencyLimiter) shouldSync(serviceName string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if last, ok := l.synced[serviceName]; ok && now.Sub(last) < localRateSyncInterval {
+		return false
+	}
+	l.synced[serviceName] = now
+	return true
+}
+
+// setRules replaces the concurrency rules of serviceName.
+func (l *concurrencyLimiter) setRules(serviceName string, rules []concurrencyRule) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rules[serviceName] = rules
+}
+
+// acquire takes a slot of key out of limit, waiting up to maxWait, and returns the
+// function releasing it. It returns false when no slot became free in time.
+func (l *concurrencyLimiter) acquire(ctx context.Context, key string, limit int, maxWait time.Duration) (func(), bool, error) {
+	l.mu.Lock()
+	pool, ok := l.pools[key]
+	if !ok || cap(pool.slots) != limit {
+		// A changed limit applies to new calls; calls in flight release into the old pool.
+		pool = &slotPool{slots: make(chan struct{}, limit)}
+		l.pools[key] = pool
+	}
+	select {
+	case pool.slots <- struct{}{}:
+		l.mu.Unlock()
+		return l.releaser(key, pool), true, nil
+	default:
+	}
+	if maxWait <= 0 {
+		l.mu.Unlock()
+		return nil, false, nil
+	}
+	pool.waiters++
+	l.mu.Unlock()
+
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+	var err error
+	acquired := false
+	select {
+	case pool.slots <- struct{}{}:
+		acquired = true
+	case <-timer.C:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	l.mu.Lock()
+	pool.waiters--
+	l.dropIdle(key, pool)
+	l.mu.Unlock()
+	if !acquired {
+		return nil, false, err
+	}
+	return l.releaser(key, pool), true, nil
+}
+
+// releaser returns the function releasing a slot of pool, effective once.
+func (l *concurrencyLimiter) releaser(key string, pool *slotPool) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			<-pool.slots
+			l.dropIdle(key, pool)
+			l.mu.Unlock()
+		})
+	}
+}
+
+// dropIdle forgets pool once no call holds or waits for its slots. Callers hold l.mu.
+func (l *concurrencyLimiter) dropIdle(key string, pool *slotPool) {
+	if len(pool.slots) == 0 && pool.waiters == 0 && l.pools[key] == pool {
+		delete(l.pools, key)
+	}
+}
+
+// syncConcurrencyRules refreshes the concurrency rules of serviceName from Polaris.
+func (p *PlugPolaris) syncConcurrencyRules(serviceName string) {
+	rules, err := p.fetchRateLimitRules(serviceName)
+	if err != nil {
+		log.Debugf("Failed to sync concurrency rules of service %s: %v", serviceName, err)
+		return
+	}
+	p.concurrencyLimiter.setRules(serviceName, concurrencyRulesFrom(rateLimitRulesFromProto(rules)))
+}
+
+// acquireConcurrencySlot takes a slot of serviceName for a call with labels. The limit is
+// that of the strictest matching Polaris concurrency rule, or concurrency_limit.max_in_flight
+// when none matches; without either the call is unlimited.
+func (p *PlugPolaris) acquireConcurrencySlot(ctx context.Context, serviceName string, labels map[string]string) (func(), error) {
+	if err := p.checkInitialized(); err != nil {
+		return nil, err
+	}
+	if serviceName == "" {
+		serviceName = currentLynxName()
+	}
+	p.mu.RLock()
+	cfg := p.currentConf().GetConcurrencyLimit()
+	labelsCfg := p.currentConf().GetRateLimitLabels()
+	metrics := p.metrics
+	p.mu.RUnlock()
+
+	if p.concurrencyLimiter.shouldSync(serviceName, time.Now()) {
+		p.syncConcurrencyRules(serviceName)
+	}
+	limit, ok := p.concurrencyLimiter.limit(serviceName, labels)
+	if !ok {
+		limit = int(cfg.GetMaxInFlight())
+	}
+	if !ok && limit <= 0 {
+		return func() {}, nil
+	}
+
+	normalized, _ := normalizeRateLimitLabels(labelsCfg, labels)
+	release, acquired, err := p.concurrencyLimiter.acquire(ctx, localBucketKey(serviceName, normalized), limit, cfg.GetMaxWait().AsDuration())
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		if metrics != nil {
+			metrics.RecordConcurrencyRequest(serviceName, "rejected")
+		}
+		return nil, NewServiceError(ErrCodeConcurrencyLimitExceeded, "concurrency limit of service "+serviceName+" exceeded")
+	}
+	if metrics == nil {
+		return release, nil
+	}
+	metrics.RecordConcurrencyRequest(serviceName, "acquired")
+	metrics.AddConcurrencyInFlight(serviceName, 1)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			release()
+			metrics.AddConcurrencyInFlight(serviceName, -1)
+		})
+	}, nil
+}
+
+// AcquireConcurrencySlot takes a concurrency slot of the running application for a call
+// with labels and returns the function that releases it when the call ends. The "method"
+// label is matched against the method of Polaris concurrency rules. When all slots are
+// taken it waits up to concurrency_limit.max_wait, or until ctx is done, and then fails
+// with ErrCodeConcurrencyLimitExceeded.
+func (p *PlugPolaris) AcquireConcurrencySlot(ctx context.Context, labels map[string]string) (func(), error) {
+	return p.acquireConcurrencySlot(ctx, "", labels)
+}
+
+// ConcurrencyLimitOption customizes the concurrency limit middleware.
+type ConcurrencyLimitOption func(*concurrencyLimitOptions)
+
+type concurrencyLimitOptions struct {
+	service string
+	labels  func(ctx context.Context, method string) map[string]string
+}
+
+// WithConcurrencyLimitService sets the Polaris service whose concurrency rules apply. It
+// defaults to the name of the running application.
+func WithConcurrencyLimitService(service string) ConcurrencyLimitOption {
+	return func(o *concurrencyLimitOptions) {
+		o.service = service
+	}
+}
+
+// WithConcurrencyLimitLabels adds the labels returned by fn for each call. They take
+// precedence over the method label.
+func WithConcurrencyLimitLabels(fn func(ctx context.Context, method string) map[string]string) ConcurrencyLimitOption {
+	return func(o *concurrencyLimitOptions) {
+		o.labels = fn
+	}
+}
+
+// concurrencyGate takes slots for the calls of a middleware.
+type concurrencyGate struct {
+	plugin *PlugPolaris
+	opts   concurrencyLimitOptions
+}
+
+// newConcurrencyGate applies opts over the defaults.
+func (p *PlugPolaris) newConcurrencyGate(opts []ConcurrencyLimitOption) *concurrencyGate {
+	gate := &concurrencyGate{plugin: p}
+	for _, opt := range opts {
+		opt(&gate.opts)
+	}
+	return gate
+}
+
+// acquire takes a slot for a call of method. Calls whose limit cannot be checked, e.g.
+// before the plugin is initialized, proceed unlimited.
+func (g *concurrencyGate) acquire(ctx context.Context, method string) (func(), error) {
+	labels := map[string]string{"method": method}
+	if g.opts.labels != nil {
+		for key, value := range g.opts.labels(ctx, method) {
+			labels[key] = value
+		}
+	}
+	release, err := g.plugin.acquireConcurrencySlot(ctx, g.opts.service, labels)
+	if err != nil && !isErrorCode(err, ErrCodeConcurrencyLimitExceeded) && ctx.Err() == nil {
+		log.Warnf("Concurrency limit check failed for %s, allowing call: %v", method, err)
+		return func() {}, nil
+	}
+	return release, err
+}
+
+// ConcurrencyLimitMiddleware returns Kratos server middleware that caps the calls in flight
+// per operation, which is the "method" label. A call over the limit fails with a Kratos
+// error with reason CONCURRENCY_LIMITED and code 429.
+func (p *PlugPolaris) ConcurrencyLimitMiddleware(opts ...ConcurrencyLimitOption) middleware.Middleware {
+	gate := p.newConcurrencyGate(opts)
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			operation := ""
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				operation = tr.Operation()
+			}
+			release, err := gate.acquire(ctx, operation)
+			if err != nil {
+				if isErrorCode(err, ErrCodeConcurrencyLimitExceeded) {
+					return nil, errors.New(http.StatusTooManyRequests, "CONCURRENCY_LIMITED", err.Error())
+				}
+				return nil, err
+			}
+			defer release()
+			return handler(ctx, req)
+		}
+	}
+}
+
+// ConcurrencyLimitHandler returns net/http middleware that caps the requests in flight per
+// URL path, which is the "method" label. A request over the limit gets 429 Too Many Requests.
+func (p *PlugPolaris) ConcurrencyLimitHandler(opts ...ConcurrencyLimitOption) func(http.Handler) http.Handler {
+	gate := p.newConcurrencyGate(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			release, err := gate.acquire(r.Context(), r.URL.Path)
+			if err != nil {
+				if isErrorCode(err, ErrCodeConcurrencyLimitExceeded) {
+					http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				}
+				return
+			}
+			defer release()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GRPCConcurrencyLimitStreamInterceptor returns a gRPC stream server interceptor that caps
+// the streams open per full method name, which is the "method" label, for the lifetime of
+// each stream. A stream over the limit fails with codes.ResourceExhausted.
+func (p *PlugPolaris) GRPCConcurrencyLimitStreamInterceptor(opts ...ConcurrencyLimitOption) grpc.StreamServerInterceptor {
+	gate := p.newConcurrencyGate(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, err := gate.acquire(ss.Context(), info.FullMethod)
+		if err != nil {
+			if isErrorCode(err, ErrCodeConcurrencyLimitExceeded) {
+				return status.Errorf(codes.ResourceExhausted, "concurrency limited by Polaris: %s", info.FullMethod)
+			}
+			return status.FromContextError(err).Err()
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,153 @@
+package polaris
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestRateLimitMatcher(t *testing.T) {
+	assert.True(t, rateLimitMatcher(RateLimitMatch{Type: "EXACT", Value: "Create"})("Create"))
+	assert.False(t, rateLimitMatcher(RateLimitMatch{Type: "EXACT", Value: "Create"})("Delete"))
+	assert.True(t, rateLimitMatcher(RateLimitMatch{Type: "EXACT", Value: "*"})("anything"))
+	assert.True(t, rateLimitMatcher(RateLimitMatch{Type: "REGEX", Value: "^/stream/"})("/stream/events"))
+	assert.False(t, rateLimitMatcher(RateLimitMatch{Type: "REGEX", Value: "("})("("))
+	assert.True(t, rateLimitMatcher(RateLimitMatch{Type: "NOT_EQUALS", Value: "gold"})("silver"))
+	assert.True(t, rateLimitMatcher(RateLimitMatch{Type: "IN", Value: "gold,silver"})("silver"))
+	assert.False(t, rateLimitMatcher(RateLimitMatch{Type: "NOT_IN", Value: "gold,silver"})("gold"))
+}
+
+func TestConcurrencyRulesFrom(t *testing.T) {
+	rules := concurrencyRulesFrom([]RateLimitRule{
+		{Resource: "qps", Amounts: []RateLimitAmount{{MaxAmount: 1}}},
+		{Resource: "concurrency", Disabled: true, Amounts: []RateLimitAmount{{MaxAmount: 1}}},
+		{
+			Resource: "concurrency",
+			Method:   &RateLimitMatch{Type: "EXACT", Value: "Watch"},
+			Amounts:  []RateLimitAmount{{MaxAmount: 20}, {MaxAmount: 5}},
+		},
+	})
+	require.Len(t, rules, 1)
+	assert.Equal(t, 5, rules[0].maxInFlight)
+	assert.True(t, rules[0].matches(map[string]string{"method": "Watch"}))
+	assert.False(t, rules[0].matches(map[string]string{"method": "Get"}))
+}
+
+func TestConcurrencyLimiter_Acquire(t *testing.T) {
+	limiter := newConcurrencyLimiter()
+	release, ok, err := limiter.acquire(context.Background(), "svc", 1, 0)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, ok, err = limiter.acquire(context.Background(), "svc", 1, 0)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	release()
+	release()
+	assert.Empty(t, limiter.pools)
+
+	release, ok, _ = limiter.acquire(context.Background(), "svc", 1, 0)
+	require.True(t, ok)
+	release()
+}
+
+func TestConcurrencyLimiter_Wait(t *testing.T) {
+	limiter := newConcurrencyLimiter()
+	release, ok, _ := limiter.acquire(context.Background(), "svc", 1, 0)
+	require.True(t, ok)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		release()
+	}()
+	waited, ok, err := limiter.acquire(context.Background(), "svc", 1, time.Second)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, ok, err = limiter.acquire(ctx, "svc", 1, time.Second)
+	assert.False(t, ok)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	waited()
+	assert.Empty(t, limiter.pools)
+}
+
+func TestAcquireConcurrencySlot(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	_, err := plugin.AcquireConcurrencySlot(context.Background(), nil)
+	assert.Error(t, err)
+
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	atomic.StoreInt32(&plugin.initialized, 1)
+	release, err := plugin.AcquireConcurrencySlot(context.Background(), nil)
+	require.NoError(t, err, "unlimited without rules or max_in_flight")
+	release()
+
+	plugin.currentConf().ConcurrencyLimit = &conf.ConcurrencyLimit{MaxInFlight: 1, MaxWait: durationpb.New(0)}
+	labels := map[string]string{"method": "Watch"}
+	release, err = plugin.AcquireConcurrencySlot(context.Background(), labels)
+	require.NoError(t, err)
+	_, err = plugin.AcquireConcurrencySlot(context.Background(), labels)
+	assert.True(t, isErrorCode(err, ErrCodeConcurrencyLimitExceeded))
+
+	other, err := plugin.AcquireConcurrencySlot(context.Background(), map[string]string{"method": "Get"})
+	require.NoError(t, err, "label sets have separate slots")
+	other()
+	release()
+}
+
+func TestConcurrencyLimitMiddleware(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	atomic.StoreInt32(&plugin.initialized, 1)
+	plugin.concurrencyLimiter.setRules("orders", []concurrencyRule{{maxInFlight: 0, matchers: map[string]func(string) bool{}}})
+
+	handler := plugin.ConcurrencyLimitMiddleware(WithConcurrencyLimitService("orders"))(func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	_, err := handler(context.Background(), nil)
+	require.Error(t, err)
+	assert.Equal(t, "CONCURRENCY_LIMITED", kerrors.Reason(err))
+	assert.Equal(t, http.StatusTooManyRequests, int(kerrors.Code(err)))
+
+	rec := httptest.NewRecorder()
+	plugin.ConcurrencyLimitHandler(WithConcurrencyLimitService("orders"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+}
+
+func TestConcurrencyLimitMiddleware_NotInitialized(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	handler := plugin.ConcurrencyLimitMiddleware()(func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	reply, err := handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", reply)
+}
+
+func TestValidator_ConcurrencyLimit(t *testing.T) {
+	for _, cl := range []*conf.ConcurrencyLimit{
+		{MaxInFlight: -1},
+		{MaxInFlight: 10, MaxWait: durationpb.New(-time.Second)},
+	} {
+		cfg := &conf.Polaris{Namespace: "default", Weight: 100, ConcurrencyLimit: cl}
+		assert.False(t, NewValidator(cfg).Validate().IsValid)
+	}
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, ConcurrencyLimit: &conf.ConcurrencyLimit{MaxInFlight: 10, MaxWait: durationpb.New(time.Second)}}
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		assert.NotContains(t, e.Field, "concurrency_limit")
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -64,6 +67,7 @@ func TestConcurrentWatcherManagement(t *testing.T) {
 // TestConcurrentCacheAccess tests concurrent cache access
 func TestConcurrentCacheAccess(t *testing.T) {
 	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
 
 	var wg sync.WaitGroup
 	concurrentCount := 50
@@ -74,16 +78,8 @@ func TestConcurrentCacheAccess(t *testing.T) {
 		go func(index int) {
 			defer wg.Done()
 			serviceName := fmt.Sprintf("service-%d", index)
-			// Simulate cache update
-			plugin.cacheMutex.Lock()
-			if plugin.serviceCache == nil {
-				plugin.serviceCache = make(map[string]any)
-			}
-			plugin.serviceCache[serviceName] = map[string]any{
-				"service": serviceName,
-				"index":   index,
-			}
-			plugin.cacheMutex.Unlock()
+			instance := NewStaticInstance("default", serviceName, &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})
+			plugin.updateServiceInstanceCache(serviceName, []model.Instance{instance})
 		}(i)
 	}
 
@@ -92,22 +88,53 @@ func TestConcurrentCacheAccess(t *testing.T) {
 		wg.Add(1)
 		go func(index int) {
 			defer wg.Done()
-			serviceName := fmt.Sprintf("service-%d", index)
-			// Simulate cache reading
-			plugin.cacheMutex.RLock()
-			_ = plugin.serviceCache[serviceName]
-			plugin.cacheMutex.RUnlock()
+			_ = plugin.cachedServiceInstances(fmt.Sprintf("service-%d", index))
 		}(i)
 	}
 
 	wg.Wait()
 
 	// Verify cache consistency
-	plugin.cacheMutex.RLock()
-	cacheSize := len(plugin.serviceCache)
-	plugin.cacheMutex.RUnlock()
+	assert.Equal(t, concurrentCount, plugin.serviceCache.len())
+}
 
-	assert.Equal(t, concurrentCount, cacheSize)
+// TestConcurrentFetchDeduplication tests that concurrent fetches of the same service and
+// config file share one SDK call
+func TestConcurrentFetchDeduplication(t *testing.T) {
+	clients := &fakeClients{
+		instances: []model.Instance{NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})},
+		config:    "workers: 4\n",
+		release:   make(chan struct{}),
+	}
+	plugin := newTestPlugin(t, nil, clients.options()...)
+
+	var started, wg sync.WaitGroup
+	concurrentCount := 200
+	started.Add(concurrentCount)
+	wg.Add(concurrentCount)
+	for i := 0; i < concurrentCount; i++ {
+		go func() {
+			defer wg.Done()
+			started.Done()
+			if i%2 == 0 {
+				instances, err := plugin.GetServiceInstances("orders")
+				assert.NoError(t, err)
+				assert.Len(t, instances, 1)
+				return
+			}
+			content, err := plugin.GetConfigValue("app.yaml", "orders")
+			assert.NoError(t, err)
+			assert.Equal(t, "workers: 4\n", content)
+		}()
+	}
+	started.Wait()
+	// Let the goroutines join the calls in flight before they complete
+	time.Sleep(50 * time.Millisecond)
+	close(clients.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), clients.instanceCalls.Load())
+	assert.Equal(t, int32(1), clients.configCalls.Load())
 }
 
 // TestAtomicOperations tests atomic operations
@@ -173,26 +200,17 @@ func BenchmarkConcurrentStateAccess(b *testing.B) {
 func BenchmarkConcurrentCacheAccess(b *testing.B) {
 	plugin := NewPolarisControlPlane()
 
-	// Initialize cache
-	plugin.cacheMutex.Lock()
-	plugin.serviceCache = make(map[string]any)
-	plugin.cacheMutex.Unlock()
-
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
 		counter := 0
 		for pb.Next() {
-			key := fmt.Sprintf("key-%d", counter)
+			key := fmt.Sprintf("key-%d", counter%1000)
 
 			// Write
-			plugin.cacheMutex.Lock()
-			plugin.serviceCache[key] = counter
-			plugin.cacheMutex.Unlock()
+			plugin.serviceCache.set(key, cachedInstances{service: key})
 
 			// Read
-			plugin.cacheMutex.RLock()
-			_ = plugin.serviceCache[key]
-			plugin.cacheMutex.RUnlock()
+			_, _ = plugin.serviceCache.get(key)
 
 			counter++
 		}
@@ -16,10 +16,42 @@ const (
 	MinWeight     = 1
 	MaxWeight     = 1000
 
+	// Load-based weighting related
+	DefaultAutoWeightInterval = 30 * time.Second
+	MinAutoWeightInterval     = time.Second
+
+	// Warm-up related
+	DefaultWarmUpStepInterval = 5 * time.Second
+	MinWarmUpStepInterval     = time.Second
+	MaxWarmUpDuration         = time.Hour
+
+	// Registration watchdog related
+	DefaultRegistrationWatchdogInterval = 30 * time.Second
+	MinRegistrationWatchdogInterval     = 5 * time.Second
+
+	// Heartbeat related
+	DefaultHeartbeatJitter           = 0.1
+	MaxHeartbeatJitter               = 0.5
+	DefaultHeartbeatFailureThreshold = 3
+	MinHeartbeatInterval             = time.Second
+
+	// Host detection related
+	DefaultHostEnv = "POD_IP"
+
+	// Server bootstrap related
+	MinServerRefreshInterval      = 5 * time.Second
+	DefaultDiscoverPort           = 8091
+	DefaultConfigPort             = 8093
+	DefaultServerFailoverCooldown = 30 * time.Second
+	DefaultServerProbeTimeout     = time.Second
+
 	// TTL related
 	DefaultTTL = 30
 	MinTTL     = 5
 	MaxTTL     = 300
+	// AggressiveTTL is the TTL below which the validator warns that a few slow heartbeats
+	// are enough to take an instance offline
+	AggressiveTTL = 10
 
 	// Timeout related
 	DefaultTimeoutSeconds = 10
@@ -33,6 +65,8 @@ const (
 	DefaultRetryInterval = 1 * time.Second
 	MinRetryInterval     = 100 * time.Millisecond
 	MaxRetryInterval     = 30 * time.Second
+	DefaultRetryMaxDelay = 30 * time.Second
+	MaxRetryMaxDelay     = 10 * time.Minute
 
 	// Circuit breaker related
 	DefaultCircuitBreakerThreshold       = 0.5
@@ -41,6 +75,12 @@ const (
 	DefaultCircuitBreakerHalfOpenTimeout = 30 * time.Second
 	MinCircuitBreakerHalfOpenTimeout     = 5 * time.Second
 	MaxCircuitBreakerHalfOpenTimeout     = 300 * time.Second
+	DefaultCircuitBreakerHalfOpenProbes  = 1
+	MaxCircuitBreakerHalfOpenProbes      = 100
+	DefaultCircuitBreakerWindow          = 60 * time.Second
+	MinCircuitBreakerWindow              = time.Second
+	MaxCircuitBreakerWindow              = 10 * time.Minute
+	DefaultCircuitBreakerMinRequests     = 10
 
 	// Health check related
 	DefaultHealthCheckInterval = 30 * time.Second
@@ -51,6 +91,53 @@ const (
 	DefaultShutdownTimeout = 30 * time.Second
 	MinShutdownTimeout     = 5 * time.Second
 	MaxShutdownTimeout     = 300 * time.Second
+	MaxDrainDelay          = 300 * time.Second
+
+	// Audit related
+	DefaultAuditWebhookTimeout = 5 * time.Second
+
+	// Alerting related
+	DefaultAlertDedupWindow = 5 * time.Minute
+
+	// Events kept for replay by SubscribeEvents
+	DefaultEventHistorySize = 100
+
+	// Workers polling the watched services and config files
+	DefaultWatchWorkers = 8
+
+	// Self-healing related
+	DefaultSelfHealingFailureDuration = 2 * time.Minute
+	DefaultSelfHealingCooldown        = 5 * time.Minute
+
+	// Reported health state related
+	DefaultHealthUnhealthyThreshold = 1
+	DefaultHealthHealthyThreshold   = 1
+	DefaultHealthHistorySize        = 20
+
+	// Standby cluster related
+	DefaultStandbyFailoverAfter   = time.Minute
+	DefaultStandbySwitchbackAfter = 5 * time.Minute
+
+	// Remote config related
+	DefaultRemoteConfigGroup = "DEFAULT_GROUP"
+
+	// Lane routing related
+	DefaultLaneHeader      = "x-lane"
+	DefaultLaneMetadataKey = "lane"
+
+	// Instance and config cache related
+	DefaultCacheTTL        = 30 * time.Second
+	DefaultCacheMaxEntries = 10000
+
+	// Readiness related
+	DefaultReadinessTimeout = 30 * time.Second
+
+	// Token source related
+	DefaultTokenRefreshInterval = 5 * time.Minute
+	MinTokenRefreshInterval     = 10 * time.Second
+
+	// Fault injection related
+	DefaultFaultRuleRefreshInterval = 30 * time.Second
 
 	// Load balancer types
 	LoadBalancerTypeWeightedRandom = "weighted_random"
@@ -67,6 +154,52 @@ const (
 	LogLevelInfo  = "info"
 	LogLevelWarn  = "warn"
 	LogLevelError = "error"
+
+	// Watch partition levels
+	PartitionLevelZone   = "zone"
+	PartitionLevelCampus = "campus"
+
+	// Config merge strategies
+	MergeStrategyOverride = "override"
+	MergeStrategyMerge    = "merge"
+	MergeStrategyAppend   = "append"
+
+	// Rate limit fallback modes
+	RateLimitFallbackAllow = "allow"
+	RateLimitFallbackDeny  = "deny"
+	RateLimitFallbackLocal = "local"
+
+	// Retry backoff strategies
+	RetryBackoffFixed              = "fixed"
+	RetryBackoffExponential        = "exponential"
+	RetryBackoffFullJitter         = "full_jitter"
+	RetryBackoffDecorrelatedJitter = "decorrelated_jitter"
+
+	// Metrics backends
+	MetricsBackendPrometheus = "prometheus"
+	MetricsBackendOTel       = "otel"
+	MetricsBackendLynx       = "lynx"
+
+	// Audit sinks
+	AuditSinkLog     = "log"
+	AuditSinkFile    = "file"
+	AuditSinkWebhook = "webhook"
+	AuditSinkNone    = "none"
+
+	// Alert webhook types
+	AlertWebhookSlack    = "slack"
+	AlertWebhookDingTalk = "dingtalk"
+	AlertWebhookFeishu   = "feishu"
+	AlertWebhookGeneric  = "webhook"
+
+	// Alert severities
+	AlertSeverityInfo     = "info"
+	AlertSeverityWarning  = "warning"
+	AlertSeverityCritical = "critical"
+
+	// Readiness timeout policies
+	ReadinessOnTimeoutFail     = "fail"
+	ReadinessOnTimeoutDegraded = "degraded"
 )
 
 // Supported load balancer types
@@ -83,6 +216,80 @@ var SupportedRateLimitTypes = []string{
 	RateLimitTypeGlobal,
 }
 
+// Supported watch partition levels
+var SupportedPartitionLevels = []string{
+	PartitionLevelZone,
+	PartitionLevelCampus,
+}
+
+// Supported config merge strategies
+var SupportedMergeStrategies = []string{
+	MergeStrategyOverride,
+	MergeStrategyMerge,
+	MergeStrategyAppend,
+}
+
+// Supported rate limit fallback modes
+var SupportedRateLimitFallbackModes = []string{
+	RateLimitFallbackAllow,
+	RateLimitFallbackDeny,
+	RateLimitFallbackLocal,
+}
+
+// Supported readiness timeout policies
+var SupportedReadinessOnTimeout = []string{
+	ReadinessOnTimeoutFail,
+	ReadinessOnTimeoutDegraded,
+}
+
+// Supported retry backoff strategies
+var SupportedRetryBackoffs = []string{
+	RetryBackoffFixed,
+	RetryBackoffExponential,
+	RetryBackoffFullJitter,
+	RetryBackoffDecorrelatedJitter,
+}
+
+// Supported metrics backends
+var SupportedMetricsBackends = []string{
+	MetricsBackendPrometheus,
+	MetricsBackendOTel,
+	MetricsBackendLynx,
+}
+
+// Supported audit sinks
+var SupportedAuditSinks = []string{
+	AuditSinkLog,
+	AuditSinkFile,
+	AuditSinkWebhook,
+	AuditSinkNone,
+}
+
+// Supported alert webhook types
+var SupportedAlertWebhooks = []string{
+	AlertWebhookSlack,
+	AlertWebhookDingTalk,
+	AlertWebhookFeishu,
+	AlertWebhookGeneric,
+}
+
+// Supported alert severities, from lowest to highest
+var SupportedAlertSeverities = []string{
+	AlertSeverityInfo,
+	AlertSeverityWarning,
+	AlertSeverityCritical,
+}
+
+// DefaultSensitiveConfigFiles are the config file name patterns redacted from output when
+// sensitive_config_files is not set
+var DefaultSensitiveConfigFiles = []string{
+	"*secret*",
+	"*credential*",
+	"*password*",
+	"*.key",
+	"*.pem",
+}
+
 // Supported log levels
 var SupportedLogLevels = []string{
 	LogLevelDebug,
@@ -96,6 +96,157 @@ type Polaris struct {
 	LogLevel string `protobuf:"bytes,25,opt,name=log_level,json=logLevel,proto3" json:"log_level,omitempty"`
 	// service_config configuration for remote service configuration loading
 	ServiceConfig *ServiceConfig `protobuf:"bytes,26,opt,name=service_config,json=serviceConfig,proto3" json:"service_config,omitempty"`
+	// fallback_services defines static instances returned when Polaris discovery fails
+	// and no cached instances are available for the service.
+	FallbackServices []*FallbackService `protobuf:"bytes,27,rep,name=fallback_services,json=fallbackServices,proto3" json:"fallback_services,omitempty"`
+	// route_fallbacks defines per-service fallback targets used by discovery and routing
+	// when the primary service has zero healthy instances.
+	RouteFallbacks []*RouteFallback `protobuf:"bytes,28,rep,name=route_fallbacks,json=routeFallbacks,proto3" json:"route_fallbacks,omitempty"`
+	// drain_delay is how long cleanup waits after deregistering the instance before
+	// tearing down the SDK, so that requests routed by other clients' stale caches
+	// can complete. Zero disables draining.
+	DrainDelay *durationpb.Duration `protobuf:"bytes,29,opt,name=drain_delay,json=drainDelay,proto3" json:"drain_delay,omitempty"`
+	// config_staleness defines per-file staleness alarms. A config file whose content
+	// has not changed for longer than max_age is reported as stale in metrics and the
+	// health report.
+	ConfigStaleness []*ConfigStaleness `protobuf:"bytes,30,rep,name=config_staleness,json=configStaleness,proto3" json:"config_staleness,omitempty"`
+	// auto_weight periodically scales the registered weight by the host load.
+	AutoWeight *AutoWeight `protobuf:"bytes,31,opt,name=auto_weight,json=autoWeight,proto3" json:"auto_weight,omitempty"`
+	// server_bootstrap overrides the Polaris server addresses of the SDK configuration,
+	// optionally discovering them through DNS SRV records.
+	ServerBootstrap *ServerBootstrap `protobuf:"bytes,32,opt,name=server_bootstrap,json=serverBootstrap,proto3" json:"server_bootstrap,omitempty"`
+	// warm_up ramps the weight of a newly registered instance up to weight.
+	WarmUp *WarmUp `protobuf:"bytes,33,opt,name=warm_up,json=warmUp,proto3" json:"warm_up,omitempty"`
+	// rate_limit_labels normalizes the labels passed to CheckRateLimit before they reach
+	// the Polaris server, bounding their cardinality.
+	RateLimitLabels *RateLimitLabels `protobuf:"bytes,34,opt,name=rate_limit_labels,json=rateLimitLabels,proto3" json:"rate_limit_labels,omitempty"`
+	// watch_partition restricts service watches to the local zone or campus (cell),
+	// for services too large to watch in full.
+	WatchPartition *WatchPartition `protobuf:"bytes,35,opt,name=watch_partition,json=watchPartition,proto3" json:"watch_partition,omitempty"`
+	// registration_watchdog re-registers instances that disappeared from the registry,
+	// e.g. after a long Polaris outage.
+	RegistrationWatchdog *RegistrationWatchdog `protobuf:"bytes,36,opt,name=registration_watchdog,json=registrationWatchdog,proto3" json:"registration_watchdog,omitempty"`
+	// host_detection selects the address registered for hosts that are empty or
+	// unspecified (0.0.0.0, ::).
+	HostDetection *HostDetection `protobuf:"bytes,37,opt,name=host_detection,json=hostDetection,proto3" json:"host_detection,omitempty"`
+	// heartbeat runs the instance heartbeat loop inside the plugin. Registrations carry
+	// ttl when it is enabled, so Polaris marks instances unhealthy without heartbeats.
+	Heartbeat *Heartbeat `protobuf:"bytes,38,opt,name=heartbeat,proto3" json:"heartbeat,omitempty"`
+	// config_admin enables writing config files through the Polaris config OpenAPI.
+	ConfigAdmin *ConfigAdmin `protobuf:"bytes,39,opt,name=config_admin,json=configAdmin,proto3" json:"config_admin,omitempty"`
+	// config_snapshot persists the latest content of fetched and watched config files
+	// to a local directory, used when Polaris is unreachable.
+	ConfigSnapshot *ConfigSnapshot `protobuf:"bytes,40,opt,name=config_snapshot,json=configSnapshot,proto3" json:"config_snapshot,omitempty"`
+	// config_encryption decrypts ENC(...) values of config files with an AES key.
+	ConfigEncryption *ConfigEncryption `protobuf:"bytes,41,opt,name=config_encryption,json=configEncryption,proto3" json:"config_encryption,omitempty"`
+	// config_labels are the client labels (e.g. env, canary) matched by config gray-release
+	// rules. They are not sent by the current Polaris SDK, whose config protocol has no labels.
+	ConfigLabels map[string]string `protobuf:"bytes,42,rep,name=config_labels,json=configLabels,proto3" json:"config_labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// config_debounce coalesces rapid changes of a watched config file into one callback.
+	ConfigDebounce *ConfigDebounce `protobuf:"bytes,43,opt,name=config_debounce,json=configDebounce,proto3" json:"config_debounce,omitempty"`
+	// required_configs lists config files that must be fetched, or loaded from a snapshot,
+	// before startup completes and the plugin reports healthy.
+	RequiredConfigs *RequiredConfigs `protobuf:"bytes,44,opt,name=required_configs,json=requiredConfigs,proto3" json:"required_configs,omitempty"`
+	// rate_limit_fallback decides rate limit checks while the Polaris limit API fails.
+	RateLimitFallback *RateLimitFallback `protobuf:"bytes,45,opt,name=rate_limit_fallback,json=rateLimitFallback,proto3" json:"rate_limit_fallback,omitempty"`
+	// concurrency_limit caps the calls in flight per method and label set.
+	ConcurrencyLimit *ConcurrencyLimit `protobuf:"bytes,46,opt,name=concurrency_limit,json=concurrencyLimit,proto3" json:"concurrency_limit,omitempty"`
+	// circuit_breaker_open_duration is how long the circuit breaker stays open before it
+	// lets probe requests through. Defaults to 30s.
+	CircuitBreakerOpenDuration *durationpb.Duration `protobuf:"bytes,47,opt,name=circuit_breaker_open_duration,json=circuitBreakerOpenDuration,proto3" json:"circuit_breaker_open_duration,omitempty"`
+	// circuit_breaker_half_open_probes is how many probe requests the half-open circuit
+	// breaker lets through; it closes once all of them succeed. Defaults to 1.
+	CircuitBreakerHalfOpenProbes int32 `protobuf:"varint,48,opt,name=circuit_breaker_half_open_probes,json=circuitBreakerHalfOpenProbes,proto3" json:"circuit_breaker_half_open_probes,omitempty"`
+	// circuit_breaker_window is the sliding window over which the circuit breaker computes
+	// the failure rate. Defaults to 60s.
+	CircuitBreakerWindow *durationpb.Duration `protobuf:"bytes,49,opt,name=circuit_breaker_window,json=circuitBreakerWindow,proto3" json:"circuit_breaker_window,omitempty"`
+	// circuit_breaker_min_requests is how many requests the window must hold before the
+	// failure rate can open the circuit breaker. Defaults to 10.
+	CircuitBreakerMinRequests int32 `protobuf:"varint,50,opt,name=circuit_breaker_min_requests,json=circuitBreakerMinRequests,proto3" json:"circuit_breaker_min_requests,omitempty"`
+	// circuit_breaker_slow_call_threshold makes successful calls slower than it count as
+	// failures of the circuit breaker. Zero disables slow-call detection.
+	CircuitBreakerSlowCallThreshold *durationpb.Duration `protobuf:"bytes,51,opt,name=circuit_breaker_slow_call_threshold,json=circuitBreakerSlowCallThreshold,proto3" json:"circuit_breaker_slow_call_threshold,omitempty"`
+	// retry_backoff strategy of the delay between retries.
+	// Supported: fixed, exponential (default), full_jitter, decorrelated_jitter
+	RetryBackoff string `protobuf:"bytes,52,opt,name=retry_backoff,json=retryBackoff,proto3" json:"retry_backoff,omitempty"`
+	// retry_max_delay caps the delay between retries. Defaults to 30s.
+	RetryMaxDelay *durationpb.Duration `protobuf:"bytes,53,opt,name=retry_max_delay,json=retryMaxDelay,proto3" json:"retry_max_delay,omitempty"`
+	// hedge_delay makes service discovery and config reads that have not completed after it
+	// send a second, hedged request, using whichever answers first. Zero disables hedging.
+	HedgeDelay *durationpb.Duration `protobuf:"bytes,54,opt,name=hedge_delay,json=hedgeDelay,proto3" json:"hedge_delay,omitempty"`
+	// metrics_backend the plugin's metrics are recorded to.
+	// Supported: prometheus (default registry, default), otel (global OpenTelemetry meter
+	// provider), lynx (Lynx metrics handler)
+	MetricsBackend string `protobuf:"bytes,55,opt,name=metrics_backend,json=metricsBackend,proto3" json:"metrics_backend,omitempty"`
+	// audit defines where audit events (service and config changes, watch errors and
+	// registrations) are written and how they are sampled
+	Audit *Audit `protobuf:"bytes,56,opt,name=audit,proto3" json:"audit,omitempty"`
+	// alerting sends alerts on watch errors, degradations, circuit breaker opens and
+	// heartbeat failures to chat and custom webhooks
+	Alerting *Alerting `protobuf:"bytes,57,opt,name=alerting,proto3" json:"alerting,omitempty"`
+	// event_history_size is the number of past events kept for replay by SubscribeEvents
+	// Defaults to 100; a negative value disables replay
+	EventHistorySize int32 `protobuf:"varint,58,opt,name=event_history_size,json=eventHistorySize,proto3" json:"event_history_size,omitempty"`
+	// self_healing rebuilds the SDK context when the background health check keeps failing
+	SelfHealing *SelfHealing `protobuf:"bytes,59,opt,name=self_healing,json=selfHealing,proto3" json:"self_healing,omitempty"`
+	// health_state sets how many consecutive health check results change the reported
+	// health state, and the transitions kept in its history
+	HealthState *HealthState `protobuf:"bytes,60,opt,name=health_state,json=healthState,proto3" json:"health_state,omitempty"`
+	// tls secures the connections to the Polaris servers, for clusters behind TLS or mTLS
+	Tls *Tls `protobuf:"bytes,61,opt,name=tls,proto3" json:"tls,omitempty"`
+	// token_source loads the token from a file or an environment variable instead of token,
+	// and refreshes it periodically
+	TokenSource *TokenSource `protobuf:"bytes,62,opt,name=token_source,json=tokenSource,proto3" json:"token_source,omitempty"`
+	// operation_tokens are the tokens of write operations, keyed by operation: write (every
+	// write operation), config_write (config publish and delete), isolation and weight.
+	// Operations without a token use write, then token.
+	OperationTokens map[string]*OperationToken `protobuf:"bytes,63,rep,name=operation_tokens,json=operationTokens,proto3" json:"operation_tokens,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// sensitive_config_files are glob patterns of config file names, e.g. "*secret*.yaml",
+	// whose content, diffs and digests are redacted from logs, events, audit events and stats.
+	// Matching is case-insensitive on the file name.
+	// Defaults to *secret*, *credential*, *password*, *.key and *.pem
+	SensitiveConfigFiles []string `protobuf:"bytes,64,rep,name=sensitive_config_files,json=sensitiveConfigFiles,proto3" json:"sensitive_config_files,omitempty"`
+	// standby is a standby Polaris cluster the plugin switches to while the primary cluster,
+	// set by server_bootstrap or config_path, is unhealthy
+	Standby *Standby `protobuf:"bytes,65,opt,name=standby,proto3" json:"standby,omitempty"`
+	// discovery_aggregation merges the instances of services registered in several
+	// namespaces, e.g. while services migrate between namespaces
+	DiscoveryAggregation *DiscoveryAggregation `protobuf:"bytes,66,opt,name=discovery_aggregation,json=discoveryAggregation,proto3" json:"discovery_aggregation,omitempty"`
+	// remote_config loads the other settings of the plugin from a Polaris config file at
+	// startup and reloads them whenever the file changes
+	RemoteConfig *RemoteConfig `protobuf:"bytes,67,opt,name=remote_config,json=remoteConfig,proto3" json:"remote_config,omitempty"`
+	// subsystems enables the subsystems of the plugin independently. When set, only the
+	// subsystems set to true are enabled; when not set, all of them are
+	Subsystems *Subsystems `protobuf:"bytes,68,opt,name=subsystems,proto3" json:"subsystems,omitempty"`
+	// readiness defines what WaitReady waits for and what it does when the plugin is not
+	// ready in time
+	Readiness *Readiness `protobuf:"bytes,69,opt,name=readiness,proto3" json:"readiness,omitempty"`
+	// lazy_init creates the SDK context on the first use of Polaris instead of at startup.
+	// Startup steps that use Polaris, such as loading the application config or creating
+	// the registrar, connect right away
+	LazyInit bool `protobuf:"varint,70,opt,name=lazy_init,json=lazyInit,proto3" json:"lazy_init,omitempty"`
+	// dry_run logs and audits registrations, deregistrations, heartbeats, isolation changes
+	// and config writes without sending them to Polaris. Reads work normally
+	DryRun bool `protobuf:"varint,71,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// fault_injection delays and aborts calls made through the client middleware, for chaos
+	// testing. Set it through remote_config to manage the rules from the Polaris console
+	FaultInjection *FaultInjection `protobuf:"bytes,72,opt,name=fault_injection,json=faultInjection,proto3" json:"fault_injection,omitempty"`
+	// lane keeps the calls of a traffic lane, e.g. a canary release, on the instances of the
+	// lane along the whole call chain
+	Lane *Lane `protobuf:"bytes,73,opt,name=lane,proto3" json:"lane,omitempty"`
+	// cache sets the freshness and size of the instance and config caches, which serve
+	// discovery fallbacks and last good config content
+	Cache *Cache `protobuf:"bytes,74,opt,name=cache,proto3" json:"cache,omitempty"`
+	// watch_workers is the number of workers polling the watched services and config files
+	// of the plugin, which share one run loop instead of a goroutine each. Defaults to 8.
+	WatchWorkers int32 `protobuf:"varint,75,opt,name=watch_workers,json=watchWorkers,proto3" json:"watch_workers,omitempty"`
+	// hot_services are downstream services resolved and watched at startup, so that their
+	// instances are cached before the first calls to them
+	HotServices *HotServices `protobuf:"bytes,76,opt,name=hot_services,json=hotServices,proto3" json:"hot_services,omitempty"`
+	// validation tunes the optional checks of the configuration validator. Each field that is
+	// not set reads its environment variable: POLARIS_ENABLE_TOKEN_COMPLEXITY_CHECK,
+	// POLARIS_DISABLE_NAMESPACE_SENSITIVE_CHECK or POLARIS_NAMESPACE_SENSITIVE_WORDS
+	Validation    *Validation `protobuf:"bytes,77,opt,name=validation,proto3" json:"validation,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -305,6 +456,2564 @@ func (x *Polaris) GetServiceConfig() *ServiceConfig {
 	return nil
 }
 
+func (x *Polaris) GetFallbackServices() []*FallbackService {
+	if x != nil {
+		return x.FallbackServices
+	}
+	return nil
+}
+
+func (x *Polaris) GetRouteFallbacks() []*RouteFallback {
+	if x != nil {
+		return x.RouteFallbacks
+	}
+	return nil
+}
+
+func (x *Polaris) GetDrainDelay() *durationpb.Duration {
+	if x != nil {
+		return x.DrainDelay
+	}
+	return nil
+}
+
+func (x *Polaris) GetConfigStaleness() []*ConfigStaleness {
+	if x != nil {
+		return x.ConfigStaleness
+	}
+	return nil
+}
+
+func (x *Polaris) GetAutoWeight() *AutoWeight {
+	if x != nil {
+		return x.AutoWeight
+	}
+	return nil
+}
+
+func (x *Polaris) GetServerBootstrap() *ServerBootstrap {
+	if x != nil {
+		return x.ServerBootstrap
+	}
+	return nil
+}
+
+func (x *Polaris) GetWarmUp() *WarmUp {
+	if x != nil {
+		return x.WarmUp
+	}
+	return nil
+}
+
+func (x *Polaris) GetRateLimitLabels() *RateLimitLabels {
+	if x != nil {
+		return x.RateLimitLabels
+	}
+	return nil
+}
+
+func (x *Polaris) GetWatchPartition() *WatchPartition {
+	if x != nil {
+		return x.WatchPartition
+	}
+	return nil
+}
+
+func (x *Polaris) GetRegistrationWatchdog() *RegistrationWatchdog {
+	if x != nil {
+		return x.RegistrationWatchdog
+	}
+	return nil
+}
+
+func (x *Polaris) GetHostDetection() *HostDetection {
+	if x != nil {
+		return x.HostDetection
+	}
+	return nil
+}
+
+func (x *Polaris) GetHeartbeat() *Heartbeat {
+	if x != nil {
+		return x.Heartbeat
+	}
+	return nil
+}
+
+func (x *Polaris) GetConfigAdmin() *ConfigAdmin {
+	if x != nil {
+		return x.ConfigAdmin
+	}
+	return nil
+}
+
+func (x *Polaris) GetConfigSnapshot() *ConfigSnapshot {
+	if x != nil {
+		return x.ConfigSnapshot
+	}
+	return nil
+}
+
+func (x *Polaris) GetConfigEncryption() *ConfigEncryption {
+	if x != nil {
+		return x.ConfigEncryption
+	}
+	return nil
+}
+
+func (x *Polaris) GetConfigLabels() map[string]string {
+	if x != nil {
+		return x.ConfigLabels
+	}
+	return nil
+}
+
+func (x *Polaris) GetConfigDebounce() *ConfigDebounce {
+	if x != nil {
+		return x.ConfigDebounce
+	}
+	return nil
+}
+
+func (x *Polaris) GetRequiredConfigs() *RequiredConfigs {
+	if x != nil {
+		return x.RequiredConfigs
+	}
+	return nil
+}
+
+func (x *Polaris) GetRateLimitFallback() *RateLimitFallback {
+	if x != nil {
+		return x.RateLimitFallback
+	}
+	return nil
+}
+
+func (x *Polaris) GetConcurrencyLimit() *ConcurrencyLimit {
+	if x != nil {
+		return x.ConcurrencyLimit
+	}
+	return nil
+}
+
+func (x *Polaris) GetCircuitBreakerOpenDuration() *durationpb.Duration {
+	if x != nil {
+		return x.CircuitBreakerOpenDuration
+	}
+	return nil
+}
+
+func (x *Polaris) GetCircuitBreakerHalfOpenProbes() int32 {
+	if x != nil {
+		return x.CircuitBreakerHalfOpenProbes
+	}
+	return 0
+}
+
+func (x *Polaris) GetCircuitBreakerWindow() *durationpb.Duration {
+	if x != nil {
+		return x.CircuitBreakerWindow
+	}
+	return nil
+}
+
+func (x *Polaris) GetCircuitBreakerMinRequests() int32 {
+	if x != nil {
+		return x.CircuitBreakerMinRequests
+	}
+	return 0
+}
+
+func (x *Polaris) GetCircuitBreakerSlowCallThreshold() *durationpb.Duration {
+	if x != nil {
+		return x.CircuitBreakerSlowCallThreshold
+	}
+	return nil
+}
+
+func (x *Polaris) GetRetryBackoff() string {
+	if x != nil {
+		return x.RetryBackoff
+	}
+	return ""
+}
+
+func (x *Polaris) GetRetryMaxDelay() *durationpb.Duration {
+	if x != nil {
+		return x.RetryMaxDelay
+	}
+	return nil
+}
+
+func (x *Polaris) GetHedgeDelay() *durationpb.Duration {
+	if x != nil {
+		return x.HedgeDelay
+	}
+	return nil
+}
+
+func (x *Polaris) GetMetricsBackend() string {
+	if x != nil {
+		return x.MetricsBackend
+	}
+	return ""
+}
+
+func (x *Polaris) GetAudit() *Audit {
+	if x != nil {
+		return x.Audit
+	}
+	return nil
+}
+
+func (x *Polaris) GetAlerting() *Alerting {
+	if x != nil {
+		return x.Alerting
+	}
+	return nil
+}
+
+func (x *Polaris) GetEventHistorySize() int32 {
+	if x != nil {
+		return x.EventHistorySize
+	}
+	return 0
+}
+
+func (x *Polaris) GetSelfHealing() *SelfHealing {
+	if x != nil {
+		return x.SelfHealing
+	}
+	return nil
+}
+
+func (x *Polaris) GetHealthState() *HealthState {
+	if x != nil {
+		return x.HealthState
+	}
+	return nil
+}
+
+func (x *Polaris) GetTls() *Tls {
+	if x != nil {
+		return x.Tls
+	}
+	return nil
+}
+
+func (x *Polaris) GetTokenSource() *TokenSource {
+	if x != nil {
+		return x.TokenSource
+	}
+	return nil
+}
+
+func (x *Polaris) GetOperationTokens() map[string]*OperationToken {
+	if x != nil {
+		return x.OperationTokens
+	}
+	return nil
+}
+
+func (x *Polaris) GetSensitiveConfigFiles() []string {
+	if x != nil {
+		return x.SensitiveConfigFiles
+	}
+	return nil
+}
+
+func (x *Polaris) GetStandby() *Standby {
+	if x != nil {
+		return x.Standby
+	}
+	return nil
+}
+
+func (x *Polaris) GetDiscoveryAggregation() *DiscoveryAggregation {
+	if x != nil {
+		return x.DiscoveryAggregation
+	}
+	return nil
+}
+
+func (x *Polaris) GetRemoteConfig() *RemoteConfig {
+	if x != nil {
+		return x.RemoteConfig
+	}
+	return nil
+}
+
+func (x *Polaris) GetSubsystems() *Subsystems {
+	if x != nil {
+		return x.Subsystems
+	}
+	return nil
+}
+
+func (x *Polaris) GetReadiness() *Readiness {
+	if x != nil {
+		return x.Readiness
+	}
+	return nil
+}
+
+func (x *Polaris) GetLazyInit() bool {
+	if x != nil {
+		return x.LazyInit
+	}
+	return false
+}
+
+func (x *Polaris) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *Polaris) GetFaultInjection() *FaultInjection {
+	if x != nil {
+		return x.FaultInjection
+	}
+	return nil
+}
+
+func (x *Polaris) GetLane() *Lane {
+	if x != nil {
+		return x.Lane
+	}
+	return nil
+}
+
+func (x *Polaris) GetCache() *Cache {
+	if x != nil {
+		return x.Cache
+	}
+	return nil
+}
+
+func (x *Polaris) GetWatchWorkers() int32 {
+	if x != nil {
+		return x.WatchWorkers
+	}
+	return 0
+}
+
+func (x *Polaris) GetHotServices() *HotServices {
+	if x != nil {
+		return x.HotServices
+	}
+	return nil
+}
+
+func (x *Polaris) GetValidation() *Validation {
+	if x != nil {
+		return x.Validation
+	}
+	return nil
+}
+
+// Alerting defines the alert webhooks and how repeated alerts are deduplicated
+type Alerting struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// webhooks receive the alerts
+	Webhooks []*AlertWebhook `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	// dedup_window is how long repeats of an alert for the same subject are suppressed
+	// Defaults to 5m
+	DedupWindow   *durationpb.Duration `protobuf:"bytes,2,opt,name=dedup_window,json=dedupWindow,proto3" json:"dedup_window,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alerting) Reset() {
+	*x = Alerting{}
+	mi := &file_polaris_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alerting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alerting) ProtoMessage() {}
+
+func (x *Alerting) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alerting.ProtoReflect.Descriptor instead.
+func (*Alerting) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Alerting) GetWebhooks() []*AlertWebhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+func (x *Alerting) GetDedupWindow() *durationpb.Duration {
+	if x != nil {
+		return x.DedupWindow
+	}
+	return nil
+}
+
+// AlertWebhook is a webhook receiving alerts
+type AlertWebhook struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// type is the payload format.
+	// Supported: slack, dingtalk, feishu, webhook (the alert as JSON, default)
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// url is the webhook endpoint
+	Url string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// min_severity is the lowest severity sent: info, warning (default) or critical
+	MinSeverity   string `protobuf:"bytes,3,opt,name=min_severity,json=minSeverity,proto3" json:"min_severity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertWebhook) Reset() {
+	*x = AlertWebhook{}
+	mi := &file_polaris_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertWebhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertWebhook) ProtoMessage() {}
+
+func (x *AlertWebhook) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertWebhook.ProtoReflect.Descriptor instead.
+func (*AlertWebhook) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AlertWebhook) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *AlertWebhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *AlertWebhook) GetMinSeverity() string {
+	if x != nil {
+		return x.MinSeverity
+	}
+	return ""
+}
+
+// SelfHealing defines the automatic rebuild of the SDK context
+type SelfHealing struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// enabled turns on self-healing; it requires enable_health_check
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// failure_duration is how long health checks must keep failing before the SDK context
+	// is rebuilt. A destroyed SDK context is rebuilt at the next check.
+	// Defaults to 2m
+	FailureDuration *durationpb.Duration `protobuf:"bytes,2,opt,name=failure_duration,json=failureDuration,proto3" json:"failure_duration,omitempty"`
+	// cooldown is the minimum time between two rebuilds
+	// Defaults to 5m
+	Cooldown      *durationpb.Duration `protobuf:"bytes,3,opt,name=cooldown,proto3" json:"cooldown,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelfHealing) Reset() {
+	*x = SelfHealing{}
+	mi := &file_polaris_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelfHealing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelfHealing) ProtoMessage() {}
+
+func (x *SelfHealing) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelfHealing.ProtoReflect.Descriptor instead.
+func (*SelfHealing) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SelfHealing) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SelfHealing) GetFailureDuration() *durationpb.Duration {
+	if x != nil {
+		return x.FailureDuration
+	}
+	return nil
+}
+
+func (x *SelfHealing) GetCooldown() *durationpb.Duration {
+	if x != nil {
+		return x.Cooldown
+	}
+	return nil
+}
+
+// Standby defines the standby cluster and when the plugin switches to it and back
+type Standby struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// server_bootstrap addresses the standby cluster, like the server_bootstrap of the
+	// primary cluster
+	ServerBootstrap *ServerBootstrap `protobuf:"bytes,1,opt,name=server_bootstrap,json=serverBootstrap,proto3" json:"server_bootstrap,omitempty"`
+	// failover_after is how long health checks must keep failing on the primary cluster
+	// before switching to the standby; it requires enable_health_check
+	// Defaults to 1m
+	FailoverAfter *durationpb.Duration `protobuf:"bytes,2,opt,name=failover_after,json=failoverAfter,proto3" json:"failover_after,omitempty"`
+	// switchback_after is how long the primary cluster must stay reachable before switching
+	// back to it
+	// Defaults to 5m
+	SwitchbackAfter *durationpb.Duration `protobuf:"bytes,3,opt,name=switchback_after,json=switchbackAfter,proto3" json:"switchback_after,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Standby) Reset() {
+	*x = Standby{}
+	mi := &file_polaris_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Standby) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Standby) ProtoMessage() {}
+
+func (x *Standby) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Standby.ProtoReflect.Descriptor instead.
+func (*Standby) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Standby) GetServerBootstrap() *ServerBootstrap {
+	if x != nil {
+		return x.ServerBootstrap
+	}
+	return nil
+}
+
+func (x *Standby) GetFailoverAfter() *durationpb.Duration {
+	if x != nil {
+		return x.FailoverAfter
+	}
+	return nil
+}
+
+func (x *Standby) GetSwitchbackAfter() *durationpb.Duration {
+	if x != nil {
+		return x.SwitchbackAfter
+	}
+	return nil
+}
+
+// DiscoveryAggregation defines the namespaces whose instances are merged by discovery
+type DiscoveryAggregation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// namespaces are queried after namespace, in order. Instances at the same host and port
+	// are kept from the first namespace returning them.
+	Namespaces []string `protobuf:"bytes,1,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	// services limits the aggregation to these services
+	// Defaults to every service
+	Services      []string `protobuf:"bytes,2,rep,name=services,proto3" json:"services,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoveryAggregation) Reset() {
+	*x = DiscoveryAggregation{}
+	mi := &file_polaris_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoveryAggregation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoveryAggregation) ProtoMessage() {}
+
+func (x *DiscoveryAggregation) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoveryAggregation.ProtoReflect.Descriptor instead.
+func (*DiscoveryAggregation) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DiscoveryAggregation) GetNamespaces() []string {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+func (x *DiscoveryAggregation) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+// RemoteConfig defines the Polaris config file holding settings of the plugin
+type RemoteConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// filename is the config file in namespace, in YAML or JSON with the fields of
+	// lynx.polaris, at the top level or under lynx.polaris. Fields set in the file replace
+	// the local ones, except namespace, token, token_source, operation_tokens, config_path,
+	// server_bootstrap, tls, standby, remote_config, metrics_backend and audit.
+	Filename string `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	// group is the configuration group of the file
+	// Defaults to DEFAULT_GROUP
+	Group string `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+	// required fails startup when the file cannot be loaded or holds invalid settings;
+	// otherwise the local settings are used
+	Required      bool `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoteConfig) Reset() {
+	*x = RemoteConfig{}
+	mi := &file_polaris_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoteConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoteConfig) ProtoMessage() {}
+
+func (x *RemoteConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoteConfig.ProtoReflect.Descriptor instead.
+func (*RemoteConfig) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RemoteConfig) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *RemoteConfig) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *RemoteConfig) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+// Subsystems selects the enabled subsystems of the plugin
+type Subsystems struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// registration registers the instances of the application and keeps them alive
+	Registration bool `protobuf:"varint,1,opt,name=registration,proto3" json:"registration,omitempty"`
+	// discovery discovers service instances
+	Discovery bool `protobuf:"varint,2,opt,name=discovery,proto3" json:"discovery,omitempty"`
+	// config loads and watches config files
+	Config bool `protobuf:"varint,3,opt,name=config,proto3" json:"config,omitempty"`
+	// rate_limit checks requests against rate limit rules
+	RateLimit bool `protobuf:"varint,4,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	// routing applies routing rules to the nodes of service calls
+	Routing       bool `protobuf:"varint,5,opt,name=routing,proto3" json:"routing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Subsystems) Reset() {
+	*x = Subsystems{}
+	mi := &file_polaris_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subsystems) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subsystems) ProtoMessage() {}
+
+func (x *Subsystems) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subsystems.ProtoReflect.Descriptor instead.
+func (*Subsystems) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Subsystems) GetRegistration() bool {
+	if x != nil {
+		return x.Registration
+	}
+	return false
+}
+
+func (x *Subsystems) GetDiscovery() bool {
+	if x != nil {
+		return x.Discovery
+	}
+	return false
+}
+
+func (x *Subsystems) GetConfig() bool {
+	if x != nil {
+		return x.Config
+	}
+	return false
+}
+
+func (x *Subsystems) GetRateLimit() bool {
+	if x != nil {
+		return x.RateLimit
+	}
+	return false
+}
+
+func (x *Subsystems) GetRouting() bool {
+	if x != nil {
+		return x.Routing
+	}
+	return false
+}
+
+// Readiness defines the startup readiness gate of WaitReady
+type Readiness struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// timeout is how long WaitReady waits for the plugin to become ready
+	// Defaults to 30s
+	Timeout *durationpb.Duration `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// on_timeout is "fail" to return an error when the plugin is not ready in time, or
+	// "degraded" to continue with a warning and a degradation alert
+	// Defaults to "fail"
+	OnTimeout string `protobuf:"bytes,2,opt,name=on_timeout,json=onTimeout,proto3" json:"on_timeout,omitempty"`
+	// services are the services whose instances must be discovered before the plugin is
+	// ready, warming the discovery cache
+	Services      []string `protobuf:"bytes,3,rep,name=services,proto3" json:"services,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Readiness) Reset() {
+	*x = Readiness{}
+	mi := &file_polaris_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Readiness) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Readiness) ProtoMessage() {}
+
+func (x *Readiness) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Readiness.ProtoReflect.Descriptor instead.
+func (*Readiness) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Readiness) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Readiness) GetOnTimeout() string {
+	if x != nil {
+		return x.OnTimeout
+	}
+	return ""
+}
+
+func (x *Readiness) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+// HealthState defines the damping and history of the reported health state
+type HealthState struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// unhealthy_threshold is the number of consecutive failed health checks that turn a
+	// healthy plugin unhealthy
+	// Defaults to 1
+	UnhealthyThreshold int32 `protobuf:"varint,1,opt,name=unhealthy_threshold,json=unhealthyThreshold,proto3" json:"unhealthy_threshold,omitempty"`
+	// healthy_threshold is the number of consecutive passed health checks that turn an
+	// unhealthy plugin healthy
+	// Defaults to 1
+	HealthyThreshold int32 `protobuf:"varint,2,opt,name=healthy_threshold,json=healthyThreshold,proto3" json:"healthy_threshold,omitempty"`
+	// history_size is the number of health transitions kept
+	// Defaults to 20
+	HistorySize   int32 `protobuf:"varint,3,opt,name=history_size,json=historySize,proto3" json:"history_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthState) Reset() {
+	*x = HealthState{}
+	mi := &file_polaris_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthState) ProtoMessage() {}
+
+func (x *HealthState) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthState.ProtoReflect.Descriptor instead.
+func (*HealthState) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *HealthState) GetUnhealthyThreshold() int32 {
+	if x != nil {
+		return x.UnhealthyThreshold
+	}
+	return 0
+}
+
+func (x *HealthState) GetHealthyThreshold() int32 {
+	if x != nil {
+		return x.HealthyThreshold
+	}
+	return 0
+}
+
+func (x *HealthState) GetHistorySize() int32 {
+	if x != nil {
+		return x.HistorySize
+	}
+	return 0
+}
+
+// Tls defines the TLS settings of the connections to the Polaris servers
+type Tls struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// enabled turns on TLS for the naming, config and config admin connections
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// ca_file is the PEM bundle of the CAs trusted to sign the server certificates.
+	// Defaults to the system roots
+	CaFile string `protobuf:"bytes,2,opt,name=ca_file,json=caFile,proto3" json:"ca_file,omitempty"`
+	// cert_file and key_file are the PEM client certificate and key presented for mTLS
+	CertFile string `protobuf:"bytes,3,opt,name=cert_file,json=certFile,proto3" json:"cert_file,omitempty"`
+	KeyFile  string `protobuf:"bytes,4,opt,name=key_file,json=keyFile,proto3" json:"key_file,omitempty"`
+	// server_name overrides the host name verified in the server certificates
+	ServerName string `protobuf:"bytes,5,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
+	// insecure_skip_verify disables the verification of the server certificates; for
+	// testing only
+	InsecureSkipVerify bool `protobuf:"varint,6,opt,name=insecure_skip_verify,json=insecureSkipVerify,proto3" json:"insecure_skip_verify,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Tls) Reset() {
+	*x = Tls{}
+	mi := &file_polaris_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tls) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tls) ProtoMessage() {}
+
+func (x *Tls) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tls.ProtoReflect.Descriptor instead.
+func (*Tls) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Tls) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Tls) GetCaFile() string {
+	if x != nil {
+		return x.CaFile
+	}
+	return ""
+}
+
+func (x *Tls) GetCertFile() string {
+	if x != nil {
+		return x.CertFile
+	}
+	return ""
+}
+
+func (x *Tls) GetKeyFile() string {
+	if x != nil {
+		return x.KeyFile
+	}
+	return ""
+}
+
+func (x *Tls) GetServerName() string {
+	if x != nil {
+		return x.ServerName
+	}
+	return ""
+}
+
+func (x *Tls) GetInsecureSkipVerify() bool {
+	if x != nil {
+		return x.InsecureSkipVerify
+	}
+	return false
+}
+
+// TokenSource defines where the token is loaded from and how often it is refreshed
+type TokenSource struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// file is read for the token, e.g. a mounted Kubernetes secret or a Vault agent sink.
+	// Surrounding whitespace is trimmed
+	File string `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	// env is the environment variable holding the token
+	Env string `protobuf:"bytes,2,opt,name=env,proto3" json:"env,omitempty"`
+	// refresh_interval is how often the token is loaded again; a changed token is used by
+	// the following requests
+	// Defaults to 5m
+	RefreshInterval *durationpb.Duration `protobuf:"bytes,3,opt,name=refresh_interval,json=refreshInterval,proto3" json:"refresh_interval,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TokenSource) Reset() {
+	*x = TokenSource{}
+	mi := &file_polaris_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenSource) ProtoMessage() {}
+
+func (x *TokenSource) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenSource.ProtoReflect.Descriptor instead.
+func (*TokenSource) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TokenSource) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *TokenSource) GetEnv() string {
+	if x != nil {
+		return x.Env
+	}
+	return ""
+}
+
+func (x *TokenSource) GetRefreshInterval() *durationpb.Duration {
+	if x != nil {
+		return x.RefreshInterval
+	}
+	return nil
+}
+
+// OperationToken is the token of an operation, set inline or loaded like token_source and
+// refreshed at token_source.refresh_interval
+type OperationToken struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	File          string                 `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Env           string                 `protobuf:"bytes,3,opt,name=env,proto3" json:"env,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationToken) Reset() {
+	*x = OperationToken{}
+	mi := &file_polaris_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationToken) ProtoMessage() {}
+
+func (x *OperationToken) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationToken.ProtoReflect.Descriptor instead.
+func (*OperationToken) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *OperationToken) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *OperationToken) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *OperationToken) GetEnv() string {
+	if x != nil {
+		return x.Env
+	}
+	return ""
+}
+
+// Audit defines the sink and sampling of audit events
+type Audit struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sink audit events are written to.
+	// Supported: log (Lynx logger, default), file (JSON lines appended to path),
+	// webhook (JSON POSTed to url), none
+	Sink string `protobuf:"bytes,1,opt,name=sink,proto3" json:"sink,omitempty"`
+	// path is the JSON lines file of the file sink
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// url is the endpoint of the webhook sink
+	Url string `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	// timeout bounds each webhook request
+	// Defaults to 5s
+	Timeout *durationpb.Duration `protobuf:"bytes,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// sample_rate is the fraction of events written, in [0, 1]
+	// Zero means 1, all events are written
+	SampleRate float64 `protobuf:"fixed64,5,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
+	// sample_rates overrides sample_rate per event type, e.g. service_changed: 0.1;
+	// a rate of zero drops every event of the type
+	SampleRates   map[string]float64 `protobuf:"bytes,6,rep,name=sample_rates,json=sampleRates,proto3" json:"sample_rates,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Audit) Reset() {
+	*x = Audit{}
+	mi := &file_polaris_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Audit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Audit) ProtoMessage() {}
+
+func (x *Audit) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Audit.ProtoReflect.Descriptor instead.
+func (*Audit) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Audit) GetSink() string {
+	if x != nil {
+		return x.Sink
+	}
+	return ""
+}
+
+func (x *Audit) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Audit) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Audit) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Audit) GetSampleRate() float64 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+func (x *Audit) GetSampleRates() map[string]float64 {
+	if x != nil {
+		return x.SampleRates
+	}
+	return nil
+}
+
+// RequiredConfigs defines the config files gating startup
+type RequiredConfigs struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// files are the required config files; namespace defaults to the plugin namespace
+	Files []*ConfigFile `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	// wait is how long startup keeps retrying missing files before failing
+	// Zero means a single attempt
+	Wait          *durationpb.Duration `protobuf:"bytes,2,opt,name=wait,proto3" json:"wait,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequiredConfigs) Reset() {
+	*x = RequiredConfigs{}
+	mi := &file_polaris_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequiredConfigs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequiredConfigs) ProtoMessage() {}
+
+func (x *RequiredConfigs) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequiredConfigs.ProtoReflect.Descriptor instead.
+func (*RequiredConfigs) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RequiredConfigs) GetFiles() []*ConfigFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *RequiredConfigs) GetWait() *durationpb.Duration {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+// ConfigDebounce defines how config file changes are coalesced
+type ConfigDebounce struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// window is the quiet period after the last change before callbacks run
+	// Zero disables debouncing
+	Window *durationpb.Duration `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	// max_wait bounds how long callbacks are delayed while changes keep arriving
+	// Defaults to 5 times window
+	MaxWait       *durationpb.Duration `protobuf:"bytes,2,opt,name=max_wait,json=maxWait,proto3" json:"max_wait,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigDebounce) Reset() {
+	*x = ConfigDebounce{}
+	mi := &file_polaris_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigDebounce) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigDebounce) ProtoMessage() {}
+
+func (x *ConfigDebounce) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigDebounce.ProtoReflect.Descriptor instead.
+func (*ConfigDebounce) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ConfigDebounce) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *ConfigDebounce) GetMaxWait() *durationpb.Duration {
+	if x != nil {
+		return x.MaxWait
+	}
+	return nil
+}
+
+// ConfigEncryption defines the built-in AES-GCM decryptor of config files
+type ConfigEncryption struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_env is the environment variable holding the base64 AES key (16, 24 or 32 bytes)
+	KeyEnv        string `protobuf:"bytes,1,opt,name=key_env,json=keyEnv,proto3" json:"key_env,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigEncryption) Reset() {
+	*x = ConfigEncryption{}
+	mi := &file_polaris_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigEncryption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigEncryption) ProtoMessage() {}
+
+func (x *ConfigEncryption) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigEncryption.ProtoReflect.Descriptor instead.
+func (*ConfigEncryption) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ConfigEncryption) GetKeyEnv() string {
+	if x != nil {
+		return x.KeyEnv
+	}
+	return ""
+}
+
+// ConfigSnapshot defines the local directory of config file snapshots
+type ConfigSnapshot struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// dir is the snapshot directory; snapshots are disabled when empty
+	Dir string `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
+	// max_age is the oldest snapshot that may still be loaded
+	// Zero means snapshots never expire
+	MaxAge        *durationpb.Duration `protobuf:"bytes,2,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigSnapshot) Reset() {
+	*x = ConfigSnapshot{}
+	mi := &file_polaris_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigSnapshot) ProtoMessage() {}
+
+func (x *ConfigSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigSnapshot.ProtoReflect.Descriptor instead.
+func (*ConfigSnapshot) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ConfigSnapshot) GetDir() string {
+	if x != nil {
+		return x.Dir
+	}
+	return ""
+}
+
+func (x *ConfigSnapshot) GetMaxAge() *durationpb.Duration {
+	if x != nil {
+		return x.MaxAge
+	}
+	return nil
+}
+
+// ConfigAdmin defines the Polaris HTTP endpoint used to write config files.
+// Writes are authenticated with token.
+type ConfigAdmin struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// address is the base URL of the Polaris HTTP API, e.g. http://polaris:8090
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// timeout bounds each write request
+	// Defaults to timeout
+	Timeout       *durationpb.Duration `protobuf:"bytes,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigAdmin) Reset() {
+	*x = ConfigAdmin{}
+	mi := &file_polaris_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigAdmin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigAdmin) ProtoMessage() {}
+
+func (x *ConfigAdmin) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigAdmin.ProtoReflect.Descriptor instead.
+func (*ConfigAdmin) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ConfigAdmin) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *ConfigAdmin) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+// Heartbeat defines the heartbeat loop of registered instances
+type Heartbeat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// enabled turns on heartbeats
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// interval is the time between heartbeats
+	// Defaults to a third of ttl
+	Interval *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// jitter is the random fraction of interval, in [0, 0.5], added to or removed from each
+	// wait so instances started together do not beat in lockstep
+	// Defaults to 0.1
+	Jitter float64 `protobuf:"fixed64,3,opt,name=jitter,proto3" json:"jitter,omitempty"`
+	// failure_threshold is the number of consecutive failures that triggers the
+	// heartbeat failure callbacks
+	// Defaults to 3
+	FailureThreshold int32 `protobuf:"varint,4,opt,name=failure_threshold,json=failureThreshold,proto3" json:"failure_threshold,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_polaris_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Heartbeat) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Heartbeat) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *Heartbeat) GetJitter() float64 {
+	if x != nil {
+		return x.Jitter
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetFailureThreshold() int32 {
+	if x != nil {
+		return x.FailureThreshold
+	}
+	return 0
+}
+
+// HostDetection defines how the advertised host IP is detected. Rules are tried in
+// order: environment variables, interfaces, CIDRs, then the first global unicast address.
+type HostDetection struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// env lists environment variables holding the IP, e.g. POD_IP
+	// Defaults to POD_IP
+	Env []string `protobuf:"bytes,1,rep,name=env,proto3" json:"env,omitempty"`
+	// interfaces lists preferred network interface names, e.g. eth0
+	Interfaces []string `protobuf:"bytes,2,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	// cidrs lists networks the advertised IP must belong to, e.g. 10.0.0.0/8
+	Cidrs []string `protobuf:"bytes,3,rep,name=cidrs,proto3" json:"cidrs,omitempty"`
+	// prefer_ipv6 prefers IPv6 addresses over IPv4 ones
+	PreferIpv6    bool `protobuf:"varint,4,opt,name=prefer_ipv6,json=preferIpv6,proto3" json:"prefer_ipv6,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostDetection) Reset() {
+	*x = HostDetection{}
+	mi := &file_polaris_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostDetection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostDetection) ProtoMessage() {}
+
+func (x *HostDetection) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostDetection.ProtoReflect.Descriptor instead.
+func (*HostDetection) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *HostDetection) GetEnv() []string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *HostDetection) GetInterfaces() []string {
+	if x != nil {
+		return x.Interfaces
+	}
+	return nil
+}
+
+func (x *HostDetection) GetCidrs() []string {
+	if x != nil {
+		return x.Cidrs
+	}
+	return nil
+}
+
+func (x *HostDetection) GetPreferIpv6() bool {
+	if x != nil {
+		return x.PreferIpv6
+	}
+	return false
+}
+
+// RegistrationWatchdog defines the check that registered instances still exist
+type RegistrationWatchdog struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// enabled turns on the watchdog
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// interval is how often registered instances are looked up
+	// Defaults to 30s
+	Interval      *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegistrationWatchdog) Reset() {
+	*x = RegistrationWatchdog{}
+	mi := &file_polaris_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegistrationWatchdog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegistrationWatchdog) ProtoMessage() {}
+
+func (x *RegistrationWatchdog) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegistrationWatchdog.ProtoReflect.Descriptor instead.
+func (*RegistrationWatchdog) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RegistrationWatchdog) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *RegistrationWatchdog) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+// WatchPartition defines the partition of a service that a watch subscribes to
+type WatchPartition struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// enabled turns on partitioned watches
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// level is the location level to partition by: "zone" (default) or "campus"
+	Level string `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	// zone overrides the zone the SDK detected for the local host
+	Zone string `protobuf:"bytes,3,opt,name=zone,proto3" json:"zone,omitempty"`
+	// campus overrides the campus the SDK detected for the local host
+	Campus string `protobuf:"bytes,4,opt,name=campus,proto3" json:"campus,omitempty"`
+	// metadata selects instances by metadata, e.g. cell: c1. It is passed to the SDK
+	// so the destination metadata router filters before instances reach the watcher
+	Metadata map[string]string `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// services lists the services watched by partition
+	// Empty applies the partition to every watched service
+	Services      []string `protobuf:"bytes,6,rep,name=services,proto3" json:"services,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchPartition) Reset() {
+	*x = WatchPartition{}
+	mi := &file_polaris_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchPartition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPartition) ProtoMessage() {}
+
+func (x *WatchPartition) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPartition.ProtoReflect.Descriptor instead.
+func (*WatchPartition) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *WatchPartition) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *WatchPartition) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *WatchPartition) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *WatchPartition) GetCampus() string {
+	if x != nil {
+		return x.Campus
+	}
+	return ""
+}
+
+func (x *WatchPartition) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *WatchPartition) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+// RateLimitLabels defines how rate limit labels are normalized
+type RateLimitLabels struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// allowed_keys lists the label keys passed to Polaris; other keys are dropped
+	// Empty allows every key
+	AllowedKeys []string `protobuf:"bytes,1,rep,name=allowed_keys,json=allowedKeys,proto3" json:"allowed_keys,omitempty"`
+	// hashed_keys lists label keys with high-cardinality values, such as user IDs,
+	// whose values are replaced by a hash
+	HashedKeys []string `protobuf:"bytes,2,rep,name=hashed_keys,json=hashedKeys,proto3" json:"hashed_keys,omitempty"`
+	// hash_buckets maps hashed values onto this many buckets
+	// Zero keeps the full hash
+	HashBuckets int32 `protobuf:"varint,3,opt,name=hash_buckets,json=hashBuckets,proto3" json:"hash_buckets,omitempty"`
+	// max_labels caps the number of labels per check; keys beyond the cap are dropped
+	// in sorted order. Zero means no cap
+	MaxLabels int32 `protobuf:"varint,4,opt,name=max_labels,json=maxLabels,proto3" json:"max_labels,omitempty"`
+	// max_value_length truncates longer label values
+	// Zero means no limit
+	MaxValueLength int32 `protobuf:"varint,5,opt,name=max_value_length,json=maxValueLength,proto3" json:"max_value_length,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RateLimitLabels) Reset() {
+	*x = RateLimitLabels{}
+	mi := &file_polaris_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimitLabels) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitLabels) ProtoMessage() {}
+
+func (x *RateLimitLabels) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitLabels.ProtoReflect.Descriptor instead.
+func (*RateLimitLabels) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RateLimitLabels) GetAllowedKeys() []string {
+	if x != nil {
+		return x.AllowedKeys
+	}
+	return nil
+}
+
+func (x *RateLimitLabels) GetHashedKeys() []string {
+	if x != nil {
+		return x.HashedKeys
+	}
+	return nil
+}
+
+func (x *RateLimitLabels) GetHashBuckets() int32 {
+	if x != nil {
+		return x.HashBuckets
+	}
+	return 0
+}
+
+func (x *RateLimitLabels) GetMaxLabels() int32 {
+	if x != nil {
+		return x.MaxLabels
+	}
+	return 0
+}
+
+func (x *RateLimitLabels) GetMaxValueLength() int32 {
+	if x != nil {
+		return x.MaxValueLength
+	}
+	return 0
+}
+
+// RateLimitFallback defines the rate limit decision when the Polaris limit API fails
+type RateLimitFallback struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// mode is "allow" (fail-open), "deny" (fail-closed) or "local" (local token bucket)
+	// Empty returns the error to the caller
+	Mode string `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	// local_qps is the rate of the local token bucket of each service in "local" mode
+	LocalQps float64 `protobuf:"fixed64,2,opt,name=local_qps,json=localQps,proto3" json:"local_qps,omitempty"`
+	// local_burst is the capacity of the local token bucket
+	// Zero defaults to local_qps rounded up
+	LocalBurst    int32 `protobuf:"varint,3,opt,name=local_burst,json=localBurst,proto3" json:"local_burst,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLimitFallback) Reset() {
+	*x = RateLimitFallback{}
+	mi := &file_polaris_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimitFallback) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitFallback) ProtoMessage() {}
+
+func (x *RateLimitFallback) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitFallback.ProtoReflect.Descriptor instead.
+func (*RateLimitFallback) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RateLimitFallback) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *RateLimitFallback) GetLocalQps() float64 {
+	if x != nil {
+		return x.LocalQps
+	}
+	return 0
+}
+
+func (x *RateLimitFallback) GetLocalBurst() int32 {
+	if x != nil {
+		return x.LocalBurst
+	}
+	return 0
+}
+
+// ConcurrencyLimit defines the in-flight limit of calls without a Polaris concurrency rule
+type ConcurrencyLimit struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// max_in_flight is the number of calls allowed in flight per method and label set
+	// Zero leaves calls without a Polaris concurrency rule unlimited
+	MaxInFlight int32 `protobuf:"varint,1,opt,name=max_in_flight,json=maxInFlight,proto3" json:"max_in_flight,omitempty"`
+	// max_wait is how long a call waits for a free slot before it is rejected
+	// Zero rejects calls right away when all slots are taken
+	MaxWait       *durationpb.Duration `protobuf:"bytes,2,opt,name=max_wait,json=maxWait,proto3" json:"max_wait,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConcurrencyLimit) Reset() {
+	*x = ConcurrencyLimit{}
+	mi := &file_polaris_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConcurrencyLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConcurrencyLimit) ProtoMessage() {}
+
+func (x *ConcurrencyLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConcurrencyLimit.ProtoReflect.Descriptor instead.
+func (*ConcurrencyLimit) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ConcurrencyLimit) GetMaxInFlight() int32 {
+	if x != nil {
+		return x.MaxInFlight
+	}
+	return 0
+}
+
+func (x *ConcurrencyLimit) GetMaxWait() *durationpb.Duration {
+	if x != nil {
+		return x.MaxWait
+	}
+	return nil
+}
+
+// WarmUp defines the weight ramp applied after registration
+type WarmUp struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// enabled turns on warm-up after registration
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// duration is how long the ramp from initial_weight to weight takes
+	Duration *durationpb.Duration `protobuf:"bytes,2,opt,name=duration,proto3" json:"duration,omitempty"`
+	// initial_weight is the weight the instance registers with
+	// Defaults to 1
+	InitialWeight int32 `protobuf:"varint,3,opt,name=initial_weight,json=initialWeight,proto3" json:"initial_weight,omitempty"`
+	// step_interval is how often the weight is raised during the ramp
+	// Defaults to 5s
+	StepInterval  *durationpb.Duration `protobuf:"bytes,4,opt,name=step_interval,json=stepInterval,proto3" json:"step_interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WarmUp) Reset() {
+	*x = WarmUp{}
+	mi := &file_polaris_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WarmUp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WarmUp) ProtoMessage() {}
+
+func (x *WarmUp) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WarmUp.ProtoReflect.Descriptor instead.
+func (*WarmUp) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *WarmUp) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *WarmUp) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *WarmUp) GetInitialWeight() int32 {
+	if x != nil {
+		return x.InitialWeight
+	}
+	return 0
+}
+
+func (x *WarmUp) GetStepInterval() *durationpb.Duration {
+	if x != nil {
+		return x.StepInterval
+	}
+	return nil
+}
+
+// ServerBootstrap defines how the Polaris server addresses are found
+type ServerBootstrap struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// addresses are naming server addresses as host:port. Hosts may be DNS names; they
+	// are passed to the SDK unresolved so every reconnect picks up DNS changes.
+	Addresses []string `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	// config_addresses are config center addresses as host:port
+	ConfigAddresses []string `protobuf:"bytes,2,rep,name=config_addresses,json=configAddresses,proto3" json:"config_addresses,omitempty"`
+	// srv_record is a DNS SRV name, e.g. _polaris._tcp.example.com, whose targets are
+	// added to addresses
+	SrvRecord string `protobuf:"bytes,3,opt,name=srv_record,json=srvRecord,proto3" json:"srv_record,omitempty"`
+	// config_srv_record is a DNS SRV name whose targets are added to config_addresses
+	ConfigSrvRecord string `protobuf:"bytes,4,opt,name=config_srv_record,json=configSrvRecord,proto3" json:"config_srv_record,omitempty"`
+	// refresh_interval is how often the SRV records and the A/AAAA records of the server
+	// hosts are re-resolved; changes rebuild the SDK context
+	// Zero disables refresh
+	RefreshInterval *durationpb.Duration `protobuf:"bytes,5,opt,name=refresh_interval,json=refreshInterval,proto3" json:"refresh_interval,omitempty"`
+	// hosts are Polaris server hosts, combined with discover_port into addresses and with
+	// config_port into config_addresses
+	Hosts []string `protobuf:"bytes,6,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	// discover_port is the naming port of hosts
+	// Defaults to 8091
+	DiscoverPort uint32 `protobuf:"varint,7,opt,name=discover_port,json=discoverPort,proto3" json:"discover_port,omitempty"`
+	// config_port is the config center port of hosts
+	// Defaults to 8093
+	ConfigPort uint32 `protobuf:"varint,8,opt,name=config_port,json=configPort,proto3" json:"config_port,omitempty"`
+	// limiter_namespace and limiter_service name the distributed rate limit servers, which
+	// the SDK discovers through the naming servers
+	// Default to Polaris and polaris.limiter
+	LimiterNamespace string `protobuf:"bytes,9,opt,name=limiter_namespace,json=limiterNamespace,proto3" json:"limiter_namespace,omitempty"`
+	LimiterService   string `protobuf:"bytes,10,opt,name=limiter_service,json=limiterService,proto3" json:"limiter_service,omitempty"`
+	// failover_cooldown is how long a server that failed to connect is skipped while other
+	// servers are available
+	// Defaults to 30s
+	FailoverCooldown *durationpb.Duration `protobuf:"bytes,11,opt,name=failover_cooldown,json=failoverCooldown,proto3" json:"failover_cooldown,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ServerBootstrap) Reset() {
+	*x = ServerBootstrap{}
+	mi := &file_polaris_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerBootstrap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerBootstrap) ProtoMessage() {}
+
+func (x *ServerBootstrap) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerBootstrap.ProtoReflect.Descriptor instead.
+func (*ServerBootstrap) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ServerBootstrap) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *ServerBootstrap) GetConfigAddresses() []string {
+	if x != nil {
+		return x.ConfigAddresses
+	}
+	return nil
+}
+
+func (x *ServerBootstrap) GetSrvRecord() string {
+	if x != nil {
+		return x.SrvRecord
+	}
+	return ""
+}
+
+func (x *ServerBootstrap) GetConfigSrvRecord() string {
+	if x != nil {
+		return x.ConfigSrvRecord
+	}
+	return ""
+}
+
+func (x *ServerBootstrap) GetRefreshInterval() *durationpb.Duration {
+	if x != nil {
+		return x.RefreshInterval
+	}
+	return nil
+}
+
+func (x *ServerBootstrap) GetHosts() []string {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+func (x *ServerBootstrap) GetDiscoverPort() uint32 {
+	if x != nil {
+		return x.DiscoverPort
+	}
+	return 0
+}
+
+func (x *ServerBootstrap) GetConfigPort() uint32 {
+	if x != nil {
+		return x.ConfigPort
+	}
+	return 0
+}
+
+func (x *ServerBootstrap) GetLimiterNamespace() string {
+	if x != nil {
+		return x.LimiterNamespace
+	}
+	return ""
+}
+
+func (x *ServerBootstrap) GetLimiterService() string {
+	if x != nil {
+		return x.LimiterService
+	}
+	return ""
+}
+
+func (x *ServerBootstrap) GetFailoverCooldown() *durationpb.Duration {
+	if x != nil {
+		return x.FailoverCooldown
+	}
+	return nil
+}
+
+// AutoWeight defines load-based adjustment of the registered instance weight
+type AutoWeight struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// enabled turns on load-based weighting
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// interval is how often the load is sampled
+	// Defaults to 30s
+	Interval *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// min_weight is the lowest weight applied under full load
+	// Defaults to 1
+	MinWeight     int32 `protobuf:"varint,3,opt,name=min_weight,json=minWeight,proto3" json:"min_weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AutoWeight) Reset() {
+	*x = AutoWeight{}
+	mi := &file_polaris_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AutoWeight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AutoWeight) ProtoMessage() {}
+
+func (x *AutoWeight) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AutoWeight.ProtoReflect.Descriptor instead.
+func (*AutoWeight) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *AutoWeight) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *AutoWeight) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *AutoWeight) GetMinWeight() int32 {
+	if x != nil {
+		return x.MinWeight
+	}
+	return 0
+}
+
+// ConfigStaleness defines the staleness alarm of a single config file
+type ConfigStaleness struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// file_name is the name of the config file
+	FileName string `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	// group is the group of the config file
+	Group string `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+	// max_age is how long the content may go without changing before it is stale
+	MaxAge        *durationpb.Duration `protobuf:"bytes,3,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigStaleness) Reset() {
+	*x = ConfigStaleness{}
+	mi := &file_polaris_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigStaleness) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigStaleness) ProtoMessage() {}
+
+func (x *ConfigStaleness) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigStaleness.ProtoReflect.Descriptor instead.
+func (*ConfigStaleness) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ConfigStaleness) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *ConfigStaleness) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ConfigStaleness) GetMaxAge() *durationpb.Duration {
+	if x != nil {
+		return x.MaxAge
+	}
+	return nil
+}
+
+// FallbackService defines the static fallback instances of a single service
+type FallbackService struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// service is the name of the service the instances belong to
+	Service string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	// instances are the static endpoints returned for the service
+	Instances     []*FallbackInstance `protobuf:"bytes,2,rep,name=instances,proto3" json:"instances,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FallbackService) Reset() {
+	*x = FallbackService{}
+	mi := &file_polaris_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FallbackService) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FallbackService) ProtoMessage() {}
+
+func (x *FallbackService) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FallbackService.ProtoReflect.Descriptor instead.
+func (*FallbackService) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *FallbackService) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *FallbackService) GetInstances() []*FallbackInstance {
+	if x != nil {
+		return x.Instances
+	}
+	return nil
+}
+
+// FallbackInstance defines a single static service endpoint
+type FallbackInstance struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// host is the IP address or domain name of the instance
+	Host string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	// port is the listening port of the instance
+	Port uint32 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	// protocol is the protocol exposed by the instance, e.g. grpc or http
+	Protocol string `protobuf:"bytes,3,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	// version is the version of the instance
+	Version string `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	// weight is the static weight of the instance
+	// If zero, the plugin default weight is used
+	Weight int32 `protobuf:"varint,5,opt,name=weight,proto3" json:"weight,omitempty"`
+	// metadata is additional metadata attached to the instance
+	Metadata      map[string]string `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FallbackInstance) Reset() {
+	*x = FallbackInstance{}
+	mi := &file_polaris_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FallbackInstance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FallbackInstance) ProtoMessage() {}
+
+func (x *FallbackInstance) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FallbackInstance.ProtoReflect.Descriptor instead.
+func (*FallbackInstance) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *FallbackInstance) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *FallbackInstance) GetPort() uint32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *FallbackInstance) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *FallbackInstance) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *FallbackInstance) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *FallbackInstance) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 // ServiceConfig defines configuration for loading remote service configurations
 type ServiceConfig struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -324,21 +3033,324 @@ type ServiceConfig struct {
 	sizeCache         protoimpl.SizeCache
 }
 
-func (x *ServiceConfig) Reset() {
-	*x = ServiceConfig{}
-	mi := &file_polaris_proto_msgTypes[1]
+func (x *ServiceConfig) Reset() {
+	*x = ServiceConfig{}
+	mi := &file_polaris_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceConfig) ProtoMessage() {}
+
+func (x *ServiceConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceConfig.ProtoReflect.Descriptor instead.
+func (*ServiceConfig) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ServiceConfig) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ServiceConfig) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ServiceConfig) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ServiceConfig) GetAdditionalConfigs() []*ConfigFile {
+	if x != nil {
+		return x.AdditionalConfigs
+	}
+	return nil
+}
+
+// ConfigFile defines a single configuration file to load
+type ConfigFile struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// group is the configuration group name
+	Group string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	// filename is the configuration file name
+	Filename string `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	// namespace is the namespace for this specific configuration
+	// If empty, uses the service_config namespace
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// priority defines the merge priority (higher number = higher priority)
+	// Default is 0, higher priority configs will override lower priority ones
+	Priority int32 `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
+	// merge_strategy defines how to handle conflicts with existing configs
+	// "override" (default): later configs override earlier ones
+	// "merge": merge nested objects, override leaf values
+	// "append": append to arrays, override other values
+	MergeStrategy string `protobuf:"bytes,5,opt,name=merge_strategy,json=mergeStrategy,proto3" json:"merge_strategy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigFile) Reset() {
+	*x = ConfigFile{}
+	mi := &file_polaris_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigFile) ProtoMessage() {}
+
+func (x *ConfigFile) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigFile.ProtoReflect.Descriptor instead.
+func (*ConfigFile) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ConfigFile) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ConfigFile) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ConfigFile) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ConfigFile) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *ConfigFile) GetMergeStrategy() string {
+	if x != nil {
+		return x.MergeStrategy
+	}
+	return ""
+}
+
+// RouteFallback defines the backend that receives traffic of a service without healthy instances
+type RouteFallback struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// service is the name of the primary service
+	Service string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	// target_service is another service to route to, e.g. a read-only replica or a static error service
+	// Takes precedence over target_instances when set
+	TargetService string `protobuf:"bytes,2,opt,name=target_service,json=targetService,proto3" json:"target_service,omitempty"`
+	// target_instances are static endpoints used when target_service is empty
+	TargetInstances []*FallbackInstance `protobuf:"bytes,3,rep,name=target_instances,json=targetInstances,proto3" json:"target_instances,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RouteFallback) Reset() {
+	*x = RouteFallback{}
+	mi := &file_polaris_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteFallback) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteFallback) ProtoMessage() {}
+
+func (x *RouteFallback) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteFallback.ProtoReflect.Descriptor instead.
+func (*RouteFallback) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RouteFallback) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *RouteFallback) GetTargetService() string {
+	if x != nil {
+		return x.TargetService
+	}
+	return ""
+}
+
+func (x *RouteFallback) GetTargetInstances() []*FallbackInstance {
+	if x != nil {
+		return x.TargetInstances
+	}
+	return nil
+}
+
+// FaultInjection defines the faults injected into outgoing calls
+type FaultInjection struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// enabled turns the rules on. Rules are ignored while it is false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// rules are matched in order; the first rule matching a call applies
+	Rules []*FaultInjectionRule `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+	// rules_path is the resource of the Polaris OpenAPI at config_admin.address serving the
+	// rules of the namespace, as a data list of rules in this format. Once loaded, they replace
+	// rules, which stay in effect while the resource cannot be read
+	RulesPath string `protobuf:"bytes,3,opt,name=rules_path,json=rulesPath,proto3" json:"rules_path,omitempty"`
+	// refresh_interval is how often the rules are loaded from rules_path. Defaults to 30s
+	RefreshInterval *durationpb.Duration `protobuf:"bytes,4,opt,name=refresh_interval,json=refreshInterval,proto3" json:"refresh_interval,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *FaultInjection) Reset() {
+	*x = FaultInjection{}
+	mi := &file_polaris_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FaultInjection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FaultInjection) ProtoMessage() {}
+
+func (x *FaultInjection) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FaultInjection.ProtoReflect.Descriptor instead.
+func (*FaultInjection) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *FaultInjection) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *FaultInjection) GetRules() []*FaultInjectionRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *FaultInjection) GetRulesPath() string {
+	if x != nil {
+		return x.RulesPath
+	}
+	return ""
+}
+
+func (x *FaultInjection) GetRefreshInterval() *durationpb.Duration {
+	if x != nil {
+		return x.RefreshInterval
+	}
+	return nil
+}
+
+// FaultInjectionRule defines the faults of the calls to a service
+type FaultInjectionRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name identifies the rule in logs
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// service is the called service. Empty matches every service
+	Service string `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	// operation is the called operation, e.g. /orders.v1.Orders/GetOrder. A trailing * matches
+	// every operation with that prefix. Empty matches every operation
+	Operation string `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`
+	// delay delays matched calls before they are sent
+	Delay *FaultDelay `protobuf:"bytes,4,opt,name=delay,proto3" json:"delay,omitempty"`
+	// abort fails matched calls without sending them, after the delay
+	Abort         *FaultAbort `protobuf:"bytes,5,opt,name=abort,proto3" json:"abort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FaultInjectionRule) Reset() {
+	*x = FaultInjectionRule{}
+	mi := &file_polaris_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ServiceConfig) String() string {
+func (x *FaultInjectionRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ServiceConfig) ProtoMessage() {}
+func (*FaultInjectionRule) ProtoMessage() {}
 
-func (x *ServiceConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_polaris_proto_msgTypes[1]
+func (x *FaultInjectionRule) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -349,76 +3361,72 @@ func (x *ServiceConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ServiceConfig.ProtoReflect.Descriptor instead.
-func (*ServiceConfig) Descriptor() ([]byte, []int) {
-	return file_polaris_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use FaultInjectionRule.ProtoReflect.Descriptor instead.
+func (*FaultInjectionRule) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *ServiceConfig) GetGroup() string {
+func (x *FaultInjectionRule) GetName() string {
 	if x != nil {
-		return x.Group
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ServiceConfig) GetFilename() string {
+func (x *FaultInjectionRule) GetService() string {
 	if x != nil {
-		return x.Filename
+		return x.Service
 	}
 	return ""
 }
 
-func (x *ServiceConfig) GetNamespace() string {
+func (x *FaultInjectionRule) GetOperation() string {
 	if x != nil {
-		return x.Namespace
+		return x.Operation
 	}
 	return ""
 }
 
-func (x *ServiceConfig) GetAdditionalConfigs() []*ConfigFile {
+func (x *FaultInjectionRule) GetDelay() *FaultDelay {
 	if x != nil {
-		return x.AdditionalConfigs
+		return x.Delay
 	}
 	return nil
 }
 
-// ConfigFile defines a single configuration file to load
-type ConfigFile struct {
+func (x *FaultInjectionRule) GetAbort() *FaultAbort {
+	if x != nil {
+		return x.Abort
+	}
+	return nil
+}
+
+// FaultDelay defines a delay injected into calls
+type FaultDelay struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// group is the configuration group name
-	Group string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
-	// filename is the configuration file name
-	Filename string `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
-	// namespace is the namespace for this specific configuration
-	// If empty, uses the service_config namespace
-	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	// priority defines the merge priority (higher number = higher priority)
-	// Default is 0, higher priority configs will override lower priority ones
-	Priority int32 `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
-	// merge_strategy defines how to handle conflicts with existing configs
-	// "override" (default): later configs override earlier ones
-	// "merge": merge nested objects, override leaf values
-	// "append": append to arrays, override other values
-	MergeStrategy string `protobuf:"bytes,5,opt,name=merge_strategy,json=mergeStrategy,proto3" json:"merge_strategy,omitempty"`
+	// duration is the delay
+	Duration *durationpb.Duration `protobuf:"bytes,1,opt,name=duration,proto3" json:"duration,omitempty"`
+	// percentage of the matched calls to delay, from 0 to 100
+	Percentage    float64 `protobuf:"fixed64,2,opt,name=percentage,proto3" json:"percentage,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ConfigFile) Reset() {
-	*x = ConfigFile{}
-	mi := &file_polaris_proto_msgTypes[2]
+func (x *FaultDelay) Reset() {
+	*x = FaultDelay{}
+	mi := &file_polaris_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ConfigFile) String() string {
+func (x *FaultDelay) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConfigFile) ProtoMessage() {}
+func (*FaultDelay) ProtoMessage() {}
 
-func (x *ConfigFile) ProtoReflect() protoreflect.Message {
-	mi := &file_polaris_proto_msgTypes[2]
+func (x *FaultDelay) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -429,51 +3437,351 @@ func (x *ConfigFile) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConfigFile.ProtoReflect.Descriptor instead.
-func (*ConfigFile) Descriptor() ([]byte, []int) {
-	return file_polaris_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use FaultDelay.ProtoReflect.Descriptor instead.
+func (*FaultDelay) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *ConfigFile) GetGroup() string {
+func (x *FaultDelay) GetDuration() *durationpb.Duration {
 	if x != nil {
-		return x.Group
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *FaultDelay) GetPercentage() float64 {
+	if x != nil {
+		return x.Percentage
+	}
+	return 0
+}
+
+// FaultAbort defines an error injected into calls
+type FaultAbort struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// code is the HTTP status code of the error, from 400 to 599. gRPC calls fail with the
+	// matching gRPC code
+	Code int32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	// percentage of the matched calls to abort, from 0 to 100
+	Percentage float64 `protobuf:"fixed64,2,opt,name=percentage,proto3" json:"percentage,omitempty"`
+	// reason is the Kratos error reason, FAULT_INJECTED by default
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FaultAbort) Reset() {
+	*x = FaultAbort{}
+	mi := &file_polaris_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FaultAbort) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FaultAbort) ProtoMessage() {}
+
+func (x *FaultAbort) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FaultAbort.ProtoReflect.Descriptor instead.
+func (*FaultAbort) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *FaultAbort) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *FaultAbort) GetPercentage() float64 {
+	if x != nil {
+		return x.Percentage
+	}
+	return 0
+}
+
+func (x *FaultAbort) GetReason() string {
+	if x != nil {
+		return x.Reason
 	}
 	return ""
 }
 
-func (x *ConfigFile) GetFilename() string {
+// Lane defines how the lane of a request is propagated and routed
+type Lane struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// header is the request header carrying the lane between services, x-lane by default
+	Header string `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	// metadata_key is the instance metadata holding the lane of an instance, lane by default
+	MetadataKey string `protobuf:"bytes,2,opt,name=metadata_key,json=metadataKey,proto3" json:"metadata_key,omitempty"`
+	// strict fails the calls of a lane without instances instead of sending them to the
+	// instances without a lane
+	Strict        bool `protobuf:"varint,3,opt,name=strict,proto3" json:"strict,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Lane) Reset() {
+	*x = Lane{}
+	mi := &file_polaris_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lane) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lane) ProtoMessage() {}
+
+func (x *Lane) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[39]
 	if x != nil {
-		return x.Filename
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lane.ProtoReflect.Descriptor instead.
+func (*Lane) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *Lane) GetHeader() string {
+	if x != nil {
+		return x.Header
 	}
 	return ""
 }
 
-func (x *ConfigFile) GetNamespace() string {
+func (x *Lane) GetMetadataKey() string {
 	if x != nil {
-		return x.Namespace
+		return x.MetadataKey
 	}
 	return ""
 }
 
-func (x *ConfigFile) GetPriority() int32 {
+func (x *Lane) GetStrict() bool {
 	if x != nil {
-		return x.Priority
+		return x.Strict
+	}
+	return false
+}
+
+// Cache defines how long the instance and config caches keep their entries
+type Cache struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ttl is the age after which an entry is stale and refreshed in the background on its
+	// next read, 30s by default
+	Ttl *durationpb.Duration `protobuf:"bytes,1,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// max_stale is how long a stale entry is still served while its refresh fails, after
+	// which it is evicted. 0, the default, serves it until it is replaced
+	MaxStale *durationpb.Duration `protobuf:"bytes,2,opt,name=max_stale,json=maxStale,proto3" json:"max_stale,omitempty"`
+	// max_entries caps the entries of each cache, 10000 by default. They are split over the 16
+	// shards of the cache, each evicting its least recently used entries beyond its share
+	MaxEntries    int32 `protobuf:"varint,3,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cache) Reset() {
+	*x = Cache{}
+	mi := &file_polaris_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cache) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cache) ProtoMessage() {}
+
+func (x *Cache) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cache.ProtoReflect.Descriptor instead.
+func (*Cache) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *Cache) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *Cache) GetMaxStale() *durationpb.Duration {
+	if x != nil {
+		return x.MaxStale
+	}
+	return nil
+}
+
+func (x *Cache) GetMaxEntries() int32 {
+	if x != nil {
+		return x.MaxEntries
 	}
 	return 0
 }
 
-func (x *ConfigFile) GetMergeStrategy() string {
+// HotServices defines the downstream services prefetched at startup
+type HotServices struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// services are the names of the services, in the plugin namespace
+	Services []string `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	// wait is how long startup waits for the services to be resolved
+	// Zero, the default, resolves them in the background
+	Wait          *durationpb.Duration `protobuf:"bytes,2,opt,name=wait,proto3" json:"wait,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HotServices) Reset() {
+	*x = HotServices{}
+	mi := &file_polaris_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HotServices) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HotServices) ProtoMessage() {}
+
+func (x *HotServices) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[41]
 	if x != nil {
-		return x.MergeStrategy
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HotServices.ProtoReflect.Descriptor instead.
+func (*HotServices) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *HotServices) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *HotServices) GetWait() *durationpb.Duration {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+// Validation defines the optional checks of the configuration validator
+type Validation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// token_complexity_check requires tokens to contain both letters and numbers
+	// Unset reads POLARIS_ENABLE_TOKEN_COMPLEXITY_CHECK, off by default for compatibility
+	// with Polaris tokens
+	TokenComplexityCheck *bool `protobuf:"varint,1,opt,name=token_complexity_check,json=tokenComplexityCheck,proto3,oneof" json:"token_complexity_check,omitempty"`
+	// skip_namespace_sensitive_check allows namespaces containing a sensitive word
+	// Unset reads POLARIS_DISABLE_NAMESPACE_SENSITIVE_CHECK
+	SkipNamespaceSensitiveCheck *bool `protobuf:"varint,2,opt,name=skip_namespace_sensitive_check,json=skipNamespaceSensitiveCheck,proto3,oneof" json:"skip_namespace_sensitive_check,omitempty"`
+	// namespace_sensitive_words are the words that namespaces must not contain, case-insensitive
+	// Empty reads POLARIS_NAMESPACE_SENSITIVE_WORDS, then uses admin, root, system and internal
+	NamespaceSensitiveWords []string `protobuf:"bytes,3,rep,name=namespace_sensitive_words,json=namespaceSensitiveWords,proto3" json:"namespace_sensitive_words,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *Validation) Reset() {
+	*x = Validation{}
+	mi := &file_polaris_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Validation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Validation) ProtoMessage() {}
+
+func (x *Validation) ProtoReflect() protoreflect.Message {
+	mi := &file_polaris_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Validation.ProtoReflect.Descriptor instead.
+func (*Validation) Descriptor() ([]byte, []int) {
+	return file_polaris_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *Validation) GetTokenComplexityCheck() bool {
+	if x != nil && x.TokenComplexityCheck != nil {
+		return *x.TokenComplexityCheck
+	}
+	return false
+}
+
+func (x *Validation) GetSkipNamespaceSensitiveCheck() bool {
+	if x != nil && x.SkipNamespaceSensitiveCheck != nil {
+		return *x.SkipNamespaceSensitiveCheck
+	}
+	return false
+}
+
+func (x *Validation) GetNamespaceSensitiveWords() []string {
+	if x != nil {
+		return x.NamespaceSensitiveWords
+	}
+	return nil
 }
 
 var File_polaris_proto protoreflect.FileDescriptor
 
 const file_polaris_proto_rawDesc = "" +
 	"\n" +
-	"\rpolaris.proto\x12\x1clynx.protobuf.plugin.polaris\x1a\x1egoogle/protobuf/duration.proto\"\x8a\t\n" +
+	"\rpolaris.proto\x12\x1clynx.protobuf.plugin.polaris\x1a\x1egoogle/protobuf/duration.proto\"\x8e(\n" +
 	"\aPolaris\x12\x1c\n" +
 	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x14\n" +
 	"\x05token\x18\x02 \x01(\tR\x05token\x12\x16\n" +
@@ -501,7 +3809,238 @@ const file_polaris_proto_rawDesc = "" +
 	"\x10shutdown_timeout\x18\x17 \x01(\v2\x19.google.protobuf.DurationR\x0fshutdownTimeout\x12%\n" +
 	"\x0eenable_logging\x18\x18 \x01(\bR\renableLogging\x12\x1b\n" +
 	"\tlog_level\x18\x19 \x01(\tR\blogLevel\x12R\n" +
-	"\x0eservice_config\x18\x1a \x01(\v2+.lynx.protobuf.plugin.polaris.ServiceConfigR\rserviceConfig\"\xb8\x01\n" +
+	"\x0eservice_config\x18\x1a \x01(\v2+.lynx.protobuf.plugin.polaris.ServiceConfigR\rserviceConfig\x12Z\n" +
+	"\x11fallback_services\x18\x1b \x03(\v2-.lynx.protobuf.plugin.polaris.FallbackServiceR\x10fallbackServices\x12T\n" +
+	"\x0froute_fallbacks\x18\x1c \x03(\v2+.lynx.protobuf.plugin.polaris.RouteFallbackR\x0erouteFallbacks\x12:\n" +
+	"\vdrain_delay\x18\x1d \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"drainDelay\x12X\n" +
+	"\x10config_staleness\x18\x1e \x03(\v2-.lynx.protobuf.plugin.polaris.ConfigStalenessR\x0fconfigStaleness\x12I\n" +
+	"\vauto_weight\x18\x1f \x01(\v2(.lynx.protobuf.plugin.polaris.AutoWeightR\n" +
+	"autoWeight\x12X\n" +
+	"\x10server_bootstrap\x18  \x01(\v2-.lynx.protobuf.plugin.polaris.ServerBootstrapR\x0fserverBootstrap\x12=\n" +
+	"\awarm_up\x18! \x01(\v2$.lynx.protobuf.plugin.polaris.WarmUpR\x06warmUp\x12Y\n" +
+	"\x11rate_limit_labels\x18\" \x01(\v2-.lynx.protobuf.plugin.polaris.RateLimitLabelsR\x0frateLimitLabels\x12U\n" +
+	"\x0fwatch_partition\x18# \x01(\v2,.lynx.protobuf.plugin.polaris.WatchPartitionR\x0ewatchPartition\x12g\n" +
+	"\x15registration_watchdog\x18$ \x01(\v22.lynx.protobuf.plugin.polaris.RegistrationWatchdogR\x14registrationWatchdog\x12R\n" +
+	"\x0ehost_detection\x18% \x01(\v2+.lynx.protobuf.plugin.polaris.HostDetectionR\rhostDetection\x12E\n" +
+	"\theartbeat\x18& \x01(\v2'.lynx.protobuf.plugin.polaris.HeartbeatR\theartbeat\x12L\n" +
+	"\fconfig_admin\x18' \x01(\v2).lynx.protobuf.plugin.polaris.ConfigAdminR\vconfigAdmin\x12U\n" +
+	"\x0fconfig_snapshot\x18( \x01(\v2,.lynx.protobuf.plugin.polaris.ConfigSnapshotR\x0econfigSnapshot\x12[\n" +
+	"\x11config_encryption\x18) \x01(\v2..lynx.protobuf.plugin.polaris.ConfigEncryptionR\x10configEncryption\x12\\\n" +
+	"\rconfig_labels\x18* \x03(\v27.lynx.protobuf.plugin.polaris.Polaris.ConfigLabelsEntryR\fconfigLabels\x12U\n" +
+	"\x0fconfig_debounce\x18+ \x01(\v2,.lynx.protobuf.plugin.polaris.ConfigDebounceR\x0econfigDebounce\x12X\n" +
+	"\x10required_configs\x18, \x01(\v2-.lynx.protobuf.plugin.polaris.RequiredConfigsR\x0frequiredConfigs\x12_\n" +
+	"\x13rate_limit_fallback\x18- \x01(\v2/.lynx.protobuf.plugin.polaris.RateLimitFallbackR\x11rateLimitFallback\x12[\n" +
+	"\x11concurrency_limit\x18. \x01(\v2..lynx.protobuf.plugin.polaris.ConcurrencyLimitR\x10concurrencyLimit\x12\\\n" +
+	"\x1dcircuit_breaker_open_duration\x18/ \x01(\v2\x19.google.protobuf.DurationR\x1acircuitBreakerOpenDuration\x12F\n" +
+	" circuit_breaker_half_open_probes\x180 \x01(\x05R\x1ccircuitBreakerHalfOpenProbes\x12O\n" +
+	"\x16circuit_breaker_window\x181 \x01(\v2\x19.google.protobuf.DurationR\x14circuitBreakerWindow\x12?\n" +
+	"\x1ccircuit_breaker_min_requests\x182 \x01(\x05R\x19circuitBreakerMinRequests\x12g\n" +
+	"#circuit_breaker_slow_call_threshold\x183 \x01(\v2\x19.google.protobuf.DurationR\x1fcircuitBreakerSlowCallThreshold\x12#\n" +
+	"\rretry_backoff\x184 \x01(\tR\fretryBackoff\x12A\n" +
+	"\x0fretry_max_delay\x185 \x01(\v2\x19.google.protobuf.DurationR\rretryMaxDelay\x12:\n" +
+	"\vhedge_delay\x186 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"hedgeDelay\x12'\n" +
+	"\x0fmetrics_backend\x187 \x01(\tR\x0emetricsBackend\x129\n" +
+	"\x05audit\x188 \x01(\v2#.lynx.protobuf.plugin.polaris.AuditR\x05audit\x12B\n" +
+	"\balerting\x189 \x01(\v2&.lynx.protobuf.plugin.polaris.AlertingR\balerting\x12,\n" +
+	"\x12event_history_size\x18: \x01(\x05R\x10eventHistorySize\x12L\n" +
+	"\fself_healing\x18; \x01(\v2).lynx.protobuf.plugin.polaris.SelfHealingR\vselfHealing\x12L\n" +
+	"\fhealth_state\x18< \x01(\v2).lynx.protobuf.plugin.polaris.HealthStateR\vhealthState\x123\n" +
+	"\x03tls\x18= \x01(\v2!.lynx.protobuf.plugin.polaris.TlsR\x03tls\x12L\n" +
+	"\ftoken_source\x18> \x01(\v2).lynx.protobuf.plugin.polaris.TokenSourceR\vtokenSource\x12e\n" +
+	"\x10operation_tokens\x18? \x03(\v2:.lynx.protobuf.plugin.polaris.Polaris.OperationTokensEntryR\x0foperationTokens\x124\n" +
+	"\x16sensitive_config_files\x18@ \x03(\tR\x14sensitiveConfigFiles\x12?\n" +
+	"\astandby\x18A \x01(\v2%.lynx.protobuf.plugin.polaris.StandbyR\astandby\x12g\n" +
+	"\x15discovery_aggregation\x18B \x01(\v22.lynx.protobuf.plugin.polaris.DiscoveryAggregationR\x14discoveryAggregation\x12O\n" +
+	"\rremote_config\x18C \x01(\v2*.lynx.protobuf.plugin.polaris.RemoteConfigR\fremoteConfig\x12H\n" +
+	"\n" +
+	"subsystems\x18D \x01(\v2(.lynx.protobuf.plugin.polaris.SubsystemsR\n" +
+	"subsystems\x12E\n" +
+	"\treadiness\x18E \x01(\v2'.lynx.protobuf.plugin.polaris.ReadinessR\treadiness\x12\x1b\n" +
+	"\tlazy_init\x18F \x01(\bR\blazyInit\x12\x17\n" +
+	"\adry_run\x18G \x01(\bR\x06dryRun\x12U\n" +
+	"\x0ffault_injection\x18H \x01(\v2,.lynx.protobuf.plugin.polaris.FaultInjectionR\x0efaultInjection\x126\n" +
+	"\x04lane\x18I \x01(\v2\".lynx.protobuf.plugin.polaris.LaneR\x04lane\x129\n" +
+	"\x05cache\x18J \x01(\v2#.lynx.protobuf.plugin.polaris.CacheR\x05cache\x12#\n" +
+	"\rwatch_workers\x18K \x01(\x05R\fwatchWorkers\x12L\n" +
+	"\fhot_services\x18L \x01(\v2).lynx.protobuf.plugin.polaris.HotServicesR\vhotServices\x12H\n" +
+	"\n" +
+	"validation\x18M \x01(\v2(.lynx.protobuf.plugin.polaris.ValidationR\n" +
+	"validation\x1a?\n" +
+	"\x11ConfigLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1ap\n" +
+	"\x14OperationTokensEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12B\n" +
+	"\x05value\x18\x02 \x01(\v2,.lynx.protobuf.plugin.polaris.OperationTokenR\x05value:\x028\x01\"\x90\x01\n" +
+	"\bAlerting\x12F\n" +
+	"\bwebhooks\x18\x01 \x03(\v2*.lynx.protobuf.plugin.polaris.AlertWebhookR\bwebhooks\x12<\n" +
+	"\fdedup_window\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\vdedupWindow\"W\n" +
+	"\fAlertWebhook\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12!\n" +
+	"\fmin_severity\x18\x03 \x01(\tR\vminSeverity\"\xa4\x01\n" +
+	"\vSelfHealing\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12D\n" +
+	"\x10failure_duration\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x0ffailureDuration\x125\n" +
+	"\bcooldown\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\bcooldown\"\xeb\x01\n" +
+	"\aStandby\x12X\n" +
+	"\x10server_bootstrap\x18\x01 \x01(\v2-.lynx.protobuf.plugin.polaris.ServerBootstrapR\x0fserverBootstrap\x12@\n" +
+	"\x0efailover_after\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\rfailoverAfter\x12D\n" +
+	"\x10switchback_after\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x0fswitchbackAfter\"R\n" +
+	"\x14DiscoveryAggregation\x12\x1e\n" +
+	"\n" +
+	"namespaces\x18\x01 \x03(\tR\n" +
+	"namespaces\x12\x1a\n" +
+	"\bservices\x18\x02 \x03(\tR\bservices\"\\\n" +
+	"\fRemoteConfig\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x14\n" +
+	"\x05group\x18\x02 \x01(\tR\x05group\x12\x1a\n" +
+	"\brequired\x18\x03 \x01(\bR\brequired\"\x9f\x01\n" +
+	"\n" +
+	"Subsystems\x12\"\n" +
+	"\fregistration\x18\x01 \x01(\bR\fregistration\x12\x1c\n" +
+	"\tdiscovery\x18\x02 \x01(\bR\tdiscovery\x12\x16\n" +
+	"\x06config\x18\x03 \x01(\bR\x06config\x12\x1d\n" +
+	"\n" +
+	"rate_limit\x18\x04 \x01(\bR\trateLimit\x12\x18\n" +
+	"\arouting\x18\x05 \x01(\bR\arouting\"{\n" +
+	"\tReadiness\x123\n" +
+	"\atimeout\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12\x1d\n" +
+	"\n" +
+	"on_timeout\x18\x02 \x01(\tR\tonTimeout\x12\x1a\n" +
+	"\bservices\x18\x03 \x03(\tR\bservices\"\x8e\x01\n" +
+	"\vHealthState\x12/\n" +
+	"\x13unhealthy_threshold\x18\x01 \x01(\x05R\x12unhealthyThreshold\x12+\n" +
+	"\x11healthy_threshold\x18\x02 \x01(\x05R\x10healthyThreshold\x12!\n" +
+	"\fhistory_size\x18\x03 \x01(\x05R\vhistorySize\"\xc3\x01\n" +
+	"\x03Tls\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x17\n" +
+	"\aca_file\x18\x02 \x01(\tR\x06caFile\x12\x1b\n" +
+	"\tcert_file\x18\x03 \x01(\tR\bcertFile\x12\x19\n" +
+	"\bkey_file\x18\x04 \x01(\tR\akeyFile\x12\x1f\n" +
+	"\vserver_name\x18\x05 \x01(\tR\n" +
+	"serverName\x120\n" +
+	"\x14insecure_skip_verify\x18\x06 \x01(\bR\x12insecureSkipVerify\"y\n" +
+	"\vTokenSource\x12\x12\n" +
+	"\x04file\x18\x01 \x01(\tR\x04file\x12\x10\n" +
+	"\x03env\x18\x02 \x01(\tR\x03env\x12D\n" +
+	"\x10refresh_interval\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x0frefreshInterval\"L\n" +
+	"\x0eOperationToken\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x12\n" +
+	"\x04file\x18\x02 \x01(\tR\x04file\x12\x10\n" +
+	"\x03env\x18\x03 \x01(\tR\x03env\"\xb0\x02\n" +
+	"\x05Audit\x12\x12\n" +
+	"\x04sink\x18\x01 \x01(\tR\x04sink\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x123\n" +
+	"\atimeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12\x1f\n" +
+	"\vsample_rate\x18\x05 \x01(\x01R\n" +
+	"sampleRate\x12W\n" +
+	"\fsample_rates\x18\x06 \x03(\v24.lynx.protobuf.plugin.polaris.Audit.SampleRatesEntryR\vsampleRates\x1a>\n" +
+	"\x10SampleRatesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\x80\x01\n" +
+	"\x0fRequiredConfigs\x12>\n" +
+	"\x05files\x18\x01 \x03(\v2(.lynx.protobuf.plugin.polaris.ConfigFileR\x05files\x12-\n" +
+	"\x04wait\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x04wait\"y\n" +
+	"\x0eConfigDebounce\x121\n" +
+	"\x06window\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\x06window\x124\n" +
+	"\bmax_wait\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\amaxWait\"+\n" +
+	"\x10ConfigEncryption\x12\x17\n" +
+	"\akey_env\x18\x01 \x01(\tR\x06keyEnv\"V\n" +
+	"\x0eConfigSnapshot\x12\x10\n" +
+	"\x03dir\x18\x01 \x01(\tR\x03dir\x122\n" +
+	"\amax_age\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x06maxAge\"\\\n" +
+	"\vConfigAdmin\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x123\n" +
+	"\atimeout\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"\xa1\x01\n" +
+	"\tHeartbeat\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12\x16\n" +
+	"\x06jitter\x18\x03 \x01(\x01R\x06jitter\x12+\n" +
+	"\x11failure_threshold\x18\x04 \x01(\x05R\x10failureThreshold\"x\n" +
+	"\rHostDetection\x12\x10\n" +
+	"\x03env\x18\x01 \x03(\tR\x03env\x12\x1e\n" +
+	"\n" +
+	"interfaces\x18\x02 \x03(\tR\n" +
+	"interfaces\x12\x14\n" +
+	"\x05cidrs\x18\x03 \x03(\tR\x05cidrs\x12\x1f\n" +
+	"\vprefer_ipv6\x18\x04 \x01(\bR\n" +
+	"preferIpv6\"g\n" +
+	"\x14RegistrationWatchdog\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\"\x9d\x02\n" +
+	"\x0eWatchPartition\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\x12\x12\n" +
+	"\x04zone\x18\x03 \x01(\tR\x04zone\x12\x16\n" +
+	"\x06campus\x18\x04 \x01(\tR\x06campus\x12V\n" +
+	"\bmetadata\x18\x05 \x03(\v2:.lynx.protobuf.plugin.polaris.WatchPartition.MetadataEntryR\bmetadata\x12\x1a\n" +
+	"\bservices\x18\x06 \x03(\tR\bservices\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc1\x01\n" +
+	"\x0fRateLimitLabels\x12!\n" +
+	"\fallowed_keys\x18\x01 \x03(\tR\vallowedKeys\x12\x1f\n" +
+	"\vhashed_keys\x18\x02 \x03(\tR\n" +
+	"hashedKeys\x12!\n" +
+	"\fhash_buckets\x18\x03 \x01(\x05R\vhashBuckets\x12\x1d\n" +
+	"\n" +
+	"max_labels\x18\x04 \x01(\x05R\tmaxLabels\x12(\n" +
+	"\x10max_value_length\x18\x05 \x01(\x05R\x0emaxValueLength\"e\n" +
+	"\x11RateLimitFallback\x12\x12\n" +
+	"\x04mode\x18\x01 \x01(\tR\x04mode\x12\x1b\n" +
+	"\tlocal_qps\x18\x02 \x01(\x01R\blocalQps\x12\x1f\n" +
+	"\vlocal_burst\x18\x03 \x01(\x05R\n" +
+	"localBurst\"l\n" +
+	"\x10ConcurrencyLimit\x12\"\n" +
+	"\rmax_in_flight\x18\x01 \x01(\x05R\vmaxInFlight\x124\n" +
+	"\bmax_wait\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\amaxWait\"\xc0\x01\n" +
+	"\x06WarmUp\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x125\n" +
+	"\bduration\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12%\n" +
+	"\x0einitial_weight\x18\x03 \x01(\x05R\rinitialWeight\x12>\n" +
+	"\rstep_interval\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\fstepInterval\"\xe5\x03\n" +
+	"\x0fServerBootstrap\x12\x1c\n" +
+	"\taddresses\x18\x01 \x03(\tR\taddresses\x12)\n" +
+	"\x10config_addresses\x18\x02 \x03(\tR\x0fconfigAddresses\x12\x1d\n" +
+	"\n" +
+	"srv_record\x18\x03 \x01(\tR\tsrvRecord\x12*\n" +
+	"\x11config_srv_record\x18\x04 \x01(\tR\x0fconfigSrvRecord\x12D\n" +
+	"\x10refresh_interval\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\x0frefreshInterval\x12\x14\n" +
+	"\x05hosts\x18\x06 \x03(\tR\x05hosts\x12#\n" +
+	"\rdiscover_port\x18\a \x01(\rR\fdiscoverPort\x12\x1f\n" +
+	"\vconfig_port\x18\b \x01(\rR\n" +
+	"configPort\x12+\n" +
+	"\x11limiter_namespace\x18\t \x01(\tR\x10limiterNamespace\x12'\n" +
+	"\x0flimiter_service\x18\n" +
+	" \x01(\tR\x0elimiterService\x12F\n" +
+	"\x11failover_cooldown\x18\v \x01(\v2\x19.google.protobuf.DurationR\x10failoverCooldown\"|\n" +
+	"\n" +
+	"AutoWeight\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12\x1d\n" +
+	"\n" +
+	"min_weight\x18\x03 \x01(\x05R\tminWeight\"x\n" +
+	"\x0fConfigStaleness\x12\x1b\n" +
+	"\tfile_name\x18\x01 \x01(\tR\bfileName\x12\x14\n" +
+	"\x05group\x18\x02 \x01(\tR\x05group\x122\n" +
+	"\amax_age\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x06maxAge\"y\n" +
+	"\x0fFallbackService\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\x12L\n" +
+	"\tinstances\x18\x02 \x03(\v2..lynx.protobuf.plugin.polaris.FallbackInstanceR\tinstances\"\x9f\x02\n" +
+	"\x10FallbackInstance\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x12\n" +
+	"\x04port\x18\x02 \x01(\rR\x04port\x12\x1a\n" +
+	"\bprotocol\x18\x03 \x01(\tR\bprotocol\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12\x16\n" +
+	"\x06weight\x18\x05 \x01(\x05R\x06weight\x12X\n" +
+	"\bmetadata\x18\x06 \x03(\v2<.lynx.protobuf.plugin.polaris.FallbackInstance.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb8\x01\n" +
 	"\rServiceConfig\x12\x14\n" +
 	"\x05group\x18\x01 \x01(\tR\x05group\x12\x1a\n" +
 	"\bfilename\x18\x02 \x01(\tR\bfilename\x12\x1c\n" +
@@ -513,7 +4052,55 @@ const file_polaris_proto_rawDesc = "" +
 	"\bfilename\x18\x02 \x01(\tR\bfilename\x12\x1c\n" +
 	"\tnamespace\x18\x03 \x01(\tR\tnamespace\x12\x1a\n" +
 	"\bpriority\x18\x04 \x01(\x05R\bpriority\x12%\n" +
-	"\x0emerge_strategy\x18\x05 \x01(\tR\rmergeStrategyB3Z1github.com/go-lynx/lynx/plugins/polaris/conf;confb\x06proto3"
+	"\x0emerge_strategy\x18\x05 \x01(\tR\rmergeStrategy\"\xab\x01\n" +
+	"\rRouteFallback\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\x12%\n" +
+	"\x0etarget_service\x18\x02 \x01(\tR\rtargetService\x12Y\n" +
+	"\x10target_instances\x18\x03 \x03(\v2..lynx.protobuf.plugin.polaris.FallbackInstanceR\x0ftargetInstances\"\xd7\x01\n" +
+	"\x0eFaultInjection\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12F\n" +
+	"\x05rules\x18\x02 \x03(\v20.lynx.protobuf.plugin.polaris.FaultInjectionRuleR\x05rules\x12\x1d\n" +
+	"\n" +
+	"rules_path\x18\x03 \x01(\tR\trulesPath\x12D\n" +
+	"\x10refresh_interval\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x0frefreshInterval\"\xe0\x01\n" +
+	"\x12FaultInjectionRule\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aservice\x18\x02 \x01(\tR\aservice\x12\x1c\n" +
+	"\toperation\x18\x03 \x01(\tR\toperation\x12>\n" +
+	"\x05delay\x18\x04 \x01(\v2(.lynx.protobuf.plugin.polaris.FaultDelayR\x05delay\x12>\n" +
+	"\x05abort\x18\x05 \x01(\v2(.lynx.protobuf.plugin.polaris.FaultAbortR\x05abort\"c\n" +
+	"\n" +
+	"FaultDelay\x125\n" +
+	"\bduration\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12\x1e\n" +
+	"\n" +
+	"percentage\x18\x02 \x01(\x01R\n" +
+	"percentage\"X\n" +
+	"\n" +
+	"FaultAbort\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x1e\n" +
+	"\n" +
+	"percentage\x18\x02 \x01(\x01R\n" +
+	"percentage\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"Y\n" +
+	"\x04Lane\x12\x16\n" +
+	"\x06header\x18\x01 \x01(\tR\x06header\x12!\n" +
+	"\fmetadata_key\x18\x02 \x01(\tR\vmetadataKey\x12\x16\n" +
+	"\x06strict\x18\x03 \x01(\bR\x06strict\"\x8d\x01\n" +
+	"\x05Cache\x12+\n" +
+	"\x03ttl\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x126\n" +
+	"\tmax_stale\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\bmaxStale\x12\x1f\n" +
+	"\vmax_entries\x18\x03 \x01(\x05R\n" +
+	"maxEntries\"X\n" +
+	"\vHotServices\x12\x1a\n" +
+	"\bservices\x18\x01 \x03(\tR\bservices\x12-\n" +
+	"\x04wait\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x04wait\"\x8b\x02\n" +
+	"\n" +
+	"Validation\x129\n" +
+	"\x16token_complexity_check\x18\x01 \x01(\bH\x00R\x14tokenComplexityCheck\x88\x01\x01\x12H\n" +
+	"\x1eskip_namespace_sensitive_check\x18\x02 \x01(\bH\x01R\x1bskipNamespaceSensitiveCheck\x88\x01\x01\x12:\n" +
+	"\x19namespace_sensitive_words\x18\x03 \x03(\tR\x17namespaceSensitiveWordsB\x19\n" +
+	"\x17_token_complexity_checkB!\n" +
+	"\x1f_skip_namespace_sensitive_checkB3Z1github.com/go-lynx/lynx/plugins/polaris/conf;confb\x06proto3"
 
 var (
 	file_polaris_proto_rawDescOnce sync.Once
@@ -527,25 +4114,151 @@ func file_polaris_proto_rawDescGZIP() []byte {
 	return file_polaris_proto_rawDescData
 }
 
-var file_polaris_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_polaris_proto_msgTypes = make([]protoimpl.MessageInfo, 48)
 var file_polaris_proto_goTypes = []any{
-	(*Polaris)(nil),             // 0: lynx.protobuf.plugin.polaris.Polaris
-	(*ServiceConfig)(nil),       // 1: lynx.protobuf.plugin.polaris.ServiceConfig
-	(*ConfigFile)(nil),          // 2: lynx.protobuf.plugin.polaris.ConfigFile
-	(*durationpb.Duration)(nil), // 3: google.protobuf.Duration
+	(*Polaris)(nil),              // 0: lynx.protobuf.plugin.polaris.Polaris
+	(*Alerting)(nil),             // 1: lynx.protobuf.plugin.polaris.Alerting
+	(*AlertWebhook)(nil),         // 2: lynx.protobuf.plugin.polaris.AlertWebhook
+	(*SelfHealing)(nil),          // 3: lynx.protobuf.plugin.polaris.SelfHealing
+	(*Standby)(nil),              // 4: lynx.protobuf.plugin.polaris.Standby
+	(*DiscoveryAggregation)(nil), // 5: lynx.protobuf.plugin.polaris.DiscoveryAggregation
+	(*RemoteConfig)(nil),         // 6: lynx.protobuf.plugin.polaris.RemoteConfig
+	(*Subsystems)(nil),           // 7: lynx.protobuf.plugin.polaris.Subsystems
+	(*Readiness)(nil),            // 8: lynx.protobuf.plugin.polaris.Readiness
+	(*HealthState)(nil),          // 9: lynx.protobuf.plugin.polaris.HealthState
+	(*Tls)(nil),                  // 10: lynx.protobuf.plugin.polaris.Tls
+	(*TokenSource)(nil),          // 11: lynx.protobuf.plugin.polaris.TokenSource
+	(*OperationToken)(nil),       // 12: lynx.protobuf.plugin.polaris.OperationToken
+	(*Audit)(nil),                // 13: lynx.protobuf.plugin.polaris.Audit
+	(*RequiredConfigs)(nil),      // 14: lynx.protobuf.plugin.polaris.RequiredConfigs
+	(*ConfigDebounce)(nil),       // 15: lynx.protobuf.plugin.polaris.ConfigDebounce
+	(*ConfigEncryption)(nil),     // 16: lynx.protobuf.plugin.polaris.ConfigEncryption
+	(*ConfigSnapshot)(nil),       // 17: lynx.protobuf.plugin.polaris.ConfigSnapshot
+	(*ConfigAdmin)(nil),          // 18: lynx.protobuf.plugin.polaris.ConfigAdmin
+	(*Heartbeat)(nil),            // 19: lynx.protobuf.plugin.polaris.Heartbeat
+	(*HostDetection)(nil),        // 20: lynx.protobuf.plugin.polaris.HostDetection
+	(*RegistrationWatchdog)(nil), // 21: lynx.protobuf.plugin.polaris.RegistrationWatchdog
+	(*WatchPartition)(nil),       // 22: lynx.protobuf.plugin.polaris.WatchPartition
+	(*RateLimitLabels)(nil),      // 23: lynx.protobuf.plugin.polaris.RateLimitLabels
+	(*RateLimitFallback)(nil),    // 24: lynx.protobuf.plugin.polaris.RateLimitFallback
+	(*ConcurrencyLimit)(nil),     // 25: lynx.protobuf.plugin.polaris.ConcurrencyLimit
+	(*WarmUp)(nil),               // 26: lynx.protobuf.plugin.polaris.WarmUp
+	(*ServerBootstrap)(nil),      // 27: lynx.protobuf.plugin.polaris.ServerBootstrap
+	(*AutoWeight)(nil),           // 28: lynx.protobuf.plugin.polaris.AutoWeight
+	(*ConfigStaleness)(nil),      // 29: lynx.protobuf.plugin.polaris.ConfigStaleness
+	(*FallbackService)(nil),      // 30: lynx.protobuf.plugin.polaris.FallbackService
+	(*FallbackInstance)(nil),     // 31: lynx.protobuf.plugin.polaris.FallbackInstance
+	(*ServiceConfig)(nil),        // 32: lynx.protobuf.plugin.polaris.ServiceConfig
+	(*ConfigFile)(nil),           // 33: lynx.protobuf.plugin.polaris.ConfigFile
+	(*RouteFallback)(nil),        // 34: lynx.protobuf.plugin.polaris.RouteFallback
+	(*FaultInjection)(nil),       // 35: lynx.protobuf.plugin.polaris.FaultInjection
+	(*FaultInjectionRule)(nil),   // 36: lynx.protobuf.plugin.polaris.FaultInjectionRule
+	(*FaultDelay)(nil),           // 37: lynx.protobuf.plugin.polaris.FaultDelay
+	(*FaultAbort)(nil),           // 38: lynx.protobuf.plugin.polaris.FaultAbort
+	(*Lane)(nil),                 // 39: lynx.protobuf.plugin.polaris.Lane
+	(*Cache)(nil),                // 40: lynx.protobuf.plugin.polaris.Cache
+	(*HotServices)(nil),          // 41: lynx.protobuf.plugin.polaris.HotServices
+	(*Validation)(nil),           // 42: lynx.protobuf.plugin.polaris.Validation
+	nil,                          // 43: lynx.protobuf.plugin.polaris.Polaris.ConfigLabelsEntry
+	nil,                          // 44: lynx.protobuf.plugin.polaris.Polaris.OperationTokensEntry
+	nil,                          // 45: lynx.protobuf.plugin.polaris.Audit.SampleRatesEntry
+	nil,                          // 46: lynx.protobuf.plugin.polaris.WatchPartition.MetadataEntry
+	nil,                          // 47: lynx.protobuf.plugin.polaris.FallbackInstance.MetadataEntry
+	(*durationpb.Duration)(nil),  // 48: google.protobuf.Duration
 }
 var file_polaris_proto_depIdxs = []int32{
-	3, // 0: lynx.protobuf.plugin.polaris.Polaris.timeout:type_name -> google.protobuf.Duration
-	3, // 1: lynx.protobuf.plugin.polaris.Polaris.health_check_interval:type_name -> google.protobuf.Duration
-	3, // 2: lynx.protobuf.plugin.polaris.Polaris.retry_interval:type_name -> google.protobuf.Duration
-	3, // 3: lynx.protobuf.plugin.polaris.Polaris.shutdown_timeout:type_name -> google.protobuf.Duration
-	1, // 4: lynx.protobuf.plugin.polaris.Polaris.service_config:type_name -> lynx.protobuf.plugin.polaris.ServiceConfig
-	2, // 5: lynx.protobuf.plugin.polaris.ServiceConfig.additional_configs:type_name -> lynx.protobuf.plugin.polaris.ConfigFile
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	48, // 0: lynx.protobuf.plugin.polaris.Polaris.timeout:type_name -> google.protobuf.Duration
+	48, // 1: lynx.protobuf.plugin.polaris.Polaris.health_check_interval:type_name -> google.protobuf.Duration
+	48, // 2: lynx.protobuf.plugin.polaris.Polaris.retry_interval:type_name -> google.protobuf.Duration
+	48, // 3: lynx.protobuf.plugin.polaris.Polaris.shutdown_timeout:type_name -> google.protobuf.Duration
+	32, // 4: lynx.protobuf.plugin.polaris.Polaris.service_config:type_name -> lynx.protobuf.plugin.polaris.ServiceConfig
+	30, // 5: lynx.protobuf.plugin.polaris.Polaris.fallback_services:type_name -> lynx.protobuf.plugin.polaris.FallbackService
+	34, // 6: lynx.protobuf.plugin.polaris.Polaris.route_fallbacks:type_name -> lynx.protobuf.plugin.polaris.RouteFallback
+	48, // 7: lynx.protobuf.plugin.polaris.Polaris.drain_delay:type_name -> google.protobuf.Duration
+	29, // 8: lynx.protobuf.plugin.polaris.Polaris.config_staleness:type_name -> lynx.protobuf.plugin.polaris.ConfigStaleness
+	28, // 9: lynx.protobuf.plugin.polaris.Polaris.auto_weight:type_name -> lynx.protobuf.plugin.polaris.AutoWeight
+	27, // 10: lynx.protobuf.plugin.polaris.Polaris.server_bootstrap:type_name -> lynx.protobuf.plugin.polaris.ServerBootstrap
+	26, // 11: lynx.protobuf.plugin.polaris.Polaris.warm_up:type_name -> lynx.protobuf.plugin.polaris.WarmUp
+	23, // 12: lynx.protobuf.plugin.polaris.Polaris.rate_limit_labels:type_name -> lynx.protobuf.plugin.polaris.RateLimitLabels
+	22, // 13: lynx.protobuf.plugin.polaris.Polaris.watch_partition:type_name -> lynx.protobuf.plugin.polaris.WatchPartition
+	21, // 14: lynx.protobuf.plugin.polaris.Polaris.registration_watchdog:type_name -> lynx.protobuf.plugin.polaris.RegistrationWatchdog
+	20, // 15: lynx.protobuf.plugin.polaris.Polaris.host_detection:type_name -> lynx.protobuf.plugin.polaris.HostDetection
+	19, // 16: lynx.protobuf.plugin.polaris.Polaris.heartbeat:type_name -> lynx.protobuf.plugin.polaris.Heartbeat
+	18, // 17: lynx.protobuf.plugin.polaris.Polaris.config_admin:type_name -> lynx.protobuf.plugin.polaris.ConfigAdmin
+	17, // 18: lynx.protobuf.plugin.polaris.Polaris.config_snapshot:type_name -> lynx.protobuf.plugin.polaris.ConfigSnapshot
+	16, // 19: lynx.protobuf.plugin.polaris.Polaris.config_encryption:type_name -> lynx.protobuf.plugin.polaris.ConfigEncryption
+	43, // 20: lynx.protobuf.plugin.polaris.Polaris.config_labels:type_name -> lynx.protobuf.plugin.polaris.Polaris.ConfigLabelsEntry
+	15, // 21: lynx.protobuf.plugin.polaris.Polaris.config_debounce:type_name -> lynx.protobuf.plugin.polaris.ConfigDebounce
+	14, // 22: lynx.protobuf.plugin.polaris.Polaris.required_configs:type_name -> lynx.protobuf.plugin.polaris.RequiredConfigs
+	24, // 23: lynx.protobuf.plugin.polaris.Polaris.rate_limit_fallback:type_name -> lynx.protobuf.plugin.polaris.RateLimitFallback
+	25, // 24: lynx.protobuf.plugin.polaris.Polaris.concurrency_limit:type_name -> lynx.protobuf.plugin.polaris.ConcurrencyLimit
+	48, // 25: lynx.protobuf.plugin.polaris.Polaris.circuit_breaker_open_duration:type_name -> google.protobuf.Duration
+	48, // 26: lynx.protobuf.plugin.polaris.Polaris.circuit_breaker_window:type_name -> google.protobuf.Duration
+	48, // 27: lynx.protobuf.plugin.polaris.Polaris.circuit_breaker_slow_call_threshold:type_name -> google.protobuf.Duration
+	48, // 28: lynx.protobuf.plugin.polaris.Polaris.retry_max_delay:type_name -> google.protobuf.Duration
+	48, // 29: lynx.protobuf.plugin.polaris.Polaris.hedge_delay:type_name -> google.protobuf.Duration
+	13, // 30: lynx.protobuf.plugin.polaris.Polaris.audit:type_name -> lynx.protobuf.plugin.polaris.Audit
+	1,  // 31: lynx.protobuf.plugin.polaris.Polaris.alerting:type_name -> lynx.protobuf.plugin.polaris.Alerting
+	3,  // 32: lynx.protobuf.plugin.polaris.Polaris.self_healing:type_name -> lynx.protobuf.plugin.polaris.SelfHealing
+	9,  // 33: lynx.protobuf.plugin.polaris.Polaris.health_state:type_name -> lynx.protobuf.plugin.polaris.HealthState
+	10, // 34: lynx.protobuf.plugin.polaris.Polaris.tls:type_name -> lynx.protobuf.plugin.polaris.Tls
+	11, // 35: lynx.protobuf.plugin.polaris.Polaris.token_source:type_name -> lynx.protobuf.plugin.polaris.TokenSource
+	44, // 36: lynx.protobuf.plugin.polaris.Polaris.operation_tokens:type_name -> lynx.protobuf.plugin.polaris.Polaris.OperationTokensEntry
+	4,  // 37: lynx.protobuf.plugin.polaris.Polaris.standby:type_name -> lynx.protobuf.plugin.polaris.Standby
+	5,  // 38: lynx.protobuf.plugin.polaris.Polaris.discovery_aggregation:type_name -> lynx.protobuf.plugin.polaris.DiscoveryAggregation
+	6,  // 39: lynx.protobuf.plugin.polaris.Polaris.remote_config:type_name -> lynx.protobuf.plugin.polaris.RemoteConfig
+	7,  // 40: lynx.protobuf.plugin.polaris.Polaris.subsystems:type_name -> lynx.protobuf.plugin.polaris.Subsystems
+	8,  // 41: lynx.protobuf.plugin.polaris.Polaris.readiness:type_name -> lynx.protobuf.plugin.polaris.Readiness
+	35, // 42: lynx.protobuf.plugin.polaris.Polaris.fault_injection:type_name -> lynx.protobuf.plugin.polaris.FaultInjection
+	39, // 43: lynx.protobuf.plugin.polaris.Polaris.lane:type_name -> lynx.protobuf.plugin.polaris.Lane
+	40, // 44: lynx.protobuf.plugin.polaris.Polaris.cache:type_name -> lynx.protobuf.plugin.polaris.Cache
+	41, // 45: lynx.protobuf.plugin.polaris.Polaris.hot_services:type_name -> lynx.protobuf.plugin.polaris.HotServices
+	42, // 46: lynx.protobuf.plugin.polaris.Polaris.validation:type_name -> lynx.protobuf.plugin.polaris.Validation
+	2,  // 47: lynx.protobuf.plugin.polaris.Alerting.webhooks:type_name -> lynx.protobuf.plugin.polaris.AlertWebhook
+	48, // 48: lynx.protobuf.plugin.polaris.Alerting.dedup_window:type_name -> google.protobuf.Duration
+	48, // 49: lynx.protobuf.plugin.polaris.SelfHealing.failure_duration:type_name -> google.protobuf.Duration
+	48, // 50: lynx.protobuf.plugin.polaris.SelfHealing.cooldown:type_name -> google.protobuf.Duration
+	27, // 51: lynx.protobuf.plugin.polaris.Standby.server_bootstrap:type_name -> lynx.protobuf.plugin.polaris.ServerBootstrap
+	48, // 52: lynx.protobuf.plugin.polaris.Standby.failover_after:type_name -> google.protobuf.Duration
+	48, // 53: lynx.protobuf.plugin.polaris.Standby.switchback_after:type_name -> google.protobuf.Duration
+	48, // 54: lynx.protobuf.plugin.polaris.Readiness.timeout:type_name -> google.protobuf.Duration
+	48, // 55: lynx.protobuf.plugin.polaris.TokenSource.refresh_interval:type_name -> google.protobuf.Duration
+	48, // 56: lynx.protobuf.plugin.polaris.Audit.timeout:type_name -> google.protobuf.Duration
+	45, // 57: lynx.protobuf.plugin.polaris.Audit.sample_rates:type_name -> lynx.protobuf.plugin.polaris.Audit.SampleRatesEntry
+	33, // 58: lynx.protobuf.plugin.polaris.RequiredConfigs.files:type_name -> lynx.protobuf.plugin.polaris.ConfigFile
+	48, // 59: lynx.protobuf.plugin.polaris.RequiredConfigs.wait:type_name -> google.protobuf.Duration
+	48, // 60: lynx.protobuf.plugin.polaris.ConfigDebounce.window:type_name -> google.protobuf.Duration
+	48, // 61: lynx.protobuf.plugin.polaris.ConfigDebounce.max_wait:type_name -> google.protobuf.Duration
+	48, // 62: lynx.protobuf.plugin.polaris.ConfigSnapshot.max_age:type_name -> google.protobuf.Duration
+	48, // 63: lynx.protobuf.plugin.polaris.ConfigAdmin.timeout:type_name -> google.protobuf.Duration
+	48, // 64: lynx.protobuf.plugin.polaris.Heartbeat.interval:type_name -> google.protobuf.Duration
+	48, // 65: lynx.protobuf.plugin.polaris.RegistrationWatchdog.interval:type_name -> google.protobuf.Duration
+	46, // 66: lynx.protobuf.plugin.polaris.WatchPartition.metadata:type_name -> lynx.protobuf.plugin.polaris.WatchPartition.MetadataEntry
+	48, // 67: lynx.protobuf.plugin.polaris.ConcurrencyLimit.max_wait:type_name -> google.protobuf.Duration
+	48, // 68: lynx.protobuf.plugin.polaris.WarmUp.duration:type_name -> google.protobuf.Duration
+	48, // 69: lynx.protobuf.plugin.polaris.WarmUp.step_interval:type_name -> google.protobuf.Duration
+	48, // 70: lynx.protobuf.plugin.polaris.ServerBootstrap.refresh_interval:type_name -> google.protobuf.Duration
+	48, // 71: lynx.protobuf.plugin.polaris.ServerBootstrap.failover_cooldown:type_name -> google.protobuf.Duration
+	48, // 72: lynx.protobuf.plugin.polaris.AutoWeight.interval:type_name -> google.protobuf.Duration
+	48, // 73: lynx.protobuf.plugin.polaris.ConfigStaleness.max_age:type_name -> google.protobuf.Duration
+	31, // 74: lynx.protobuf.plugin.polaris.FallbackService.instances:type_name -> lynx.protobuf.plugin.polaris.FallbackInstance
+	47, // 75: lynx.protobuf.plugin.polaris.FallbackInstance.metadata:type_name -> lynx.protobuf.plugin.polaris.FallbackInstance.MetadataEntry
+	33, // 76: lynx.protobuf.plugin.polaris.ServiceConfig.additional_configs:type_name -> lynx.protobuf.plugin.polaris.ConfigFile
+	31, // 77: lynx.protobuf.plugin.polaris.RouteFallback.target_instances:type_name -> lynx.protobuf.plugin.polaris.FallbackInstance
+	36, // 78: lynx.protobuf.plugin.polaris.FaultInjection.rules:type_name -> lynx.protobuf.plugin.polaris.FaultInjectionRule
+	48, // 79: lynx.protobuf.plugin.polaris.FaultInjection.refresh_interval:type_name -> google.protobuf.Duration
+	37, // 80: lynx.protobuf.plugin.polaris.FaultInjectionRule.delay:type_name -> lynx.protobuf.plugin.polaris.FaultDelay
+	38, // 81: lynx.protobuf.plugin.polaris.FaultInjectionRule.abort:type_name -> lynx.protobuf.plugin.polaris.FaultAbort
+	48, // 82: lynx.protobuf.plugin.polaris.FaultDelay.duration:type_name -> google.protobuf.Duration
+	48, // 83: lynx.protobuf.plugin.polaris.Cache.ttl:type_name -> google.protobuf.Duration
+	48, // 84: lynx.protobuf.plugin.polaris.Cache.max_stale:type_name -> google.protobuf.Duration
+	48, // 85: lynx.protobuf.plugin.polaris.HotServices.wait:type_name -> google.protobuf.Duration
+	12, // 86: lynx.protobuf.plugin.polaris.Polaris.OperationTokensEntry.value:type_name -> lynx.protobuf.plugin.polaris.OperationToken
+	87, // [87:87] is the sub-list for method output_type
+	87, // [87:87] is the sub-list for method input_type
+	87, // [87:87] is the sub-list for extension type_name
+	87, // [87:87] is the sub-list for extension extendee
+	0,  // [0:87] is the sub-list for field type_name
 }
 
 func init() { file_polaris_proto_init() }
@@ -553,13 +4266,14 @@ func file_polaris_proto_init() {
 	if File_polaris_proto != nil {
 		return
 	}
+	file_polaris_proto_msgTypes[42].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_polaris_proto_rawDesc), len(file_polaris_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   48,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/go-lynx/lynx"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -14,6 +15,7 @@ import (
 	"github.com/go-lynx/lynx-polaris/conf"
 	"github.com/go-lynx/lynx/log"
 	"github.com/polarismesh/polaris-go/api"
+	polarisconfig "github.com/polarismesh/polaris-go/pkg/config"
 	"gopkg.in/yaml.v3"
 )
 
@@ -25,27 +27,44 @@ import (
 // Returns (nil, error) when plugin is destroyed.
 func (p *PlugPolaris) GetConfig(fileName string, group string) (config.Source, error) {
 	if p.IsDestroyed() {
-		return nil, NewInitError("Polaris plugin has been destroyed")
+		return nil, newDestroyedError()
+	}
+	if err := p.checkSubsystem(SubsystemConfig); err != nil {
+		return nil, err
+	}
+	if p.IsInitialized() {
+		if err := p.ensureConnected(); err != nil {
+			return nil, err
+		}
 	}
 	p.mu.RLock()
 	pol := p.polaris
+	namespace := p.currentConf().GetNamespace()
 	p.mu.RUnlock()
 	if pol == nil {
 		return nil, nil
 	}
-	return pol.Config(
+	source, err := pol.Config(
 		polaris.WithConfigFile(
 			polaris.File{
 				Name:  fileName,
 				Group: group,
 			}))
+	if err != nil {
+		return nil, err
+	}
+	return p.withConfigDecryption(p.withConfigSnapshot(source, namespace, group, fileName), group), nil
 }
 
 // GetConfigSources returns all configuration sources (implements MultiConfigControlPlane).
+// There are none when the config subsystem is disabled.
 func (p *PlugPolaris) GetConfigSources() ([]config.Source, error) {
 	if err := p.checkInitialized(); err != nil {
 		return nil, err
 	}
+	if !p.SubsystemEnabled(SubsystemConfig) {
+		return nil, nil
+	}
 
 	var sources []config.Source
 
@@ -69,14 +88,18 @@ func (p *PlugPolaris) GetConfigSources() ([]config.Source, error) {
 }
 
 // GetConfigWatchTargets returns the Polaris config files that should feed the global config snapshot.
+// There are none when the config subsystem is disabled.
 func (p *PlugPolaris) GetConfigWatchTargets(appName string) ([]lynx.ControlPlaneConfigTarget, error) {
 	if err := p.checkInitialized(); err != nil {
 		return nil, err
 	}
+	if !p.SubsystemEnabled(SubsystemConfig) {
+		return nil, nil
+	}
 
 	mainFile := ""
 	mainGroup := ""
-	if p.conf.ServiceConfig == nil {
+	if p.currentConf().ServiceConfig == nil {
 		if appName == "" {
 			appName = currentLynxName()
 		}
@@ -86,7 +109,7 @@ func (p *PlugPolaris) GetConfigWatchTargets(appName string) ([]lynx.ControlPlane
 		mainFile = fmt.Sprintf("%s.yaml", appName)
 		mainGroup = appName
 	} else {
-		mainFile = p.conf.ServiceConfig.Filename
+		mainFile = p.currentConf().ServiceConfig.Filename
 		if mainFile == "" {
 			if appName == "" {
 				appName = currentLynxName()
@@ -96,7 +119,7 @@ func (p *PlugPolaris) GetConfigWatchTargets(appName string) ([]lynx.ControlPlane
 			}
 			mainFile = fmt.Sprintf("%s.yaml", appName)
 		}
-		mainGroup = p.conf.ServiceConfig.Group
+		mainGroup = p.currentConf().ServiceConfig.Group
 		if mainGroup == "" {
 			if appName == "" {
 				appName = currentLynxName()
@@ -113,11 +136,11 @@ func (p *PlugPolaris) GetConfigWatchTargets(appName string) ([]lynx.ControlPlane
 		FileName: mainFile,
 		Group:    mainGroup,
 	}}
-	if p.conf.ServiceConfig == nil {
+	if p.currentConf().ServiceConfig == nil {
 		return targets, nil
 	}
 
-	for _, cfg := range p.conf.ServiceConfig.AdditionalConfigs {
+	for _, cfg := range p.currentConf().ServiceConfig.AdditionalConfigs {
 		if cfg == nil || cfg.Filename == "" {
 			continue
 		}
@@ -157,24 +180,33 @@ func (p *PlugPolaris) WatchControlPlaneConfig(ctx context.Context, target lynx.C
 
 // getMainConfigSource gets the main configuration source based on service_config
 func (p *PlugPolaris) getMainConfigSource() (config.Source, error) {
-	if p.conf.ServiceConfig == nil {
+	mainFile := p.mainConfigFile()
+
+	log.Infof("Loading main configuration - File: [%s] Group: [%s] Namespace: [%s]",
+		mainFile.Filename, mainFile.Group, mainFile.Namespace)
+
+	return p.GetConfig(mainFile.Filename, mainFile.Group)
+}
+
+// mainConfigFile resolves the main configuration file from service_config, falling
+// back to {application_name}.yaml in the application group.
+func (p *PlugPolaris) mainConfigFile() *conf.ConfigFile {
+	cfg := p.currentConf()
+	if cfg.ServiceConfig == nil {
 		// Fallback to default behavior if service_config is not configured
 		appName := currentLynxName()
 		if appName == "" {
 			appName = "application"
 		}
-		configFileName := fmt.Sprintf("%s.yaml", appName)
-		group := appName
-		namespace := p.conf.Namespace
-
-		log.Infof("Loading main configuration - File: [%s] Group: [%s] Namespace: [%s]",
-			configFileName, group, namespace)
-
-		return p.GetConfig(configFileName, group)
+		return &conf.ConfigFile{
+			Filename:  fmt.Sprintf("%s.yaml", appName),
+			Group:     appName,
+			Namespace: cfg.Namespace,
+		}
 	}
 
 	// Use service_config configuration
-	serviceConfig := p.conf.ServiceConfig
+	serviceConfig := cfg.ServiceConfig
 
 	// Determine filename
 	filename := serviceConfig.Filename
@@ -198,22 +230,20 @@ func (p *PlugPolaris) getMainConfigSource() (config.Source, error) {
 	// Determine namespace
 	namespace := serviceConfig.Namespace
 	if namespace == "" {
-		namespace = p.conf.Namespace
+		namespace = cfg.Namespace
 	}
 
-	log.Infof("Loading main configuration - File: [%s] Group: [%s] Namespace: [%s]",
-		filename, group, namespace)
-
-	return p.GetConfig(filename, group)
+	return &conf.ConfigFile{Filename: filename, Group: group, Namespace: namespace}
 }
 
 // getAdditionalConfigSources gets additional configuration sources
 func (p *PlugPolaris) getAdditionalConfigSources() ([]config.Source, error) {
-	if p.conf.ServiceConfig == nil || len(p.conf.ServiceConfig.AdditionalConfigs) == 0 {
+	cfg := p.currentConf()
+	if cfg.ServiceConfig == nil || len(cfg.ServiceConfig.AdditionalConfigs) == 0 {
 		return nil, nil
 	}
 
-	serviceConfig := p.conf.ServiceConfig
+	serviceConfig := cfg.ServiceConfig
 
 	// Sort config files by priority (lower priority first, so higher priority overrides)
 	configFiles := make([]*conf.ConfigFile, len(serviceConfig.AdditionalConfigs))
@@ -232,7 +262,7 @@ func (p *PlugPolaris) getAdditionalConfigSources() ([]config.Source, error) {
 			namespace = serviceConfig.Namespace
 		}
 		if namespace == "" {
-			namespace = p.conf.Namespace
+			namespace = cfg.Namespace
 		}
 
 		// Determine merge strategy
@@ -259,25 +289,43 @@ func (p *PlugPolaris) getAdditionalConfigSources() ([]config.Source, error) {
 
 // GetConfigValue gets configuration value
 func (p *PlugPolaris) GetConfigValue(fileName, group string) (string, error) {
+	return p.getConfigContent("", fileName, group)
+}
+
+// getConfigContent fetches the content of a config file in namespace, or in the
+// plugin namespace when namespace is empty. Concurrent calls for the same file share one
+// fetch.
+func (p *PlugPolaris) getConfigContent(namespace, fileName, group string) (string, error) {
+	v, err, _ := p.fetches.Do("config\x00"+namespace+"\x00"+group+"\x00"+fileName, func() (any, error) {
+		return p.fetchConfigContent(namespace, fileName, group)
+	})
+	content, _ := v.(string)
+	return content, err
+}
+
+// fetchConfigContent fetches the content of a config file for getConfigContent.
+func (p *PlugPolaris) fetchConfigContent(namespace, fileName, group string) (string, error) {
 	if err := p.checkInitialized(); err != nil {
 		return "", err
 	}
+	if err := p.checkSubsystem(SubsystemConfig); err != nil {
+		return "", err
+	}
 
 	// Snapshot mutable plugin state under the lock to avoid a data race / nil
 	// dereference if cleanup runs concurrently with this request.
 	p.mu.RLock()
-	sdk := p.sdk
-	namespace := ""
-	if p.conf != nil {
-		namespace = p.conf.Namespace
+	configAPI := p.configLocked()
+	if namespace == "" && p.currentConf() != nil {
+		namespace = p.currentConf().Namespace
 	}
 	metrics := p.metrics
 	circuitBreaker := p.circuitBreaker
 	retryManager := p.retryManager
 	p.mu.RUnlock()
 
-	if sdk == nil || circuitBreaker == nil || retryManager == nil {
-		return "", NewInitError("Polaris plugin has been destroyed")
+	if configAPI == nil || circuitBreaker == nil || retryManager == nil {
+		return "", newDestroyedError()
 	}
 
 	// Record configuration operation metrics
@@ -292,35 +340,38 @@ func (p *PlugPolaris) GetConfigValue(fileName, group string) (string, error) {
 
 	log.Infof("Getting configFile: %s, group: %s", fileName, group)
 
-	// Create Config API client
-	configAPI := api.NewConfigFileAPIBySDKContext(sdk)
-	if configAPI == nil {
-		return "", NewInitError("failed to create configFile API")
-	}
-
 	// Execute with circuit breaker and retry mechanism
+	start := time.Now()
 	var configFile model.ConfigFile
 	var lastErr error
 
 	err := circuitBreaker.Do(func() error {
-		return retryManager.DoWithRetry(func() error {
+		// Config reads are idempotent, so slow ones are hedged
+		cfg, err := DoWithHedging(context.Background(), retryManager, func(context.Context) (model.ConfigFile, error) {
 			// Call SDK API to get configuration
-			cfg, err := configAPI.GetConfigFile(namespace, group, fileName)
-			if err != nil {
-				lastErr = err
-				return err
-			}
-			configFile = cfg
-			return nil
+			return configAPI.GetConfigFile(namespace, group, fileName)
 		})
+		if err != nil {
+			lastErr = err
+			return err
+		}
+		configFile = cfg
+		return nil
 	})
 
+	if metrics != nil {
+		metrics.RecordConfigOperationDuration("get", fileName, group, time.Since(start).Seconds())
+	}
 	if err != nil {
-		log.Errorf("Failed to get configFile %s:%s after retries: %v", fileName, group, err)
+		log.Errorf("Failed to get configFile %s:%s after retries: %v", fileName, group, p.redactError(err))
 		if metrics != nil {
 			metrics.RecordConfigOperation("get", fileName, group, "error")
+			metrics.RecordOperationError("get_config", err)
 		}
-		return "", WrapServiceError(lastErr, ErrCodeConfigGetFailed, "failed to get configFile value")
+		if content, snapshotErr := p.loadConfigSnapshot(namespace, group, fileName); snapshotErr == nil {
+			return p.decryptConfigContent(fileName, group, content)
+		}
+		return "", WrapServiceError(TranslateSDKError(lastErr), ErrCodeConfigGetFailed, "failed to get configFile value")
 	}
 
 	// Check if configuration exists
@@ -331,25 +382,32 @@ func (p *PlugPolaris) GetConfigValue(fileName, group string) (string, error) {
 
 	// Get configuration content
 	content := configFile.GetContent()
+	if err := p.checkConfigContent(fileName, group, content); err != nil {
+		log.Errorf("Config %s:%s failed validation, serving last good content: %v", fileName, group, p.redactError(err))
+		return p.lastGoodConfigContent(namespace, fileName, group, err)
+	}
+	p.recordConfigFetch(fileName, group, content)
+	p.saveConfigSnapshot(namespace, group, fileName, content)
 	log.Infof("Successfully got configFile %s:%s, content length: %d", fileName, group, len(content))
-	return content, nil
+	return p.decryptConfigContent(fileName, group, content)
 }
 
 // loadPolarisConfiguration loads the Polaris SDK config file (from ConfigPath when set,
 // otherwise falls back to the embedded default) and initializes the SDK context.
 func (p *PlugPolaris) loadPolarisConfiguration() (api.SDKContext, error) {
+	cfg := p.currentConf()
 	// Create basic configuration
 	configuration := api.NewConfiguration()
 
-	if p.conf.ConfigPath != "" {
+	if cfg.ConfigPath != "" {
 		// Check if configuration file exists
-		if _, err := os.Stat(p.conf.ConfigPath); os.IsNotExist(err) {
-			log.Warnf("Polaris configuration file not found: %s, using default configuration", p.conf.ConfigPath)
+		if _, err := os.Stat(cfg.ConfigPath); os.IsNotExist(err) {
+			log.Warnf("Polaris configuration file not found: %s, using default configuration", cfg.ConfigPath)
 		} else {
-			log.Infof("Loading Polaris SDK configuration from: %s", p.conf.ConfigPath)
+			log.Infof("Loading Polaris SDK configuration from: %s", cfg.ConfigPath)
 
 			// Read configuration file content
-			configData, err := os.ReadFile(p.conf.ConfigPath)
+			configData, err := os.ReadFile(cfg.ConfigPath)
 			if err != nil {
 				log.Errorf("Failed to read Polaris configuration file: %v", err)
 				return nil, fmt.Errorf("failed to read Polaris configuration file: %w", err)
@@ -368,30 +426,48 @@ func (p *PlugPolaris) loadPolarisConfiguration() (api.SDKContext, error) {
 				return nil, fmt.Errorf("failed to apply Polaris configuration: %w", err)
 			}
 
-			log.Infof("Successfully loaded Polaris configuration from: %s", p.conf.ConfigPath)
+			log.Infof("Successfully loaded Polaris configuration from: %s", cfg.ConfigPath)
 
-			// Initialize SDK context directly from the YAML file to ensure full configuration is applied
-			sdk, err := api.InitContextByFile(p.conf.ConfigPath)
+			if !hasServerBootstrap(p.activeServerBootstrap()) {
+				// Initialize SDK context directly from the YAML file to ensure full configuration is applied
+				sdk, err := api.InitContextByFile(cfg.ConfigPath)
+				if err != nil {
+					return nil, fmt.Errorf("failed to initialize Polaris SDK context: %w", err)
+				}
+				return p.applyServerConnections(sdk)
+			}
+
+			// Load the full file configuration so the bootstrap addresses can override it
+			fileConfiguration, err := polarisconfig.LoadConfigurationByFile(cfg.ConfigPath)
 			if err != nil {
-				return nil, fmt.Errorf("failed to initialize Polaris SDK context: %w", err)
+				return nil, fmt.Errorf("failed to load Polaris SDK configuration: %w", err)
 			}
-			return sdk, nil
+			configuration = fileConfiguration
 		}
 	} else {
 		log.Info("Using default Polaris SDK configuration")
 	}
 
+	if hasServerBootstrap(p.activeServerBootstrap()) {
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DefaultTimeoutSeconds*time.Second)
+		defer cancel()
+		if err := p.applyServerBootstrap(ctx, configuration); err != nil {
+			return nil, fmt.Errorf("failed to resolve Polaris server addresses: %w", err)
+		}
+	}
+
 	// Initialize SDK context
 	sdk, err := api.InitContextByConfig(configuration)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize Polaris SDK context: %w", err)
 	}
 
-	return sdk, nil
+	return p.applyServerConnections(sdk)
 }
 
 // applyPolarisConfig applies parsed configuration file content to SDK configuration object
 func (p *PlugPolaris) applyPolarisConfig(_ any, polarisConfig map[string]any) error {
+	cfg := p.currentConf()
 	// Validate basic structure of configuration file
 	if polarisConfig == nil {
 		return fmt.Errorf("polaris config is nil")
@@ -403,11 +479,11 @@ func (p *PlugPolaris) applyPolarisConfig(_ any, polarisConfig map[string]any) er
 	}
 
 	// NOTE: The configuration file path is passed directly to the SDK via
-	// api.InitContextByFile(p.conf.ConfigPath) in loadPolarisConfiguration.
+	// api.InitContextByFile(cfg.ConfigPath) in loadPolarisConfiguration.
 	// We deliberately do NOT mutate the process-global POLARIS_CONFIG_PATH
 	// environment variable here: os.Setenv is not concurrency-safe and would
 	// leak state across plugin instances / the whole process.
-	log.Infof("Polaris configuration file will be loaded directly from: %s", p.conf.ConfigPath)
+	log.Infof("Polaris configuration file will be loaded directly from: %s", cfg.ConfigPath)
 
 	return nil
 }
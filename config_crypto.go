@@ -0,0 +1,230 @@
+package polaris
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/config"
+)
+
+// encryptedValuePattern matches ENC(<base64 ciphertext>) values in config content.
+var encryptedValuePattern = regexp.MustCompile(`ENC\(([A-Za-z0-9+/=]+)\)`)
+
+// Decryptor decrypts the ciphertext of ENC(...) config values. Implementations can
+// use a local key, a KMS or Vault.
+type Decryptor interface {
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// DecryptorFunc adapts a function to a Decryptor.
+type DecryptorFunc func(ctx context.Context, ciphertext []byte) ([]byte, error)
+
+// Decrypt implements Decryptor.
+func (f DecryptorFunc) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return f(ctx, ciphertext)
+}
+
+// aesDecryptor decrypts AES-GCM ciphertext prefixed with its nonce.
+type aesDecryptor struct {
+	aead cipher.AEAD
+}
+
+// newAESGCM returns the AES-GCM cipher of key, which must be 16, 24 or 32 bytes.
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// NewAESDecryptor returns a Decryptor for AES-GCM ciphertext with a leading nonce,
+// as produced by EncryptConfigValue.
+func NewAESDecryptor(key []byte) (Decryptor, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, NewConfigError(fmt.Sprintf("invalid AES key: %v", err))
+	}
+	return &aesDecryptor{aead: aead}, nil
+}
+
+// NewAESDecryptorFromEnv returns an AES-GCM Decryptor whose key is read, base64
+// encoded, from the environment variable name.
+func NewAESDecryptorFromEnv(name string) (Decryptor, error) {
+	encoded := os.Getenv(name)
+	if encoded == "" {
+		return nil, NewConfigError(fmt.Sprintf("environment variable %s holding the config key is not set", name))
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, NewConfigError(fmt.Sprintf("environment variable %s is not a base64 key: %v", name, err))
+	}
+	return NewAESDecryptor(key)
+}
+
+// Decrypt implements Decryptor.
+func (d *aesDecryptor) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	size := d.aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, fmt.Errorf("ciphertext is shorter than the nonce")
+	}
+	return d.aead.Open(nil, ciphertext[:size], ciphertext[size:], nil)
+}
+
+// EncryptConfigValue encrypts plaintext with AES-GCM and returns it as an ENC(...) value
+// that the plugin decrypts with the same key.
+func EncryptConfigValue(key, plaintext []byte) (string, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return "", NewConfigError(fmt.Sprintf("invalid AES key: %v", err))
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+	return "ENC(" + base64.StdEncoding.EncodeToString(sealed) + ")", nil
+}
+
+// SetDecryptor sets the decryptor of ENC(...) config values, replacing the one built
+// from config_encryption.
+func (p *PlugPolaris) SetDecryptor(decryptor Decryptor) {
+	p.decryptorMutex.Lock()
+	defer p.decryptorMutex.Unlock()
+	p.decryptor = decryptor
+}
+
+// configDecryptor returns the decryptor set at runtime, or builds the AES decryptor
+// from config_encryption.key_env on first use.
+func (p *PlugPolaris) configDecryptor() (Decryptor, error) {
+	p.decryptorMutex.Lock()
+	defer p.decryptorMutex.Unlock()
+	if p.decryptor != nil {
+		return p.decryptor, nil
+	}
+	p.mu.RLock()
+	keyEnv := p.currentConf().GetConfigEncryption().GetKeyEnv()
+	p.mu.RUnlock()
+	if keyEnv == "" {
+		return nil, NewConfigError("no decryptor is configured, set config_encryption.key_env or call SetDecryptor")
+	}
+	decryptor, err := NewAESDecryptorFromEnv(keyEnv)
+	if err != nil {
+		return nil, err
+	}
+	p.decryptor = decryptor
+	return decryptor, nil
+}
+
+// isEncryptedConfig reports whether content holds ENC(...) values.
+func isEncryptedConfig(content string) bool {
+	return strings.Contains(content, "ENC(") && encryptedValuePattern.MatchString(content)
+}
+
+// decryptConfigContent replaces every ENC(...) value of a config file with its plaintext.
+// Content without encrypted values is returned unchanged. Errors never include plaintext.
+func (p *PlugPolaris) decryptConfigContent(fileName, group, content string) (string, error) {
+	if !isEncryptedConfig(content) {
+		return content, nil
+	}
+	decryptor, err := p.configDecryptor()
+	if err != nil {
+		return "", NewPolarisError(ErrCodeConfigInvalid,
+			fmt.Sprintf("config %s:%s is encrypted", fileName, group)).WithCause(err)
+	}
+	ctx := p.watcherContext()
+	var decryptErr error
+	decrypted := encryptedValuePattern.ReplaceAllStringFunc(content, func(value string) string {
+		if decryptErr != nil {
+			return value
+		}
+		encoded := encryptedValuePattern.FindStringSubmatch(value)[1]
+		ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			decryptErr = err
+			return value
+		}
+		plaintext, err := decryptor.Decrypt(ctx, ciphertext)
+		if err != nil {
+			decryptErr = err
+			return value
+		}
+		return string(plaintext)
+	})
+	if decryptErr != nil {
+		return "", NewPolarisError(ErrCodeConfigInvalid,
+			fmt.Sprintf("failed to decrypt config %s:%s", fileName, group)).WithCause(decryptErr)
+	}
+	return decrypted, nil
+}
+
+// decryptingConfigSource decrypts the ENC(...) values loaded by its source.
+type decryptingConfigSource struct {
+	config.Source
+	plugin *PlugPolaris
+	group  string
+}
+
+// withConfigDecryption wraps source so that it serves decrypted content.
+func (p *PlugPolaris) withConfigDecryption(source config.Source, group string) config.Source {
+	if source == nil {
+		return nil
+	}
+	return &decryptingConfigSource{Source: source, plugin: p, group: group}
+}
+
+// Load implements config.Source.
+func (s *decryptingConfigSource) Load() ([]*config.KeyValue, error) {
+	kvs, err := s.Source.Load()
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(kvs)
+}
+
+// Watch implements config.Source.
+func (s *decryptingConfigSource) Watch() (config.Watcher, error) {
+	watcher, err := s.Source.Watch()
+	if err != nil {
+		return nil, err
+	}
+	return &decryptingConfigWatcher{Watcher: watcher, source: s}, nil
+}
+
+// decrypt returns copies of kvs with decrypted values.
+func (s *decryptingConfigSource) decrypt(kvs []*config.KeyValue) ([]*config.KeyValue, error) {
+	out := make([]*config.KeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		if kv == nil {
+			continue
+		}
+		content, err := s.plugin.decryptConfigContent(kv.Key, s.group, string(kv.Value))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, &config.KeyValue{Key: kv.Key, Value: []byte(content), Format: kv.Format})
+	}
+	return out, nil
+}
+
+// decryptingConfigWatcher decrypts the changes reported by a watcher.
+type decryptingConfigWatcher struct {
+	config.Watcher
+	source *decryptingConfigSource
+}
+
+// Next implements config.Watcher.
+func (w *decryptingConfigWatcher) Next() ([]*config.KeyValue, error) {
+	kvs, err := w.Watcher.Next()
+	if err != nil {
+		return nil, err
+	}
+	return w.source.decrypt(kvs)
+}
@@ -0,0 +1,79 @@
+package polaris
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecryptConfigContent_InlineValues(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	password, err := EncryptConfigValue(key, []byte("s3cret"))
+	require.NoError(t, err)
+	token, err := EncryptConfigValue(key, []byte("t0ken"))
+	require.NoError(t, err)
+
+	t.Setenv("TEST_CONFIG_KEY", base64.StdEncoding.EncodeToString(key))
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", ConfigEncryption: &conf.ConfigEncryption{KeyEnv: "TEST_CONFIG_KEY"}})
+
+	content, err := plugin.decryptConfigContent("db.yaml", "orders", "user: app\npassword: "+password+"\ntoken: "+token+"\n")
+	require.NoError(t, err)
+	assert.Equal(t, "user: app\npassword: s3cret\ntoken: t0ken\n", content)
+
+	plain := "password: ENC not used here\n"
+	content, err = plugin.decryptConfigContent("db.yaml", "orders", plain)
+	require.NoError(t, err)
+	assert.Equal(t, plain, content)
+}
+
+func TestDecryptConfigContent_Errors(t *testing.T) {
+	encrypted, err := EncryptConfigValue(bytes.Repeat([]byte{1}, 16), []byte("s3cret"))
+	require.NoError(t, err)
+
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	_, err = plugin.decryptConfigContent("db.yaml", "orders", encrypted)
+	assert.True(t, IsConfigError(err))
+
+	wrongKey, err := NewAESDecryptor(bytes.Repeat([]byte{2}, 16))
+	require.NoError(t, err)
+	plugin.SetDecryptor(wrongKey)
+	_, err = plugin.decryptConfigContent("db.yaml", "orders", encrypted)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "db.yaml")
+	assert.NotContains(t, err.Error(), "s3cret")
+
+	_, err = NewAESDecryptor([]byte("short"))
+	assert.True(t, IsConfigError(err))
+	_, err = NewAESDecryptorFromEnv("TEST_CONFIG_KEY_UNSET")
+	assert.True(t, IsConfigError(err))
+}
+
+func TestDecryptingConfigSource(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	plugin.SetDecryptor(DecryptorFunc(func(_ context.Context, ciphertext []byte) ([]byte, error) {
+		return bytes.ToUpper(ciphertext), nil
+	}))
+	value := "key: ENC(" + base64.StdEncoding.EncodeToString([]byte("secret")) + ")\n"
+	inner := &stubConfigSource{kvs: []*config.KeyValue{{Key: "app.yaml", Value: []byte(value), Format: "yaml"}}}
+	source := plugin.withConfigDecryption(inner, "orders")
+
+	kvs, err := source.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "key: SECRET\n", string(kvs[0].Value))
+	assert.Equal(t, value, string(inner.kvs[0].Value))
+
+	watcher, err := source.Watch()
+	require.NoError(t, err)
+	kvs, err = watcher.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "key: SECRET\n", string(kvs[0].Value))
+}
@@ -0,0 +1,93 @@
+package polaris
+
+import (
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// defaultDebounceMaxWaitFactor sets max_wait to this multiple of the window by default.
+const defaultDebounceMaxWaitFactor = 5
+
+// debounceSettings returns the debounce window and max wait from config.
+func debounceSettings(cfg *conf.ConfigDebounce) (time.Duration, time.Duration) {
+	return cfg.GetWindow().AsDuration(), cfg.GetMaxWait().AsDuration()
+}
+
+// SetDebounce coalesces changes that arrive within window of each other into one
+// callback carrying the content before the first change and after the last. Callbacks
+// are delayed by at most maxWait (default 5 times window) while changes keep arriving.
+// A zero window disables debouncing.
+func (cw *ConfigWatcher) SetDebounce(window, maxWait time.Duration) {
+	if maxWait <= 0 {
+		maxWait = defaultDebounceMaxWaitFactor * window
+	}
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	cw.debounce = max(window, 0)
+	cw.debounceMaxWait = max(maxWait, cw.debounce)
+}
+
+// scheduleConfigChanged notifies a change now, or after the debounce window when
+// debouncing is enabled.
+func (cw *ConfigWatcher) scheduleConfigChanged(previous, config model.ConfigFile) {
+	cw.mu.Lock()
+	if cw.debounce <= 0 {
+		cw.mu.Unlock()
+		cw.notifyConfigChanged(previous, config)
+		return
+	}
+	now := time.Now()
+	if cw.pending == 0 {
+		cw.pendingSince = now
+		cw.pendingPrevious = previous
+	}
+	cw.pending++
+	cw.pendingConfig = config
+
+	delay := cw.debounce
+	if remaining := cw.pendingSince.Add(cw.debounceMaxWait).Sub(now); remaining < delay {
+		delay = max(remaining, 0)
+	}
+	if cw.debounceTimer == nil {
+		cw.debounceTimer = time.AfterFunc(delay, cw.flushConfigChanged)
+	} else {
+		cw.debounceTimer.Reset(delay)
+	}
+	cw.mu.Unlock()
+}
+
+// flushConfigChanged runs the callbacks for the coalesced pending changes.
+func (cw *ConfigWatcher) flushConfigChanged() {
+	cw.mu.Lock()
+	if cw.pending == 0 || cw.ctx.Err() != nil {
+		cw.clearPendingLocked()
+		cw.mu.Unlock()
+		return
+	}
+	count, previous, config := cw.pending, cw.pendingPrevious, cw.pendingConfig
+	cw.clearPendingLocked()
+	cw.mu.Unlock()
+
+	if previous != nil && config != nil && previous.GetContent() == config.GetContent() {
+		log.Infof("Config %s:%s changed %d times and was restored within the debounce window", cw.fileName, cw.group, count)
+		return
+	}
+	if count > 1 {
+		log.Infof("Coalesced %d changes of config %s:%s", count, cw.fileName, cw.group)
+	}
+	cw.notifyConfigChanged(previous, config)
+}
+
+// clearPendingLocked drops pending changes and stops the debounce timer. cw.mu must be held.
+func (cw *ConfigWatcher) clearPendingLocked() {
+	if cw.debounceTimer != nil {
+		cw.debounceTimer.Stop()
+		cw.debounceTimer = nil
+	}
+	cw.pending = 0
+	cw.pendingPrevious = nil
+	cw.pendingConfig = nil
+}
@@ -0,0 +1,87 @@
+package polaris
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordedChanges collects the changes reported by a config watcher.
+type recordedChanges struct {
+	mu      sync.Mutex
+	changes []ConfigChange
+}
+
+func (r *recordedChanges) record(change ConfigChange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, change)
+}
+
+func (r *recordedChanges) get() []ConfigChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ConfigChange(nil), r.changes...)
+}
+
+func newDebouncedWatcher(window, maxWait time.Duration) (*ConfigWatcher, *recordedChanges) {
+	watcher := NewConfigWatcher(nil, "app.yaml", "orders", "default")
+	watcher.SetDebounce(window, maxWait)
+	recorded := &recordedChanges{}
+	watcher.SetOnConfigChange(recorded.record)
+	return watcher, recorded
+}
+
+// publish feeds content to the watcher as a polled config.
+func publish(watcher *ConfigWatcher, content string) {
+	config := &fakeConfigFile{content: content}
+	if previous, changed := watcher.updateConfig(config); changed {
+		watcher.scheduleConfigChanged(previous, config)
+	}
+}
+
+func TestConfigWatcher_DebounceCoalescesChanges(t *testing.T) {
+	watcher, recorded := newDebouncedWatcher(50*time.Millisecond, time.Second)
+	publish(watcher, "v: 1\n")
+	require.Eventually(t, func() bool { return len(recorded.get()) == 1 }, time.Second, 5*time.Millisecond)
+
+	publish(watcher, "v: 2\n")
+	publish(watcher, "v: 3\n")
+	publish(watcher, "v: 4\n")
+	require.Eventually(t, func() bool { return len(recorded.get()) == 2 }, time.Second, 5*time.Millisecond)
+	change := recorded.get()[1]
+	assert.Equal(t, "v: 1\n", change.OldContent)
+	assert.Equal(t, "v: 4\n", change.NewContent)
+
+	// A change reverted within the window produces no callback.
+	publish(watcher, "v: 5\n")
+	publish(watcher, "v: 4\n")
+	time.Sleep(150 * time.Millisecond)
+	assert.Len(t, recorded.get(), 2)
+}
+
+func TestConfigWatcher_DebounceMaxWait(t *testing.T) {
+	watcher, recorded := newDebouncedWatcher(80*time.Millisecond, 150*time.Millisecond)
+	start := time.Now()
+	for i := 0; time.Since(start) < 400*time.Millisecond; i++ {
+		publish(watcher, string(rune('a'+i%26))+"\n")
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.NotEmpty(t, recorded.get(), "changes arriving faster than the window must still be flushed by max_wait")
+}
+
+func TestConfigWatcher_NoDebounceAndStop(t *testing.T) {
+	watcher, recorded := newDebouncedWatcher(0, 0)
+	publish(watcher, "v: 1\n")
+	assert.Len(t, recorded.get(), 1)
+
+	watcher, recorded = newDebouncedWatcher(50*time.Millisecond, 0)
+	watcher.Start()
+	publish(watcher, "v: 1\n")
+	watcher.Stop()
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, recorded.get())
+}
@@ -0,0 +1,112 @@
+package polaris
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file formats understood by GetConfigInto.
+const (
+	ConfigFormatJSON = "json"
+	ConfigFormatYAML = "yaml"
+	ConfigFormatTOML = "toml"
+)
+
+// configValidator is implemented by config structs that check themselves after decoding.
+type configValidator interface {
+	Validate() error
+}
+
+// detectConfigFormat returns the format of a config file from its extension, falling
+// back to its content: a leading '{' or '[' means JSON, anything else YAML.
+func detectConfigFormat(fileName, content string) string {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".json":
+		return ConfigFormatJSON
+	case ".yaml", ".yml":
+		return ConfigFormatYAML
+	case ".toml":
+		return ConfigFormatTOML
+	}
+	trimmed := strings.TrimSpace(content)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return ConfigFormatJSON
+	}
+	return ConfigFormatYAML
+}
+
+// jsonErrorLocation returns the line and column of a byte offset in content.
+func jsonErrorLocation(content string, offset int64) (int, int) {
+	offset = min(max(offset, 0), int64(len(content)))
+	before := content[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := int(offset) - strings.LastIndex(before, "\n")
+	return line, column
+}
+
+// decodeConfig unmarshals content in format into out.
+func decodeConfig(format, content string, out any) error {
+	switch format {
+	case ConfigFormatJSON:
+		decoder := json.NewDecoder(strings.NewReader(content))
+		if err := decoder.Decode(out); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			switch {
+			case errors.As(err, &syntaxErr):
+				line, column := jsonErrorLocation(content, syntaxErr.Offset)
+				return fmt.Errorf("line %d, column %d: %w", line, column, err)
+			case errors.As(err, &typeErr):
+				line, column := jsonErrorLocation(content, typeErr.Offset)
+				return fmt.Errorf("line %d, column %d: %w", line, column, err)
+			}
+			return err
+		}
+		return nil
+	case ConfigFormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader([]byte(content)))
+		if err := decoder.Decode(out); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		return nil
+	case ConfigFormatTOML:
+		_, err := toml.Decode(content, out)
+		return err
+	}
+	return fmt.Errorf("unknown config format %q", format)
+}
+
+// GetConfigInto fetches a config file and unmarshals it into out, which must be a pointer.
+// The format is detected from the file extension (.json, .yaml, .yml, .toml) or, without one,
+// from the content. When out implements Validate() error, it is called after decoding.
+// Parse and validation errors are config errors that name the file and format.
+func (p *PlugPolaris) GetConfigInto(fileName, group string, out any) error {
+	if out == nil {
+		return NewConfigError("config target must not be nil")
+	}
+	content, err := p.GetConfigValue(fileName, group)
+	if err != nil {
+		return err
+	}
+
+	format := detectConfigFormat(fileName, content)
+	if err := decodeConfig(format, content, out); err != nil {
+		return NewPolarisError(ErrCodeConfigInvalid, fmt.Sprintf("failed to parse config %s:%s as %s", group, fileName, format)).
+			WithCause(err)
+	}
+	if v, ok := out.(configValidator); ok {
+		if err := v.Validate(); err != nil {
+			return NewPolarisError(ErrCodeConfigValidation, fmt.Sprintf("config %s:%s is invalid", group, fileName)).
+				WithCause(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package polaris
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decodedConfig struct {
+	Name    string `json:"name" yaml:"name" toml:"name"`
+	Workers int    `json:"workers" yaml:"workers" toml:"workers"`
+}
+
+func (c *decodedConfig) Validate() error {
+	if c.Workers <= 0 {
+		return errors.New("workers must be positive")
+	}
+	return nil
+}
+
+func TestDetectConfigFormat(t *testing.T) {
+	assert.Equal(t, ConfigFormatJSON, detectConfigFormat("app.json", ""))
+	assert.Equal(t, ConfigFormatYAML, detectConfigFormat("app.YML", ""))
+	assert.Equal(t, ConfigFormatTOML, detectConfigFormat("app.toml", ""))
+	assert.Equal(t, ConfigFormatJSON, detectConfigFormat("app", "  {\"name\": \"a\"}"))
+	assert.Equal(t, ConfigFormatYAML, detectConfigFormat("app", "name: a"))
+}
+
+func TestDecodeConfig(t *testing.T) {
+	var cfg decodedConfig
+	require.NoError(t, decodeConfig(ConfigFormatJSON, `{"name": "orders", "workers": 4}`, &cfg))
+	assert.Equal(t, decodedConfig{Name: "orders", Workers: 4}, cfg)
+
+	cfg = decodedConfig{}
+	require.NoError(t, decodeConfig(ConfigFormatYAML, "name: orders\nworkers: 8\n", &cfg))
+	assert.Equal(t, decodedConfig{Name: "orders", Workers: 8}, cfg)
+	require.NoError(t, decodeConfig(ConfigFormatYAML, "", &cfg), "empty YAML leaves out unchanged")
+
+	err := decodeConfig(ConfigFormatJSON, "{\n  \"name\": \"orders\",\n  \"workers\": \"four\"\n}", &cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 3")
+
+	err = decodeConfig(ConfigFormatJSON, "{\n  \"name\" \"orders\"\n}", &cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+
+	assert.Error(t, decodeConfig(ConfigFormatYAML, "workers: [", &cfg))
+
+	cfg = decodedConfig{}
+	require.NoError(t, decodeConfig(ConfigFormatTOML, "name = \"orders\"\nworkers = 16\n", &cfg))
+	assert.Equal(t, decodedConfig{Name: "orders", Workers: 16}, cfg)
+	err = decodeConfig(ConfigFormatTOML, "name = \"orders\"\nworkers = \n", &cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestGetConfigInto_Errors(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.True(t, IsConfigError(plugin.GetConfigInto("app.yaml", "default", nil)))
+	assert.Error(t, plugin.GetConfigInto("app.yaml", "default", &decodedConfig{}), "not initialized")
+}
@@ -0,0 +1,158 @@
+package polaris
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxConfigDiffCells bounds the size of the line table used to diff config content.
+// Larger changes are reported as the whole changed region being replaced.
+const maxConfigDiffCells = 1 << 22
+
+// ConfigDiffKind tells whether a diff line was added or removed.
+type ConfigDiffKind string
+
+const (
+	// ConfigLineAdded marks a line present only in the new content.
+	ConfigLineAdded ConfigDiffKind = "added"
+	// ConfigLineRemoved marks a line present only in the previous content.
+	ConfigLineRemoved ConfigDiffKind = "removed"
+)
+
+// ConfigDiffLine is one changed line of a config file. Line is the 1-based line number
+// in the new content for added lines and in the previous content for removed lines.
+type ConfigDiffLine struct {
+	Kind ConfigDiffKind `json:"kind"`
+	Line int            `json:"line"`
+	Text string         `json:"text"`
+}
+
+// ConfigChange describes a change of a watched config file.
+type ConfigChange struct {
+	FileName   string
+	Group      string
+	Namespace  string
+	OldContent string
+	NewContent string
+	Diff       []ConfigDiffLine
+}
+
+// newConfigChange builds a change with the line diff between oldContent and newContent.
+func newConfigChange(fileName, group, namespace, oldContent, newContent string) ConfigChange {
+	return ConfigChange{
+		FileName:   fileName,
+		Group:      group,
+		Namespace:  namespace,
+		OldContent: oldContent,
+		NewContent: newContent,
+		Diff:       diffConfigLines(oldContent, newContent),
+	}
+}
+
+// Counts returns the number of added and removed lines.
+func (c ConfigChange) Counts() (added, removed int) {
+	for _, line := range c.Diff {
+		if line.Kind == ConfigLineAdded {
+			added++
+		} else {
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// DiffText renders the diff with one "-<line>: text" or "+<line>: text" entry per line.
+func (c ConfigChange) DiffText() string {
+	var b strings.Builder
+	for _, line := range c.Diff {
+		sign := "+"
+		if line.Kind == ConfigLineRemoved {
+			sign = "-"
+		}
+		fmt.Fprintf(&b, "%s%d: %s\n", sign, line.Line, line.Text)
+	}
+	return b.String()
+}
+
+// splitConfigLines splits content into lines, ignoring a trailing newline.
+func splitConfigLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// diffConfigLines returns the lines removed from oldContent and added in newContent,
+// based on their longest common subsequence. Removed lines come before added lines
+// within each changed region.
+func diffConfigLines(oldContent, newContent string) []ConfigDiffLine {
+	oldLines, newLines := splitConfigLines(oldContent), splitConfigLines(newContent)
+
+	// Trim the common prefix and suffix, which covers most config edits cheaply.
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+	a := oldLines[prefix : len(oldLines)-suffix]
+	b := newLines[prefix : len(newLines)-suffix]
+
+	var diff []ConfigDiffLine
+	removed := func(i int) {
+		diff = append(diff, ConfigDiffLine{Kind: ConfigLineRemoved, Line: prefix + i + 1, Text: a[i]})
+	}
+	added := func(j int) {
+		diff = append(diff, ConfigDiffLine{Kind: ConfigLineAdded, Line: prefix + j + 1, Text: b[j]})
+	}
+	if len(a)*len(b) > maxConfigDiffCells {
+		for i := range a {
+			removed(i)
+		}
+		for j := range b {
+			added(j)
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the common subsequence length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j, pending := 0, 0, 0
+	flush := func() {
+		for ; pending > 0; pending-- {
+			added(j - pending)
+		}
+	}
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flush()
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			pending++
+			j++
+		default:
+			removed(i)
+			i++
+		}
+	}
+	flush()
+	return diff
+}
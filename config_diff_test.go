@@ -0,0 +1,77 @@
+package polaris
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffConfigLines(t *testing.T) {
+	diff := diffConfigLines("a\nb\nc\nd\n", "a\nB\nc\nd\ne\n")
+	assert.Equal(t, []ConfigDiffLine{
+		{Kind: ConfigLineRemoved, Line: 2, Text: "b"},
+		{Kind: ConfigLineAdded, Line: 2, Text: "B"},
+		{Kind: ConfigLineAdded, Line: 5, Text: "e"},
+	}, diff)
+
+	assert.Empty(t, diffConfigLines("a\nb", "a\nb\n"))
+	assert.Equal(t, []ConfigDiffLine{{Kind: ConfigLineAdded, Line: 1, Text: "x"}}, diffConfigLines("", "x"))
+
+	change := newConfigChange("app.yaml", "orders", "default", "x: 1\ny: 2\n", "y: 2\nz: 3\n")
+	added, removed := change.Counts()
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, "-1: x: 1\n+2: z: 3\n", change.DiffText())
+}
+
+func TestConfigWatcher_ChangeCallbackCarriesDiff(t *testing.T) {
+	watcher := NewConfigWatcher(nil, "app.yaml", "orders", "default")
+	var changes []ConfigChange
+	watcher.SetOnConfigChange(func(change ConfigChange) {
+		changes = append(changes, change)
+	})
+
+	for _, content := range []string{"workers: 4\n", "workers: 4\n", "workers: 8\n"} {
+		config := &fakeConfigFile{content: content}
+		if previous, changed := watcher.updateConfig(config); changed {
+			watcher.notifyConfigChanged(previous, config)
+		}
+	}
+
+	require.Len(t, changes, 2)
+	assert.Empty(t, changes[0].OldContent)
+	assert.Equal(t, "workers: 4\n", changes[1].OldContent)
+	assert.Equal(t, "workers: 8\n", changes[1].NewContent)
+	assert.Equal(t, "-1: workers: 4\n+1: workers: 8\n", changes[1].DiffText())
+}
+
+func TestHandleConfigChanged_PublishesDiffAgainstCache(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := plugin.Subscribe(ctx, EventTypeConfigChanged)
+	require.NoError(t, err)
+
+	plugin.updateConfigCache("app.yaml", "orders", &fakeConfigFile{content: "a\nb\n"})
+	plugin.handleConfigChanged("app.yaml", "orders", &fakeConfigFile{content: "a\nc\n"})
+
+	select {
+	case ev := <-events:
+		changed := ev.(*ConfigChangedEvent)
+		assert.Equal(t, 4, changed.PreviousContentLength)
+		assert.Equal(t, []ConfigDiffLine{
+			{Kind: ConfigLineRemoved, Line: 2, Text: "b"},
+			{Kind: ConfigLineAdded, Line: 2, Text: "c"},
+		}, changed.Diff)
+	case <-time.After(time.Second):
+		t.Fatal("expected config changed event")
+	}
+	content, ok := plugin.cachedConfigContent("app.yaml", "orders")
+	assert.True(t, ok)
+	assert.Equal(t, "a\nc\n", content)
+}
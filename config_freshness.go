@@ -0,0 +1,223 @@
+package polaris
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/go-lynx/lynx/log"
+	"github.com/go-lynx/lynx/plugins"
+)
+
+// ConfigFreshness describes how fresh the locally known content of a config file is.
+type ConfigFreshness struct {
+	FileName string `json:"file_name"`
+	Group    string `json:"group"`
+	// Version is the MD5 digest of the current content, matching Polaris release digests.
+	Version string `json:"version"`
+	// LastFetch is the time of the last successful fetch or change notification.
+	LastFetch time.Time `json:"last_fetch"`
+	// LastChange is the time the content last changed.
+	LastChange time.Time `json:"last_change"`
+	// Age is the time since the content last changed.
+	Age time.Duration `json:"age"`
+	// MaxAge is the staleness threshold of the file; zero means no alarm.
+	MaxAge time.Duration `json:"max_age,omitempty"`
+	// Stale reports whether Age exceeds MaxAge.
+	Stale bool `json:"stale"`
+}
+
+// configFreshnessEntry is the tracked state of a single config file.
+type configFreshnessEntry struct {
+	fileName   string
+	group      string
+	version    string
+	lastFetch  time.Time
+	lastChange time.Time
+	stale      bool
+}
+
+func configFreshnessKey(fileName, group string) string {
+	return group + "/" + fileName
+}
+
+// contentVersion returns the MD5 digest of content.
+func contentVersion(content string) string {
+	sum := md5.Sum([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// recordConfigFetch records a successful read of a config file.
+func (p *PlugPolaris) recordConfigFetch(fileName, group, content string) {
+	now := time.Now()
+	version := contentVersion(content)
+
+	p.freshnessMutex.Lock()
+	if p.configFreshness == nil {
+		p.configFreshness = make(map[string]*configFreshnessEntry)
+	}
+	key := configFreshnessKey(fileName, group)
+	entry, ok := p.configFreshness[key]
+	if !ok {
+		entry = &configFreshnessEntry{fileName: fileName, group: group}
+		p.configFreshness[key] = entry
+	}
+	entry.lastFetch = now
+	if entry.version != version {
+		entry.version = version
+		entry.lastChange = now
+	}
+	p.freshnessMutex.Unlock()
+
+	p.evaluateConfigFreshness(now)
+}
+
+// SetConfigStalenessThreshold sets the staleness alarm of a config file, overriding the
+// config_staleness entry for the same file. A zero maxAge removes the override.
+func (p *PlugPolaris) SetConfigStalenessThreshold(fileName, group string, maxAge time.Duration) error {
+	if fileName == "" {
+		return NewConfigError("config file name must not be empty")
+	}
+	if maxAge < 0 {
+		return NewConfigError("staleness threshold must not be negative")
+	}
+	p.freshnessMutex.Lock()
+	defer p.freshnessMutex.Unlock()
+	key := configFreshnessKey(fileName, group)
+	if maxAge == 0 {
+		delete(p.stalenessThresholds, key)
+		return nil
+	}
+	if p.stalenessThresholds == nil {
+		p.stalenessThresholds = make(map[string]time.Duration)
+	}
+	p.stalenessThresholds[key] = maxAge
+	return nil
+}
+
+// stalenessThresholdsSnapshot returns the effective per-file thresholds, with programmatic
+// values taking precedence over config.
+func (p *PlugPolaris) stalenessThresholdsSnapshot() map[string]time.Duration {
+	p.mu.RLock()
+	cfg := p.currentConf()
+	p.mu.RUnlock()
+
+	thresholds := make(map[string]time.Duration)
+	for _, s := range cfg.GetConfigStaleness() {
+		if s.GetFileName() == "" || s.GetMaxAge().AsDuration() <= 0 {
+			continue
+		}
+		thresholds[configFreshnessKey(s.GetFileName(), s.GetGroup())] = s.GetMaxAge().AsDuration()
+	}
+	p.freshnessMutex.Lock()
+	for key, maxAge := range p.stalenessThresholds {
+		thresholds[key] = maxAge
+	}
+	p.freshnessMutex.Unlock()
+	return thresholds
+}
+
+// evaluateConfigFreshness recomputes the age and staleness of every tracked config file,
+// updates the freshness metrics and logs files that became stale or recovered.
+func (p *PlugPolaris) evaluateConfigFreshness(now time.Time) []ConfigFreshness {
+	thresholds := p.stalenessThresholdsSnapshot()
+	p.mu.RLock()
+	metrics := p.metrics
+	p.mu.RUnlock()
+
+	p.freshnessMutex.Lock()
+	report := make([]ConfigFreshness, 0, len(p.configFreshness))
+	for key, entry := range p.configFreshness {
+		maxAge := thresholds[key]
+		age := now.Sub(entry.lastChange)
+		stale := maxAge > 0 && age > maxAge
+		if stale != entry.stale {
+			if stale {
+				log.Warnf("Config %s:%s is stale: content unchanged for %v (threshold %v)",
+					entry.fileName, entry.group, age.Truncate(time.Second), maxAge)
+			} else {
+				log.Infof("Config %s:%s is fresh again", entry.fileName, entry.group)
+			}
+			entry.stale = stale
+		}
+		report = append(report, ConfigFreshness{
+			FileName:   entry.fileName,
+			Group:      entry.group,
+			Version:    entry.version,
+			LastFetch:  entry.lastFetch,
+			LastChange: entry.lastChange,
+			Age:        age,
+			MaxAge:     maxAge,
+			Stale:      stale,
+		})
+	}
+	p.freshnessMutex.Unlock()
+
+	// The digest of a short secret can be brute-forced, so it is not reported
+	for i := range report {
+		if p.isSensitiveConfig(report[i].FileName) {
+			report[i].Version = redactedValue
+		}
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Group != report[j].Group {
+			return report[i].Group < report[j].Group
+		}
+		return report[i].FileName < report[j].FileName
+	})
+	if metrics != nil {
+		for _, f := range report {
+			metrics.SetConfigFreshness(f.FileName, f.Group, float64(f.LastFetch.Unix()), f.Age.Seconds(), f.Stale)
+		}
+	}
+	return report
+}
+
+// ConfigFreshness returns the freshness of every config file read through the plugin,
+// sorted by group and file name.
+func (p *PlugPolaris) ConfigFreshness() []ConfigFreshness {
+	return p.evaluateConfigFreshness(time.Now())
+}
+
+// GetHealth extends the base health report with the components of the last health check
+// and per-file config freshness. A healthy plugin with a degraded component or a stale
+// config file is reported as degraded.
+func (p *PlugPolaris) GetHealth() plugins.HealthReport {
+	report := p.BasePlugin.GetHealth()
+	health, checked := p.LastHealthReport()
+	freshness := p.ConfigFreshness()
+	if !checked && len(freshness) == 0 {
+		return report
+	}
+	if report.Details == nil {
+		report.Details = make(map[string]any)
+	}
+	if checked {
+		report.Details["components"] = health.Components
+		if report.Status == "healthy" && health.Status == HealthStatusDegraded {
+			for _, c := range health.Components {
+				if c.Status == HealthStatusDegraded {
+					report.Status = "degraded"
+					report.Message = c.Name + ": " + c.Message
+					break
+				}
+			}
+		}
+	}
+	if len(freshness) == 0 {
+		return report
+	}
+	report.Details["config_freshness"] = freshness
+	if report.Status != "healthy" {
+		return report
+	}
+	for _, f := range freshness {
+		if f.Stale {
+			report.Status = "degraded"
+			report.Message = "config " + f.Group + ":" + f.FileName + " is stale"
+			break
+		}
+	}
+	return report
+}
@@ -0,0 +1,76 @@
+package polaris
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestRecordConfigFetch_TracksVersionAndChange(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.Empty(t, plugin.ConfigFreshness())
+
+	plugin.recordConfigFetch("app.yaml", "DEFAULT_GROUP", "a: 1")
+	first := plugin.ConfigFreshness()
+	require.Len(t, first, 1)
+	assert.Equal(t, contentVersion("a: 1"), first[0].Version)
+	assert.False(t, first[0].Stale)
+
+	// Same content: fetch time moves, change time does not.
+	time.Sleep(5 * time.Millisecond)
+	plugin.recordConfigFetch("app.yaml", "DEFAULT_GROUP", "a: 1")
+	second := plugin.ConfigFreshness()
+	assert.True(t, second[0].LastFetch.After(first[0].LastFetch))
+	assert.Equal(t, first[0].LastChange, second[0].LastChange)
+
+	plugin.recordConfigFetch("app.yaml", "DEFAULT_GROUP", "a: 2")
+	third := plugin.ConfigFreshness()
+	assert.NotEqual(t, first[0].Version, third[0].Version)
+	assert.True(t, third[0].LastChange.After(first[0].LastChange))
+}
+
+func TestEvaluateConfigFreshness_Staleness(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{
+		ConfigStaleness: []*conf.ConfigStaleness{
+			{FileName: "flags.yaml", Group: "ops", MaxAge: durationpb.New(time.Minute)},
+		},
+	})
+	plugin.metrics = NewPolarisMetrics()
+	plugin.recordConfigFetch("flags.yaml", "ops", "x: 1")
+	plugin.recordConfigFetch("app.yaml", "ops", "y: 1")
+
+	report := plugin.evaluateConfigFreshness(time.Now().Add(2 * time.Minute))
+	require.Len(t, report, 2)
+	assert.Equal(t, "app.yaml", report[0].FileName)
+	assert.False(t, report[0].Stale)
+	assert.Equal(t, "flags.yaml", report[1].FileName)
+	assert.True(t, report[1].Stale)
+
+	// A programmatic threshold overrides config.
+	require.NoError(t, plugin.SetConfigStalenessThreshold("flags.yaml", "ops", time.Hour))
+	report = plugin.evaluateConfigFreshness(time.Now().Add(2 * time.Minute))
+	assert.False(t, report[1].Stale)
+
+	assert.Error(t, plugin.SetConfigStalenessThreshold("", "ops", time.Minute))
+	assert.Error(t, plugin.SetConfigStalenessThreshold("flags.yaml", "ops", -time.Minute))
+}
+
+func TestValidator_ConfigStaleness(t *testing.T) {
+	cfg := &conf.Polaris{
+		Namespace: "default",
+		Weight:    100,
+		Ttl:       30,
+		ConfigStaleness: []*conf.ConfigStaleness{
+			{Group: "ops", MaxAge: durationpb.New(time.Minute)},
+			{FileName: "flags.yaml"},
+		},
+	}
+	result := NewValidator(cfg).Validate()
+	assert.False(t, result.IsValid)
+	assert.Len(t, result.Errors, 2)
+}
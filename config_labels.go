@@ -0,0 +1,40 @@
+package polaris
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/go-lynx/lynx/log"
+)
+
+// ConfigLabels returns the client labels matched by config gray-release rules:
+// config_labels, overridden by the labels set with SetConfigLabels.
+//
+// polaris-go v1.3.0 sends no client labels with config requests, so Polaris cannot yet
+// apply gray-release rules to these labels. They are kept for that purpose and for
+// application code that selects config variants itself.
+func (p *PlugPolaris) ConfigLabels() map[string]string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	labels := maps.Clone(p.currentConf().GetConfigLabels())
+	if labels == nil {
+		labels = make(map[string]string, len(p.configLabels))
+	}
+	maps.Copy(labels, p.configLabels)
+	return labels
+}
+
+// SetConfigLabels replaces the config labels set at runtime, e.g. canary=true. Keys
+// must not be empty. Passing nil keeps only the configured config_labels.
+func (p *PlugPolaris) SetConfigLabels(labels map[string]string) error {
+	for key := range labels {
+		if strings.TrimSpace(key) == "" {
+			return NewConfigError("config label keys must not be empty")
+		}
+	}
+	p.mu.Lock()
+	p.configLabels = maps.Clone(labels)
+	p.mu.Unlock()
+	log.Infof("Config labels set to %v (not sent to Polaris: the SDK config protocol has no client labels)", p.ConfigLabels())
+	return nil
+}
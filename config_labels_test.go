@@ -0,0 +1,32 @@
+package polaris
+
+import (
+	"testing"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigLabels(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", ConfigLabels: map[string]string{"env": "prod", "canary": "false"}})
+	assert.Equal(t, map[string]string{"env": "prod", "canary": "false"}, plugin.ConfigLabels())
+
+	require.NoError(t, plugin.SetConfigLabels(map[string]string{"canary": "true"}))
+	labels := plugin.ConfigLabels()
+	assert.Equal(t, map[string]string{"env": "prod", "canary": "true"}, labels)
+
+	labels["env"] = "test"
+	assert.Equal(t, "prod", plugin.ConfigLabels()["env"])
+	assert.Equal(t, "false", plugin.currentConf().ConfigLabels["canary"])
+
+	assert.True(t, IsConfigError(plugin.SetConfigLabels(map[string]string{" ": "x"})))
+	require.NoError(t, plugin.SetConfigLabels(nil))
+	assert.Equal(t, "false", plugin.ConfigLabels()["canary"])
+}
+
+func TestValidator_ConfigLabels(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, ConfigLabels: map[string]string{"": "x"}}
+	assert.False(t, NewValidator(cfg).Validate().IsValid)
+}
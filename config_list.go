@@ -0,0 +1,167 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-lynx/lynx/log"
+)
+
+// configListPageSize is the number of config files requested per page.
+const configListPageSize = 100
+
+// polarisTimeLayout is the timestamp layout of the Polaris OpenAPI, in server local time.
+const polarisTimeLayout = "2006-01-02 15:04:05"
+
+// ConfigFileInfo describes a config file and its current release.
+type ConfigFileInfo struct {
+	Name       string    `json:"name"`
+	Namespace  string    `json:"namespace"`
+	Group      string    `json:"group"`
+	Format     string    `json:"format"`
+	Released   bool      `json:"released"`
+	Version    uint64    `json:"version,omitempty"`
+	MD5        string    `json:"md5,omitempty"`
+	ReleasedAt time.Time `json:"released_at,omitzero"`
+	ModifiedAt time.Time `json:"modified_at,omitzero"`
+}
+
+// flexUint decodes unsigned integers that the OpenAPI encodes as numbers or strings.
+type flexUint uint64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *flexUint) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+	if s == "" || s == "null" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*f = flexUint(v)
+	return nil
+}
+
+// polarisConfigFile is a config file in OpenAPI listings.
+type polarisConfigFile struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Group       string `json:"group"`
+	Format      string `json:"format"`
+	ModifyTime  string `json:"modifyTime"`
+	ReleaseTime string `json:"releaseTime"`
+}
+
+// polarisConfigRelease is the current release of a config file in the OpenAPI.
+type polarisConfigRelease struct {
+	MD5        string   `json:"md5"`
+	Version    flexUint `json:"version"`
+	CreateTime string   `json:"createTime"`
+	ModifyTime string   `json:"modifyTime"`
+}
+
+// configFileListResponse is a page of config files.
+type configFileListResponse struct {
+	Total       flexUint            `json:"total"`
+	ConfigFiles []polarisConfigFile `json:"configFiles"`
+}
+
+// configReleaseResponse holds the current release of a config file.
+type configReleaseResponse struct {
+	ConfigFileRelease *polarisConfigRelease `json:"configFileRelease"`
+}
+
+// parsePolarisTime parses an OpenAPI timestamp, returning the zero time when it is
+// empty or malformed.
+func parsePolarisTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	if t, err := time.ParseInLocation(polarisTimeLayout, value, time.Local); err == nil {
+		return t
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// ListConfigFiles returns the config files of group in the plugin namespace, or of all
+// groups when group is empty, with the version, MD5 and time of their current release.
+// Files that were never released have Released set to false. The listing uses the
+// Polaris config OpenAPI with config_admin.address and the plugin token, since polaris-go
+// cannot enumerate config files.
+func (p *PlugPolaris) ListConfigFiles(group string) ([]ConfigFileInfo, error) {
+	admin, err := p.configAdmin("")
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+
+	var files []polarisConfigFile
+	for offset := 0; ; offset += configListPageSize {
+		query := url.Values{
+			"namespace": {admin.namespace},
+			"offset":    {strconv.Itoa(offset)},
+			"limit":     {strconv.Itoa(configListPageSize)},
+		}
+		if group != "" {
+			query.Set("group", group)
+		}
+		var page configFileListResponse
+		if _, err := admin.do(ctx, http.MethodGet, configAdminPath+"/search", query, nil, &page); err != nil {
+			return nil, WrapServiceError(err, ErrCodeServiceUnavailable, fmt.Sprintf("failed to list config files of group %q", group))
+		}
+		files = append(files, page.ConfigFiles...)
+		if len(page.ConfigFiles) < configListPageSize || uint64(len(files)) >= uint64(page.Total) {
+			break
+		}
+	}
+
+	infos := make([]ConfigFileInfo, 0, len(files))
+	for _, file := range files {
+		info := ConfigFileInfo{
+			Name:       file.Name,
+			Namespace:  file.Namespace,
+			Group:      file.Group,
+			Format:     file.Format,
+			ModifiedAt: parsePolarisTime(file.ModifyTime),
+		}
+		if info.Namespace == "" {
+			info.Namespace = admin.namespace
+		}
+		query := url.Values{"namespace": {info.Namespace}, "group": {info.Group}, "name": {info.Name}}
+		var release configReleaseResponse
+		code, err := admin.do(ctx, http.MethodGet, configAdminPath+"/release", query, nil, &release)
+		switch {
+		case code == polarisCodeNotFound:
+			// Never released
+		case err != nil:
+			return nil, WrapServiceError(err, ErrCodeServiceUnavailable, fmt.Sprintf("failed to get release of config %s:%s", info.Group, info.Name))
+		case release.ConfigFileRelease != nil:
+			info.Released = true
+			info.Version = uint64(release.ConfigFileRelease.Version)
+			info.MD5 = release.ConfigFileRelease.MD5
+			info.ReleasedAt = parsePolarisTime(release.ConfigFileRelease.ModifyTime)
+			if info.ReleasedAt.IsZero() {
+				info.ReleasedAt = parsePolarisTime(release.ConfigFileRelease.CreateTime)
+			}
+		}
+		if info.ReleasedAt.IsZero() {
+			info.ReleasedAt = parsePolarisTime(file.ReleaseTime)
+		}
+		infos = append(infos, info)
+	}
+	log.Infof("Listed %d config files of group %q in namespace %s", len(infos), group, admin.namespace)
+	return infos, nil
+}
@@ -0,0 +1,95 @@
+package polaris
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// configListServer serves count config files of group "orders"; every other file is released.
+func configListServer(t *testing.T, count int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case configAdminPath + "/search":
+			assert.Equal(t, "secret-token", r.Header.Get("X-Polaris-Token"))
+			assert.Equal(t, "orders", query.Get("group"))
+			offset, _ := strconv.Atoi(query.Get("offset"))
+			limit, _ := strconv.Atoi(query.Get("limit"))
+			files := []map[string]string{}
+			for i := offset; i < count && i < offset+limit; i++ {
+				files = append(files, map[string]string{
+					"name": fmt.Sprintf("app-%03d.yaml", i), "namespace": "default", "group": "orders",
+					"format": "yaml", "modifyTime": "2026-10-01 08:00:00",
+				})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": polarisCodeSuccess, "total": count, "configFiles": files})
+		case configAdminPath + "/release":
+			var i int
+			_, _ = fmt.Sscanf(query.Get("name"), "app-%03d.yaml", &i)
+			if i%2 == 1 {
+				_ = json.NewEncoder(w).Encode(map[string]any{"code": polarisCodeNotFound, "info": "not found resource"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": polarisCodeSuccess, "configFileRelease": map[string]any{
+				"md5": fmt.Sprintf("md5-%d", i), "version": strconv.Itoa(i + 1), "modifyTime": "2026-10-02 09:30:00",
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestListConfigFiles(t *testing.T) {
+	server := configListServer(t, configListPageSize+3)
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", Token: "secret-token", ConfigAdmin: &conf.ConfigAdmin{Address: server.URL}})
+	atomic.StoreInt32(&plugin.initialized, 1)
+
+	files, err := plugin.ListConfigFiles("orders")
+	require.NoError(t, err)
+	require.Len(t, files, configListPageSize+3)
+
+	assert.Equal(t, ConfigFileInfo{
+		Name:       "app-000.yaml",
+		Namespace:  "default",
+		Group:      "orders",
+		Format:     "yaml",
+		Released:   true,
+		Version:    1,
+		MD5:        "md5-0",
+		ReleasedAt: time.Date(2026, 10, 2, 9, 30, 0, 0, time.Local),
+		ModifiedAt: time.Date(2026, 10, 1, 8, 0, 0, 0, time.Local),
+	}, files[0])
+	assert.False(t, files[1].Released)
+	assert.Zero(t, files[1].Version)
+	assert.Equal(t, "app-102.yaml", files[102].Name)
+}
+
+func TestListConfigFiles_RequiresConfigAdmin(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", Token: "secret-token"})
+	atomic.StoreInt32(&plugin.initialized, 1)
+	_, err := plugin.ListConfigFiles("orders")
+	assert.True(t, IsConfigError(err))
+}
+
+func TestFlexUint(t *testing.T) {
+	var v struct{ A, B, C flexUint }
+	require.NoError(t, json.Unmarshal([]byte(`{"A": 3, "B": "4", "C": null}`), &v))
+	assert.Equal(t, flexUint(3), v.A)
+	assert.Equal(t, flexUint(4), v.B)
+	assert.Zero(t, v.C)
+}
@@ -1,8 +1,11 @@
 package polaris
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
+	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -45,3 +48,13 @@ func TestConfigOperationsWithMetrics(t *testing.T) {
 	assert.Error(t, err)
 	assert.IsType(t, &PolarisError{}, err)
 }
+
+func TestErrorCodeLabel(t *testing.T) {
+	sdkErr := model.NewSDKError(model.ErrCodeNetworkError, nil, "connection refused")
+	assert.Equal(t, "ErrCodeNetworkError", errorCodeLabel(sdkErr))
+	assert.Equal(t, "ErrCodeNetworkError", errorCodeLabel(WrapServiceError(sdkErr, ErrCodeServiceUnavailable, "discovery failed")))
+	assert.Equal(t, "SERVICE_UNAVAILABLE", errorCodeLabel(NewServiceError(ErrCodeServiceUnavailable, "discovery failed")))
+	assert.Equal(t, "canceled", errorCodeLabel(fmt.Errorf("operation cancelled: %w", context.Canceled)))
+	assert.Equal(t, "deadline_exceeded", errorCodeLabel(context.DeadlineExceeded))
+	assert.Equal(t, "unknown", errorCodeLabel(assert.AnError))
+}
@@ -0,0 +1,301 @@
+package polaris
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/protobuf/proto"
+)
+
+// Polaris OpenAPI response codes handled by the config admin API.
+const (
+	polarisCodeSuccess       = 200000
+	polarisCodeDataNoChange  = 200001
+	polarisCodeExistResource = 400201
+	polarisCodeNotFound      = 400202
+)
+
+// configAdminPath is the config file resource of the Polaris OpenAPI.
+const configAdminPath = "/config/v1/configfiles"
+
+// configFileRequest is a config file in the Polaris OpenAPI.
+type configFileRequest struct {
+	Namespace string `json:"namespace"`
+	Group     string `json:"group"`
+	Name      string `json:"name"`
+	Content   string `json:"content,omitempty"`
+	Format    string `json:"format,omitempty"`
+}
+
+// configReleaseRequest releases a config file in the Polaris OpenAPI.
+type configReleaseRequest struct {
+	Namespace string `json:"namespace"`
+	Group     string `json:"group"`
+	FileName  string `json:"fileName"`
+	Name      string `json:"name"`
+}
+
+// polarisResponse is the envelope of Polaris OpenAPI responses.
+type polarisResponse struct {
+	Code int    `json:"code"`
+	Info string `json:"info"`
+}
+
+// configAdmin manages config files through the Polaris HTTP OpenAPI. polaris-go only
+// reads single config files, so writes and listings go to the server directly.
+type configAdmin struct {
+	baseURL   string
+	token     string
+	namespace string
+	client    *http.Client
+	// dryRun logs the requests other than GET instead of sending them
+	dryRun bool
+}
+
+// configAdmin returns the config admin client authenticated with the token of operation, or
+// with the plugin token for reads when operation is empty. It fails when
+// config_admin.address is not configured, or when the config subsystem is disabled.
+func (p *PlugPolaris) configAdmin(operation TokenOperation) (*configAdmin, error) {
+	if err := p.checkInitialized(); err != nil {
+		return nil, err
+	}
+	if err := p.checkSubsystem(SubsystemConfig); err != nil {
+		return nil, err
+	}
+	return p.openAPIClient(operation, "manage config files")
+}
+
+// openAPIClient returns a client of the Polaris OpenAPI at config_admin.address,
+// authenticated with the token of operation. Without a token, requests are sent
+// unauthenticated, for servers with auth disabled. purpose completes the error returned
+// when the address is not configured.
+func (p *PlugPolaris) openAPIClient(operation TokenOperation, purpose string) (*configAdmin, error) {
+	cfg := p.currentConf()
+	if cfg.GetConfigAdmin().GetAddress() == "" {
+		return nil, NewConfigError("config_admin.address is required to " + purpose)
+	}
+	client, err := p.openAPIHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newOpenAPIClient(cfg, p.operationToken(operation), client), nil
+}
+
+// openAPIHTTPClient returns the HTTP client of the Polaris OpenAPI, shared by the OpenAPI
+// clients of the plugin so that their connections are reused. It is replaced when the
+// timeout or the TLS settings of cfg change.
+func (p *PlugPolaris) openAPIHTTPClient(cfg *conf.Polaris) (*http.Client, error) {
+	p.openAPIMutex.Lock()
+	defer p.openAPIMutex.Unlock()
+	if p.openAPIHTTP != nil && p.openAPIHTTP.Timeout == openAPITimeout(cfg) && proto.Equal(p.openAPITLS, cfg.GetTls()) {
+		return p.openAPIHTTP, nil
+	}
+	client, err := newOpenAPIHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if p.openAPIHTTP != nil {
+		p.openAPIHTTP.CloseIdleConnections()
+	}
+	p.openAPIHTTP = client
+	p.openAPITLS = proto.CloneOf(cfg.GetTls())
+	return client, nil
+}
+
+// closeOpenAPIHTTPClient closes the idle connections of the OpenAPI HTTP client.
+func (p *PlugPolaris) closeOpenAPIHTTPClient() {
+	p.openAPIMutex.Lock()
+	defer p.openAPIMutex.Unlock()
+	if p.openAPIHTTP != nil {
+		p.openAPIHTTP.CloseIdleConnections()
+		p.openAPIHTTP = nil
+		p.openAPITLS = nil
+	}
+}
+
+// openAPITimeout returns the timeout of the Polaris OpenAPI calls: config_admin.timeout,
+// then the plugin timeout.
+func openAPITimeout(cfg *conf.Polaris) time.Duration {
+	timeout := time.Duration(conf.DefaultTimeoutSeconds) * time.Second
+	if cfg.GetTimeout() != nil && cfg.GetTimeout().AsDuration() > 0 {
+		timeout = cfg.GetTimeout().AsDuration()
+	}
+	if admin := cfg.GetConfigAdmin(); admin.GetTimeout() != nil && admin.GetTimeout().AsDuration() > 0 {
+		timeout = admin.GetTimeout().AsDuration()
+	}
+	return timeout
+}
+
+// newOpenAPIHTTPClient returns an HTTP client for the Polaris OpenAPI with the timeout and
+// the TLS settings of cfg.
+func newOpenAPIHTTPClient(cfg *conf.Polaris) (*http.Client, error) {
+	transport, err := tlsHTTPTransport(cfg.GetTls())
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Timeout: openAPITimeout(cfg), Transport: transport}, nil
+}
+
+// newOpenAPIClient returns a client of the Polaris OpenAPI at config_admin.address of cfg,
+// authenticated with token when it is set, sending its requests through client.
+func newOpenAPIClient(cfg *conf.Polaris, token string, client *http.Client) *configAdmin {
+	return &configAdmin{
+		baseURL:   strings.TrimSuffix(cfg.GetConfigAdmin().GetAddress(), "/"),
+		token:     token,
+		namespace: cfg.GetNamespace(),
+		client:    client,
+		dryRun:    cfg.GetDryRun(),
+	}
+}
+
+// do sends a request to the Polaris OpenAPI and returns the response code. On success,
+// the response is also decoded into out when it is not nil. In dry-run mode, requests
+// other than GET are logged and reported as successful without being sent.
+func (a *configAdmin) do(ctx context.Context, method, resource string, query url.Values, body, out any) (int, error) {
+	if a.dryRun && method != http.MethodGet {
+		log.Infof("[dry-run] Not sending %s %s %s", method, resource, query.Encode())
+		return polarisCodeSuccess, nil
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	target := a.baseURL + resource
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.token != "" {
+		req.Header.Set("X-Polaris-Token", a.token)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, err
+	}
+	var result polarisResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return 0, fmt.Errorf("unexpected response (HTTP %d): %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	switch result.Code {
+	case polarisCodeSuccess, polarisCodeDataNoChange:
+		if out != nil {
+			if err := json.Unmarshal(data, out); err != nil {
+				return result.Code, fmt.Errorf("failed to decode response: %w", err)
+			}
+		}
+		return result.Code, nil
+	}
+	return result.Code, newServerResponseError(result.Code, result.Info)
+}
+
+// configFileFormat returns the Polaris format of a config file from its extension.
+func configFileFormat(fileName string) string {
+	switch ext := strings.ToLower(strings.TrimPrefix(path.Ext(fileName), ".")); ext {
+	case "yml":
+		return "yaml"
+	case "json", "yaml", "xml", "properties", "html", "toml":
+		return ext
+	}
+	return "text"
+}
+
+// writeConfigError wraps a config write failure.
+func writeConfigError(err error, operation, fileName, group string) error {
+	return WrapServiceError(err, ErrCodeServiceUnavailable, fmt.Sprintf("failed to %s config %s:%s", operation, group, fileName))
+}
+
+// UpdateConfig creates the config file or replaces its content without releasing it.
+// Consumers keep the released content until ReleaseConfig is called.
+func (p *PlugPolaris) UpdateConfig(ctx context.Context, fileName, group, content string) error {
+	if fileName == "" || group == "" {
+		return NewConfigError("config file name and group must not be empty")
+	}
+	admin, err := p.configAdmin(TokenOperationConfigWrite)
+	if err != nil {
+		return err
+	}
+	file := configFileRequest{Namespace: admin.namespace, Group: group, Name: fileName, Content: content, Format: configFileFormat(fileName)}
+	code, err := admin.do(ctx, http.MethodPost, configAdminPath, nil, file, nil)
+	if code == polarisCodeExistResource {
+		_, err = admin.do(ctx, http.MethodPut, configAdminPath, nil, file, nil)
+	}
+	if err != nil {
+		return writeConfigError(err, "update", fileName, group)
+	}
+	log.Infof("Updated config %s:%s", group, fileName)
+	p.recordApplicationAudit(AuditEvent{Type: AuditConfigUpdated, FileName: fileName, Group: group, ContentLength: len(content)})
+	return nil
+}
+
+// ReleaseConfig releases the current content of the config file to consumers.
+func (p *PlugPolaris) ReleaseConfig(ctx context.Context, fileName, group string) error {
+	if fileName == "" || group == "" {
+		return NewConfigError("config file name and group must not be empty")
+	}
+	admin, err := p.configAdmin(TokenOperationConfigWrite)
+	if err != nil {
+		return err
+	}
+	release := configReleaseRequest{
+		Namespace: admin.namespace,
+		Group:     group,
+		FileName:  fileName,
+		Name:      fmt.Sprintf("%s-%d", fileName, time.Now().UnixMilli()),
+	}
+	if _, err := admin.do(ctx, http.MethodPost, configAdminPath+"/release", nil, release, nil); err != nil {
+		return writeConfigError(err, "release", fileName, group)
+	}
+	log.Infof("Released config %s:%s", group, fileName)
+	p.recordApplicationAudit(AuditEvent{Type: AuditConfigReleased, FileName: fileName, Group: group})
+	return nil
+}
+
+// PublishConfig creates or updates the config file and releases it.
+func (p *PlugPolaris) PublishConfig(ctx context.Context, fileName, group, content string) error {
+	if err := p.UpdateConfig(ctx, fileName, group, content); err != nil {
+		return err
+	}
+	return p.ReleaseConfig(ctx, fileName, group)
+}
+
+// DeleteConfig deletes the config file and its releases.
+func (p *PlugPolaris) DeleteConfig(ctx context.Context, fileName, group string) error {
+	if fileName == "" || group == "" {
+		return NewConfigError("config file name and group must not be empty")
+	}
+	admin, err := p.configAdmin(TokenOperationConfigWrite)
+	if err != nil {
+		return err
+	}
+	query := url.Values{"namespace": {admin.namespace}, "group": {group}, "name": {fileName}}
+	if _, err := admin.do(ctx, http.MethodDelete, configAdminPath, query, nil, nil); err != nil {
+		return writeConfigError(err, "delete", fileName, group)
+	}
+	log.Infof("Deleted config %s:%s", group, fileName)
+	p.recordApplicationAudit(AuditEvent{Type: AuditConfigDeleted, FileName: fileName, Group: group})
+	return nil
+}
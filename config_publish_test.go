@@ -0,0 +1,155 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// fakeConfigServer is a minimal Polaris config OpenAPI that stores config files. Requests
+// must carry token, unless it is empty as on servers with auth disabled.
+type fakeConfigServer struct {
+	token    string
+	mu       sync.Mutex
+	files    map[string]string
+	released map[string]string
+	requests []string
+}
+
+func (s *fakeConfigServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	reply := func(code int, info string) {
+		_ = json.NewEncoder(w).Encode(polarisResponse{Code: code, Info: info})
+	}
+	if r.Header.Get("X-Polaris-Token") != s.token {
+		w.WriteHeader(http.StatusUnauthorized)
+		reply(401000, "access is not approved")
+		return
+	}
+	switch {
+	case r.URL.Path == configAdminPath && r.Method == http.MethodDelete:
+		key := r.URL.Query().Get("group") + "/" + r.URL.Query().Get("name")
+		delete(s.files, key)
+		delete(s.released, key)
+		reply(polarisCodeSuccess, "ok")
+	case r.URL.Path == configAdminPath:
+		var file configFileRequest
+		_ = json.NewDecoder(r.Body).Decode(&file)
+		key := file.Group + "/" + file.Name
+		if _, ok := s.files[key]; ok && r.Method == http.MethodPost {
+			reply(polarisCodeExistResource, "existed resource")
+			return
+		}
+		s.files[key] = file.Content
+		reply(polarisCodeSuccess, "ok")
+	case r.URL.Path == configAdminPath+"/release":
+		var release configReleaseRequest
+		_ = json.NewDecoder(r.Body).Decode(&release)
+		key := release.Group + "/" + release.FileName
+		content, ok := s.files[key]
+		if !ok {
+			reply(400202, fmt.Sprintf("config %s not found", key))
+			return
+		}
+		s.released[key] = content
+		reply(polarisCodeSuccess, "ok")
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newConfigAdminPlugin(t *testing.T, token string) (*PlugPolaris, *fakeConfigServer) {
+	t.Helper()
+	fake := &fakeConfigServer{token: "secret-token", files: map[string]string{}, released: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", Token: token, ConfigAdmin: &conf.ConfigAdmin{Address: server.URL + "/"}})
+	atomic.StoreInt32(&plugin.initialized, 1)
+	return plugin, fake
+}
+
+func TestPublishConfig(t *testing.T) {
+	plugin, fake := newConfigAdminPlugin(t, "secret-token")
+
+	require.NoError(t, plugin.PublishConfig(context.Background(), "app.yaml", "orders", "workers: 4"))
+	assert.Equal(t, "workers: 4", fake.released["orders/app.yaml"])
+
+	// Updating an existing file falls back to PUT and leaves the release untouched.
+	require.NoError(t, plugin.UpdateConfig(context.Background(), "app.yaml", "orders", "workers: 8"))
+	assert.Equal(t, "workers: 8", fake.files["orders/app.yaml"])
+	assert.Equal(t, "workers: 4", fake.released["orders/app.yaml"])
+	require.NoError(t, plugin.ReleaseConfig(context.Background(), "app.yaml", "orders"))
+	assert.Equal(t, "workers: 8", fake.released["orders/app.yaml"])
+
+	require.NoError(t, plugin.DeleteConfig(context.Background(), "app.yaml", "orders"))
+	assert.Empty(t, fake.files)
+
+	err := plugin.ReleaseConfig(context.Background(), "app.yaml", "orders")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400202")
+}
+
+func TestPublishConfig_TokenAndAddress(t *testing.T) {
+	plugin, fake := newConfigAdminPlugin(t, "")
+	assert.True(t, IsAuthError(plugin.PublishConfig(context.Background(), "app.yaml", "orders", "x")))
+
+	// Servers with auth disabled accept requests without a token
+	fake.token = ""
+	require.NoError(t, plugin.PublishConfig(context.Background(), "app.yaml", "orders", "x"))
+	assert.Equal(t, "x", fake.released["orders/app.yaml"])
+
+	plugin, _ = newConfigAdminPlugin(t, "wrong-token")
+	assert.Error(t, plugin.PublishConfig(context.Background(), "app.yaml", "orders", "x"))
+
+	plugin.currentConf().ConfigAdmin = nil
+	assert.True(t, IsConfigError(plugin.DeleteConfig(context.Background(), "app.yaml", "orders")))
+	assert.True(t, IsConfigError(plugin.UpdateConfig(context.Background(), "", "orders", "x")))
+}
+
+func TestPublishConfig_Context(t *testing.T) {
+	plugin, fake := newConfigAdminPlugin(t, "secret-token")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := plugin.PublishConfig(ctx, "app.yaml", "orders", "workers: 4")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, fake.requests)
+}
+
+func TestOpenAPIHTTPClient_Reused(t *testing.T) {
+	plugin, _ := newConfigAdminPlugin(t, "secret-token")
+	first, err := plugin.openAPIClient(TokenOperationConfigWrite, "test")
+	require.NoError(t, err)
+	second, err := plugin.openAPIClient("", "test")
+	require.NoError(t, err)
+	assert.Same(t, first.client, second.client)
+
+	// A new timeout replaces the client
+	cfg := plugin.GetPolarisConfig()
+	cfg.ConfigAdmin.Timeout = durationpb.New(time.Minute)
+	plugin.setConf(cfg)
+	third, err := plugin.openAPIClient("", "test")
+	require.NoError(t, err)
+	assert.NotSame(t, first.client, third.client)
+	assert.Equal(t, time.Minute, third.client.Timeout)
+}
+
+func TestConfigFileFormat(t *testing.T) {
+	assert.Equal(t, "yaml", configFileFormat("app.yml"))
+	assert.Equal(t, "json", configFileFormat("app.JSON"))
+	assert.Equal(t, "text", configFileFormat("app"))
+}
@@ -0,0 +1,182 @@
+package polaris
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// configSnapshotConfig returns the config snapshot settings.
+func (p *PlugPolaris) configSnapshotConfig() *conf.ConfigSnapshot {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.currentConf().GetConfigSnapshot()
+}
+
+// snapshotPathSegment escapes a namespace, group or file name into a single path element.
+// Escaping removes separators, dot-only names are escaped so they cannot leave the
+// directory, and an empty name becomes "%", which escaping never produces.
+func snapshotPathSegment(name string) string {
+	escaped := url.PathEscape(name)
+	if escaped == "" {
+		return "%"
+	}
+	if strings.Trim(escaped, ".") == "" {
+		return strings.ReplaceAll(escaped, ".", "%2E")
+	}
+	return escaped
+}
+
+// configSnapshotPath returns the snapshot file of a config file under dir.
+func configSnapshotPath(dir, namespace, group, fileName string) string {
+	return filepath.Join(dir, snapshotPathSegment(namespace), snapshotPathSegment(group), snapshotPathSegment(fileName))
+}
+
+// writeSnapshotFile replaces path with content through a temporary file, so readers
+// never see a partial snapshot. An unchanged snapshot only has its time refreshed.
+func writeSnapshotFile(path string, content []byte) error {
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+		now := time.Now()
+		return os.Chtimes(path, now, now)
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// saveConfigSnapshot persists the content of a config file when snapshots are enabled.
+// Failures are logged, since the snapshot only matters once Polaris is unreachable.
+func (p *PlugPolaris) saveConfigSnapshot(namespace, group, fileName, content string) {
+	dir := p.configSnapshotConfig().GetDir()
+	if dir == "" {
+		return
+	}
+	path := configSnapshotPath(dir, namespace, group, fileName)
+	if err := writeSnapshotFile(path, []byte(content)); err != nil {
+		log.Warnf("Failed to save config snapshot %s:%s to %s: %v", fileName, group, path, err)
+	}
+}
+
+// loadConfigSnapshot returns the persisted content of a config file. Snapshots older
+// than max_age are rejected.
+func (p *PlugPolaris) loadConfigSnapshot(namespace, group, fileName string) (string, error) {
+	cfg := p.configSnapshotConfig()
+	if cfg.GetDir() == "" {
+		return "", fmt.Errorf("config snapshots are disabled")
+	}
+	path := configSnapshotPath(cfg.GetDir(), namespace, group, fileName)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if maxAge := cfg.GetMaxAge().AsDuration(); maxAge > 0 {
+		if age := time.Since(info.ModTime()); age > maxAge {
+			return "", fmt.Errorf("config snapshot %s is %v old, max_age is %v", path, age.Truncate(time.Second), maxAge)
+		}
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	p.mu.RLock()
+	metrics := p.metrics
+	p.mu.RUnlock()
+	if metrics != nil {
+		metrics.RecordConfigOperation("snapshot", fileName, group, "loaded")
+	}
+	log.Warnf("Using local snapshot of config %s:%s from %s (saved %s)",
+		fileName, group, path, info.ModTime().Format(time.RFC3339))
+	return string(content), nil
+}
+
+// snapshotConfigSource persists what its source loads and falls back to the snapshots
+// when loading fails.
+type snapshotConfigSource struct {
+	config.Source
+	plugin    *PlugPolaris
+	namespace string
+	group     string
+	fileName  string
+}
+
+// withConfigSnapshot wraps source with snapshot persistence when snapshots are enabled.
+func (p *PlugPolaris) withConfigSnapshot(source config.Source, namespace, group, fileName string) config.Source {
+	if source == nil || p.configSnapshotConfig().GetDir() == "" {
+		return source
+	}
+	return &snapshotConfigSource{Source: source, plugin: p, namespace: namespace, group: group, fileName: fileName}
+}
+
+// Load loads the config from Polaris, or from the snapshot when Polaris fails.
+func (s *snapshotConfigSource) Load() ([]*config.KeyValue, error) {
+	kvs, err := s.Source.Load()
+	if err == nil {
+		s.save(kvs)
+		return kvs, nil
+	}
+	content, snapshotErr := s.plugin.loadConfigSnapshot(s.namespace, s.group, s.fileName)
+	if snapshotErr != nil {
+		return nil, err
+	}
+	log.Warnf("Failed to load config %s:%s from Polaris, using snapshot: %v", s.fileName, s.group, err)
+	return []*config.KeyValue{{
+		Key:    s.fileName,
+		Value:  []byte(content),
+		Format: strings.TrimPrefix(filepath.Ext(s.fileName), "."),
+	}}, nil
+}
+
+// Watch returns a watcher that persists every change.
+func (s *snapshotConfigSource) Watch() (config.Watcher, error) {
+	watcher, err := s.Source.Watch()
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotConfigWatcher{Watcher: watcher, source: s}, nil
+}
+
+// save persists the loaded content of the source's file.
+func (s *snapshotConfigSource) save(kvs []*config.KeyValue) {
+	for _, kv := range kvs {
+		if kv != nil && kv.Key == s.fileName {
+			s.plugin.saveConfigSnapshot(s.namespace, s.group, s.fileName, string(kv.Value))
+		}
+	}
+}
+
+// snapshotConfigWatcher persists the changes reported by a watcher.
+type snapshotConfigWatcher struct {
+	config.Watcher
+	source *snapshotConfigSource
+}
+
+// Next returns the next change and saves it.
+func (w *snapshotConfigWatcher) Next() ([]*config.KeyValue, error) {
+	kvs, err := w.Watcher.Next()
+	if err == nil {
+		w.source.save(kvs)
+	}
+	return kvs, err
+}
@@ -0,0 +1,103 @@
+package polaris
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// stubConfigSource returns fixed key-values or an error.
+type stubConfigSource struct {
+	kvs []*config.KeyValue
+	err error
+}
+
+func (s *stubConfigSource) Load() ([]*config.KeyValue, error) { return s.kvs, s.err }
+func (s *stubConfigSource) Watch() (config.Watcher, error) {
+	return &stubConfigWatcher{kvs: s.kvs}, nil
+}
+
+type stubConfigWatcher struct{ kvs []*config.KeyValue }
+
+func (w *stubConfigWatcher) Next() ([]*config.KeyValue, error) { return w.kvs, nil }
+func (w *stubConfigWatcher) Stop() error                       { return nil }
+
+func newSnapshotPlugin(t *testing.T, maxAge time.Duration) (*PlugPolaris, string) {
+	t.Helper()
+	dir := t.TempDir()
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", ConfigSnapshot: &conf.ConfigSnapshot{Dir: dir, MaxAge: durationpb.New(maxAge)}})
+	return plugin, dir
+}
+
+func TestSnapshotPathSegment(t *testing.T) {
+	assert.Equal(t, "a%2Fb.yaml", snapshotPathSegment("a/b.yaml"))
+	assert.Equal(t, "%2E%2E", snapshotPathSegment(".."))
+	assert.Equal(t, "%", snapshotPathSegment(""))
+	assert.Equal(t, filepath.Join("/data", "default", "%2E", "app.yaml"), configSnapshotPath("/data", "default", ".", "app.yaml"))
+}
+
+func TestConfigSnapshot_SaveAndLoad(t *testing.T) {
+	plugin, dir := newSnapshotPlugin(t, time.Hour)
+
+	plugin.saveConfigSnapshot("default", "orders", "app.yaml", "workers: 4\n")
+	content, err := plugin.loadConfigSnapshot("default", "orders", "app.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "workers: 4\n", content)
+
+	plugin.saveConfigSnapshot("default", "orders", "app.yaml", "workers: 8\n")
+	content, err = plugin.loadConfigSnapshot("default", "orders", "app.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "workers: 8\n", content)
+
+	path := configSnapshotPath(dir, "default", "orders", "app.yaml")
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+	_, err = plugin.loadConfigSnapshot("default", "orders", "app.yaml")
+	assert.ErrorContains(t, err, "max_age")
+
+	_, err = plugin.loadConfigSnapshot("default", "orders", "missing.yaml")
+	assert.Error(t, err)
+}
+
+func TestSnapshotConfigSource_FallsBackWhenPolarisFails(t *testing.T) {
+	plugin, _ := newSnapshotPlugin(t, 0)
+	inner := &stubConfigSource{kvs: []*config.KeyValue{{Key: "app.yaml", Value: []byte("workers: 4\n"), Format: "yaml"}}}
+	source := plugin.withConfigSnapshot(inner, "default", "orders", "app.yaml")
+
+	_, err := source.Load()
+	require.NoError(t, err)
+
+	// The stub watcher reports the source content at Watch time as the next change.
+	inner.kvs = []*config.KeyValue{{Key: "app.yaml", Value: []byte("workers: 8\n"), Format: "yaml"}}
+	watcher, err := source.Watch()
+	require.NoError(t, err)
+	_, err = watcher.Next()
+	require.NoError(t, err)
+
+	inner.err = errors.New("polaris unreachable")
+	kvs, err := source.Load()
+	require.NoError(t, err)
+	require.Len(t, kvs, 1)
+	assert.Equal(t, "workers: 8\n", string(kvs[0].Value))
+	assert.Equal(t, "yaml", kvs[0].Format)
+
+	other := plugin.withConfigSnapshot(&stubConfigSource{err: inner.err}, "default", "orders", "other.yaml")
+	_, err = other.Load()
+	assert.ErrorIs(t, err, inner.err)
+}
+
+func TestWithConfigSnapshot_DisabledReturnsSource(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	inner := &stubConfigSource{}
+	assert.Same(t, config.Source(inner), plugin.withConfigSnapshot(inner, "default", "orders", "app.yaml"))
+}
@@ -0,0 +1,217 @@
+package polaris
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// mergedConfigPollInterval is how often a merged config source checks its files for changes.
+const mergedConfigPollInterval = 10 * time.Second
+
+// mergedConfigFile is one file of a merged config source.
+type mergedConfigFile struct {
+	fileName  string
+	group     string
+	namespace string
+	priority  int32
+	strategy  string
+}
+
+// mergedConfigSource is a Kratos config.Source that loads several Polaris config files,
+// merges them in priority order and serves the result as a single JSON value.
+type mergedConfigSource struct {
+	ctx      context.Context
+	key      string
+	files    []mergedConfigFile
+	fetch    func(namespace, fileName, group string) (string, error)
+	interval time.Duration
+
+	mu   sync.Mutex
+	last []byte
+}
+
+// GetMergedConfigSource returns a config.Source that merges the main configuration file
+// with the additional_configs of service_config. Files are applied in ascending priority,
+// the main file first, each with its merge_strategy. Its watcher streams the merged
+// configuration to Kratos whenever one of the files changes.
+func (p *PlugPolaris) GetMergedConfigSource() (config.Source, error) {
+	if err := p.checkInitialized(); err != nil {
+		return nil, err
+	}
+	p.mu.RLock()
+	files := append([]*conf.ConfigFile{p.mainConfigFile()}, p.currentConf().GetServiceConfig().GetAdditionalConfigs()...)
+	p.mu.RUnlock()
+	return p.NewMergedConfigSource(files...)
+}
+
+// NewMergedConfigSource returns a config.Source that merges the given config files.
+// Files are applied in ascending priority, keeping the given order for equal priorities.
+// A file without a namespace uses the service_config namespace, then the plugin namespace.
+func (p *PlugPolaris) NewMergedConfigSource(files ...*conf.ConfigFile) (config.Source, error) {
+	if err := p.checkInitialized(); err != nil {
+		return nil, err
+	}
+	p.mu.RLock()
+	namespace := p.currentConf().GetServiceConfig().GetNamespace()
+	if namespace == "" {
+		namespace = p.currentConf().GetNamespace()
+	}
+	p.mu.RUnlock()
+
+	source := &mergedConfigSource{
+		ctx:      p.watcherContext(),
+		fetch:    p.getConfigContent,
+		interval: mergedConfigPollInterval,
+	}
+	var names []string
+	for _, file := range files {
+		if file.GetFilename() == "" {
+			continue
+		}
+		entry := mergedConfigFile{
+			fileName:  file.GetFilename(),
+			group:     file.GetGroup(),
+			namespace: file.GetNamespace(),
+			priority:  file.GetPriority(),
+			strategy:  file.GetMergeStrategy(),
+		}
+		if entry.namespace == "" {
+			entry.namespace = namespace
+		}
+		if entry.strategy == "" {
+			entry.strategy = conf.MergeStrategyOverride
+		}
+		source.files = append(source.files, entry)
+		names = append(names, entry.group+"/"+entry.fileName)
+	}
+	if len(source.files) == 0 {
+		return nil, NewConfigError("at least one config file is required")
+	}
+	sort.SliceStable(source.files, func(i, j int) bool {
+		return source.files[i].priority < source.files[j].priority
+	})
+	source.key = "polaris:" + strings.Join(names, ",")
+	return source, nil
+}
+
+// Load fetches and merges all files.
+func (s *mergedConfigSource) Load() ([]*config.KeyValue, error) {
+	kv, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	s.swap(kv.Value)
+	return []*config.KeyValue{kv}, nil
+}
+
+// Watch returns a watcher that polls the files and reports merged changes.
+func (s *mergedConfigSource) Watch() (config.Watcher, error) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	return &mergedConfigWatcher{source: s, ctx: ctx, cancel: cancel}, nil
+}
+
+// load fetches every file and merges them into one JSON key-value.
+func (s *mergedConfigSource) load() (*config.KeyValue, error) {
+	merged := make(map[string]any)
+	for _, file := range s.files {
+		content, err := s.fetch(file.namespace, file.fileName, file.group)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config %s:%s: %w", file.group, file.fileName, err)
+		}
+		format := detectConfigFormat(file.fileName, content)
+		var values map[string]any
+		if err := decodeConfig(format, content, &values); err != nil {
+			return nil, NewPolarisError(ErrCodeConfigInvalid,
+				fmt.Sprintf("failed to parse %s config %s:%s", format, file.group, file.fileName)).WithCause(err)
+		}
+		log.Debugf("Merging config %s:%s (priority: %d, strategy: %s)", file.group, file.fileName, file.priority, file.strategy)
+		mergeConfigValues(merged, values, file.strategy)
+	}
+	value, err := json.Marshal(merged)
+	if err != nil {
+		return nil, NewPolarisError(ErrCodeConfigInvalid, "failed to encode merged config").WithCause(err)
+	}
+	return &config.KeyValue{Key: s.key, Value: value, Format: ConfigFormatJSON}, nil
+}
+
+// swap stores value as the last merged config and reports whether it changed.
+func (s *mergedConfigSource) swap(value []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if bytes.Equal(s.last, value) {
+		return false
+	}
+	s.last = value
+	return true
+}
+
+// mergeConfigValues merges src into dst. With the override strategy, top-level keys of
+// src replace those of dst. With merge, nested objects are merged and other values
+// replaced. With append, arrays are additionally concatenated.
+func mergeConfigValues(dst, src map[string]any, strategy string) {
+	for key, value := range src {
+		existing, ok := dst[key]
+		if !ok || strategy == conf.MergeStrategyOverride {
+			dst[key] = value
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			if existingMap, ok := existing.(map[string]any); ok {
+				mergeConfigValues(existingMap, v, strategy)
+				continue
+			}
+		case []any:
+			if existingList, ok := existing.([]any); ok && strategy == conf.MergeStrategyAppend {
+				dst[key] = append(existingList[:len(existingList):len(existingList)], v...)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
+
+// mergedConfigWatcher reports changes of a merged config source.
+type mergedConfigWatcher struct {
+	source *mergedConfigSource
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Next blocks until the merged configuration changes or the watcher is stopped.
+func (w *mergedConfigWatcher) Next() ([]*config.KeyValue, error) {
+	ticker := time.NewTicker(w.source.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case <-ticker.C:
+			kv, err := w.source.load()
+			if err != nil {
+				log.Warnf("Failed to reload merged config %s: %v", w.source.key, err)
+				continue
+			}
+			if w.source.swap(kv.Value) {
+				log.Infof("Merged config %s changed", w.source.key)
+				return []*config.KeyValue{kv}, nil
+			}
+		}
+	}
+}
+
+// Stop stops the watcher.
+func (w *mergedConfigWatcher) Stop() error {
+	w.cancel()
+	return nil
+}
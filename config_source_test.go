@@ -0,0 +1,130 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryConfigFiles serves config content keyed by namespace/group/file.
+type memoryConfigFiles struct {
+	mu    sync.Mutex
+	files map[string]string
+}
+
+func (m *memoryConfigFiles) set(key, content string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[key] = content
+}
+
+func (m *memoryConfigFiles) fetch(namespace, fileName, group string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	content, ok := m.files[namespace+"/"+group+"/"+fileName]
+	if !ok {
+		return "", NewServiceError(ErrCodeConfigNotFound, "configFile not found")
+	}
+	return content, nil
+}
+
+func newMergedSource(t *testing.T, files *memoryConfigFiles, configs ...*conf.ConfigFile) *mergedConfigSource {
+	t.Helper()
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", ServiceConfig: &conf.ServiceConfig{Namespace: "shared"}})
+	atomic.StoreInt32(&plugin.initialized, 1)
+	source, err := plugin.NewMergedConfigSource(configs...)
+	require.NoError(t, err)
+	merged := source.(*mergedConfigSource)
+	merged.fetch = files.fetch
+	merged.interval = 10 * time.Millisecond
+	return merged
+}
+
+func decodeMerged(t *testing.T, value []byte) map[string]any {
+	t.Helper()
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(value, &out))
+	return out
+}
+
+func TestMergeConfigValues(t *testing.T) {
+	base := func() map[string]any {
+		return map[string]any{"http": map[string]any{"addr": ":8080", "timeout": "5s"}, "tags": []any{"a"}}
+	}
+	update := map[string]any{"http": map[string]any{"timeout": "10s"}, "tags": []any{"b"}}
+
+	dst := base()
+	mergeConfigValues(dst, update, conf.MergeStrategyOverride)
+	assert.Equal(t, map[string]any{"http": map[string]any{"timeout": "10s"}, "tags": []any{"b"}}, dst)
+
+	dst = base()
+	mergeConfigValues(dst, update, conf.MergeStrategyMerge)
+	assert.Equal(t, map[string]any{"http": map[string]any{"addr": ":8080", "timeout": "10s"}, "tags": []any{"b"}}, dst)
+
+	dst = base()
+	mergeConfigValues(dst, update, conf.MergeStrategyAppend)
+	assert.Equal(t, map[string]any{"http": map[string]any{"addr": ":8080", "timeout": "10s"}, "tags": []any{"a", "b"}}, dst)
+}
+
+func TestMergedConfigSource_LoadsByPriority(t *testing.T) {
+	files := &memoryConfigFiles{files: map[string]string{
+		"shared/app/app.yaml":        "http:\n  addr: \":8080\"\n  timeout: 5s\n",
+		"shared/common/env.json":     `{"http": {"timeout": "10s"}, "feature": {"new_api": true}}`,
+		"default/common/shared.yaml": "http:\n  addr: \":9090\"\n",
+	}}
+	source := newMergedSource(t, files,
+		&conf.ConfigFile{Filename: "app.yaml", Group: "app"},
+		&conf.ConfigFile{Filename: "env.json", Group: "common", Priority: 20, MergeStrategy: conf.MergeStrategyMerge},
+		&conf.ConfigFile{Filename: "shared.yaml", Group: "common", Namespace: "default", Priority: 10, MergeStrategy: conf.MergeStrategyMerge},
+	)
+
+	kvs, err := source.Load()
+	require.NoError(t, err)
+	require.Len(t, kvs, 1)
+	assert.Equal(t, ConfigFormatJSON, kvs[0].Format)
+	assert.Equal(t, map[string]any{
+		"http":    map[string]any{"addr": ":9090", "timeout": "10s"},
+		"feature": map[string]any{"new_api": true},
+	}, decodeMerged(t, kvs[0].Value))
+
+	files.set("shared/common/env.json", `{"http": `)
+	_, err = source.Load()
+	assert.True(t, IsConfigError(err))
+}
+
+func TestMergedConfigSource_WatchStreamsChanges(t *testing.T) {
+	files := &memoryConfigFiles{files: map[string]string{"shared/app/app.yaml": "workers: 4\n"}}
+	source := newMergedSource(t, files, &conf.ConfigFile{Filename: "app.yaml", Group: "app"})
+	_, err := source.Load()
+	require.NoError(t, err)
+
+	watcher, err := source.Watch()
+	require.NoError(t, err)
+	files.set("shared/app/app.yaml", "workers: 8\n")
+	kvs, err := watcher.Next()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"workers": float64(8)}, decodeMerged(t, kvs[0].Value))
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = watcher.Stop()
+	}()
+	_, err = watcher.Next()
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestValidator_MergeStrategy(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, ServiceConfig: &conf.ServiceConfig{
+		AdditionalConfigs: []*conf.ConfigFile{{Filename: "a.yaml", MergeStrategy: "replace"}},
+	}}
+	result := NewValidator(cfg).Validate()
+	assert.False(t, result.IsValid)
+}
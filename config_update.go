@@ -0,0 +1,152 @@
+package polaris
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ConfigUpdate module
+// Responsibility: replacing the configuration of a running plugin and updating the
+// components built from the settings that changed.
+
+// immutableSettings are the settings that cannot change at runtime: those needed to reach
+// Polaris and those only read when the plugin is created. remote_config ignores them and
+// UpdatePolarisConfig rejects changes to them.
+var immutableSettings = []protoreflect.Name{
+	"namespace", "token", "token_source", "operation_tokens", "config_path", "server_bootstrap",
+	"tls", "standby", "remote_config", "metrics_backend", "audit", "subsystems", "lazy_init", "dry_run",
+}
+
+// resilienceSettings are the settings of the retry manager and circuit breaker, which are
+// rebuilt when one of them changes.
+var resilienceSettings = []protoreflect.Name{
+	"max_retry_times", "retry_interval", "retry_backoff", "retry_max_delay", "hedge_delay",
+	"circuit_breaker_threshold", "circuit_breaker_open_duration", "circuit_breaker_half_open_probes",
+	"circuit_breaker_window", "circuit_breaker_min_requests", "circuit_breaker_slow_call_threshold",
+}
+
+// UpdatePolarisConfig replaces the configuration of the plugin with newConf at runtime.
+// newConf is validated like the startup configuration, with defaults applied to unset
+// fields. Changes to settings that need a restart, such as namespace, token and the server
+// addresses, are rejected and nothing is applied. Other settings take effect immediately:
+// the retry manager and circuit breaker are rebuilt, alert webhooks re-registered, and the
+// weight and TTL of registered instances registered again. When remote_config is set, the
+// settings of its file are applied onto newConf at the next change of the file.
+func (p *PlugPolaris) UpdatePolarisConfig(newConf *conf.Polaris) error {
+	if newConf == nil {
+		return NewConfigError("configuration is required")
+	}
+	updated := proto.Clone(newConf).(*conf.Polaris)
+	setConfigDefaults(updated)
+	result := NewValidator(updated).Validate()
+	if !result.IsValid {
+		return NewConfigError(p.redact(result.Errors[0].Error()))
+	}
+	p.logValidationWarnings(result)
+
+	p.mu.Lock()
+	previous := p.currentConf()
+	if previous == nil {
+		p.mu.Unlock()
+		return newNotInitializedError()
+	}
+	changed := changedSettings(previous, updated)
+	var immutable []string
+	for _, name := range changed {
+		if slices.Contains(immutableSettings, protoreflect.Name(name)) {
+			immutable = append(immutable, name)
+		}
+	}
+	if len(immutable) > 0 {
+		p.mu.Unlock()
+		return NewConfigError(fmt.Sprintf("%s cannot change at runtime, restart the plugin to apply them",
+			strings.Join(immutable, ", ")))
+	}
+	p.setConf(updated)
+	if p.localConf != nil {
+		p.localConf = proto.Clone(updated).(*conf.Polaris)
+	}
+	p.mu.Unlock()
+
+	if len(changed) == 0 {
+		return nil
+	}
+	p.applySettingsChange(previous, updated)
+	log.Infof("Updated Polaris plugin settings: %s", strings.Join(changed, ", "))
+	return nil
+}
+
+// changedSettings returns the names of the settings that differ between a and b.
+func changedSettings(a, b *conf.Polaris) []string {
+	ma, mb := a.ProtoReflect(), b.ProtoReflect()
+	fields := ma.Descriptor().Fields()
+	var changed []string
+	for i := range fields.Len() {
+		field := fields.Get(i)
+		if !ma.Get(field).Equal(mb.Get(field)) {
+			changed = append(changed, string(field.Name()))
+		}
+	}
+	return changed
+}
+
+// settingsChanged reports whether one of the named settings differs between a and b.
+func settingsChanged(a, b *conf.Polaris, names ...protoreflect.Name) bool {
+	ma, mb := a.ProtoReflect(), b.ProtoReflect()
+	fields := ma.Descriptor().Fields()
+	for _, name := range names {
+		field := fields.ByName(name)
+		if !ma.Get(field).Equal(mb.Get(field)) {
+			return true
+		}
+	}
+	return false
+}
+
+// applySettingsChange updates the components built from the settings that changed from
+// previous to current. Other settings are read from the configuration when used.
+func (p *PlugPolaris) applySettingsChange(previous, current *conf.Polaris) {
+	if settingsChanged(previous, current, resilienceSettings...) {
+		log.Infof("Rebuilding the retry manager and circuit breaker with the new settings")
+		p.initResilience()
+	}
+	if settingsChanged(previous, current, "alerting") {
+		for i := range previous.GetAlerting().GetWebhooks() {
+			p.RemoveAlerter(fmt.Sprintf("alerting-%d", i))
+		}
+		p.initAlerters()
+	}
+	if size := current.GetEventHistorySize(); size != 0 && settingsChanged(previous, current, "event_history_size") {
+		p.events.setHistorySize(int(size))
+	}
+	p.loadConfiguredFallbacks()
+	p.loadConfiguredRouteFallbacks()
+
+	p.mu.RLock()
+	registrar := p.registrar
+	p.mu.RUnlock()
+	if registrar == nil {
+		return
+	}
+	if settingsChanged(previous, current, "ttl", "heartbeat") {
+		ttl := 0
+		if cfg, heartbeatTTL := p.heartbeatConfig(); cfg.GetEnabled() {
+			ttl = heartbeatTTL
+		}
+		if err := registrar.setTTL(context.Background(), ttl); err != nil {
+			log.Warnf("Failed to register the new TTL: %v", p.redactError(err))
+		}
+	}
+	if settingsChanged(previous, current, "weight") {
+		if err := p.applyInstanceWeight(context.Background()); err != nil {
+			log.Warnf("Failed to register the new weight: %v", p.redactError(err))
+		}
+	}
+}
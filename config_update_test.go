@@ -0,0 +1,100 @@
+package polaris
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUpdatePolarisConfig(t *testing.T) {
+	plugin := newTestPlugin(t, &conf.Polaris{Namespace: "default", Heartbeat: &conf.Heartbeat{Enabled: true}})
+	provider := &fakeClients{}
+	plugin.registrar = NewPolarisRegistrar(provider, "default")
+	plugin.registrar.ttl = conf.DefaultTTL
+	require.NoError(t, plugin.registrar.Register(context.Background(), &registry.ServiceInstance{
+		Name: "svc", Endpoints: []string{"http://10.0.0.1:8080"},
+	}))
+	circuitBreaker := plugin.circuitBreaker
+
+	updated := proto.Clone(plugin.GetPolarisConfig()).(*conf.Polaris)
+	updated.Weight = 60
+	updated.Ttl = 15
+	updated.MaxRetryTimes = 6
+	provider.registered = nil
+	require.NoError(t, plugin.UpdatePolarisConfig(updated))
+	assert.Equal(t, int32(60), plugin.GetPolarisConfig().Weight)
+	assert.Equal(t, 6, plugin.retryManager.maxRetries)
+	assert.NotSame(t, circuitBreaker, plugin.circuitBreaker)
+	last := provider.registered[len(provider.registered)-1]
+	assert.Equal(t, 60, *last.Weight)
+	assert.Equal(t, 15, *last.TTL)
+
+	// Immutable settings are rejected and nothing is applied
+	rejected := proto.Clone(updated).(*conf.Polaris)
+	rejected.Namespace = "staging"
+	rejected.ServerBootstrap = &conf.ServerBootstrap{Addresses: []string{"10.0.0.9:8091"}}
+	rejected.Weight = 70
+	err := plugin.UpdatePolarisConfig(rejected)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "namespace, server_bootstrap cannot change at runtime")
+	assert.Equal(t, int32(60), plugin.GetPolarisConfig().Weight)
+
+	// Invalid configurations are rejected by the validator
+	invalid := proto.Clone(updated).(*conf.Polaris)
+	invalid.Weight = conf.MaxWeight + 1
+	assert.Error(t, plugin.UpdatePolarisConfig(invalid))
+	assert.Error(t, plugin.UpdatePolarisConfig(nil))
+}
+
+func TestChangedSettings(t *testing.T) {
+	a := &conf.Polaris{Namespace: "default", Weight: 100}
+	b := &conf.Polaris{Namespace: "default", Weight: 50, Alerting: &conf.Alerting{Webhooks: []*conf.AlertWebhook{{Url: "https://hooks.example.com"}}}}
+	assert.Equal(t, []string{"weight", "alerting"}, changedSettings(a, b))
+	assert.Empty(t, changedSettings(a, proto.Clone(a).(*conf.Polaris)))
+	assert.True(t, settingsChanged(a, b, "weight"))
+	assert.False(t, settingsChanged(a, b, resilienceSettings...))
+}
+
+func TestUpdatePolarisConfig_ConcurrentReads(t *testing.T) {
+	plugin, err := NewPluginWithClients(&conf.Polaris{Namespace: "default"}, WithConsumerClient(&fakeClients{}))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = plugin.CleanupTasks() })
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				plugin.updateServiceInstanceCache("orders", nil)
+				_ = plugin.cachedServiceInstances("orders")
+				_, _ = plugin.cachedConfigContent("app.yaml", "orders")
+				_, _ = plugin.GetServiceInstances("orders")
+				_ = plugin.GetPolarisConfig().GetWeight()
+			}
+		}()
+	}
+
+	updated := plugin.GetPolarisConfig()
+	for i := range 50 {
+		updated = proto.Clone(updated).(*conf.Polaris)
+		updated.Weight = int32(10 + i)
+		updated.MaxRetryTimes = int32(i % 3)
+		require.NoError(t, plugin.UpdatePolarisConfig(updated))
+	}
+	close(done)
+	wg.Wait()
+	assert.Equal(t, int32(59), plugin.GetPolarisConfig().GetWeight())
+}
@@ -0,0 +1,97 @@
+package polaris
+
+import (
+	"fmt"
+
+	"github.com/go-lynx/lynx/log"
+)
+
+// RegisterConfigValidator registers fn to check new content of fileName in group before it
+// is applied. Validators run in registration order on the decrypted content; the first
+// error rejects the change. A rejected change is not cached or snapshotted, reload
+// handlers are not called, and GetConfigValue keeps returning the last content that
+// passed. Results are counted in config_validations_total.
+func (p *PlugPolaris) RegisterConfigValidator(fileName, group string, fn func(content string) error) error {
+	if fn == nil {
+		return NewConfigError("config validator must not be nil")
+	}
+	if fileName == "" {
+		return NewConfigError("config file name is required")
+	}
+	p.validatorMutex.Lock()
+	defer p.validatorMutex.Unlock()
+	if p.configValidators == nil {
+		p.configValidators = make(map[string][]func(content string) error)
+	}
+	key := reloadKey(fileName, group)
+	p.configValidators[key] = append(p.configValidators[key], fn)
+	log.Infof("Registered config validator for %s:%s", fileName, group)
+	return nil
+}
+
+// configValidatorsFor returns a copy of the validators of a config file.
+func (p *PlugPolaris) configValidatorsFor(fileName, group string) []func(content string) error {
+	p.validatorMutex.Lock()
+	defer p.validatorMutex.Unlock()
+	return append([]func(content string) error(nil), p.configValidators[reloadKey(fileName, group)]...)
+}
+
+// runConfigValidator calls a validator, turning a panic into an error.
+func runConfigValidator(fn func(content string) error, content string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("config validator panicked: %v", r)
+		}
+	}()
+	return fn(content)
+}
+
+// checkConfigContent runs the registered validators of a config file against its raw
+// content and records the result. Files without validators always pass.
+func (p *PlugPolaris) checkConfigContent(fileName, group, content string) error {
+	validators := p.configValidatorsFor(fileName, group)
+	if len(validators) == 0 {
+		return nil
+	}
+	p.mu.RLock()
+	metrics := p.metrics
+	p.mu.RUnlock()
+
+	decrypted, err := p.decryptConfigContent(fileName, group, content)
+	for _, fn := range validators {
+		if err != nil {
+			break
+		}
+		err = runConfigValidator(fn, decrypted)
+	}
+	result := "passed"
+	if err != nil {
+		result = "rejected"
+		err = WrapError(err, ErrCodeConfigValidation, fmt.Sprintf("config %s:%s failed validation", fileName, group))
+	}
+	if metrics != nil {
+		metrics.RecordConfigValidation(fileName, group, result)
+	}
+	return err
+}
+
+// lastGoodConfigContent returns the decrypted content that last passed validation, from
+// the watch cache of the plugin namespace or the local snapshot, or cause when there is none.
+func (p *PlugPolaris) lastGoodConfigContent(namespace, fileName, group string, cause error) (string, error) {
+	p.mu.RLock()
+	cached := p.currentConf() != nil && namespace == p.currentConf().Namespace
+	p.mu.RUnlock()
+	var content string
+	var ok bool
+	if cached {
+		content, ok = p.cachedConfigContent(fileName, group)
+	}
+	if !ok {
+		snapshot, err := p.loadConfigSnapshot(namespace, group, fileName)
+		if err != nil {
+			return "", cause
+		}
+		content = snapshot
+	}
+	return p.decryptConfigContent(fileName, group, content)
+}
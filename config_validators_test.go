@@ -0,0 +1,80 @@
+package polaris
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterConfigValidator_RejectsInvalidArguments(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.Error(t, plugin.RegisterConfigValidator("app.yaml", "orders", nil))
+	assert.Error(t, plugin.RegisterConfigValidator("", "orders", func(string) error { return nil }))
+}
+
+func TestCheckConfigContent_RunsValidatorsInOrder(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	assert.NoError(t, plugin.checkConfigContent("app.yaml", "orders", "anything"))
+
+	var calls []string
+	require.NoError(t, plugin.RegisterConfigValidator("app.yaml", "orders", func(content string) error {
+		calls = append(calls, "first")
+		if content == "bad" {
+			return errors.New("bad content")
+		}
+		return nil
+	}))
+	require.NoError(t, plugin.RegisterConfigValidator("app.yaml", "orders", func(content string) error {
+		calls = append(calls, "second")
+		if content == "panic" {
+			panic("boom")
+		}
+		return nil
+	}))
+
+	assert.NoError(t, plugin.checkConfigContent("app.yaml", "orders", "good"))
+	assert.Equal(t, []string{"first", "second"}, calls)
+
+	err := plugin.checkConfigContent("app.yaml", "orders", "bad")
+	require.Error(t, err)
+	assert.True(t, IsConfigError(err))
+	assert.Contains(t, err.Error(), "bad content")
+
+	assert.ErrorContains(t, plugin.checkConfigContent("app.yaml", "orders", "panic"), "panicked")
+	assert.NoError(t, plugin.checkConfigContent("other.yaml", "orders", "bad"))
+}
+
+func TestHandleConfigChanged_RejectedChangeKeepsLastGoodContent(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	require.NoError(t, plugin.RegisterConfigValidator("app.yaml", "orders", func(content string) error {
+		if content == "" {
+			return errors.New("empty config")
+		}
+		return nil
+	}))
+	var reloaded []string
+	_, err := plugin.addReloadHandler("app.yaml", "orders", func(content string) error {
+		reloaded = append(reloaded, content)
+		return nil
+	})
+	require.NoError(t, err)
+
+	plugin.handleConfigChanged("app.yaml", "orders", &fakeConfigFile{content: "workers: 4\n"})
+	plugin.handleConfigChanged("app.yaml", "orders", &fakeConfigFile{content: ""})
+
+	assert.Equal(t, []string{"workers: 4\n"}, reloaded)
+	content, ok := plugin.cachedConfigContent("app.yaml", "orders")
+	assert.True(t, ok)
+	assert.Equal(t, "workers: 4\n", content)
+
+	content, err = plugin.lastGoodConfigContent("default", "app.yaml", "orders", errors.New("rejected"))
+	require.NoError(t, err)
+	assert.Equal(t, "workers: 4\n", content)
+	_, err = plugin.lastGoodConfigContent("other", "app.yaml", "orders", errors.New("rejected"))
+	assert.EqualError(t, err, "rejected")
+}
@@ -0,0 +1,224 @@
+package polaris
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	kgrpc "github.com/go-kratos/kratos/v2/transport/grpc"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx/log"
+)
+
+// Service contract module
+// Responsibility: publishing the API definition of services (their HTTP routes or gRPC
+// methods) to the service contract API of Polaris, for interface-level governance.
+
+// serviceContractPath is the service contract resource of the Polaris OpenAPI.
+const serviceContractPath = "/naming/v1/service/contracts"
+
+// contractSourceClient marks interfaces reported by clients rather than entered in the
+// console.
+const contractSourceClient = 2
+
+// Service contract protocols.
+const (
+	ContractProtocolHTTP = "http"
+	ContractProtocolGRPC = "grpc"
+)
+
+// ServiceContract is the API definition of a service.
+type ServiceContract struct {
+	// Name is the contract name. It defaults to the protocol.
+	Name string
+	// Service is the service the contract belongs to. It defaults to the registered
+	// service, or to the application name.
+	Service string
+	// Namespace defaults to the plugin namespace.
+	Namespace string
+	// Protocol is the protocol of the interfaces, e.g. ContractProtocolHTTP.
+	Protocol string
+	// Version is the version of the contract, e.g. the service version.
+	Version string
+	// Revision identifies the content of the contract. It defaults to a hash of Content
+	// and Interfaces, so that Polaris only stores changed contracts.
+	Revision string
+	// Content is the full definition, e.g. an OpenAPI document or a proto file.
+	Content string
+	// Interfaces are the methods of the service.
+	Interfaces []ContractInterface
+}
+
+// ContractInterface is a method of a service contract.
+type ContractInterface struct {
+	// Method is the HTTP method, empty for gRPC methods and routes matching any method.
+	Method string
+	// Path is the HTTP path, or the full gRPC method name, e.g. /helloworld.Greeter/SayHello.
+	Path string
+	// Name is the display name of the interface.
+	Name string
+	// Content is the definition of the interface.
+	Content string
+}
+
+// serviceContractRequest is a service contract in the Polaris OpenAPI.
+type serviceContractRequest struct {
+	Name       string                     `json:"name"`
+	Namespace  string                     `json:"namespace"`
+	Service    string                     `json:"service"`
+	Protocol   string                     `json:"protocol"`
+	Version    string                     `json:"version,omitempty"`
+	Revision   string                     `json:"revision"`
+	Content    string                     `json:"content,omitempty"`
+	Interfaces []contractInterfaceRequest `json:"interfaces"`
+}
+
+// contractInterfaceRequest is an interface of a service contract in the Polaris OpenAPI.
+type contractInterfaceRequest struct {
+	Method  string `json:"method,omitempty"`
+	Path    string `json:"path"`
+	Name    string `json:"name,omitempty"`
+	Content string `json:"content,omitempty"`
+	Source  int    `json:"source"`
+}
+
+// withDefaults returns the contract with the defaults of the empty fields filled in, and
+// its interfaces sorted.
+func (c ServiceContract) withDefaults(namespace, service string) ServiceContract {
+	c.Service = cmp.Or(c.Service, service)
+	c.Namespace = cmp.Or(c.Namespace, namespace)
+	c.Name = cmp.Or(c.Name, c.Protocol)
+	c.Interfaces = slices.Clone(c.Interfaces)
+	slices.SortStableFunc(c.Interfaces, func(a, b ContractInterface) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Method, b.Method))
+	})
+	if c.Revision == "" {
+		data, _ := json.Marshal(struct {
+			Content    string
+			Interfaces []ContractInterface
+		}{c.Content, c.Interfaces})
+		sum := sha256.Sum256(data)
+		c.Revision = hex.EncodeToString(sum[:])
+	}
+	return c
+}
+
+// validate checks the fields Polaris requires.
+func (c ServiceContract) validate() error {
+	if c.Service == "" {
+		return NewConfigError("service contract service must not be empty")
+	}
+	if c.Protocol == "" {
+		return NewConfigError("service contract protocol must not be empty")
+	}
+	for _, iface := range c.Interfaces {
+		if iface.Path == "" {
+			return NewConfigError("service contract interface path must not be empty")
+		}
+	}
+	return nil
+}
+
+// request returns the contract in the Polaris OpenAPI format.
+func (c ServiceContract) request() serviceContractRequest {
+	interfaces := make([]contractInterfaceRequest, 0, len(c.Interfaces))
+	for _, iface := range c.Interfaces {
+		interfaces = append(interfaces, contractInterfaceRequest{
+			Method:  iface.Method,
+			Path:    iface.Path,
+			Name:    iface.Name,
+			Content: iface.Content,
+			Source:  contractSourceClient,
+		})
+	}
+	return serviceContractRequest{
+		Name:       c.Name,
+		Namespace:  c.Namespace,
+		Service:    c.Service,
+		Protocol:   c.Protocol,
+		Version:    c.Version,
+		Revision:   c.Revision,
+		Content:    c.Content,
+		Interfaces: interfaces,
+	}
+}
+
+// ReportServiceContract publishes contract to the service contract API of Polaris, through
+// the OpenAPI at config_admin.address with the write token. An unchanged contract is
+// stored once: its revision defaults to a hash of its content. The registration subsystem
+// has to be enabled.
+func (p *PlugPolaris) ReportServiceContract(contract ServiceContract) error {
+	return p.reportServiceContract(context.Background(), contract, "")
+}
+
+// reportServiceContract reports contract, defaulting its service to service and then to
+// the application name.
+func (p *PlugPolaris) reportServiceContract(ctx context.Context, contract ServiceContract, service string) error {
+	if err := p.checkInitialized(); err != nil {
+		return err
+	}
+	if err := p.checkSubsystem(SubsystemRegistration); err != nil {
+		return err
+	}
+	p.mu.RLock()
+	namespace := p.currentConf().GetNamespace()
+	p.mu.RUnlock()
+	contract = contract.withDefaults(namespace, cmp.Or(service, currentLynxName()))
+	if err := contract.validate(); err != nil {
+		return err
+	}
+	client, err := p.openAPIClient(TokenOperationWrite, "report service contracts")
+	if err != nil {
+		return err
+	}
+	if _, err := client.do(ctx, http.MethodPost, serviceContractPath, nil, []serviceContractRequest{contract.request()}, nil); err != nil {
+		return WrapServiceError(err, ErrCodeServiceUnavailable, fmt.Sprintf("failed to report %s contract %s of service %s", contract.Protocol, contract.Name, contract.Service))
+	}
+	log.Infof("Reported %s contract %s of service %s with %d interfaces", contract.Protocol, contract.Name, contract.Service, len(contract.Interfaces))
+	p.recordApplicationAudit(AuditEvent{Type: AuditServiceContractReported, Service: contract.Service, ContractName: contract.Name, ContractRevision: contract.Revision})
+	return nil
+}
+
+// HTTPServiceContract returns the contract of the routes registered on srv, with protocol
+// http. Call it after the routes are registered.
+func HTTPServiceContract(srv *khttp.Server) (ServiceContract, error) {
+	contract := ServiceContract{Protocol: ContractProtocolHTTP}
+	if srv == nil {
+		return contract, NewConfigError("HTTP server must not be nil")
+	}
+	err := srv.WalkRoute(func(route khttp.RouteInfo) error {
+		if route.Path != "" {
+			contract.Interfaces = append(contract.Interfaces, ContractInterface{Method: route.Method, Path: route.Path})
+		}
+		return nil
+	})
+	if err != nil {
+		return contract, WrapServiceError(err, ErrCodeServiceUnavailable, "failed to walk HTTP routes")
+	}
+	return contract, nil
+}
+
+// GRPCServiceContract returns the contract of the services registered on srv, with
+// protocol grpc, leaving out the services of gRPC and Kratos themselves, such as health,
+// channelz and the Kratos metadata service. Call it after the services are registered.
+func GRPCServiceContract(srv *kgrpc.Server) ServiceContract {
+	contract := ServiceContract{Protocol: ContractProtocolGRPC}
+	if srv == nil || srv.Server == nil {
+		return contract
+	}
+	for name, info := range srv.GetServiceInfo() {
+		if strings.HasPrefix(name, "grpc.") || strings.HasPrefix(name, "kratos.api.") {
+			continue
+		}
+		for _, method := range info.Methods {
+			contract.Interfaces = append(contract.Interfaces, ContractInterface{Path: "/" + name + "/" + method.Name, Name: method.Name})
+		}
+	}
+	return contract
+}
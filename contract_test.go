@@ -0,0 +1,141 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	kgrpc "github.com/go-kratos/kratos/v2/transport/grpc"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// fakeContractServer is a minimal Polaris service contract OpenAPI.
+type fakeContractServer struct {
+	mu        sync.Mutex
+	contracts []serviceContractRequest
+}
+
+func (s *fakeContractServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != serviceContractPath || r.Method != http.MethodPost || r.Header.Get("X-Polaris-Token") != "secret-token" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var contracts []serviceContractRequest
+	_ = json.NewDecoder(r.Body).Decode(&contracts)
+	s.mu.Lock()
+	s.contracts = append(s.contracts, contracts...)
+	s.mu.Unlock()
+	_ = json.NewEncoder(w).Encode(polarisResponse{Code: polarisCodeSuccess, Info: "ok"})
+}
+
+// withContractServer points the OpenAPI of plugin at a fake contract server
+func withContractServer(t *testing.T, plugin *PlugPolaris) *fakeContractServer {
+	t.Helper()
+	fake := &fakeContractServer{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	plugin.currentConf().Token = "secret-token"
+	plugin.currentConf().ConfigAdmin = &conf.ConfigAdmin{Address: server.URL}
+	return fake
+}
+
+func TestReportServiceContract(t *testing.T) {
+	plugin := newTestPlugin(t, nil)
+	fake := withContractServer(t, plugin)
+
+	contract := ServiceContract{Service: "orders", Protocol: ContractProtocolHTTP, Version: "v1", Interfaces: []ContractInterface{
+		{Method: http.MethodPost, Path: "/orders"},
+		{Method: http.MethodGet, Path: "/orders"},
+	}}
+	require.NoError(t, plugin.ReportServiceContract(contract))
+	require.NoError(t, plugin.ReportServiceContract(contract))
+	require.Len(t, fake.contracts, 2)
+	reported := fake.contracts[0]
+	assert.Equal(t, "http", reported.Name, "the name defaults to the protocol")
+	assert.Equal(t, "default", reported.Namespace)
+	assert.Equal(t, "orders", reported.Service)
+	assert.Equal(t, "v1", reported.Version)
+	assert.NotEmpty(t, reported.Revision)
+	assert.Equal(t, reported.Revision, fake.contracts[1].Revision, "the same contract has the same revision")
+	assert.Equal(t, []contractInterfaceRequest{
+		{Method: http.MethodGet, Path: "/orders", Source: contractSourceClient},
+		{Method: http.MethodPost, Path: "/orders", Source: contractSourceClient},
+	}, reported.Interfaces)
+
+	contract.Interfaces = contract.Interfaces[:1]
+	require.NoError(t, plugin.ReportServiceContract(contract))
+	assert.NotEqual(t, reported.Revision, fake.contracts[2].Revision)
+}
+
+func TestReportServiceContract_Validation(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.True(t, IsInitError(plugin.ReportServiceContract(ServiceContract{Service: "orders", Protocol: "http"})))
+
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	plugin.setInitialized()
+	assert.True(t, IsConfigError(plugin.ReportServiceContract(ServiceContract{Service: "orders"})), "protocol is required")
+	assert.True(t, IsConfigError(plugin.ReportServiceContract(ServiceContract{Service: "orders", Protocol: "http"})), "the OpenAPI address is required")
+
+	plugin.currentConf().Subsystems = &conf.Subsystems{Discovery: true}
+	assert.True(t, IsSubsystemDisabled(plugin.ReportServiceContract(ServiceContract{Service: "orders", Protocol: "http"})))
+}
+
+func TestHTTPServiceContract(t *testing.T) {
+	srv := khttp.NewServer()
+	noop := func(khttp.Context) error { return nil }
+	srv.Route("/").GET("/orders/{id}", noop)
+	srv.Route("/").POST("/orders", noop)
+
+	contract, err := HTTPServiceContract(srv)
+	require.NoError(t, err)
+	assert.Equal(t, ContractProtocolHTTP, contract.Protocol)
+	assert.ElementsMatch(t, []ContractInterface{
+		{Method: http.MethodGet, Path: "/orders/{id}"},
+		{Method: http.MethodPost, Path: "/orders"},
+	}, contract.Interfaces)
+
+	_, err = HTTPServiceContract(nil)
+	assert.Error(t, err)
+}
+
+func TestGRPCServiceContract(t *testing.T) {
+	srv := kgrpc.NewServer()
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "orders.v1.Orders",
+		HandlerType: (*any)(nil),
+		Methods:     []grpc.MethodDesc{{MethodName: "GetOrder"}},
+		Streams:     []grpc.StreamDesc{{StreamName: "WatchOrders", ServerStreams: true}},
+	}, struct{}{})
+
+	contract := GRPCServiceContract(srv)
+	assert.Equal(t, ContractProtocolGRPC, contract.Protocol)
+	assert.ElementsMatch(t, []ContractInterface{
+		{Path: "/orders.v1.Orders/GetOrder", Name: "GetOrder"},
+		{Path: "/orders.v1.Orders/WatchOrders", Name: "WatchOrders"},
+	}, contract.Interfaces, "the gRPC and Kratos services are left out")
+	assert.Empty(t, GRPCServiceContract(nil).Interfaces)
+}
+
+func TestBuildRegistrar_ReportsContracts(t *testing.T) {
+	plugin := newBuilderTestPlugin(t, &fakeClients{}, &fakeClients{})
+	fake := withContractServer(t, plugin)
+	contract := ServiceContract{Protocol: ContractProtocolGRPC, Interfaces: []ContractInterface{{Path: "/orders.v1.Orders/GetOrder"}}}
+	registrar := plugin.BuildRegistrar(WithRegisterContract(contract))
+
+	svc := &registry.ServiceInstance{Name: "orders", Version: "v2", Endpoints: []string{"grpc://10.0.0.1:9000"}}
+	require.NoError(t, registrar.Register(context.Background(), svc))
+	require.Len(t, fake.contracts, 1)
+	assert.Equal(t, "orders", fake.contracts[0].Service)
+	assert.Equal(t, "v2", fake.contracts[0].Version)
+
+	plugin.currentConf().ConfigAdmin = nil
+	require.NoError(t, registrar.Register(context.Background(), svc), "failed reports do not fail the registration")
+}
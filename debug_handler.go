@@ -0,0 +1,122 @@
+package polaris
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// CachedService is the instance cache entry of a service
+type CachedService struct {
+	Service   string             `json:"service"`
+	Namespace string             `json:"namespace"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	Instances []InstanceSnapshot `json:"instances"`
+}
+
+// DebugHandler returns an HTTP handler exposing the plugin internals as JSON. Mount it on an
+// internal listener only, stripping its prefix:
+//
+//	mux.Handle("/debug/polaris/", http.StripPrefix("/debug/polaris", plugin.DebugHandler()))
+//
+// It serves:
+//   - GET /stats: the GetStats snapshot
+//   - GET /instances: the cached instances, of the service named by the "service" parameter if set
+//   - GET /configs: the config watchers and the freshness of the config files read
+//   - GET /health: the last health report and the health state history
+//   - GET and POST /breakers: the circuit breakers, see CircuitBreakerAdminHandler
+//   - GET /ratelimit/rules: the rate limit rules, see RateLimitRulesHandler
+//   - POST /services/{name}/refresh: fetches the instances of a service now and caches them
+func (p *PlugPolaris) DebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stats", func(w http.ResponseWriter, r *http.Request) {
+		writeDebugJSON(w, p.GetStats())
+	})
+	mux.HandleFunc("GET /instances", func(w http.ResponseWriter, r *http.Request) {
+		writeDebugJSON(w, struct {
+			Services []CachedService `json:"services"`
+		}{p.cachedServices(r.URL.Query().Get("service"))})
+	})
+	mux.HandleFunc("GET /configs", func(w http.ResponseWriter, r *http.Request) {
+		watchers := make([]WatcherStats, 0)
+		for _, watcher := range p.watcherStats() {
+			if watcher.Type == watcherTypeConfig {
+				watchers = append(watchers, watcher)
+			}
+		}
+		writeDebugJSON(w, struct {
+			Watchers []WatcherStats    `json:"watchers"`
+			Files    []ConfigFreshness `json:"files"`
+		}{watchers, p.ConfigFreshness()})
+	})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		report, _ := p.LastHealthReport()
+		writeDebugJSON(w, struct {
+			Report HealthReport `json:"report"`
+			State  HealthStats  `json:"state"`
+		}{report, p.healthStats()})
+	})
+	mux.Handle("/breakers", p.CircuitBreakerAdminHandler())
+	mux.Handle("GET /ratelimit/rules", p.RateLimitRulesHandler())
+	mux.HandleFunc("POST /services/{name}/refresh", func(w http.ResponseWriter, r *http.Request) {
+		serviceName := r.PathValue("name")
+		instances, err := p.RefreshService(serviceName)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if IsInitError(err) {
+				status = http.StatusServiceUnavailable
+			} else if isErrorCode(err, ErrCodeServiceNotFound) {
+				status = http.StatusBadRequest
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		writeDebugJSON(w, struct {
+			Service   string             `json:"service"`
+			Instances []InstanceSnapshot `json:"instances"`
+		}{serviceName, newInstanceSnapshots(instances)})
+	})
+	return mux
+}
+
+// RefreshService fetches the instances of serviceName from Polaris now, instead of waiting
+// for the next poll of its watcher, and stores them in the instance cache
+func (p *PlugPolaris) RefreshService(serviceName string) ([]model.Instance, error) {
+	if serviceName == "" {
+		return nil, NewServiceError(ErrCodeServiceNotFound, "service name is required")
+	}
+	instances, err := p.getServiceInstances(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	p.updateServiceInstanceCache(serviceName, instances)
+	return instances, nil
+}
+
+// cachedServices returns the instance cache entries of serviceName, or of every service
+// when serviceName is empty, sorted by service
+func (p *PlugPolaris) cachedServices(serviceName string) []CachedService {
+	services := make([]CachedService, 0, p.serviceCache.len())
+	p.serviceCache.each(func(cached cachedInstances, updatedAt time.Time) {
+		if serviceName != "" && cached.service != serviceName {
+			return
+		}
+		services = append(services, CachedService{
+			Service:   cached.service,
+			Namespace: cached.namespace,
+			UpdatedAt: updatedAt,
+			Instances: newInstanceSnapshots(cached.instances),
+		})
+	})
+	sort.Slice(services, func(i, j int) bool { return services[i].Service < services[j].Service })
+	return services
+}
+
+// writeDebugJSON writes v as the JSON body of a debug response
+func writeDebugJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
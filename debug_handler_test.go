@@ -0,0 +1,79 @@
+package polaris
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveDebug(plugin *PlugPolaris, method, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler := http.StripPrefix("/debug/polaris", plugin.DebugHandler())
+	handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestDebugHandler(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	instance := NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})
+	plugin.updateServiceInstanceCache("orders", []model.Instance{instance})
+	plugin.updateServiceInstanceCache("payments", nil)
+	plugin.configWatchers["app.yaml:orders"] = NewConfigWatcher(nil, "app.yaml", "orders", "default")
+	plugin.circuitBreakers.Register(PluginCircuitBreakerKey, NewCircuitBreaker(0.5, time.Second))
+
+	rec := serveDebug(plugin, http.MethodGet, "/debug/polaris/stats")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var stats PluginStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, 2, stats.ServiceCache.Size)
+
+	rec = serveDebug(plugin, http.MethodGet, "/debug/polaris/instances?service=orders")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var instances struct {
+		Services []CachedService `json:"services"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &instances))
+	require.Len(t, instances.Services, 1)
+	require.Len(t, instances.Services[0].Instances, 1)
+	assert.Equal(t, "10.0.0.1", instances.Services[0].Instances[0].Host)
+
+	rec = serveDebug(plugin, http.MethodGet, "/debug/polaris/configs")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"app.yaml:orders"`)
+
+	rec = serveDebug(plugin, http.MethodPost, "/debug/polaris/breakers?key=polaris&action=open")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"state":"open"`)
+
+	rec = serveDebug(plugin, http.MethodPost, "/debug/polaris/services/orders/refresh")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "not initialized")
+
+	rec = serveDebug(plugin, http.MethodGet, "/debug/polaris/services/orders/refresh")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	rec = serveDebug(plugin, http.MethodGet, "/debug/polaris/unknown")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDebugHandler_Health(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	plugin.recordHealthTransition(nil)
+	plugin.recordHealthTransition(assert.AnError)
+
+	rec := serveDebug(plugin, http.MethodGet, "/debug/polaris/health")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var health struct {
+		State HealthStats `json:"state"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+	assert.Equal(t, HealthStatusUnhealthy, health.State.State)
+	assert.Len(t, health.State.History, 1)
+}
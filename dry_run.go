@@ -0,0 +1,52 @@
+package polaris
+
+import (
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Dry-run module
+// Responsibility: keeping mutating calls from reaching Polaris when dry_run is set, so a
+// configuration can be tried against a live control plane without changing it.
+
+// DryRun reports whether the plugin logs and audits its mutating calls instead of sending
+// them to Polaris.
+func (p *PlugPolaris) DryRun() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.currentConf().GetDryRun()
+}
+
+// dryRunProvider is a ProviderClient that logs registrations, deregistrations and heartbeats
+// and reports them as successful without sending them.
+type dryRunProvider struct {
+	ProviderClient
+}
+
+func (dryRunProvider) RegisterInstance(req *api.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	logDryRunRegistration(req)
+	return &model.InstanceRegisterResponse{}, nil
+}
+
+func (dryRunProvider) Register(req *api.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	logDryRunRegistration(req)
+	return &model.InstanceRegisterResponse{}, nil
+}
+
+func (dryRunProvider) Deregister(req *api.InstanceDeRegisterRequest) error {
+	log.Infof("[dry-run] Not deregistering service %s at %s:%d", req.Service, req.Host, req.Port)
+	return nil
+}
+
+func (dryRunProvider) Heartbeat(req *api.InstanceHeartbeatRequest) error {
+	log.Debugf("[dry-run] Not sending heartbeat of service %s at %s:%d", req.Service, req.Host, req.Port)
+	return nil
+}
+
+func logDryRunRegistration(req *api.InstanceRegisterRequest) {
+	isolated := req.Isolate != nil && *req.Isolate
+	healthy := req.Healthy == nil || *req.Healthy
+	log.Infof("[dry-run] Not registering service %s at %s:%d (healthy=%t, isolated=%t)",
+		req.Service, req.Host, req.Port, healthy, isolated)
+}
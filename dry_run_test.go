@@ -0,0 +1,56 @@
+package polaris
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunProvider(t *testing.T) {
+	provider := &fakeClients{}
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", Weight: 100, DryRun: true})
+	plugin.registrar = NewPolarisRegistrar(dryRunProvider{ProviderClient: provider}, "default")
+	plugin.registrar.audit = plugin.registrationAudit()
+	plugin.setInitialized()
+	sink := &recordingAuditSink{}
+	plugin.SetAuditSink(sink)
+
+	svc := &registry.ServiceInstance{Name: "svc", Endpoints: []string{"grpc://10.0.0.1:9000"}}
+	require.NoError(t, plugin.registrar.Register(context.Background(), svc))
+	assert.True(t, plugin.registrar.hasInstances())
+	require.NoError(t, plugin.Isolate())
+	assert.True(t, plugin.IsIsolated())
+	require.NoError(t, plugin.registrar.Deregister(context.Background(), svc))
+
+	assert.Empty(t, provider.registered, "nothing is sent to Polaris")
+	assert.Empty(t, provider.deregistered)
+	assert.Equal(t, []AuditEventType{AuditServiceRegistered, AuditInstancesIsolated, AuditServiceDeregistered}, sink.types())
+	for _, event := range sink.events {
+		assert.True(t, event.DryRun)
+	}
+}
+
+func TestDryRunConfigWrites(t *testing.T) {
+	plugin, fake := newConfigAdminPlugin(t, "secret-token")
+	fake.files["orders/app.yaml"] = "workers: 4"
+	plugin.currentConf().DryRun = true
+	sink := &recordingAuditSink{}
+	plugin.SetAuditSink(sink)
+
+	require.NoError(t, plugin.PublishConfig(context.Background(), "app.yaml", "orders", "workers: 8"))
+	require.NoError(t, plugin.DeleteConfig(context.Background(), "app.yaml", "orders"))
+	assert.Empty(t, fake.requests, "writes are not sent")
+	assert.Equal(t, "workers: 4", fake.files["orders/app.yaml"])
+	assert.Equal(t, []AuditEventType{AuditConfigUpdated, AuditConfigReleased, AuditConfigDeleted}, sink.types())
+	for _, event := range sink.events {
+		assert.True(t, event.DryRun)
+	}
+
+	_, err := plugin.ListConfigFiles("orders")
+	assert.Len(t, fake.requests, 1, "reads are sent: %v", err)
+}
@@ -0,0 +1,123 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// ErrorClass tells the retry manager and the circuit breaker how to treat an error
+type ErrorClass int
+
+const (
+	// ErrorClassRetryable is a transient failure: it is retried and counts as a circuit breaker failure
+	ErrorClassRetryable ErrorClass = iota
+	// ErrorClassFatal is a failure that retrying cannot fix: it is not retried but counts as a circuit breaker failure
+	ErrorClassFatal
+	// ErrorClassIgnore says nothing about the health of the dependency: it is neither retried nor counted
+	ErrorClassIgnore
+)
+
+// String returns the name of the error class
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassRetryable:
+		return "retryable"
+	case ErrorClassFatal:
+		return "fatal"
+	case ErrorClassIgnore:
+		return "ignore"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrorClassifier classifies the non-nil error of an operation
+type ErrorClassifier func(error) ErrorClass
+
+// DefaultErrorClassifier is the classifier used when none is configured:
+//   - context.Canceled is ignored, since the caller gave up rather than the dependency failing
+//   - polaris-go SDK errors caused by the request itself, such as invalid arguments or an
+//     unknown service, are ignored; network, timeout and server-side errors are retryable;
+//     authentication failures, including 401 and 403 responses of the Polaris server, and all
+//     other SDK errors are fatal
+//   - plugin errors are classified by their code: missing resources, invalid requests and
+//     rejections by a limiter or an open circuit breaker are ignored, authentication failures
+//     and calls to an uninitialized or destroyed plugin are fatal
+//   - any other error, including context.DeadlineExceeded, is retryable
+func DefaultErrorClassifier(err error) ErrorClass {
+	if errors.Is(err, context.Canceled) {
+		return ErrorClassIgnore
+	}
+	var sdkErr model.SDKError
+	if errors.As(err, &sdkErr) {
+		if code, ok := serverErrorCode(sdkErr.ServerCode()); ok {
+			return classifyErrorCode(code)
+		}
+		return classifySDKErrorCode(sdkErr.ErrorCode())
+	}
+	// A plugin error wrapping a more specific one, e.g. a failed config write caused by a
+	// rejected token, is classified by the first code that is not retryable
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if polarisErr, ok := e.(*PolarisError); ok {
+			if class := classifyErrorCode(polarisErr.Code); class != ErrorClassRetryable {
+				return class
+			}
+		}
+	}
+	return ErrorClassRetryable
+}
+
+// classifySDKErrorCode classifies a polaris-go SDK error code
+func classifySDKErrorCode(code model.ErrCode) ErrorClass {
+	if code.Retryable() {
+		return ErrorClassRetryable
+	}
+	switch code {
+	case model.ErrCodeAPITimeoutError, model.ErrCodeConnectError, model.ErrCodeServerError,
+		model.ErrorCodeRpcError, model.ErrorCodeRpcTimeout, model.ErrCodeInvalidServerResponse,
+		model.ErrCodeRequestLimit, model.ErrCodeUnknownServerError:
+		return ErrorClassRetryable
+	case model.ErrCodeAPIInvalidArgument, model.ErrCodeServerUserError, model.ErrCodeInvalidRequest,
+		model.ErrCodeServiceNotFound, model.ErrCodeAPIInstanceNotFound, model.ErrCodeRouteRuleNotMatch,
+		model.ErrCodeDstMetaMismatch, model.ErrCodeLocationNotFound, model.ErrCodeMeshConfigNotFound:
+		return ErrorClassIgnore
+	default:
+		return ErrorClassFatal
+	}
+}
+
+// classifyErrorCode classifies a plugin error code
+func classifyErrorCode(code ErrorCode) ErrorClass {
+	switch code {
+	case ErrCodeUnauthorized, ErrCodeNotInitialized, ErrCodeSDKDestroyed, ErrCodeInitFailed,
+		ErrCodeAlreadyInitialized, ErrCodeSDKContextFailed, ErrCodeAPIInitFailed, ErrCodeSDKError:
+		return ErrorClassFatal
+	case ErrCodeServiceNotFound, ErrCodeConfigNotFound, ErrCodeResourceNotFound, ErrCodeInvalidRequest,
+		ErrCodeConfigInvalid, ErrCodeConfigMissing, ErrCodeConfigValidation, ErrCodeSubsystemDisabled,
+		ErrCodeRateLimitExceeded, ErrCodeConcurrencyLimitExceeded, ErrCodeCircuitBreakerOpen:
+		return ErrorClassIgnore
+	default:
+		return ErrorClassRetryable
+	}
+}
+
+// SetErrorClassifier sets the classifier shared by the plugin's retry manager and circuit
+// breaker. A nil classifier restores DefaultErrorClassifier.
+func (p *PlugPolaris) SetErrorClassifier(classifier ErrorClassifier) {
+	p.errorClassifierMutex.Lock()
+	defer p.errorClassifierMutex.Unlock()
+	p.errorClassifier = classifier
+}
+
+// classifyError classifies err with the classifier set by SetErrorClassifier
+func (p *PlugPolaris) classifyError(err error) ErrorClass {
+	p.errorClassifierMutex.RLock()
+	classifier := p.errorClassifier
+	p.errorClassifierMutex.RUnlock()
+	if classifier == nil {
+		return DefaultErrorClassifier(err)
+	}
+	return classifier(err)
+}
@@ -0,0 +1,44 @@
+package polaris
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultErrorClassifier(t *testing.T) {
+	assert.Equal(t, ErrorClassIgnore, DefaultErrorClassifier(context.Canceled))
+	assert.Equal(t, ErrorClassIgnore, DefaultErrorClassifier(fmt.Errorf("watch stopped: %w", context.Canceled)))
+	assert.Equal(t, ErrorClassRetryable, DefaultErrorClassifier(context.DeadlineExceeded))
+	assert.Equal(t, ErrorClassRetryable, DefaultErrorClassifier(assert.AnError))
+
+	sdkErr := func(code model.ErrCode) error { return model.NewSDKError(code, nil, "sdk failure") }
+	assert.Equal(t, ErrorClassRetryable, DefaultErrorClassifier(sdkErr(model.ErrCodeNetworkError)))
+	assert.Equal(t, ErrorClassRetryable, DefaultErrorClassifier(sdkErr(model.ErrCodeAPITimeoutError)))
+	assert.Equal(t, ErrorClassIgnore, DefaultErrorClassifier(sdkErr(model.ErrCodeAPIInvalidArgument)))
+	assert.Equal(t, ErrorClassIgnore, DefaultErrorClassifier(sdkErr(model.ErrCodeServiceNotFound)))
+	assert.Equal(t, ErrorClassFatal, DefaultErrorClassifier(sdkErr(model.ErrCodeUnauthorized)))
+	assert.Equal(t, ErrorClassRetryable, DefaultErrorClassifier(WrapServiceError(sdkErr(model.ErrCodeServerException), ErrCodeServiceUnavailable, "wrapped")))
+	forbidden := model.NewServerSDKError(403001, "token forbidden", nil, "get instances")
+	assert.Equal(t, ErrorClassFatal, DefaultErrorClassifier(forbidden), "a 403 is not retried")
+
+	assert.Equal(t, ErrorClassFatal, DefaultErrorClassifier(newNotInitializedError()))
+	assert.Equal(t, ErrorClassIgnore, DefaultErrorClassifier(NewServiceError(ErrCodeConfigNotFound, "configFile not found")))
+	assert.Equal(t, ErrorClassRetryable, DefaultErrorClassifier(NewServiceError(ErrCodeServiceUnavailable, "unavailable")))
+	rejected := writeConfigError(newServerResponseError(401000, "access denied"), "update", "app.yaml", "DEFAULT_GROUP")
+	assert.Equal(t, ErrorClassFatal, DefaultErrorClassifier(rejected), "classified by the wrapped code")
+}
+
+func TestSetErrorClassifier(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.Equal(t, ErrorClassIgnore, plugin.classifyError(context.Canceled))
+
+	plugin.SetErrorClassifier(func(error) ErrorClass { return ErrorClassFatal })
+	assert.Equal(t, ErrorClassFatal, plugin.classifyError(context.Canceled))
+
+	plugin.SetErrorClassifier(nil)
+	assert.Equal(t, ErrorClassIgnore, plugin.classifyError(context.Canceled))
+}
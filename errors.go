@@ -1,7 +1,9 @@
 package polaris
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"strings"
 )
 
@@ -19,17 +21,24 @@ const (
 	ErrCodeInitFailed         ErrorCode = "INIT_FAILED"
 	ErrCodeAlreadyInitialized ErrorCode = "ALREADY_INITIALIZED"
 	ErrCodeNotInitialized     ErrorCode = "NOT_INITIALIZED"
+	ErrCodeSubsystemDisabled  ErrorCode = "SUBSYSTEM_DISABLED"
 
 	// ErrCodeSDKContextFailed SDK related errors
 	ErrCodeSDKContextFailed ErrorCode = "SDK_CONTEXT_FAILED"
 	ErrCodeAPIInitFailed    ErrorCode = "API_INIT_FAILED"
 	ErrCodeSDKDestroyed     ErrorCode = "SDK_DESTROYED"
+	ErrCodeSDKUnavailable   ErrorCode = "SDK_UNAVAILABLE"
+	ErrCodeSDKError         ErrorCode = "SDK_ERROR"
+	ErrCodeUnauthorized     ErrorCode = "UNAUTHORIZED"
+	ErrCodeInvalidRequest   ErrorCode = "INVALID_REQUEST"
 
 	// ErrCodeServiceNotFound Service related errors
 	ErrCodeServiceNotFound       ErrorCode = "SERVICE_NOT_FOUND"
+	ErrCodeResourceNotFound      ErrorCode = "RESOURCE_NOT_FOUND"
 	ErrCodeServiceUnavailable    ErrorCode = "SERVICE_UNAVAILABLE"
 	ErrCodeServiceRegistration   ErrorCode = "SERVICE_REGISTRATION"
 	ErrCodeServiceDeregistration ErrorCode = "SERVICE_DEREGISTRATION"
+	ErrCodeCallResultReport      ErrorCode = "CALL_RESULT_REPORT"
 
 	// ErrCodeConfigNotFound Configuration management related errors
 	ErrCodeConfigNotFound    ErrorCode = "CONFIG_NOT_FOUND"
@@ -40,6 +49,9 @@ const (
 	ErrCodeRateLimitExceeded ErrorCode = "RATE_LIMIT_EXCEEDED"
 	ErrCodeRateLimitFailed   ErrorCode = "RATE_LIMIT_FAILED"
 
+	// ErrCodeConcurrencyLimitExceeded Concurrency limiting related errors
+	ErrCodeConcurrencyLimitExceeded ErrorCode = "CONCURRENCY_LIMIT_EXCEEDED"
+
 	// ErrCodeHealthCheckFailed Health check related errors
 	ErrCodeHealthCheckFailed  ErrorCode = "HEALTH_CHECK_FAILED"
 	ErrCodeHealthCheckTimeout ErrorCode = "HEALTH_CHECK_TIMEOUT"
@@ -65,6 +77,23 @@ const (
 	ErrCodeShutdownTimeout ErrorCode = "SHUTDOWN_TIMEOUT"
 )
 
+// Sentinel errors for errors.Is, matched by code, including the codes SDK errors map to;
+// WithCause and WithContext return copies, so never modify a sentinel itself
+var (
+	ErrNotInitialized     = &PolarisError{Code: ErrCodeNotInitialized, Message: "Polaris plugin not initialized"}
+	ErrDestroyed          = &PolarisError{Code: ErrCodeSDKDestroyed, Message: "Polaris plugin has been destroyed"}
+	ErrSubsystemDisabled  = &PolarisError{Code: ErrCodeSubsystemDisabled, Message: "subsystem disabled"}
+	ErrSDKUnavailable     = &PolarisError{Code: ErrCodeSDKUnavailable, Message: "Polaris SDK unavailable"}
+	ErrUnauthorized       = &PolarisError{Code: ErrCodeUnauthorized, Message: "unauthorized"}
+	ErrTimeout            = &PolarisError{Code: ErrCodeTimeout, Message: "operation timed out"}
+	ErrServiceNotFound    = &PolarisError{Code: ErrCodeServiceNotFound, Message: "service not found"}
+	ErrResourceNotFound   = &PolarisError{Code: ErrCodeResourceNotFound, Message: "resource not found"}
+	ErrInvalidRequest     = &PolarisError{Code: ErrCodeInvalidRequest, Message: "invalid request"}
+	ErrConfigNotFound     = &PolarisError{Code: ErrCodeConfigNotFound, Message: "config file not found"}
+	ErrRateLimited        = &PolarisError{Code: ErrCodeRateLimitExceeded, Message: "rate limit exceeded"}
+	ErrCircuitBreakerOpen = &PolarisError{Code: ErrCodeCircuitBreakerOpen, Message: "circuit breaker open"}
+)
+
 // PolarisError Polaris plugin error
 type PolarisError struct {
 	Code    ErrorCode
@@ -82,16 +111,27 @@ func NewPolarisError(code ErrorCode, message string) *PolarisError {
 	}
 }
 
-// WithCause sets error cause
+// WithCause returns a copy of e with cause set, leaving e, e.g. a sentinel, unchanged
 func (e *PolarisError) WithCause(cause error) *PolarisError {
-	e.Cause = cause
-	return e
+	c := e.clone()
+	c.Cause = cause
+	return c
 }
 
-// WithContext adds context information
+// WithContext returns a copy of e with the context information added, leaving e, e.g. a
+// sentinel, unchanged
 func (e *PolarisError) WithContext(key string, value any) *PolarisError {
-	e.Context[key] = value
-	return e
+	c := e.clone()
+	c.Context[key] = value
+	return c
+}
+
+// clone returns a copy of e with a context map of its own
+func (e *PolarisError) clone() *PolarisError {
+	c := *e
+	c.Context = make(map[string]any, len(e.Context)+1)
+	maps.Copy(c.Context, e.Context)
+	return &c
 }
 
 // Error implements error interface
@@ -126,12 +166,34 @@ func (e *PolarisError) Unwrap() error {
 	return e.Cause
 }
 
-// Is checks error type
+// Is reports whether target is a *PolarisError with the same code, or with the code that the
+// SDK or context error wrapped by e maps to
 func (e *PolarisError) Is(target error) bool {
-	if targetError, ok := target.(*PolarisError); ok {
-		return e.Code == targetError.Code
+	targetError, ok := target.(*PolarisError)
+	if !ok {
+		return false
 	}
-	return false
+	if e.Code == targetError.Code {
+		return true
+	}
+	var causeErr *PolarisError
+	if e.Cause == nil || errors.As(e.Cause, &causeErr) {
+		// Wrapped Polaris errors are compared by errors.Is itself
+		return false
+	}
+	code, ok := causeErrorCode(e.Cause)
+	return ok && code == targetError.Code
+}
+
+// ErrorCodeOf returns the code of the first *PolarisError in err's chain, or the code that a
+// polaris-go SDK or context error maps to. It returns "" for any other error.
+func ErrorCodeOf(err error) ErrorCode {
+	var polarisErr *PolarisError
+	if errors.As(err, &polarisErr) {
+		return polarisErr.Code
+	}
+	code, _ := causeErrorCode(err)
+	return code
 }
 
 // Convenient error creation functions
@@ -146,6 +208,16 @@ func NewInitError(message string) *PolarisError {
 	return NewPolarisError(ErrCodeInitFailed, message)
 }
 
+// newNotInitializedError creates the error of calls made before the plugin is initialized
+func newNotInitializedError() *PolarisError {
+	return NewPolarisError(ErrCodeNotInitialized, "Polaris plugin not initialized")
+}
+
+// newDestroyedError creates the error of calls made once the plugin is destroyed
+func newDestroyedError() *PolarisError {
+	return NewPolarisError(ErrCodeSDKDestroyed, "Polaris plugin has been destroyed")
+}
+
 // NewServiceError creates service error
 func NewServiceError(code ErrorCode, message string) *PolarisError {
 	return NewPolarisError(code, message)
@@ -178,9 +250,15 @@ func IsConfigError(err error) bool {
 	return isErrorCode(err, ErrCodeConfigInvalid, ErrCodeConfigMissing, ErrCodeConfigValidation)
 }
 
-// IsInitError checks if it's an initialization error
+// IsInitError checks if it's an initialization error, including calls made before the plugin
+// is initialized or once it is destroyed
 func IsInitError(err error) bool {
-	return isErrorCode(err, ErrCodeInitFailed, ErrCodeAlreadyInitialized, ErrCodeNotInitialized)
+	return isErrorCode(err, ErrCodeInitFailed, ErrCodeAlreadyInitialized, ErrCodeNotInitialized, ErrCodeSDKDestroyed)
+}
+
+// IsSubsystemDisabled checks if the error comes from a subsystem disabled by subsystems
+func IsSubsystemDisabled(err error) bool {
+	return isErrorCode(err, ErrCodeSubsystemDisabled)
 }
 
 // IsServiceError checks if it's a service error
@@ -198,9 +276,10 @@ func IsRetryError(err error) bool {
 	return isErrorCode(err, ErrCodeRetryExhausted, ErrCodeCircuitBreakerOpen)
 }
 
-// isErrorCode checks error code
+// isErrorCode checks the code of the first *PolarisError in err's chain
 func isErrorCode(err error, codes ...ErrorCode) bool {
-	if polarisErr, ok := err.(*PolarisError); ok {
+	var polarisErr *PolarisError
+	if errors.As(err, &polarisErr) {
 		for _, code := range codes {
 			if polarisErr.Code == code {
 				return true
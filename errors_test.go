@@ -0,0 +1,74 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolarisError_IsMatchesSentinels(t *testing.T) {
+	err := fmt.Errorf("check orders: %w", NewServiceError(ErrCodeRateLimitExceeded, "rate limit exceeded: quota used"))
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.NotErrorIs(t, err, ErrTimeout)
+
+	var polarisErr *PolarisError
+	require.ErrorAs(t, err, &polarisErr)
+	assert.Equal(t, ErrCodeRateLimitExceeded, polarisErr.Code)
+	assert.Equal(t, ErrCodeRateLimitExceeded, ErrorCodeOf(err))
+}
+
+func TestPolarisError_IsMatchesWrappedSDKErrors(t *testing.T) {
+	tests := []struct {
+		cause    error
+		sentinel error
+	}{
+		{model.NewSDKError(model.ErrCodeAPITimeoutError, nil, "timeout"), ErrTimeout},
+		{context.DeadlineExceeded, ErrTimeout},
+		{model.NewSDKError(model.ErrCodeUnauthorized, nil, "token rejected"), ErrUnauthorized},
+		{model.NewSDKError(model.ErrCodeServiceNotFound, nil, "no orders"), ErrServiceNotFound},
+		{model.NewSDKError(model.ErrCodeConnectError, nil, "dial"), ErrSDKUnavailable},
+	}
+	for _, tt := range tests {
+		err := WrapServiceError(tt.cause, ErrCodeServiceUnavailable, "failed to get service instances")
+		assert.ErrorIs(t, err, tt.sentinel, "%v", tt.cause)
+		assert.ErrorIs(t, err, tt.cause)
+		assert.Equal(t, ErrCodeServiceUnavailable, ErrorCodeOf(err), "the code of the Polaris error comes first")
+	}
+	assert.NotErrorIs(t, WrapServiceError(errors.New("boom"), ErrCodeServiceUnavailable, "failed"), ErrTimeout)
+	assert.Equal(t, ErrCodeUnauthorized, ErrorCodeOf(model.NewSDKError(model.ErrCodeUnauthorized, nil, "token rejected")))
+	assert.Empty(t, ErrorCodeOf(errors.New("boom")))
+}
+
+func TestPolarisError_SentinelsAreNotModified(t *testing.T) {
+	cause := errors.New("deadline")
+	err := ErrTimeout.WithCause(cause).WithContext("service", "orders")
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "orders", err.Context["service"])
+	assert.Nil(t, ErrTimeout.Cause)
+	assert.Empty(t, ErrTimeout.Context)
+
+	base := NewServiceError(ErrCodeServiceUnavailable, "unavailable").WithContext("service", "orders")
+	other := base.WithContext("service", "payments")
+	assert.Equal(t, "orders", base.Context["service"], "copies do not share their context")
+	assert.Equal(t, "payments", other.Context["service"])
+}
+
+func TestLifecycleErrors(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	err := plugin.checkLifecycle()
+	assert.ErrorIs(t, err, ErrNotInitialized)
+	assert.True(t, IsInitError(err))
+
+	plugin.setInitialized()
+	atomic.StoreInt32(&plugin.destroyed, 1)
+	err = plugin.checkLifecycle()
+	assert.ErrorIs(t, err, ErrDestroyed)
+	assert.True(t, IsInitError(fmt.Errorf("get instances: %w", err)), "wrapped errors are classified too")
+}
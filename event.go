@@ -14,7 +14,7 @@ func (p *PlugPolaris) handleServiceInstancesChanged(serviceName string, instance
 	// TOCTOU data race against concurrent cleanup.
 	p.mu.RLock()
 	destroyed := p.IsDestroyed()
-	conf := p.conf
+	conf := p.currentConf()
 	metrics := p.metrics
 	p.mu.RUnlock()
 	if destroyed || conf == nil {
@@ -33,7 +33,7 @@ func (p *PlugPolaris) handleServiceInstancesChanged(serviceName string, instance
 	// 2. Record audit logs
 	p.recordServiceChangeAudit(serviceName, instances)
 
-	// 3. Notify related components
+	// 3. Notify subscribers and notifiers
 	p.notifyServiceChange(serviceName, instances)
 
 	// 4. Trigger load balancer update
@@ -52,7 +52,7 @@ func (p *PlugPolaris) handleServiceWatchError(serviceName string, err error) {
 	if destroyed {
 		return
 	}
-	log.Errorf("Service %s watch error: %v", serviceName, err)
+	log.Errorf("Service %s watch error: %v", serviceName, p.redactError(err))
 
 	// Record error metrics
 	if metrics != nil {
@@ -68,51 +68,34 @@ func (p *PlugPolaris) handleServiceWatchError(serviceName string, err error) {
 	// 3. Try degradation handling
 	p.handleServiceWatchDegradation(serviceName, err)
 
-	// 4. Start retry mechanism (deduplicated: only one retry goroutine per service)
-	if p.tryStartServiceWatchRetry(serviceName) {
-		go p.retryServiceWatch(serviceName)
+	// 4. Schedule a retry (deduplicated: only one retry per service)
+	if ctx, retry, ok := p.tryStartServiceWatchRetry(p.serviceWatcherContext(serviceName), serviceName); ok {
+		if metrics != nil {
+			metrics.RecordWatcherRetry(watcherTypeService, serviceName)
+		}
+		log.Infof("Retrying service watch for %s", serviceName)
+		p.scheduleWatchRetry(ctx, func(canceled bool) { p.retryServiceWatch(serviceName, retry, canceled) })
 	}
 }
 
-// notifyServiceChange notifies service changes
+// notifyServiceChange publishes the new instance set of a service to channel subscribers
+// and notifiers
 func (p *PlugPolaris) notifyServiceChange(serviceName string, instances []model.Instance) {
-	if p.conf == nil {
-		return
-	}
-	// Implement notification logic
-	notification := map[string]any{
-		"event_type":      "service_change",
-		"service_name":    serviceName,
-		"namespace":       p.conf.Namespace,
-		"instance_count":  len(instances),
-		"timestamp":       time.Now().Unix(),
-		"healthy_count":   0,
-		"unhealthy_count": 0,
-	}
-
-	// Count health status
-	for _, instance := range instances {
-		if instance == nil {
-			continue
-		}
-		if instance.IsHealthy() {
-			if count, ok := notification["healthy_count"].(int); ok {
-				notification["healthy_count"] = count + 1
-			}
-		} else {
-			if count, ok := notification["unhealthy_count"].(int); ok {
-				notification["unhealthy_count"] = count + 1
-			}
+	snapshots := newInstanceSnapshots(instances)
+	healthy := 0
+	for _, s := range snapshots {
+		if s.Healthy && !s.Isolated {
+			healthy++
 		}
 	}
-
-	// Here you can integrate specific notification implementations, such as:
-	// 1. Send to message queue (Kafka, RabbitMQ, etc.)
-	// 2. Send Webhook notifications
-	// 3. Send to event bus
-	// 4. Send to monitoring system
-
-	log.Infof("Service change notification: %+v", notification)
+	p.publishEvent(&ServiceChangedEvent{
+		Kind:         EventTypeServiceChanged,
+		Service:      serviceName,
+		Namespace:    p.currentConf().GetNamespace(),
+		Instances:    snapshots,
+		HealthyCount: healthy,
+		Timestamp:    time.Now(),
+	})
 }
 
 // handleConfigChanged handles configuration change events
@@ -121,7 +104,7 @@ func (p *PlugPolaris) handleConfigChanged(fileName, group string, config model.C
 	// TOCTOU data race against concurrent cleanup.
 	p.mu.RLock()
 	destroyed := p.IsDestroyed()
-	conf := p.conf
+	conf := p.currentConf()
 	metrics := p.metrics
 	p.mu.RUnlock()
 	if destroyed || conf == nil {
@@ -134,20 +117,29 @@ func (p *PlugPolaris) handleConfigChanged(fileName, group string, config model.C
 		metrics.RecordConfigChange(fileName, group)
 	}
 
+	// Validate the new content before anything applies it
+	if err := p.validateConfigChange(fileName, group, config); err != nil {
+		log.Errorf("Rejected config change %s:%s, keeping last good content: %v", fileName, group, p.redactError(err))
+		return
+	}
+
+	// Diff against the cached content before the cache is replaced
+	oldContent, _ := p.cachedConfigContent(fileName, group)
+	change := newConfigChange(fileName, group, conf.Namespace, oldContent, config.GetContent())
+
 	// 1. Record configuration change audit logs
-	p.recordConfigChangeAudit(fileName, group, config)
+	p.recordConfigChangeAudit(change)
 
-	// 2. Update configuration cache
+	// 2. Update configuration cache and freshness
 	p.updateConfigCache(fileName, group, config)
+	p.recordConfigFetch(fileName, group, config.GetContent())
+	p.saveConfigSnapshot(conf.Namespace, group, fileName, config.GetContent())
 
-	// 3. Notify configuration changes
-	p.notifyConfigChange(fileName, group, config)
-
-	// 4. Trigger configuration hot reload
+	// 3. Trigger configuration hot reload
 	p.triggerConfigReload(fileName, group, config)
 
-	// 5. Validate configuration validity
-	p.validateConfigChange(fileName, group, config)
+	// 4. Notify subscribers and notifiers
+	p.notifyConfigChange(change)
 }
 
 // handleConfigWatchError handles configuration watch error events
@@ -159,7 +151,7 @@ func (p *PlugPolaris) handleConfigWatchError(fileName, group string, err error)
 	if destroyed {
 		return
 	}
-	log.Errorf("Config %s:%s watch error: %v", fileName, group, err)
+	log.Errorf("Config %s:%s watch error: %v", fileName, group, p.redactError(err))
 
 	// Record error metrics
 	if metrics != nil {
@@ -175,96 +167,78 @@ func (p *PlugPolaris) handleConfigWatchError(fileName, group string, err error)
 	// 3. Try degradation handling
 	p.handleConfigWatchDegradation(fileName, group, err)
 
-	// 4. Start retry mechanism (deduplicated: only one retry goroutine per config)
+	// 4. Schedule a retry (deduplicated: only one retry per config)
 	configKey := fmt.Sprintf("%s:%s", fileName, group)
-	if p.tryStartConfigWatchRetry(configKey) {
-		go p.retryConfigWatch(fileName, group)
+	if ctx, retry, ok := p.tryStartConfigWatchRetry(p.configWatcherContext(configKey), configKey); ok {
+		if metrics != nil {
+			metrics.RecordWatcherRetry(watcherTypeConfig, configKey)
+		}
+		log.Infof("Retrying config watch for %s:%s", fileName, group)
+		p.scheduleWatchRetry(ctx, func(canceled bool) { p.retryConfigWatch(fileName, group, retry, canceled) })
 	}
 }
 
-// notifyConfigChange notifies configuration changes
-func (p *PlugPolaris) notifyConfigChange(fileName, group string, config model.ConfigFile) {
-	if p.conf == nil {
-		return
-	}
-	// Implement notification logic
-	notification := map[string]any{
-		"event_type":     "config_change",
-		"config_file":    fileName,
-		"group":          group,
-		"namespace":      p.conf.Namespace,
-		"content_length": len(config.GetContent()),
-		"timestamp":      time.Now().Unix(),
-	}
-
-	// Here you can integrate specific notification implementations, such as:
-	// 1. Send to message queue (Kafka, RabbitMQ, etc.)
-	// 2. Send Webhook notifications
-	// 3. Send to event bus
-	// 4. Send to monitoring system
-
-	log.Infof("Config change notification: %+v", notification)
+// notifyConfigChange publishes a config file change to channel subscribers and notifiers
+func (p *PlugPolaris) notifyConfigChange(change ConfigChange) {
+	p.publishEvent(&ConfigChangedEvent{
+		Kind:                  EventTypeConfigChanged,
+		FileName:              change.FileName,
+		Group:                 change.Group,
+		Namespace:             change.Namespace,
+		ContentLength:         len(change.NewContent),
+		PreviousContentLength: len(change.OldContent),
+		Diff:                  change.Diff,
+		Timestamp:             time.Now(),
+	})
 }
 
 // triggerConfigReload triggers configuration reload
 func (p *PlugPolaris) triggerConfigReload(fileName, group string, config model.ConfigFile) {
-	if p.conf == nil {
+	if p.currentConf() == nil || config == nil {
 		return
 	}
-	// Implement configuration hot reload logic
-	reloadInfo := map[string]any{
-		"config_file":    fileName,
-		"group":          group,
-		"namespace":      p.conf.Namespace,
-		"content_length": len(config.GetContent()),
-		"reload_type":    "hot_reload",
-		"timestamp":      time.Now().Unix(),
+	handlers := p.reloadHandlersFor(fileName, group)
+	if len(handlers) == 0 {
+		log.Debugf("No reload handlers registered for config %s:%s", fileName, group)
+		return
 	}
-
-	// Here you can integrate specific configuration hot reload implementations, such as:
-	// 1. Notify application to reload configuration
-	// 2. Update configuration in memory
-	// 3. Trigger configuration change events
-	// 4. Reinitialize related components
-
-	log.Infof("Config reload triggered: %+v", reloadInfo)
+	p.dispatchConfigReload(fileName, group, config.GetContent(), handlers)
 }
 
-// validateConfigChange validates configuration changes
-func (p *PlugPolaris) validateConfigChange(fileName, group string, config model.ConfigFile) {
+// validateConfigChange validates configuration changes with the validators registered
+// through RegisterConfigValidator. An error means the change must not be applied.
+func (p *PlugPolaris) validateConfigChange(fileName, group string, config model.ConfigFile) error {
 	if config == nil {
-		return
+		return nil
 	}
 	content := config.GetContent()
 
 	// Basic validation
 	if len(content) == 0 {
 		log.Warnf("Config %s:%s has empty content", fileName, group)
-		return
 	}
 
-	// Here you can add more complex validation logic, such as:
-	// 1. Validate JSON/YAML format
-	// 2. Validate configuration item validity
-	// 3. Validate configuration completeness
-	// 4. Validate configuration security
-
+	if err := p.checkConfigContent(fileName, group, content); err != nil {
+		return err
+	}
 	log.Infof("Config %s:%s validation passed, content length: %d", fileName, group, len(content))
+	return nil
 }
 
 // handleServiceWatchDegradation handles service watch degradation
 func (p *PlugPolaris) handleServiceWatchDegradation(serviceName string, err error) {
-	if p.conf == nil {
+	cfg := p.currentConf()
+	if cfg == nil {
 		return
 	}
 	// Implement degradation handling logic
-	log.Warnf("Service watch degradation for %s: %v", serviceName, err)
+	log.Warnf("Service watch degradation for %s: %v", serviceName, p.redactError(err))
 
 	// Build degradation information
 	degradationInfo := map[string]any{
 		"service_name":      serviceName,
-		"namespace":         p.conf.Namespace,
-		"error":             err.Error(),
+		"namespace":         cfg.Namespace,
+		"error":             p.redactError(err),
 		"degradation_type":  "service_watch_failure",
 		"timestamp":         time.Now().Unix(),
 		"fallback_strategy": "cache_only",
@@ -279,27 +253,54 @@ func (p *PlugPolaris) handleServiceWatchDegradation(serviceName string, err erro
 
 	// 3. Notify related components to enter degradation mode
 	p.notifyDegradationMode(serviceName, degradationInfo)
+	p.publishDegradation(&DegradationEvent{
+		Kind:             EventTypeDegradation,
+		DegradationType:  "service_watch_failure",
+		Service:          serviceName,
+		Namespace:        cfg.Namespace,
+		Error:            err.Error(),
+		FallbackStrategy: "cache_only",
+		Timestamp:        time.Now(),
+	})
 
 	log.Warnf("Service degradation activated: %+v", degradationInfo)
 }
 
 // handleConfigWatchDegradation handles configuration watch degradation
 func (p *PlugPolaris) handleConfigWatchDegradation(fileName, group string, err error) {
-	if p.conf == nil {
+	cfg := p.currentConf()
+	if cfg == nil {
 		return
 	}
-	log.Warnf("Config watch degradation for %s:%s: %v", fileName, group, err)
+	log.Warnf("Config watch degradation for %s:%s: %v", fileName, group, p.redactError(err))
+
+	// Prefer the local snapshot when one is available
+	fallbackStrategy := "cache_only"
+	if _, snapshotErr := p.loadConfigSnapshot(cfg.Namespace, group, fileName); snapshotErr == nil {
+		fallbackStrategy = "local_snapshot"
+	}
 
 	// Implement degradation handling logic
 	degradationInfo := map[string]any{
 		"config_file":       fileName,
 		"group":             group,
-		"namespace":         p.conf.Namespace,
-		"error":             err.Error(),
+		"namespace":         cfg.Namespace,
+		"error":             p.redactError(err),
 		"degradation_type":  "config_watch_failure",
 		"timestamp":         time.Now().Unix(),
-		"fallback_strategy": "cache_only",
+		"fallback_strategy": fallbackStrategy,
 	}
 
+	p.publishDegradation(&DegradationEvent{
+		Kind:             EventTypeDegradation,
+		DegradationType:  "config_watch_failure",
+		FileName:         fileName,
+		Group:            group,
+		Namespace:        cfg.Namespace,
+		Error:            err.Error(),
+		FallbackStrategy: fallbackStrategy,
+		Timestamp:        time.Now(),
+	})
+
 	log.Warnf("Config degradation activated: %+v", degradationInfo)
 }
@@ -0,0 +1,469 @@
+package polaris
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// EventBus module
+// Responsibility: typed plugin events and a Go-channel based subscription model
+// for modules that prefer consuming events over registering callbacks.
+
+// EventType identifies the kind of a plugin event.
+type EventType string
+
+// Event type constants. The string values are part of the JSON schema and must stay stable.
+const (
+	EventTypeServiceChanged EventType = "service_changed"
+	EventTypeConfigChanged  EventType = "config_changed"
+	EventTypeDegradation    EventType = "degradation"
+	EventTypeHealthChanged  EventType = "health_changed"
+	EventTypeConfigReloaded EventType = "config_reloaded"
+	// EventTypeCircuitBreakerChanged is published on every state transition of the plugin's
+	// circuit breaker
+	EventTypeCircuitBreakerChanged EventType = "circuit_breaker_changed"
+)
+
+// defaultSubscriptionBuffer is the channel capacity handed out to each subscriber.
+const defaultSubscriptionBuffer = 64
+
+// Event is implemented by every typed plugin event.
+type Event interface {
+	// Type returns the event type used for subscription filtering.
+	Type() EventType
+	// OccurredAt returns the time the event was produced.
+	OccurredAt() time.Time
+}
+
+// InstanceSnapshot is an immutable, JSON-friendly view of a Polaris instance.
+type InstanceSnapshot struct {
+	ID       string            `json:"id"`
+	Host     string            `json:"host"`
+	Port     uint32            `json:"port"`
+	Protocol string            `json:"protocol,omitempty"`
+	Version  string            `json:"version,omitempty"`
+	Weight   int               `json:"weight"`
+	Healthy  bool              `json:"healthy"`
+	Isolated bool              `json:"isolated"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ServiceChangedEvent is published when the instance set of a watched service changes.
+type ServiceChangedEvent struct {
+	Kind         EventType          `json:"type"`
+	Service      string             `json:"service"`
+	Namespace    string             `json:"namespace"`
+	Instances    []InstanceSnapshot `json:"instances"`
+	HealthyCount int                `json:"healthy_count"`
+	Timestamp    time.Time          `json:"timestamp"`
+}
+
+// Type implements Event.
+func (e *ServiceChangedEvent) Type() EventType { return EventTypeServiceChanged }
+
+// OccurredAt implements Event.
+func (e *ServiceChangedEvent) OccurredAt() time.Time { return e.Timestamp }
+
+// ConfigChangedEvent is published when the content of a watched config file changes.
+type ConfigChangedEvent struct {
+	Kind          EventType `json:"type"`
+	FileName      string    `json:"file_name"`
+	Group         string    `json:"group"`
+	Namespace     string    `json:"namespace"`
+	ContentLength int       `json:"content_length"`
+	// PreviousContentLength is the length of the cached content before the change.
+	PreviousContentLength int `json:"previous_content_length"`
+	// Diff lists the lines removed and added by the change.
+	Diff []ConfigDiffLine `json:"diff,omitempty"`
+	// Redacted is set when the file matches sensitive_config_files and Diff is left out.
+	Redacted  bool      `json:"redacted,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Type implements Event.
+func (e *ConfigChangedEvent) Type() EventType { return EventTypeConfigChanged }
+
+// OccurredAt implements Event.
+func (e *ConfigChangedEvent) OccurredAt() time.Time { return e.Timestamp }
+
+// DegradationEvent is published when a watcher fails and the plugin enters a fallback mode.
+type DegradationEvent struct {
+	Kind             EventType `json:"type"`
+	DegradationType  string    `json:"degradation_type"`
+	Service          string    `json:"service,omitempty"`
+	FileName         string    `json:"file_name,omitempty"`
+	Group            string    `json:"group,omitempty"`
+	Namespace        string    `json:"namespace"`
+	Error            string    `json:"error"`
+	FallbackStrategy string    `json:"fallback_strategy"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// Type implements Event.
+func (e *DegradationEvent) Type() EventType { return EventTypeDegradation }
+
+// OccurredAt implements Event.
+func (e *DegradationEvent) OccurredAt() time.Time { return e.Timestamp }
+
+// HealthChangedEvent is published when the plugin health status flips.
+type HealthChangedEvent struct {
+	Kind      EventType `json:"type"`
+	Healthy   bool      `json:"healthy"`
+	Previous  bool      `json:"previous"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Type implements Event.
+func (e *HealthChangedEvent) Type() EventType { return EventTypeHealthChanged }
+
+// OccurredAt implements Event.
+func (e *HealthChangedEvent) OccurredAt() time.Time { return e.Timestamp }
+
+// ConfigReloadedEvent is published after the reload handlers of a changed config file ran.
+type ConfigReloadedEvent struct {
+	Kind      EventType `json:"type"`
+	FileName  string    `json:"file_name"`
+	Group     string    `json:"group"`
+	Namespace string    `json:"namespace"`
+	Handlers  int       `json:"handlers"`
+	// Errors holds the failures by handler name; it is empty when every handler succeeded.
+	Errors    map[string]string `json:"errors,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// Type implements Event.
+func (e *ConfigReloadedEvent) Type() EventType { return EventTypeConfigReloaded }
+
+// OccurredAt implements Event.
+func (e *ConfigReloadedEvent) OccurredAt() time.Time { return e.Timestamp }
+
+// CircuitBreakerChangedEvent is published when a circuit breaker of the plugin changes state.
+type CircuitBreakerChangedEvent struct {
+	Kind EventType `json:"type"`
+	// Breaker is the key the breaker is registered under, e.g. PluginCircuitBreakerKey
+	Breaker   string    `json:"breaker"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Type implements Event.
+func (e *CircuitBreakerChangedEvent) Type() EventType { return EventTypeCircuitBreakerChanged }
+
+// OccurredAt implements Event.
+func (e *CircuitBreakerChangedEvent) OccurredAt() time.Time { return e.Timestamp }
+
+// newInstanceSnapshots converts SDK instances into snapshots, skipping nil entries.
+func newInstanceSnapshots(instances []model.Instance) []InstanceSnapshot {
+	snapshots := make([]InstanceSnapshot, 0, len(instances))
+	for _, inst := range instances {
+		if inst == nil {
+			continue
+		}
+		var metadata map[string]string
+		if md := inst.GetMetadata(); len(md) > 0 {
+			metadata = make(map[string]string, len(md))
+			for k, v := range md {
+				metadata[k] = v
+			}
+		}
+		snapshots = append(snapshots, InstanceSnapshot{
+			ID:       inst.GetId(),
+			Host:     inst.GetHost(),
+			Port:     inst.GetPort(),
+			Protocol: inst.GetProtocol(),
+			Version:  inst.GetVersion(),
+			Weight:   inst.GetWeight(),
+			Healthy:  inst.IsHealthy(),
+			Isolated: inst.IsIsolated(),
+			Metadata: metadata,
+		})
+	}
+	return snapshots
+}
+
+// isKnownEventType reports whether t is one of the published event types.
+func isKnownEventType(t EventType) bool {
+	switch t {
+	case EventTypeServiceChanged, EventTypeConfigChanged, EventTypeDegradation, EventTypeHealthChanged,
+		EventTypeConfigReloaded, EventTypeCircuitBreakerChanged:
+		return true
+	}
+	return false
+}
+
+// EventFilter selects the events of a subscription
+type EventFilter struct {
+	// Types are the event types delivered, all types when empty
+	Types []EventType
+	// Subjects are the services, file:group config keys and circuit breaker keys whose
+	// events are delivered, all when empty
+	Subjects []string
+	// Replay is the number of buffered past events matching the filter delivered first,
+	// oldest first; a negative value replays every buffered event
+	Replay int
+}
+
+// eventSubscriber is a single channel subscription with optional type and subject filters.
+type eventSubscriber struct {
+	ch       chan Event
+	types    map[EventType]struct{}
+	subjects map[string]struct{}
+}
+
+func (s *eventSubscriber) accepts(event Event) bool {
+	if len(s.types) > 0 {
+		if _, ok := s.types[event.Type()]; !ok {
+			return false
+		}
+	}
+	if len(s.subjects) > 0 {
+		if _, ok := s.subjects[eventSubject(event)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// eventBus fans out plugin events to channel subscribers and keeps the last events for
+// replay. Publishing never blocks: events are dropped for subscribers whose buffer is full.
+type eventBus struct {
+	mu          sync.RWMutex
+	subscribers map[uint64]*eventSubscriber
+	nextID      uint64
+	closed      bool
+	done        chan struct{}
+	dropped     uint64
+
+	// history is a ring buffer of the last historySize events, starting at historyStart
+	history      []Event
+	historyStart int
+	historySize  int
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{
+		subscribers: make(map[uint64]*eventSubscriber),
+		done:        make(chan struct{}),
+		historySize: conf.DefaultEventHistorySize,
+	}
+}
+
+// setHistorySize resizes the replay buffer, keeping the most recent events. Zero or a
+// negative size disables replay.
+func (b *eventBus) setHistorySize(size int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	events := b.historyLocked()
+	if len(events) > max(size, 0) {
+		events = events[len(events)-max(size, 0):]
+	}
+	b.history, b.historyStart, b.historySize = events, 0, size
+}
+
+// recordLocked adds event to the replay buffer, replacing the oldest event when it is full.
+func (b *eventBus) recordLocked(event Event) {
+	if b.historySize <= 0 {
+		return
+	}
+	if len(b.history) < b.historySize {
+		b.history = append(b.history, event)
+		return
+	}
+	b.history[b.historyStart] = event
+	b.historyStart = (b.historyStart + 1) % len(b.history)
+}
+
+// historyLocked returns the buffered events, oldest first.
+func (b *eventBus) historyLocked() []Event {
+	events := make([]Event, 0, len(b.history))
+	events = append(events, b.history[b.historyStart:]...)
+	return append(events, b.history[:b.historyStart]...)
+}
+
+// subscribe registers a subscriber that is removed (and its channel closed) when ctx is done.
+func (b *eventBus) subscribe(ctx context.Context, eventTypes ...EventType) (<-chan Event, error) {
+	ch, _, err := b.subscribeFilter(ctx, EventFilter{Types: eventTypes})
+	return ch, err
+}
+
+// subscribeFilter registers a subscriber of the events matching filter, sending it the
+// replayed events first, and returns its channel and ID. The subscriber is removed (and its
+// channel closed) when ctx is done.
+func (b *eventBus) subscribeFilter(ctx context.Context, filter EventFilter) (<-chan Event, uint64, error) {
+	if ctx == nil {
+		return nil, 0, fmt.Errorf("subscribe context is nil")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	sub := &eventSubscriber{types: make(map[EventType]struct{}, len(filter.Types))}
+	for _, t := range filter.Types {
+		if !isKnownEventType(t) {
+			return nil, 0, fmt.Errorf("unknown event type: %s", t)
+		}
+		sub.types[t] = struct{}{}
+	}
+	if len(filter.Subjects) > 0 {
+		sub.subjects = make(map[string]struct{}, len(filter.Subjects))
+		for _, subject := range filter.Subjects {
+			sub.subjects[subject] = struct{}{}
+		}
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil, 0, newDestroyedError()
+	}
+	var replay []Event
+	if filter.Replay != 0 {
+		for _, event := range b.historyLocked() {
+			if sub.accepts(event) {
+				replay = append(replay, event)
+			}
+		}
+		if filter.Replay > 0 && len(replay) > filter.Replay {
+			replay = replay[len(replay)-filter.Replay:]
+		}
+	}
+	// Replayed events are sent while the lock is held, so they precede new events
+	sub.ch = make(chan Event, defaultSubscriptionBuffer+len(replay))
+	for _, event := range replay {
+		sub.ch <- event
+	}
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = sub
+	b.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			b.unsubscribe(id)
+		case <-b.done:
+		}
+	}()
+
+	return sub.ch, id, nil
+}
+
+// unsubscribe removes a subscriber and closes its channel.
+func (b *eventBus) unsubscribe(id uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if sub, ok := b.subscribers[id]; ok {
+		delete(b.subscribers, id)
+		close(sub.ch)
+	}
+}
+
+// publish delivers the event to all matching subscribers without blocking.
+func (b *eventBus) publish(event Event) {
+	if event == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.recordLocked(event)
+	for _, sub := range b.subscribers {
+		if !sub.accepts(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			b.dropped++
+			log.Warnf("Dropping %s event for slow subscriber (total dropped: %d)", event.Type(), b.dropped)
+		}
+	}
+}
+
+// close closes every subscriber channel and rejects new subscriptions.
+func (b *eventBus) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	close(b.done)
+	for id, sub := range b.subscribers {
+		delete(b.subscribers, id)
+		close(sub.ch)
+	}
+}
+
+// reopen lets a closed bus accept subscribers again, keeping its history.
+func (b *eventBus) reopen() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		return
+	}
+	b.closed = false
+	b.done = make(chan struct{})
+}
+
+// Subscribe returns a channel that receives plugin events of the given types
+// (all types when none are given). The channel is closed when ctx is done or the
+// plugin is destroyed. Slow consumers lose events instead of blocking the plugin.
+func (p *PlugPolaris) Subscribe(ctx context.Context, eventTypes ...EventType) (<-chan Event, error) {
+	if p.IsDestroyed() {
+		return nil, newDestroyedError()
+	}
+	return p.events.subscribe(ctx, eventTypes...)
+}
+
+// SubscribeEvents returns a channel receiving the plugin events matching filter, starting
+// with up to filter.Replay buffered past events so late subscribers catch up, and a function
+// ending the subscription and closing the channel. The buffer keeps the last
+// event_history_size events. The channel is closed when the plugin is destroyed, and right
+// away when the plugin is already destroyed or the filter has an unknown event type.
+func (p *PlugPolaris) SubscribeEvents(filter EventFilter) (<-chan Event, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, id, err := p.events.subscribeFilter(ctx, filter)
+	if err != nil {
+		cancel()
+		log.Warnf("Event subscription rejected: %v", err)
+		closed := make(chan Event)
+		close(closed)
+		return closed, func() {}
+	}
+	return ch, func() {
+		cancel()
+		p.events.unsubscribe(id)
+	}
+}
+
+// publishBreakerTransitions publishes the state transitions of the breaker registered
+// under name.
+func (p *PlugPolaris) publishBreakerTransitions(name string, cb *CircuitBreaker) {
+	cb.OnStateChange(func(from, to CircuitState) {
+		p.publishEvent(&CircuitBreakerChangedEvent{
+			Kind:      EventTypeCircuitBreakerChanged,
+			Breaker:   name,
+			From:      from.String(),
+			To:        to.String(),
+			Timestamp: time.Now(),
+		})
+	})
+}
+
+// publishEvent delivers an event to channel subscribers.
+func (p *PlugPolaris) publishEvent(event Event) {
+	if p.events == nil {
+		return
+	}
+	p.events.publish(p.redactEvent(event))
+}
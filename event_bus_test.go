@@ -0,0 +1,173 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBus_SubscribeFiltersByType(t *testing.T) {
+	bus := newEventBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := bus.subscribe(ctx, EventTypeConfigChanged)
+	require.NoError(t, err)
+
+	bus.publish(&ServiceChangedEvent{Kind: EventTypeServiceChanged, Service: "svc"})
+	bus.publish(&ConfigChangedEvent{Kind: EventTypeConfigChanged, FileName: "app.yaml"})
+
+	select {
+	case ev := <-ch:
+		cfg, ok := ev.(*ConfigChangedEvent)
+		require.True(t, ok)
+		assert.Equal(t, "app.yaml", cfg.FileName)
+	case <-time.After(time.Second):
+		t.Fatal("expected config event")
+	}
+	assert.Len(t, ch, 0)
+}
+
+func TestEventBus_ContextCancelClosesChannel(t *testing.T) {
+	bus := newEventBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := bus.subscribe(ctx)
+	require.NoError(t, err)
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed after context cancel")
+	}
+}
+
+func TestEventBus_CloseRejectsNewSubscriptions(t *testing.T) {
+	bus := newEventBus()
+	ch, err := bus.subscribe(context.Background())
+	require.NoError(t, err)
+
+	bus.close()
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	_, err = bus.subscribe(context.Background())
+	assert.Error(t, err)
+	bus.publish(&HealthChangedEvent{Kind: EventTypeHealthChanged}) // must not panic
+}
+
+func TestEventBus_UnknownType(t *testing.T) {
+	bus := newEventBus()
+	_, err := bus.subscribe(context.Background(), EventType("bogus"))
+	assert.Error(t, err)
+}
+
+func TestEventBus_SlowSubscriberDoesNotBlock(t *testing.T) {
+	bus := newEventBus()
+	_, err := bus.subscribe(context.Background())
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < defaultSubscriptionBuffer*2; i++ {
+			bus.publish(&HealthChangedEvent{Kind: EventTypeHealthChanged})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publish blocked on a full subscriber")
+	}
+}
+
+func TestEvent_JSONSchema(t *testing.T) {
+	ev := &DegradationEvent{
+		Kind:             EventTypeDegradation,
+		DegradationType:  "service_watch_failure",
+		Service:          "svc",
+		Namespace:        "default",
+		Error:            "boom",
+		FallbackStrategy: "cache_only",
+		Timestamp:        time.Unix(0, 0).UTC(),
+	}
+	data, err := json.Marshal(ev)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"degradation","degradation_type":"service_watch_failure","service":"svc","namespace":"default","error":"boom","fallback_strategy":"cache_only","timestamp":"1970-01-01T00:00:00Z"}`, string(data))
+}
+
+func TestPlugin_HealthTransitionPublishesEvent(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	ch, err := plugin.Subscribe(context.Background(), EventTypeHealthChanged)
+	require.NoError(t, err)
+
+	plugin.recordHealthTransition(nil)            // baseline, no event
+	plugin.recordHealthTransition(assert.AnError) // healthy -> unhealthy
+
+	select {
+	case ev := <-ch:
+		hc := ev.(*HealthChangedEvent)
+		assert.False(t, hc.Healthy)
+		assert.True(t, hc.Previous)
+		assert.NotEmpty(t, hc.Error)
+	case <-time.After(time.Second):
+		t.Fatal("expected health changed event")
+	}
+}
+
+func TestEventBus_Replay(t *testing.T) {
+	bus := newEventBus()
+	bus.setHistorySize(3)
+	for _, svc := range []string{"a", "b", "orders", "c"} {
+		bus.publish(&ServiceChangedEvent{Kind: EventTypeServiceChanged, Service: svc})
+	}
+	bus.publish(&ConfigChangedEvent{Kind: EventTypeConfigChanged, FileName: "app.yaml", Group: "orders"})
+
+	// The buffer keeps the last 3 events, oldest first
+	ch, _, err := bus.subscribeFilter(context.Background(), EventFilter{Types: []EventType{EventTypeServiceChanged}, Replay: -1})
+	require.NoError(t, err)
+	require.Len(t, ch, 2)
+	assert.Equal(t, "orders", (<-ch).(*ServiceChangedEvent).Service)
+	assert.Equal(t, "c", (<-ch).(*ServiceChangedEvent).Service)
+
+	ch, _, err = bus.subscribeFilter(context.Background(), EventFilter{Replay: 1})
+	require.NoError(t, err)
+	require.Len(t, ch, 1)
+	assert.IsType(t, &ConfigChangedEvent{}, <-ch)
+
+	bus.setHistorySize(0)
+	ch, _, err = bus.subscribeFilter(context.Background(), EventFilter{Replay: -1})
+	require.NoError(t, err)
+	assert.Len(t, ch, 0)
+}
+
+func TestPlugin_SubscribeEvents(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	cb := NewCircuitBreaker(0.5, time.Minute)
+	plugin.publishBreakerTransitions(PluginCircuitBreakerKey, cb)
+	cb.ForceOpen()
+	plugin.publishEvent(&ServiceChangedEvent{Kind: EventTypeServiceChanged, Service: "orders"})
+
+	events, unsubscribe := plugin.SubscribeEvents(EventFilter{Subjects: []string{PluginCircuitBreakerKey}, Replay: -1})
+	select {
+	case ev := <-events:
+		changed, ok := ev.(*CircuitBreakerChangedEvent)
+		require.True(t, ok, "only breaker events are delivered")
+		assert.Equal(t, "closed", changed.From)
+		assert.Equal(t, "open", changed.To)
+	case <-time.After(time.Second):
+		t.Fatal("breaker transition not replayed")
+	}
+	unsubscribe()
+	_, open := <-events
+	assert.False(t, open)
+
+	events, _ = plugin.SubscribeEvents(EventFilter{Types: []EventType{"bogus"}})
+	_, open = <-events
+	assert.False(t, open)
+}
@@ -0,0 +1,210 @@
+package polaris
+
+import (
+	"cmp"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+// Quota results of fakeClients, shared so that quota checks do not allocate
+var (
+	fakeQuotaAllowed = model.QuotaFutureWithResponse(&model.QuotaResponse{Code: model.QuotaResultOk})
+	fakeQuotaLimited = model.QuotaFutureWithResponse(&model.QuotaResponse{Code: model.QuotaResultLimited})
+)
+
+// fakeClients is the in-memory consumer, provider, config and limit client of the tests.
+// The zero value serves no instances and empty config files, and accepts every call.
+type fakeClients struct {
+	mu sync.Mutex
+
+	// instances are served for every service, unless services is set; then the services
+	// it has no entry for are not found
+	instances []model.Instance
+	services  map[string][]model.Instance
+	// config is the content of every config file
+	config string
+	// err fails the instance and config calls
+	err error
+	// release, when set, blocks the instance and config calls until it is closed
+	release chan struct{}
+
+	// failPort fails the registrations of the port, registerFailures the next registrations
+	failPort         int
+	registerFailures int
+	failHeartbeats   bool
+
+	// last is the last instance request and lastLabels, when set, receives the labels of
+	// the last quota request, of which those with the value "blocked" are limited
+	last       *api.GetInstancesRequest
+	lastLabels map[string]string
+
+	instanceCalls atomic.Int32
+	configCalls   atomic.Int32
+	registered    []*api.InstanceRegisterRequest
+	deregistered  []*api.InstanceDeRegisterRequest
+	heartbeats    []*api.InstanceHeartbeatRequest
+}
+
+// options returns the options making a plugin use c for every client.
+func (c *fakeClients) options() []Option {
+	return []Option{WithConsumerClient(c), WithProviderClient(c), WithConfigClient(c), WithLimitClient(c)}
+}
+
+// wait blocks until release is closed, when it is set, and returns err.
+func (c *fakeClients) wait() error {
+	if c.release != nil {
+		<-c.release
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *fakeClients) serviceInstances(service string) ([]model.Instance, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.services == nil {
+		return c.instances, nil
+	}
+	instances, ok := c.services[service]
+	if !ok {
+		return nil, NewServiceError(ErrCodeServiceNotFound, "service not found")
+	}
+	return instances, nil
+}
+
+func (c *fakeClients) GetOneInstance(req *api.GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	resp, err := c.GetInstances(&api.GetInstancesRequest{GetInstancesRequest: model.GetInstancesRequest{
+		Namespace: req.Namespace, Service: req.Service,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Instances) == 0 {
+		return nil, NewServiceError(ErrCodeServiceNotFound, "no instance")
+	}
+	return &model.OneInstanceResponse{InstancesResponse: model.InstancesResponse{Instances: resp.Instances[:1]}}, nil
+}
+
+func (c *fakeClients) GetInstances(req *api.GetInstancesRequest) (*model.InstancesResponse, error) {
+	c.instanceCalls.Add(1)
+	c.mu.Lock()
+	c.last = req
+	c.mu.Unlock()
+	if err := c.wait(); err != nil {
+		return nil, err
+	}
+	instances, err := c.serviceInstances(req.Service)
+	if err != nil {
+		return nil, err
+	}
+	return &model.InstancesResponse{Instances: instances}, nil
+}
+
+func (c *fakeClients) GetAllInstances(req *api.GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	return c.GetInstances(&api.GetInstancesRequest{GetInstancesRequest: model.GetInstancesRequest{
+		Namespace: req.Namespace, Service: req.Service,
+	}})
+}
+
+func (c *fakeClients) WatchService(*api.WatchServiceRequest) (*model.WatchServiceResponse, error) {
+	return nil, errors.New("fakeClients does not support watches")
+}
+
+func (c *fakeClients) UpdateServiceCallResult(*api.ServiceCallResult) error {
+	return nil
+}
+
+func (c *fakeClients) RegisterInstance(req *api.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	return c.Register(req)
+}
+
+func (c *fakeClients) Register(req *api.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.registerFailures > 0 {
+		c.registerFailures--
+		return nil, NewServiceError(ErrCodeServiceUnavailable, "unavailable")
+	}
+	if req.Port == c.failPort {
+		return nil, errors.New("register failed")
+	}
+	c.registered = append(c.registered, req)
+	return &model.InstanceRegisterResponse{}, nil
+}
+
+func (c *fakeClients) Deregister(req *api.InstanceDeRegisterRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deregistered = append(c.deregistered, req)
+	return nil
+}
+
+func (c *fakeClients) Heartbeat(req *api.InstanceHeartbeatRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.heartbeats = append(c.heartbeats, req)
+	if c.failHeartbeats {
+		return errors.New("heartbeat failed")
+	}
+	return nil
+}
+
+func (c *fakeClients) GetConfigFile(namespace, fileGroup, fileName string) (model.ConfigFile, error) {
+	c.configCalls.Add(1)
+	if err := c.wait(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &fakeConfigFile{namespace: namespace, group: fileGroup, fileName: fileName, content: c.config}, nil
+}
+
+func (c *fakeClients) GetQuota(req api.QuotaRequest) (api.QuotaFuture, error) {
+	clear(c.lastLabels)
+	blocked := false
+	for _, arg := range req.(*model.QuotaRequestImpl).Arguments() {
+		if c.lastLabels != nil {
+			c.lastLabels[arg.Key()] = arg.Value()
+		}
+		blocked = blocked || arg.Value() == "blocked"
+	}
+	if blocked {
+		return fakeQuotaLimited, nil
+	}
+	return fakeQuotaAllowed, nil
+}
+
+// fakeConfigFile is a config file carrying content, by default app.yaml of group orders in
+// the default namespace.
+type fakeConfigFile struct {
+	model.ConfigFile
+	namespace, group, fileName string
+	content                    string
+}
+
+func (f *fakeConfigFile) GetNamespace() string { return cmp.Or(f.namespace, "default") }
+func (f *fakeConfigFile) GetFileGroup() string { return cmp.Or(f.group, "orders") }
+func (f *fakeConfigFile) GetFileName() string  { return cmp.Or(f.fileName, "app.yaml") }
+func (f *fakeConfigFile) GetContent() string   { return f.content }
+func (f *fakeConfigFile) HasContent() bool     { return f.content != "" }
+
+// newTestPlugin returns a plugin started by NewPluginWithClients on cfg, or on the default
+// namespace when cfg is nil, and stopped at the end of the test.
+func newTestPlugin(t testing.TB, cfg *conf.Polaris, opts ...Option) *PlugPolaris {
+	t.Helper()
+	if cfg == nil {
+		cfg = &conf.Polaris{Namespace: "default"}
+	}
+	plugin, err := NewPluginWithClients(cfg, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = plugin.CleanupTasks() })
+	return plugin
+}
@@ -0,0 +1,188 @@
+package polaris
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Fallback module
+// Responsibility: static per-service instance lists that back discovery when Polaris
+// is unreachable. The fallback chain is: live discovery -> cached instances -> static instances.
+
+// Fallback source labels used in logs and metrics.
+const (
+	fallbackSourceCache  = "cache"
+	fallbackSourceStatic = "static"
+)
+
+// staticInstance is a model.Instance backed by a static endpoint definition.
+type staticInstance struct {
+	namespace string
+	service   string
+	host      string
+	port      uint32
+	protocol  string
+	version   string
+	weight    int
+	metadata  map[string]string
+}
+
+// NewStaticInstance builds a model.Instance from a static endpoint definition so it can be
+// passed to SetFallbackInstances. A zero weight falls back to conf.DefaultWeight.
+func NewStaticInstance(namespace, serviceName string, fi *conf.FallbackInstance) model.Instance {
+	if fi == nil {
+		return nil
+	}
+	weight := int(fi.GetWeight())
+	if weight <= 0 {
+		weight = conf.DefaultWeight
+	}
+	var metadata map[string]string
+	if md := fi.GetMetadata(); len(md) > 0 {
+		metadata = make(map[string]string, len(md))
+		for k, v := range md {
+			metadata[k] = v
+		}
+	}
+	return &staticInstance{
+		namespace: namespace,
+		service:   serviceName,
+		host:      fi.GetHost(),
+		port:      fi.GetPort(),
+		protocol:  fi.GetProtocol(),
+		version:   fi.GetVersion(),
+		weight:    weight,
+		metadata:  metadata,
+	}
+}
+
+func (s *staticInstance) GetInstanceKey() model.InstanceKey {
+	return model.InstanceKey{
+		ServiceKey: model.ServiceKey{Namespace: s.namespace, Service: s.service},
+		Host:       s.host,
+		Port:       int(s.port),
+	}
+}
+
+func (s *staticInstance) GetNamespace() string { return s.namespace }
+func (s *staticInstance) GetService() string   { return s.service }
+func (s *staticInstance) GetId() string {
+	return fmt.Sprintf("static-%s", net.JoinHostPort(s.host, strconv.FormatUint(uint64(s.port), 10)))
+}
+func (s *staticInstance) GetHost() string                                     { return s.host }
+func (s *staticInstance) GetPort() uint32                                     { return s.port }
+func (s *staticInstance) GetVpcId() string                                    { return "" }
+func (s *staticInstance) GetProtocol() string                                 { return s.protocol }
+func (s *staticInstance) GetVersion() string                                  { return s.version }
+func (s *staticInstance) GetWeight() int                                      { return s.weight }
+func (s *staticInstance) GetPriority() uint32                                 { return 0 }
+func (s *staticInstance) GetMetadata() map[string]string                      { return s.metadata }
+func (s *staticInstance) GetLogicSet() string                                 { return "" }
+func (s *staticInstance) IsHealthy() bool                                     { return true }
+func (s *staticInstance) IsIsolated() bool                                    { return false }
+func (s *staticInstance) IsEnableHealthCheck() bool                           { return false }
+func (s *staticInstance) GetRegion() string                                   { return "" }
+func (s *staticInstance) GetZone() string                                     { return "" }
+func (s *staticInstance) GetIDC() string                                      { return "" }
+func (s *staticInstance) GetCampus() string                                   { return "" }
+func (s *staticInstance) GetRevision() string                                 { return "" }
+func (s *staticInstance) GetCircuitBreakerStatus() model.CircuitBreakerStatus { return nil }
+
+// SetFallbackInstances sets the static instances returned for serviceName when discovery
+// fails and no cached instances exist. Programmatic values take precedence over
+// fallback_services from configuration. Passing an empty list removes the fallback.
+func (p *PlugPolaris) SetFallbackInstances(serviceName string, instances []model.Instance) error {
+	if serviceName == "" {
+		return NewConfigError("service name cannot be empty")
+	}
+	copied := make([]model.Instance, 0, len(instances))
+	for _, inst := range instances {
+		if inst != nil {
+			copied = append(copied, inst)
+		}
+	}
+
+	p.fallbackMutex.Lock()
+	defer p.fallbackMutex.Unlock()
+	if len(copied) == 0 {
+		delete(p.fallbackInstances, serviceName)
+		log.Infof("Removed static fallback instances for %s", serviceName)
+		return nil
+	}
+	if p.fallbackInstances == nil {
+		p.fallbackInstances = make(map[string][]model.Instance)
+	}
+	p.fallbackInstances[serviceName] = copied
+	log.Infof("Set %d static fallback instances for %s", len(copied), serviceName)
+	return nil
+}
+
+// GetFallbackInstances returns the static fallback instances configured for serviceName.
+func (p *PlugPolaris) GetFallbackInstances(serviceName string) []model.Instance {
+	p.fallbackMutex.RLock()
+	defer p.fallbackMutex.RUnlock()
+	instances := p.fallbackInstances[serviceName]
+	if len(instances) == 0 {
+		return nil
+	}
+	return append([]model.Instance(nil), instances...)
+}
+
+// loadConfiguredFallbacks registers fallback_services from configuration. Services that
+// already have programmatic fallbacks are left untouched.
+func (p *PlugPolaris) loadConfiguredFallbacks() {
+	cfg := p.currentConf()
+	if cfg == nil {
+		return
+	}
+	p.fallbackMutex.Lock()
+	defer p.fallbackMutex.Unlock()
+	for _, svc := range cfg.GetFallbackServices() {
+		if svc == nil || svc.GetService() == "" {
+			continue
+		}
+		if _, exists := p.fallbackInstances[svc.GetService()]; exists {
+			continue
+		}
+		instances := make([]model.Instance, 0, len(svc.GetInstances()))
+		for _, fi := range svc.GetInstances() {
+			if inst := NewStaticInstance(cfg.Namespace, svc.GetService(), fi); inst != nil {
+				instances = append(instances, inst)
+			}
+		}
+		if len(instances) == 0 {
+			continue
+		}
+		if p.fallbackInstances == nil {
+			p.fallbackInstances = make(map[string][]model.Instance)
+		}
+		p.fallbackInstances[svc.GetService()] = instances
+		log.Infof("Loaded %d static fallback instances for %s from configuration", len(instances), svc.GetService())
+	}
+}
+
+// discoveryFallback walks the fallback chain for serviceName after live discovery failed.
+// It returns cached instances if any, otherwise static instances, along with their source.
+func (p *PlugPolaris) discoveryFallback(serviceName string) ([]model.Instance, string) {
+	if instances := p.cachedServiceInstances(serviceName); len(instances) > 0 {
+		return instances, fallbackSourceCache
+	}
+	if instances := p.GetFallbackInstances(serviceName); len(instances) > 0 {
+		return instances, fallbackSourceStatic
+	}
+	return nil, ""
+}
+
+// discoveryFallbackInstances is the fallback hook handed to PolarisDiscovery.
+func (p *PlugPolaris) discoveryFallbackInstances(serviceName string) []model.Instance {
+	instances, source := p.discoveryFallback(serviceName)
+	if len(instances) > 0 {
+		log.Warnf("Serving %d %s fallback instances for %s", len(instances), source, serviceName)
+	}
+	return instances
+}
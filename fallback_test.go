@@ -0,0 +1,194 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStaticInstance(t *testing.T) {
+	inst := NewStaticInstance("default", "svc", &conf.FallbackInstance{
+		Host:     "10.0.0.1",
+		Port:     9000,
+		Protocol: "grpc",
+		Metadata: map[string]string{"zone": "a"},
+	})
+	require.NotNil(t, inst)
+	assert.Equal(t, "svc", inst.GetService())
+	assert.Equal(t, "default", inst.GetNamespace())
+	assert.Equal(t, "10.0.0.1", inst.GetHost())
+	assert.Equal(t, uint32(9000), inst.GetPort())
+	assert.Equal(t, conf.DefaultWeight, inst.GetWeight())
+	assert.True(t, inst.IsHealthy())
+	assert.Equal(t, "a", inst.GetMetadata()["zone"])
+	assert.Nil(t, NewStaticInstance("default", "svc", nil))
+}
+
+func TestSetFallbackInstances(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.Error(t, plugin.SetFallbackInstances("", nil))
+
+	inst := NewStaticInstance("default", "svc", &conf.FallbackInstance{Host: "10.0.0.1", Port: 9000})
+	require.NoError(t, plugin.SetFallbackInstances("svc", []model.Instance{inst, nil}))
+	assert.Len(t, plugin.GetFallbackInstances("svc"), 1)
+
+	require.NoError(t, plugin.SetFallbackInstances("svc", nil))
+	assert.Empty(t, plugin.GetFallbackInstances("svc"))
+}
+
+func TestLoadConfiguredFallbacks_ProgrammaticWins(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{
+		Namespace: "default",
+		FallbackServices: []*conf.FallbackService{
+			{Service: "a", Instances: []*conf.FallbackInstance{{Host: "10.0.0.1", Port: 80}}},
+			{Service: "b", Instances: []*conf.FallbackInstance{{Host: "10.0.0.2", Port: 80}}},
+		},
+	})
+	manual := NewStaticInstance("default", "b", &conf.FallbackInstance{Host: "10.9.9.9", Port: 80})
+	require.NoError(t, plugin.SetFallbackInstances("b", []model.Instance{manual}))
+
+	plugin.loadConfiguredFallbacks()
+
+	require.Len(t, plugin.GetFallbackInstances("a"), 1)
+	assert.Equal(t, "10.0.0.1", plugin.GetFallbackInstances("a")[0].GetHost())
+	require.Len(t, plugin.GetFallbackInstances("b"), 1)
+	assert.Equal(t, "10.9.9.9", plugin.GetFallbackInstances("b")[0].GetHost())
+}
+
+func TestDiscoveryFallback_CachePreferredOverStatic(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+
+	_, source := plugin.discoveryFallback("svc")
+	assert.Empty(t, source)
+
+	static := NewStaticInstance("default", "svc", &conf.FallbackInstance{Host: "10.0.0.1", Port: 80})
+	require.NoError(t, plugin.SetFallbackInstances("svc", []model.Instance{static}))
+	instances, source := plugin.discoveryFallback("svc")
+	assert.Equal(t, fallbackSourceStatic, source)
+	assert.Len(t, instances, 1)
+
+	cached := NewStaticInstance("default", "svc", &conf.FallbackInstance{Host: "10.0.0.2", Port: 80})
+	plugin.updateServiceInstanceCache("svc", []model.Instance{cached})
+	instances, source = plugin.discoveryFallback("svc")
+	assert.Equal(t, fallbackSourceCache, source)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "10.0.0.2", instances[0].GetHost())
+}
+
+func TestPolarisDiscovery_GetServiceUsesFallback(t *testing.T) {
+	disc := NewPolarisDiscovery(&fakeClients{err: errors.New("polaris unreachable")}, "default", nil)
+	_, err := disc.GetService(context.Background(), "svc")
+	require.Error(t, err)
+
+	disc.fallback = func(name string) []model.Instance {
+		return []model.Instance{NewStaticInstance("default", name, &conf.FallbackInstance{
+			Host: "10.0.0.1", Port: 9000, Protocol: "grpc",
+		})}
+	}
+	instances, err := disc.GetService(context.Background(), "svc")
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, []string{"grpc://10.0.0.1:9000"}, instances[0].Endpoints)
+}
+
+func TestValidator_FallbackServices(t *testing.T) {
+	cfg := &conf.Polaris{
+		Namespace: "default",
+		Weight:    100,
+		Ttl:       30,
+		FallbackServices: []*conf.FallbackService{
+			{Service: "svc", Instances: []*conf.FallbackInstance{{Host: "", Port: 80}, {Host: "h", Port: 0}}},
+		},
+	}
+	result := NewValidator(cfg).Validate()
+	assert.False(t, result.IsValid)
+	assert.Len(t, result.Errors, 2)
+}
+
+// unhealthyInstance is a static instance reported as unhealthy.
+type unhealthyInstance struct {
+	model.Instance
+}
+
+func (unhealthyInstance) IsHealthy() bool { return false }
+
+func TestSetRouteFallback(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.Error(t, plugin.SetRouteFallback("", "b", nil))
+	assert.Error(t, plugin.SetRouteFallback("a", "a", nil))
+
+	assert.Nil(t, plugin.routeFallback("a"))
+	static := NewStaticInstance("default", "a", &conf.FallbackInstance{Host: "10.0.0.9", Port: 8080})
+	require.NoError(t, plugin.SetRouteFallback("a", "", []model.Instance{static}))
+	fallback := plugin.routeFallback("a")
+	require.Len(t, fallback, 1)
+	assert.Equal(t, "10.0.0.9", fallback[0].GetHost())
+
+	require.NoError(t, plugin.SetRouteFallback("a", "", nil))
+	assert.Nil(t, plugin.routeFallback("a"))
+}
+
+func TestLoadConfiguredRouteFallbacks(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{
+		Namespace: "default",
+		RouteFallbacks: []*conf.RouteFallback{
+			{Service: "a", TargetInstances: []*conf.FallbackInstance{{Host: "10.0.0.1", Port: 80}}},
+			{Service: "b", TargetService: "b"},
+			{Service: "c"},
+		},
+	})
+	plugin.loadConfiguredRouteFallbacks()
+
+	assert.Len(t, plugin.routeFallback("a"), 1)
+	assert.Nil(t, plugin.routeFallback("b"))
+	assert.Nil(t, plugin.routeFallback("c"))
+}
+
+func TestHealthyInstances(t *testing.T) {
+	healthy := NewStaticInstance("default", "svc", &conf.FallbackInstance{Host: "10.0.0.1", Port: 80})
+	instances := []model.Instance{healthy, unhealthyInstance{healthy}, nil}
+	assert.Len(t, healthyInstances(instances), 1)
+}
+
+func TestRouteFallbackNodeFilter(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	static := NewStaticInstance("default", "svc", &conf.FallbackInstance{Host: "10.0.0.9", Port: 9000, Protocol: "grpc"})
+	require.NoError(t, plugin.SetRouteFallback("svc", "", []model.Instance{static}))
+
+	dropAll := func(context.Context, []selector.Node) []selector.Node { return nil }
+	filter := plugin.routeFallbackNodeFilter(dropAll)
+	nodes := []selector.Node{selector.NewNode("grpc", "10.0.0.1:9000", &registry.ServiceInstance{Name: "svc"})}
+
+	out := filter(context.Background(), nodes)
+	require.Len(t, out, 1)
+	assert.Equal(t, "10.0.0.9:9000", out[0].Address())
+
+	// Nodes that survive filtering are returned unchanged.
+	out = plugin.routeFallbackNodeFilter(nil)(context.Background(), nodes)
+	assert.Equal(t, nodes, out)
+}
+
+func TestPolarisDiscovery_GetServiceUsesRouteFallback(t *testing.T) {
+	disc := NewPolarisDiscovery(&fakeClients{instances: []model.Instance{
+		unhealthyInstance{NewStaticInstance("default", "svc", &conf.FallbackInstance{Host: "10.0.0.1", Port: 80})},
+	}}, "default", nil)
+	disc.routeFallback = func(string) []model.Instance {
+		return []model.Instance{NewStaticInstance("default", "svc-readonly", &conf.FallbackInstance{
+			Host: "10.0.0.2", Port: 8080, Protocol: "http",
+		})}
+	}
+	instances, err := disc.GetService(context.Background(), "svc")
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, []string{"http://10.0.0.2:8080"}, instances[0].Endpoints)
+}
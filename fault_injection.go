@@ -0,0 +1,201 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Fault injection module
+// Responsibility: delaying and aborting outgoing calls as the fault_injection rules say,
+// so that chaos experiments take effect in the clients of this application. polaris-go has
+// no fault injection rules, so they are loaded from the Polaris OpenAPI at rules_path, and
+// otherwise taken from the plugin settings.
+
+// faultInjectedReason is the Kratos error reason of aborted calls without one set by the rule.
+const faultInjectedReason = "FAULT_INJECTED"
+
+// FaultInjectionMiddleware returns Kratos client middleware applying the fault_injection
+// rules to the calls to targetService. The first rule matching the service and the
+// operation of a call delays it, aborts it with a Kratos error, or both, each for its
+// percentage of the calls. The rules are read on every call, so changes apply right away.
+func (p *PlugPolaris) FaultInjectionMiddleware(targetService string) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			operation := ""
+			if tr, ok := transport.FromClientContext(ctx); ok {
+				operation = tr.Operation()
+			}
+			if err := p.injectFault(ctx, targetService, operation); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// GRPCFaultInjectionUnaryInterceptor returns a gRPC unary client interceptor applying the
+// fault_injection rules like FaultInjectionMiddleware, for clients that do not use Kratos
+// middleware. Aborted calls fail with the gRPC code matching the HTTP code of the rule.
+func (p *PlugPolaris) GRPCFaultInjectionUnaryInterceptor(targetService string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := p.injectFault(ctx, targetService, method); err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// injectFault applies the first rule matching the call of operation of service. It returns
+// the error of an aborted call, or the error of ctx when it ends during the delay.
+func (p *PlugPolaris) injectFault(ctx context.Context, service, operation string) error {
+	p.mu.RLock()
+	cfg := p.currentConf().GetFaultInjection()
+	p.mu.RUnlock()
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	rules := cfg.GetRules()
+	if loaded := p.serverFaultRules.Load(); loaded != nil && cfg.GetRulesPath() != "" {
+		rules = *loaded
+	}
+	rule := matchFaultInjectionRule(rules, service, operation)
+	if rule == nil {
+		return nil
+	}
+	if delay := rule.GetDelay(); delay != nil && p.sampled(delay.GetPercentage()) {
+		log.Debugf("Fault injection rule %s delays call %s %s by %v", rule.GetName(), service, operation, delay.GetDuration().AsDuration())
+		timer := p.clock.NewTimer(delay.GetDuration().AsDuration())
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+	if abort := rule.GetAbort(); abort != nil && p.sampled(abort.GetPercentage()) {
+		log.Debugf("Fault injection rule %s aborts call %s %s with code %d", rule.GetName(), service, operation, abort.GetCode())
+		reason := abort.GetReason()
+		if reason == "" {
+			reason = faultInjectedReason
+		}
+		return errors.New(int(abort.GetCode()), reason, "fault injected by rule "+rule.GetName())
+	}
+	return nil
+}
+
+// sampled reports whether a call falls in percentage of the calls.
+func (p *PlugPolaris) sampled(percentage float64) bool {
+	return p.rand.Float64()*100 < percentage
+}
+
+// matchFaultInjectionRule returns the first of rules matching service and operation, or nil.
+func matchFaultInjectionRule(rules []*conf.FaultInjectionRule, service, operation string) *conf.FaultInjectionRule {
+	for _, rule := range rules {
+		if rule.GetService() != "" && rule.GetService() != service {
+			continue
+		}
+		pattern := rule.GetOperation()
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if !strings.HasPrefix(operation, prefix) {
+				continue
+			}
+		} else if pattern != "" && pattern != operation {
+			continue
+		}
+		return rule
+	}
+	return nil
+}
+
+// faultRulesResponse is the list of fault injection rules of the Polaris OpenAPI.
+type faultRulesResponse struct {
+	Data []json.RawMessage `json:"data"`
+}
+
+// loadFaultInjectionRules loads the rules of the namespace from fault_injection.rules_path
+// and makes them replace the configured rules. The rules in effect are kept on failure.
+func (p *PlugPolaris) loadFaultInjectionRules(ctx context.Context) error {
+	cfg := p.currentConf()
+	path := cfg.GetFaultInjection().GetRulesPath()
+	if path == "" {
+		return nil
+	}
+	client, err := p.openAPIClient("", "load fault injection rules")
+	if err != nil {
+		return err
+	}
+	var resp faultRulesResponse
+	query := url.Values{"namespace": {cfg.GetNamespace()}}
+	if _, err := client.do(ctx, http.MethodGet, path, query, nil, &resp); err != nil {
+		return WrapServiceError(err, ErrCodeServiceUnavailable, "failed to load fault injection rules")
+	}
+	rules := make([]*conf.FaultInjectionRule, 0, len(resp.Data))
+	for i, data := range resp.Data {
+		rule := &conf.FaultInjectionRule{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, rule); err != nil {
+			return NewConfigError(fmt.Sprintf("invalid fault injection rule %d from %s: %v", i, path, err))
+		}
+		rules = append(rules, rule)
+	}
+	if err := validateFaultInjectionRules(rules); err != nil {
+		return err
+	}
+	previous := p.serverFaultRules.Swap(&rules)
+	if previous == nil || !slices.EqualFunc(*previous, rules, func(a, b *conf.FaultInjectionRule) bool { return proto.Equal(a, b) }) {
+		log.Infof("Loaded %d fault injection rules from %s", len(rules), path)
+	}
+	return nil
+}
+
+// validateFaultInjectionRules checks loaded rules like the configured ones.
+func validateFaultInjectionRules(rules []*conf.FaultInjectionRule) error {
+	result := NewValidationResult()
+	validateFaultInjectionRuleList(result, "fault_injection.rules_path", rules)
+	if !result.IsValid {
+		return NewConfigError(result.Error())
+	}
+	return nil
+}
+
+// startFaultRuleRefresh loads the fault injection rules from fault_injection.rules_path now
+// and at every refresh_interval. The loop stops with the plugin lifecycle.
+func (p *PlugPolaris) startFaultRuleRefresh() {
+	cfg := p.currentConf().GetFaultInjection()
+	if cfg.GetRulesPath() == "" {
+		return
+	}
+	interval := conf.DefaultFaultRuleRefreshInterval
+	if d := cfg.GetRefreshInterval(); d != nil && d.AsDuration() > 0 {
+		interval = d.AsDuration()
+	}
+	ctx := p.watcherContext()
+	log.Infof("Loading fault injection rules from %s every %v", cfg.GetRulesPath(), interval)
+	go func() {
+		ticker := p.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := p.loadFaultInjectionRules(ctx); err != nil && ctx.Err() == nil {
+				log.Warnf("Failed to load fault injection rules, keeping the current ones: %s", p.redactError(err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+			}
+		}
+	}()
+}
@@ -0,0 +1,201 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestMatchFaultInjectionRule(t *testing.T) {
+	rules := []*conf.FaultInjectionRule{
+		{Name: "exact", Service: "payments", Operation: "/payments.v1.Payments/Charge"},
+		{Name: "prefix", Service: "payments", Operation: "/payments.v1.Refunds/*"},
+		{Name: "service", Service: "orders"},
+		{Name: "any", Operation: "/health"},
+	}
+	tests := []struct {
+		service, operation, want string
+	}{
+		{"payments", "/payments.v1.Payments/Charge", "exact"},
+		{"payments", "/payments.v1.Refunds/Create", "prefix"},
+		{"orders", "/orders.v1.Orders/GetOrder", "service"},
+		{"users", "/health", "any"},
+		{"payments", "/payments.v1.Payments/Get", ""},
+	}
+	for _, tt := range tests {
+		rule := matchFaultInjectionRule(rules, tt.service, tt.operation)
+		if tt.want == "" {
+			assert.Nil(t, rule, tt.operation)
+			continue
+		}
+		require.NotNil(t, rule, tt.operation)
+		assert.Equal(t, tt.want, rule.Name)
+	}
+}
+
+func TestFaultInjectionMiddleware_Abort(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{FaultInjection: &conf.FaultInjection{Enabled: true, Rules: []*conf.FaultInjectionRule{
+		{Name: "outage", Service: "payments", Abort: &conf.FaultAbort{Code: http.StatusServiceUnavailable, Percentage: 100}},
+	}}})
+	sent := 0
+	handler := plugin.FaultInjectionMiddleware("payments")(func(context.Context, any) (any, error) {
+		sent++
+		return "ok", nil
+	})
+	ctx := transport.NewClientContext(context.Background(), &testClientTransport{operation: "/payments.v1.Payments/Charge"})
+
+	_, err := handler(ctx, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, kerrors.Code(err))
+	assert.Equal(t, faultInjectedReason, kerrors.Reason(err))
+	assert.Zero(t, sent)
+
+	plugin.currentConf().FaultInjection.Rules[0].Abort.Percentage = 0
+	_, err = handler(ctx, nil)
+	require.NoError(t, err)
+	plugin.currentConf().FaultInjection.Rules[0].Abort.Percentage = 100
+	plugin.currentConf().FaultInjection.Enabled = false
+	_, err = handler(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+
+	_, err = plugin.FaultInjectionMiddleware("orders")(func(context.Context, any) (any, error) { return nil, nil })(ctx, nil)
+	assert.NoError(t, err, "other services are not affected")
+}
+
+func TestFaultInjectionMiddleware_Delay(t *testing.T) {
+	clock := newManualClock()
+	plugin := NewPolarisControlPlane(WithClock(clock))
+	plugin.setConf(&conf.Polaris{FaultInjection: &conf.FaultInjection{Enabled: true, Rules: []*conf.FaultInjectionRule{
+		{Name: "slow", Delay: &conf.FaultDelay{Duration: durationpb.New(2 * time.Second), Percentage: 100}},
+	}}})
+	handler := plugin.FaultInjectionMiddleware("payments")(func(context.Context, any) (any, error) { return "ok", nil })
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := handler(context.Background(), nil)
+		done <- err
+	}()
+	clock.waitForTimers(t, 1)
+	select {
+	case <-done:
+		t.Fatal("the call is sent before the delay")
+	default:
+	}
+	clock.Advance(2 * time.Second)
+	require.NoError(t, <-done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		_, err := handler(ctx, nil)
+		done <- err
+	}()
+	clock.waitForTimers(t, 1)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestGRPCFaultInjectionUnaryInterceptor(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{FaultInjection: &conf.FaultInjection{Enabled: true, Rules: []*conf.FaultInjectionRule{
+		{Name: "outage", Operation: "/payments.v1.Payments/*", Abort: &conf.FaultAbort{Code: http.StatusServiceUnavailable, Percentage: 100}},
+	}}})
+	interceptor := plugin.GRPCFaultInjectionUnaryInterceptor("payments")
+	invoked := false
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		invoked = true
+		return nil
+	}
+
+	err := interceptor(context.Background(), "/payments.v1.Payments/Charge", nil, nil, nil, invoker)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.False(t, invoked)
+	require.NoError(t, interceptor(context.Background(), "/payments.v1.Refunds/Create", nil, nil, nil, invoker))
+	assert.True(t, invoked)
+}
+
+func TestValidator_FaultInjection(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", FaultInjection: &conf.FaultInjection{Rules: []*conf.FaultInjectionRule{
+		{Name: "empty"},
+		{Name: "bad", Delay: &conf.FaultDelay{Percentage: 120}, Abort: &conf.FaultAbort{Code: 200, Percentage: -1}},
+	}}}
+	setConfigDefaults(cfg)
+	result := NewValidator(cfg).Validate()
+	var fields []string
+	for _, err := range result.Errors {
+		fields = append(fields, err.Field)
+	}
+	assert.Subset(t, fields, []string{
+		"fault_injection.rules[0]",
+		"fault_injection.rules[1].delay.duration",
+		"fault_injection.rules[1].delay.percentage",
+		"fault_injection.rules[1].abort.code",
+		"fault_injection.rules[1].abort.percentage",
+	})
+}
+
+func TestLoadFaultInjectionRules(t *testing.T) {
+	var rules atomic.Value
+	rules.Store(`[{"name": "outage", "service": "payments", "abort": {"code": 503, "percentage": 100}, "owner": "sre"}]`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/naming/v1/chaos/rules", r.URL.Path)
+		assert.Equal(t, "default", r.URL.Query().Get("namespace"))
+		assert.Equal(t, "secret-token", r.Header.Get("X-Polaris-Token"))
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": polarisCodeSuccess, "data": json.RawMessage(rules.Load().(string))})
+	}))
+	t.Cleanup(server.Close)
+	plugin := newTestPlugin(t, &conf.Polaris{
+		Namespace:   "default",
+		Token:       "secret-token",
+		ConfigAdmin: &conf.ConfigAdmin{Address: server.URL},
+		FaultInjection: &conf.FaultInjection{Enabled: true, RulesPath: "/naming/v1/chaos/rules", Rules: []*conf.FaultInjectionRule{
+			{Name: "local", Service: "orders", Abort: &conf.FaultAbort{Code: http.StatusBadGateway, Percentage: 100}},
+		}},
+	})
+	require.Error(t, plugin.injectFault(context.Background(), "orders", "/orders.v1.Orders/Get"), "the configured rules apply until loaded")
+
+	require.NoError(t, plugin.loadFaultInjectionRules(context.Background()))
+	err := plugin.injectFault(context.Background(), "payments", "/payments.v1.Payments/Charge")
+	assert.Equal(t, 503, kerrors.Code(err))
+	assert.NoError(t, plugin.injectFault(context.Background(), "orders", "/orders.v1.Orders/Get"), "loaded rules replace the configured ones")
+
+	rules.Store(`[{"name": "bad", "abort": {"code": 200, "percentage": 100}}]`)
+	assert.True(t, IsConfigError(plugin.loadFaultInjectionRules(context.Background())))
+	assert.Error(t, plugin.injectFault(context.Background(), "payments", "/payments.v1.Payments/Charge"), "invalid rules keep the loaded ones")
+
+	plugin.currentConf().FaultInjection.RulesPath = ""
+	assert.Error(t, plugin.injectFault(context.Background(), "orders", "/orders.v1.Orders/Get"), "without rules_path the configured rules apply")
+}
+
+func TestValidator_FaultInjectionRulesPath(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", FaultInjection: &conf.FaultInjection{
+		RulesPath:       "naming/v1/chaos/rules",
+		RefreshInterval: durationpb.New(-time.Second),
+	}}
+	setConfigDefaults(cfg)
+	result := NewValidator(cfg).Validate()
+	var fields []string
+	for _, err := range result.Errors {
+		fields = append(fields, err.Field+" "+string(err.Code))
+	}
+	assert.Subset(t, fields, []string{
+		"fault_injection.rules_path INVALID_FORMAT",
+		"fault_injection.rules_path DEPENDENCY",
+		"fault_injection.refresh_interval OUT_OF_RANGE",
+	})
+}
@@ -0,0 +1,168 @@
+package polaris
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// grpcRateLimitMethodLabel is the label carrying the full gRPC method name.
+const grpcRateLimitMethodLabel = "method"
+
+// GRPCRateLimitOption customizes the gRPC rate limit interceptors.
+type GRPCRateLimitOption func(*grpcRateLimitOptions)
+
+type grpcRateLimitOptions struct {
+	service      string
+	metadataKeys []string
+	labels       func(ctx context.Context, fullMethod string) map[string]string
+	failClosed   bool
+}
+
+// WithGRPCRateLimitService sets the Polaris service whose rate limit rules apply. It
+// defaults to the name of the running application.
+func WithGRPCRateLimitService(service string) GRPCRateLimitOption {
+	return func(o *grpcRateLimitOptions) {
+		o.service = service
+	}
+}
+
+// WithGRPCRateLimitMetadata adds the first value of each of the incoming metadata keys
+// to the labels, under the lower-cased key. Missing keys are skipped.
+func WithGRPCRateLimitMetadata(keys ...string) GRPCRateLimitOption {
+	return func(o *grpcRateLimitOptions) {
+		for _, key := range keys {
+			o.metadataKeys = append(o.metadataKeys, strings.ToLower(key))
+		}
+	}
+}
+
+// WithGRPCRateLimitLabels adds the labels returned by fn for each call. They take
+// precedence over the method and metadata labels.
+func WithGRPCRateLimitLabels(fn func(ctx context.Context, fullMethod string) map[string]string) GRPCRateLimitOption {
+	return func(o *grpcRateLimitOptions) {
+		o.labels = fn
+	}
+}
+
+// WithGRPCRateLimitFailClosed rejects calls with codes.Unavailable when the quota cannot be
+// checked, e.g. because Polaris is unreachable. By default such calls are let through.
+func WithGRPCRateLimitFailClosed() GRPCRateLimitOption {
+	return func(o *grpcRateLimitOptions) {
+		o.failClosed = true
+	}
+}
+
+// grpcRateLimiter checks the quota of incoming gRPC calls.
+type grpcRateLimiter struct {
+	gate *quotaGate
+	opts grpcRateLimitOptions
+}
+
+// GRPCRateLimitUnaryInterceptor returns a gRPC unary server interceptor that checks every
+// call against the Polaris rate limit rules of the service. The labels are the full method
+// name under "method", the configured metadata values and any custom labels. A rejected
+// call fails with codes.ResourceExhausted, carrying an ErrorInfo with the rule details and
+// a RetryInfo when Polaris suggests a wait. The quota is acquired for the full method name,
+// so rules restricted to a method match too.
+func (p *PlugPolaris) GRPCRateLimitUnaryInterceptor(opts ...GRPCRateLimitOption) grpc.UnaryServerInterceptor {
+	limiter := p.newGRPCRateLimiter(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := limiter.allow(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// GRPCRateLimitStreamInterceptor returns a gRPC stream server interceptor that checks the
+// quota once per stream, the same way as GRPCRateLimitUnaryInterceptor.
+func (p *PlugPolaris) GRPCRateLimitStreamInterceptor(opts ...GRPCRateLimitOption) grpc.StreamServerInterceptor {
+	limiter := p.newGRPCRateLimiter(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := limiter.allow(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// newGRPCRateLimiter applies opts over the defaults.
+func (p *PlugPolaris) newGRPCRateLimiter(opts []GRPCRateLimitOption) *grpcRateLimiter {
+	limiter := &grpcRateLimiter{}
+	for _, opt := range opts {
+		opt(&limiter.opts)
+	}
+	limiter.gate = p.newQuotaGate(limiter.opts.service)
+	return limiter
+}
+
+// labels builds the rate limit labels of a call.
+func (l *grpcRateLimiter) labels(ctx context.Context, fullMethod string) map[string]string {
+	labels := map[string]string{grpcRateLimitMethodLabel: fullMethod}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, key := range l.opts.metadataKeys {
+			if values := md.Get(key); len(values) > 0 {
+				labels[key] = values[0]
+			}
+		}
+	}
+	if l.opts.labels != nil {
+		for key, value := range l.opts.labels(ctx, fullMethod) {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// allow returns nil when the call may proceed, or the gRPC status error to fail it with.
+func (l *grpcRateLimiter) allow(ctx context.Context, fullMethod string) error {
+	result, namespace, err := l.gate.acquire(fullMethod, l.labels(ctx, fullMethod))
+	if err != nil {
+		if l.opts.failClosed {
+			return status.Errorf(codes.Unavailable, "rate limit check failed: %v", err)
+		}
+		log.Warnf("Rate limit check failed for %s, allowing call: %v", fullMethod, err)
+		return nil
+	}
+	if result.Code == model.QuotaResultOk {
+		return nil
+	}
+	return l.rejection(namespace, fullMethod, result)
+}
+
+// rejection builds the codes.ResourceExhausted status of a rejected call.
+func (l *grpcRateLimiter) rejection(namespace, fullMethod string, result *model.QuotaResponse) error {
+	st := status.Newf(codes.ResourceExhausted, "rate limited by Polaris: %s", fullMethod)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason: "RATE_LIMITED",
+		Domain: "polaris",
+		Metadata: map[string]string{
+			"service":   l.gate.service,
+			"namespace": namespace,
+			"method":    fullMethod,
+			"info":      result.Info,
+			"wait_ms":   strconv.FormatInt(result.WaitMs, 10),
+		},
+	}}
+	if result.WaitMs > 0 {
+		details = append(details, &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(time.Duration(result.WaitMs) * time.Millisecond),
+		})
+	}
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
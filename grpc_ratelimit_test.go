@@ -0,0 +1,114 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// quotaCall is a recorded getQuota call.
+type quotaCall struct {
+	service string
+	method  string
+	labels  map[string]string
+}
+
+// newTestGRPCRateLimiter returns a plugin whose rate limiter answers with result and err.
+func newTestGRPCRateLimiter(result *model.QuotaResponse, err error, opts ...GRPCRateLimitOption) (*grpcRateLimiter, *[]quotaCall) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	limiter := plugin.newGRPCRateLimiter(append([]GRPCRateLimitOption{WithGRPCRateLimitService("orders")}, opts...))
+	calls := &[]quotaCall{}
+	limiter.gate.getQuota = func(service, method string, labels map[string]string) (*model.QuotaResponse, error) {
+		*calls = append(*calls, quotaCall{service: service, method: method, labels: labels})
+		return result, err
+	}
+	return limiter, calls
+}
+
+func TestGRPCRateLimiter_AllowsAndBuildsLabels(t *testing.T) {
+	limiter, calls := newTestGRPCRateLimiter(&model.QuotaResponse{Code: model.QuotaResultOk}, nil,
+		WithGRPCRateLimitMetadata("X-User-Tier", "x-missing"),
+		WithGRPCRateLimitLabels(func(ctx context.Context, fullMethod string) map[string]string {
+			return map[string]string{"region": "eu"}
+		}))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-tier", "gold", "x-user-tier", "silver"))
+	require.NoError(t, limiter.allow(ctx, "/orders.v1.Orders/Create"))
+
+	require.Len(t, *calls, 1)
+	assert.Equal(t, quotaCall{
+		service: "orders",
+		method:  "/orders.v1.Orders/Create",
+		labels:  map[string]string{"method": "/orders.v1.Orders/Create", "x-user-tier": "gold", "region": "eu"},
+	}, (*calls)[0])
+}
+
+func TestGRPCRateLimiter_RejectsWithRuleDetails(t *testing.T) {
+	limiter, _ := newTestGRPCRateLimiter(&model.QuotaResponse{Code: model.QuotaResultLimited, Info: "rule orders-qps", WaitMs: 250}, nil)
+	interceptor := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := limiter.allow(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	called := false
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/orders.v1.Orders/Create"},
+		func(context.Context, any) (any, error) {
+			called = true
+			return nil, nil
+		})
+	assert.False(t, called)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 2)
+	info := st.Details()[0].(*errdetails.ErrorInfo)
+	assert.Equal(t, "RATE_LIMITED", info.GetReason())
+	assert.Equal(t, "rule orders-qps", info.GetMetadata()["info"])
+	assert.Equal(t, "default", info.GetMetadata()["namespace"])
+	assert.Equal(t, 250*time.Millisecond, st.Details()[1].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+}
+
+func TestGRPCRateLimiter_CheckFailure(t *testing.T) {
+	limiter, _ := newTestGRPCRateLimiter(nil, errors.New("polaris unreachable"))
+	assert.NoError(t, limiter.allow(context.Background(), "/orders.v1.Orders/Create"))
+
+	limiter, _ = newTestGRPCRateLimiter(nil, errors.New("polaris unreachable"), WithGRPCRateLimitFailClosed())
+	assert.Equal(t, codes.Unavailable, status.Code(limiter.allow(context.Background(), "/orders.v1.Orders/Create")))
+}
+
+// contextServerStream is a server stream that only carries a context.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context { return s.ctx }
+
+func TestGRPCRateLimitStreamInterceptor_UninitializedPluginFailsOpen(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	interceptor := plugin.GRPCRateLimitStreamInterceptor()
+
+	called := false
+	err := interceptor(nil, &contextServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/orders.v1.Orders/Watch"},
+		func(any, grpc.ServerStream) error {
+			called = true
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
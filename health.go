@@ -3,53 +3,284 @@ package polaris
 import (
 	"context"
 	"fmt"
-	"strings"
+	"time"
 
+	"github.com/go-lynx/lynx-polaris/conf"
 	"github.com/go-lynx/lynx/log"
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
+// Health component names of a HealthReport.
+const (
+	HealthComponentSDK          = "sdk"
+	HealthComponentDiscovery    = "discovery"
+	HealthComponentConfig       = "config"
+	HealthComponentRateLimit    = "rate_limit"
+	HealthComponentRegistration = "registration"
+	HealthComponentHeartbeat    = "heartbeat"
+)
+
+// Health statuses of a HealthReport and its components.
+const (
+	HealthStatusHealthy   = "healthy"
+	HealthStatusDegraded  = "degraded"
+	HealthStatusUnhealthy = "unhealthy"
+	// HealthStatusSkipped marks a component that does not apply, e.g. heartbeats when
+	// they are disabled
+	HealthStatusSkipped = "skipped"
+)
+
+// ComponentHealth is the result of the health check of one component.
+type ComponentHealth struct {
+	Name    string        `json:"name"`
+	Status  string        `json:"status"`
+	Message string        `json:"message,omitempty"`
+	Latency time.Duration `json:"latency"`
+}
+
+// HealthReport is the per-component result of a health check. Unhealthy SDK connectivity,
+// discovery, config API or rate limit components fail CheckHealth; a missing registration
+// or stale heartbeats only degrade the report.
+type HealthReport struct {
+	// Status is the worst status of the components
+	Status     string            `json:"status"`
+	Components []ComponentHealth `json:"components"`
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// Component returns the health of the named component.
+func (r HealthReport) Component(name string) (ComponentHealth, bool) {
+	for _, c := range r.Components {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return ComponentHealth{}, false
+}
+
+// add appends c and lowers the report status to c's status when it is worse.
+func (r *HealthReport) add(c ComponentHealth) {
+	r.Components = append(r.Components, c)
+	if healthStatusRank(c.Status) > healthStatusRank(r.Status) {
+		r.Status = c.Status
+	}
+}
+
+func healthStatusRank(status string) int {
+	switch status {
+	case HealthStatusDegraded:
+		return 1
+	case HealthStatusUnhealthy:
+		return 2
+	}
+	return 0
+}
+
 // CheckHealth performs a health check.
 func (p *PlugPolaris) CheckHealth() error {
 	return p.checkHealthContext(context.Background())
 }
 
+// CheckHealthReport performs a health check and returns its per-component report along with
+// the error CheckHealth would return.
+func (p *PlugPolaris) CheckHealthReport(ctx context.Context) (HealthReport, error) {
+	err := p.checkHealthContext(ctx)
+	report, _ := p.LastHealthReport()
+	return report, err
+}
+
+// LastHealthReport returns the report of the last health check, run by CheckHealth, the
+// readiness probe or the background loop, and false before the first check.
+func (p *PlugPolaris) LastHealthReport() (HealthReport, bool) {
+	p.healthReportMutex.RLock()
+	defer p.healthReportMutex.RUnlock()
+	return p.healthReport, !p.healthReport.Timestamp.IsZero()
+}
+
 func (p *PlugPolaris) checkHealthContext(ctx context.Context) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
-	if err := p.checkInitialized(); err != nil {
+	if err := p.checkLifecycle(); err != nil {
 		return err
 	}
+	report := HealthReport{Status: HealthStatusHealthy, Timestamp: time.Now()}
+	err := p.runHealthCheckContext(ctx, &report)
+	report.add(p.registrationHealth())
+	report.add(p.heartbeatHealth(report.Timestamp))
+	for i := range report.Components {
+		report.Components[i].Message = p.redact(report.Components[i].Message)
+	}
+	p.healthReportMutex.Lock()
+	p.healthReport = report
+	p.healthReportMutex.Unlock()
+	p.recordHealthTransition(err)
+	p.evaluateConfigFreshness(time.Now())
+	return err
+}
+
+// startHealthCheckLoop runs the health check every health_check_interval when
+// enable_health_check is set, so the last report, health events and alerts stay current
+// without a caller. The loop stops with the plugin lifecycle.
+func (p *PlugPolaris) startHealthCheckLoop() {
+	p.mu.RLock()
+	enabled := p.currentConf().GetEnableHealthCheck()
+	configured := p.currentConf().GetHealthCheckInterval()
+	stop := p.healthCheckCh
+	p.mu.RUnlock()
+	if !enabled {
+		return
+	}
+	interval := conf.DefaultHealthCheckInterval
+	if configured != nil && configured.AsDuration() > 0 {
+		interval = min(max(configured.AsDuration(), conf.MinHealthCheckInterval), conf.MaxHealthCheckInterval)
+	}
+	ctx := p.watcherContext()
+	log.Infof("Starting background health check (interval: %v)", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stop:
+				return
+			case <-ticker.C:
+				err := p.checkHealthContext(ctx)
+				if ctx.Err() != nil {
+					return
+				}
+				if err != nil {
+					log.Warnf("Background health check failed: %v", p.redactError(err))
+				}
+				if !p.evaluateStandby(ctx, err, time.Now()) {
+					p.evaluateSelfHealing(err, time.Now())
+				}
+			}
+		}
+	}()
+}
+
+// registrationHealth reports whether the instances of the plugin's registrar are
+// registered. It is skipped when nothing is registered through the plugin.
+func (p *PlugPolaris) registrationHealth() ComponentHealth {
+	component := ComponentHealth{Name: HealthComponentRegistration, Status: HealthStatusSkipped}
+	p.mu.RLock()
+	registrar := p.registrar
+	p.mu.RUnlock()
+	if registrar == nil || !registrar.hasInstances() {
+		return component
+	}
+	if err := p.checkRegistered(); err != nil {
+		component.Status, component.Message = HealthStatusDegraded, err.Error()
+		return component
+	}
+	component.Status = HealthStatusHealthy
+	return component
+}
+
+// heartbeatHealth reports heartbeats as stale when none succeeded for three heartbeat
+// intervals, and degraded while instances are past the failure threshold. It is skipped
+// when heartbeats are disabled or nothing is registered through the plugin.
+func (p *PlugPolaris) heartbeatHealth(now time.Time) ComponentHealth {
+	component := ComponentHealth{Name: HealthComponentHeartbeat, Status: HealthStatusSkipped}
+	cfg, ttl := p.heartbeatConfig()
+	p.mu.RLock()
+	registrar := p.registrar
+	p.mu.RUnlock()
+	if !cfg.GetEnabled() || registrar == nil || !registrar.hasInstances() {
+		return component
+	}
+
+	threshold := heartbeatFailureThreshold(cfg)
+	p.heartbeatMutex.Lock()
+	last := p.lastHeartbeat
+	failing := 0
+	for _, failures := range p.heartbeatFailures {
+		if failures >= threshold {
+			failing++
+		}
+	}
+	p.heartbeatMutex.Unlock()
+
+	// Before the first heartbeat, the age counts from the registration
+	since := last
+	if registered := registrar.registeredSince(); registered.After(since) {
+		since = registered
+	}
+	component.Status = HealthStatusHealthy
+	if maxAge := 3 * heartbeatInterval(cfg, ttl); !since.IsZero() && now.Sub(since) > maxAge {
+		component.Status = HealthStatusDegraded
+		component.Message = fmt.Sprintf("no successful heartbeat for %v", now.Sub(since).Round(time.Second))
+	}
+	if failing > 0 {
+		component.Status = HealthStatusDegraded
+		component.Message = fmt.Sprintf("%d instances reached %d consecutive heartbeat failures", failing, threshold)
+	}
+	return component
+}
+
+// runHealthCheckContext runs the control-plane probes against the current SDK snapshot and
+// adds their components to report.
+func (p *PlugPolaris) runHealthCheckContext(ctx context.Context, report *HealthReport) error {
+	// Health checks do not count as a first use with lazy_init
+	if p.lazilyUnconnected() {
+		report.add(ComponentHealth{Name: HealthComponentSDK, Status: HealthStatusSkipped, Message: "not connected yet (lazy_init)"})
+		return nil
+	}
 
 	// Snapshot mutable plugin state under the lock to avoid a data race / nil
 	// dereference if cleanup runs concurrently with this health check.
 	p.mu.RLock()
 	pol := p.polaris
 	sdk := p.sdk
+	clients := sdkClients{consumer: p.consumerLocked(), config: p.configLocked()}
 	namespace := ""
-	if p.conf != nil {
-		namespace = p.conf.Namespace
+	if cfg := p.currentConf(); cfg != nil {
+		namespace = cfg.Namespace
 	}
 	p.mu.RUnlock()
 
 	// Check Polaris instance
 	if pol == nil {
-		return NewInitError("Polaris instance is nil")
+		err := NewInitError("Polaris instance is nil")
+		report.add(ComponentHealth{Name: HealthComponentSDK, Status: HealthStatusUnhealthy, Message: err.Error()})
+		return err
 	}
 
 	// Check SDK connection
 	if sdk == nil {
-		return NewInitError("Polaris SDK context is nil")
+		err := NewInitError("Polaris SDK context is nil")
+		report.add(ComponentHealth{Name: HealthComponentSDK, Status: HealthStatusUnhealthy, Message: err.Error()})
+		return err
 	}
 
 	// Perform actual health check of the Polaris control plane
-	return p.checkPolarisControlPlaneHealthContext(ctx, sdk, namespace)
+	err := p.checkPolarisControlPlaneHealthContext(ctx, clients, namespace, report)
+
+	// Stay unhealthy until the required config files are loaded
+	if !p.requiredConfigsReady() {
+		requiredErr := NewHealthCheckError("required config files are not loaded")
+		report.add(ComponentHealth{Name: HealthComponentConfig, Status: HealthStatusUnhealthy, Message: requiredErr.Error()})
+		if err == nil {
+			err = requiredErr
+		}
+	}
+	return err
+}
+
+// healthProbe is a control-plane health check of one component. Probes of a disabled
+// subsystem are skipped.
+type healthProbe struct {
+	name      string
+	subsystem Subsystem
+	check     func() error
 }
 
 // checkPolarisControlPlaneHealth checks the health of the Polaris control plane.
-func (p *PlugPolaris) checkPolarisControlPlaneHealthContext(ctx context.Context, sdk api.SDKContext, namespace string) error {
+func (p *PlugPolaris) checkPolarisControlPlaneHealthContext(ctx context.Context, clients sdkClients, namespace string, report *HealthReport) error {
 	// Snapshot metrics/breaker/retry under the lock for the same reason.
 	p.mu.RLock()
 	metrics := p.metrics
@@ -58,7 +289,7 @@ func (p *PlugPolaris) checkPolarisControlPlaneHealthContext(ctx context.Context,
 	p.mu.RUnlock()
 
 	if circuitBreaker == nil || retryManager == nil {
-		return NewInitError("Polaris plugin has been destroyed")
+		return newDestroyedError()
 	}
 
 	// Record the start of the health check
@@ -73,42 +304,61 @@ func (p *PlugPolaris) checkPolarisControlPlaneHealthContext(ctx context.Context,
 
 	log.Infof("Checking Polaris control plane health")
 
-	// Execute health checks using circuit breaker and retry mechanisms
+	var probes []healthProbe
+	for _, probe := range []healthProbe{
+		// 1) Check SDK connection status
+		{HealthComponentSDK, "", func() error { return p.checkSDKConnection(clients.consumer, namespace) }},
+		// 2) Check service discovery functionality
+		{HealthComponentDiscovery, SubsystemDiscovery, func() error { return p.checkServiceDiscoveryHealth(clients.consumer, namespace) }},
+		// 3) Check configuration management functionality
+		{HealthComponentConfig, SubsystemConfig, func() error { return p.checkConfigManagementHealth(clients.config, namespace) }},
+		// 4) Check rate limiting functionality
+		{HealthComponentRateLimit, SubsystemRateLimit, p.checkRateLimitHealth},
+	} {
+		if probe.subsystem != "" && !p.SubsystemEnabled(probe.subsystem) {
+			report.add(ComponentHealth{Name: probe.name, Status: HealthStatusSkipped, Message: "subsystem disabled"})
+			continue
+		}
+		probes = append(probes, probe)
+	}
+	components := make([]ComponentHealth, len(probes))
+
+	// Execute health checks using circuit breaker and retry mechanisms. Every probe runs
+	// on each attempt so the report covers all components; the first failure is retried.
 	var healthErr error
 	err := circuitBreaker.Do(func() error {
 		return retryManager.DoWithRetryContext(ctx, func() error {
 			if err := ctx.Err(); err != nil {
 				return err
 			}
-			// 1) Check SDK connection status
-			if err := p.checkSDKConnection(sdk, namespace); err != nil {
-				healthErr = err
-				return err
-			}
-
-			// 2) Check service discovery functionality
-			if err := p.checkServiceDiscoveryHealth(sdk, namespace); err != nil {
-				healthErr = err
-				return err
-			}
-
-			// 3) Check configuration management functionality
-			if err := p.checkConfigManagementHealth(sdk, namespace); err != nil {
-				healthErr = err
-				return err
+			healthErr = nil
+			for i, probe := range probes {
+				start := time.Now()
+				err := probe.check()
+				components[i] = ComponentHealth{Name: probe.name, Status: HealthStatusHealthy, Latency: time.Since(start)}
+				if err != nil {
+					components[i].Status, components[i].Message = HealthStatusUnhealthy, err.Error()
+					if healthErr == nil {
+						healthErr = err
+					}
+				}
 			}
-
-			// 4) Check rate limiting functionality
-			if err := p.checkRateLimitHealth(); err != nil {
-				healthErr = err
-				return err
-			}
-
-			return nil
+			return healthErr
 		})
 	})
 
+	for i, component := range components {
+		// Probes that never ran, e.g. behind an open circuit breaker, take the overall error
+		if component.Name == "" {
+			component = ComponentHealth{Name: probes[i].name, Status: HealthStatusUnhealthy, Message: err.Error()}
+		}
+		report.add(component)
+	}
+
 	if err != nil {
+		if healthErr == nil {
+			healthErr = err
+		}
 		log.Errorf("Polaris control plane health check failed: %v", healthErr)
 		if metrics != nil {
 			metrics.RecordHealthCheck("polaris", "error")
@@ -121,11 +371,9 @@ func (p *PlugPolaris) checkPolarisControlPlaneHealthContext(ctx context.Context,
 }
 
 // checkSDKConnection verifies SDK connection status.
-func (p *PlugPolaris) checkSDKConnection(sdk api.SDKContext, namespace string) error {
-	// Try to create a Consumer API client to validate connectivity
-	consumerAPI := api.NewConsumerAPIByContext(sdk)
+func (p *PlugPolaris) checkSDKConnection(consumerAPI ConsumerClient, namespace string) error {
 	if consumerAPI == nil {
-		return fmt.Errorf("failed to create consumer API client")
+		return fmt.Errorf("consumer API client is not available")
 	}
 
 	// Try to create a simple service discovery request to validate connectivity
@@ -140,7 +388,7 @@ func (p *PlugPolaris) checkSDKConnection(sdk api.SDKContext, namespace string) e
 	_, err := consumerAPI.GetInstances(req)
 	if err != nil {
 		// If the error indicates the service is not found, connectivity is fine
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "no instances") {
+		if IsNotFoundError(err) {
 			log.Debugf("SDK connection test passed (service not found is expected)")
 			return nil
 		}
@@ -151,10 +399,9 @@ func (p *PlugPolaris) checkSDKConnection(sdk api.SDKContext, namespace string) e
 }
 
 // checkServiceDiscoveryHealth checks service discovery with a real GetInstances probe.
-func (p *PlugPolaris) checkServiceDiscoveryHealth(sdk api.SDKContext, namespace string) error {
-	consumerAPI := api.NewConsumerAPIByContext(sdk)
+func (p *PlugPolaris) checkServiceDiscoveryHealth(consumerAPI ConsumerClient, namespace string) error {
 	if consumerAPI == nil {
-		return fmt.Errorf("failed to create consumer API for discovery probe")
+		return fmt.Errorf("consumer API is not available for the discovery probe")
 	}
 	req := &api.GetInstancesRequest{
 		GetInstancesRequest: model.GetInstancesRequest{
@@ -164,7 +411,7 @@ func (p *PlugPolaris) checkServiceDiscoveryHealth(sdk api.SDKContext, namespace
 	}
 	_, err := consumerAPI.GetInstances(req)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "no instances") {
+		if IsNotFoundError(err) {
 			log.Debugf("Service discovery probe passed (service not found is expected)")
 			return nil
 		}
@@ -175,14 +422,13 @@ func (p *PlugPolaris) checkServiceDiscoveryHealth(sdk api.SDKContext, namespace
 }
 
 // checkConfigManagementHealth checks configuration management with a real GetConfigFile probe.
-func (p *PlugPolaris) checkConfigManagementHealth(sdk api.SDKContext, namespace string) error {
-	configAPI := api.NewConfigFileAPIBySDKContext(sdk)
+func (p *PlugPolaris) checkConfigManagementHealth(configAPI ConfigClient, namespace string) error {
 	if configAPI == nil {
-		return fmt.Errorf("failed to create config API for config probe")
+		return fmt.Errorf("config API is not available for the config probe")
 	}
 	_, err := configAPI.GetConfigFile(namespace, "DEFAULT_GROUP", "lynx-polaris-health-probe.yaml")
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "NotFound") {
+		if IsNotFoundError(err) {
 			log.Debugf("Config management probe passed (file not found is expected)")
 			return nil
 		}
@@ -0,0 +1,140 @@
+package polaris
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// Health states tracked for HealthChangedEvent publication.
+const (
+	healthStateUnknown int32 = iota
+	healthStateHealthy
+	healthStateUnhealthy
+)
+
+// HealthTransition is a change of the reported health state.
+type HealthTransition struct {
+	Healthy  bool `json:"healthy"`
+	Previous bool `json:"previous"`
+	// Results is the number of consecutive health check results that caused the change
+	Results   int       `json:"results"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// HealthStats describes the reported health state and its recent transitions.
+type HealthStats struct {
+	// State is "healthy", "unhealthy" or "unknown" before the first health check
+	State string `json:"state"`
+	// Pending is the number of consecutive results disagreeing with State, which change
+	// it once they reach the health_state threshold
+	Pending int `json:"pending"`
+	// SuppressedFlaps counts the state changes suppressed because fewer consecutive results
+	// than the threshold disagreed with the state
+	SuppressedFlaps int64              `json:"suppressed_flaps"`
+	History         []HealthTransition `json:"history"`
+}
+
+// healthStateSettings returns the unhealthy and healthy thresholds and the history size
+// with defaults applied.
+func healthStateSettings(cfg *conf.HealthState) (unhealthy, healthy, historySize int) {
+	unhealthy, healthy, historySize = conf.DefaultHealthUnhealthyThreshold, conf.DefaultHealthHealthyThreshold, conf.DefaultHealthHistorySize
+	if cfg.GetUnhealthyThreshold() > 0 {
+		unhealthy = int(cfg.GetUnhealthyThreshold())
+	}
+	if cfg.GetHealthyThreshold() > 0 {
+		healthy = int(cfg.GetHealthyThreshold())
+	}
+	if cfg.GetHistorySize() > 0 {
+		historySize = int(cfg.GetHistorySize())
+	}
+	return unhealthy, healthy, historySize
+}
+
+// recordHealthTransition feeds a health check result to the reported health state. The
+// state changes, is added to the history and is published as a HealthChangedEvent once
+// health_state.unhealthy_threshold consecutive checks failed, or healthy_threshold passed;
+// shorter runs are counted as suppressed flaps. The first observation after startup only
+// establishes the baseline state.
+func (p *PlugPolaris) recordHealthTransition(err error) {
+	observed := healthStateHealthy
+	if err != nil {
+		observed = healthStateUnhealthy
+	}
+	p.mu.RLock()
+	unhealthyThreshold, healthyThreshold, historySize := healthStateSettings(p.currentConf().GetHealthState())
+	p.mu.RUnlock()
+
+	p.healthStateMutex.Lock()
+	prev := atomic.LoadInt32(&p.lastHealth)
+	switch {
+	case prev == healthStateUnknown:
+		atomic.StoreInt32(&p.lastHealth, observed)
+		p.healthStateMutex.Unlock()
+		return
+	case prev == observed:
+		if p.healthPending > 0 {
+			p.healthSuppressedFlaps++
+			log.Debugf("Suppressed health flap after %d results", p.healthPending)
+		}
+		p.healthPending = 0
+		p.healthStateMutex.Unlock()
+		return
+	}
+	p.healthPending++
+	threshold := healthyThreshold
+	if observed == healthStateUnhealthy {
+		threshold = unhealthyThreshold
+	}
+	if p.healthPending < threshold {
+		p.healthStateMutex.Unlock()
+		return
+	}
+
+	transition := HealthTransition{
+		Healthy:   observed == healthStateHealthy,
+		Previous:  prev == healthStateHealthy,
+		Results:   p.healthPending,
+		Timestamp: time.Now(),
+	}
+	if err != nil {
+		transition.Error = p.redactError(err)
+	}
+	p.healthPending = 0
+	atomic.StoreInt32(&p.lastHealth, observed)
+	p.healthHistory = append(p.healthHistory, transition)
+	if len(p.healthHistory) > historySize {
+		p.healthHistory = append([]HealthTransition(nil), p.healthHistory[len(p.healthHistory)-historySize:]...)
+	}
+	p.healthStateMutex.Unlock()
+
+	p.publishEvent(&HealthChangedEvent{
+		Kind:      EventTypeHealthChanged,
+		Healthy:   transition.Healthy,
+		Previous:  transition.Previous,
+		Error:     transition.Error,
+		Timestamp: transition.Timestamp,
+	})
+}
+
+// healthStats returns the reported health state and its transitions, oldest first.
+func (p *PlugPolaris) healthStats() HealthStats {
+	p.healthStateMutex.Lock()
+	defer p.healthStateMutex.Unlock()
+	stats := HealthStats{
+		State:           "unknown",
+		Pending:         p.healthPending,
+		SuppressedFlaps: p.healthSuppressedFlaps,
+		History:         append([]HealthTransition{}, p.healthHistory...),
+	}
+	switch atomic.LoadInt32(&p.lastHealth) {
+	case healthStateHealthy:
+		stats.State = HealthStatusHealthy
+	case healthStateUnhealthy:
+		stats.State = HealthStatusUnhealthy
+	}
+	return stats
+}
@@ -0,0 +1,123 @@
+package polaris
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthReport_Status(t *testing.T) {
+	report := HealthReport{Status: HealthStatusHealthy}
+	report.add(ComponentHealth{Name: HealthComponentSDK, Status: HealthStatusHealthy})
+	report.add(ComponentHealth{Name: HealthComponentHeartbeat, Status: HealthStatusSkipped})
+	assert.Equal(t, HealthStatusHealthy, report.Status)
+	report.add(ComponentHealth{Name: HealthComponentRegistration, Status: HealthStatusDegraded})
+	assert.Equal(t, HealthStatusDegraded, report.Status)
+	report.add(ComponentHealth{Name: HealthComponentConfig, Status: HealthStatusUnhealthy})
+	report.add(ComponentHealth{Name: HealthComponentRateLimit, Status: HealthStatusDegraded})
+	assert.Equal(t, HealthStatusUnhealthy, report.Status)
+
+	c, ok := report.Component(HealthComponentConfig)
+	require.True(t, ok)
+	assert.Equal(t, HealthStatusUnhealthy, c.Status)
+	_, ok = report.Component("bogus")
+	assert.False(t, ok)
+}
+
+func TestCheckHealthReport_NoSDK(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	_, checked := plugin.LastHealthReport()
+	assert.False(t, checked)
+
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	plugin.setInitialized()
+	report, err := plugin.CheckHealthReport(context.Background())
+	assert.True(t, IsInitError(err))
+	assert.Equal(t, HealthStatusUnhealthy, report.Status)
+	sdk, ok := report.Component(HealthComponentSDK)
+	require.True(t, ok)
+	assert.Contains(t, sdk.Message, "Polaris instance is nil")
+	registration, _ := report.Component(HealthComponentRegistration)
+	assert.Equal(t, HealthStatusSkipped, registration.Status)
+
+	last, checked := plugin.LastHealthReport()
+	assert.True(t, checked)
+	assert.Equal(t, report.Timestamp, last.Timestamp)
+	assert.Equal(t, report.Components, plugin.GetHealth().Details["components"])
+}
+
+func TestRegistrationAndHeartbeatHealth(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", Ttl: 30})
+	assert.Equal(t, HealthStatusSkipped, plugin.heartbeatHealth(time.Now()).Status)
+
+	provider := &fakeClients{}
+	plugin.registrar = NewPolarisRegistrar(provider, "default")
+	require.NoError(t, plugin.registrar.Register(context.Background(), &registry.ServiceInstance{
+		Name: "svc", Endpoints: []string{"http://10.0.0.1:8080"},
+	}))
+	assert.Equal(t, HealthStatusHealthy, plugin.registrationHealth().Status)
+	assert.Equal(t, HealthStatusSkipped, plugin.heartbeatHealth(time.Now()).Status, "heartbeats are disabled")
+
+	// Heartbeats every 10s are stale after 30s without a success
+	plugin.currentConf().Heartbeat = &conf.Heartbeat{Enabled: true, FailureThreshold: 2}
+	assert.Equal(t, HealthStatusHealthy, plugin.heartbeatHealth(time.Now()).Status)
+	stale := plugin.heartbeatHealth(time.Now().Add(time.Minute))
+	assert.Equal(t, HealthStatusDegraded, stale.Status)
+	assert.Contains(t, stale.Message, "no successful heartbeat")
+
+	plugin.sendHeartbeats(plugin.registrar, 2)
+	assert.Equal(t, HealthStatusHealthy, plugin.heartbeatHealth(time.Now()).Status)
+	provider.failHeartbeats = true
+	plugin.sendHeartbeats(plugin.registrar, 2)
+	plugin.sendHeartbeats(plugin.registrar, 2)
+	failing := plugin.heartbeatHealth(time.Now())
+	assert.Equal(t, HealthStatusDegraded, failing.Status)
+	assert.Contains(t, failing.Message, "1 instances")
+}
+
+func TestRecordHealthTransition_Flapping(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", HealthState: &conf.HealthState{
+		UnhealthyThreshold: 3, HealthyThreshold: 2, HistorySize: 2,
+	}})
+	events, err := plugin.Subscribe(context.Background(), EventTypeHealthChanged)
+	require.NoError(t, err)
+
+	plugin.recordHealthTransition(nil) // baseline
+	plugin.recordHealthTransition(assert.AnError)
+	plugin.recordHealthTransition(assert.AnError)
+	plugin.recordHealthTransition(nil) // brief hiccup, suppressed
+	assert.Len(t, events, 0)
+	stats := plugin.GetStats().Health
+	assert.Equal(t, HealthStatusHealthy, stats.State)
+	assert.Equal(t, int64(1), stats.SuppressedFlaps)
+
+	for range 3 {
+		plugin.recordHealthTransition(assert.AnError)
+	}
+	require.Len(t, events, 1)
+	assert.False(t, (<-events).(*HealthChangedEvent).Healthy)
+	plugin.recordHealthTransition(nil)
+	assert.Equal(t, 1, plugin.GetStats().Health.Pending)
+	plugin.recordHealthTransition(nil)
+	require.Len(t, events, 1)
+	assert.True(t, (<-events).(*HealthChangedEvent).Healthy)
+
+	// The history keeps the last history_size transitions
+	for range 3 {
+		plugin.recordHealthTransition(assert.AnError)
+	}
+	stats = plugin.GetStats().Health
+	require.Len(t, stats.History, 2)
+	assert.True(t, stats.History[0].Healthy)
+	assert.Equal(t, 2, stats.History[0].Results)
+	assert.False(t, stats.History[1].Healthy)
+	assert.Equal(t, 3, stats.History[1].Results)
+	assert.NotEmpty(t, stats.History[1].Error)
+}
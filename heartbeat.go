@@ -0,0 +1,178 @@
+package polaris
+
+import (
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// HeartbeatFailure describes an instance whose heartbeats keep failing.
+type HeartbeatFailure struct {
+	Service string
+	Host    string
+	Port    int
+	// ConsecutiveFailures is the number of heartbeats that failed in a row.
+	ConsecutiveFailures int
+	// Err is the error of the last heartbeat.
+	Err error
+}
+
+// HeartbeatFailureHandler is called when the consecutive heartbeat failures of an instance
+// reach the configured threshold.
+type HeartbeatFailureHandler func(failure HeartbeatFailure)
+
+// OnHeartbeatFailure adds a handler called once each time an instance reaches
+// heartbeat.failure_threshold consecutive failures. Handlers run on the heartbeat loop and
+// should return quickly.
+func (p *PlugPolaris) OnHeartbeatFailure(handler HeartbeatFailureHandler) {
+	if handler == nil {
+		return
+	}
+	p.heartbeatMutex.Lock()
+	defer p.heartbeatMutex.Unlock()
+	p.heartbeatHandlers = append(p.heartbeatHandlers, handler)
+}
+
+// heartbeatConfig returns the heartbeat config snapshot and the registration TTL in seconds.
+func (p *PlugPolaris) heartbeatConfig() (*conf.Heartbeat, int) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	ttl := int(p.currentConf().GetTtl())
+	if ttl <= 0 {
+		ttl = conf.DefaultTTL
+	}
+	return p.currentConf().GetHeartbeat(), ttl
+}
+
+// heartbeatInterval returns the heartbeat interval, defaulting to a third of ttl.
+func heartbeatInterval(cfg *conf.Heartbeat, ttl int) time.Duration {
+	interval := time.Duration(ttl) * time.Second / 3
+	if cfg.GetInterval() != nil && cfg.GetInterval().AsDuration() > 0 {
+		interval = cfg.GetInterval().AsDuration()
+	}
+	return max(interval, conf.MinHeartbeatInterval)
+}
+
+// heartbeatJitter returns the jitter fraction with defaults and bounds applied.
+func heartbeatJitter(cfg *conf.Heartbeat) float64 {
+	if cfg.GetJitter() <= 0 {
+		return conf.DefaultHeartbeatJitter
+	}
+	return min(cfg.GetJitter(), conf.MaxHeartbeatJitter)
+}
+
+// heartbeatFailureThreshold returns the failure threshold with defaults applied.
+func heartbeatFailureThreshold(cfg *conf.Heartbeat) int {
+	if cfg.GetFailureThreshold() > 0 {
+		return int(cfg.GetFailureThreshold())
+	}
+	return conf.DefaultHeartbeatFailureThreshold
+}
+
+// jitteredInterval spreads base by up to jitter in both directions; r is uniform in [0, 1).
+func jitteredInterval(base time.Duration, jitter, r float64) time.Duration {
+	return time.Duration(float64(base) * (1 + jitter*(2*r-1)))
+}
+
+// sendHeartbeats sends a heartbeat for every instance of registrar, tracks consecutive
+// failures and calls the failure handlers when an instance reaches threshold.
+func (p *PlugPolaris) sendHeartbeats(registrar *PolarisRegistrar, threshold int) {
+	p.mu.RLock()
+	metrics := p.metrics
+	namespace := p.currentConf().GetNamespace()
+	p.mu.RUnlock()
+
+	targets := registrar.heartbeatTargets()
+	var failures []HeartbeatFailure
+	p.heartbeatMutex.Lock()
+	if p.heartbeatFailures == nil {
+		p.heartbeatFailures = make(map[string]int)
+	}
+	tracked := make(map[string]struct{}, len(targets))
+	for _, target := range targets {
+		tracked[target.key] = struct{}{}
+	}
+	for key := range p.heartbeatFailures {
+		if _, ok := tracked[key]; !ok {
+			delete(p.heartbeatFailures, key)
+		}
+	}
+	handlers := append([]HeartbeatFailureHandler(nil), p.heartbeatHandlers...)
+	p.heartbeatMutex.Unlock()
+
+	for _, target := range targets {
+		err := registrar.heartbeat(target)
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		if metrics != nil {
+			metrics.RecordServiceHeartbeat(target.service, namespace, status)
+		}
+
+		p.heartbeatMutex.Lock()
+		previous := p.heartbeatFailures[target.key]
+		if err == nil {
+			delete(p.heartbeatFailures, target.key)
+			p.lastHeartbeat = p.clock.Now()
+		} else {
+			p.heartbeatFailures[target.key] = previous + 1
+		}
+		p.heartbeatMutex.Unlock()
+
+		switch {
+		case err == nil && previous >= threshold:
+			log.Infof("Heartbeat of %s recovered after %d failures", target.key, previous)
+		case err != nil:
+			log.Warnf("Heartbeat of %s failed (%d in a row): %v", target.key, previous+1, p.redactError(err))
+			if previous+1 == threshold {
+				failures = append(failures, HeartbeatFailure{
+					Service: target.service, Host: target.host, Port: target.port,
+					ConsecutiveFailures: previous + 1, Err: err,
+				})
+			}
+		}
+	}
+
+	for _, failure := range failures {
+		p.sendHeartbeatAlert(failure)
+		for _, handler := range handlers {
+			handler(failure)
+		}
+	}
+}
+
+// startHeartbeat starts the heartbeat loop when heartbeats are enabled. Each wait is
+// jittered so instances started together spread their heartbeats. The loop stops with the
+// plugin lifecycle.
+func (p *PlugPolaris) startHeartbeat() {
+	cfg, ttl := p.heartbeatConfig()
+	if !cfg.GetEnabled() {
+		return
+	}
+	interval := heartbeatInterval(cfg, ttl)
+	jitter := heartbeatJitter(cfg)
+	threshold := heartbeatFailureThreshold(cfg)
+	ctx := p.watcherContext()
+	log.Infof("Starting heartbeats (ttl: %ds, interval: %v, jitter: %.0f%%, failure threshold: %d)",
+		ttl, interval, jitter*100, threshold)
+	go func() {
+		timer := p.clock.NewTimer(jitteredInterval(interval, jitter, p.rand.Float64()))
+		defer timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C():
+				p.mu.RLock()
+				registrar := p.registrar
+				p.mu.RUnlock()
+				if registrar != nil {
+					p.sendHeartbeats(registrar, threshold)
+				}
+				timer.Reset(jitteredInterval(interval, jitter, p.rand.Float64()))
+			}
+		}
+	}()
+}
@@ -0,0 +1,71 @@
+package polaris
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestHeartbeatSettings(t *testing.T) {
+	assert.Equal(t, 10*time.Second, heartbeatInterval(nil, 30))
+	assert.Equal(t, 4*time.Second, heartbeatInterval(&conf.Heartbeat{Interval: durationpb.New(4 * time.Second)}, 30))
+	assert.Equal(t, conf.MinHeartbeatInterval, heartbeatInterval(&conf.Heartbeat{Interval: durationpb.New(time.Millisecond)}, 30))
+
+	assert.Equal(t, conf.DefaultHeartbeatJitter, heartbeatJitter(nil))
+	assert.Equal(t, conf.MaxHeartbeatJitter, heartbeatJitter(&conf.Heartbeat{Jitter: 2}))
+	assert.Equal(t, conf.DefaultHeartbeatFailureThreshold, heartbeatFailureThreshold(nil))
+
+	assert.Equal(t, 9*time.Second, jitteredInterval(10*time.Second, 0.1, 0))
+	assert.Equal(t, 10*time.Second, jitteredInterval(10*time.Second, 0.1, 0.5))
+	assert.InDelta(t, float64(11*time.Second), float64(jitteredInterval(10*time.Second, 0.1, 0.9999)), float64(time.Millisecond))
+}
+
+func TestSendHeartbeats(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	var failures []HeartbeatFailure
+	plugin.OnHeartbeatFailure(func(f HeartbeatFailure) { failures = append(failures, f) })
+
+	provider := &fakeClients{}
+	reg := NewPolarisRegistrar(provider, "default")
+	reg.ttl = 30
+	require.NoError(t, reg.Register(context.Background(), &registry.ServiceInstance{
+		Name: "svc", Endpoints: []string{"http://10.0.0.1:8080"},
+	}))
+	assert.Equal(t, 30, *provider.registered[0].TTL)
+
+	plugin.sendHeartbeats(reg, 2)
+	require.Len(t, provider.heartbeats, 1)
+	assert.Equal(t, "10.0.0.1", provider.heartbeats[0].Host)
+	assert.Equal(t, 8080, provider.heartbeats[0].Port)
+
+	provider.failHeartbeats = true
+	plugin.sendHeartbeats(reg, 2)
+	assert.Empty(t, failures)
+	plugin.sendHeartbeats(reg, 2)
+	require.Len(t, failures, 1)
+	assert.Equal(t, 2, failures[0].ConsecutiveFailures)
+	assert.Equal(t, "svc", failures[0].Service)
+	// Handlers run once per threshold crossing.
+	plugin.sendHeartbeats(reg, 2)
+	assert.Len(t, failures, 1)
+
+	provider.failHeartbeats = false
+	plugin.sendHeartbeats(reg, 2)
+	assert.Empty(t, plugin.heartbeatFailures)
+}
+
+func TestRegistrarWithoutHeartbeatHasNoTTL(t *testing.T) {
+	provider := &fakeClients{}
+	reg := NewPolarisRegistrar(provider, "default")
+	require.NoError(t, reg.Register(context.Background(), &registry.ServiceInstance{
+		Name: "svc", Endpoints: []string{"http://10.0.0.1:8080"},
+	}))
+	assert.Nil(t, provider.registered[0].TTL)
+}
@@ -0,0 +1,116 @@
+package polaris
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx/log"
+)
+
+// RegistrationHook is called with a single-endpoint instance registered or deregistered by
+// the plugin's registrar. An instance with several endpoints triggers the hook once per
+// endpoint, since every endpoint is its own Polaris instance.
+type RegistrationHook func(ctx context.Context, instance *registry.ServiceInstance) error
+
+// registrationHooks holds the hooks run by PolarisRegistrar.
+type registrationHooks struct {
+	mu         sync.RWMutex
+	before     []RegistrationHook
+	after      []RegistrationHook
+	deregister []RegistrationHook
+}
+
+// snapshot returns a copy of hooks under the read lock. A nil receiver has no hooks.
+func (h *registrationHooks) snapshot(hooks *[]RegistrationHook) []RegistrationHook {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]RegistrationHook(nil), *hooks...)
+}
+
+// runBefore runs the before-register hooks, stopping at the first error.
+func (h *registrationHooks) runBefore(ctx context.Context, instance *registry.ServiceInstance) error {
+	if h == nil {
+		return nil
+	}
+	for _, hook := range h.snapshot(&h.before) {
+		if err := hook(ctx, instance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runAfter runs the after-register hooks. Their errors are logged, since the instance is
+// already registered.
+func (h *registrationHooks) runAfter(ctx context.Context, instance *registry.ServiceInstance) {
+	if h == nil {
+		return
+	}
+	for _, hook := range h.snapshot(&h.after) {
+		if err := hook(ctx, instance); err != nil {
+			log.Warnf("After-register hook failed for service %s at %v: %v", instance.Name, instance.Endpoints, err)
+		}
+	}
+}
+
+// runDeregister runs the deregister hooks, logging their errors.
+func (h *registrationHooks) runDeregister(ctx context.Context, instance *registry.ServiceInstance) {
+	if h == nil {
+		return
+	}
+	for _, hook := range h.snapshot(&h.deregister) {
+		if err := hook(ctx, instance); err != nil {
+			log.Warnf("Deregister hook failed for service %s at %v: %v", instance.Name, instance.Endpoints, err)
+		}
+	}
+}
+
+// registrationHooks returns the plugin's hooks, creating them on first use.
+func (p *PlugPolaris) registrationHooks() *registrationHooks {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.hooks == nil {
+		p.hooks = &registrationHooks{}
+	}
+	return p.hooks
+}
+
+// OnBeforeRegister adds a hook run before each instance is registered. An error aborts the
+// registration, rolling back the endpoints registered so far.
+func (p *PlugPolaris) OnBeforeRegister(hook RegistrationHook) {
+	if hook == nil {
+		return
+	}
+	h := p.registrationHooks()
+	h.mu.Lock()
+	h.before = append(h.before, hook)
+	h.mu.Unlock()
+}
+
+// OnAfterRegister adds a hook run after each instance is registered, e.g. to publish the
+// registration to a CMDB.
+func (p *PlugPolaris) OnAfterRegister(hook RegistrationHook) {
+	if hook == nil {
+		return
+	}
+	h := p.registrationHooks()
+	h.mu.Lock()
+	h.after = append(h.after, hook)
+	h.mu.Unlock()
+}
+
+// OnDeregister adds a hook run after each instance is deregistered, including
+// deregistration during shutdown.
+func (p *PlugPolaris) OnDeregister(hook RegistrationHook) {
+	if hook == nil {
+		return
+	}
+	h := p.registrationHooks()
+	h.mu.Lock()
+	h.deregister = append(h.deregister, hook)
+	h.mu.Unlock()
+}
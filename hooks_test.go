@@ -0,0 +1,80 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistrationHooks(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	var events []string
+	record := func(kind string) RegistrationHook {
+		return func(_ context.Context, instance *registry.ServiceInstance) error {
+			events = append(events, kind+" "+instance.Endpoints[0])
+			return nil
+		}
+	}
+	plugin.OnBeforeRegister(record("before"))
+	plugin.OnAfterRegister(record("after"))
+	plugin.OnDeregister(record("deregister"))
+
+	provider := &fakeClients{}
+	reg := NewPolarisRegistrar(provider, "default")
+	reg.hooks = plugin.registrationHooks()
+	svc := &registry.ServiceInstance{Name: "svc", Endpoints: []string{"http://10.0.0.1:8080", "grpc://10.0.0.1:9090"}}
+
+	require.NoError(t, reg.Register(context.Background(), svc))
+	require.NoError(t, reg.SetWeight(context.Background(), 50), "re-registration does not run hooks")
+	reg.Close(context.Background())
+	assert.Equal(t, []string{
+		"before http://10.0.0.1:8080", "after http://10.0.0.1:8080",
+		"before grpc://10.0.0.1:9090", "after grpc://10.0.0.1:9090",
+	}, events[:4])
+	assert.ElementsMatch(t, []string{"deregister http://10.0.0.1:8080", "deregister grpc://10.0.0.1:9090"}, events[4:])
+}
+
+func TestRegistrationHooks_BeforeRejects(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.OnBeforeRegister(func(_ context.Context, instance *registry.ServiceInstance) error {
+		if instance.Metadata[endpointProtocolMetadataKey] == "grpc" {
+			return errors.New("grpc not allowed")
+		}
+		return nil
+	})
+	var deregistered int
+	plugin.OnDeregister(func(context.Context, *registry.ServiceInstance) error {
+		deregistered++
+		return nil
+	})
+
+	provider := &fakeClients{}
+	reg := NewPolarisRegistrar(provider, "default")
+	reg.hooks = plugin.registrationHooks()
+	svc := &registry.ServiceInstance{Name: "svc", Endpoints: []string{"http://10.0.0.1:8080", "grpc://10.0.0.1:9090"}}
+
+	err := reg.Register(context.Background(), svc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "grpc not allowed")
+	// The HTTP endpoint registered before the rejection is rolled back.
+	assert.Len(t, provider.registered, 1)
+	assert.Len(t, provider.deregistered, 1)
+	assert.Equal(t, 1, deregistered)
+	assert.False(t, reg.hasInstances())
+}
+
+func TestServiceInfo_InstanceID(t *testing.T) {
+	info := &ServiceInfo{ID: "svc-node-1", Service: "svc", Host: "10.0.0.1", Port: 8080}
+	instance := info.ServiceInstance()
+	assert.Equal(t, "svc-node-1", instance.ID)
+
+	provider := &fakeClients{}
+	reg := NewPolarisRegistrar(provider, "default")
+	require.NoError(t, reg.Register(context.Background(), instance))
+	require.Len(t, provider.registered, 1)
+	assert.Equal(t, "svc-node-1", provider.registered[0].Metadata[instanceIDMetadataKey])
+}
@@ -0,0 +1,145 @@
+package polaris
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+)
+
+// interfaceAddrs returns the addresses of a network interface; replaced in tests.
+var interfaceAddrs = func(name string) ([]net.Addr, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if iface.Flags&net.FlagUp == 0 {
+		return nil, fmt.Errorf("interface %s is down", name)
+	}
+	return iface.Addrs()
+}
+
+// allInterfaceAddrs returns the addresses of every interface that is up; replaced in tests.
+var allInterfaceAddrs = func() ([]net.Addr, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var addrs []net.Addr
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		ifaceAddrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		addrs = append(addrs, ifaceAddrs...)
+	}
+	return addrs, nil
+}
+
+// isUnspecifiedHost reports whether host must be replaced by a detected address.
+func isUnspecifiedHost(host string) bool {
+	if host == "" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsUnspecified()
+}
+
+// addrIP returns the IP of an interface address.
+func addrIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.IPNet:
+		return a.IP
+	case *net.IPAddr:
+		return a.IP
+	}
+	return nil
+}
+
+// pickIP returns the first global unicast IP of addrs accepted by allow, preferring the
+// requested address family.
+func pickIP(addrs []net.Addr, preferIPv6 bool, allow func(net.IP) bool) net.IP {
+	var fallback net.IP
+	for _, addr := range addrs {
+		ip := addrIP(addr)
+		if ip == nil || !ip.IsGlobalUnicast() || !allow(ip) {
+			continue
+		}
+		if (ip.To4() == nil) == preferIPv6 {
+			return ip
+		}
+		if fallback == nil {
+			fallback = ip
+		}
+	}
+	return fallback
+}
+
+// detectHostIP detects the IP to advertise. Rules are tried in order: environment
+// variables, preferred interfaces, then every interface; with CIDRs configured, only
+// addresses inside them are accepted.
+func detectHostIP(cfg *conf.HostDetection) (string, error) {
+	var networks []*net.IPNet
+	for _, cidr := range cfg.GetCidrs() {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return "", fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+	allow := func(ip net.IP) bool {
+		if len(networks) == 0 {
+			return true
+		}
+		for _, network := range networks {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	envs := cfg.GetEnv()
+	if len(envs) == 0 {
+		envs = []string{conf.DefaultHostEnv}
+	}
+	for _, env := range envs {
+		value := strings.TrimSpace(os.Getenv(env))
+		if ip := net.ParseIP(value); ip != nil && !ip.IsUnspecified() && allow(ip) {
+			return ip.String(), nil
+		}
+	}
+
+	for _, name := range cfg.GetInterfaces() {
+		addrs, err := interfaceAddrs(name)
+		if err != nil {
+			continue
+		}
+		if ip := pickIP(addrs, cfg.GetPreferIpv6(), allow); ip != nil {
+			return ip.String(), nil
+		}
+	}
+
+	addrs, err := allInterfaceAddrs()
+	if err != nil {
+		return "", fmt.Errorf("failed to list interfaces: %w", err)
+	}
+	if ip := pickIP(addrs, cfg.GetPreferIpv6(), allow); ip != nil {
+		return ip.String(), nil
+	}
+	return "", fmt.Errorf("no address matches the host detection rules")
+}
+
+// DetectHostIP returns the IP the plugin advertises for empty or unspecified hosts,
+// following the host_detection rules.
+func (p *PlugPolaris) DetectHostIP() (string, error) {
+	p.mu.RLock()
+	cfg := p.currentConf().GetHostDetection()
+	p.mu.RUnlock()
+	return detectHostIP(cfg)
+}
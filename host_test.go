@@ -0,0 +1,112 @@
+package polaris
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stubInterfaces(t *testing.T, byName map[string][]string) {
+	t.Helper()
+	toAddrs := func(cidrs []string) []net.Addr {
+		var addrs []net.Addr
+		for _, cidr := range cidrs {
+			ip, network, err := net.ParseCIDR(cidr)
+			require.NoError(t, err)
+			addrs = append(addrs, &net.IPNet{IP: ip, Mask: network.Mask})
+		}
+		return addrs
+	}
+	origIface, origAll := interfaceAddrs, allInterfaceAddrs
+	interfaceAddrs = func(name string) ([]net.Addr, error) {
+		cidrs, ok := byName[name]
+		if !ok {
+			return nil, &net.OpError{Op: "route", Err: net.UnknownNetworkError(name)}
+		}
+		return toAddrs(cidrs), nil
+	}
+	allInterfaceAddrs = func() ([]net.Addr, error) {
+		var all []net.Addr
+		for _, name := range []string{"eth0", "eth1"} {
+			all = append(all, toAddrs(byName[name])...)
+		}
+		return all, nil
+	}
+	t.Cleanup(func() { interfaceAddrs, allInterfaceAddrs = origIface, origAll })
+}
+
+func TestDetectHostIP(t *testing.T) {
+	stubInterfaces(t, map[string][]string{
+		"eth0": {"172.17.0.5/16", "fe80::1/64"},
+		"eth1": {"10.20.0.7/16", "2001:db8::7/64"},
+	})
+	t.Setenv(conf.DefaultHostEnv, "")
+
+	ip, err := detectHostIP(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "172.17.0.5", ip, "first global unicast address")
+
+	ip, err = detectHostIP(&conf.HostDetection{Interfaces: []string{"missing", "eth1"}})
+	require.NoError(t, err)
+	assert.Equal(t, "10.20.0.7", ip)
+
+	ip, err = detectHostIP(&conf.HostDetection{Interfaces: []string{"eth1"}, PreferIpv6: true})
+	require.NoError(t, err)
+	assert.Equal(t, "2001:db8::7", ip)
+
+	ip, err = detectHostIP(&conf.HostDetection{Cidrs: []string{"10.0.0.0/8"}})
+	require.NoError(t, err)
+	assert.Equal(t, "10.20.0.7", ip)
+
+	_, err = detectHostIP(&conf.HostDetection{Cidrs: []string{"192.168.0.0/16"}})
+	assert.Error(t, err)
+	_, err = detectHostIP(&conf.HostDetection{Cidrs: []string{"bogus"}})
+	assert.Error(t, err)
+
+	t.Setenv(conf.DefaultHostEnv, "10.244.1.9")
+	ip, err = detectHostIP(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "10.244.1.9", ip)
+	// The environment value must still satisfy the CIDR allowlist.
+	ip, err = detectHostIP(&conf.HostDetection{Cidrs: []string{"10.20.0.0/16"}})
+	require.NoError(t, err)
+	assert.Equal(t, "10.20.0.7", ip)
+}
+
+func TestPolarisRegistrar_AdvertisedEndpoint(t *testing.T) {
+	provider := &fakeClients{}
+	reg := NewPolarisRegistrar(provider, "default")
+	reg.advertiseHost = func() (string, error) { return "10.0.0.9", nil }
+
+	assert.Equal(t, "http://10.0.0.1:8080", reg.advertisedEndpoint("http://10.0.0.1:8080"))
+	assert.Equal(t, "grpc://10.0.0.9:9090", reg.advertisedEndpoint("grpc://0.0.0.0:9090"))
+	assert.Equal(t, "http://10.0.0.9:8080", reg.advertisedEndpoint("http://[::]:8080"))
+
+	svc := &registry.ServiceInstance{Name: "svc", Endpoints: []string{"grpc://0.0.0.0:9090"}}
+	require.NoError(t, reg.Register(context.Background(), svc))
+	require.Len(t, provider.registered, 1)
+	assert.Equal(t, "10.0.0.9", provider.registered[0].Host)
+
+	require.NoError(t, reg.Deregister(context.Background(), svc))
+	require.Len(t, provider.deregistered, 1)
+	assert.Equal(t, "10.0.0.9", provider.deregistered[0].Host)
+	assert.False(t, reg.hasInstances())
+}
+
+func TestSetServiceInfo_DetectsHost(t *testing.T) {
+	stubInterfaces(t, map[string][]string{"eth0": {"172.17.0.5/16"}})
+	t.Setenv(conf.DefaultHostEnv, "")
+	plugin := NewPolarisControlPlane()
+	plugin.SetServiceInfo(&ServiceInfo{
+		Service: "svc", Host: "0.0.0.0", Port: 8080,
+		Endpoints: []ServiceEndpoint{{Host: "0.0.0.0", Port: 9090, Protocol: "grpc"}},
+	})
+	info := plugin.GetServiceInfo()
+	assert.Equal(t, "172.17.0.5", info.Host)
+	assert.Equal(t, "172.17.0.5", info.Endpoints[0].Host)
+}
@@ -0,0 +1,74 @@
+package polaris
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-lynx/lynx/log"
+)
+
+// Hot services module
+// Responsibility: resolving and watching the hot_services at startup, so that the first calls
+// to them after a deploy do not wait for discovery. Their watchers keep the SDK and instance
+// caches warm afterwards.
+
+// prefetchHotServices resolves and watches the hot services in the background. With
+// hot_services.wait, it waits for them for up to that long; startup never fails because of
+// them.
+func (p *PlugPolaris) prefetchHotServices(ctx context.Context) {
+	p.mu.RLock()
+	cfg := p.currentConf().GetHotServices()
+	p.mu.RUnlock()
+	services := cfg.GetServices()
+	if len(services) == 0 || !p.SubsystemEnabled(SubsystemDiscovery) {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		start := time.Now()
+		var wg sync.WaitGroup
+		var resolved atomic.Int32
+		for _, service := range services {
+			wg.Go(func() {
+				if p.prefetchService(service) {
+					resolved.Add(1)
+				}
+			})
+		}
+		wg.Wait()
+		log.Infof("Prefetched %d of %d hot services in %v", resolved.Load(), len(services), time.Since(start))
+	}()
+
+	wait := cfg.GetWait().AsDuration()
+	if wait <= 0 {
+		return
+	}
+	timer := p.clock.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C():
+		log.Warnf("Hot services not prefetched after %v, continuing startup", wait)
+	case <-ctx.Done():
+	}
+}
+
+// prefetchService watches serviceName and resolves its instances right away, which caches
+// them through the change callback. It reports whether they were resolved.
+func (p *PlugPolaris) prefetchService(serviceName string) bool {
+	watcher, err := p.WatchService(serviceName)
+	if err != nil {
+		log.Warnf("Failed to watch hot service %s: %v", serviceName, err)
+		return false
+	}
+	watcher.Refresh()
+	if watcher.LastEventTime().IsZero() {
+		log.Warnf("Failed to resolve hot service %s, its watcher keeps trying", serviceName)
+		return false
+	}
+	return true
+}
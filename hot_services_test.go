@@ -0,0 +1,30 @@
+package polaris
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestPrefetchHotServices(t *testing.T) {
+	instance := NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})
+	consumer := &fakeClients{services: map[string][]model.Instance{"orders": {instance}}}
+	plugin := newBuilderTestPlugin(t, &fakeClients{}, consumer)
+	plugin.currentConf().HotServices = &conf.HotServices{Services: []string{"orders", "missing"}, Wait: durationpb.New(time.Second)}
+
+	plugin.prefetchHotServices(context.Background())
+
+	plugin.watcherMutex.RLock()
+	assert.Len(t, plugin.activeWatchers, 2, "unresolved services are watched too")
+	plugin.watcherMutex.RUnlock()
+	cached := plugin.cachedServiceInstances("orders")
+	require.Len(t, cached, 1, "resolved before startup continues")
+	assert.Equal(t, "10.0.0.1", cached[0].GetHost())
+	assert.Empty(t, plugin.cachedServiceInstances("missing"))
+}
@@ -0,0 +1,199 @@
+package polaris
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Rate limit response headers set on rejected HTTP requests.
+const (
+	headerRetryAfter         = "Retry-After"
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// HTTPRateLimitOption customizes the HTTP rate limit middleware.
+type HTTPRateLimitOption func(*httpRateLimitOptions)
+
+type httpRateLimitOptions struct {
+	service    string
+	headerKeys []string
+	labels     func(r *http.Request) map[string]string
+	limit      int
+	failClosed bool
+}
+
+// WithHTTPRateLimitService sets the Polaris service whose rate limit rules apply. It
+// defaults to the name of the running application.
+func WithHTTPRateLimitService(service string) HTTPRateLimitOption {
+	return func(o *httpRateLimitOptions) {
+		o.service = service
+	}
+}
+
+// WithHTTPRateLimitHeaders adds the value of each of the request headers to the labels,
+// under the header name as given. Missing headers are skipped.
+func WithHTTPRateLimitHeaders(keys ...string) HTTPRateLimitOption {
+	return func(o *httpRateLimitOptions) {
+		o.headerKeys = append(o.headerKeys, keys...)
+	}
+}
+
+// WithHTTPRateLimitLabels adds the labels returned by fn for each request. They take
+// precedence over the method, path and header labels.
+func WithHTTPRateLimitLabels(fn func(r *http.Request) map[string]string) HTTPRateLimitOption {
+	return func(o *httpRateLimitOptions) {
+		o.labels = fn
+	}
+}
+
+// WithHTTPRateLimitLimit sets the X-RateLimit-Limit header of rejected requests. Polaris
+// quota results do not carry the rule amount, so the header is omitted unless set.
+func WithHTTPRateLimitLimit(limit int) HTTPRateLimitOption {
+	return func(o *httpRateLimitOptions) {
+		o.limit = limit
+	}
+}
+
+// WithHTTPRateLimitFailClosed rejects requests with 503 Service Unavailable when the quota
+// cannot be checked, e.g. because Polaris is unreachable. By default they are let through.
+func WithHTTPRateLimitFailClosed() HTTPRateLimitOption {
+	return func(o *httpRateLimitOptions) {
+		o.failClosed = true
+	}
+}
+
+// httpRateLimiter checks the quota of incoming HTTP requests.
+type httpRateLimiter struct {
+	gate *quotaGate
+	opts httpRateLimitOptions
+}
+
+// newHTTPRateLimiter applies opts over the defaults.
+func (p *PlugPolaris) newHTTPRateLimiter(opts []HTTPRateLimitOption) *httpRateLimiter {
+	limiter := &httpRateLimiter{}
+	for _, opt := range opts {
+		opt(&limiter.opts)
+	}
+	limiter.gate = p.newQuotaGate(limiter.opts.service)
+	return limiter
+}
+
+// HTTPRateLimitHandler returns net/http middleware that checks every request against the
+// Polaris rate limit rules of the service. The quota is requested for the URL path, with
+// the labels "method" (the HTTP method), "path", the configured headers and any custom
+// labels. A rejected request gets 429 Too Many Requests with Retry-After,
+// X-RateLimit-Remaining and X-RateLimit-Reset headers derived from the Polaris result.
+func (p *PlugPolaris) HTTPRateLimitHandler(opts ...HTTPRateLimitOption) func(http.Handler) http.Handler {
+	return p.newHTTPRateLimiter(opts).handler
+}
+
+// HTTPRateLimitMiddleware returns Kratos middleware with the behavior of
+// HTTPRateLimitHandler. Rejections are returned as Kratos errors with reason RATE_LIMITED
+// and code 429, and the rate limit headers are set on the reply. Requests of other
+// transports pass through unchecked.
+func (p *PlugPolaris) HTTPRateLimitMiddleware(opts ...HTTPRateLimitOption) middleware.Middleware {
+	return p.newHTTPRateLimiter(opts).middleware
+}
+
+// handler wraps next with the quota check.
+func (l *httpRateLimiter) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, retryAfter := l.check(r)
+		if status == http.StatusOK {
+			next.ServeHTTP(w, r)
+			return
+		}
+		l.setHeaders(w.Header(), status, retryAfter)
+		http.Error(w, http.StatusText(status), status)
+	})
+}
+
+// middleware wraps a Kratos handler with the quota check.
+func (l *httpRateLimiter) middleware(handler middleware.Handler) middleware.Handler {
+	return func(ctx context.Context, req any) (any, error) {
+		tr, ok := transport.FromServerContext(ctx)
+		if !ok {
+			return handler(ctx, req)
+		}
+		ht, ok := tr.(khttp.Transporter)
+		if !ok || ht.Request() == nil {
+			return handler(ctx, req)
+		}
+		status, retryAfter := l.check(ht.Request())
+		if status == http.StatusOK {
+			return handler(ctx, req)
+		}
+		l.setHeaders(tr.ReplyHeader(), status, retryAfter)
+		if status == http.StatusTooManyRequests {
+			return nil, errors.New(status, "RATE_LIMITED", "rate limited by Polaris")
+		}
+		return nil, errors.New(status, "RATE_LIMIT_UNAVAILABLE", "rate limit check failed")
+	}
+}
+
+// labels builds the rate limit labels of a request.
+func (l *httpRateLimiter) labels(r *http.Request) map[string]string {
+	labels := map[string]string{"method": r.Method, "path": r.URL.Path}
+	for _, key := range l.opts.headerKeys {
+		if value := r.Header.Get(key); value != "" {
+			labels[key] = value
+		}
+	}
+	if l.opts.labels != nil {
+		for key, value := range l.opts.labels(r) {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// check returns http.StatusOK when the request may proceed, or the status to reject it
+// with and the seconds after which it may be retried.
+func (l *httpRateLimiter) check(r *http.Request) (int, int64) {
+	result, _, err := l.gate.acquire(r.URL.Path, l.labels(r))
+	if err != nil {
+		if l.opts.failClosed {
+			return http.StatusServiceUnavailable, 0
+		}
+		log.Warnf("Rate limit check failed for %s %s, allowing request: %v", r.Method, r.URL.Path, err)
+		return http.StatusOK, 0
+	}
+	if result.Code == model.QuotaResultOk {
+		return http.StatusOK, 0
+	}
+	return http.StatusTooManyRequests, retryAfterSeconds(result.WaitMs)
+}
+
+// retryAfterSeconds rounds a Polaris wait up to whole seconds, at least one.
+func retryAfterSeconds(waitMs int64) int64 {
+	return max(1, (waitMs+999)/1000)
+}
+
+// headerSetter is the part of http.Header and transport.Header used for rate limit headers.
+type headerSetter interface {
+	Set(key, value string)
+}
+
+// setHeaders sets the rate limit headers of a rejection.
+func (l *httpRateLimiter) setHeaders(h headerSetter, status int, retryAfter int64) {
+	if status != http.StatusTooManyRequests {
+		return
+	}
+	seconds := strconv.FormatInt(retryAfter, 10)
+	h.Set(headerRetryAfter, seconds)
+	h.Set(headerRateLimitRemaining, "0")
+	h.Set(headerRateLimitReset, seconds)
+	if l.opts.limit > 0 {
+		h.Set(headerRateLimitLimit, strconv.Itoa(l.opts.limit))
+	}
+}
@@ -0,0 +1,134 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestHTTPRateLimiter returns a rate limiter whose quota checks answer with result and err.
+func newTestHTTPRateLimiter(result *model.QuotaResponse, err error, opts ...HTTPRateLimitOption) (*httpRateLimiter, *[]quotaCall) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	limiter := plugin.newHTTPRateLimiter(append([]HTTPRateLimitOption{WithHTTPRateLimitService("orders")}, opts...))
+	calls := &[]quotaCall{}
+	limiter.gate.getQuota = func(service, method string, labels map[string]string) (*model.QuotaResponse, error) {
+		*calls = append(*calls, quotaCall{service: service, method: method, labels: labels})
+		return result, err
+	}
+	return limiter, calls
+}
+
+// serve runs a request through the net/http middleware of limiter.
+func serve(limiter *httpRateLimiter, r *http.Request) (*httptest.ResponseRecorder, bool) {
+	called := false
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true })
+	w := httptest.NewRecorder()
+	limiter.handler(next).ServeHTTP(w, r)
+	return w, called
+}
+
+func TestHTTPRateLimitHandler_AllowsAndBuildsLabels(t *testing.T) {
+	limiter, calls := newTestHTTPRateLimiter(&model.QuotaResponse{Code: model.QuotaResultOk}, nil,
+		WithHTTPRateLimitHeaders("X-Tenant", "X-Missing"),
+		WithHTTPRateLimitLabels(func(*http.Request) map[string]string { return map[string]string{"region": "eu"} }))
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/orders?dry_run=1", nil)
+	r.Header.Set("X-Tenant", "acme")
+	w, called := serve(limiter, r)
+
+	assert.True(t, called)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(headerRetryAfter))
+	require.Len(t, *calls, 1)
+	assert.Equal(t, quotaCall{
+		service: "orders",
+		method:  "/v1/orders",
+		labels:  map[string]string{"method": "POST", "path": "/v1/orders", "X-Tenant": "acme", "region": "eu"},
+	}, (*calls)[0])
+}
+
+func TestHTTPRateLimitHandler_RejectsWith429(t *testing.T) {
+	limiter, _ := newTestHTTPRateLimiter(&model.QuotaResponse{Code: model.QuotaResultLimited, WaitMs: 1500}, nil,
+		WithHTTPRateLimitLimit(100))
+
+	w, called := serve(limiter, httptest.NewRequest(http.MethodGet, "/v1/orders", nil))
+	assert.False(t, called)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get(headerRetryAfter))
+	assert.Equal(t, "2", w.Header().Get(headerRateLimitReset))
+	assert.Equal(t, "0", w.Header().Get(headerRateLimitRemaining))
+	assert.Equal(t, "100", w.Header().Get(headerRateLimitLimit))
+
+	limiter, _ = newTestHTTPRateLimiter(&model.QuotaResponse{Code: model.QuotaResultLimited}, nil)
+	w, _ = serve(limiter, httptest.NewRequest(http.MethodGet, "/v1/orders", nil))
+	assert.Equal(t, "1", w.Header().Get(headerRetryAfter))
+	assert.Empty(t, w.Header().Get(headerRateLimitLimit))
+}
+
+func TestHTTPRateLimitHandler_CheckFailure(t *testing.T) {
+	limiter, _ := newTestHTTPRateLimiter(nil, errors.New("polaris unreachable"))
+	_, called := serve(limiter, httptest.NewRequest(http.MethodGet, "/v1/orders", nil))
+	assert.True(t, called)
+
+	limiter, _ = newTestHTTPRateLimiter(nil, errors.New("polaris unreachable"), WithHTTPRateLimitFailClosed())
+	w, called := serve(limiter, httptest.NewRequest(http.MethodGet, "/v1/orders", nil))
+	assert.False(t, called)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Empty(t, w.Header().Get(headerRetryAfter))
+}
+
+// testHeader is a transport.Header backed by http.Header.
+type testHeader http.Header
+
+func (h testHeader) Get(key string) string      { return http.Header(h).Get(key) }
+func (h testHeader) Set(key, value string)      { http.Header(h).Set(key, value) }
+func (h testHeader) Add(key, value string)      { http.Header(h).Add(key, value) }
+func (h testHeader) Values(key string) []string { return http.Header(h).Values(key) }
+func (h testHeader) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// testHTTPTransport is a Kratos HTTP server transport for a request.
+type testHTTPTransport struct {
+	request *http.Request
+	reply   testHeader
+}
+
+func (t *testHTTPTransport) Kind() transport.Kind            { return transport.KindHTTP }
+func (t *testHTTPTransport) Endpoint() string                { return "" }
+func (t *testHTTPTransport) Operation() string               { return t.request.URL.Path }
+func (t *testHTTPTransport) RequestHeader() transport.Header { return testHeader(t.request.Header) }
+func (t *testHTTPTransport) ReplyHeader() transport.Header   { return t.reply }
+func (t *testHTTPTransport) Request() *http.Request          { return t.request }
+func (t *testHTTPTransport) PathTemplate() string            { return t.request.URL.Path }
+
+func TestHTTPRateLimitMiddleware_Kratos(t *testing.T) {
+	limiter, calls := newTestHTTPRateLimiter(&model.QuotaResponse{Code: model.QuotaResultLimited, WaitMs: 300}, nil)
+	handler := limiter.middleware(func(context.Context, any) (any, error) { return "ok", nil })
+
+	reply, err := handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", reply)
+	assert.Empty(t, *calls)
+
+	tr := &testHTTPTransport{request: httptest.NewRequest(http.MethodGet, "/v1/orders", nil), reply: testHeader{}}
+	_, err = handler(transport.NewServerContext(context.Background(), tr), nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, kerrors.Code(err))
+	assert.Equal(t, "RATE_LIMITED", kerrors.Reason(err))
+	assert.Equal(t, "1", tr.reply.Get(headerRetryAfter))
+}
@@ -0,0 +1,56 @@
+package polaris
+
+import (
+	"context"
+
+	"github.com/go-lynx/lynx/log"
+)
+
+// Isolate isolates the instances registered through the plugin's registrar on the Polaris
+// server, taking them out of rotation without deregistering them. Use it as a maintenance
+// switch; Unisolate restores traffic.
+func (p *PlugPolaris) Isolate() error {
+	return p.setIsolated(true)
+}
+
+// Unisolate clears the isolated flag set by Isolate so the instances receive traffic again.
+func (p *PlugPolaris) Unisolate() error {
+	return p.setIsolated(false)
+}
+
+// IsIsolated reports whether the plugin's instances are isolated.
+func (p *PlugPolaris) IsIsolated() bool {
+	p.mu.RLock()
+	registrar := p.registrar
+	p.mu.RUnlock()
+	return registrar != nil && registrar.Isolated()
+}
+
+func (p *PlugPolaris) setIsolated(isolated bool) error {
+	if err := p.checkInitialized(); err != nil {
+		return err
+	}
+	p.mu.RLock()
+	registrar := p.registrar
+	p.mu.RUnlock()
+	if registrar == nil {
+		return NewInitError("Polaris registrar is not available")
+	}
+	changed := registrar.Isolated() != isolated
+	if err := registrar.SetIsolated(WithToken(context.Background(), p.operationToken(TokenOperationIsolation)), isolated); err != nil {
+		return WrapServiceError(err, ErrCodeServiceRegistration, "failed to update instance isolation")
+	}
+	if isolated {
+		log.Infof("Instances isolated for maintenance")
+	} else {
+		log.Infof("Instances returned to rotation")
+	}
+	if changed {
+		eventType := AuditInstancesUnisolated
+		if isolated {
+			eventType = AuditInstancesIsolated
+		}
+		p.recordApplicationAudit(AuditEvent{Type: eventType})
+	}
+	return nil
+}
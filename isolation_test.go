@@ -0,0 +1,62 @@
+package polaris
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolarisRegistrar_SetIsolated(t *testing.T) {
+	provider := &fakeClients{}
+	reg := NewPolarisRegistrar(provider, "default")
+	svc := &registry.ServiceInstance{Name: "svc", Endpoints: []string{"http://10.0.0.1:8080", "grpc://10.0.0.1:9090"}}
+	require.NoError(t, reg.Register(context.Background(), svc))
+	assert.False(t, *provider.registered[0].Isolate)
+	provider.registered = nil
+
+	require.NoError(t, reg.SetIsolated(context.Background(), true))
+	assert.True(t, reg.Isolated())
+	require.Len(t, provider.registered, 2)
+	for _, req := range provider.registered {
+		assert.True(t, *req.Isolate)
+		assert.True(t, *req.Healthy)
+	}
+
+	// Unchanged flag does not re-register.
+	provider.registered = nil
+	require.NoError(t, reg.SetIsolated(context.Background(), true))
+	assert.Empty(t, provider.registered)
+
+	// Weight changes keep the instances isolated.
+	require.NoError(t, reg.SetWeight(context.Background(), 50))
+	require.Len(t, provider.registered, 2)
+	assert.True(t, *provider.registered[0].Isolate)
+}
+
+func TestIsolate(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Weight: 100})
+	assert.Error(t, plugin.Isolate(), "not initialized")
+
+	atomic.StoreInt32(&plugin.initialized, 1)
+	assert.Error(t, plugin.Isolate(), "no registrar yet")
+
+	provider := &fakeClients{}
+	plugin.registrar = NewPolarisRegistrar(provider, "default")
+	require.NoError(t, plugin.registrar.Register(context.Background(), &registry.ServiceInstance{
+		Name: "svc", Endpoints: []string{"http://10.0.0.1:8080"},
+	}))
+
+	require.NoError(t, plugin.Isolate())
+	assert.True(t, plugin.IsIsolated())
+	assert.True(t, *provider.registered[len(provider.registered)-1].Isolate)
+
+	require.NoError(t, plugin.Unisolate())
+	assert.False(t, plugin.IsIsolated())
+	assert.False(t, *provider.registered[len(provider.registered)-1].Isolate)
+}
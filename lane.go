@@ -0,0 +1,135 @@
+package polaris
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-polaris/conf"
+)
+
+// Lane module
+// Responsibility: full-link gray routing. The lane of a request, e.g. "canary", travels in a
+// header from service to service, and the node router keeps the calls of a lane on the
+// instances whose lane metadata matches it. polaris-go has no lane router, so the lanes are
+// matched by the plugin after the Polaris routing rules.
+
+type laneContextKey struct{}
+
+// WithLane returns a context whose calls through the node router are kept on the instances
+// of lane. An empty lane returns ctx unchanged.
+func WithLane(ctx context.Context, lane string) context.Context {
+	if lane == "" {
+		return ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, laneContextKey{}, lane)
+}
+
+// LaneFromContext returns the lane set on ctx by WithLane or ExtractLane, or an empty string.
+func LaneFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	lane, _ := ctx.Value(laneContextKey{}).(string)
+	return lane
+}
+
+// laneSettings returns the lane header, the instance metadata key and strict mode.
+func (p *PlugPolaris) laneSettings() (header, metadataKey string, strict bool) {
+	p.mu.RLock()
+	cfg := p.currentConf().GetLane()
+	p.mu.RUnlock()
+	header, metadataKey = cfg.GetHeader(), cfg.GetMetadataKey()
+	if header == "" {
+		header = conf.DefaultLaneHeader
+	}
+	if metadataKey == "" {
+		metadataKey = conf.DefaultLaneMetadataKey
+	}
+	return header, metadataKey, cfg.GetStrict()
+}
+
+// ExtractLane returns ctx with the lane of the lane header of the incoming Kratos HTTP or
+// gRPC request of ctx. ctx is returned unchanged when the request has no lane.
+func (p *PlugPolaris) ExtractLane(ctx context.Context) context.Context {
+	tr, ok := transport.FromServerContext(ctx)
+	if !ok {
+		return ctx
+	}
+	header, _, _ := p.laneSettings()
+	return WithLane(ctx, strings.TrimSpace(tr.RequestHeader().Get(header)))
+}
+
+// InjectLane sets the lane header of the outgoing Kratos HTTP or gRPC request of ctx to
+// the lane of ctx. Requests are left unchanged when ctx has no lane.
+func (p *PlugPolaris) InjectLane(ctx context.Context) {
+	lane := LaneFromContext(ctx)
+	if lane == "" {
+		return
+	}
+	if tr, ok := transport.FromClientContext(ctx); ok {
+		header, _, _ := p.laneSettings()
+		tr.RequestHeader().Set(header, lane)
+	}
+}
+
+// LaneServerMiddleware returns Kratos server middleware putting the lane of every request
+// into its context with ExtractLane.
+func (p *PlugPolaris) LaneServerMiddleware() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			return handler(p.ExtractLane(ctx), req)
+		}
+	}
+}
+
+// LaneClientMiddleware returns Kratos client middleware passing the lane of the context of
+// every call on with InjectLane, so that the next services keep the lane.
+func (p *PlugPolaris) LaneClientMiddleware() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			p.InjectLane(ctx)
+			return handler(ctx, req)
+		}
+	}
+}
+
+// laneNodeFilter wraps next so that calls of a lane only go to the nodes of the lane, or,
+// when the lane has none and lane.strict is not set, like calls without a lane. Those go
+// to the nodes without a lane, or to every node when all of them have one.
+func (p *PlugPolaris) laneNodeFilter(next selector.NodeFilter) selector.NodeFilter {
+	return func(ctx context.Context, nodes []selector.Node) []selector.Node {
+		if next != nil {
+			nodes = next(ctx, nodes)
+		}
+		_, metadataKey, strict := p.laneSettings()
+		return laneNodes(nodes, metadataKey, LaneFromContext(ctx), strict)
+	}
+}
+
+// laneNodes returns the nodes of lane, read from their metadataKey metadata.
+func laneNodes(nodes []selector.Node, metadataKey, lane string, strict bool) []selector.Node {
+	var inLane, baseline []selector.Node
+	for _, node := range nodes {
+		switch nodeLane := node.Metadata()[metadataKey]; {
+		case nodeLane == "":
+			baseline = append(baseline, node)
+		case lane != "" && nodeLane == lane:
+			inLane = append(inLane, node)
+		}
+	}
+	switch {
+	case len(inLane) > 0:
+		return inLane
+	case lane != "" && strict:
+		return nil
+	case len(baseline) > 0:
+		return baseline
+	}
+	return nodes
+}
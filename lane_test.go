@@ -0,0 +1,85 @@
+package polaris
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func laneNode(addr, lane string) selector.Node {
+	metadata := map[string]string{}
+	if lane != "" {
+		metadata["lane"] = lane
+	}
+	return selector.NewNode("grpc", addr, &registry.ServiceInstance{Name: "orders", Metadata: metadata})
+}
+
+func TestLaneNodes(t *testing.T) {
+	base := laneNode("10.0.0.1:9000", "")
+	canary := laneNode("10.0.0.2:9000", "canary")
+	blue := laneNode("10.0.0.3:9000", "blue")
+	all := []selector.Node{base, canary, blue}
+
+	assert.Equal(t, []selector.Node{canary}, laneNodes(all, "lane", "canary", false))
+	assert.Equal(t, []selector.Node{base}, laneNodes(all, "lane", "", false), "calls without a lane stay off the lanes")
+	assert.Equal(t, []selector.Node{base}, laneNodes(all, "lane", "green", false), "lanes without instances fall back to the baseline")
+	assert.Empty(t, laneNodes(all, "lane", "green", true))
+	assert.Equal(t, []selector.Node{canary, blue}, laneNodes([]selector.Node{canary, blue}, "lane", "", false), "services only deployed in lanes")
+	assert.Equal(t, all, laneNodes(all, "zone", "canary", false), "nodes are read with the metadata key")
+}
+
+func TestLaneNodeFilter(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Lane: &conf.Lane{MetadataKey: "env", Strict: true}})
+	node := func(addr, env string) selector.Node {
+		return selector.NewNode("grpc", addr, &registry.ServiceInstance{Name: "orders", Metadata: map[string]string{"env": env}})
+	}
+	nodes := []selector.Node{node("10.0.0.1:9000", ""), node("10.0.0.2:9000", "canary")}
+	dropFirst := func(_ context.Context, nodes []selector.Node) []selector.Node { return nodes[1:] }
+
+	filter := plugin.laneNodeFilter(nil)
+	assert.Equal(t, nodes[1:], filter(WithLane(context.Background(), "canary"), nodes))
+	assert.Empty(t, filter(WithLane(context.Background(), "blue"), nodes), "strict lanes do not fall back")
+	assert.Equal(t, nodes[:1], filter(context.Background(), nodes))
+	assert.Equal(t, nodes[1:], plugin.laneNodeFilter(dropFirst)(context.Background(), nodes), "applied to the nodes of next")
+}
+
+// testServerTransport is a Kratos server transport with request headers.
+type testServerTransport struct {
+	testHTTPTransport
+}
+
+func (t *testServerTransport) Kind() transport.Kind { return transport.KindGRPC }
+
+func TestLanePropagation(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.Empty(t, LaneFromContext(context.Background()))
+	assert.Equal(t, "canary", LaneFromContext(WithLane(context.Background(), "canary")))
+
+	incoming := httptest.NewRequest("GET", "/orders", nil)
+	incoming.Header.Set("X-Lane", "canary")
+	serverCtx := transport.NewServerContext(context.Background(), &testServerTransport{testHTTPTransport{request: incoming}})
+
+	outgoing := httptest.NewRequest("GET", "/payments", nil)
+	client := &testClientTransport{testHTTPTransport: testHTTPTransport{request: outgoing}, operation: "/payments.v1.Payments/Charge"}
+	_, err := plugin.LaneServerMiddleware()(func(ctx context.Context, req any) (any, error) {
+		assert.Equal(t, "canary", LaneFromContext(ctx))
+		return plugin.LaneClientMiddleware()(func(context.Context, any) (any, error) {
+			return nil, nil
+		})(transport.NewClientContext(ctx, client), req)
+	})(serverCtx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "canary", outgoing.Header.Get("x-lane"))
+
+	plugin.setConf(&conf.Polaris{Lane: &conf.Lane{Header: "x-gray"}})
+	ctx := plugin.ExtractLane(serverCtx)
+	assert.Empty(t, LaneFromContext(ctx), "the lane is read from the configured header")
+	assert.Equal(t, "blue", LaneFromContext(plugin.ExtractLane(WithLane(context.Background(), "blue"))), "contexts without a request keep their lane")
+}
@@ -11,7 +11,8 @@ import (
 // load-balancer integrations.  Extend the stub helpers below to plug in real
 // implementations (Kratos balancer, Nginx upstream, Istio, etc.).
 func (p *PlugPolaris) triggerLoadBalancerUpdate(serviceName string, instances []model.Instance) {
-	if p.conf == nil {
+	cfg := p.currentConf()
+	if cfg == nil {
 		return
 	}
 
@@ -29,7 +30,7 @@ func (p *PlugPolaris) triggerLoadBalancerUpdate(serviceName string, instances []
 	}
 
 	log.Infof("Load balancer update: service=%s namespace=%s healthy=%d/%d totalWeight=%d updatedAt=%d",
-		serviceName, p.conf.Namespace, healthyInstances, len(instances), totalWeight, time.Now().Unix())
+		serviceName, cfg.Namespace, healthyInstances, len(instances), totalWeight, time.Now().Unix())
 
 	p.updateKratosLoadBalancer(serviceName)
 	p.updateLocalLoadBalancerCache(serviceName)
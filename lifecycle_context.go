@@ -3,6 +3,7 @@ package polaris
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	kratospolaris "github.com/go-kratos/kratos/contrib/polaris/v2"
@@ -97,7 +98,14 @@ func (p *PlugPolaris) StopContext(ctx context.Context, plugin plugins.Plugin) er
 	return nil
 }
 
-func (p *PlugPolaris) startupTasksContext(ctx context.Context) (startErr error) {
+func (p *PlugPolaris) startupTasksContext(ctx context.Context) error {
+	return p.startup(ctx, false)
+}
+
+// startup connects to Polaris, sets the plugin as the Lynx control plane and starts its
+// background tasks. Dependent plugins are loaded from the control plane config, except on
+// restart, where they are already loaded.
+func (p *PlugPolaris) startup(ctx context.Context, restart bool) (startErr error) {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
@@ -131,26 +139,19 @@ func (p *PlugPolaris) startupTasksContext(ctx context.Context) (startErr error)
 		}()
 	}
 
-	log.Infof("Initializing polaris plugin with namespace: %s", p.conf.Namespace)
+	log.Infof("Initializing polaris plugin with namespace: %s", p.currentConf().Namespace)
 
 	if err := ctx.Err(); err != nil {
 		return fmt.Errorf("polaris startup canceled before sdk init: %w", err)
 	}
-	sdk, err := p.loadPolarisConfiguration()
-	if err != nil {
-		log.Errorf("Failed to initialize Polaris SDK: %v", err)
-		return WrapInitError(err, "failed to initialize Polaris SDK")
+	if p.currentConf().GetLazyInit() {
+		log.Infof("Lazy initialization enabled, connecting to Polaris on first use")
+	} else if err := p.connect(); err != nil {
+		return err
 	}
 
-	pol := kratospolaris.New(
-		sdk,
-		kratospolaris.WithService(currentLynxName()),
-		kratospolaris.WithNamespace(p.conf.Namespace),
-	)
-
 	p.mu.Lock()
-	p.sdk = sdk
-	p.polaris = &pol
+	p.startTime = time.Now()
 	p.setInitialized()
 	p.mu.Unlock()
 
@@ -160,6 +161,18 @@ func (p *PlugPolaris) startupTasksContext(ctx context.Context) (startErr error)
 		}
 	}()
 
+	if p.SubsystemEnabled(SubsystemConfig) {
+		if err := p.loadRemoteConfig(); err != nil {
+			log.Errorf("Failed to load remote config: %v", p.redactError(err))
+			return err
+		}
+
+		if err := p.loadRequiredConfigs(ctx, p.getConfigContent); err != nil {
+			log.Errorf("Failed to load required config files: %v", err)
+			return err
+		}
+	}
+
 	if err := ctx.Err(); err != nil {
 		return fmt.Errorf("polaris startup canceled before setting control plane: %w", err)
 	}
@@ -184,7 +197,25 @@ func (p *PlugPolaris) startupTasksContext(ctx context.Context) (startErr error)
 		log.Errorf("Failed to publish Polaris runtime resources: %v", err)
 		return WrapInitError(err, "failed to publish runtime resources")
 	}
+	if p.SubsystemEnabled(SubsystemRegistration) {
+		p.startWarmUp()
+		p.startAutoWeight()
+		p.startRegistrationWatchdog()
+		p.startHeartbeat()
+	}
+	p.startHealthCheckLoop()
+	p.startServerRefresh()
+	p.startFaultRuleRefresh()
+	if !p.currentConf().GetLazyInit() {
+		p.startRateLimitPrefetch()
+		p.prefetchHotServices(ctx)
+	}
+	p.startTokenRefresh()
 
+	if restart {
+		log.Infof("Polaris plugin restarted successfully")
+		return nil
+	}
 	if err := ctx.Err(); err != nil {
 		return fmt.Errorf("polaris startup canceled before loading dependent plugins: %w", err)
 	}
@@ -197,6 +228,60 @@ func (p *PlugPolaris) startupTasksContext(ctx context.Context) (startErr error)
 	return nil
 }
 
+// connect loads the Polaris token and creates the SDK context and the Kratos Polaris client.
+func (p *PlugPolaris) connect() error {
+	if err := p.initTokenSource(); err != nil {
+		log.Errorf("Failed to load Polaris token: %v", err)
+		return WrapInitError(err, "failed to load Polaris token")
+	}
+	sdk, err := p.loadPolarisConfiguration()
+	if err != nil {
+		log.Errorf("Failed to initialize Polaris SDK: %v", err)
+		return WrapInitError(err, "failed to initialize Polaris SDK")
+	}
+
+	pol := kratospolaris.New(
+		sdk,
+		kratospolaris.WithService(currentLynxName()),
+		kratospolaris.WithNamespace(p.currentConf().Namespace),
+	)
+
+	p.mu.Lock()
+	p.setSDKLocked(sdk)
+	p.polaris = &pol
+	p.mu.Unlock()
+	return nil
+}
+
+// ensureConnected creates the SDK context on first use when lazy_init is set.
+func (p *PlugPolaris) ensureConnected() error {
+	p.mu.RLock()
+	connected := p.sdk != nil
+	lazy := p.currentConf().GetLazyInit()
+	p.mu.RUnlock()
+	if connected || !lazy {
+		return nil
+	}
+	p.connectMutex.Lock()
+	defer p.connectMutex.Unlock()
+	p.mu.RLock()
+	connected = p.sdk != nil
+	p.mu.RUnlock()
+	if connected {
+		return nil
+	}
+	log.Infof("Connecting to Polaris on first use")
+	return p.connect()
+}
+
+// lazilyUnconnected reports whether lazy_init has deferred the SDK context and nothing
+// has used Polaris yet.
+func (p *PlugPolaris) lazilyUnconnected() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.currentConf().GetLazyInit() && p.sdk == nil
+}
+
 func (p *PlugPolaris) ensureLifecycleContextLocked() {
 	if p.healthCheckCh == nil {
 		p.healthCheckCh = make(chan struct{})
@@ -234,18 +319,20 @@ func (p *PlugPolaris) lifecycleDone() <-chan struct{} {
 	return nil
 }
 
-func (p *PlugPolaris) waitForRetryDelay(delay time.Duration) bool {
-	done := p.lifecycleDone()
-	if done == nil {
-		time.Sleep(delay)
-		return p.IsDestroyed()
-	}
+// waitForRetryDelay waits for delay and reports whether the retry should be abandoned
+// because ctx was canceled or the plugin is shutting down.
+func (p *PlugPolaris) waitForRetryDelay(ctx context.Context, delay time.Duration) bool {
+	timer := p.clock.NewTimer(delay)
+	defer timer.Stop()
 
+	// A nil lifecycle channel blocks forever, leaving ctx and the timer in charge.
 	select {
-	case <-done:
+	case <-ctx.Done():
+		return true
+	case <-p.lifecycleDone():
 		return true
-	case <-time.After(delay):
-		return false
+	case <-timer.C():
+		return p.IsDestroyed()
 	}
 }
 
@@ -262,4 +349,6 @@ func (p *PlugPolaris) rollbackStartupState() {
 	p.lifecycleStop = nil
 	p.clearInitialized()
 	p.mu.Unlock()
+	p.waitCacheRefreshes()
+	atomic.StoreInt32(&p.requiredConfigsLoaded, 0)
 }
@@ -1,6 +1,8 @@
 package polaris
 
 import (
+	"time"
+
 	"github.com/go-kratos/kratos/contrib/polaris/v2"
 	"github.com/go-kratos/kratos/v2/middleware"
 	"github.com/go-lynx/lynx/log"
@@ -14,11 +16,16 @@ import (
 // HTTPRateLimit creates HTTP rate limit middleware.
 // It fetches HTTP rate limit policies from Polaris and applies them to the HTTP request flow.
 func (p *PlugPolaris) HTTPRateLimit() middleware.Middleware {
+	cfg := p.currentConf()
 	if err := p.checkInitialized(); err != nil {
 		log.Warnf("Polaris plugin not initialized, returning nil HTTP rate limit middleware: %v", err)
 		return nil
 	}
-	if p.polaris == nil || p.conf == nil {
+	if !p.SubsystemEnabled(SubsystemRateLimit) {
+		log.Infof("Polaris rate limit subsystem disabled, returning nil HTTP rate limit middleware")
+		return nil
+	}
+	if p.polaris == nil || cfg == nil {
 		log.Warnf("Polaris instance or config is nil, returning nil HTTP rate limit middleware")
 		return nil
 	}
@@ -27,18 +34,23 @@ func (p *PlugPolaris) HTTPRateLimit() middleware.Middleware {
 
 	return polaris.Ratelimit(p.polaris.Limiter(
 		polaris.WithLimiterService(currentLynxName()),
-		polaris.WithLimiterNamespace(p.conf.Namespace),
+		polaris.WithLimiterNamespace(cfg.Namespace),
 	))
 }
 
 // GRPCRateLimit creates gRPC rate limit middleware.
 // It fetches gRPC rate limit policies from Polaris and applies them to the gRPC request flow.
 func (p *PlugPolaris) GRPCRateLimit() middleware.Middleware {
+	cfg := p.currentConf()
 	if err := p.checkInitialized(); err != nil {
 		log.Warnf("Polaris plugin not initialized, returning nil gRPC rate limit middleware: %v", err)
 		return nil
 	}
-	if p.polaris == nil || p.conf == nil {
+	if !p.SubsystemEnabled(SubsystemRateLimit) {
+		log.Infof("Polaris rate limit subsystem disabled, returning nil gRPC rate limit middleware")
+		return nil
+	}
+	if p.polaris == nil || cfg == nil {
 		log.Warnf("Polaris instance or config is nil, returning nil gRPC rate limit middleware")
 		return nil
 	}
@@ -47,62 +59,111 @@ func (p *PlugPolaris) GRPCRateLimit() middleware.Middleware {
 
 	return polaris.Ratelimit(p.polaris.Limiter(
 		polaris.WithLimiterService(currentLynxName()),
-		polaris.WithLimiterNamespace(p.conf.Namespace),
+		polaris.WithLimiterNamespace(cfg.Namespace),
 	))
 }
 
 // CheckRateLimit checks rate limiting for a service with optional labels.
 func (p *PlugPolaris) CheckRateLimit(serviceName string, labels map[string]string) (bool, error) {
-	if err := p.checkInitialized(); err != nil {
+	result, err := p.getQuota(serviceName, "", labels)
+	if err != nil {
 		return false, err
 	}
 
+	// Check whether the request is allowed
+	if result.Code == model.QuotaResultOk {
+		log.Infof("Rate limit check passed for service %s", serviceName)
+		return true, nil
+	} else {
+		log.Warnf("Rate limit exceeded for service %s", serviceName)
+		return false, nil
+	}
+}
+
+// getQuota acquires a quota for a call to method of serviceName with labels and any extra
+// arguments, waiting out any queueing delay of the rule. An empty method matches the rate
+// limit rules that do not restrict the method.
+func (p *PlugPolaris) getQuota(serviceName, method string, labels map[string]string, args ...model.Argument) (*model.QuotaResponse, error) {
+	if err := p.checkSubsystem(SubsystemRateLimit); err != nil {
+		return nil, err
+	}
+	future, err := p.requestQuota(serviceName, method, labels, args...)
+	if err != nil {
+		return p.rateLimitFallback(serviceName, labels, err)
+	}
+
+	// Obtain rate limit result
+	result := future.Get()
+	if result == nil {
+		log.Errorf("Rate limit result is nil for service %s", serviceName)
+		return nil, NewServiceError(ErrCodeRateLimitFailed, "rate limit result is nil")
+	}
+	return result, nil
+}
+
+// requestQuota requests a quota for a call to method of serviceName with labels and any
+// extra arguments, and returns the pending allocation.
+func (p *PlugPolaris) requestQuota(serviceName, method string, labels map[string]string, args ...model.Argument) (api.QuotaFuture, error) {
+	log.Infof("Checking rate limit for service: %s", serviceName)
+
+	// Build quota request
+	quotaReq := api.NewQuotaRequest()
+	quotaReq.SetService(serviceName)
+	if method != "" {
+		quotaReq.SetMethod(method)
+	}
+
+	// Set labels, normalized to bound their cardinality
+	for key, value := range p.normalizeLabels(labels) {
+		quotaReq.AddArgument(model.BuildQueryArgument(key, value))
+	}
+	for _, arg := range args {
+		quotaReq.AddArgument(arg)
+	}
+	return p.sendQuotaRequest(serviceName, quotaReq, true)
+}
+
+// sendQuotaRequest sends quotaReq through the circuit breaker and retries, and returns the
+// pending allocation. The plugin namespace is set on quotaReq when setNamespace is true;
+// otherwise quotaReq already has it and is only read. It does not allocate, apart from the
+// SDK call, so it serves the allocation-free checks of CheckRateLimitLabels.
+func (p *PlugPolaris) sendQuotaRequest(serviceName string, quotaReq api.QuotaRequest, setNamespace bool) (api.QuotaFuture, error) {
+	if err := p.checkInitialized(); err != nil {
+		return nil, err
+	}
+
 	// Snapshot mutable plugin state under the lock to avoid a data race / nil
 	// dereference if cleanup runs concurrently with this request.
 	p.mu.RLock()
-	sdk := p.sdk
+	limitAPI := p.limitLocked()
 	namespace := ""
-	if p.conf != nil {
-		namespace = p.conf.Namespace
+	if cfg := p.currentConf(); cfg != nil {
+		namespace = cfg.Namespace
 	}
 	metrics := p.metrics
 	circuitBreaker := p.circuitBreaker
 	retryManager := p.retryManager
 	p.mu.RUnlock()
 
-	if sdk == nil || circuitBreaker == nil || retryManager == nil {
-		return false, NewInitError("Polaris plugin has been destroyed")
+	if limitAPI == nil || circuitBreaker == nil || retryManager == nil {
+		return nil, newDestroyedError()
+	}
+	if setNamespace {
+		quotaReq.SetNamespace(namespace)
 	}
 
 	// Record metrics for the rate limit check operation
 	if metrics != nil {
-		metrics.RecordSDKOperation("check_rate_limit", "start")
+		metrics.recordSDKOperationValues(checkRateLimitStartLabels)
 		defer func() {
 			if metrics != nil {
-				metrics.RecordSDKOperation("check_rate_limit", "success")
+				metrics.recordSDKOperationValues(checkRateLimitSuccessLabels)
 			}
 		}()
 	}
 
-	log.Infof("Checking rate limit for service: %s", serviceName)
-
-	// Create Limit API client
-	limitAPI := api.NewLimitAPIByContext(sdk)
-	if limitAPI == nil {
-		return false, NewInitError("failed to create limit API")
-	}
-
-	// Build quota request
-	quotaReq := api.NewQuotaRequest()
-	quotaReq.SetService(serviceName)
-	quotaReq.SetNamespace(namespace)
-
-	// Set labels
-	for key, value := range labels {
-		quotaReq.AddArgument(model.BuildQueryArgument(key, value))
-	}
-
 	// Execute with circuit breaker and retry mechanism
+	start := time.Now()
 	var future api.QuotaFuture
 	var lastErr error
 
@@ -119,27 +180,65 @@ func (p *PlugPolaris) CheckRateLimit(serviceName string, labels map[string]strin
 		})
 	})
 
+	if metrics != nil {
+		metrics.observeRateLimitCheckDuration(p.quotaMetricLabels.get(serviceName, namespace), time.Since(start).Seconds())
+	}
 	if err != nil {
 		log.Errorf("Failed to check rate limit for service %s after retries: %v", serviceName, err)
 		if metrics != nil {
 			metrics.RecordSDKOperation("check_rate_limit", "error")
+			metrics.RecordOperationError("check_rate_limit", err)
 		}
-		return false, WrapServiceError(lastErr, ErrCodeRateLimitFailed, "failed to check rate limit")
+		return nil, WrapServiceError(TranslateSDKError(lastErr), ErrCodeRateLimitFailed, "failed to check rate limit")
+	}
+	p.rateLimitRecovered(serviceName)
+	return future, nil
+}
+
+// quotaGate acquires quotas for the rate limit middleware of a service and counts the
+// results in the rate limit metrics.
+type quotaGate struct {
+	plugin   *PlugPolaris
+	service  string
+	getQuota func(service, method string, labels map[string]string) (*model.QuotaResponse, error)
+}
+
+// newQuotaGate returns a gate for service, or for the running application when empty.
+func (p *PlugPolaris) newQuotaGate(service string) *quotaGate {
+	if service == "" {
+		service = currentLynxName()
 	}
+	return &quotaGate{plugin: p, service: service, getQuota: func(service, method string, labels map[string]string) (*model.QuotaResponse, error) {
+		return p.getQuota(service, method, labels)
+	}}
+}
 
-	// Obtain rate limit result
-	result := future.Get()
-	if result == nil {
-		log.Errorf("Rate limit result is nil for service %s", serviceName)
-		return false, NewServiceError(ErrCodeRateLimitFailed, "rate limit result is nil")
+// acquire requests a quota for method with labels and returns the plugin namespace along
+// with the result. The namespace and metrics are read at call time, so middleware created
+// before the plugin starts still reports them. Every call is allowed when the rate limit
+// subsystem is disabled.
+func (g *quotaGate) acquire(method string, labels map[string]string) (*model.QuotaResponse, string, error) {
+	g.plugin.mu.RLock()
+	namespace := g.plugin.currentConf().GetNamespace()
+	metrics := g.plugin.metrics
+	g.plugin.mu.RUnlock()
+	if !g.plugin.SubsystemEnabled(SubsystemRateLimit) {
+		return &model.QuotaResponse{Code: model.QuotaResultOk}, namespace, nil
 	}
 
-	// Check whether the request is allowed
-	if result.Code == model.QuotaResultOk {
-		log.Infof("Rate limit check passed for service %s", serviceName)
-		return true, nil
-	} else {
-		log.Warnf("Rate limit exceeded for service %s", serviceName)
-		return false, nil
+	result, err := g.getQuota(g.service, method, labels)
+	status := "allowed"
+	switch {
+	case err != nil:
+		status = "error"
+	case result.Code != model.QuotaResultOk:
+		status = "rejected"
+	}
+	if metrics != nil {
+		metrics.RecordRateLimitRequest(g.service, namespace, status)
+		if status == "rejected" {
+			metrics.RecordRateLimitRejection(g.service, namespace)
+		}
 	}
+	return result, namespace, err
 }
@@ -0,0 +1,170 @@
+package polaris
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	namingpb "github.com/polarismesh/polaris-go/pkg/model/pb/v1"
+)
+
+const (
+	// localRateSyncInterval is how often the local rate of a service is refreshed from its
+	// Polaris rules while rate limit checks succeed.
+	localRateSyncInterval = 30 * time.Second
+
+	// maxLocalBuckets caps the local token buckets; all are dropped when it is reached.
+	maxLocalBuckets = 10000
+)
+
+// localRate is the rate of the local token buckets of a service.
+type localRate struct {
+	qps   float64
+	burst int
+}
+
+// localLimiter decides rate limit checks in process while the Polaris limit API fails.
+// It keeps a token bucket per service and label set, refilled at the strictest rate of
+// the Polaris rules of the service, or at local_qps when the rules are unknown.
+type localLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	rates   map[string]localRate
+	synced  map[string]time.Time
+	active  bool
+}
+
+// newLocalLimiter returns an idle limiter.
+func newLocalLimiter() *localLimiter {
+	return &localLimiter{
+		buckets: make(map[string]*tokenBucket),
+		rates:   make(map[string]localRate),
+		synced:  make(map[string]time.Time),
+	}
+}
+
+// localBucketKey returns the bucket key of serviceName and labels.
+func localBucketKey(serviceName string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(serviceName)
+	for _, key := range keys {
+		b.WriteString("|")
+		b.WriteString(key)
+		b.WriteString("=")
+		b.WriteString(labels[key])
+	}
+	return b.String()
+}
+
+// allow takes a token from the bucket of serviceName and labels at now.
+func (l *localLimiter) allow(serviceName string, labels map[string]string, cfg *conf.RateLimitFallback, now time.Time) bool {
+	key := localBucketKey(serviceName, labels)
+	l.mu.Lock()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		rate, synced := l.rates[serviceName]
+		if !synced {
+			rate = localRate{qps: cfg.GetLocalQps(), burst: int(cfg.GetLocalBurst())}
+		}
+		if len(l.buckets) >= maxLocalBuckets {
+			l.buckets = make(map[string]*tokenBucket)
+		}
+		bucket = newTokenBucket(rate.qps, rate.burst, now)
+		l.buckets[key] = bucket
+	}
+	l.mu.Unlock()
+	return bucket.take(now)
+}
+
+// enter marks the limiter active and reports whether it was idle.
+func (l *localLimiter) enter() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entered := !l.active
+	l.active = true
+	return entered
+}
+
+// recover marks the limiter idle and drops its buckets, reporting whether it was active.
+func (l *localLimiter) recover() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.active {
+		return false
+	}
+	l.active = false
+	l.buckets = make(map[string]*tokenBucket)
+	return true
+}
+
+// shouldSync reports whether the rate of serviceName is due for a refresh at now, and if
+// so records the refresh.
+func (l *localLimiter) shouldSync(serviceName string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if last, ok := l.synced[serviceName]; ok && now.Sub(last) < localRateSyncInterval {
+		return false
+	}
+	l.synced[serviceName] = now
+	return true
+}
+
+// setRate sets the rate of serviceName, or forgets it when ok is false.
+func (l *localLimiter) setRate(serviceName string, rate localRate, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if ok {
+		l.rates[serviceName] = rate
+	} else {
+		delete(l.rates, serviceName)
+	}
+}
+
+// localRateFromRules returns the strictest rate among the enabled amounts of the QPS rules
+// in rules, with the amount of that window as burst.
+func localRateFromRules(rules *namingpb.RateLimit) (localRate, bool) {
+	var best localRate
+	found := false
+	for _, rule := range rules.GetRules() {
+		if rule.GetDisable().GetValue() || rule.GetResource() != namingpb.Rule_QPS {
+			continue
+		}
+		for _, amount := range rule.GetAmounts() {
+			maxAmount := amount.GetMaxAmount().GetValue()
+			window := time.Duration(amount.GetValidDuration().GetSeconds())*time.Second +
+				time.Duration(amount.GetValidDuration().GetNanos())
+			if window <= 0 {
+				continue
+			}
+			qps := float64(maxAmount) / window.Seconds()
+			if !found || qps < best.qps {
+				best = localRate{qps: qps, burst: int(math.Max(1, float64(maxAmount)))}
+				found = true
+			}
+		}
+	}
+	return best, found
+}
+
+// syncLocalRate refreshes the local rate of serviceName from its Polaris rate limit rules.
+func (p *PlugPolaris) syncLocalRate(serviceName string) {
+	rules, err := p.fetchRateLimitRules(serviceName)
+	if err != nil {
+		log.Debugf("Failed to sync rate limit rules of service %s for the local limiter: %v", serviceName, err)
+		return
+	}
+	rate, ok := localRateFromRules(rules)
+	p.localLimiter.setRate(serviceName, rate, ok)
+	if ok {
+		log.Debugf("Local fallback rate of service %s synced from Polaris rules: %.2f qps, burst %d", serviceName, rate.qps, rate.burst)
+	}
+}
@@ -0,0 +1,99 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	namingpb "github.com/polarismesh/polaris-go/pkg/model/pb/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestLocalBucketKey(t *testing.T) {
+	assert.Equal(t, "orders|method=Create|tier=gold",
+		localBucketKey("orders", map[string]string{"tier": "gold", "method": "Create"}))
+	assert.Equal(t, "orders", localBucketKey("orders", nil))
+}
+
+func TestLocalRateFromRules(t *testing.T) {
+	amount := func(max uint32, window time.Duration) *namingpb.Amount {
+		return &namingpb.Amount{MaxAmount: wrapperspb.UInt32(max), ValidDuration: durationpb.New(window)}
+	}
+	rules := &namingpb.RateLimit{Rules: []*namingpb.Rule{
+		{Amounts: []*namingpb.Amount{amount(100, time.Second), amount(600, time.Minute)}},
+		{Amounts: []*namingpb.Amount{amount(1, time.Second)}, Disable: wrapperspb.Bool(true)},
+		{Amounts: []*namingpb.Amount{amount(50, 0)}},
+	}}
+	rate, ok := localRateFromRules(rules)
+	require.True(t, ok)
+	assert.Equal(t, localRate{qps: 10, burst: 600}, rate)
+
+	_, ok = localRateFromRules(nil)
+	assert.False(t, ok)
+}
+
+func TestLocalLimiter_BucketsPerLabelSet(t *testing.T) {
+	limiter := newLocalLimiter()
+	cfg := &conf.RateLimitFallback{Mode: conf.RateLimitFallbackLocal, LocalQps: 0.001, LocalBurst: 1}
+	now := time.Now()
+
+	gold := map[string]string{"tier": "gold"}
+	assert.True(t, limiter.allow("orders", gold, cfg, now))
+	assert.False(t, limiter.allow("orders", gold, cfg, now))
+	assert.True(t, limiter.allow("orders", map[string]string{"tier": "silver"}, cfg, now))
+
+	limiter.setRate("payments", localRate{qps: 1, burst: 2}, true)
+	assert.True(t, limiter.allow("payments", nil, cfg, now))
+	assert.True(t, limiter.allow("payments", nil, cfg, now))
+	assert.False(t, limiter.allow("payments", nil, cfg, now))
+
+	assert.False(t, limiter.recover())
+	assert.True(t, limiter.enter())
+	assert.False(t, limiter.enter())
+	assert.True(t, limiter.recover())
+	assert.True(t, limiter.allow("orders", gold, cfg, now), "recovery drops the buckets")
+
+	assert.True(t, limiter.shouldSync("orders", now))
+	assert.False(t, limiter.shouldSync("orders", now.Add(time.Second)))
+	assert.True(t, limiter.shouldSync("orders", now.Add(localRateSyncInterval)))
+}
+
+func TestRateLimitFallback_PublishesDegradationOnce(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", RateLimitFallback: &conf.RateLimitFallback{Mode: conf.RateLimitFallbackLocal, LocalQps: 100}})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := plugin.Subscribe(ctx, EventTypeDegradation)
+	require.NoError(t, err)
+
+	checkErr := WrapServiceError(errors.New("connection refused"), ErrCodeRateLimitFailed, "failed to check rate limit")
+	for range 3 {
+		resp, err := plugin.rateLimitFallback("orders", map[string]string{"method": "Create"}, checkErr)
+		require.NoError(t, err)
+		assert.Equal(t, model.QuotaResultOk, resp.Code)
+	}
+
+	select {
+	case ev := <-events:
+		degradation := ev.(*DegradationEvent)
+		assert.Equal(t, "rate_limit_failure", degradation.DegradationType)
+		assert.Equal(t, "orders", degradation.Service)
+		assert.Equal(t, conf.RateLimitFallbackLocal, degradation.FallbackStrategy)
+	case <-time.After(time.Second):
+		t.Fatal("expected degradation event")
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected second event %v", ev)
+	default:
+	}
+
+	plugin.rateLimitRecovered("orders")
+	assert.True(t, plugin.localLimiter.enter(), "recovery releases the local limiter")
+}
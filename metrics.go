@@ -1,459 +1,713 @@
 package polaris
 
 import (
+	"context"
 	"errors"
-	"fmt"
-	"sync"
+	"time"
 
+	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-var metricsRegistrationMu sync.Mutex
-
 // Metrics defines Polaris-related monitoring metrics
 type Metrics struct {
+	sink MetricsSink
+
 	// SDK operation metrics
-	sdkOperationsTotal    *prometheus.CounterVec
-	sdkOperationsDuration *prometheus.HistogramVec
-	sdkErrorsTotal        *prometheus.CounterVec
+	sdkOperationsTotal    MetricCounter
+	sdkOperationsDuration MetricHistogram
+	sdkErrorsTotal        MetricCounter
 
 	// Service discovery metrics
-	serviceDiscoveryTotal    *prometheus.CounterVec
-	serviceDiscoveryDuration *prometheus.HistogramVec
-	serviceInstancesTotal    *prometheus.GaugeVec
+	serviceDiscoveryTotal    MetricCounter
+	serviceDiscoveryDuration MetricHistogram
+	serviceInstancesTotal    MetricGauge
 
 	// Service registration metrics
-	serviceRegistrationTotal    *prometheus.CounterVec
-	serviceRegistrationDuration *prometheus.HistogramVec
-	serviceHeartbeatTotal       *prometheus.CounterVec
+	serviceRegistrationTotal    MetricCounter
+	serviceRegistrationDuration MetricHistogram
+	serviceHeartbeatTotal       MetricCounter
 
 	// Configuration management metrics
-	configOperationsTotal    *prometheus.CounterVec
-	configOperationsDuration *prometheus.HistogramVec
-	configChangesTotal       *prometheus.CounterVec
+	configOperationsTotal    MetricCounter
+	configOperationsDuration MetricHistogram
+	configChangesTotal       MetricCounter
+	configLastFetch          MetricGauge
+	configContentAge         MetricGauge
+	configStale              MetricGauge
+	configReloadsTotal       MetricCounter
+	configValidationsTotal   MetricCounter
 
 	// Routing metrics
-	routeOperationsTotal    *prometheus.CounterVec
-	routeOperationsDuration *prometheus.HistogramVec
+	routeOperationsTotal    MetricCounter
+	routeOperationsDuration MetricHistogram
 
 	// Rate limiting metrics
-	rateLimitRequestsTotal *prometheus.CounterVec
-	rateLimitRejectedTotal *prometheus.CounterVec
-	rateLimitQuotaUsed     *prometheus.GaugeVec
+	rateLimitRequestsTotal MetricCounter
+	rateLimitCheckDuration MetricHistogram
+	rateLimitRejectedTotal MetricCounter
+	rateLimitQuotaUsed     MetricGauge
+	rateLimitLabelsTotal   MetricCounter
+
+	// Concurrency limiting metrics
+	concurrencyRequestsTotal MetricCounter
+	concurrencyInFlight      MetricGauge
+
+	// Traffic mirroring metrics
+	mirrorRequestsTotal MetricCounter
+
+	// Instance and config cache metrics
+	cacheRequestsTotal  MetricCounter
+	cacheEvictionsTotal MetricCounter
+	cacheRefreshesTotal MetricCounter
+	cacheEntries        MetricGauge
+
+	// Retry and circuit breaker metrics
+	retriesTotal                   MetricCounter
+	retryFailuresTotal             MetricCounter
+	retryAttempts                  MetricHistogram
+	circuitBreakerTransitionsTotal MetricCounter
+	circuitBreakerRejectedTotal    MetricCounter
+	circuitBreakerState            MetricGauge
+
+	// Watcher metrics
+	watcherLastEvent       MetricGauge
+	watcherRestartsTotal   MetricCounter
+	watcherRetriesTotal    MetricCounter
+	watcherCallbackSeconds MetricHistogram
+	watcherSkippedTotal    MetricCounter
+	watcherDroppedTotal    MetricCounter
+	watcherTimeoutsTotal   MetricCounter
 
 	// Health check metrics
-	healthCheckTotal    *prometheus.CounterVec
-	healthCheckDuration *prometheus.HistogramVec
-	healthCheckFailed   *prometheus.CounterVec
+	healthCheckTotal    MetricCounter
+	healthCheckDuration MetricHistogram
+	healthCheckFailed   MetricCounter
 
 	// Connection metrics
-	connectionTotal       *prometheus.GaugeVec
-	connectionErrorsTotal *prometheus.CounterVec
+	connectionTotal       MetricGauge
+	connectionErrorsTotal MetricCounter
+	serverActive          MetricGauge
+	serverFailoversTotal  MetricCounter
 }
 
-// NewPolarisMetrics creates new monitoring metrics instance
+// NewPolarisMetrics creates new monitoring metrics instance registered with the default
+// Prometheus registry
 func NewPolarisMetrics() *Metrics {
-	metricsRegistrationMu.Lock()
-	defer metricsRegistrationMu.Unlock()
+	return NewMetrics(NewPrometheusSink(prometheus.DefaultRegisterer))
+}
 
+// NewMetrics creates new monitoring metrics instance recorded to sink
+func NewMetrics(sink MetricsSink) *Metrics {
 	return &Metrics{
+		sink: sink,
+
 		// SDK operation metrics
-		sdkOperationsTotal: registerCounterVec(
-			prometheus.CounterOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "sdk_operations_total",
-				Help:      "Total number of SDK operations",
-			},
-			[]string{"operation", "status"},
-		),
-		sdkOperationsDuration: registerHistogramVec(
-			prometheus.HistogramOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "sdk_operations_duration_seconds",
-				Help:      "Duration of SDK operations",
-				Buckets:   prometheus.DefBuckets,
-			},
-			[]string{"operation"},
-		),
-		sdkErrorsTotal: registerCounterVec(
-			prometheus.CounterOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "sdk_errors_total",
-				Help:      "Total number of SDK errors",
-			},
-			[]string{"operation", "error_type"},
-		),
+		sdkOperationsTotal: sink.Counter(MetricDesc{
+			Name:       "sdk_operations_total",
+			Help:       "Total number of SDK operations",
+			LabelNames: []string{"operation", "status"},
+		}),
+		sdkOperationsDuration: sink.Histogram(MetricDesc{
+			Name:       "sdk_operations_duration_seconds",
+			Help:       "Duration of SDK operations",
+			LabelNames: []string{"operation"},
+			Buckets:    prometheus.DefBuckets,
+		}),
+		sdkErrorsTotal: sink.Counter(MetricDesc{
+			Name:       "sdk_errors_total",
+			Help:       "Total number of SDK errors by Polaris SDK error code",
+			LabelNames: []string{"operation", "error_code"},
+		}),
 
 		// Service discovery metrics
-		serviceDiscoveryTotal: registerCounterVec(
-			prometheus.CounterOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "service_discovery_total",
-				Help:      "Total number of service discovery operations",
-			},
-			[]string{"service", "namespace", "status"},
-		),
-		serviceDiscoveryDuration: registerHistogramVec(
-			prometheus.HistogramOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "service_discovery_duration_seconds",
-				Help:      "Duration of service discovery operations",
-				Buckets:   prometheus.DefBuckets,
-			},
-			[]string{"service", "namespace"},
-		),
-		serviceInstancesTotal: registerGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "service_instances_total",
-				Help:      "Total number of service instances",
-			},
-			[]string{"service", "namespace", "status"},
-		),
+		serviceDiscoveryTotal: sink.Counter(MetricDesc{
+			Name:       "service_discovery_total",
+			Help:       "Total number of service discovery operations",
+			LabelNames: []string{"service", "namespace", "status"},
+		}),
+		serviceDiscoveryDuration: sink.Histogram(MetricDesc{
+			Name:       "service_discovery_duration_seconds",
+			Help:       "Duration of service discovery operations",
+			LabelNames: []string{"service", "namespace"},
+			Buckets:    prometheus.DefBuckets,
+		}),
+		serviceInstancesTotal: sink.Gauge(MetricDesc{
+			Name:       "service_instances_total",
+			Help:       "Total number of service instances",
+			LabelNames: []string{"service", "namespace", "status"},
+		}),
 
 		// Service registration metrics
-		serviceRegistrationTotal: registerCounterVec(
-			prometheus.CounterOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "service_registration_total",
-				Help:      "Total number of service registration operations",
-			},
-			[]string{"service", "namespace", "status"},
-		),
-		serviceRegistrationDuration: registerHistogramVec(
-			prometheus.HistogramOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "service_registration_duration_seconds",
-				Help:      "Duration of service registration operations",
-				Buckets:   prometheus.DefBuckets,
-			},
-			[]string{"service", "namespace"},
-		),
-		serviceHeartbeatTotal: registerCounterVec(
-			prometheus.CounterOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "service_heartbeat_total",
-				Help:      "Total number of service heartbeat operations",
-			},
-			[]string{"service", "namespace", "status"},
-		),
+		serviceRegistrationTotal: sink.Counter(MetricDesc{
+			Name:       "service_registration_total",
+			Help:       "Total number of service registration operations",
+			LabelNames: []string{"service", "namespace", "status"},
+		}),
+		serviceRegistrationDuration: sink.Histogram(MetricDesc{
+			Name:       "service_registration_duration_seconds",
+			Help:       "Duration of service registration operations",
+			LabelNames: []string{"service", "namespace"},
+			Buckets:    prometheus.DefBuckets,
+		}),
+		serviceHeartbeatTotal: sink.Counter(MetricDesc{
+			Name:       "service_heartbeat_total",
+			Help:       "Total number of service heartbeat operations",
+			LabelNames: []string{"service", "namespace", "status"},
+		}),
 
 		// Configuration management metrics
-		configOperationsTotal: registerCounterVec(
-			prometheus.CounterOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "config_operations_total",
-				Help:      "Total number of config operations",
-			},
-			[]string{"operation", "file", "group", "status"},
-		),
-		configOperationsDuration: registerHistogramVec(
-			prometheus.HistogramOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "config_operations_duration_seconds",
-				Help:      "Duration of config operations",
-				Buckets:   prometheus.DefBuckets,
-			},
-			[]string{"operation", "file", "group"},
-		),
-		configChangesTotal: registerCounterVec(
-			prometheus.CounterOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "config_changes_total",
-				Help:      "Total number of config changes",
-			},
-			[]string{"file", "group"},
-		),
+		configOperationsTotal: sink.Counter(MetricDesc{
+			Name:       "config_operations_total",
+			Help:       "Total number of config operations",
+			LabelNames: []string{"operation", "file", "group", "status"},
+		}),
+		configOperationsDuration: sink.Histogram(MetricDesc{
+			Name:       "config_operations_duration_seconds",
+			Help:       "Duration of config operations",
+			LabelNames: []string{"operation", "file", "group"},
+			Buckets:    prometheus.DefBuckets,
+		}),
+		configChangesTotal: sink.Counter(MetricDesc{
+			Name:       "config_changes_total",
+			Help:       "Total number of config changes",
+			LabelNames: []string{"file", "group"},
+		}),
+		configLastFetch: sink.Gauge(MetricDesc{
+			Name:       "config_last_fetch_timestamp_seconds",
+			Help:       "Unix time of the last successful fetch of a config file",
+			LabelNames: []string{"file", "group"},
+		}),
+		configContentAge: sink.Gauge(MetricDesc{
+			Name:       "config_content_age_seconds",
+			Help:       "Time since the content of a config file last changed",
+			LabelNames: []string{"file", "group"},
+		}),
+		configStale: sink.Gauge(MetricDesc{
+			Name:       "config_stale",
+			Help:       "Whether a config file exceeds its staleness threshold (1) or not (0)",
+			LabelNames: []string{"file", "group"},
+		}),
+		configReloadsTotal: sink.Counter(MetricDesc{
+			Name:       "config_reloads_total",
+			Help:       "Total number of config reload handler runs by result",
+			LabelNames: []string{"file", "group", "result"},
+		}),
+		configValidationsTotal: sink.Counter(MetricDesc{
+			Name:       "config_validations_total",
+			Help:       "Total number of config content validations by result",
+			LabelNames: []string{"file", "group", "result"},
+		}),
 
 		// Routing metrics
-		routeOperationsTotal: registerCounterVec(
-			prometheus.CounterOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "route_operations_total",
-				Help:      "Total number of route operations",
-			},
-			[]string{"service", "namespace", "status"},
-		),
-		routeOperationsDuration: registerHistogramVec(
-			prometheus.HistogramOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "route_operations_duration_seconds",
-				Help:      "Duration of route operations",
-				Buckets:   prometheus.DefBuckets,
-			},
-			[]string{"service", "namespace"},
-		),
+		routeOperationsTotal: sink.Counter(MetricDesc{
+			Name:       "route_operations_total",
+			Help:       "Total number of route operations",
+			LabelNames: []string{"service", "namespace", "status"},
+		}),
+		routeOperationsDuration: sink.Histogram(MetricDesc{
+			Name:       "route_operations_duration_seconds",
+			Help:       "Duration of route operations",
+			LabelNames: []string{"service", "namespace"},
+			Buckets:    prometheus.DefBuckets,
+		}),
 
 		// Rate limiting metrics
-		rateLimitRequestsTotal: registerCounterVec(
-			prometheus.CounterOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "rate_limit_requests_total",
-				Help:      "Total number of rate limit requests",
-			},
-			[]string{"service", "namespace", "status"},
-		),
-		rateLimitRejectedTotal: registerCounterVec(
-			prometheus.CounterOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "rate_limit_rejected_total",
-				Help:      "Total number of rate limit rejections",
-			},
-			[]string{"service", "namespace"},
-		),
-		rateLimitQuotaUsed: registerGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "rate_limit_quota_used",
-				Help:      "Rate limit quota usage",
-			},
-			[]string{"service", "namespace"},
-		),
+		rateLimitRequestsTotal: sink.Counter(MetricDesc{
+			Name:       "rate_limit_requests_total",
+			Help:       "Total number of rate limit requests",
+			LabelNames: []string{"service", "namespace", "status"},
+		}),
+		rateLimitCheckDuration: sink.Histogram(MetricDesc{
+			Name:       "rate_limit_check_duration_seconds",
+			Help:       "Duration of rate limit quota checks",
+			LabelNames: []string{"service", "namespace"},
+			Buckets:    prometheus.DefBuckets,
+		}),
+		rateLimitRejectedTotal: sink.Counter(MetricDesc{
+			Name:       "rate_limit_rejected_total",
+			Help:       "Total number of rate limit rejections",
+			LabelNames: []string{"service", "namespace"},
+		}),
+		rateLimitQuotaUsed: sink.Gauge(MetricDesc{
+			Name:       "rate_limit_quota_used",
+			Help:       "Rate limit quota usage",
+			LabelNames: []string{"service", "namespace"},
+		}),
+		rateLimitLabelsTotal: sink.Counter(MetricDesc{
+			Name:       "rate_limit_labels_normalized_total",
+			Help:       "Total number of rate limit labels dropped, hashed or truncated by normalization",
+			LabelNames: []string{"action"},
+		}),
+
+		// Concurrency limiting metrics
+		concurrencyRequestsTotal: sink.Counter(MetricDesc{
+			Name:       "concurrency_limit_requests_total",
+			Help:       "Total number of concurrency slot requests",
+			LabelNames: []string{"service", "result"},
+		}),
+		concurrencyInFlight: sink.Gauge(MetricDesc{
+			Name:       "concurrency_in_flight",
+			Help:       "Number of calls holding a concurrency slot",
+			LabelNames: []string{"service"},
+		}),
+
+		// Traffic mirroring metrics
+		mirrorRequestsTotal: sink.Counter(MetricDesc{
+			Name:       "mirror_requests_total",
+			Help:       "Total number of calls mirrored to shadow services",
+			LabelNames: []string{"service", "result"},
+		}),
+
+		// Instance and config cache metrics
+		cacheRequestsTotal: sink.Counter(MetricDesc{
+			Name:       "cache_requests_total",
+			Help:       "Total number of cache lookups by result",
+			LabelNames: []string{"cache", "result"},
+		}),
+		cacheEvictionsTotal: sink.Counter(MetricDesc{
+			Name:       "cache_evictions_total",
+			Help:       "Total number of cache entries evicted by reason",
+			LabelNames: []string{"cache", "reason"},
+		}),
+		cacheRefreshesTotal: sink.Counter(MetricDesc{
+			Name:       "cache_refreshes_total",
+			Help:       "Total number of background refreshes of stale cache entries",
+			LabelNames: []string{"cache", "result"},
+		}),
+		cacheEntries: sink.Gauge(MetricDesc{
+			Name:       "cache_entries",
+			Help:       "Number of entries in the cache",
+			LabelNames: []string{"cache"},
+		}),
+
+		// Retry and circuit breaker metrics
+		retriesTotal: sink.Counter(MetricDesc{
+			Name:       "retries_total",
+			Help:       "Total number of retries of failed operations",
+			LabelNames: []string{"name"},
+		}),
+		retryFailuresTotal: sink.Counter(MetricDesc{
+			Name:       "retry_failures_total",
+			Help:       "Total number of operations that failed after their last attempt",
+			LabelNames: []string{"name"},
+		}),
+		retryAttempts: sink.Histogram(MetricDesc{
+			Name:       "retry_attempts",
+			Help:       "Number of attempts made per operation",
+			LabelNames: []string{"name"},
+			Buckets:    []float64{1, 2, 3, 4, 5, 6, 8, 11},
+		}),
+		circuitBreakerTransitionsTotal: sink.Counter(MetricDesc{
+			Name:       "circuit_breaker_transitions_total",
+			Help:       "Total number of circuit breaker state transitions",
+			LabelNames: []string{"name", "from", "to"},
+		}),
+		circuitBreakerRejectedTotal: sink.Counter(MetricDesc{
+			Name:       "circuit_breaker_rejected_total",
+			Help:       "Total number of calls rejected by a circuit breaker",
+			LabelNames: []string{"name", "state"},
+		}),
+		circuitBreakerState: sink.Gauge(MetricDesc{
+			Name:       "circuit_breaker_state",
+			Help:       "Circuit breaker state (0=closed, 1=open, 2=half-open)",
+			LabelNames: []string{"name"},
+		}),
+
+		// Watcher metrics
+		watcherLastEvent: sink.Gauge(MetricDesc{
+			Name:       "watcher_last_event_timestamp_seconds",
+			Help:       "Unix time of the last answer from Polaris received by a watcher",
+			LabelNames: []string{"type", "name"},
+		}),
+		watcherRestartsTotal: sink.Counter(MetricDesc{
+			Name:       "watcher_restarts_total",
+			Help:       "Total number of watchers re-established by the watch retry loop",
+			LabelNames: []string{"type", "name"},
+		}),
+		watcherRetriesTotal: sink.Counter(MetricDesc{
+			Name:       "watcher_retries_total",
+			Help:       "Total number of watch retry loop activations after watch errors",
+			LabelNames: []string{"type", "name"},
+		}),
+		watcherCallbackSeconds: sink.Histogram(MetricDesc{
+			Name:       "watcher_callback_duration_seconds",
+			Help:       "Duration of watcher change callbacks",
+			LabelNames: []string{"type", "name"},
+			Buckets:    prometheus.DefBuckets,
+		}),
+		watcherSkippedTotal: sink.Counter(MetricDesc{
+			Name:       "watcher_polls_skipped_total",
+			Help:       "Total number of watcher polls skipped because the previous poll was still running",
+			LabelNames: []string{"type", "name"},
+		}),
+		watcherDroppedTotal: sink.Counter(MetricDesc{
+			Name:       "watcher_polls_dropped_total",
+			Help:       "Total number of watcher polls dropped because the watch queue was full",
+			LabelNames: []string{"type", "name"},
+		}),
+		watcherTimeoutsTotal: sink.Counter(MetricDesc{
+			Name:       "watcher_poll_timeouts_total",
+			Help:       "Total number of watcher polls that held their worker past the poll interval",
+			LabelNames: []string{"type", "name"},
+		}),
 
 		// Health check metrics
-		healthCheckTotal: registerCounterVec(
-			prometheus.CounterOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "health_check_total",
-				Help:      "Total number of health checks",
-			},
-			[]string{"component", "status"},
-		),
-		healthCheckDuration: registerHistogramVec(
-			prometheus.HistogramOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "health_check_duration_seconds",
-				Help:      "Duration of health checks",
-				Buckets:   prometheus.DefBuckets,
-			},
-			[]string{"component"},
-		),
-		healthCheckFailed: registerCounterVec(
-			prometheus.CounterOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "health_check_failed_total",
-				Help:      "Total number of failed health checks",
-			},
-			[]string{"component", "error_type"},
-		),
+		healthCheckTotal: sink.Counter(MetricDesc{
+			Name:       "health_check_total",
+			Help:       "Total number of health checks",
+			LabelNames: []string{"component", "status"},
+		}),
+		healthCheckDuration: sink.Histogram(MetricDesc{
+			Name:       "health_check_duration_seconds",
+			Help:       "Duration of health checks",
+			LabelNames: []string{"component"},
+			Buckets:    prometheus.DefBuckets,
+		}),
+		healthCheckFailed: sink.Counter(MetricDesc{
+			Name:       "health_check_failed_total",
+			Help:       "Total number of failed health checks",
+			LabelNames: []string{"component", "error_type"},
+		}),
 
 		// Connection metrics
-		connectionTotal: registerGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "connection_total",
-				Help:      "Total number of connections",
-			},
-			[]string{"type", "status"},
-		),
-		connectionErrorsTotal: registerCounterVec(
-			prometheus.CounterOpts{
-				Namespace: "lynx",
-				Subsystem: "polaris",
-				Name:      "connection_errors_total",
-				Help:      "Total number of connection errors",
-			},
-			[]string{"type", "error_type"},
-		),
+		connectionTotal: sink.Gauge(MetricDesc{
+			Name:       "connection_total",
+			Help:       "Total number of connections",
+			LabelNames: []string{"type", "status"},
+		}),
+		connectionErrorsTotal: sink.Counter(MetricDesc{
+			Name:       "connection_errors_total",
+			Help:       "Total number of connection errors",
+			LabelNames: []string{"type", "error_type"},
+		}),
+		serverActive: sink.Gauge(MetricDesc{
+			Name:       "server_active",
+			Help:       "Polaris server of the latest connection of a cluster (1=active, 0=inactive)",
+			LabelNames: []string{"cluster", "address"},
+		}),
+		serverFailoversTotal: sink.Counter(MetricDesc{
+			Name:       "server_failovers_total",
+			Help:       "Total number of connections made to another Polaris server after a failed one",
+			LabelNames: []string{"cluster"},
+		}),
 	}
 }
 
-func registerCounterVec(opts prometheus.CounterOpts, labelNames []string) *prometheus.CounterVec {
-	collector := prometheus.NewCounterVec(opts, labelNames)
-	if err := prometheus.DefaultRegisterer.Register(collector); err != nil {
-		var alreadyRegistered prometheus.AlreadyRegisteredError
-		if errors.As(err, &alreadyRegistered) {
-			existing, ok := alreadyRegistered.ExistingCollector.(*prometheus.CounterVec)
-			if !ok {
-				panic(fmt.Sprintf("unexpected counter collector type for %s_%s_%s", opts.Namespace, opts.Subsystem, opts.Name))
-			}
-			return existing
-		}
-		panic(fmt.Sprintf("failed to register counter collector %s_%s_%s: %v", opts.Namespace, opts.Subsystem, opts.Name, err))
-	}
-	return collector
-}
-
-func registerHistogramVec(opts prometheus.HistogramOpts, labelNames []string) *prometheus.HistogramVec {
-	collector := prometheus.NewHistogramVec(opts, labelNames)
-	if err := prometheus.DefaultRegisterer.Register(collector); err != nil {
-		var alreadyRegistered prometheus.AlreadyRegisteredError
-		if errors.As(err, &alreadyRegistered) {
-			existing, ok := alreadyRegistered.ExistingCollector.(*prometheus.HistogramVec)
-			if !ok {
-				panic(fmt.Sprintf("unexpected histogram collector type for %s_%s_%s", opts.Namespace, opts.Subsystem, opts.Name))
-			}
-			return existing
-		}
-		panic(fmt.Sprintf("failed to register histogram collector %s_%s_%s: %v", opts.Namespace, opts.Subsystem, opts.Name, err))
-	}
-	return collector
-}
-
-func registerGaugeVec(opts prometheus.GaugeOpts, labelNames []string) *prometheus.GaugeVec {
-	collector := prometheus.NewGaugeVec(opts, labelNames)
-	if err := prometheus.DefaultRegisterer.Register(collector); err != nil {
-		var alreadyRegistered prometheus.AlreadyRegisteredError
-		if errors.As(err, &alreadyRegistered) {
-			existing, ok := alreadyRegistered.ExistingCollector.(*prometheus.GaugeVec)
-			if !ok {
-				panic(fmt.Sprintf("unexpected gauge collector type for %s_%s_%s", opts.Namespace, opts.Subsystem, opts.Name))
-			}
-			return existing
-		}
-		panic(fmt.Sprintf("failed to register gauge collector %s_%s_%s: %v", opts.Namespace, opts.Subsystem, opts.Name, err))
-	}
-	return collector
-}
-
-// collectors returns all Prometheus collectors for unregister on plugin unload
-func (m *Metrics) collectors() []prometheus.Collector {
-	return []prometheus.Collector{
-		m.sdkOperationsTotal, m.sdkOperationsDuration, m.sdkErrorsTotal,
-		m.serviceDiscoveryTotal, m.serviceDiscoveryDuration, m.serviceInstancesTotal,
-		m.serviceRegistrationTotal, m.serviceRegistrationDuration, m.serviceHeartbeatTotal,
-		m.configOperationsTotal, m.configOperationsDuration, m.configChangesTotal,
-		m.routeOperationsTotal, m.routeOperationsDuration,
-		m.rateLimitRequestsTotal, m.rateLimitRejectedTotal, m.rateLimitQuotaUsed,
-		m.healthCheckTotal, m.healthCheckDuration, m.healthCheckFailed,
-		m.connectionTotal, m.connectionErrorsTotal,
-	}
-}
-
-// Unregister unregisters all metrics from the default Prometheus registry (call on plugin cleanup)
+// Unregister removes the metrics from the backend of their sink (call on plugin cleanup)
 func (m *Metrics) Unregister() {
-	for _, c := range m.collectors() {
-		_ = prometheus.DefaultRegisterer.Unregister(c)
-	}
+	m.sink.Unregister()
 }
 
 // RecordSDKOperation records SDK operation
 func (m *Metrics) RecordSDKOperation(operation, status string) {
-	m.sdkOperationsTotal.WithLabelValues(operation, status).Inc()
+	m.sdkOperationsTotal.Add(1, operation, status)
+}
+
+// recordSDKOperationValues is RecordSDKOperation with prebuilt label values, operation then
+// status, which are not allocated per call
+func (m *Metrics) recordSDKOperationValues(labelValues []string) {
+	m.sdkOperationsTotal.Add(1, labelValues...)
 }
 
 // RecordSDKOperationDuration records SDK operation duration
 func (m *Metrics) RecordSDKOperationDuration(operation string, duration float64) {
-	m.sdkOperationsDuration.WithLabelValues(operation).Observe(duration)
+	m.sdkOperationsDuration.Observe(duration, operation)
 }
 
 // RecordSDKError records SDK error
-func (m *Metrics) RecordSDKError(operation, errorType string) {
-	m.sdkErrorsTotal.WithLabelValues(operation, errorType).Inc()
+func (m *Metrics) RecordSDKError(operation, errorCode string) {
+	m.sdkErrorsTotal.Add(1, operation, errorCode)
+}
+
+// RecordOperationError records err of operation, labeled with its error code
+func (m *Metrics) RecordOperationError(operation string, err error) {
+	m.RecordSDKError(operation, errorCodeLabel(err))
+}
+
+// errorCodeLabel returns the error code label of err: the polaris-go SDK error code when err
+// wraps an SDK error, the plugin error code for plugin errors, and a generic value otherwise
+func errorCodeLabel(err error) string {
+	var sdkErr model.SDKError
+	if errors.As(err, &sdkErr) {
+		return model.ErrCodeToString(sdkErr.ErrorCode())
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	}
+	var polarisErr *PolarisError
+	if errors.As(err, &polarisErr) {
+		return string(polarisErr.Code)
+	}
+	return "unknown"
 }
 
 // RecordServiceDiscovery records service discovery operation
 func (m *Metrics) RecordServiceDiscovery(service, namespace, status string) {
-	m.serviceDiscoveryTotal.WithLabelValues(service, namespace, status).Inc()
+	m.serviceDiscoveryTotal.Add(1, service, namespace, status)
 }
 
 // RecordServiceDiscoveryDuration records service discovery duration
 func (m *Metrics) RecordServiceDiscoveryDuration(service, namespace string, duration float64) {
-	m.serviceDiscoveryDuration.WithLabelValues(service, namespace).Observe(duration)
+	m.serviceDiscoveryDuration.Observe(duration, service, namespace)
 }
 
 // SetServiceInstances sets service instance count
 func (m *Metrics) SetServiceInstances(service, namespace, status string, count float64) {
-	m.serviceInstancesTotal.WithLabelValues(service, namespace, status).Set(count)
+	m.serviceInstancesTotal.Set(count, service, namespace, status)
 }
 
 // RecordServiceRegistration records service registration operation
 func (m *Metrics) RecordServiceRegistration(service, namespace, status string) {
-	m.serviceRegistrationTotal.WithLabelValues(service, namespace, status).Inc()
+	m.serviceRegistrationTotal.Add(1, service, namespace, status)
 }
 
 // RecordServiceRegistrationDuration records service registration duration
 func (m *Metrics) RecordServiceRegistrationDuration(service, namespace string, duration float64) {
-	m.serviceRegistrationDuration.WithLabelValues(service, namespace).Observe(duration)
+	m.serviceRegistrationDuration.Observe(duration, service, namespace)
 }
 
 // RecordServiceHeartbeat records service heartbeat
 func (m *Metrics) RecordServiceHeartbeat(service, namespace, status string) {
-	m.serviceHeartbeatTotal.WithLabelValues(service, namespace, status).Inc()
+	m.serviceHeartbeatTotal.Add(1, service, namespace, status)
 }
 
 // RecordConfigOperation records configuration operation
 func (m *Metrics) RecordConfigOperation(operation, file, group, status string) {
-	m.configOperationsTotal.WithLabelValues(operation, file, group, status).Inc()
+	m.configOperationsTotal.Add(1, operation, file, group, status)
 }
 
 // RecordConfigOperationDuration records configuration operation duration
 func (m *Metrics) RecordConfigOperationDuration(operation, file, group string, duration float64) {
-	m.configOperationsDuration.WithLabelValues(operation, file, group).Observe(duration)
+	m.configOperationsDuration.Observe(duration, operation, file, group)
 }
 
 // RecordConfigChange records configuration change
 func (m *Metrics) RecordConfigChange(file, group string) {
-	m.configChangesTotal.WithLabelValues(file, group).Inc()
+	m.configChangesTotal.Add(1, file, group)
+}
+
+// SetConfigFreshness records the last fetch time and content age of a config file
+func (m *Metrics) SetConfigFreshness(file, group string, lastFetch, age float64, stale bool) {
+	m.configLastFetch.Set(lastFetch, file, group)
+	m.configContentAge.Set(age, file, group)
+	staleValue := 0.0
+	if stale {
+		staleValue = 1
+	}
+	m.configStale.Set(staleValue, file, group)
+}
+
+// RecordConfigReload records a config reload handler run with result (success or error)
+func (m *Metrics) RecordConfigReload(file, group, result string) {
+	m.configReloadsTotal.Add(1, file, group, result)
+}
+
+// RecordConfigValidation records a config content validation with result (passed or rejected)
+func (m *Metrics) RecordConfigValidation(file, group, result string) {
+	m.configValidationsTotal.Add(1, file, group, result)
 }
 
 // RecordRouteOperation records route operation
 func (m *Metrics) RecordRouteOperation(service, namespace, status string) {
-	m.routeOperationsTotal.WithLabelValues(service, namespace, status).Inc()
+	m.routeOperationsTotal.Add(1, service, namespace, status)
 }
 
 // RecordRouteOperationDuration records route operation duration
 func (m *Metrics) RecordRouteOperationDuration(service, namespace string, duration float64) {
-	m.routeOperationsDuration.WithLabelValues(service, namespace).Observe(duration)
+	m.routeOperationsDuration.Observe(duration, service, namespace)
 }
 
 // RecordRateLimitRequest records rate limit request
 func (m *Metrics) RecordRateLimitRequest(service, namespace, status string) {
-	m.rateLimitRequestsTotal.WithLabelValues(service, namespace, status).Inc()
+	m.rateLimitRequestsTotal.Add(1, service, namespace, status)
+}
+
+// RecordRateLimitCheckDuration records the duration of a rate limit quota check
+func (m *Metrics) RecordRateLimitCheckDuration(service, namespace string, duration float64) {
+	m.rateLimitCheckDuration.Observe(duration, service, namespace)
+}
+
+// observeRateLimitCheckDuration is RecordRateLimitCheckDuration with prebuilt label values,
+// service then namespace, which are not allocated per call
+func (m *Metrics) observeRateLimitCheckDuration(labelValues []string, duration float64) {
+	m.rateLimitCheckDuration.Observe(duration, labelValues...)
 }
 
 // RecordRateLimitRejection records rate limit rejection
 func (m *Metrics) RecordRateLimitRejection(service, namespace string) {
-	m.rateLimitRejectedTotal.WithLabelValues(service, namespace).Inc()
+	m.rateLimitRejectedTotal.Add(1, service, namespace)
 }
 
 // SetRateLimitQuota sets rate limit quota usage
 func (m *Metrics) SetRateLimitQuota(service, namespace string, quota float64) {
-	m.rateLimitQuotaUsed.WithLabelValues(service, namespace).Set(quota)
+	m.rateLimitQuotaUsed.Set(quota, service, namespace)
+}
+
+// RecordRateLimitLabels records rate limit labels normalized with action (dropped, hashed or truncated)
+func (m *Metrics) RecordRateLimitLabels(action string, count int) {
+	if count > 0 {
+		m.rateLimitLabelsTotal.Add(float64(count), action)
+	}
+}
+
+// RecordConcurrencyRequest records a concurrency slot request with result (acquired or rejected)
+func (m *Metrics) RecordConcurrencyRequest(service, result string) {
+	m.concurrencyRequestsTotal.Add(1, service, result)
+}
+
+// AddConcurrencyInFlight adjusts the number of calls holding a concurrency slot
+func (m *Metrics) AddConcurrencyInFlight(service string, delta float64) {
+	m.concurrencyInFlight.Add(delta, service)
+}
+
+// RecordMirrorRequest records a call mirrored to the shadow service with result (success,
+// error or dropped)
+func (m *Metrics) RecordMirrorRequest(service, result string) {
+	m.mirrorRequestsTotal.Add(1, service, result)
+}
+
+// RecordCacheRequest records a lookup of cache with result (hit, stale or miss)
+func (m *Metrics) RecordCacheRequest(cache, result string) {
+	m.cacheRequestsTotal.Add(1, cache, result)
+}
+
+// RecordCacheEviction records an entry of cache evicted for reason (capacity or expired)
+func (m *Metrics) RecordCacheEviction(cache, reason string) {
+	m.cacheEvictionsTotal.Add(1, cache, reason)
+}
+
+// RecordCacheRefresh records a background refresh of a stale entry of cache with result
+// (success or error)
+func (m *Metrics) RecordCacheRefresh(cache, result string) {
+	m.cacheRefreshesTotal.Add(1, cache, result)
+}
+
+// SetCacheEntries sets the number of entries of cache
+func (m *Metrics) SetCacheEntries(cache string, count int) {
+	m.cacheEntries.Set(float64(count), cache)
+}
+
+// RecordRetry records a retry of a failed operation of the retry manager name
+func (m *Metrics) RecordRetry(name string) {
+	m.retriesTotal.Add(1, name)
+}
+
+// RecordRetryCompletion records the number of attempts of a completed operation and whether it failed
+func (m *Metrics) RecordRetryCompletion(name string, attempts int, failed bool) {
+	m.retryAttempts.Observe(float64(attempts), name)
+	if failed {
+		m.retryFailuresTotal.Add(1, name)
+	}
+}
+
+// RecordCircuitBreakerTransition records a state transition of the circuit breaker name
+func (m *Metrics) RecordCircuitBreakerTransition(name string, from, to CircuitState) {
+	m.circuitBreakerTransitionsTotal.Add(1, name, from.String(), to.String())
+	m.circuitBreakerState.Set(float64(to), name)
+}
+
+// RecordCircuitBreakerRejection records a call rejected by the circuit breaker name in state
+func (m *Metrics) RecordCircuitBreakerRejection(name string, state CircuitState) {
+	m.circuitBreakerRejectedTotal.Add(1, name, state.String())
+}
+
+// InstrumentRetryManager records the retries and completions of r under name
+func (m *Metrics) InstrumentRetryManager(name string, r *RetryManager) {
+	// The label values are built once, as every call through r completes
+	labelValues := []string{name}
+	r.OnRetry(func(int, error) { m.RecordRetry(name) })
+	r.OnComplete(func(attempts int, err error) {
+		m.retryAttempts.Observe(float64(attempts), labelValues...)
+		if err != nil {
+			m.retryFailuresTotal.Add(1, labelValues...)
+		}
+	})
+}
+
+// InstrumentCircuitBreaker records the state transitions and rejections of cb under name
+func (m *Metrics) InstrumentCircuitBreaker(name string, cb *CircuitBreaker) {
+	m.circuitBreakerState.Set(float64(cb.GetState()), name)
+	cb.OnStateChange(func(from, to CircuitState) { m.RecordCircuitBreakerTransition(name, from, to) })
+	cb.OnReject(func(state CircuitState) { m.RecordCircuitBreakerRejection(name, state) })
+}
+
+// RecordWatcherEvent records that the watcher name of watcherType (service or config)
+// received an answer from Polaris at
+func (m *Metrics) RecordWatcherEvent(watcherType, name string, at time.Time) {
+	m.watcherLastEvent.Set(float64(at.Unix()), watcherType, name)
+}
+
+// RecordWatcherRestart records that the watch retry loop re-established a watcher
+func (m *Metrics) RecordWatcherRestart(watcherType, name string) {
+	m.watcherRestartsTotal.Add(1, watcherType, name)
+}
+
+// RecordWatcherPollSkipped records a poll of a watcher skipped because its previous poll,
+// including its callbacks, was still running
+func (m *Metrics) RecordWatcherPollSkipped(watcherType, name string) {
+	m.watcherSkippedTotal.Add(1, watcherType, name)
+}
+
+// RecordWatcherPollDropped records a poll of a watcher dropped because the watch queue was
+// full
+func (m *Metrics) RecordWatcherPollDropped(watcherType, name string) {
+	m.watcherDroppedTotal.Add(1, watcherType, name)
+}
+
+// RecordWatcherPollTimeout records a poll of a watcher, including its callbacks, that held
+// its worker past the poll interval, so that a replacement worker was started
+func (m *Metrics) RecordWatcherPollTimeout(watcherType, name string) {
+	m.watcherTimeoutsTotal.Add(1, watcherType, name)
+}
+
+// RecordWatcherRetry records an activation of the watch retry loop of a watcher
+func (m *Metrics) RecordWatcherRetry(watcherType, name string) {
+	m.watcherRetriesTotal.Add(1, watcherType, name)
+}
+
+// RecordWatcherCallbackDuration records the duration of a watcher change callback
+func (m *Metrics) RecordWatcherCallbackDuration(watcherType, name string, duration float64) {
+	m.watcherCallbackSeconds.Observe(duration, watcherType, name)
 }
 
 // RecordHealthCheck records health check
 func (m *Metrics) RecordHealthCheck(component, status string) {
-	m.healthCheckTotal.WithLabelValues(component, status).Inc()
+	m.healthCheckTotal.Add(1, component, status)
 }
 
 // RecordHealthCheckDuration records health check duration
 func (m *Metrics) RecordHealthCheckDuration(component string, duration float64) {
-	m.healthCheckDuration.WithLabelValues(component).Observe(duration)
+	m.healthCheckDuration.Observe(duration, component)
 }
 
 // RecordHealthCheckFailed records health check failure
 func (m *Metrics) RecordHealthCheckFailed(component, errorType string) {
-	m.healthCheckFailed.WithLabelValues(component, errorType).Inc()
+	m.healthCheckFailed.Add(1, component, errorType)
 }
 
 // SetConnectionCount sets connection count
 func (m *Metrics) SetConnectionCount(connType, status string, count float64) {
-	m.connectionTotal.WithLabelValues(connType, status).Set(count)
+	m.connectionTotal.Set(count, connType, status)
 }
 
 // RecordConnectionError records connection error
 func (m *Metrics) RecordConnectionError(connType, errorType string) {
-	m.connectionErrorsTotal.WithLabelValues(connType, errorType).Inc()
+	m.connectionErrorsTotal.Add(1, connType, errorType)
+}
+
+// RecordServerSwitch marks address as the active server of cluster instead of previous
+func (m *Metrics) RecordServerSwitch(cluster, previous, address string) {
+	if previous != "" {
+		m.serverActive.Set(0, cluster, previous)
+	}
+	m.serverActive.Set(1, cluster, address)
+}
+
+// RecordServerFailover records a connection to another server of cluster after a failed one
+func (m *Metrics) RecordServerFailover(cluster string) {
+	m.serverFailoversTotal.Add(1, cluster)
 }
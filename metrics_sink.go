@@ -0,0 +1,367 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	lynxmetrics "github.com/go-lynx/lynx/observability/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// MetricDesc describes a metric of the plugin. Name is given without the backend's
+// "lynx_polaris_" or "lynx.polaris." prefix.
+type MetricDesc struct {
+	Name       string
+	Help       string
+	LabelNames []string
+	// Buckets are the histogram bucket boundaries; nil selects the backend's defaults
+	Buckets []float64
+}
+
+// MetricCounter is a monotonically increasing metric
+type MetricCounter interface {
+	// Add increases the counter of labelValues, given in the order of the label names, by value
+	Add(value float64, labelValues ...string)
+}
+
+// MetricGauge is a metric that can go up and down
+type MetricGauge interface {
+	// Set sets the gauge of labelValues, given in the order of the label names, to value
+	Set(value float64, labelValues ...string)
+	// Add adjusts the gauge of labelValues by delta
+	Add(delta float64, labelValues ...string)
+}
+
+// MetricHistogram is a metric that samples observations into buckets
+type MetricHistogram interface {
+	// Observe records value for labelValues, given in the order of the label names
+	Observe(value float64, labelValues ...string)
+}
+
+// MetricsSink is the backend the plugin's metrics are recorded to. Implementations have to
+// be safe for concurrent use.
+type MetricsSink interface {
+	Counter(desc MetricDesc) MetricCounter
+	Gauge(desc MetricDesc) MetricGauge
+	Histogram(desc MetricDesc) MetricHistogram
+	// Unregister removes the metrics created by the sink from its backend
+	Unregister()
+}
+
+// metricsRegistrationMu serializes the registration of Prometheus collectors, so that
+// concurrently created sinks on the same registerer share their collectors
+var metricsRegistrationMu sync.Mutex
+
+// prometheusSink records metrics as Prometheus collectors named lynx_polaris_<name>
+type prometheusSink struct {
+	registerer prometheus.Registerer
+
+	mu         sync.Mutex
+	collectors []prometheus.Collector
+}
+
+// NewPrometheusSink returns a sink registering the plugin's metrics with registerer, e.g. an
+// application's own *prometheus.Registry. A nil registerer selects prometheus.DefaultRegisterer.
+func NewPrometheusSink(registerer prometheus.Registerer) MetricsSink {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	return &prometheusSink{registerer: registerer}
+}
+
+// Counter returns a CounterVec registered with the sink's registerer
+func (s *prometheusSink) Counter(desc MetricDesc) MetricCounter {
+	return prometheusCounter{registerCollector(s, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "lynx",
+		Subsystem: "polaris",
+		Name:      desc.Name,
+		Help:      desc.Help,
+	}, desc.LabelNames), desc.Name)}
+}
+
+// Gauge returns a GaugeVec registered with the sink's registerer
+func (s *prometheusSink) Gauge(desc MetricDesc) MetricGauge {
+	return prometheusGauge{registerCollector(s, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "lynx",
+		Subsystem: "polaris",
+		Name:      desc.Name,
+		Help:      desc.Help,
+	}, desc.LabelNames), desc.Name)}
+}
+
+// Histogram returns a HistogramVec registered with the sink's registerer
+func (s *prometheusSink) Histogram(desc MetricDesc) MetricHistogram {
+	buckets := desc.Buckets
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+	return prometheusHistogram{registerCollector(s, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "lynx",
+		Subsystem: "polaris",
+		Name:      desc.Name,
+		Help:      desc.Help,
+		Buckets:   buckets,
+	}, desc.LabelNames), desc.Name)}
+}
+
+// Unregister unregisters every collector of the sink from its registerer
+func (s *prometheusSink) Unregister() {
+	s.mu.Lock()
+	collectors := s.collectors
+	s.collectors = nil
+	s.mu.Unlock()
+	for _, c := range collectors {
+		_ = s.registerer.Unregister(c)
+	}
+}
+
+// registerCollector registers collector with the registerer of s. When an identical
+// collector is already registered, e.g. by a previous plugin instance, it is reused.
+func registerCollector[C prometheus.Collector](s *prometheusSink, collector C, name string) C {
+	metricsRegistrationMu.Lock()
+	defer metricsRegistrationMu.Unlock()
+
+	if err := s.registerer.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			panic(fmt.Sprintf("failed to register collector lynx_polaris_%s: %v", name, err))
+		}
+		existing, ok := alreadyRegistered.ExistingCollector.(C)
+		if !ok {
+			panic(fmt.Sprintf("unexpected collector type for lynx_polaris_%s", name))
+		}
+		collector = existing
+	}
+	s.mu.Lock()
+	s.collectors = append(s.collectors, collector)
+	s.mu.Unlock()
+	return collector
+}
+
+type prometheusCounter struct{ vec *prometheus.CounterVec }
+
+func (c prometheusCounter) Add(value float64, labelValues ...string) {
+	c.vec.WithLabelValues(labelValues...).Add(value)
+}
+
+type prometheusGauge struct{ vec *prometheus.GaugeVec }
+
+func (g prometheusGauge) Set(value float64, labelValues ...string) {
+	g.vec.WithLabelValues(labelValues...).Set(value)
+}
+
+func (g prometheusGauge) Add(delta float64, labelValues ...string) {
+	g.vec.WithLabelValues(labelValues...).Add(delta)
+}
+
+type prometheusHistogram struct{ vec *prometheus.HistogramVec }
+
+func (h prometheusHistogram) Observe(value float64, labelValues ...string) {
+	h.vec.WithLabelValues(labelValues...).Observe(value)
+}
+
+// lynxRegistry is the private registry of the Lynx sinks, exposed once through the Lynx
+// metrics handler
+var lynxRegistry = sync.OnceValue(func() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	lynxmetrics.RegisterGatherer(registry)
+	return registry
+})
+
+// NewLynxSink returns a sink exposing the plugin's metrics through the Lynx monitoring
+// handler (observability/metrics.Handler) instead of the default Prometheus registry
+func NewLynxSink() MetricsSink {
+	return NewPrometheusSink(lynxRegistry())
+}
+
+// otelMeterName is the instrumentation scope of the meter used by the otel metrics backend
+const otelMeterName = "github.com/go-lynx/lynx-polaris"
+
+// otelSink records metrics as OpenTelemetry instruments named lynx.polaris.<name>
+type otelSink struct {
+	meter metric.Meter
+
+	mu            sync.Mutex
+	registrations []metric.Registration
+}
+
+// NewOTelSink returns a sink recording the plugin's metrics with meter, so that they are
+// exported by the meter provider's readers, e.g. OTLP. Label names become attribute keys.
+func NewOTelSink(meter metric.Meter) MetricsSink {
+	return &otelSink{meter: meter}
+}
+
+// Counter returns a Float64Counter of the sink's meter
+func (s *otelSink) Counter(desc MetricDesc) MetricCounter {
+	counter, err := s.meter.Float64Counter(otelMetricName(desc), metric.WithDescription(desc.Help))
+	if err != nil {
+		log.Warnf("Failed to create OpenTelemetry counter %s: %v", otelMetricName(desc), err)
+		counter = noop.Float64Counter{}
+	}
+	return otelCounter{counter: counter, labelNames: desc.LabelNames}
+}
+
+// Gauge returns a gauge whose values are reported by an observable gauge of the sink's meter
+func (s *otelSink) Gauge(desc MetricDesc) MetricGauge {
+	gauge := &otelGauge{labelNames: desc.LabelNames, points: make(map[string]*otelGaugePoint)}
+	observable, err := s.meter.Float64ObservableGauge(otelMetricName(desc), metric.WithDescription(desc.Help))
+	if err != nil {
+		log.Warnf("Failed to create OpenTelemetry gauge %s: %v", otelMetricName(desc), err)
+		return gauge
+	}
+	registration, err := s.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		gauge.observe(o, observable)
+		return nil
+	}, observable)
+	if err != nil {
+		log.Warnf("Failed to register OpenTelemetry gauge %s: %v", otelMetricName(desc), err)
+		return gauge
+	}
+	s.mu.Lock()
+	s.registrations = append(s.registrations, registration)
+	s.mu.Unlock()
+	return gauge
+}
+
+// Histogram returns a Float64Histogram of the sink's meter
+func (s *otelSink) Histogram(desc MetricDesc) MetricHistogram {
+	opts := []metric.Float64HistogramOption{metric.WithDescription(desc.Help)}
+	if desc.Buckets != nil {
+		opts = append(opts, metric.WithExplicitBucketBoundaries(desc.Buckets...))
+	}
+	histogram, err := s.meter.Float64Histogram(otelMetricName(desc), opts...)
+	if err != nil {
+		log.Warnf("Failed to create OpenTelemetry histogram %s: %v", otelMetricName(desc), err)
+		histogram = noop.Float64Histogram{}
+	}
+	return otelHistogram{histogram: histogram, labelNames: desc.LabelNames}
+}
+
+// Unregister stops reporting the sink's gauges. OpenTelemetry has no way to remove
+// synchronous instruments; they stop changing once the plugin is gone.
+func (s *otelSink) Unregister() {
+	s.mu.Lock()
+	registrations := s.registrations
+	s.registrations = nil
+	s.mu.Unlock()
+	for _, r := range registrations {
+		_ = r.Unregister()
+	}
+}
+
+// otelMetricName returns the OpenTelemetry instrument name of desc
+func otelMetricName(desc MetricDesc) string {
+	return "lynx.polaris." + desc.Name
+}
+
+// otelAttributes pairs label names with label values
+func otelAttributes(labelNames, labelValues []string) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(labelNames))
+	for i, name := range labelNames {
+		if i < len(labelValues) {
+			attrs = append(attrs, attribute.String(name, labelValues[i]))
+		}
+	}
+	return attrs
+}
+
+type otelCounter struct {
+	counter    metric.Float64Counter
+	labelNames []string
+}
+
+func (c otelCounter) Add(value float64, labelValues ...string) {
+	c.counter.Add(context.Background(), value, metric.WithAttributes(otelAttributes(c.labelNames, labelValues)...))
+}
+
+type otelHistogram struct {
+	histogram  metric.Float64Histogram
+	labelNames []string
+}
+
+func (h otelHistogram) Observe(value float64, labelValues ...string) {
+	h.histogram.Record(context.Background(), value, metric.WithAttributes(otelAttributes(h.labelNames, labelValues)...))
+}
+
+// otelGauge keeps the last value of every label set until it is observed
+type otelGauge struct {
+	labelNames []string
+
+	mu     sync.Mutex
+	points map[string]*otelGaugePoint
+}
+
+type otelGaugePoint struct {
+	attrs attribute.Set
+	value float64
+}
+
+func (g *otelGauge) Set(value float64, labelValues ...string) {
+	g.point(labelValues).value = value
+	g.mu.Unlock()
+}
+
+func (g *otelGauge) Add(delta float64, labelValues ...string) {
+	g.point(labelValues).value += delta
+	g.mu.Unlock()
+}
+
+// point returns the point of labelValues with g.mu held; the caller unlocks it
+func (g *otelGauge) point(labelValues []string) *otelGaugePoint {
+	key := strings.Join(labelValues, "\xff")
+	g.mu.Lock()
+	p, ok := g.points[key]
+	if !ok {
+		p = &otelGaugePoint{attrs: attribute.NewSet(otelAttributes(g.labelNames, labelValues)...)}
+		g.points[key] = p
+	}
+	return p
+}
+
+// observe reports every point of g through observable
+func (g *otelGauge) observe(o metric.Observer, observable metric.Float64Observable) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, p := range g.points {
+		o.ObserveFloat64(observable, p.value, metric.WithAttributeSet(p.attrs))
+	}
+}
+
+// SetMetricsSink sets the sink the plugin records its metrics to, e.g.
+// NewPrometheusSink(registry) for an application's own registry. It takes precedence over
+// metrics_backend and has to be called before the plugin initializes; nil restores the
+// configured backend.
+func (p *PlugPolaris) SetMetricsSink(sink MetricsSink) {
+	p.metricsSinkMutex.Lock()
+	defer p.metricsSinkMutex.Unlock()
+	p.metricsSink = sink
+}
+
+// newMetricsSink returns the sink set by SetMetricsSink, or one for the configured metrics_backend
+func (p *PlugPolaris) newMetricsSink() MetricsSink {
+	p.metricsSinkMutex.Lock()
+	sink := p.metricsSink
+	p.metricsSinkMutex.Unlock()
+	if sink != nil {
+		return sink
+	}
+	switch backend := p.currentConf().GetMetricsBackend(); backend {
+	case conf.MetricsBackendOTel:
+		return NewOTelSink(otel.GetMeterProvider().Meter(otelMeterName))
+	case conf.MetricsBackendLynx:
+		return NewLynxSink()
+	case "", conf.MetricsBackendPrometheus:
+	default:
+		log.Warnf("Unknown metrics_backend %q, using %s", backend, conf.MetricsBackendPrometheus)
+	}
+	return NewPrometheusSink(prometheus.DefaultRegisterer)
+}
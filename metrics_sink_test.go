@@ -0,0 +1,174 @@
+package polaris
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// recordingMeter is a meter keeping the values recorded with its instruments by
+// "name{attributes}"
+type recordingMeter struct {
+	noop.Meter
+
+	mu        sync.Mutex
+	values    map[string]float64
+	callbacks []metric.Callback
+}
+
+func newRecordingMeter() *recordingMeter {
+	return &recordingMeter{values: make(map[string]float64)}
+}
+
+func (m *recordingMeter) add(name string, attrs attribute.Set, value float64, replace bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := name + "{" + attrs.Encoded(attribute.DefaultEncoder()) + "}"
+	if replace {
+		m.values[key] = value
+	} else {
+		m.values[key] += value
+	}
+}
+
+func (m *recordingMeter) value(key string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.values[key]
+}
+
+func (m *recordingMeter) Float64Counter(name string, _ ...metric.Float64CounterOption) (metric.Float64Counter, error) {
+	return recordingCounter{meter: m, name: name}, nil
+}
+
+func (m *recordingMeter) Float64Histogram(name string, _ ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return recordingHistogram{meter: m, name: name}, nil
+}
+
+func (m *recordingMeter) Float64ObservableGauge(name string, _ ...metric.Float64ObservableGaugeOption) (metric.Float64ObservableGauge, error) {
+	return recordingGauge{name: name}, nil
+}
+
+func (m *recordingMeter) RegisterCallback(callback metric.Callback, _ ...metric.Observable) (metric.Registration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callbacks = append(m.callbacks, callback)
+	return noop.Registration{}, nil
+}
+
+// collect runs the registered callbacks, as a reader would
+func (m *recordingMeter) collect() {
+	m.mu.Lock()
+	callbacks := m.callbacks
+	m.mu.Unlock()
+	for _, callback := range callbacks {
+		_ = callback(context.Background(), recordingObserver{meter: m})
+	}
+}
+
+type recordingCounter struct {
+	noop.Float64Counter
+	meter *recordingMeter
+	name  string
+}
+
+func (c recordingCounter) Add(_ context.Context, value float64, opts ...metric.AddOption) {
+	c.meter.add(c.name, metric.NewAddConfig(opts).Attributes(), value, false)
+}
+
+type recordingHistogram struct {
+	noop.Float64Histogram
+	meter *recordingMeter
+	name  string
+}
+
+func (h recordingHistogram) Record(_ context.Context, value float64, opts ...metric.RecordOption) {
+	h.meter.add(h.name+"_count", metric.NewRecordConfig(opts).Attributes(), 1, false)
+	h.meter.add(h.name+"_sum", metric.NewRecordConfig(opts).Attributes(), value, false)
+}
+
+type recordingGauge struct {
+	noop.Float64ObservableGauge
+	name string
+}
+
+type recordingObserver struct {
+	noop.Observer
+	meter *recordingMeter
+}
+
+func (o recordingObserver) ObserveFloat64(observable metric.Float64Observable, value float64, opts ...metric.ObserveOption) {
+	o.meter.add(observable.(recordingGauge).name, metric.NewObserveConfig(opts).Attributes(), value, true)
+}
+
+func TestPrometheusSink_CustomRegistry(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics := NewMetrics(NewPrometheusSink(registry))
+	metrics.RecordSDKOperation("get_instances", "success")
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	var found bool
+	for _, family := range families {
+		if family.GetName() == "lynx_polaris_sdk_operations_total" {
+			found = true
+			assert.Equal(t, 1.0, family.GetMetric()[0].GetCounter().GetValue())
+		}
+	}
+	assert.True(t, found, "metrics are registered with the injected registry")
+
+	metrics.Unregister()
+	families, err = registry.Gather()
+	require.NoError(t, err)
+	assert.Empty(t, families)
+}
+
+func TestOTelSink(t *testing.T) {
+	meter := newRecordingMeter()
+	metrics := NewMetrics(NewOTelSink(meter))
+
+	metrics.RecordSDKOperation("get_instances", "success")
+	metrics.RecordSDKOperation("get_instances", "success")
+	metrics.RecordSDKOperationDuration("get_instances", 0.5)
+	metrics.AddConcurrencyInFlight("orders", 2)
+	metrics.AddConcurrencyInFlight("orders", -1)
+	meter.collect()
+
+	assert.Equal(t, 2.0, meter.value("lynx.polaris.sdk_operations_total{operation=get_instances,status=success}"))
+	assert.Equal(t, 1.0, meter.value("lynx.polaris.sdk_operations_duration_seconds_count{operation=get_instances}"))
+	assert.Equal(t, 0.5, meter.value("lynx.polaris.sdk_operations_duration_seconds_sum{operation=get_instances}"))
+	assert.Equal(t, 1.0, meter.value("lynx.polaris.concurrency_in_flight{service=orders}"))
+	metrics.Unregister()
+}
+
+func TestSetMetricsSink(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", MetricsBackend: conf.MetricsBackendLynx})
+	assert.IsType(t, &prometheusSink{}, plugin.newMetricsSink())
+	assert.Equal(t, lynxRegistry(), plugin.newMetricsSink().(*prometheusSink).registerer)
+
+	plugin.currentConf().MetricsBackend = conf.MetricsBackendOTel
+	assert.IsType(t, &otelSink{}, plugin.newMetricsSink())
+
+	sink := NewOTelSink(newRecordingMeter())
+	plugin.SetMetricsSink(sink)
+	assert.Same(t, sink, plugin.newMetricsSink())
+}
+
+func TestValidator_MetricsBackend(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, MetricsBackend: "statsd"}
+	assert.False(t, NewValidator(cfg).Validate().IsValid)
+
+	cfg.MetricsBackend = conf.MetricsBackendOTel
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		assert.NotContains(t, e.Field, "metrics_backend")
+	}
+}
@@ -0,0 +1,274 @@
+package polaris
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-kratos/kratos/v2/transport"
+	kgrpc "github.com/go-kratos/kratos/v2/transport/grpc"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Traffic mirroring module
+// Responsibility: sending copies of a share of the calls of a client to a shadow service
+// discovered through Polaris, e.g. a rewrite validated against production traffic. Copies
+// are fire-and-forget: their responses are discarded and never affect the original call.
+
+// MirrorHeader marks the mirrored requests, so that shadow services can skip side effects.
+const MirrorHeader = "x-mirror"
+
+// Default mirroring settings.
+const (
+	defaultMirrorTimeout     = 3 * time.Second
+	defaultMirrorMaxInFlight = 64
+)
+
+// Mirror results recorded in lynx_polaris_mirror_requests_total.
+const (
+	mirrorResultSuccess = "success"
+	mirrorResultError   = "error"
+	mirrorResultDropped = "dropped"
+)
+
+// MirrorOption customizes traffic mirroring.
+type MirrorOption func(*mirrorOptions)
+
+type mirrorOptions struct {
+	percentage  float64
+	version     string
+	timeout     time.Duration
+	maxInFlight int
+	tlsConfig   *tls.Config
+}
+
+// WithMirrorPercentage mirrors percentage, from 0 to 100, of the calls instead of all of them.
+func WithMirrorPercentage(percentage float64) MirrorOption {
+	return func(o *mirrorOptions) {
+		o.percentage = min(max(percentage, 0), 100)
+	}
+}
+
+// WithMirrorVersion mirrors the calls only to the instances of the shadow service with
+// version, e.g. a new release registered next to the current one under the same name.
+func WithMirrorVersion(version string) MirrorOption {
+	return func(o *mirrorOptions) {
+		o.version = version
+	}
+}
+
+// WithMirrorTimeout sets the timeout of mirrored calls, 3s by default. They do not inherit
+// the deadline or the cancellation of the original call.
+func WithMirrorTimeout(timeout time.Duration) MirrorOption {
+	return func(o *mirrorOptions) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
+// WithMirrorMaxInFlight caps the mirrored calls in flight, 64 by default. Calls are not
+// mirrored while the cap is reached.
+func WithMirrorMaxInFlight(n int) MirrorOption {
+	return func(o *mirrorOptions) {
+		if n > 0 {
+			o.maxInFlight = n
+		}
+	}
+}
+
+// WithMirrorTLS connects to the shadow service with TLS.
+func WithMirrorTLS(config *tls.Config) MirrorOption {
+	return func(o *mirrorOptions) {
+		o.tlsConfig = config
+	}
+}
+
+// mirror sends copies of calls to a shadow service through send.
+type mirror struct {
+	plugin   *PlugPolaris
+	service  string
+	opts     mirrorOptions
+	inFlight chan struct{}
+	send     func(ctx context.Context, req any) error
+}
+
+func (p *PlugPolaris) newMirror(service string, opts []MirrorOption) *mirror {
+	o := mirrorOptions{percentage: 100, timeout: defaultMirrorTimeout, maxInFlight: defaultMirrorMaxInFlight}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return &mirror{plugin: p, service: service, opts: o, inFlight: make(chan struct{}, o.maxInFlight)}
+}
+
+// middleware returns client middleware mirroring calls once the original call returned,
+// whatever its result.
+func (m *mirror) middleware() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			reply, err := handler(ctx, req)
+			if m.plugin.sampled(m.opts.percentage) {
+				m.mirror(ctx, req)
+			}
+			return reply, err
+		}
+	}
+}
+
+// mirror sends a copy of req in the background, unless too many copies are in flight.
+func (m *mirror) mirror(ctx context.Context, req any) {
+	select {
+	case m.inFlight <- struct{}{}:
+	default:
+		m.record(mirrorResultDropped)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.opts.timeout)
+	go func() {
+		defer func() { <-m.inFlight }()
+		defer cancel()
+		if err := m.send(ctx, req); err != nil {
+			log.Debugf("Mirrored call to %s failed: %v", m.service, err)
+			m.record(mirrorResultError)
+			return
+		}
+		m.record(mirrorResultSuccess)
+	}()
+}
+
+func (m *mirror) record(result string) {
+	if metrics := m.plugin.GetMetrics(); metrics != nil {
+		metrics.RecordMirrorRequest(m.service, result)
+	}
+}
+
+// nodeFilters returns the node filters selecting the shadow instances.
+func (m *mirror) nodeFilters() []selector.NodeFilter {
+	if m.opts.version == "" {
+		return nil
+	}
+	version := m.opts.version
+	return []selector.NodeFilter{NodeMatch(func(node selector.Node) bool { return node.Version() == version })}
+}
+
+// markMirrored is client middleware setting MirrorHeader on the mirrored calls.
+func markMirrored(handler middleware.Handler) middleware.Handler {
+	return func(ctx context.Context, req any) (any, error) {
+		if tr, ok := transport.FromClientContext(ctx); ok {
+			tr.RequestHeader().Set(MirrorHeader, "true")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// lazyClient creates a client on first use and closes it in the plugin cleanup.
+type lazyClient[C io.Closer] struct {
+	mu     sync.Mutex
+	client C
+	ok     bool
+}
+
+// get returns the client, creating it with dial on first use or after the plugin closed it.
+func (c *lazyClient[C]) get(p *PlugPolaris, name string, dial func() (C, error)) (C, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ok {
+		return c.client, nil
+	}
+	client, err := dial()
+	if err != nil {
+		return client, err
+	}
+	c.client, c.ok = client, true
+	_ = p.RegisterCleanupHook(name, func(context.Context) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if !c.ok {
+			return nil
+		}
+		c.ok = false
+		return c.client.Close()
+	}, 0)
+	return client, nil
+}
+
+// GRPCMirrorMiddleware returns Kratos gRPC client middleware sending copies of the calls to
+// shadowService, discovered through Polaris. The copies are sent once the original call has
+// returned, with MirrorHeader set, and their responses are discarded. Mirrored calls are
+// counted in lynx_polaris_mirror_requests_total by result: success, error, or dropped when
+// too many are in flight.
+func (p *PlugPolaris) GRPCMirrorMiddleware(shadowService string, opts ...MirrorOption) middleware.Middleware {
+	m := p.newMirror(shadowService, opts)
+	var conn lazyClient[*grpc.ClientConn]
+	m.send = func(ctx context.Context, req any) error {
+		tr, ok := transport.FromClientContext(ctx)
+		if !ok {
+			return fmt.Errorf("no client transport")
+		}
+		cc, err := conn.get(p, fmt.Sprintf("grpc-mirror-%s-%p", shadowService, m), func() (*grpc.ClientConn, error) {
+			options := []kgrpc.ClientOption{
+				kgrpc.WithEndpoint(discoveryEndpoint(shadowService)),
+				kgrpc.WithDiscovery(p.BuildDiscovery()),
+				kgrpc.WithNodeFilter(m.nodeFilters()...),
+				kgrpc.WithMiddleware(markMirrored),
+			}
+			if m.opts.tlsConfig != nil {
+				return kgrpc.Dial(context.Background(), append(options, kgrpc.WithTLSConfig(m.opts.tlsConfig))...)
+			}
+			return kgrpc.DialInsecure(context.Background(), options...)
+		})
+		if err != nil {
+			return err
+		}
+		// The reply is decoded into an empty message, keeping its fields as unknown fields
+		return cc.Invoke(ctx, tr.Operation(), req, &emptypb.Empty{})
+	}
+	return m.middleware()
+}
+
+// HTTPMirrorMiddleware returns Kratos HTTP client middleware sending copies of the calls,
+// with the same method, path and body, to shadowService, like GRPCMirrorMiddleware.
+func (p *PlugPolaris) HTTPMirrorMiddleware(shadowService string, opts ...MirrorOption) middleware.Middleware {
+	m := p.newMirror(shadowService, opts)
+	var client lazyClient[*khttp.Client]
+	m.send = func(ctx context.Context, req any) error {
+		tr, ok := transport.FromClientContext(ctx)
+		if !ok {
+			return fmt.Errorf("no client transport")
+		}
+		ht, ok := tr.(khttp.Transporter)
+		if !ok || ht.Request() == nil {
+			return fmt.Errorf("not an HTTP call")
+		}
+		c, err := client.get(p, fmt.Sprintf("http-mirror-%s-%p", shadowService, m), func() (*khttp.Client, error) {
+			return khttp.NewClient(context.Background(),
+				khttp.WithEndpoint(discoveryEndpoint(shadowService)),
+				khttp.WithDiscovery(p.BuildDiscovery()),
+				khttp.WithNodeFilter(m.nodeFilters()...),
+				khttp.WithMiddleware(markMirrored),
+				khttp.WithTLSConfig(m.opts.tlsConfig),
+				khttp.WithResponseDecoder(func(_ context.Context, res *http.Response, _ any) error {
+					_, err := io.Copy(io.Discard, res.Body)
+					return err
+				}),
+			)
+		})
+		if err != nil {
+			return err
+		}
+		original := ht.Request()
+		return c.Invoke(ctx, original.Method, original.URL.RequestURI(), req, nil)
+	}
+	return m.middleware()
+}
@@ -0,0 +1,110 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirrorMiddleware(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	m := plugin.newMirror("orders-shadow", nil)
+	mirrored := make(chan any, 1)
+	m.send = func(ctx context.Context, req any) error {
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline, "mirrored calls have their own timeout")
+		assert.NoError(t, ctx.Err(), "mirrored calls outlive the original call")
+		mirrored <- req
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	reply, err := m.middleware()(func(context.Context, any) (any, error) {
+		return "ok", errors.New("primary failed")
+	})(ctx, "order-1")
+	cancel()
+	assert.Equal(t, "ok", reply)
+	assert.EqualError(t, err, "primary failed", "the original result is returned unchanged")
+
+	select {
+	case req := <-mirrored:
+		assert.Equal(t, "order-1", req)
+	case <-time.After(time.Second):
+		t.Fatal("call not mirrored")
+	}
+
+	none := plugin.newMirror("orders-shadow", []MirrorOption{WithMirrorPercentage(0)})
+	none.send = func(context.Context, any) error {
+		t.Error("no call is mirrored with a percentage of 0")
+		return nil
+	}
+	_, err = none.middleware()(func(context.Context, any) (any, error) { return nil, nil })(context.Background(), nil)
+	require.NoError(t, err)
+}
+
+func TestMirrorMiddleware_MaxInFlight(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	m := plugin.newMirror("orders-shadow", []MirrorOption{WithMirrorMaxInFlight(1), WithMirrorTimeout(time.Minute)})
+	release := make(chan struct{})
+	sent := make(chan struct{}, 2)
+	m.send = func(context.Context, any) error {
+		sent <- struct{}{}
+		<-release
+		return nil
+	}
+	handler := m.middleware()(func(context.Context, any) (any, error) { return nil, nil })
+
+	_, _ = handler(context.Background(), nil)
+	<-sent
+	_, _ = handler(context.Background(), nil)
+	close(release)
+	select {
+	case <-sent:
+		t.Fatal("calls are not mirrored while the cap is reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMirrorNodeFilters(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.Empty(t, plugin.newMirror("orders", nil).nodeFilters())
+
+	node := func(addr, version string) selector.Node {
+		return selector.NewNode("grpc", addr, &registry.ServiceInstance{Name: "orders", Version: version})
+	}
+	nodes := []selector.Node{node("10.0.0.1:9000", "v1"), node("10.0.0.2:9000", "v2")}
+	filters := plugin.newMirror("orders", []MirrorOption{WithMirrorVersion("v2")}).nodeFilters()
+	require.Len(t, filters, 1)
+	assert.Equal(t, nodes[1:], filters[0](context.Background(), nodes))
+}
+
+func TestMarkMirrored(t *testing.T) {
+	request := httptest.NewRequest("POST", "/orders", nil)
+	ctx := transport.NewClientContext(context.Background(), &testClientTransport{testHTTPTransport: testHTTPTransport{request: request}})
+	_, err := markMirrored(func(context.Context, any) (any, error) { return nil, nil })(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "true", request.Header.Get(MirrorHeader))
+}
+
+func TestMirrorMiddleware_NoTransport(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	for name, mw := range map[string]func() error{
+		"grpc": func() error {
+			_, err := plugin.GRPCMirrorMiddleware("orders")(func(context.Context, any) (any, error) { return "ok", nil })(context.Background(), nil)
+			return err
+		},
+		"http": func() error {
+			_, err := plugin.HTTPMirrorMiddleware("orders")(func(context.Context, any) (any, error) { return "ok", nil })(context.Background(), nil)
+			return err
+		},
+	} {
+		assert.NoError(t, mw(), name)
+	}
+}
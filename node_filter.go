@@ -0,0 +1,70 @@
+package polaris
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/selector"
+)
+
+// NewNodeRouterChain returns the node router of NewNodeRouter followed by extraFilters, so
+// that organization-specific filtering applies to the nodes the Polaris routing rules
+// select. When NewNodeRouter returns nil, e.g. before initialization or with the routing
+// subsystem disabled, only extraFilters are applied. It returns nil when there is no
+// filter at all.
+func (p *PlugPolaris) NewNodeRouterChain(name string, extraFilters ...selector.NodeFilter) selector.NodeFilter {
+	return ChainNodeFilters(append([]selector.NodeFilter{p.NewNodeRouter(name)}, extraFilters...)...)
+}
+
+// ChainNodeFilters returns a node filter applying filters in order, each to the nodes the
+// previous one kept. Nil filters are skipped, and the chain stops once no node is left. It
+// returns nil when every filter is nil.
+func ChainNodeFilters(filters ...selector.NodeFilter) selector.NodeFilter {
+	chain := make([]selector.NodeFilter, 0, len(filters))
+	for _, filter := range filters {
+		if filter != nil {
+			chain = append(chain, filter)
+		}
+	}
+	switch len(chain) {
+	case 0:
+		return nil
+	case 1:
+		return chain[0]
+	}
+	return func(ctx context.Context, nodes []selector.Node) []selector.Node {
+		for _, filter := range chain {
+			if len(nodes) == 0 {
+				break
+			}
+			nodes = filter(ctx, nodes)
+		}
+		return nodes
+	}
+}
+
+// NodeMatch returns a node filter keeping the nodes for which match returns true.
+func NodeMatch(match func(node selector.Node) bool) selector.NodeFilter {
+	return func(_ context.Context, nodes []selector.Node) []selector.Node {
+		kept := make([]selector.Node, 0, len(nodes))
+		for _, node := range nodes {
+			if match(node) {
+				kept = append(kept, node)
+			}
+		}
+		return kept
+	}
+}
+
+// RequireNodeMetadata returns a node filter keeping the nodes with a non-empty value for
+// every one of keys in their metadata.
+func RequireNodeMetadata(keys ...string) selector.NodeFilter {
+	return NodeMatch(func(node selector.Node) bool {
+		metadata := node.Metadata()
+		for _, key := range keys {
+			if metadata[key] == "" {
+				return false
+			}
+		}
+		return true
+	})
+}
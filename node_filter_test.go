@@ -0,0 +1,53 @@
+package polaris
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainNodeFilters(t *testing.T) {
+	node := func(addr string, metadata map[string]string) selector.Node {
+		return selector.NewNode("grpc", addr, &registry.ServiceInstance{Name: "orders", Metadata: metadata})
+	}
+	nodes := []selector.Node{
+		node("10.0.0.1:9000", map[string]string{"cell": "a", "tier": "gold"}),
+		node("10.0.0.2:9000", map[string]string{"cell": "b"}),
+		node("10.0.0.3:9000", nil),
+	}
+	calls := 0
+	counting := func(_ context.Context, nodes []selector.Node) []selector.Node {
+		calls++
+		return nodes
+	}
+
+	assert.Nil(t, ChainNodeFilters(nil, nil))
+	chain := ChainNodeFilters(RequireNodeMetadata("cell"), nil, RequireNodeMetadata("tier"), counting)
+	require.NotNil(t, chain)
+	assert.Equal(t, nodes[:1], chain(context.Background(), nodes))
+	assert.Equal(t, 1, calls)
+
+	chain = ChainNodeFilters(NodeMatch(func(selector.Node) bool { return false }), counting)
+	assert.Empty(t, chain(context.Background(), nodes))
+	assert.Equal(t, 1, calls, "the chain stops once no node is left")
+}
+
+func TestNewNodeRouterChain(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.Nil(t, plugin.NewNodeRouterChain("orders"))
+
+	plugin.setConf(&conf.Polaris{Namespace: "default", Subsystems: &conf.Subsystems{Discovery: true}})
+	plugin.setInitialized()
+	nodes := []selector.Node{
+		selector.NewNode("grpc", "10.0.0.1:9000", &registry.ServiceInstance{Name: "orders", Metadata: map[string]string{"cell": "a"}}),
+		selector.NewNode("grpc", "10.0.0.2:9000", &registry.ServiceInstance{Name: "orders"}),
+	}
+	chain := plugin.NewNodeRouterChain("orders", RequireNodeMetadata("cell"))
+	require.NotNil(t, chain, "extra filters apply with the routing subsystem disabled")
+	assert.Equal(t, nodes[:1], chain(context.Background(), nodes))
+}
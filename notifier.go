@@ -0,0 +1,291 @@
+package polaris
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-lynx/lynx/log"
+	"github.com/go-lynx/lynx/plugins"
+)
+
+// Notifier delivers plugin events, such as *ServiceChangedEvent and *ConfigChangedEvent, to
+// an external system
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// NotifierFunc adapts a function to a Notifier
+type NotifierFunc func(ctx context.Context, event Event) error
+
+// Notify calls f(ctx, event)
+func (f NotifierFunc) Notify(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// MessagePublisher publishes a keyed message, e.g. through a Kafka producer
+type MessagePublisher func(ctx context.Context, key string, value []byte) error
+
+// AddNotifier registers notifier under name, replacing the notifier registered under the
+// same name, and delivers it the events of the given types (all types when none are given).
+// Each notifier is called from its own goroutine, so a slow notifier neither blocks the
+// watchers nor the other notifiers; like Subscribe, it loses the events arriving while its
+// buffer is full. Delivery errors are logged.
+func (p *PlugPolaris) AddNotifier(name string, notifier Notifier, eventTypes ...EventType) error {
+	if name == "" || notifier == nil {
+		return NewConfigError("notifier name and notifier are required")
+	}
+	if p.IsDestroyed() {
+		return newDestroyedError()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := p.events.subscribe(ctx, eventTypes...)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	p.notifierMutex.Lock()
+	if p.notifiers == nil {
+		p.notifiers = make(map[string]context.CancelFunc)
+	}
+	if previous := p.notifiers[name]; previous != nil {
+		previous()
+	}
+	p.notifiers[name] = cancel
+	p.notifierMutex.Unlock()
+
+	go runNotifier(ctx, name, notifier, events)
+	return nil
+}
+
+// RemoveNotifier stops the notifier registered under name and reports whether there was one
+func (p *PlugPolaris) RemoveNotifier(name string) bool {
+	p.notifierMutex.Lock()
+	cancel, ok := p.notifiers[name]
+	delete(p.notifiers, name)
+	p.notifierMutex.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// stopNotifiers stops every notifier, canceling their deliveries in flight
+func (p *PlugPolaris) stopNotifiers() {
+	p.notifierMutex.Lock()
+	notifiers := p.notifiers
+	p.notifiers = nil
+	p.notifierMutex.Unlock()
+	for _, cancel := range notifiers {
+		cancel()
+	}
+}
+
+// runNotifier delivers events to notifier until the subscription is closed
+func runNotifier(ctx context.Context, name string, notifier Notifier, events <-chan Event) {
+	for event := range events {
+		if err := notifier.Notify(ctx, event); err != nil {
+			log.Warnf("Notifier %s failed to deliver %s event for %s: %v", name, event.Type(), eventSubject(event), err)
+		}
+	}
+}
+
+// eventSubject returns the service, or file:group of config events, an event is about
+func eventSubject(event Event) string {
+	switch e := event.(type) {
+	case *ServiceChangedEvent:
+		return e.Service
+	case *ConfigChangedEvent:
+		return configWatcherName(e.FileName, e.Group)
+	case *ConfigReloadedEvent:
+		return configWatcherName(e.FileName, e.Group)
+	case *DegradationEvent:
+		if e.Service != "" {
+			return e.Service
+		}
+		return configWatcherName(e.FileName, e.Group)
+	case *CircuitBreakerChangedEvent:
+		return e.Breaker
+	}
+	return string(event.Type())
+}
+
+// NotifierOption customizes a webhook or publisher notifier
+type NotifierOption func(*notifierOptions)
+
+type notifierOptions struct {
+	client *http.Client
+	header http.Header
+	retry  *RetryManager
+	format EventFormat
+}
+
+// WithNotifierClient sets the HTTP client of a webhook notifier. Defaults to a client with
+// a 5s timeout.
+func WithNotifierClient(client *http.Client) NotifierOption {
+	return func(o *notifierOptions) {
+		if client != nil {
+			o.client = client
+		}
+	}
+}
+
+// WithNotifierHeader adds a header, e.g. an authorization token, to every request of a
+// webhook notifier
+func WithNotifierHeader(key, value string) NotifierOption {
+	return func(o *notifierOptions) {
+		o.header.Add(key, value)
+	}
+}
+
+// WithNotifierRetry sets the retry manager of failed deliveries. Webhook notifiers default
+// to 3 retries with exponential backoff from 500ms; publisher notifiers do not retry.
+func WithNotifierRetry(retry *RetryManager) NotifierOption {
+	return func(o *notifierOptions) {
+		o.retry = retry
+	}
+}
+
+// WithNotifierFormat sets how events are serialized, e.g. NewCloudEventsFormat for
+// CloudEvents envelopes. Defaults to JSONEventFormat.
+func WithNotifierFormat(format EventFormat) NotifierOption {
+	return func(o *notifierOptions) {
+		if format != nil {
+			o.format = format
+		}
+	}
+}
+
+// newNotifierOptions applies opts over the defaults, retrying with retry
+func newNotifierOptions(retry *RetryManager, opts []NotifierOption) *notifierOptions {
+	o := &notifierOptions{
+		client: &http.Client{Timeout: 5 * time.Second},
+		header: make(http.Header),
+		retry:  retry,
+		format: JSONEventFormat,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// deliver runs send, through the retry manager if one is set
+func (o *notifierOptions) deliver(ctx context.Context, send func() error) error {
+	if o.retry == nil {
+		return send()
+	}
+	return o.retry.DoWithRetryContext(ctx, send)
+}
+
+// webhookNotifier POSTs events to a URL
+type webhookNotifier struct {
+	url     string
+	options *notifierOptions
+}
+
+// NewWebhookNotifier returns a notifier POSTing every event to url, as JSON unless
+// WithNotifierFormat sets another format, retrying failed requests and responses other
+// than 2xx
+func NewWebhookNotifier(url string, opts ...NotifierOption) Notifier {
+	return &webhookNotifier{url: url, options: newNotifierOptions(NewRetryManager(3, 500*time.Millisecond), opts)}
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, event Event) error {
+	data, err := n.options.format.Marshal(event)
+	if err != nil {
+		return err
+	}
+	header := n.options.header.Clone()
+	header.Set("Content-Type", n.options.format.ContentType())
+	return n.options.deliver(ctx, func() error {
+		return postJSON(ctx, n.options.client, n.url, header, data)
+	})
+}
+
+// postJSON POSTs data to url, as application/json unless header sets another content
+// type, failing on responses other than 2xx
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s answered %s", url, resp.Status)
+	}
+	return nil
+}
+
+// NewPublisherNotifier returns a notifier publishing every event through publish, as JSON
+// unless WithNotifierFormat sets another format, keyed by the service, or file:group of
+// config events, so that a Kafka producer keeps the events of each service and config file
+// in order
+func NewPublisherNotifier(publish MessagePublisher, opts ...NotifierOption) Notifier {
+	options := newNotifierOptions(nil, opts)
+	return NotifierFunc(func(ctx context.Context, event Event) error {
+		data, err := options.format.Marshal(event)
+		if err != nil {
+			return err
+		}
+		return options.deliver(ctx, func() error {
+			return publish(ctx, eventSubject(event), data)
+		})
+	})
+}
+
+// LynxNotifier returns a notifier emitting the events on the Lynx plugin event bus, with
+// the typed event in the "event" metadata. Config changes are config.changed events,
+// config reloads config.applied, service changes resource.modified, degradations
+// performance.degraded, health changes health.status.changed and circuit breaker changes
+// dependency.status.changed.
+func (p *PlugPolaris) LynxNotifier() Notifier {
+	return NotifierFunc(func(_ context.Context, event Event) error {
+		p.EmitEvent(plugins.PluginEvent{
+			Type:     lynxEventType(event.Type()),
+			Priority: plugins.PriorityNormal,
+			Source:   "Notifier",
+			Category: "polaris",
+			Metadata: map[string]any{
+				"event_type": string(event.Type()),
+				"subject":    eventSubject(event),
+				"event":      event,
+			},
+		})
+		return nil
+	})
+}
+
+// lynxEventType maps a plugin event type to the Lynx plugin event type
+func lynxEventType(eventType EventType) plugins.EventType {
+	switch eventType {
+	case EventTypeConfigChanged:
+		return plugins.EventConfigurationChanged
+	case EventTypeConfigReloaded:
+		return plugins.EventConfigurationApplied
+	case EventTypeDegradation:
+		return plugins.EventPerformanceDegraded
+	case EventTypeHealthChanged:
+		return plugins.EventHealthStatusChanged
+	case EventTypeCircuitBreakerChanged:
+		return plugins.EventDependencyStatusChanged
+	}
+	return plugins.EventResourceModified
+}
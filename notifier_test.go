@@ -0,0 +1,85 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddNotifier(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	received := make(chan Event, 4)
+	notifier := NotifierFunc(func(_ context.Context, event Event) error {
+		received <- event
+		return nil
+	})
+	require.NoError(t, plugin.AddNotifier("test", notifier, EventTypeConfigChanged))
+	assert.Error(t, plugin.AddNotifier("", notifier))
+
+	plugin.notifyServiceChange("orders", nil)
+	plugin.notifyConfigChange(newConfigChange("app.yaml", "orders", "default", "a: 1\n", "a: 2\n"))
+	select {
+	case event := <-received:
+		changed, ok := event.(*ConfigChangedEvent)
+		require.True(t, ok, "only config changes are delivered")
+		assert.Equal(t, "app.yaml", changed.FileName)
+		assert.Len(t, changed.Diff, 2)
+	case <-time.After(time.Second):
+		t.Fatal("config change not delivered")
+	}
+
+	assert.True(t, plugin.RemoveNotifier("test"))
+	assert.False(t, plugin.RemoveNotifier("test"))
+	plugin.stopNotifiers()
+}
+
+func TestWebhookNotifier_Retry(t *testing.T) {
+	var calls atomic.Int32
+	var received ServiceChangedEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, WithNotifierHeader("Authorization", "Bearer token"),
+		WithNotifierRetry(NewRetryManager(2, time.Millisecond)))
+	event := &ServiceChangedEvent{Kind: EventTypeServiceChanged, Service: "orders", HealthyCount: 2}
+	require.NoError(t, notifier.Notify(context.Background(), event))
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, "orders", received.Service)
+	assert.Equal(t, 2, received.HealthyCount)
+}
+
+func TestPublisherNotifier(t *testing.T) {
+	var keys []string
+	notifier := NewPublisherNotifier(func(_ context.Context, key string, value []byte) error {
+		keys = append(keys, key)
+		assert.True(t, json.Valid(value))
+		return nil
+	})
+	require.NoError(t, notifier.Notify(context.Background(), &ServiceChangedEvent{Service: "orders"}))
+	require.NoError(t, notifier.Notify(context.Background(), &ConfigChangedEvent{FileName: "app.yaml", Group: "orders"}))
+	require.NoError(t, notifier.Notify(context.Background(), &HealthChangedEvent{}))
+	assert.Equal(t, []string{"orders", "app.yaml:orders", "health_changed"}, keys)
+}
+
+func TestLynxEventType(t *testing.T) {
+	assert.EqualValues(t, "config.changed", lynxEventType(EventTypeConfigChanged))
+	assert.EqualValues(t, "resource.modified", lynxEventType(EventTypeServiceChanged))
+	assert.EqualValues(t, "health.status.changed", lynxEventType(EventTypeHealthChanged))
+	assert.NoError(t, NewPolarisControlPlane().LynxNotifier().Notify(context.Background(), &ServiceChangedEvent{}))
+}
@@ -0,0 +1,81 @@
+package polaris
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallerArgument(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	arg := plugin.callerArgument()
+	assert.Equal(t, model.ArgumentTypeCallerService, arg.ArgumentType())
+	assert.Equal(t, "default", arg.Key())
+}
+
+func TestCheckOutboundRateLimit_NotInitialized(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	allowed, err := plugin.CheckOutboundRateLimit("payments", nil)
+	assert.Error(t, err)
+	assert.False(t, allowed)
+}
+
+// testClientTransport is a Kratos client transport for an operation.
+type testClientTransport struct {
+	testHTTPTransport
+	operation string
+}
+
+func (t *testClientTransport) Operation() string { return t.operation }
+
+func TestOutboundRateLimitMiddleware(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	var calls []quotaCall
+	code := model.QuotaResultOk
+	mw := outboundRateLimit(&quotaGate{plugin: plugin, service: "payments", getQuota: func(service, method string, labels map[string]string) (*model.QuotaResponse, error) {
+		calls = append(calls, quotaCall{service: service, method: method, labels: labels})
+		return &model.QuotaResponse{Code: code}, nil
+	}})
+	sent := 0
+	next := mw(func(context.Context, any) (any, error) {
+		sent++
+		return "ok", nil
+	})
+	ctx := transport.NewClientContext(context.Background(), &testClientTransport{operation: "/payments.v1.Payments/Charge"})
+
+	reply, err := next(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", reply)
+	assert.Equal(t, []quotaCall{{
+		service: "payments",
+		method:  "/payments.v1.Payments/Charge",
+		labels:  map[string]string{"method": "/payments.v1.Payments/Charge"},
+	}}, calls)
+
+	code = model.QuotaResultLimited
+	_, err = next(ctx, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, kerrors.Code(err))
+	assert.Equal(t, "RATE_LIMITED", kerrors.Reason(err))
+	assert.Equal(t, 1, sent)
+}
+
+func TestOutboundRateLimitMiddleware_UninitializedPluginSendsCall(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	sent := false
+	_, err := plugin.OutboundRateLimitMiddleware("payments")(func(context.Context, any) (any, error) {
+		sent = true
+		return nil, nil
+	})(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.True(t, sent)
+}
@@ -0,0 +1,96 @@
+package polaris
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// watchPartition is the subset of a service's instances a ServiceWatcher subscribes to.
+type watchPartition struct {
+	zone     string
+	campus   string
+	metadata map[string]string
+}
+
+func (wp *watchPartition) String() string {
+	return fmt.Sprintf("{zone: %s, campus: %s, metadata: %v}", wp.zone, wp.campus, wp.metadata)
+}
+
+// matches reports whether instance belongs to the partition.
+func (wp *watchPartition) matches(instance model.Instance) bool {
+	if wp.zone != "" && instance.GetZone() != wp.zone {
+		return false
+	}
+	if wp.campus != "" && instance.GetCampus() != wp.campus {
+		return false
+	}
+	metadata := instance.GetMetadata()
+	for k, v := range wp.metadata {
+		if metadata[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// filter returns the instances of the partition. Metadata is also filtered here, since the
+// SDK only applies it when the destination metadata router is enabled. When the partition
+// has no healthy instance, every instance is returned so the service stays reachable.
+func (wp *watchPartition) filter(instances []model.Instance) []model.Instance {
+	if wp == nil {
+		return instances
+	}
+	var partition []model.Instance
+	for _, instance := range instances {
+		if instance != nil && wp.matches(instance) {
+			partition = append(partition, instance)
+		}
+	}
+	if len(healthyInstances(partition)) == 0 && len(instances) > 0 {
+		return instances
+	}
+	return partition
+}
+
+// watchPartitionFor returns the partition a watch of serviceName subscribes to, or nil when
+// the service is watched in full. Zone and campus default to the location the SDK detected
+// for the local host.
+func (p *PlugPolaris) watchPartitionFor(serviceName string) *watchPartition {
+	p.mu.RLock()
+	cfg := p.currentConf().GetWatchPartition()
+	sdk := p.sdk
+	p.mu.RUnlock()
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	if len(cfg.GetServices()) > 0 && !slices.Contains(cfg.GetServices(), serviceName) {
+		return nil
+	}
+
+	var local model.Location
+	if sdk != nil && sdk.GetValueContext() != nil {
+		if info := sdk.GetValueContext().GetCurrentLocation(); info != nil && info.GetLocation() != nil {
+			local = *info.GetLocation()
+		}
+	}
+	wp := &watchPartition{zone: cfg.GetZone(), metadata: maps.Clone(cfg.GetMetadata())}
+	if wp.zone == "" {
+		wp.zone = local.Zone
+	}
+	if cfg.GetLevel() == conf.PartitionLevelCampus {
+		wp.campus = cfg.GetCampus()
+		if wp.campus == "" {
+			wp.campus = local.Campus
+		}
+	}
+	if wp.zone == "" && wp.campus == "" && len(wp.metadata) == 0 {
+		log.Warnf("Local location is unknown, watching service %s in full", serviceName)
+		return nil
+	}
+	return wp
+}
@@ -0,0 +1,91 @@
+package polaris
+
+import (
+	"testing"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// locatedInstance is a static instance with a zone and campus.
+type locatedInstance struct {
+	model.Instance
+	zone   string
+	campus string
+}
+
+func (i locatedInstance) GetZone() string   { return i.zone }
+func (i locatedInstance) GetCampus() string { return i.campus }
+
+func newLocatedInstance(host, zone, campus string, metadata map[string]string) model.Instance {
+	inst := NewStaticInstance("default", "svc", &conf.FallbackInstance{Host: host, Port: 8080, Metadata: metadata})
+	return locatedInstance{Instance: inst, zone: zone, campus: campus}
+}
+
+func TestWatchPartition_Filter(t *testing.T) {
+	instances := []model.Instance{
+		newLocatedInstance("10.0.0.1", "sh", "sh-1", map[string]string{"cell": "c1"}),
+		newLocatedInstance("10.0.0.2", "sh", "sh-2", map[string]string{"cell": "c2"}),
+		newLocatedInstance("10.1.0.1", "bj", "bj-1", map[string]string{"cell": "c1"}),
+	}
+
+	assert.Equal(t, instances, (*watchPartition)(nil).filter(instances))
+
+	out := (&watchPartition{zone: "sh"}).filter(instances)
+	require.Len(t, out, 2)
+
+	out = (&watchPartition{zone: "sh", campus: "sh-2"}).filter(instances)
+	require.Len(t, out, 1)
+	assert.Equal(t, "10.0.0.2", out[0].GetHost())
+
+	out = (&watchPartition{metadata: map[string]string{"cell": "c1"}}).filter(instances)
+	require.Len(t, out, 2)
+
+	// An empty partition spills over to the full set.
+	assert.Equal(t, instances, (&watchPartition{zone: "gz"}).filter(instances))
+}
+
+func TestWatchPartitionFor(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{})
+	assert.Nil(t, plugin.watchPartitionFor("svc"), "disabled")
+
+	plugin.currentConf().WatchPartition = &conf.WatchPartition{Enabled: true, Zone: "sh", Services: []string{"big"}}
+	assert.Nil(t, plugin.watchPartitionFor("svc"), "not a partitioned service")
+	wp := plugin.watchPartitionFor("big")
+	require.NotNil(t, wp)
+	assert.Equal(t, "sh", wp.zone)
+	assert.Empty(t, wp.campus, "zone level ignores campus")
+
+	plugin.currentConf().WatchPartition = &conf.WatchPartition{Enabled: true, Level: conf.PartitionLevelCampus, Zone: "sh", Campus: "sh-1"}
+	wp = plugin.watchPartitionFor("svc")
+	require.NotNil(t, wp)
+	assert.Equal(t, "sh-1", wp.campus)
+
+	// Without a detected or configured location there is nothing to partition by.
+	plugin.currentConf().WatchPartition = &conf.WatchPartition{Enabled: true}
+	assert.Nil(t, plugin.watchPartitionFor("svc"))
+}
+
+func TestServiceWatcher_Partition(t *testing.T) {
+	consumer := &fakeClients{instances: []model.Instance{
+		newLocatedInstance("10.0.0.1", "sh", "sh-1", map[string]string{"cell": "c1"}),
+		newLocatedInstance("10.1.0.1", "bj", "bj-1", map[string]string{"cell": "c1"}),
+	}}
+	watcher := NewServiceWatcher(consumer, "svc", "default")
+	watcher.setPartition(&watchPartition{zone: "sh", metadata: map[string]string{"cell": "c1"}})
+
+	var changed []model.Instance
+	watcher.SetOnInstancesChanged(func(instances []model.Instance) { changed = instances })
+	watcher.checkInstances()
+	require.Len(t, changed, 1)
+	assert.Equal(t, "10.0.0.1", changed[0].GetHost())
+	assert.Equal(t, map[string]string{"cell": "c1"}, consumer.last.Metadata)
+
+	full, err := watcher.FullInstances()
+	require.NoError(t, err)
+	assert.Len(t, full, 2)
+	assert.Nil(t, consumer.last.Metadata)
+}
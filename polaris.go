@@ -4,17 +4,22 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net"
+	"net/http"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/go-kratos/kratos/contrib/polaris/v2"
+	"github.com/go-kratos/kratos/v2/registry"
 	"github.com/go-lynx/lynx"
 	"github.com/go-lynx/lynx-polaris/conf"
 	"github.com/go-lynx/lynx/log"
 	"github.com/go-lynx/lynx/plugins"
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"golang.org/x/sync/singleflight"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -38,22 +43,36 @@ const (
 type PlugPolaris struct {
 	*plugins.BasePlugin
 	polaris *polaris.Polaris
-	conf    *conf.Polaris
-	rt      plugins.Runtime
+	// conf is the configuration, replaced as a whole at runtime; see currentConf
+	conf atomic.Pointer[conf.Polaris]
+	rt   plugins.Runtime
 
-	// SDK components
-	sdk api.SDKContext
+	// SDK components: the SDK context, its APIs created on first use, and the clients
+	// injected through the options of NewPolarisControlPlane, used instead of the APIs
+	sdk             api.SDKContext
+	apis            *sdkAPIs
+	clientOverrides sdkClients
+
+	// Time and jitter sources of retries, the circuit breaker, heartbeats and watchers
+	clock Clock
+	rand  *jitterRand
 
 	// Handed-out registry adapters that wrap the same SDK context. Retained so
 	// they can be torn down (deregistered) before the SDK is destroyed, avoiding
 	// use-after-destroy when Kratos calls Register/GetService during shutdown.
 	registrar *PolarisRegistrar
+	// discovery is the handed-out discovery, retained so self-healing can switch it to a
+	// rebuilt SDK context
+	discovery *PolarisDiscovery
 
 	// Enhanced components
 	metrics        *Metrics
 	retryManager   *RetryManager
 	circuitBreaker *CircuitBreaker
 
+	// Registry of circuit breakers controllable at runtime, including circuitBreaker
+	circuitBreakers *CircuitBreakerRegistry
+
 	// State management - using atomic operations to improve concurrency safety
 	mu            sync.RWMutex
 	initialized   int32 // Use int32 instead of bool to support atomic operations
@@ -62,27 +81,178 @@ type PlugPolaris struct {
 	lifecycleCtx  context.Context
 	lifecycleStop context.CancelFunc
 
-	// Service information
+	// Service information and registration hooks
 	serviceInfo *ServiceInfo
+	hooks       *registrationHooks
 
 	// Event handling
 	activeWatchers map[string]*ServiceWatcher // Active service watchers
 	configWatchers map[string]*ConfigWatcher  // Active configuration watchers
 	watcherMutex   sync.RWMutex               // Watcher mutex
 
-	// Retry deduplication: prevent multiple retry goroutines for same service/config
-	retryingServiceWatchers map[string]struct{}
-	retryingConfigWatchers  map[string]struct{}
-	retryMutex              sync.Mutex
+	// Shared run loop and workers polling the watchers, per lifecycle
+	dispatcher      *watchDispatcher
+	dispatcherMutex sync.Mutex
 
-	// Cache system
-	serviceCache map[string]any // Service instance cache
-	configCache  map[string]any // Configuration cache
-	cacheMutex   sync.RWMutex   // Cache mutex
+	// Retry deduplication: prevent multiple retry goroutines for same service/config.
+	// Each in-flight retry owns a context derived from its watcher so that stopping
+	// the watcher or cleaning up the plugin cancels the retry.
+	retryingServiceWatchers map[string]*watchRetryEntry
+	retryingConfigWatchers  map[string]*watchRetryEntry
+	retryMutex              sync.Mutex
+	retryWg                 sync.WaitGroup
+
+	// Deduplicates concurrent discovery and config fetches, e.g. on a cache miss at startup
+	fetches singleflight.Group
+
+	// Instance and config caches, serving discovery fallbacks and last good config content
+	serviceCache *ttlCache[cachedInstances]
+	configCache  *ttlCache[cachedConfig]
+
+	// Cache lookups, retries and start time reported by GetStats
+	counters  runtimeCounters
+	startTime time.Time
+
+	// Static discovery fallbacks, used when discovery fails and the cache is empty,
+	// and route fallbacks, used when a service has no healthy instances
+	fallbackInstances map[string][]model.Instance
+	routeFallbacks    map[string]routeFallbackTarget
+	fallbackMutex     sync.RWMutex
+
+	// Config freshness tracking and per-file staleness alarms set at runtime
+	configFreshness     map[string]*configFreshnessEntry
+	stalenessThresholds map[string]time.Duration
+	freshnessMutex      sync.Mutex
+
+	// Instance weight set at runtime and the load source used by auto weighting
+	baseWeight int
+	loadSource LoadSource
+	warmedUp   int32 // set once the post-registration warm-up has completed
+
+	// Heartbeat failure handlers and consecutive failures per instance
+	heartbeatHandlers []HeartbeatFailureHandler
+	heartbeatFailures map[string]int
+	lastHeartbeat     time.Time
+	heartbeatMutex    sync.Mutex
+
+	// Config reload handlers by file and group
+	reloadHandlers map[string][]reloadHandler
+	reloadSeq      int
+	reloadMutex    sync.Mutex
+
+	// Config content validators by file and group
+	configValidators map[string][]func(content string) error
+	validatorMutex   sync.Mutex
+
+	// Local limiter deciding rate limit checks while the Polaris limit API fails
+	localLimiter *localLimiter
+
+	// Label values of the rate limit check metrics per service, built once
+	quotaMetricLabels metricLabelValues
+
+	// Concurrency limiter capping the calls in flight per service and label set
+	concurrencyLimiter *concurrencyLimiter
+
+	// Config gray-release labels set at runtime
+	configLabels map[string]string
+
+	// Decryptor of encrypted config values, set at runtime or built from config_encryption
+	decryptor      Decryptor
+	decryptorMutex sync.Mutex
+
+	// Error classifier shared by the retry manager and the circuit breaker, set at runtime
+	errorClassifier      ErrorClassifier
+	errorClassifierMutex sync.RWMutex
+
+	// Metrics sink set at runtime, overriding metrics_backend
+	metricsSink      MetricsSink
+	metricsSinkMutex sync.Mutex
+
+	// Audit sink set at runtime, the sink built from the audit config, and the sample
+	// rates set at runtime
+	auditSink           AuditSink
+	configuredAuditSink AuditSink
+	auditSampleRates    map[AuditEventType]float64
+	auditMutex          sync.RWMutex
+
+	// Alerters by name, the dedup window set at runtime and the dedup state by alert
+	alerters         map[string]registeredAlerter
+	alertDedupWindow time.Duration
+	alertDedups      map[string]alertDedup
+	alertMutex       sync.Mutex
+
+	// HTTP client of the Polaris OpenAPI shared by the OpenAPI clients, and the TLS
+	// settings it was built with
+	openAPIHTTP  *http.Client
+	openAPITLS   *conf.Tls
+	openAPIMutex sync.Mutex
+
+	// Fault injection rules loaded from fault_injection.rules_path, nil until loaded
+	serverFaultRules atomic.Pointer[[]*conf.FaultInjectionRule]
+
+	// Server addresses resolved from the server bootstrap, and the connection state of the
+	// naming and config servers by cluster
+	serverAddresses serverAddresses
+	servers         map[string]*serverCluster
+	serverMutex     sync.Mutex
+
+	// Typed event subscriptions, and the cancel functions of the notifiers by name
+	events                *eventBus
+	notifiers             map[string]context.CancelFunc
+	notifierMutex         sync.Mutex
+	lastHealth            int32 // healthStateUnknown / healthStateHealthy / healthStateUnhealthy
+	healthPending         int   // consecutive health results disagreeing with lastHealth
+	healthSuppressedFlaps int64
+	healthHistory         []HealthTransition
+	healthStateMutex      sync.Mutex
+	healthReport          HealthReport
+	healthReportMutex     sync.RWMutex
+	requiredConfigsLoaded int32 // set once the required config files have been fetched
+
+	// Self-healing: when health checks started failing, the last SDK rebuild, and the
+	// lock serializing rebuilds
+	failingSince  time.Time
+	lastRecovery  time.Time
+	selfHealMutex sync.Mutex
+	recoveryMutex sync.Mutex
+
+	// Serializes the creation of the SDK context on first use with lazy_init
+	connectMutex sync.Mutex
+
+	// Cleanup hooks run by CleanupTasks, and the lock serializing cleanups
+	cleanupHooks     []registeredCleanupHook
+	cleanupHookMutex sync.Mutex
+	cleanupMutex     sync.Mutex
+
+	// Standby cluster: whether it is active, since when health checks have been failing on
+	// the primary cluster or the primary has been reachable again from the standby, and the
+	// primary naming server addresses probed while on standby
+	standbyActive       int32
+	primaryFailingSince time.Time
+	primaryHealthySince time.Time
+	primaryAddresses    []string
+	standbyMutex        sync.Mutex
+
+	// Local configuration that the settings of remote_config are applied onto, captured
+	// before they are first loaded, and the reload handler applying them
+	localConf           *conf.Polaris
+	remoteConfigHandler string
+
+	// Token loaded from the token provider, replacing the token set in config, and the
+	// tokens of write operations with their providers
+	token                   string
+	tokenProvider           TokenProvider
+	operationTokens         map[TokenOperation]string
+	operationTokenProviders map[TokenOperation]TokenProvider
+	tokenMutex              sync.RWMutex
 }
 
 // ServiceInfo service registration information
 type ServiceInfo struct {
+	// ID is an explicit instance ID that stays the same across restarts, e.g. for
+	// correlating logs. It is registered as the instance_id metadata, since Polaris
+	// derives its own instance ID from namespace, service, host and port.
+	ID        string            `json:"id,omitempty"`
 	Service   string            `json:"service"`
 	Namespace string            `json:"namespace"`
 	Host      string            `json:"host"`
@@ -90,12 +260,66 @@ type ServiceInfo struct {
 	Protocol  string            `json:"protocol"`
 	Version   string            `json:"version"`
 	Metadata  map[string]string `json:"metadata"`
+	// Priority is the Polaris instance priority in [0, 9]; 0 is the highest priority.
+	// Consumers using priority routing only send traffic to lower priorities when
+	// every instance of a higher priority is unhealthy.
+	Priority int `json:"priority,omitempty"`
+	// Endpoints are additional endpoints registered under the same service,
+	// e.g. a gRPC port next to the primary HTTP port
+	Endpoints []ServiceEndpoint `json:"endpoints,omitempty"`
+}
+
+// ServiceEndpoint is an additional host/port/protocol of a service
+type ServiceEndpoint struct {
+	Host     string `json:"host"`
+	Port     int32  `json:"port"`
+	Protocol string `json:"protocol"`
+}
+
+// ServiceInstance converts the service information into a Kratos registry instance whose
+// endpoints are the primary endpoint followed by the additional endpoints. Each endpoint
+// is registered as its own Polaris instance by PolarisRegistrar.
+func (info *ServiceInfo) ServiceInstance() *registry.ServiceInstance {
+	if info == nil {
+		return nil
+	}
+	instance := &registry.ServiceInstance{
+		ID:       info.ID,
+		Name:     info.Service,
+		Version:  info.Version,
+		Metadata: make(map[string]string, len(info.Metadata)),
+	}
+	for k, v := range info.Metadata {
+		instance.Metadata[k] = v
+	}
+	if info.Priority != 0 {
+		instance.Metadata[instancePriorityMetadataKey] = strconv.Itoa(info.Priority)
+	}
+	if info.Host != "" {
+		instance.Endpoints = append(instance.Endpoints, formatEndpoint(info.Protocol, info.Host, info.Port))
+	}
+	for _, ep := range info.Endpoints {
+		if ep.Host == "" {
+			continue
+		}
+		instance.Endpoints = append(instance.Endpoints, formatEndpoint(ep.Protocol, ep.Host, ep.Port))
+	}
+	return instance
+}
+
+// formatEndpoint formats an endpoint URL, defaulting the protocol to http.
+func formatEndpoint(protocol, host string, port int32) string {
+	if protocol == "" {
+		protocol = "http"
+	}
+	return fmt.Sprintf("%s://%s", protocol, net.JoinHostPort(host, strconv.Itoa(int(port))))
 }
 
 // NewPolarisControlPlane creates a new Polaris control plane plugin.
-// Weight is MaxInt so it initializes before plugins that depend on it.
-func NewPolarisControlPlane() *PlugPolaris {
-	return &PlugPolaris{
+// Weight is MaxInt so it initializes before plugins that depend on it. opts replace the
+// polaris-go clients of the plugin, e.g. with fakes in tests.
+func NewPolarisControlPlane(opts ...Option) *PlugPolaris {
+	p := &PlugPolaris{
 		BasePlugin: plugins.NewBasePlugin(
 			plugins.GeneratePluginID("", pluginName, pluginVersion),
 			pluginName,
@@ -107,19 +331,30 @@ func NewPolarisControlPlane() *PlugPolaris {
 		healthCheckCh:           make(chan struct{}),
 		activeWatchers:          make(map[string]*ServiceWatcher),
 		configWatchers:          make(map[string]*ConfigWatcher),
-		retryingServiceWatchers: make(map[string]struct{}),
-		retryingConfigWatchers:  make(map[string]struct{}),
-		serviceCache:            make(map[string]any),
-		configCache:             make(map[string]any),
+		retryingServiceWatchers: make(map[string]*watchRetryEntry),
+		retryingConfigWatchers:  make(map[string]*watchRetryEntry),
+		events:                  newEventBus(),
+		localLimiter:            newLocalLimiter(),
+		concurrencyLimiter:      newConcurrencyLimiter(),
+		circuitBreakers:         NewCircuitBreakerRegistry(),
+		clock:                   SystemClock,
+	}
+	p.serviceCache = newTTLCache(p, cacheNameService, p.refreshServiceCache)
+	p.configCache = newTTLCache(p, cacheNameConfig, p.refreshConfigCache)
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
 	}
+	return p
 }
 
 // InitializeResources scans the "lynx.polaris" config subtree and validates it.
 func (p *PlugPolaris) InitializeResources(rt plugins.Runtime) error {
 	p.rt = rt
-	p.conf = &conf.Polaris{}
+	p.setConf(&conf.Polaris{})
 
-	err := rt.GetConfig().Value(confPrefix).Scan(p.conf)
+	err := rt.GetConfig().Value(confPrefix).Scan(p.currentConf())
 	if err != nil {
 		return WrapInitError(err, "failed to scan polaris configuration")
 	}
@@ -142,83 +377,165 @@ func (p *PlugPolaris) InitializeResources(rt plugins.Runtime) error {
 
 // setDefaultConfig sets default configuration
 func (p *PlugPolaris) setDefaultConfig() {
+	setConfigDefaults(p.currentConf())
+}
+
+// setConfigDefaults sets the defaults of unset fields of cfg
+func setConfigDefaults(cfg *conf.Polaris) {
 	// Default namespace is 'default'
-	if p.conf.Namespace == "" {
-		p.conf.Namespace = conf.DefaultNamespace
+	if cfg.Namespace == "" {
+		cfg.Namespace = conf.DefaultNamespace
 	}
 	// Default service instance weight is 100
-	if p.conf.Weight == 0 {
-		p.conf.Weight = conf.DefaultWeight
+	if cfg.Weight == 0 {
+		cfg.Weight = conf.DefaultWeight
 	}
 	// Default TTL is 5 seconds
-	if p.conf.Ttl == 0 {
-		p.conf.Ttl = conf.DefaultTTL
+	if cfg.Ttl == 0 {
+		cfg.Ttl = conf.DefaultTTL
 	}
 	// Default timeout is 5 seconds
-	if p.conf.Timeout == nil {
-		p.conf.Timeout = conf.GetDefaultTimeout()
+	if cfg.Timeout == nil {
+		cfg.Timeout = conf.GetDefaultTimeout()
 	}
 	// Default shutdown timeout for graceful cleanup
-	if p.conf.ShutdownTimeout == nil {
-		p.conf.ShutdownTimeout = conf.GetDefaultShutdownTimeout()
+	if cfg.ShutdownTimeout == nil {
+		cfg.ShutdownTimeout = conf.GetDefaultShutdownTimeout()
 	}
 	// Default circuit breaker threshold
-	if p.conf.CircuitBreakerThreshold <= 0 {
-		p.conf.CircuitBreakerThreshold = float32(conf.DefaultCircuitBreakerThreshold)
+	if cfg.CircuitBreakerThreshold <= 0 {
+		cfg.CircuitBreakerThreshold = float32(conf.DefaultCircuitBreakerThreshold)
 	}
 }
 
 // validateConfig validates configuration
 func (p *PlugPolaris) validateConfig() error {
-	if p.conf == nil {
+	cfg := p.currentConf()
+	if cfg == nil {
 		return NewConfigError("configuration is required")
 	}
 
-	validator := NewValidator(p.conf)
+	validator := NewValidator(cfg)
 	result := validator.Validate()
 	if !result.IsValid {
 		return NewConfigError(result.Errors[0].Error())
 	}
+	p.logValidationWarnings(result)
 
 	return nil
 }
 
+// logValidationWarnings logs the warnings of a valid configuration
+func (p *PlugPolaris) logValidationWarnings(result *ValidationResult) {
+	for _, warning := range result.Warnings {
+		log.Warnf("Polaris configuration: %s", p.redact(warning.Error()))
+	}
+}
+
 // initComponents initializes enhanced components
 func (p *PlugPolaris) initComponents() error {
-	// Initialize monitoring metrics
-	p.metrics = NewPolarisMetrics()
+	// Initialize monitoring metrics on the injected sink or the configured backend
+	p.metrics = NewMetrics(p.newMetricsSink())
+
+	// Initialize the audit sink from config
+	if err := p.initAuditSink(); err != nil {
+		return err
+	}
+
+	// Initialize retry manager and circuit breaker from config
+	p.initResilience()
+
+	// Size the event replay buffer from config
+	if size := p.currentConf().GetEventHistorySize(); size != 0 {
+		p.events.setHistorySize(int(size))
+	}
+
+	// Register the alert webhooks from config
+	p.initAlerters()
+
+	// Register static discovery and route fallbacks from configuration
+	p.loadConfiguredFallbacks()
+	p.loadConfiguredRouteFallbacks()
+
+	return nil
+}
 
+// initResilience builds the retry manager and circuit breaker of the plugin from config,
+// replacing the previous ones.
+func (p *PlugPolaris) initResilience() {
 	// Initialize retry manager from config
-	maxRetry := int(p.conf.MaxRetryTimes)
+	maxRetry := int(p.currentConf().MaxRetryTimes)
 	if maxRetry <= 0 {
 		maxRetry = 3
 	}
 	retryInterval := time.Second
-	if p.conf.RetryInterval != nil && p.conf.RetryInterval.AsDuration() > 0 {
-		retryInterval = p.conf.RetryInterval.AsDuration()
+	if p.currentConf().RetryInterval != nil && p.currentConf().RetryInterval.AsDuration() > 0 {
+		retryInterval = p.currentConf().RetryInterval.AsDuration()
 	} else {
 		retryInterval = conf.DefaultRetryInterval
 	}
-	p.retryManager = NewRetryManager(maxRetry, retryInterval)
+	backoff, ok := backoffStrategyByName(p.currentConf().GetRetryBackoff(), p.rand)
+	if !ok {
+		log.Warnf("Unknown retry_backoff %q, using %s", p.currentConf().GetRetryBackoff(), conf.RetryBackoffExponential)
+		backoff = ExponentialBackoff
+	}
+	retryManager := NewRetryManager(maxRetry, retryInterval, WithRetryBackoff(backoff),
+		WithRetryMaxDelay(p.currentConf().GetRetryMaxDelay().AsDuration()), WithRetryErrorClassifier(p.classifyError),
+		WithHedging(p.currentConf().GetHedgeDelay().AsDuration()), WithRetryClock(p.clock))
 
-	// Initialize circuit breaker from config (threshold + half-open timeout from defaults)
-	threshold := float64(p.conf.CircuitBreakerThreshold)
+	// Initialize circuit breaker from config (threshold, open duration, half-open probes, sliding window and slow calls)
+	threshold := float64(p.currentConf().CircuitBreakerThreshold)
 	if threshold <= 0 {
 		threshold = conf.DefaultCircuitBreakerThreshold
 	}
 	halfOpenTimeout := conf.DefaultCircuitBreakerHalfOpenTimeout
-	p.circuitBreaker = NewCircuitBreaker(threshold, halfOpenTimeout)
-
-	return nil
+	if d := p.currentConf().GetCircuitBreakerOpenDuration(); d != nil && d.AsDuration() > 0 {
+		halfOpenTimeout = d.AsDuration()
+	}
+	probes := int(p.currentConf().GetCircuitBreakerHalfOpenProbes())
+	if probes <= 0 {
+		probes = conf.DefaultCircuitBreakerHalfOpenProbes
+	}
+	window := conf.DefaultCircuitBreakerWindow
+	if d := p.currentConf().GetCircuitBreakerWindow(); d != nil && d.AsDuration() > 0 {
+		window = d.AsDuration()
+	}
+	minRequests := int(p.currentConf().GetCircuitBreakerMinRequests())
+	if minRequests <= 0 {
+		minRequests = conf.DefaultCircuitBreakerMinRequests
+	}
+	circuitBreaker := NewCircuitBreaker(threshold, halfOpenTimeout,
+		WithHalfOpenProbes(probes), WithRollingWindow(window), WithMinRequests(minRequests),
+		WithSlowCallThreshold(p.currentConf().GetCircuitBreakerSlowCallThreshold().AsDuration()),
+		WithErrorClassifier(p.classifyError), WithCircuitBreakerClock(p.clock))
+	p.mu.Lock()
+	p.retryManager, p.circuitBreaker = retryManager, circuitBreaker
+	p.mu.Unlock()
+	p.circuitBreakers.Register(PluginCircuitBreakerKey, circuitBreaker)
+	p.metrics.InstrumentRetryManager("polaris", retryManager)
+	p.counters.instrumentRetryManager(retryManager)
+	p.metrics.InstrumentCircuitBreaker(PluginCircuitBreakerKey, circuitBreaker)
+	p.alertOnBreakerOpen(PluginCircuitBreakerKey, circuitBreaker)
+	p.publishBreakerTransitions(PluginCircuitBreakerKey, circuitBreaker)
 }
 
-// checkInitialized unified state checking method ensuring thread safety
+// checkInitialized unified state checking method ensuring thread safety. With lazy_init,
+// the first check after startup creates the SDK context.
 func (p *PlugPolaris) checkInitialized() error {
+	if err := p.checkLifecycle(); err != nil {
+		return err
+	}
+	return p.ensureConnected()
+}
+
+// checkLifecycle fails unless the plugin is initialized and not destroyed, without
+// connecting to Polaris.
+func (p *PlugPolaris) checkLifecycle() error {
 	if atomic.LoadInt32(&p.initialized) == 0 {
-		return NewInitError("Polaris plugin not initialized")
+		return newNotInitializedError()
 	}
 	if atomic.LoadInt32(&p.destroyed) == 1 {
-		return NewInitError("Polaris plugin has been destroyed")
+		return newDestroyedError()
 	}
 	return nil
 }
@@ -262,6 +579,11 @@ func (p *PlugPolaris) publishRuntimeResources() error {
 		}
 	}
 	if discovery := p.NewServiceDiscovery(); discovery != nil {
+		if pd, ok := discovery.(*PolarisDiscovery); ok {
+			p.mu.Lock()
+			p.discovery = pd
+			p.mu.Unlock()
+		}
 		if err := p.rt.RegisterSharedResource(pluginName+".service_discovery", discovery); err != nil {
 			log.Warnf("failed to register polaris service discovery resource: %v", err)
 		}
@@ -309,19 +631,47 @@ func (p *PlugPolaris) IsDestroyed() bool {
 
 // GetPolarisConfig gets Polaris configuration
 func (p *PlugPolaris) GetPolarisConfig() *conf.Polaris {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	if p.conf == nil {
+	cfg := p.currentConf()
+	if cfg == nil {
 		return nil
 	}
-	return proto.Clone(p.conf).(*conf.Polaris)
+	return proto.Clone(cfg).(*conf.Polaris)
 }
 
-// SetServiceInfo sets service information
+// currentConf returns the configuration of the plugin, or nil before it is loaded. Once the
+// plugin has started, the configuration is replaced as a whole by setConf and never modified,
+// so it may be read without p.mu; read it once for settings that must be consistent.
+func (p *PlugPolaris) currentConf() *conf.Polaris {
+	return p.conf.Load()
+}
+
+// setConf replaces the configuration of the plugin
+func (p *PlugPolaris) setConf(cfg *conf.Polaris) {
+	p.conf.Store(cfg)
+}
+
+// SetServiceInfo sets service information. An empty or unspecified host (0.0.0.0, ::) is
+// replaced by the address detected with the host_detection rules, as are unspecified
+// hosts of additional endpoints.
 func (p *PlugPolaris) SetServiceInfo(info *ServiceInfo) {
+	clone := cloneServiceInfo(info)
+	if clone != nil {
+		if isUnspecifiedHost(clone.Host) {
+			if ip, err := p.DetectHostIP(); err != nil {
+				log.Warnf("Failed to detect advertise address for service %s: %v", clone.Service, err)
+			} else {
+				clone.Host = ip
+			}
+		}
+		for i, ep := range clone.Endpoints {
+			if ep.Host != "" && isUnspecifiedHost(ep.Host) {
+				clone.Endpoints[i].Host = clone.Host
+			}
+		}
+	}
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.serviceInfo = cloneServiceInfo(info)
+	p.serviceInfo = clone
 }
 
 // GetServiceInfo gets service information
@@ -336,6 +686,7 @@ func cloneServiceInfo(info *ServiceInfo) *ServiceInfo {
 		return nil
 	}
 	clone := *info
+	clone.Endpoints = append([]ServiceEndpoint(nil), info.Endpoints...)
 	if info.Metadata != nil {
 		clone.Metadata = make(map[string]string, len(info.Metadata))
 		for k, v := range info.Metadata {
@@ -345,22 +696,28 @@ func cloneServiceInfo(info *ServiceInfo) *ServiceInfo {
 	return &clone
 }
 
-// ControlPlaneCapabilities declares Polaris' explicit control plane contract.
+// ControlPlaneCapabilities declares Polaris' explicit control plane contract: the
+// capabilities of the enabled subsystems.
 func (p *PlugPolaris) ControlPlaneCapabilities() []lynx.ControlPlaneCapability {
-	return []lynx.ControlPlaneCapability{
-		lynx.ControlPlaneCapabilityConfig,
-		lynx.ControlPlaneCapabilityWatcher,
-		lynx.ControlPlaneCapabilityRegistry,
-		lynx.ControlPlaneCapabilityDiscovery,
-		lynx.ControlPlaneCapabilityRouter,
-		lynx.ControlPlaneCapabilityRateLimit,
+	var capabilities []lynx.ControlPlaneCapability
+	for _, sc := range subsystemCapabilities {
+		if p.SubsystemEnabled(sc.subsystem) {
+			capabilities = append(capabilities, sc.capabilities...)
+		}
 	}
+	return capabilities
 }
 
 // WatchConfig watches configuration changes
 func (p *PlugPolaris) WatchConfig(fileName, group string) (*ConfigWatcher, error) {
 	if !p.IsInitialized() {
-		return nil, NewInitError("Polaris plugin not initialized")
+		return nil, newNotInitializedError()
+	}
+	if err := p.checkSubsystem(SubsystemConfig); err != nil {
+		return nil, err
+	}
+	if err := p.ensureConnected(); err != nil {
+		return nil, err
 	}
 
 	// Record configuration watch operation metrics
@@ -378,16 +735,17 @@ func (p *PlugPolaris) WatchConfig(fileName, group string) (*ConfigWatcher, error
 	// Snapshot mutable plugin state under the lock to avoid a data race / nil
 	// dereference if cleanup runs concurrently.
 	p.mu.RLock()
-	sdk := p.sdk
+	configAPI := p.configLocked()
 	namespace := ""
-	if p.conf != nil {
-		namespace = p.conf.Namespace
+	if cfg := p.currentConf(); cfg != nil {
+		namespace = cfg.Namespace
 	}
 	metrics := p.metrics
+	debounceWindow, debounceMaxWait := debounceSettings(p.currentConf().GetConfigDebounce())
 	p.mu.RUnlock()
 
-	if sdk == nil {
-		return nil, NewInitError("Polaris plugin has been destroyed")
+	if configAPI == nil {
+		return nil, newDestroyedError()
 	}
 
 	// Check if the configuration is already being watched
@@ -400,15 +758,12 @@ func (p *PlugPolaris) WatchConfig(fileName, group string) (*ConfigWatcher, error
 	}
 	p.watcherMutex.Unlock()
 
-	// Create Config API client
-	configAPI := api.NewConfigFileAPIBySDKContext(sdk)
-	if configAPI == nil {
-		return nil, NewInitError("failed to create config API")
-	}
-
 	// Create configuration watcher and connect to SDK
 	watcher := NewConfigWatcherWithContext(p.watcherContext(), configAPI, fileName, group, namespace)
 	watcher.metrics = metrics // Pass metrics reference
+	watcher.SetClock(p.clock)
+	watcher.setDispatcher(p.watchDispatcher())
+	watcher.SetDebounce(debounceWindow, debounceMaxWait)
 
 	// Set event handling callbacks
 	watcher.SetOnConfigChanged(func(config model.ConfigFile) {
@@ -430,113 +785,66 @@ func (p *PlugPolaris) WatchConfig(fileName, group string) (*ConfigWatcher, error
 	return watcher, nil
 }
 
-// recordServiceChangeAudit logs an audit entry for a service-instance change event.
-func (p *PlugPolaris) recordServiceChangeAudit(serviceName string, instances []model.Instance) {
-	type instanceEntry struct {
-		ID       string `json:"id"`
-		Host     string `json:"host"`
-		Port     uint32 `json:"port"`
-		Weight   int    `json:"weight"`
-		Healthy  bool   `json:"healthy"`
-		Isolated bool   `json:"isolated"`
-	}
-	entries := make([]instanceEntry, 0, len(instances))
-	for _, inst := range instances {
-		if inst == nil {
-			continue
-		}
-		entries = append(entries, instanceEntry{
-			ID:       inst.GetId(),
-			Host:     inst.GetHost(),
-			Port:     inst.GetPort(),
-			Weight:   inst.GetWeight(),
-			Healthy:  inst.IsHealthy(),
-			Isolated: inst.IsIsolated(),
-		})
-	}
-	log.Infof("Service change audit: service=%s namespace=%s count=%d instances=%+v",
-		serviceName, p.conf.Namespace, len(instances), entries)
-}
-
-// recordServiceWatchErrorAudit logs an audit entry for a service-watcher error.
-func (p *PlugPolaris) recordServiceWatchErrorAudit(serviceName string, err error) {
-	if p.conf == nil {
-		return
-	}
-	log.Errorf("Service watch error audit: service=%s namespace=%s initialized=%v destroyed=%v err=%v errType=%T",
-		serviceName, p.conf.Namespace, p.IsInitialized(), p.IsDestroyed(), err, err)
-}
-
-// sendServiceWatchAlert emits a structured warning log for a service-watcher error.
-// Integrate external alerting (PagerDuty, DingTalk, SMS, etc.) here when needed.
-func (p *PlugPolaris) sendServiceWatchAlert(serviceName string, err error) {
-	if p.conf == nil {
-		return
-	}
-	log.Warnf("Service watch alert: type=service_watch_error service=%s namespace=%s severity=warning initialized=%v destroyed=%v err=%v",
-		serviceName, p.conf.Namespace, p.IsInitialized(), p.IsDestroyed(), err)
-}
-
-// recordConfigChangeAudit logs an audit entry for a configuration change event.
-func (p *PlugPolaris) recordConfigChangeAudit(fileName, group string, config model.ConfigFile) {
-	if p.conf == nil || config == nil {
-		return
-	}
-	log.Infof("Config change audit: file=%s group=%s namespace=%s contentLen=%d changeType=config_updated",
-		fileName, group, p.conf.Namespace, len(config.GetContent()))
-}
-
-// recordConfigWatchErrorAudit logs an audit entry for a config-watcher error.
-func (p *PlugPolaris) recordConfigWatchErrorAudit(fileName, group string, err error) {
-	if p.conf == nil {
-		return
-	}
-	log.Errorf("Config watch error audit: file=%s group=%s namespace=%s initialized=%v destroyed=%v err=%v errType=%T",
-		fileName, group, p.conf.Namespace, p.IsInitialized(), p.IsDestroyed(), err, err)
-}
-
-// sendConfigWatchAlert emits a structured warning log for a config-watcher error.
-// Integrate external alerting here when needed.
-func (p *PlugPolaris) sendConfigWatchAlert(fileName, group string, err error) {
-	if p.conf == nil {
-		return
+// configWatcherContext returns the context of the registered config watcher, or the
+// plugin lifecycle context when no watcher is registered for the key.
+func (p *PlugPolaris) configWatcherContext(configKey string) context.Context {
+	p.watcherMutex.RLock()
+	watcher := p.configWatchers[configKey]
+	p.watcherMutex.RUnlock()
+	if watcher != nil && watcher.ctx != nil {
+		return watcher.ctx
 	}
-	log.Warnf("Config watch alert: type=config_watch_error file=%s group=%s namespace=%s severity=warning err=%v",
-		fileName, group, p.conf.Namespace, err)
+	return p.watcherContext()
 }
 
-// tryStartConfigWatchRetry marks config as retrying and returns true if this goroutine should run the retry.
-// Returns false if a retry is already in progress for this config (deduplication).
-func (p *PlugPolaris) tryStartConfigWatchRetry(configKey string) bool {
+// tryStartConfigWatchRetry marks config as retrying and returns a retry context derived
+// from parent. Returns false if a retry is already in progress for this config (deduplication).
+func (p *PlugPolaris) tryStartConfigWatchRetry(parent context.Context, configKey string) (context.Context, *watchRetryEntry, bool) {
 	p.retryMutex.Lock()
 	defer p.retryMutex.Unlock()
 	if p.retryingConfigWatchers == nil {
-		return false
+		return nil, nil, false
 	}
 	if _, exists := p.retryingConfigWatchers[configKey]; exists {
-		return false
+		return nil, nil, false
 	}
-	p.retryingConfigWatchers[configKey] = struct{}{}
-	return true
+	ctx, cancel := context.WithCancel(parent)
+	retry := &watchRetryEntry{cancel: cancel}
+	p.retryingConfigWatchers[configKey] = retry
+	p.retryWg.Add(1)
+	return ctx, retry, true
 }
 
-// finishConfigWatchRetry removes config from retrying set (call when retry completes).
-func (p *PlugPolaris) finishConfigWatchRetry(fileName, group string) {
+// finishConfigWatchRetry releases the context of retry and removes it from the retrying set,
+// unless a later retry of the config replaced it.
+func (p *PlugPolaris) finishConfigWatchRetry(fileName, group string, retry *watchRetryEntry) {
 	configKey := fmt.Sprintf("%s:%s", fileName, group)
+	retry.cancel()
 	p.retryMutex.Lock()
 	defer p.retryMutex.Unlock()
-	if p.retryingConfigWatchers != nil {
+	if p.retryingConfigWatchers[configKey] == retry {
 		delete(p.retryingConfigWatchers, configKey)
 	}
 }
 
-// retryConfigWatch retries configuration watching
-func (p *PlugPolaris) retryConfigWatch(fileName, group string) {
-	defer p.finishConfigWatchRetry(fileName, group)
-	log.Infof("Retrying config watch for %s:%s", fileName, group)
+// recordWatcherRestart records that the retry loop re-established a watcher
+func (p *PlugPolaris) recordWatcherRestart(watcherType, name string) {
+	p.mu.RLock()
+	metrics := p.metrics
+	p.mu.RUnlock()
+	if metrics != nil {
+		metrics.RecordWatcherRestart(watcherType, name)
+	}
+}
+
+// retryConfigWatch recreates the watcher of a config file once its retry is due, unless the
+// retry was canceled.
+func (p *PlugPolaris) retryConfigWatch(fileName, group string, retry *watchRetryEntry, canceled bool) {
+	defer p.retryWg.Done()
+	defer p.finishConfigWatchRetry(fileName, group, retry)
 
-	if p.waitForRetryDelay(5 * time.Second) {
-		log.Infof("Config watch retry canceled due to plugin shutdown: %s:%s", fileName, group)
+	if canceled {
+		log.Infof("Config watch retry canceled (watcher stopped or plugin shutdown): %s:%s", fileName, group)
 		return
 	}
 
@@ -546,6 +854,7 @@ func (p *PlugPolaris) retryConfigWatch(fileName, group string) {
 
 	// Recreate watcher
 	if _, err := p.WatchConfig(fileName, group); err == nil {
+		p.recordWatcherRestart(watcherTypeConfig, configWatcherName(fileName, group))
 		log.Infof("Successfully recreated config watcher for %s:%s", fileName, group)
 	} else {
 		log.Errorf("Failed to recreate config watcher for %s:%s: %v", fileName, group, err)
@@ -10,6 +10,7 @@ import (
 	"github.com/go-kratos/kratos/v2/registry"
 	"github.com/go-lynx/lynx-polaris/conf"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/types/known/durationpb"
 )
@@ -108,6 +109,129 @@ func TestRetryManager_Context(t *testing.T) {
 	assert.Contains(t, err.Error(), "cancelled")
 }
 
+func TestDoWithRetryResult(t *testing.T) {
+	retryManager := NewRetryManager(3, time.Millisecond)
+	attempts := 0
+	value, err := DoWithRetryResult(context.Background(), retryManager, func() (int, error) {
+		attempts++
+		if attempts < 3 {
+			return attempts, assert.AnError
+		}
+		return 42, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 42, value)
+	assert.Equal(t, 3, attempts)
+
+	name, err := DoWithRetryResult(context.Background(), retryManager, func() (string, error) {
+		return "partial", assert.AnError
+	}, WithRetryErrorClassifier(func(error) ErrorClass { return ErrorClassFatal }))
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, name, "failures return the zero value")
+}
+
+func TestDoWithHedging(t *testing.T) {
+	retryManager := NewRetryManager(0, time.Millisecond, WithHedging(10*time.Millisecond))
+	var runs int32
+	loserCancelled := make(chan struct{})
+	value, err := DoWithHedging(context.Background(), retryManager, func(ctx context.Context) (string, error) {
+		if atomic.AddInt32(&runs, 1) == 1 {
+			<-ctx.Done()
+			close(loserCancelled)
+			return "", ctx.Err()
+		}
+		return "hedged", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "hedged", value)
+	select {
+	case <-loserCancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the slow run was not cancelled")
+	}
+
+	atomic.StoreInt32(&runs, 0)
+	_, err = DoWithHedging(context.Background(), retryManager, func(context.Context) (string, error) {
+		atomic.AddInt32(&runs, 1)
+		return "", assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs), "fast failures are not hedged")
+
+	atomic.StoreInt32(&runs, 0)
+	_, err = DoWithHedging(context.Background(), retryManager, func(context.Context) (string, error) {
+		atomic.AddInt32(&runs, 1)
+		return "", assert.AnError
+	}, WithHedging(0))
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+}
+
+func TestDoWithHedging_BothRunsFail(t *testing.T) {
+	retryManager := NewRetryManager(0, time.Millisecond, WithHedging(5*time.Millisecond))
+	var runs int32
+	_, err := DoWithHedging(context.Background(), retryManager, func(context.Context) (int, error) {
+		atomic.AddInt32(&runs, 1)
+		time.Sleep(20 * time.Millisecond)
+		return 0, assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&runs))
+}
+
+func TestBackoffStrategies(t *testing.T) {
+	base, maxDelay := 100*time.Millisecond, time.Second
+	assert.Equal(t, base, FixedBackoff(5, base, maxDelay, 0))
+	assert.Equal(t, 400*time.Millisecond, ExponentialBackoff(2, base, maxDelay, 0))
+	assert.Equal(t, maxDelay, ExponentialBackoff(10, base, maxDelay, 0))
+	for i := 0; i < 100; i++ {
+		assert.LessOrEqual(t, FullJitterBackoff(2, base, maxDelay, 0), 400*time.Millisecond)
+		delay := DecorrelatedJitterBackoff(3, base, maxDelay, 500*time.Millisecond)
+		assert.GreaterOrEqual(t, delay, base)
+		assert.LessOrEqual(t, delay, maxDelay)
+	}
+
+	for _, name := range conf.SupportedRetryBackoffs {
+		_, ok := BackoffStrategyByName(name)
+		assert.True(t, ok, name)
+	}
+	_, ok := BackoffStrategyByName("linear")
+	assert.False(t, ok)
+}
+
+func TestRetryManager_Backoff(t *testing.T) {
+	retryManager := NewRetryManager(3, 10*time.Millisecond, WithRetryBackoff(FixedBackoff))
+	assert.Equal(t, 10*time.Millisecond, retryManager.calculateBackoff(3, 0))
+
+	retryManager = NewRetryManager(3, 10*time.Millisecond, WithRetryMaxDelay(15*time.Millisecond))
+	assert.Equal(t, 15*time.Millisecond, retryManager.calculateBackoff(3, 0), "capped at the max delay")
+
+	var attempts []time.Time
+	err := retryManager.DoWithRetry(func() error {
+		attempts = append(attempts, time.Now())
+		return assert.AnError
+	}, WithRetryBackoff(func(int, time.Duration, time.Duration, time.Duration) time.Duration { return 0 }))
+	assert.Error(t, err)
+	assert.Len(t, attempts, 4)
+	assert.Less(t, attempts[3].Sub(attempts[0]), 10*time.Millisecond, "per-call backoff overrides the manager's")
+	assert.Equal(t, 15*time.Millisecond, retryManager.calculateBackoff(3, 0), "per-call options do not change the manager")
+}
+
+func TestValidator_RetryBackoff(t *testing.T) {
+	for _, cfg := range []*conf.Polaris{
+		{Namespace: "default", Weight: 100, RetryBackoff: "linear"},
+		{Namespace: "default", Weight: 100, RetryMaxDelay: durationpb.New(0)},
+		{Namespace: "default", Weight: 100, RetryMaxDelay: durationpb.New(time.Hour)},
+		{Namespace: "default", Weight: 100, HedgeDelay: durationpb.New(-time.Millisecond)},
+	} {
+		assert.False(t, NewValidator(cfg).Validate().IsValid)
+	}
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, RetryBackoff: conf.RetryBackoffFullJitter, RetryMaxDelay: durationpb.New(time.Minute)}
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		assert.NotContains(t, e.Field, "retry_")
+	}
+}
+
 // TestCircuitBreaker_Functionality tests circuit breaker functionality
 func TestCircuitBreaker_Functionality(t *testing.T) {
 	circuitBreaker := NewCircuitBreaker(0.5, 10*time.Millisecond)
@@ -159,9 +283,243 @@ func TestCircuitBreaker_DoesNotSerializeClosedOperations(t *testing.T) {
 	assert.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
 }
 
+// openCircuitBreaker trips cb and advances clock past its open duration
+func openCircuitBreaker(t *testing.T, cb *CircuitBreaker, clock *manualClock, openDuration time.Duration) {
+	_ = cb.Do(func() error { return assert.AnError })
+	assert.Equal(t, CircuitStateOpen, cb.GetState())
+	clock.Advance(openDuration + time.Millisecond)
+}
+
+func TestCircuitBreaker_HalfOpenProbes(t *testing.T) {
+	clock := newManualClock()
+	circuitBreaker := NewCircuitBreaker(0.5, 10*time.Millisecond, WithHalfOpenProbes(2), WithCircuitBreakerClock(clock))
+	openCircuitBreaker(t, circuitBreaker, clock, 10*time.Millisecond)
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, circuitBreaker.Do(func() error {
+				started <- struct{}{}
+				<-release
+				return nil
+			}))
+		}()
+	}
+	<-started
+	<-started
+	assert.Equal(t, CircuitStateHalfOpen, circuitBreaker.GetState())
+	err := circuitBreaker.Do(func() error { return nil })
+	assert.ErrorContains(t, err, "circuit breaker is half-open")
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, CircuitStateClosed, circuitBreaker.GetState())
+}
+
+func TestCircuitBreaker_HalfOpenProbeFailureReopens(t *testing.T) {
+	clock := newManualClock()
+	circuitBreaker := NewCircuitBreaker(0.5, 10*time.Millisecond, WithHalfOpenProbes(3), WithCircuitBreakerClock(clock))
+	openCircuitBreaker(t, circuitBreaker, clock, 10*time.Millisecond)
+
+	assert.NoError(t, circuitBreaker.Do(func() error { return nil }))
+	assert.Equal(t, CircuitStateHalfOpen, circuitBreaker.GetState(), "stays half-open until all probes succeed")
+	_ = circuitBreaker.Do(func() error { return assert.AnError })
+	assert.Equal(t, CircuitStateOpen, circuitBreaker.GetState())
+	assert.ErrorContains(t, circuitBreaker.Do(func() error { return nil }), "circuit breaker is open")
+}
+
+func TestCircuitBreaker_MinRequests(t *testing.T) {
+	circuitBreaker := NewCircuitBreaker(0.5, time.Second, WithMinRequests(4))
+	for i := 0; i < 3; i++ {
+		_ = circuitBreaker.Do(func() error { return assert.AnError })
+	}
+	assert.Equal(t, CircuitStateClosed, circuitBreaker.GetState(), "below the minimum request volume")
+	_ = circuitBreaker.Do(func() error { return assert.AnError })
+	assert.Equal(t, CircuitStateOpen, circuitBreaker.GetState())
+}
+
+func TestCircuitBreaker_SlidingWindowExpiresOldFailures(t *testing.T) {
+	clock := newManualClock()
+	circuitBreaker := NewCircuitBreaker(0.5, time.Second, WithRollingWindow(100*time.Millisecond), WithMinRequests(2),
+		WithCircuitBreakerClock(clock))
+	_ = circuitBreaker.Do(func() error { return assert.AnError })
+	assert.Equal(t, 1.0, circuitBreaker.GetFailureRate())
+
+	clock.Advance(120 * time.Millisecond)
+	assert.Equal(t, 0.0, circuitBreaker.GetFailureRate(), "failure left the window")
+	_ = circuitBreaker.Do(func() error { return assert.AnError })
+	assert.Equal(t, CircuitStateClosed, circuitBreaker.GetState(), "the early failure no longer counts")
+
+	_ = circuitBreaker.Do(func() error { return nil })
+	_ = circuitBreaker.Do(func() error { return assert.AnError })
+	assert.Equal(t, CircuitStateOpen, circuitBreaker.GetState())
+}
+
+func TestCircuitBreaker_SlowCalls(t *testing.T) {
+	clock := newManualClock()
+	circuitBreaker := NewCircuitBreaker(0.5, time.Second, WithSlowCallThreshold(10*time.Millisecond), WithMinRequests(2),
+		WithCircuitBreakerClock(clock))
+	assert.NoError(t, circuitBreaker.Do(func() error { return nil }))
+	err := circuitBreaker.Do(func() error {
+		clock.Advance(20 * time.Millisecond)
+		return nil
+	})
+	assert.NoError(t, err, "slow calls keep their result")
+	assert.Equal(t, CircuitStateOpen, circuitBreaker.GetState())
+}
+
+func TestCircuitBreaker_IgnoresClassifiedErrors(t *testing.T) {
+	clock := newManualClock()
+	circuitBreaker := NewCircuitBreaker(0.5, 10*time.Millisecond, WithCircuitBreakerClock(clock))
+	for i := 0; i < 3; i++ {
+		err := circuitBreaker.Do(func() error { return context.Canceled })
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+	assert.Equal(t, CircuitStateClosed, circuitBreaker.GetState(), "cancellations are not failures")
+	assert.Equal(t, 0.0, circuitBreaker.GetFailureRate())
+
+	openCircuitBreaker(t, circuitBreaker, clock, 10*time.Millisecond)
+	_ = circuitBreaker.Do(func() error { return context.Canceled })
+	assert.Equal(t, CircuitStateHalfOpen, circuitBreaker.GetState())
+	assert.NoError(t, circuitBreaker.Do(func() error { return nil }), "an ignored probe frees its slot")
+	assert.Equal(t, CircuitStateClosed, circuitBreaker.GetState())
+}
+
+func TestRetryManager_ErrorClassifier(t *testing.T) {
+	retryManager := NewRetryManager(3, time.Millisecond)
+	attempts := 0
+	err := retryManager.DoWithRetry(func() error {
+		attempts++
+		return context.Canceled
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, attempts, "cancellations are not retried")
+
+	retryManager = NewRetryManager(3, time.Millisecond, WithRetryErrorClassifier(func(error) ErrorClass { return ErrorClassFatal }))
+	attempts = 0
+	err = retryManager.DoWithRetryContext(context.Background(), func() error {
+		attempts++
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, attempts, "fatal errors are not retried")
+}
+
+func TestCircuitBreaker_Hooks(t *testing.T) {
+	clock := newManualClock()
+	circuitBreaker := NewCircuitBreaker(0.5, 10*time.Millisecond, WithCircuitBreakerClock(clock))
+	var transitions []string
+	var rejections []CircuitState
+	circuitBreaker.OnStateChange(func(from, to CircuitState) {
+		assert.Equal(t, to, circuitBreaker.GetState(), "hooks run outside the lock")
+		transitions = append(transitions, from.String()+"->"+to.String())
+	})
+	circuitBreaker.OnReject(func(state CircuitState) { rejections = append(rejections, state) })
+
+	openCircuitBreaker(t, circuitBreaker, clock, 0)
+	assert.Error(t, circuitBreaker.Do(func() error { return nil }))
+	clock.Advance(15 * time.Millisecond)
+	assert.NoError(t, circuitBreaker.Do(func() error { return nil }))
+	circuitBreaker.ForceOpen()
+
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed", "closed->open"}, transitions)
+	assert.Equal(t, []CircuitState{CircuitStateOpen}, rejections)
+}
+
+func TestRetryManager_Hooks(t *testing.T) {
+	retryManager := NewRetryManager(2, time.Millisecond)
+	var retried []int
+	var completed []int
+	var lastErr error
+	retryManager.OnRetry(func(attempt int, err error) { retried = append(retried, attempt) })
+	retryManager.OnComplete(func(attempts int, err error) {
+		completed = append(completed, attempts)
+		lastErr = err
+	})
+
+	assert.Error(t, retryManager.DoWithRetry(func() error { return assert.AnError }))
+	assert.Equal(t, []int{1, 2}, retried)
+	assert.ErrorIs(t, lastErr, assert.AnError)
+
+	assert.NoError(t, retryManager.DoWithRetryContext(context.Background(), func() error { return nil },
+		WithRetryBackoff(FixedBackoff)), "per-call copies keep the hooks")
+	assert.Equal(t, []int{3, 1}, completed)
+	assert.NoError(t, lastErr)
+}
+
+func TestMetrics_InstrumentResilience(t *testing.T) {
+	metrics := NewPolarisMetrics()
+	retryManager := NewRetryManager(1, time.Millisecond)
+	circuitBreaker := NewCircuitBreaker(0.5, time.Second)
+	metrics.InstrumentRetryManager("test-retry", retryManager)
+	metrics.InstrumentCircuitBreaker("test-breaker", circuitBreaker)
+
+	_ = circuitBreaker.Do(func() error {
+		return retryManager.DoWithRetry(func() error { return assert.AnError })
+	})
+	_ = circuitBreaker.Do(func() error { return nil })
+
+	assert.Equal(t, 1.0, gatheredValue(t, "lynx_polaris_retries_total", map[string]string{"name": "test-retry"}))
+	assert.Equal(t, 1.0, gatheredValue(t, "lynx_polaris_retry_failures_total", map[string]string{"name": "test-retry"}))
+	assert.Equal(t, 1.0, gatheredValue(t, "lynx_polaris_circuit_breaker_transitions_total", map[string]string{"name": "test-breaker", "from": "closed", "to": "open"}))
+	assert.Equal(t, 1.0, gatheredValue(t, "lynx_polaris_circuit_breaker_rejected_total", map[string]string{"name": "test-breaker", "state": "open"}))
+	assert.Equal(t, float64(CircuitStateOpen), gatheredValue(t, "lynx_polaris_circuit_breaker_state", map[string]string{"name": "test-breaker"}))
+}
+
+// gatheredValue returns the value of the counter or gauge name with labels from the default registry
+func gatheredValue(t *testing.T, name string, labels map[string]string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if labels[label.GetName()] != label.GetValue() {
+					continue metrics
+				}
+			}
+			if metric.GetCounter() != nil {
+				return metric.GetCounter().GetValue()
+			}
+			return metric.GetGauge().GetValue()
+		}
+	}
+	t.Fatalf("metric %s %v not found", name, labels)
+	return 0
+}
+
+func TestValidator_CircuitBreaker(t *testing.T) {
+	for _, cfg := range []*conf.Polaris{
+		{Namespace: "default", Weight: 100, CircuitBreakerOpenDuration: durationpb.New(time.Second)},
+		{Namespace: "default", Weight: 100, CircuitBreakerHalfOpenProbes: -1},
+		{Namespace: "default", Weight: 100, CircuitBreakerHalfOpenProbes: conf.MaxCircuitBreakerHalfOpenProbes + 1},
+		{Namespace: "default", Weight: 100, CircuitBreakerWindow: durationpb.New(time.Hour)},
+		{Namespace: "default", Weight: 100, CircuitBreakerMinRequests: -1},
+		{Namespace: "default", Weight: 100, CircuitBreakerSlowCallThreshold: durationpb.New(-time.Second)},
+	} {
+		assert.False(t, NewValidator(cfg).Validate().IsValid)
+	}
+	cfg := &conf.Polaris{
+		Namespace: "default", Weight: 100,
+		CircuitBreakerOpenDuration: durationpb.New(time.Minute), CircuitBreakerHalfOpenProbes: 5,
+		CircuitBreakerWindow: durationpb.New(30 * time.Second), CircuitBreakerMinRequests: 20,
+	}
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		assert.NotContains(t, e.Field, "circuit_breaker")
+	}
+}
+
 // TestServiceWatcher_Functionality tests service watcher functionality
 func TestServiceWatcher_Functionality(t *testing.T) {
-	watcher := NewServiceWatcher(nil, "test-service", "test-namespace")
+	instance := NewStaticInstance("test-namespace", "test-service", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})
+	watcher := NewServiceWatcher(&fakeClients{instances: []model.Instance{instance}}, "test-service", "test-namespace")
 	assert.NotNil(t, watcher)
 
 	// Test callback setting
@@ -176,6 +534,8 @@ func TestServiceWatcher_Functionality(t *testing.T) {
 
 	// Verify callback setting success
 	assert.False(t, callbackCalled)
+	watcher.checkInstances()
+	assert.True(t, callbackCalled)
 
 	// Test start and stop
 	watcher.Start()
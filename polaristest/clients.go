@@ -0,0 +1,293 @@
+package polaristest
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	polaris "github.com/go-lynx/lynx-polaris"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+var (
+	_ polaris.ConsumerClient = consumer{}
+	_ polaris.ProviderClient = provider{}
+	_ polaris.ConfigClient   = configClient{}
+	_ polaris.LimitClient    = limitClient{}
+)
+
+// Consumer returns the consumer client of the fake. GetInstances leaves out unhealthy and
+// isolated instances unless the request skips route filters, and keeps the instances
+// matching the request metadata. GetAllInstances returns every instance.
+func (f *Fake) Consumer() polaris.ConsumerClient {
+	return consumer{f}
+}
+
+// Provider returns the provider client of the fake
+func (f *Fake) Provider() polaris.ProviderClient {
+	return provider{f}
+}
+
+// Config returns the config client of the fake. A missing config file is returned without
+// content, like polaris-go does.
+func (f *Fake) Config() polaris.ConfigClient {
+	return configClient{f}
+}
+
+// Limit returns the limit client of the fake
+func (f *Fake) Limit() polaris.LimitClient {
+	return limitClient{f}
+}
+
+// consumer is the ConsumerClient of a Fake
+type consumer struct {
+	fake *Fake
+}
+
+func (c consumer) GetOneInstance(req *api.GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	if err := c.fake.call(OpGetInstances); err != nil {
+		return nil, err
+	}
+	c.fake.mu.Lock()
+	instances := c.fake.instancesLocked(serviceKey{req.Namespace, req.Service}, req.Metadata, false)
+	c.fake.mu.Unlock()
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("polaristest: no instances of service %s in namespace %s", req.Service, req.Namespace)
+	}
+	return &model.OneInstanceResponse{InstancesResponse: instancesResponse(req.Namespace, req.Service, instances[:1])}, nil
+}
+
+func (c consumer) GetInstances(req *api.GetInstancesRequest) (*model.InstancesResponse, error) {
+	if err := c.fake.call(OpGetInstances); err != nil {
+		return nil, err
+	}
+	c.fake.mu.Lock()
+	instances := c.fake.instancesLocked(serviceKey{req.Namespace, req.Service}, req.Metadata, req.SkipRouteFilter)
+	c.fake.mu.Unlock()
+	resp := instancesResponse(req.Namespace, req.Service, instances)
+	return &resp, nil
+}
+
+func (c consumer) GetAllInstances(req *api.GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	if err := c.fake.call(OpGetInstances); err != nil {
+		return nil, err
+	}
+	c.fake.mu.Lock()
+	instances := c.fake.instancesLocked(serviceKey{req.Namespace, req.Service}, nil, true)
+	c.fake.mu.Unlock()
+	resp := instancesResponse(req.Namespace, req.Service, instances)
+	return &resp, nil
+}
+
+// WatchService returns the instances of the service and a channel receiving an instance
+// event for every later change of them
+func (c consumer) WatchService(req *api.WatchServiceRequest) (*model.WatchServiceResponse, error) {
+	if err := c.fake.call(OpWatchService); err != nil {
+		return nil, err
+	}
+	key := serviceKey{req.Key.Namespace, req.Key.Service}
+	ch := make(chan model.SubScribeEvent, watchBuffer)
+	c.fake.mu.Lock()
+	c.fake.watches[key] = append(c.fake.watches[key], ch)
+	instances := c.fake.instancesLocked(key, nil, true)
+	c.fake.mu.Unlock()
+	resp := instancesResponse(key.namespace, key.service, instances)
+	return &model.WatchServiceResponse{EventChannel: ch, GetAllInstancesResp: &resp}, nil
+}
+
+func (c consumer) UpdateServiceCallResult(*api.ServiceCallResult) error {
+	return c.fake.call(OpReportCallResult)
+}
+
+func instancesResponse(namespace, service string, instances []model.Instance) model.InstancesResponse {
+	return model.InstancesResponse{
+		ServiceInfo: model.ServiceInfo{Namespace: namespace, Service: service},
+		Instances:   instances,
+	}
+}
+
+// provider is the ProviderClient of a Fake
+type provider struct {
+	fake *Fake
+}
+
+func (p provider) RegisterInstance(req *api.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	return p.Register(req)
+}
+
+// Register stores the instance of the request, replacing the one at the same address
+func (p provider) Register(req *api.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	if err := p.fake.call(OpRegister); err != nil {
+		return nil, err
+	}
+	inst := &instance{
+		namespace: req.Namespace,
+		service:   req.Service,
+		host:      req.Host,
+		port:      uint32(req.Port),
+		weight:    conf.DefaultWeight,
+		healthy:   req.Healthy == nil || *req.Healthy,
+		isolated:  req.Isolate != nil && *req.Isolate,
+		metadata:  maps.Clone(req.Metadata),
+	}
+	if req.Protocol != nil {
+		inst.protocol = *req.Protocol
+	}
+	if req.Version != nil {
+		inst.version = *req.Version
+	}
+	if req.Weight != nil {
+		inst.weight = *req.Weight
+	}
+	existed := p.fake.putInstance(inst)
+	return &model.InstanceRegisterResponse{InstanceID: inst.GetId(), Existed: existed}, nil
+}
+
+func (p provider) Deregister(req *api.InstanceDeRegisterRequest) error {
+	if err := p.fake.call(OpDeregister); err != nil {
+		return err
+	}
+	p.fake.RemoveInstance(req.Namespace, req.Service, req.Host, req.Port)
+	return nil
+}
+
+// Heartbeat fails for instances that are not registered
+func (p provider) Heartbeat(req *api.InstanceHeartbeatRequest) error {
+	if err := p.fake.call(OpHeartbeat); err != nil {
+		return err
+	}
+	p.fake.mu.Lock()
+	_, ok := p.fake.instances[serviceKey{req.Namespace, req.Service}][address(req.Host, req.Port)]
+	p.fake.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("polaristest: instance %s of service %s is not registered", address(req.Host, req.Port), req.Service)
+	}
+	return nil
+}
+
+// configClient is the ConfigClient of a Fake
+type configClient struct {
+	fake *Fake
+}
+
+// GetConfigFile returns a snapshot of the config file. Its change listeners receive the
+// later changes published to the fake.
+func (c configClient) GetConfigFile(namespace, fileGroup, fileName string) (model.ConfigFile, error) {
+	if err := c.fake.call(OpGetConfigFile); err != nil {
+		return nil, err
+	}
+	key := configKey{namespace, fileGroup, fileName}
+	c.fake.mu.Lock()
+	content, ok := c.fake.configs[key]
+	c.fake.mu.Unlock()
+	return &configFile{fake: c.fake, key: key, content: content, hasContent: ok}, nil
+}
+
+// configFile is a snapshot of a config file of a Fake
+type configFile struct {
+	fake       *Fake
+	key        configKey
+	content    string
+	hasContent bool
+}
+
+func (c *configFile) GetNamespace() string { return c.key.namespace }
+func (c *configFile) GetFileGroup() string { return c.key.group }
+func (c *configFile) GetFileName() string  { return c.key.fileName }
+func (c *configFile) GetContent() string   { return c.content }
+func (c *configFile) HasContent() bool     { return c.hasContent }
+
+func (c *configFile) AddChangeListenerWithChannel(ch chan model.ConfigFileChangeEvent) {
+	c.AddChangeListener(func(event model.ConfigFileChangeEvent) { ch <- event })
+}
+
+func (c *configFile) AddChangeListener(cb model.OnConfigFileChange) {
+	c.fake.mu.Lock()
+	defer c.fake.mu.Unlock()
+	c.fake.listeners[c.key] = append(slices.Clip(c.fake.listeners[c.key]), cb)
+}
+
+// limitClient is the LimitClient of a Fake
+type limitClient struct {
+	fake *Fake
+}
+
+// GetQuota grants the request unless its service is limited with SetLimited
+func (l limitClient) GetQuota(req api.QuotaRequest) (api.QuotaFuture, error) {
+	if err := l.fake.call(OpGetQuota); err != nil {
+		return nil, err
+	}
+	var key serviceKey
+	if r, ok := req.(*model.QuotaRequestImpl); ok {
+		key = serviceKey{r.GetNamespace(), r.GetService()}
+	}
+	l.fake.mu.Lock()
+	limited := l.fake.limited[key]
+	l.fake.mu.Unlock()
+	resp := &model.QuotaResponse{Code: model.QuotaResultOk}
+	if limited {
+		resp = &model.QuotaResponse{Code: model.QuotaResultLimited, Info: "limited by polaristest"}
+	}
+	done := make(chan struct{})
+	close(done)
+	return quotaFuture{resp: resp, done: done}, nil
+}
+
+// quotaFuture is a QuotaFuture that is already done
+type quotaFuture struct {
+	resp *model.QuotaResponse
+	done chan struct{}
+}
+
+func (q quotaFuture) Done() <-chan struct{}                { return q.done }
+func (q quotaFuture) Get() *model.QuotaResponse            { return q.resp }
+func (q quotaFuture) GetImmediately() *model.QuotaResponse { return q.resp }
+func (q quotaFuture) Release()                             {}
+
+// instance is a model.Instance of a Fake
+type instance struct {
+	namespace string
+	service   string
+	host      string
+	port      uint32
+	protocol  string
+	version   string
+	weight    int
+	healthy   bool
+	isolated  bool
+	metadata  map[string]string
+}
+
+func (i *instance) address() string { return address(i.host, int(i.port)) }
+
+func (i *instance) GetInstanceKey() model.InstanceKey {
+	return model.InstanceKey{
+		ServiceKey: model.ServiceKey{Namespace: i.namespace, Service: i.service},
+		Host:       i.host,
+		Port:       int(i.port),
+	}
+}
+
+func (i *instance) GetNamespace() string                                { return i.namespace }
+func (i *instance) GetService() string                                  { return i.service }
+func (i *instance) GetId() string                                       { return i.namespace + "/" + i.service + "/" + i.address() }
+func (i *instance) GetHost() string                                     { return i.host }
+func (i *instance) GetPort() uint32                                     { return i.port }
+func (i *instance) GetVpcId() string                                    { return "" }
+func (i *instance) GetProtocol() string                                 { return i.protocol }
+func (i *instance) GetVersion() string                                  { return i.version }
+func (i *instance) GetWeight() int                                      { return i.weight }
+func (i *instance) GetPriority() uint32                                 { return 0 }
+func (i *instance) GetMetadata() map[string]string                      { return i.metadata }
+func (i *instance) GetLogicSet() string                                 { return "" }
+func (i *instance) IsHealthy() bool                                     { return i.healthy }
+func (i *instance) IsIsolated() bool                                    { return i.isolated }
+func (i *instance) IsEnableHealthCheck() bool                           { return false }
+func (i *instance) GetRegion() string                                   { return "" }
+func (i *instance) GetZone() string                                     { return "" }
+func (i *instance) GetIDC() string                                      { return "" }
+func (i *instance) GetCampus() string                                   { return "" }
+func (i *instance) GetRevision() string                                 { return "" }
+func (i *instance) GetCircuitBreakerStatus() model.CircuitBreakerStatus { return nil }
@@ -0,0 +1,350 @@
+// Package polaristest provides an in-memory Polaris backend for testing code that uses the
+// Polaris plugin, without a Polaris server.
+//
+// A Fake implements the plugin's SDK client interfaces. Instances are registered either
+// directly with AddInstance or by the plugin's registrar, configs are published with
+// PublishConfig, and errors and latency are injected per operation:
+//
+//	fake := polaristest.New()
+//	fake.AddInstance("default", "orders", "10.0.0.1", 9000, nil)
+//	fake.PublishConfig("default", "orders", "app.yaml", "workers: 4")
+//	plugin := polaris.NewPolarisControlPlane(fake.Options()...)
+package polaristest
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	polaris "github.com/go-lynx/lynx-polaris"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Operation identifies a call to the fake, for error and latency injection
+type Operation string
+
+const (
+	// OpGetInstances is GetInstances, GetOneInstance and GetAllInstances
+	OpGetInstances Operation = "get_instances"
+	// OpWatchService is WatchService
+	OpWatchService Operation = "watch_service"
+	// OpReportCallResult is UpdateServiceCallResult
+	OpReportCallResult Operation = "report_call_result"
+	// OpRegister is Register and RegisterInstance
+	OpRegister Operation = "register"
+	// OpDeregister is Deregister
+	OpDeregister Operation = "deregister"
+	// OpHeartbeat is Heartbeat
+	OpHeartbeat Operation = "heartbeat"
+	// OpGetConfigFile is GetConfigFile
+	OpGetConfigFile Operation = "get_config_file"
+	// OpGetQuota is GetQuota
+	OpGetQuota Operation = "get_quota"
+)
+
+// watchBuffer is the number of instance events buffered per watch. Events are dropped when
+// a watch falls this far behind.
+const watchBuffer = 64
+
+// serviceKey identifies a service
+type serviceKey struct {
+	namespace string
+	service   string
+}
+
+// configKey identifies a config file
+type configKey struct {
+	namespace string
+	group     string
+	fileName  string
+}
+
+// fault is the error and latency injected into an operation. The error fails the given
+// fraction of the calls.
+type fault struct {
+	err     error
+	rate    float64
+	latency time.Duration
+}
+
+// Fake is an in-memory Polaris backend. It is safe for concurrent use.
+type Fake struct {
+	mu        sync.Mutex
+	instances map[serviceKey]map[string]*instance
+	watches   map[serviceKey][]chan model.SubScribeEvent
+	configs   map[configKey]string
+	listeners map[configKey][]func(model.ConfigFileChangeEvent)
+	limited   map[serviceKey]bool
+	faults    map[Operation]fault
+	calls     map[Operation]int
+	rnd       *rand.Rand
+}
+
+// New creates an empty fake backend that grants every quota
+func New() *Fake {
+	return &Fake{
+		instances: make(map[serviceKey]map[string]*instance),
+		watches:   make(map[serviceKey][]chan model.SubScribeEvent),
+		configs:   make(map[configKey]string),
+		listeners: make(map[configKey][]func(model.ConfigFileChangeEvent)),
+		limited:   make(map[serviceKey]bool),
+		faults:    make(map[Operation]fault),
+		calls:     make(map[Operation]int),
+		rnd:       rand.New(rand.NewSource(1)),
+	}
+}
+
+// Options returns the options of polaris.NewPolarisControlPlane that make the plugin use
+// the fake for discovery, registration, configs and rate limiting
+func (f *Fake) Options() []polaris.Option {
+	return []polaris.Option{
+		polaris.WithConsumerClient(f.Consumer()),
+		polaris.WithProviderClient(f.Provider()),
+		polaris.WithConfigClient(f.Config()),
+		polaris.WithLimitClient(f.Limit()),
+	}
+}
+
+// SetError makes every later call of op fail with err. A nil err clears it.
+func (f *Fake) SetError(op Operation, err error) {
+	f.SetErrorRate(op, err, 1)
+}
+
+// SetErrorRate makes the fraction rate (0..1) of the later calls of op fail with err. The
+// failing calls are drawn from a source seeded with Seed, so that runs are reproducible.
+func (f *Fake) SetErrorRate(op Operation, err error, rate float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ft := f.faults[op]
+	ft.err, ft.rate = err, rate
+	f.faults[op] = ft
+}
+
+// Seed reseeds the source drawing the calls failed by SetErrorRate
+func (f *Fake) Seed(seed int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rnd.Seed(seed)
+}
+
+// SetLatency delays every later call of op by latency. Zero clears it.
+func (f *Fake) SetLatency(op Operation, latency time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ft := f.faults[op]
+	ft.latency = latency
+	f.faults[op] = ft
+}
+
+// Calls returns the number of calls of op, including failed ones
+func (f *Fake) Calls(op Operation) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[op]
+}
+
+// call counts a call of op and applies its latency, returning its injected error
+func (f *Fake) call(op Operation) error {
+	f.mu.Lock()
+	f.calls[op]++
+	ft := f.faults[op]
+	failed := ft.err != nil && (ft.rate >= 1 || f.rnd.Float64() < ft.rate)
+	f.mu.Unlock()
+	if ft.latency > 0 {
+		time.Sleep(ft.latency)
+	}
+	if failed {
+		return fmt.Errorf("polaristest: %s: %w", op, ft.err)
+	}
+	return nil
+}
+
+// AddInstance registers a healthy instance of service in namespace, replacing the one at the
+// same address, and returns it
+func (f *Fake) AddInstance(namespace, service, host string, port int, metadata map[string]string) model.Instance {
+	inst := &instance{
+		namespace: namespace,
+		service:   service,
+		host:      host,
+		port:      uint32(port),
+		weight:    conf.DefaultWeight,
+		healthy:   true,
+		metadata:  maps.Clone(metadata),
+	}
+	f.putInstance(inst)
+	return inst
+}
+
+// RemoveInstance deregisters the instance of service at host:port and reports whether
+// there was one
+func (f *Fake) RemoveInstance(namespace, service, host string, port int) bool {
+	key := serviceKey{namespace, service}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	inst, ok := f.instances[key][address(host, port)]
+	if !ok {
+		return false
+	}
+	delete(f.instances[key], inst.address())
+	f.notifyLocked(key, &model.InstanceEvent{DeleteEvent: &model.InstanceDeleteEvent{Instances: []model.Instance{inst}}})
+	return true
+}
+
+// SetInstanceHealthy sets the health of the instance of service at host:port and reports
+// whether there is one
+func (f *Fake) SetInstanceHealthy(namespace, service, host string, port int, healthy bool) bool {
+	key := serviceKey{namespace, service}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	before, ok := f.instances[key][address(host, port)]
+	if !ok {
+		return false
+	}
+	after := *before
+	after.healthy = healthy
+	f.instances[key][after.address()] = &after
+	f.notifyLocked(key, &model.InstanceEvent{UpdateEvent: &model.InstanceUpdateEvent{
+		UpdateList: []model.OneInstanceUpdate{{Before: before, After: &after}},
+	}})
+	return true
+}
+
+// Instances returns every instance of service, including unhealthy and isolated ones,
+// ordered by address
+func (f *Fake) Instances(namespace, service string) []model.Instance {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.instancesLocked(serviceKey{namespace, service}, nil, true)
+}
+
+// putInstance stores inst and notifies the watches of its service
+func (f *Fake) putInstance(inst *instance) (existed bool) {
+	key := serviceKey{inst.namespace, inst.service}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.instances[key] == nil {
+		f.instances[key] = make(map[string]*instance)
+	}
+	before, existed := f.instances[key][inst.address()]
+	f.instances[key][inst.address()] = inst
+	if existed {
+		f.notifyLocked(key, &model.InstanceEvent{UpdateEvent: &model.InstanceUpdateEvent{
+			UpdateList: []model.OneInstanceUpdate{{Before: before, After: inst}},
+		}})
+	} else {
+		f.notifyLocked(key, &model.InstanceEvent{AddEvent: &model.InstanceAddEvent{Instances: []model.Instance{inst}}})
+	}
+	return existed
+}
+
+// instancesLocked returns the instances of key matching metadata, ordered by address. Unless
+// all is set, unhealthy and isolated instances are left out.
+func (f *Fake) instancesLocked(key serviceKey, metadata map[string]string, all bool) []model.Instance {
+	addresses := slices.Sorted(maps.Keys(f.instances[key]))
+	instances := make([]model.Instance, 0, len(addresses))
+	for _, addr := range addresses {
+		inst := f.instances[key][addr]
+		if !all && (!inst.healthy || inst.isolated) {
+			continue
+		}
+		if !matches(inst.metadata, metadata) {
+			continue
+		}
+		instances = append(instances, inst)
+	}
+	return instances
+}
+
+// notifyLocked sends event to the watches of key, dropping it for watches that are full
+func (f *Fake) notifyLocked(key serviceKey, event model.SubScribeEvent) {
+	for _, ch := range f.watches[key] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// PublishConfig creates or replaces the content of a config file and notifies its listeners
+func (f *Fake) PublishConfig(namespace, group, fileName, content string) {
+	key := configKey{namespace, group, fileName}
+	f.mu.Lock()
+	previous, existed := f.configs[key]
+	f.configs[key] = content
+	listeners := slices.Clone(f.listeners[key])
+	f.mu.Unlock()
+
+	changeType := model.Added
+	if existed {
+		if previous == content {
+			return
+		}
+		changeType = model.Modified
+	}
+	notifyConfig(listeners, model.ConfigFileChangeEvent{
+		ConfigFileMetadata: &configFile{key: key},
+		OldValue:           previous,
+		NewValue:           content,
+		ChangeType:         changeType,
+	})
+}
+
+// DeleteConfig deletes a config file, notifies its listeners and reports whether it existed
+func (f *Fake) DeleteConfig(namespace, group, fileName string) bool {
+	key := configKey{namespace, group, fileName}
+	f.mu.Lock()
+	previous, existed := f.configs[key]
+	delete(f.configs, key)
+	listeners := slices.Clone(f.listeners[key])
+	f.mu.Unlock()
+	if !existed {
+		return false
+	}
+	notifyConfig(listeners, model.ConfigFileChangeEvent{
+		ConfigFileMetadata: &configFile{key: key},
+		OldValue:           previous,
+		ChangeType:         model.Deleted,
+	})
+	return true
+}
+
+// ConfigContent returns the content of a config file and whether it exists
+func (f *Fake) ConfigContent(namespace, group, fileName string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	content, ok := f.configs[configKey{namespace, group, fileName}]
+	return content, ok
+}
+
+func notifyConfig(listeners []func(model.ConfigFileChangeEvent), event model.ConfigFileChangeEvent) {
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// SetLimited makes the quota requests of service be limited, or granted again
+func (f *Fake) SetLimited(namespace, service string, limited bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.limited[serviceKey{namespace, service}] = limited
+}
+
+// matches reports whether metadata contains every entry of filter
+func matches(metadata, filter map[string]string) bool {
+	for k, v := range filter {
+		if metadata[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func address(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
@@ -0,0 +1,150 @@
+package polaristest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	polaris "github.com/go-lynx/lynx-polaris"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFake_Registration(t *testing.T) {
+	fake := New()
+	registrar := polaris.NewPolarisRegistrar(fake.Provider(), "default")
+	discovery := polaris.NewPolarisDiscovery(fake.Consumer(), "default", nil)
+	watch, err := fake.Consumer().WatchService(&api.WatchServiceRequest{WatchServiceRequest: model.WatchServiceRequest{
+		Key: model.ServiceKey{Namespace: "default", Service: "orders"},
+	}})
+	require.NoError(t, err)
+
+	svc := &registry.ServiceInstance{Name: "orders", Version: "v1", Endpoints: []string{"grpc://10.0.0.1:9000"}}
+	require.NoError(t, registrar.Register(context.Background(), svc))
+	instances, err := discovery.GetService(context.Background(), "orders")
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, []string{"grpc://10.0.0.1:9000"}, instances[0].Endpoints)
+	event := (<-watch.EventChannel).(*model.InstanceEvent)
+	require.NotNil(t, event.AddEvent)
+
+	require.NoError(t, registrar.SetIsolated(context.Background(), true))
+	assert.True(t, fake.Instances("default", "orders")[0].IsIsolated())
+	resp, err := fake.Consumer().GetInstances(&api.GetInstancesRequest{GetInstancesRequest: model.GetInstancesRequest{
+		Namespace: "default", Service: "orders",
+	}})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Instances, "isolated instances are filtered")
+
+	require.NoError(t, registrar.Deregister(context.Background(), svc))
+	assert.Empty(t, fake.Instances("default", "orders"))
+	assert.Equal(t, 2, fake.Calls(OpRegister))
+}
+
+func TestFake_ServiceWatcher(t *testing.T) {
+	fake := New()
+	fake.AddInstance("default", "orders", "10.0.0.1", 9000, map[string]string{"zone": "a"})
+	watcher := polaris.NewServiceWatcher(fake.Consumer(), "orders", "default")
+	var changes [][]model.Instance
+	watcher.SetOnInstancesChanged(func(instances []model.Instance) { changes = append(changes, instances) })
+
+	watcher.Refresh()
+	fake.AddInstance("default", "orders", "10.0.0.2", 9000, nil)
+	watcher.Refresh()
+	assert.True(t, fake.SetInstanceHealthy("default", "orders", "10.0.0.1", 9000, false))
+	watcher.Refresh()
+	require.Len(t, changes, 3)
+	assert.Len(t, changes[1], 2)
+	require.Len(t, changes[2], 1)
+	assert.Equal(t, "10.0.0.2", changes[2][0].GetHost())
+}
+
+func TestFake_Config(t *testing.T) {
+	fake := New()
+	watcher := polaris.NewConfigWatcher(fake.Config(), "app.yaml", "orders", "default")
+	var contents []string
+	watcher.SetOnConfigChanged(func(file model.ConfigFile) { contents = append(contents, file.GetContent()) })
+
+	file, err := fake.Config().GetConfigFile("default", "orders", "app.yaml")
+	require.NoError(t, err)
+	assert.False(t, file.HasContent())
+	var events []model.ConfigFileChangeEvent
+	file.AddChangeListener(func(event model.ConfigFileChangeEvent) { events = append(events, event) })
+
+	fake.PublishConfig("default", "orders", "app.yaml", "workers: 4")
+	watcher.Refresh()
+	fake.PublishConfig("default", "orders", "app.yaml", "workers: 8")
+	watcher.Refresh()
+	assert.Equal(t, []string{"workers: 4", "workers: 8"}, contents)
+	assert.True(t, fake.DeleteConfig("default", "orders", "app.yaml"))
+
+	require.Len(t, events, 3)
+	assert.Equal(t, model.Added, events[0].ChangeType)
+	assert.Equal(t, model.Modified, events[1].ChangeType)
+	assert.Equal(t, "workers: 4", events[1].OldValue)
+	assert.Equal(t, model.Deleted, events[2].ChangeType)
+}
+
+func TestFake_Quota(t *testing.T) {
+	fake := New()
+	req := api.NewQuotaRequest()
+	req.SetNamespace("default")
+	req.SetService("orders")
+
+	future, err := fake.Limit().GetQuota(req)
+	require.NoError(t, err)
+	assert.Equal(t, model.QuotaResultOk, future.Get().Code)
+
+	fake.SetLimited("default", "orders", true)
+	future, err = fake.Limit().GetQuota(req)
+	require.NoError(t, err)
+	assert.Equal(t, model.QuotaResultLimited, future.GetImmediately().Code)
+}
+
+func TestFake_Faults(t *testing.T) {
+	fake := New()
+	unavailable := errors.New("unavailable")
+	fake.SetError(OpRegister, unavailable)
+	fake.SetLatency(OpGetConfigFile, 20*time.Millisecond)
+
+	registrar := polaris.NewPolarisRegistrar(fake.Provider(), "default")
+	err := registrar.Register(context.Background(), &registry.ServiceInstance{Name: "orders", Endpoints: []string{"grpc://10.0.0.1:9000"}})
+	assert.ErrorIs(t, err, unavailable)
+	assert.Empty(t, fake.Instances("default", "orders"))
+
+	start := time.Now()
+	_, err = fake.Config().GetConfigFile("default", "orders", "app.yaml")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	fake.SetError(OpRegister, nil)
+	require.NoError(t, registrar.Register(context.Background(), &registry.ServiceInstance{Name: "orders", Endpoints: []string{"grpc://10.0.0.1:9000"}}))
+	assert.Equal(t, 2, fake.Calls(OpRegister))
+	assert.Error(t, fake.Provider().Heartbeat(&api.InstanceHeartbeatRequest{InstanceHeartbeatRequest: model.InstanceHeartbeatRequest{
+		Namespace: "default", Service: "payments", Host: "10.0.0.1", Port: 9000,
+	}}))
+}
+
+func TestFake_ErrorRate(t *testing.T) {
+	failures := func(seed int64) []bool {
+		fake := New()
+		fake.Seed(seed)
+		fake.SetErrorRate(OpGetQuota, errors.New("unavailable"), 0.5)
+		var failed []bool
+		for range 100 {
+			_, err := fake.Config().GetConfigFile("default", "orders", "app.yaml")
+			require.NoError(t, err, "other operations are not failed")
+			_, err = fake.Limit().GetQuota(api.NewQuotaRequest())
+			failed = append(failed, err != nil)
+		}
+		return failed
+	}
+	first := failures(7)
+	assert.Equal(t, first, failures(7), "the same seed fails the same calls")
+	assert.Contains(t, first, true)
+	assert.Contains(t, first, false)
+}
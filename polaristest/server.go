@@ -0,0 +1,236 @@
+//go:build integration
+
+package polaristest
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	polaris "github.com/go-lynx/lynx-polaris"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// DefaultServerImage is the Polaris server image started by StartServer. The
+// POLARIS_TEST_IMAGE environment variable or WithImage overrides it.
+const DefaultServerImage = "polarismesh/polaris-standalone:v1.18.1"
+
+// DefaultServerToken is the token of the polaris administrator created by the standalone
+// image, used for the OpenAPI calls of the plugin
+const DefaultServerToken = "nu/0WRA4EqSR1FagrjRj0fZwPXuGlMpX+zCuWu4uMqy8xr1vRjisSbA25aAC3mtU8MeeRsKhQiDAynUR09I="
+
+// Ports of the Polaris server inside its container
+const (
+	containerHTTPPort     = "8090/tcp"
+	containerDiscoverPort = "8091/tcp"
+	containerConfigPort   = "8093/tcp"
+)
+
+// defaultStartTimeout is how long StartServer waits for the server to answer
+const defaultStartTimeout = 2 * time.Minute
+
+// Server is a Polaris server running in a container started by StartServer
+type Server struct {
+	// ContainerID is the ID of the Docker container
+	ContainerID string
+	// Host is the host the server ports are published on
+	Host string
+	// HTTPPort is the published port of the HTTP OpenAPI
+	HTTPPort int
+	// DiscoverPort is the published port of the naming gRPC API
+	DiscoverPort int
+	// ConfigPort is the published port of the config gRPC API
+	ConfigPort int
+	// Token authenticates the OpenAPI calls
+	Token string
+}
+
+// serverOptions are the settings of StartServer
+type serverOptions struct {
+	image   string
+	token   string
+	timeout time.Duration
+}
+
+// ServerOption configures StartServer
+type ServerOption func(*serverOptions)
+
+// WithImage starts image instead of DefaultServerImage
+func WithImage(image string) ServerOption {
+	return func(o *serverOptions) { o.image = image }
+}
+
+// WithServerToken authenticates the OpenAPI calls with token instead of DefaultServerToken,
+// for images with another administrator. An empty token is for servers without auth.
+func WithServerToken(token string) ServerOption {
+	return func(o *serverOptions) { o.token = token }
+}
+
+// WithStartTimeout sets how long StartServer waits for the server to answer
+func WithStartTimeout(timeout time.Duration) ServerOption {
+	return func(o *serverOptions) { o.timeout = timeout }
+}
+
+// StartServer starts a Polaris server container with testcontainers, waits until it
+// answers and removes it when t ends. t is skipped in short mode and when Docker is not
+// available.
+func StartServer(t testing.TB, opts ...ServerOption) *Server {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("polaristest: Polaris server tests are skipped in short mode")
+	}
+	options := serverOptions{image: DefaultServerImage, token: DefaultServerToken, timeout: defaultStartTimeout}
+	if image := os.Getenv("POLARIS_TEST_IMAGE"); image != "" {
+		options.image = image
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	ctx := context.Background()
+	if err := dockerHealth(ctx); err != nil {
+		t.Skipf("polaristest: docker is not available: %v", err)
+	}
+
+	// The server starts its HTTP OpenAPI after its gRPC APIs. Docker accepts connections on
+	// published ports before the server listens, so a TCP connection alone does not tell it
+	// is up.
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        options.image,
+			ExposedPorts: []string{containerHTTPPort, containerDiscoverPort, containerConfigPort},
+			WaitingFor:   wait.ForHTTP("/").WithPort(containerHTTPPort).WithStartupTimeout(options.timeout),
+		},
+		Started: true,
+	})
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("polaristest: failed to start %s: %v", options.image, err)
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			logContainer(t, container)
+		}
+	})
+
+	server := &Server{ContainerID: container.GetContainerID(), Token: options.token}
+	if server.Host, err = container.Host(ctx); err != nil {
+		t.Fatalf("polaristest: failed to find the host of the Polaris server: %v", err)
+	}
+	httpPort, err := container.MappedPort(ctx, containerHTTPPort)
+	if err != nil {
+		t.Fatalf("polaristest: failed to find the published port of %s: %v", containerHTTPPort, err)
+	}
+	discoverPort, err := container.MappedPort(ctx, containerDiscoverPort)
+	if err != nil {
+		t.Fatalf("polaristest: failed to find the published port of %s: %v", containerDiscoverPort, err)
+	}
+	configPort, err := container.MappedPort(ctx, containerConfigPort)
+	if err != nil {
+		t.Fatalf("polaristest: failed to find the published port of %s: %v", containerConfigPort, err)
+	}
+	server.HTTPPort, server.DiscoverPort, server.ConfigPort = httpPort.Int(), discoverPort.Int(), configPort.Int()
+	return server
+}
+
+// dockerHealth reports whether the Docker daemon testcontainers uses answers
+func dockerHealth(ctx context.Context) error {
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+	return provider.Health(ctx)
+}
+
+// logContainer logs the last output of the container of a failed test
+func logContainer(t testing.TB, container testcontainers.Container) {
+	logs, err := container.Logs(context.Background())
+	if err != nil {
+		return
+	}
+	defer logs.Close()
+	out, err := io.ReadAll(logs)
+	if err != nil {
+		return
+	}
+	const tail = 8 << 10
+	if len(out) > tail {
+		out = out[len(out)-tail:]
+	}
+	t.Logf("polaristest: last Polaris server logs:\n%s", out)
+}
+
+// HTTPAddress returns the base URL of the HTTP OpenAPI
+func (s *Server) HTTPAddress() string {
+	return "http://" + net.JoinHostPort(s.Host, strconv.Itoa(s.HTTPPort))
+}
+
+// DiscoverAddress returns the host:port of the naming gRPC API
+func (s *Server) DiscoverAddress() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.DiscoverPort))
+}
+
+// ConfigAddress returns the host:port of the config gRPC API
+func (s *Server) ConfigAddress() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.ConfigPort))
+}
+
+// Config returns a plugin configuration for namespace pointing at the server
+func (s *Server) Config(namespace string) *conf.Polaris {
+	return &conf.Polaris{
+		Namespace: namespace,
+		Token:     s.Token,
+		Weight:    conf.DefaultWeight,
+		ServerBootstrap: &conf.ServerBootstrap{
+			Addresses:       []string{s.DiscoverAddress()},
+			ConfigAddresses: []string{s.ConfigAddress()},
+		},
+		ConfigAdmin: &conf.ConfigAdmin{Address: s.HTTPAddress()},
+	}
+}
+
+// SDKContext returns a polaris-go SDK context connected to the server and destroyed when t
+// ends, for building registrars, discoveries and watchers against it
+func (s *Server) SDKContext(t testing.TB) api.SDKContext {
+	t.Helper()
+	configuration := api.NewConfiguration()
+	configuration.GetGlobal().GetServerConnector().SetAddresses([]string{s.DiscoverAddress()})
+	configuration.GetConfigFile().GetConfigConnectorConfig().SetAddresses([]string{s.ConfigAddress()})
+	sdk, err := api.InitContextByConfig(configuration)
+	if err != nil {
+		t.Fatalf("polaristest: failed to connect to the Polaris server: %v", err)
+	}
+	t.Cleanup(sdk.Destroy)
+	return sdk
+}
+
+// Plugin returns a plugin started with cfg on the polaris-go clients of a new SDK context
+// connected to the server, see polaris.NewPluginWithClients, and stopped when t ends. A nil
+// cfg is the Config of the default namespace.
+func (s *Server) Plugin(t testing.TB, cfg *conf.Polaris) *polaris.PlugPolaris {
+	t.Helper()
+	if cfg == nil {
+		cfg = s.Config("default")
+	}
+	sdk := s.SDKContext(t)
+	plugin, err := polaris.NewPluginWithClients(cfg,
+		polaris.WithConsumerClient(api.NewConsumerAPIByContext(sdk)),
+		polaris.WithProviderClient(api.NewProviderAPIByContext(sdk)),
+		polaris.WithConfigClient(api.NewConfigFileAPIBySDKContext(sdk)),
+		polaris.WithLimitClient(api.NewLimitAPIByContext(sdk)),
+	)
+	if err != nil {
+		t.Fatalf("polaristest: failed to start the plugin: %v", err)
+	}
+	t.Cleanup(func() { _ = plugin.CleanupTasks() })
+	return plugin
+}
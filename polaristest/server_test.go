@@ -0,0 +1,98 @@
+//go:build integration
+
+package polaristest
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	polaris "github.com/go-lynx/lynx-polaris"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_RegistrationAndDiscovery(t *testing.T) {
+	server := StartServer(t)
+	sdk := server.SDKContext(t)
+	registrar := polaris.NewPolarisRegistrar(api.NewProviderAPIByContext(sdk), "default")
+	discovery := polaris.NewPolarisDiscovery(api.NewConsumerAPIByContext(sdk), "default", server.Config("default"))
+
+	svc := &registry.ServiceInstance{Name: "polaristest-orders", Version: "v1", Endpoints: []string{"grpc://127.0.0.1:9000"}}
+	ctx := context.Background()
+	require.NoError(t, registrar.Register(ctx, svc))
+	require.Eventually(t, func() bool {
+		instances, err := discovery.GetService(ctx, svc.Name)
+		return err == nil && len(instances) == 1
+	}, 30*time.Second, time.Second)
+
+	require.NoError(t, registrar.Deregister(ctx, svc))
+	require.Eventually(t, func() bool {
+		instances, err := discovery.GetService(ctx, svc.Name)
+		return err == nil && len(instances) == 0
+	}, 30*time.Second, time.Second)
+}
+
+func TestServer_MissingConfig(t *testing.T) {
+	server := StartServer(t)
+	file, err := api.NewConfigFileAPIBySDKContext(server.SDKContext(t)).GetConfigFile("default", "polaristest", "missing.yaml")
+	require.NoError(t, err)
+	assert.False(t, file.HasContent())
+}
+
+func TestServer_ConfigPublishAndWatch(t *testing.T) {
+	server := StartServer(t)
+	plugin := server.Plugin(t, nil)
+	ctx := context.Background()
+	require.NoError(t, plugin.PublishConfig(ctx, "app.yaml", "polaristest", "workers: 4\n"))
+
+	watcher := polaris.NewConfigWatcher(api.NewConfigFileAPIBySDKContext(server.SDKContext(t)), "app.yaml", "polaristest", "default")
+	var content atomic.Value
+	watcher.SetOnConfigChanged(func(file model.ConfigFile) { content.Store(file.GetContent()) })
+	watcher.Start()
+	t.Cleanup(watcher.Stop)
+	require.Eventually(t, func() bool { return content.Load() == "workers: 4\n" }, 30*time.Second, 100*time.Millisecond)
+
+	require.NoError(t, plugin.PublishConfig(ctx, "app.yaml", "polaristest", "workers: 8\n"))
+	require.Eventually(t, func() bool { return content.Load() == "workers: 8\n" }, 30*time.Second, 100*time.Millisecond)
+
+	require.NoError(t, plugin.DeleteConfig(ctx, "app.yaml", "polaristest"))
+}
+
+func TestServer_Plugin(t *testing.T) {
+	server := StartServer(t)
+	plugin := server.Plugin(t, nil)
+	ctx := context.Background()
+
+	svc := &registry.ServiceInstance{Name: "polaristest-payments", Version: "v1", Endpoints: []string{"grpc://127.0.0.1:9100"}}
+	registrar := plugin.NewServiceRegistry()
+	require.NoError(t, registrar.Register(ctx, svc))
+	discovery := plugin.NewServiceDiscovery()
+	require.Eventually(t, func() bool {
+		instances, err := discovery.GetService(ctx, svc.Name)
+		return err == nil && len(instances) == 1
+	}, 30*time.Second, time.Second)
+
+	require.NoError(t, plugin.PublishConfig(ctx, "app.yaml", "polaristest-payments", "workers: 4\n"))
+	require.Eventually(t, func() bool {
+		content, err := plugin.GetConfigValue("app.yaml", "polaristest-payments")
+		return err == nil && content == "workers: 4\n"
+	}, 30*time.Second, time.Second)
+	var reloaded atomic.Value
+	require.NoError(t, plugin.RegisterReloadable("app.yaml", "polaristest-payments", func(content string) error {
+		reloaded.Store(content)
+		return nil
+	}))
+	require.NoError(t, plugin.PublishConfig(ctx, "app.yaml", "polaristest-payments", "workers: 8\n"))
+	require.Eventually(t, func() bool { return reloaded.Load() == "workers: 8\n" }, 30*time.Second, 100*time.Millisecond)
+
+	require.NoError(t, registrar.Deregister(ctx, svc))
+	require.Eventually(t, func() bool {
+		instances, err := discovery.GetService(ctx, svc.Name)
+		return err == nil && len(instances) == 0
+	}, 30*time.Second, time.Second)
+}
@@ -0,0 +1,132 @@
+package polaris
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// instancePriorityMetadataKey is the metadata key carrying the Polaris priority of an
+// instance. PolarisRegistrar reads it to set the registered priority, and discovered
+// instances expose it so that node filters can see it.
+const instancePriorityMetadataKey = "priority"
+
+// InstanceOption customizes GetServiceInstances.
+type InstanceOption func(*instanceOptions)
+
+type instanceOptions struct {
+	preferPriority bool
+	namespaces     []string
+}
+
+// WithPriorityPreference makes GetServiceInstances return only the healthy instances of
+// the highest priority (lowest Polaris priority value) that has any healthy instance, so
+// lower priorities only receive traffic when every preferred instance is unhealthy.
+func WithPriorityPreference() InstanceOption {
+	return func(o *instanceOptions) {
+		o.preferPriority = true
+	}
+}
+
+// preferredInstances returns the healthy instances of the best priority present among
+// instances. When no instance is healthy, instances are returned unchanged.
+func preferredInstances(instances []model.Instance) []model.Instance {
+	healthy := healthyInstances(instances)
+	if len(healthy) == 0 {
+		return instances
+	}
+	best := healthy[0].GetPriority()
+	for _, inst := range healthy[1:] {
+		if inst.GetPriority() < best {
+			best = inst.GetPriority()
+		}
+	}
+	preferred := make([]model.Instance, 0, len(healthy))
+	for _, inst := range healthy {
+		if inst.GetPriority() == best {
+			preferred = append(preferred, inst)
+		}
+	}
+	return preferred
+}
+
+// priorityNodeFilter wraps next so that only the nodes of the best priority it returns are
+// kept. Nodes without a priority are treated as the highest priority, matching the Polaris
+// default of 0.
+func priorityNodeFilter(next selector.NodeFilter) selector.NodeFilter {
+	return func(ctx context.Context, nodes []selector.Node) []selector.Node {
+		if next != nil {
+			nodes = next(ctx, nodes)
+		}
+		if len(nodes) < 2 {
+			return nodes
+		}
+		best := nodePriority(nodes[0])
+		mixed := false
+		for _, node := range nodes[1:] {
+			priority := nodePriority(node)
+			if priority != best {
+				mixed = true
+			}
+			if priority < best {
+				best = priority
+			}
+		}
+		if !mixed {
+			return nodes
+		}
+		preferred := make([]selector.Node, 0, len(nodes))
+		for _, node := range nodes {
+			if nodePriority(node) == best {
+				preferred = append(preferred, node)
+			}
+		}
+		return preferred
+	}
+}
+
+// nodePriority reads the Polaris priority of a node from its metadata.
+func nodePriority(node selector.Node) uint32 {
+	if node == nil {
+		return 0
+	}
+	priority, err := strconv.ParseUint(node.Metadata()[instancePriorityMetadataKey], 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(priority)
+}
+
+// instanceMetadata returns the metadata of instance, adding its priority when it is not the
+// default so that it survives conversion into Kratos registry instances.
+func instanceMetadata(instance model.Instance) map[string]string {
+	metadata := instance.GetMetadata()
+	priority := instance.GetPriority()
+	if priority == 0 {
+		return metadata
+	}
+	withPriority := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		withPriority[k] = v
+	}
+	withPriority[instancePriorityMetadataKey] = strconv.FormatUint(uint64(priority), 10)
+	return withPriority
+}
+
+// registrationPriority parses the priority to register from instance metadata. It returns
+// nil when no priority is set, leaving the Polaris default in place.
+func registrationPriority(metadata map[string]string) (*int, error) {
+	value, ok := metadata[instancePriorityMetadataKey]
+	if !ok || value == "" {
+		return nil, nil
+	}
+	priority, err := strconv.Atoi(value)
+	if err != nil || priority < model.MinPriority || priority > model.MaxPriority {
+		return nil, NewConfigError(fmt.Sprintf("instance priority %q must be an integer in [%d, %d]",
+			value, model.MinPriority, model.MaxPriority))
+	}
+	return &priority, nil
+}
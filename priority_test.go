@@ -0,0 +1,109 @@
+package polaris
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// priorityInstance is a static instance with a priority and health status.
+type priorityInstance struct {
+	model.Instance
+	priority uint32
+	healthy  bool
+}
+
+func (i priorityInstance) GetPriority() uint32 { return i.priority }
+func (i priorityInstance) IsHealthy() bool     { return i.healthy }
+
+func newPriorityInstance(host string, priority uint32, healthy bool) model.Instance {
+	inst := NewStaticInstance("default", "svc", &conf.FallbackInstance{Host: host, Port: 8080})
+	return priorityInstance{Instance: inst, priority: priority, healthy: healthy}
+}
+
+func TestPreferredInstances(t *testing.T) {
+	instances := []model.Instance{
+		newPriorityInstance("10.0.0.1", 0, true),
+		newPriorityInstance("10.0.0.2", 0, true),
+		newPriorityInstance("10.0.1.1", 1, true),
+	}
+	preferred := preferredInstances(instances)
+	require.Len(t, preferred, 2)
+	assert.Equal(t, uint32(0), preferred[0].GetPriority())
+
+	// Spill to the next priority once the preferred set is unhealthy.
+	instances = []model.Instance{
+		newPriorityInstance("10.0.0.1", 0, false),
+		newPriorityInstance("10.0.1.1", 1, true),
+		newPriorityInstance("10.0.2.1", 2, true),
+	}
+	preferred = preferredInstances(instances)
+	require.Len(t, preferred, 1)
+	assert.Equal(t, "10.0.1.1", preferred[0].GetHost())
+
+	// Nothing healthy: leave the result unchanged.
+	unhealthy := []model.Instance{newPriorityInstance("10.0.0.1", 0, false)}
+	assert.Equal(t, unhealthy, preferredInstances(unhealthy))
+}
+
+func TestPriorityNodeFilter(t *testing.T) {
+	node := func(addr, priority string) selector.Node {
+		md := map[string]string{}
+		if priority != "" {
+			md[instancePriorityMetadataKey] = priority
+		}
+		return selector.NewNode("grpc", addr, &registry.ServiceInstance{Name: "svc", Metadata: md})
+	}
+	nodes := []selector.Node{node("10.0.1.1:80", "1"), node("10.0.2.1:80", "2"), node("10.0.1.2:80", "1")}
+
+	out := priorityNodeFilter(nil)(context.Background(), nodes)
+	require.Len(t, out, 2)
+	assert.Equal(t, "10.0.1.1:80", out[0].Address())
+	assert.Equal(t, "10.0.1.2:80", out[1].Address())
+
+	// Nodes without a priority count as the highest priority.
+	out = priorityNodeFilter(nil)(context.Background(), append(nodes, node("10.0.0.1:80", "")))
+	require.Len(t, out, 1)
+	assert.Equal(t, "10.0.0.1:80", out[0].Address())
+}
+
+func TestRegistrationPriority(t *testing.T) {
+	priority, err := registrationPriority(nil)
+	require.NoError(t, err)
+	assert.Nil(t, priority)
+
+	priority, err = registrationPriority(map[string]string{instancePriorityMetadataKey: "3"})
+	require.NoError(t, err)
+	require.NotNil(t, priority)
+	assert.Equal(t, 3, *priority)
+
+	_, err = registrationPriority(map[string]string{instancePriorityMetadataKey: "10"})
+	assert.Error(t, err)
+	_, err = registrationPriority(map[string]string{instancePriorityMetadataKey: "high"})
+	assert.Error(t, err)
+}
+
+func TestPolarisRegistrar_RegisterPriority(t *testing.T) {
+	provider := &fakeClients{}
+	reg := NewPolarisRegistrar(provider, "default")
+	info := &ServiceInfo{Service: "svc", Host: "10.0.0.1", Port: 8080, Priority: 2}
+
+	require.NoError(t, reg.Register(context.Background(), info.ServiceInstance()))
+	require.Len(t, provider.registered, 1)
+	require.NotNil(t, provider.registered[0].Priority)
+	assert.Equal(t, 2, *provider.registered[0].Priority)
+}
+
+func TestInstanceMetadata_Priority(t *testing.T) {
+	inst := newPriorityInstance("10.0.0.1", 0, true)
+	assert.NotContains(t, instanceMetadata(inst), instancePriorityMetadataKey)
+
+	inst = newPriorityInstance("10.0.0.1", 4, true)
+	assert.Equal(t, "4", toRegistryServiceInstance("svc", inst).Metadata[instancePriorityMetadataKey])
+}
@@ -0,0 +1,102 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// ProbeOption customizes the readiness probe.
+type ProbeOption func(*probeOptions)
+
+type probeOptions struct {
+	skipRegistration bool
+}
+
+// WithoutRegistrationCheck makes readiness independent of service registration, for
+// applications that only discover services or read config through the plugin.
+func WithoutRegistrationCheck() ProbeOption {
+	return func(o *probeOptions) {
+		o.skipRegistration = true
+	}
+}
+
+// CheckLiveness reports whether the plugin is initialized and its SDK context is alive.
+// It does not call Polaris, so an unreachable control plane does not fail it. With
+// lazy_init, it passes until the first use creates the SDK context.
+func (p *PlugPolaris) CheckLiveness() error {
+	if err := p.checkLifecycle(); err != nil {
+		return err
+	}
+	if p.lazilyUnconnected() {
+		return nil
+	}
+	p.mu.RLock()
+	sdk := p.sdk
+	p.mu.RUnlock()
+	if sdk == nil || sdk.IsDestroyed() {
+		return NewHealthCheckError("Polaris SDK context is not alive")
+	}
+	return nil
+}
+
+// CheckReadiness runs CheckHealth, which probes the Polaris control plane and requires the
+// required config files to be loaded, and then requires the service to be registered.
+func (p *PlugPolaris) CheckReadiness(ctx context.Context, opts ...ProbeOption) error {
+	var options probeOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	if err := p.checkHealthContext(ctx); err != nil {
+		return err
+	}
+	if options.skipRegistration {
+		return nil
+	}
+	return p.checkRegistered()
+}
+
+// checkRegistered fails until the plugin's registrar has registered an instance
+func (p *PlugPolaris) checkRegistered() error {
+	p.mu.RLock()
+	registrar := p.registrar
+	p.mu.RUnlock()
+	if registrar == nil || registrar.registeredSince().IsZero() {
+		return NewHealthCheckError("service is not registered with Polaris")
+	}
+	return nil
+}
+
+// LivenessHandler returns an HTTP handler for Kubernetes liveness probes, answering 200
+// while CheckLiveness passes and 503 otherwise. The plugin is not live before it has
+// initialized, so give the probe an initial delay or pair it with a startup probe.
+func (p *PlugPolaris) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeProbeResult(w, p.CheckLiveness())
+	})
+}
+
+// ReadinessHandler returns an HTTP handler for Kubernetes readiness probes, answering 200
+// while CheckReadiness passes and 503 otherwise. The check calls Polaris and stops when the
+// probe request is canceled, so set the probe timeout above the plugin timeout.
+func (p *PlugPolaris) ReadinessHandler(opts ...ProbeOption) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeProbeResult(w, p.CheckReadiness(r.Context(), opts...))
+	})
+}
+
+// writeProbeResult writes the JSON result of a probe
+func writeProbeResult(w http.ResponseWriter, err error) {
+	result := struct {
+		Status string `json:"status"`
+		Error  string `json:"error,omitempty"`
+	}{Status: "ok"}
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		result.Status, result.Error = "unavailable", err.Error()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
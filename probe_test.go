@@ -0,0 +1,60 @@
+package polaris
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// aliveSDK is an SDK context reporting whether it was destroyed.
+type aliveSDK struct {
+	api.SDKContext
+	destroyed bool
+}
+
+func (s *aliveSDK) IsDestroyed() bool { return s.destroyed }
+
+func TestLivenessHandler(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	rec := httptest.NewRecorder()
+	plugin.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	plugin.setInitialized()
+	sdk := &aliveSDK{}
+	plugin.sdk = sdk
+	rec = httptest.NewRecorder()
+	plugin.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+
+	sdk.destroyed = true
+	assert.Error(t, plugin.CheckLiveness())
+}
+
+func TestReadinessHandler_NotInitialized(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	rec := httptest.NewRecorder()
+	plugin.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"unavailable"`)
+	assert.True(t, IsInitError(plugin.CheckReadiness(context.Background(), WithoutRegistrationCheck())))
+}
+
+func TestCheckRegistered(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.Error(t, plugin.checkRegistered())
+
+	plugin.registrar = NewPolarisRegistrar(nil, "default")
+	assert.Error(t, plugin.checkRegistered())
+
+	plugin.registrar.registeredAt = time.Now()
+	assert.NoError(t, plugin.checkRegistered())
+}
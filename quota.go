@@ -0,0 +1,82 @@
+package polaris
+
+import (
+	"context"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/quota"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// QuotaResult is the outcome of a rate limit quota request.
+//
+// polaris-go v1.3.0 reports only the result code, an info message and the queueing wait
+// of a quota, so the ID of the matched rule and the remaining quota are not available.
+type QuotaResult struct {
+	// Allowed reports whether the call may proceed, after WaitDuration.
+	Allowed bool
+	// WaitDuration is how long the call has to be delayed by a uniform-rate rule before
+	// it proceeds. It is zero for rejected calls and for rules without queueing.
+	WaitDuration time.Duration
+	// RuleMatched reports whether any rate limit rule applied to the call.
+	RuleMatched bool
+	// Degraded reports that no Polaris rule was evaluated: either rate limiting is
+	// disabled in the SDK, or the check failed and rate_limit_fallback decided the call.
+	Degraded bool
+	// Info is the message of the rate limiter, e.g. why the call was rejected.
+	Info string
+}
+
+// newQuotaResult converts a Polaris quota response.
+func newQuotaResult(resp *model.QuotaResponse) *QuotaResult {
+	return &QuotaResult{
+		Allowed:      resp.Code == model.QuotaResultOk,
+		WaitDuration: time.Duration(resp.WaitMs) * time.Millisecond,
+		RuleMatched:  resp.Info != quota.Disabled && resp.Info != quota.RuleNotExists && !isRateLimitFallback(resp),
+		Degraded:     resp.Info == quota.Disabled || isRateLimitFallback(resp),
+		Info:         resp.Info,
+	}
+}
+
+// Wait blocks for WaitDuration so that the call proceeds at the rate of the rule. It
+// returns ctx.Err() when ctx is done first and ErrCodeRateLimitExceeded when the call
+// was not allowed.
+func (r *QuotaResult) Wait(ctx context.Context) error {
+	if !r.Allowed {
+		return NewServiceError(ErrCodeRateLimitExceeded, "rate limit exceeded: "+r.Info)
+	}
+	if r.WaitDuration <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(r.WaitDuration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// AcquireQuota requests a rate limit quota for serviceName with labels and returns the
+// result without waiting. Unlike CheckRateLimit, which blocks through any queueing delay,
+// callers decide how to wait: call Wait on the result, or reject the call when the delay
+// is too long for them.
+func (p *PlugPolaris) AcquireQuota(serviceName string, labels map[string]string) (*QuotaResult, error) {
+	if err := p.checkSubsystem(SubsystemRateLimit); err != nil {
+		return nil, err
+	}
+	future, err := p.requestQuota(serviceName, "", labels)
+	if err != nil {
+		resp, fallbackErr := p.rateLimitFallback(serviceName, labels, err)
+		if fallbackErr != nil {
+			return nil, fallbackErr
+		}
+		return newQuotaResult(resp), nil
+	}
+	resp := future.GetImmediately()
+	if resp == nil {
+		return nil, NewServiceError(ErrCodeRateLimitFailed, "rate limit result is nil")
+	}
+	return newQuotaResult(resp), nil
+}
@@ -0,0 +1,44 @@
+package polaris
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/quota"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewQuotaResult(t *testing.T) {
+	assert.Equal(t, &QuotaResult{Allowed: true, WaitDuration: 40 * time.Millisecond, RuleMatched: true},
+		newQuotaResult(&model.QuotaResponse{Code: model.QuotaResultOk, WaitMs: 40}))
+	assert.Equal(t, &QuotaResult{RuleMatched: true, Info: "queueing time exceeded"},
+		newQuotaResult(&model.QuotaResponse{Code: model.QuotaResultLimited, Info: "queueing time exceeded"}))
+	assert.Equal(t, &QuotaResult{Allowed: true, Info: quota.RuleNotExists},
+		newQuotaResult(&model.QuotaResponse{Code: model.QuotaResultOk, Info: quota.RuleNotExists}))
+	assert.Equal(t, &QuotaResult{Allowed: true, Degraded: true, Info: quota.Disabled},
+		newQuotaResult(&model.QuotaResponse{Code: model.QuotaResultOk, Info: quota.Disabled}))
+}
+
+func TestQuotaResult_Wait(t *testing.T) {
+	start := time.Now()
+	require.NoError(t, (&QuotaResult{Allowed: true, WaitDuration: 20 * time.Millisecond}).Wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, (&QuotaResult{Allowed: true, WaitDuration: time.Minute}).Wait(ctx), context.Canceled)
+
+	err := (&QuotaResult{Info: "limited"}).Wait(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "limited")
+}
+
+func TestAcquireQuota_NotInitialized(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	result, err := plugin.AcquireQuota("orders", nil)
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
@@ -13,10 +13,14 @@ func (p *PlugPolaris) NewNodeRouter(name string) selector.NodeFilter {
 		log.Warnf("Polaris plugin not initialized, returning nil node router: %v", err)
 		return nil
 	}
+	if !p.SubsystemEnabled(SubsystemRouting) {
+		log.Infof("Polaris routing subsystem disabled, returning nil node router")
+		return nil
+	}
 	if p.polaris == nil {
 		log.Warnf("Polaris instance is nil, returning nil node router")
 		return nil
 	}
 	log.Infof("Synchronizing [%v] routing policy", name)
-	return p.polaris.NodeFilter(polaris.WithRouterService(name))
+	return p.routeFallbackNodeFilter(priorityNodeFilter(p.laneNodeFilter(p.polaris.NodeFilter(polaris.WithRouterService(name)))))
 }
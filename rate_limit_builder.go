@@ -0,0 +1,245 @@
+package polaris
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Allocation-free rate limit checks
+// Responsibility: rate limit checks for hot paths, e.g. a gateway checking every request.
+// CheckRateLimit builds a label map, normalizes it into another one and creates a quota
+// request per call; CheckRateLimitLabels takes a reusable label builder and pooled quota
+// requests instead, and PreparedRateLimit builds its quota request once.
+
+// quotaRequests pools the quota requests of CheckRateLimitLabels
+var quotaRequests = sync.Pool{New: func() any { return new(model.QuotaRequestImpl) }}
+
+// Label values of the check_rate_limit SDK operation metrics
+var (
+	checkRateLimitStartLabels   = []string{"check_rate_limit", "start"}
+	checkRateLimitSuccessLabels = []string{"check_rate_limit", "success"}
+)
+
+// metricLabelValues caches the label values, service then namespace, of the rate limit
+// metrics recorded per check, so that they are not allocated per call
+type metricLabelValues struct {
+	mu     sync.RWMutex
+	values map[string][]string
+}
+
+// get returns the label values of service in namespace.
+func (c *metricLabelValues) get(service, namespace string) []string {
+	c.mu.RLock()
+	values, ok := c.values[service]
+	c.mu.RUnlock()
+	if ok && values[1] == namespace {
+		return values
+	}
+	values = []string{service, namespace}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.values == nil {
+		c.values = make(map[string][]string)
+	}
+	c.values[service] = values
+	return values
+}
+
+// RateLimitLabels is a reusable set of rate limit labels. Reset it between requests and
+// keep it, e.g. in a sync.Pool, so that checks do not allocate once it has grown to its
+// label count. It is not safe for concurrent use.
+type RateLimitLabels struct {
+	keys   []string
+	values []string
+}
+
+// Set sets the label key to value.
+func (l *RateLimitLabels) Set(key, value string) {
+	if i := slices.Index(l.keys, key); i >= 0 {
+		l.values[i] = value
+		return
+	}
+	l.keys = append(l.keys, key)
+	l.values = append(l.values, value)
+}
+
+// Reset removes all labels, keeping the capacity for the next request.
+func (l *RateLimitLabels) Reset() {
+	clear(l.keys)
+	clear(l.values)
+	l.keys = l.keys[:0]
+	l.values = l.values[:0]
+}
+
+// Len returns the number of labels.
+func (l *RateLimitLabels) Len() int {
+	return len(l.keys)
+}
+
+// labelsByKey sorts labels by key, the order in which max_labels keeps them
+type labelsByKey RateLimitLabels
+
+func (l *labelsByKey) Len() int           { return len(l.keys) }
+func (l *labelsByKey) Less(i, j int) bool { return l.keys[i] < l.keys[j] }
+func (l *labelsByKey) Swap(i, j int) {
+	l.keys[i], l.keys[j] = l.keys[j], l.keys[i]
+	l.values[i], l.values[j] = l.values[j], l.values[i]
+}
+
+// labelMap returns the labels as a map, for the rate limit fallback.
+func (l *RateLimitLabels) labelMap() map[string]string {
+	labels := make(map[string]string, len(l.keys))
+	for i, key := range l.keys {
+		labels[key] = l.values[i]
+	}
+	return labels
+}
+
+// addTo adds the labels to req, normalized with cfg like normalizeRateLimitLabels does. The
+// labels must be sorted by key when cfg limits their number.
+func (l *RateLimitLabels) addTo(req *model.QuotaRequestImpl, cfg *conf.RateLimitLabels) labelNormalization {
+	var stats labelNormalization
+	allowed := cfg.GetAllowedKeys()
+	maxLabels := int(cfg.GetMaxLabels())
+	maxLen := int(cfg.GetMaxValueLength())
+	kept := 0
+	for i, key := range l.keys {
+		if len(allowed) > 0 && !slices.Contains(allowed, key) {
+			stats.dropped++
+			continue
+		}
+		if maxLabels > 0 && kept == maxLabels {
+			stats.dropped++
+			continue
+		}
+		kept++
+		value := l.values[i]
+		if slices.Contains(cfg.GetHashedKeys(), key) {
+			value = hashLabelValue(value, cfg.GetHashBuckets())
+			stats.hashed++
+		}
+		if maxLen > 0 && len(value) > maxLen {
+			value = value[:maxLen]
+			stats.truncated++
+		}
+		req.AddArgument(model.BuildQueryArgument(key, value))
+	}
+	return stats
+}
+
+// recordLabelNormalization records what the normalization of rate limit labels changed.
+func recordLabelNormalization(metrics *Metrics, stats labelNormalization) {
+	if metrics != nil {
+		metrics.RecordRateLimitLabels("dropped", stats.dropped)
+		metrics.RecordRateLimitLabels("hashed", stats.hashed)
+		metrics.RecordRateLimitLabels("truncated", stats.truncated)
+	}
+}
+
+// CheckRateLimitLabels checks rate limiting for a service like CheckRateLimit, with labels
+// from a reusable RateLimitLabels. The quota request comes from a pool and the labels are
+// normalized without copying them into a map, so the check only allocates the argument list
+// that polaris-go grows as labels are added to the request, and whatever the rate_limit_fallback
+// path needs. labels may be reordered.
+func (p *PlugPolaris) CheckRateLimitLabels(serviceName string, labels *RateLimitLabels) (bool, error) {
+	if err := p.checkSubsystem(SubsystemRateLimit); err != nil {
+		return false, err
+	}
+	p.mu.RLock()
+	cfg := p.currentConf().GetRateLimitLabels()
+	metrics := p.metrics
+	p.mu.RUnlock()
+
+	req := quotaRequests.Get().(*model.QuotaRequestImpl)
+	defer func() {
+		*req = model.QuotaRequestImpl{}
+		quotaRequests.Put(req)
+	}()
+	req.SetService(serviceName)
+	if cfg.GetMaxLabels() > 0 {
+		sort.Sort((*labelsByKey)(labels))
+	}
+	recordLabelNormalization(metrics, labels.addTo(req, cfg))
+
+	future, err := p.sendQuotaRequest(serviceName, req, true)
+	if err != nil {
+		return quotaAllowed(p.rateLimitFallback(serviceName, labels.labelMap(), err))
+	}
+	return quotaAllowed(future.Get(), nil)
+}
+
+// PreparedRateLimit is a rate limit check of a service with a fixed label set, e.g. a route
+// of a gateway, whose quota request is built once and shared by all its checks. It is safe
+// for concurrent use.
+type PreparedRateLimit struct {
+	plugin  *PlugPolaris
+	service string
+	labels  RateLimitLabels
+	quota   atomic.Pointer[preparedQuota]
+}
+
+// preparedQuota is the quota request of a PreparedRateLimit, built for a namespace and
+// label settings. It is never modified once built.
+type preparedQuota struct {
+	namespace string
+	cfg       *conf.RateLimitLabels
+	req       *model.QuotaRequestImpl
+	stats     labelNormalization
+}
+
+// PrepareRateLimit returns a rate limit check of serviceName with labels. Its Check calls
+// do not allocate apart from the SDK call, except on the rate_limit_fallback path.
+func (p *PlugPolaris) PrepareRateLimit(serviceName string, labels map[string]string) *PreparedRateLimit {
+	r := &PreparedRateLimit{plugin: p, service: serviceName}
+	for key, value := range labels {
+		r.labels.Set(key, value)
+	}
+	sort.Sort((*labelsByKey)(&r.labels))
+	return r
+}
+
+// Check checks rate limiting like CheckRateLimit. The quota request is built again when the
+// namespace or the rate_limit_labels settings of the plugin have changed.
+func (r *PreparedRateLimit) Check() (bool, error) {
+	p := r.plugin
+	if err := p.checkSubsystem(SubsystemRateLimit); err != nil {
+		return false, err
+	}
+	p.mu.RLock()
+	namespace := p.currentConf().GetNamespace()
+	cfg := p.currentConf().GetRateLimitLabels()
+	metrics := p.metrics
+	p.mu.RUnlock()
+
+	quota := r.quota.Load()
+	if quota == nil || quota.namespace != namespace || quota.cfg != cfg {
+		quota = &preparedQuota{namespace: namespace, cfg: cfg, req: new(model.QuotaRequestImpl)}
+		quota.req.SetService(r.service)
+		quota.req.SetNamespace(namespace)
+		quota.stats = r.labels.addTo(quota.req, cfg)
+		r.quota.Store(quota)
+	}
+	recordLabelNormalization(metrics, quota.stats)
+
+	future, err := p.sendQuotaRequest(r.service, quota.req, false)
+	if err != nil {
+		return quotaAllowed(p.rateLimitFallback(r.service, r.labels.labelMap(), err))
+	}
+	return quotaAllowed(future.Get(), nil)
+}
+
+// quotaAllowed reports whether a quota result allows the call.
+func quotaAllowed(result *model.QuotaResponse, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	if result == nil {
+		return false, NewServiceError(ErrCodeRateLimitFailed, "rate limit result is nil")
+	}
+	return result.Code == model.QuotaResultOk, nil
+}
@@ -0,0 +1,105 @@
+package polaris
+
+import (
+	"testing"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitTestPlugin(t testing.TB, client LimitClient, labels *conf.RateLimitLabels) *PlugPolaris {
+	t.Helper()
+	return newTestPlugin(t, &conf.Polaris{Namespace: "default", RateLimitLabels: labels}, WithLimitClient(client))
+}
+
+func TestCheckRateLimitLabels(t *testing.T) {
+	client := &fakeClients{}
+	client.lastLabels = map[string]string{}
+	plugin := newRateLimitTestPlugin(t, client, &conf.RateLimitLabels{MaxLabels: 2, MaxValueLength: 8})
+
+	var labels RateLimitLabels
+	labels.Set("user", "alice")
+	labels.Set("region", "eu")
+	labels.Set("route", "/orders/42")
+	labels.Set("region", "us")
+	allowed, err := plugin.CheckRateLimitLabels("gateway", &labels)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, map[string]string{"region": "us", "route": "/orders/"}, client.lastLabels, "normalized like CheckRateLimit")
+
+	labels.Reset()
+	assert.Zero(t, labels.Len())
+	labels.Set("region", "blocked")
+	allowed, err = plugin.CheckRateLimitLabels("gateway", &labels)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestPreparedRateLimit(t *testing.T) {
+	client := &fakeClients{}
+	client.lastLabels = map[string]string{}
+	plugin := newRateLimitTestPlugin(t, client, nil)
+
+	check := plugin.PrepareRateLimit("gateway", map[string]string{"route": "/orders"})
+	allowed, err := check.Check()
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, map[string]string{"route": "/orders"}, client.lastLabels)
+
+	plugin.mu.Lock()
+	plugin.currentConf().RateLimitLabels = &conf.RateLimitLabels{AllowedKeys: []string{"user"}}
+	plugin.mu.Unlock()
+	_, err = check.Check()
+	require.NoError(t, err)
+	assert.Empty(t, client.lastLabels, "rebuilt with the new label settings")
+
+	blocked := plugin.PrepareRateLimit("gateway", map[string]string{"user": "blocked"})
+	allowed, err = blocked.Check()
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestRateLimitChecks_DoNotAllocate(t *testing.T) {
+	plugin := newRateLimitTestPlugin(t, &fakeClients{}, &conf.RateLimitLabels{MaxLabels: 4})
+	var labels RateLimitLabels
+	check := plugin.PrepareRateLimit("gateway", map[string]string{"route": "/orders"})
+
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		_, _ = check.Check()
+	}), "prepared checks")
+	assert.Equal(t, float64(2), testing.AllocsPerRun(100, func() {
+		labels.Reset()
+		labels.Set("user", "alice")
+		labels.Set("route", "/orders")
+		_, _ = plugin.CheckRateLimitLabels("gateway", &labels)
+	}), "label builder checks allocate only the arguments of the SDK request, one per growth")
+}
+
+func BenchmarkPreparedRateLimit(b *testing.B) {
+	plugin := newRateLimitTestPlugin(b, &fakeClients{}, nil)
+	check := plugin.PrepareRateLimit("gateway", map[string]string{"route": "/orders"})
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = check.Check()
+	}
+}
+
+func BenchmarkCheckRateLimitLabels(b *testing.B) {
+	plugin := newRateLimitTestPlugin(b, &fakeClients{}, nil)
+	var labels RateLimitLabels
+	b.ReportAllocs()
+	for b.Loop() {
+		labels.Reset()
+		labels.Set("route", "/orders")
+		_, _ = plugin.CheckRateLimitLabels("gateway", &labels)
+	}
+}
+
+func BenchmarkCheckRateLimit(b *testing.B) {
+	plugin := newRateLimitTestPlugin(b, &fakeClients{}, nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = plugin.CheckRateLimit("gateway", map[string]string{"route": "/orders"})
+	}
+}
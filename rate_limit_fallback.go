@@ -0,0 +1,118 @@
+package polaris
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// rateLimitFallbackInfo prefixes the info of quota results decided by the fallback policy.
+const rateLimitFallbackInfo = "rate limit fallback: "
+
+// tokenBucket is a token bucket refilled at rate tokens per second up to burst tokens.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full bucket.
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	capacity := float64(burst)
+	if capacity <= 0 {
+		capacity = math.Max(1, math.Ceil(rate))
+	}
+	return &tokenBucket{rate: rate, burst: capacity, tokens: capacity, last: now}
+}
+
+// take removes a token at now and reports whether one was available.
+func (b *tokenBucket) take(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// rateLimitFallback decides a quota of serviceName with labels that could not be checked
+// because of err, following rate_limit_fallback. Errors other than failed checks, and any
+// error when no fallback mode is configured, are returned unchanged. The first decision
+// of an outage publishes a DegradationEvent.
+func (p *PlugPolaris) rateLimitFallback(serviceName string, labels map[string]string, err error) (*model.QuotaResponse, error) {
+	if !isErrorCode(err, ErrCodeRateLimitFailed) {
+		return nil, err
+	}
+	p.mu.RLock()
+	cfg := p.currentConf().GetRateLimitFallback()
+	labelsCfg := p.currentConf().GetRateLimitLabels()
+	namespace := p.currentConf().GetNamespace()
+	metrics := p.metrics
+	p.mu.RUnlock()
+
+	mode := cfg.GetMode()
+	var code model.QuotaResultCode
+	switch mode {
+	case conf.RateLimitFallbackAllow:
+		code = model.QuotaResultOk
+	case conf.RateLimitFallbackDeny:
+		code = model.QuotaResultLimited
+	case conf.RateLimitFallbackLocal:
+		code = model.QuotaResultLimited
+		normalized, _ := normalizeRateLimitLabels(labelsCfg, labels)
+		if p.localLimiter.allow(serviceName, normalized, cfg, time.Now()) {
+			code = model.QuotaResultOk
+		}
+	default:
+		return nil, err
+	}
+	if metrics != nil {
+		metrics.RecordSDKOperation("rate_limit_fallback", mode)
+	}
+	if p.localLimiter.enter() {
+		log.Warnf("Rate limit check for service %s failed, applying %s fallback until Polaris recovers: %v", serviceName, mode, err)
+		p.publishDegradation(&DegradationEvent{
+			Kind:             EventTypeDegradation,
+			DegradationType:  "rate_limit_failure",
+			Service:          serviceName,
+			Namespace:        namespace,
+			Error:            err.Error(),
+			FallbackStrategy: mode,
+			Timestamp:        time.Now(),
+		})
+	} else {
+		log.Debugf("Rate limit check for service %s failed, applying %s fallback: %v", serviceName, mode, err)
+	}
+	return &model.QuotaResponse{Code: code, Info: rateLimitFallbackInfo + mode}, nil
+}
+
+// rateLimitRecovered hands rate limiting of serviceName back to Polaris after a successful
+// check and, in local fallback mode, refreshes the local rate from the Polaris rules.
+func (p *PlugPolaris) rateLimitRecovered(serviceName string) {
+	if p.localLimiter.recover() {
+		log.Infof("Polaris rate limiting recovered, local fallback limiter released")
+	}
+	p.mu.RLock()
+	mode := p.currentConf().GetRateLimitFallback().GetMode()
+	p.mu.RUnlock()
+	if mode == conf.RateLimitFallbackLocal && p.localLimiter.shouldSync(serviceName, time.Now()) {
+		p.syncLocalRate(serviceName)
+	}
+}
+
+// isRateLimitFallback reports whether a quota result was decided by the fallback policy.
+func isRateLimitFallback(resp *model.QuotaResponse) bool {
+	return strings.HasPrefix(resp.Info, rateLimitFallbackInfo)
+}
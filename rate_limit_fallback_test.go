@@ -0,0 +1,78 @@
+package polaris
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	bucket := newTokenBucket(2, 0, now)
+	assert.True(t, bucket.take(now))
+	assert.True(t, bucket.take(now))
+	assert.False(t, bucket.take(now))
+	assert.False(t, bucket.take(now.Add(400*time.Millisecond)))
+	assert.True(t, bucket.take(now.Add(500*time.Millisecond)))
+	assert.True(t, bucket.take(now.Add(10*time.Second)))
+	assert.True(t, bucket.take(now.Add(10*time.Second)))
+	assert.False(t, bucket.take(now.Add(10*time.Second)))
+
+	assert.Equal(t, float64(1), newTokenBucket(0.5, 0, now).burst)
+	assert.Equal(t, float64(5), newTokenBucket(0.5, 5, now).burst)
+}
+
+func TestRateLimitFallback_Modes(t *testing.T) {
+	checkErr := WrapServiceError(errors.New("connection refused"), ErrCodeRateLimitFailed, "failed to check rate limit")
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+
+	_, err := plugin.rateLimitFallback("orders", nil, checkErr)
+	assert.Equal(t, checkErr, err)
+
+	plugin.currentConf().RateLimitFallback = &conf.RateLimitFallback{Mode: conf.RateLimitFallbackAllow}
+	resp, err := plugin.rateLimitFallback("orders", nil, checkErr)
+	require.NoError(t, err)
+	assert.Equal(t, model.QuotaResultOk, resp.Code)
+	assert.Equal(t, &QuotaResult{Allowed: true, Degraded: true, Info: "rate limit fallback: allow"}, newQuotaResult(resp))
+
+	initErr := NewInitError("Polaris plugin has been destroyed")
+	_, err = plugin.rateLimitFallback("orders", nil, initErr)
+	assert.Equal(t, initErr, err)
+
+	plugin.currentConf().RateLimitFallback = &conf.RateLimitFallback{Mode: conf.RateLimitFallbackDeny}
+	resp, err = plugin.rateLimitFallback("orders", nil, checkErr)
+	require.NoError(t, err)
+	assert.Equal(t, model.QuotaResultLimited, resp.Code)
+
+	plugin.currentConf().RateLimitFallback = &conf.RateLimitFallback{Mode: conf.RateLimitFallbackLocal, LocalQps: 0.001, LocalBurst: 1}
+	resp, err = plugin.rateLimitFallback("orders", nil, checkErr)
+	require.NoError(t, err)
+	assert.Equal(t, model.QuotaResultOk, resp.Code)
+	resp, err = plugin.rateLimitFallback("orders", nil, checkErr)
+	require.NoError(t, err)
+	assert.Equal(t, model.QuotaResultLimited, resp.Code)
+	resp, err = plugin.rateLimitFallback("payments", nil, checkErr)
+	require.NoError(t, err)
+	assert.Equal(t, model.QuotaResultOk, resp.Code)
+}
+
+func TestValidator_RateLimitFallback(t *testing.T) {
+	for _, fb := range []*conf.RateLimitFallback{
+		{Mode: "retry"},
+		{Mode: conf.RateLimitFallbackLocal},
+		{Mode: conf.RateLimitFallbackLocal, LocalQps: 10, LocalBurst: -1},
+	} {
+		cfg := &conf.Polaris{Namespace: "default", Weight: 100, RateLimitFallback: fb}
+		assert.False(t, NewValidator(cfg).Validate().IsValid, "mode %q", fb.Mode)
+	}
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, RateLimitFallback: &conf.RateLimitFallback{Mode: conf.RateLimitFallbackLocal, LocalQps: 10}}
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		assert.NotContains(t, e.Field, "rate_limit_fallback")
+	}
+}
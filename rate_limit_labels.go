@@ -0,0 +1,85 @@
+package polaris
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+)
+
+// labelNormalization counts the labels changed by normalizeRateLimitLabels.
+type labelNormalization struct {
+	dropped   int
+	hashed    int
+	truncated int
+}
+
+// normalizeRateLimitLabels applies cfg to labels: keys outside the allowlist are dropped,
+// labels beyond max_labels are dropped in sorted key order, values of hashed keys are
+// replaced by their hash and long values are truncated. labels is not modified.
+func normalizeRateLimitLabels(cfg *conf.RateLimitLabels, labels map[string]string) (map[string]string, labelNormalization) {
+	var stats labelNormalization
+	if cfg == nil || len(labels) == 0 {
+		return labels, stats
+	}
+
+	keys := make([]string, 0, len(labels))
+	allowed := cfg.GetAllowedKeys()
+	for key := range labels {
+		if len(allowed) > 0 && !slices.Contains(allowed, key) {
+			stats.dropped++
+			continue
+		}
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	if maxLabels := int(cfg.GetMaxLabels()); maxLabels > 0 && len(keys) > maxLabels {
+		stats.dropped += len(keys) - maxLabels
+		keys = keys[:maxLabels]
+	}
+
+	normalized := make(map[string]string, len(keys))
+	for _, key := range keys {
+		value := labels[key]
+		if slices.Contains(cfg.GetHashedKeys(), key) {
+			value = hashLabelValue(value, cfg.GetHashBuckets())
+			stats.hashed++
+		}
+		if maxLen := int(cfg.GetMaxValueLength()); maxLen > 0 && len(value) > maxLen {
+			value = value[:maxLen]
+			stats.truncated++
+		}
+		normalized[key] = value
+	}
+	return normalized, stats
+}
+
+// hashLabelValue returns the FNV-1a hash of value in hex, or its bucket index when
+// buckets is positive.
+func hashLabelValue(value string, buckets int32) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(value))
+	sum := h.Sum64()
+	if buckets > 0 {
+		return strconv.FormatUint(sum%uint64(buckets), 10)
+	}
+	return strconv.FormatUint(sum, 16)
+}
+
+// normalizeLabels normalizes rate limit labels with the configured rules and records what
+// was changed.
+func (p *PlugPolaris) normalizeLabels(labels map[string]string) map[string]string {
+	p.mu.RLock()
+	cfg := p.currentConf().GetRateLimitLabels()
+	metrics := p.metrics
+	p.mu.RUnlock()
+
+	normalized, stats := normalizeRateLimitLabels(cfg, labels)
+	if metrics != nil {
+		metrics.RecordRateLimitLabels("dropped", stats.dropped)
+		metrics.RecordRateLimitLabels("hashed", stats.hashed)
+		metrics.RecordRateLimitLabels("truncated", stats.truncated)
+	}
+	return normalized
+}
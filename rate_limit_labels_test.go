@@ -0,0 +1,41 @@
+package polaris
+
+import (
+	"testing"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeRateLimitLabels(t *testing.T) {
+	labels := map[string]string{"method": "GET", "path": "/orders", "user_id": "u-123456", "trace_id": "abc"}
+
+	out, stats := normalizeRateLimitLabels(nil, labels)
+	assert.Equal(t, labels, out)
+	assert.Zero(t, stats)
+
+	cfg := &conf.RateLimitLabels{AllowedKeys: []string{"method", "path", "user_id"}, HashedKeys: []string{"user_id"}}
+	out, stats = normalizeRateLimitLabels(cfg, labels)
+	assert.Equal(t, 1, stats.dropped)
+	assert.Equal(t, 1, stats.hashed)
+	assert.NotContains(t, out, "trace_id")
+	assert.Equal(t, hashLabelValue("u-123456", 0), out["user_id"])
+	assert.Equal(t, "GET", out["method"])
+	assert.Equal(t, "u-123456", labels["user_id"], "input is not modified")
+
+	// Hashing into buckets bounds the number of distinct values.
+	cfg.HashBuckets = 8
+	out, _ = normalizeRateLimitLabels(cfg, labels)
+	assert.Len(t, out["user_id"], 1)
+
+	// The label cap keeps keys in sorted order.
+	out, stats = normalizeRateLimitLabels(&conf.RateLimitLabels{MaxLabels: 2, MaxValueLength: 3}, labels)
+	assert.Equal(t, map[string]string{"method": "GET", "path": "/or"}, out)
+	assert.Equal(t, 2, stats.dropped)
+	assert.Equal(t, 1, stats.truncated)
+}
+
+func TestHashLabelValue(t *testing.T) {
+	assert.Equal(t, hashLabelValue("u-1", 0), hashLabelValue("u-1", 0))
+	assert.NotEqual(t, hashLabelValue("u-1", 0), hashLabelValue("u-2", 0))
+}
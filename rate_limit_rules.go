@@ -0,0 +1,216 @@
+package polaris
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	namingpb "github.com/polarismesh/polaris-go/pkg/model/pb/v1"
+)
+
+// RateLimitRule is a Polaris rate limit rule as seen by the SDK of this application.
+type RateLimitRule struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Service   string `json:"service"`
+	// Type is "global" for rules counted by the Polaris rate limit server and "local" for
+	// rules counted in each process.
+	Type string `json:"type"`
+	// Resource is "qps" for request rate rules and "concurrency" for in-flight limits.
+	Resource  string                    `json:"resource"`
+	Disabled  bool                      `json:"disabled"`
+	Priority  uint32                    `json:"priority"`
+	Method    *RateLimitMatch           `json:"method,omitempty"`
+	Labels    map[string]RateLimitMatch `json:"labels,omitempty"`
+	Arguments []RateLimitArgument       `json:"arguments,omitempty"`
+	Amounts   []RateLimitAmount         `json:"amounts"`
+	// Action is the limiting behavior, e.g. "REJECT" or "UNIRATE".
+	Action   string `json:"action"`
+	Revision string `json:"revision"`
+}
+
+// RateLimitMatch is how a rule matches a value, e.g. {"type": "EXACT", "value": "GET"}.
+type RateLimitMatch struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// RateLimitArgument is a matched request argument of a rule, such as a header or query
+// parameter.
+type RateLimitArgument struct {
+	Type  string         `json:"type"`
+	Key   string         `json:"key"`
+	Match RateLimitMatch `json:"match"`
+}
+
+// RateLimitAmount is a quota of a rule: at most MaxAmount calls per Window.
+type RateLimitAmount struct {
+	MaxAmount uint32        `json:"max_amount"`
+	Window    time.Duration `json:"-"`
+}
+
+// MarshalJSON encodes Window as a duration string such as "1s".
+func (a RateLimitAmount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		MaxAmount uint32 `json:"max_amount"`
+		Window    string `json:"window"`
+	}{a.MaxAmount, a.Window.String()})
+}
+
+// fetchRateLimitRules loads the rate limit rules of serviceName through the SDK, which
+// caches them for later quota requests.
+func (p *PlugPolaris) fetchRateLimitRules(serviceName string) (*namingpb.RateLimit, error) {
+	p.mu.RLock()
+	sdk := p.sdk
+	namespace := p.currentConf().GetNamespace()
+	p.mu.RUnlock()
+	if sdk == nil {
+		return nil, NewPolarisError(ErrCodeNotInitialized, "Polaris SDK not initialized")
+	}
+	resp, err := sdk.GetEngine().SyncGetServiceRule(model.EventRateLimiting, &model.GetServiceRuleRequest{
+		Namespace: namespace,
+		Service:   serviceName,
+	})
+	if err != nil {
+		return nil, WrapServiceError(err, ErrCodeRateLimitFailed, "failed to get rate limit rules of service "+serviceName)
+	}
+	rules, _ := resp.Value.(*namingpb.RateLimit)
+	return rules, nil
+}
+
+// GetRateLimitRules returns the Polaris rate limit rules of serviceName, or of the running
+// application when serviceName is empty, so that operators can verify which rules are
+// active. Quota checks of the service use the same rules.
+func (p *PlugPolaris) GetRateLimitRules(serviceName string) ([]RateLimitRule, error) {
+	if err := p.checkInitialized(); err != nil {
+		return nil, err
+	}
+	if err := p.checkSubsystem(SubsystemRateLimit); err != nil {
+		return nil, err
+	}
+	if serviceName == "" {
+		serviceName = currentLynxName()
+	}
+	if serviceName == "" {
+		return nil, NewServiceError(ErrCodeServiceNotFound, "service name is required")
+	}
+	rules, err := p.fetchRateLimitRules(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	return rateLimitRulesFromProto(rules), nil
+}
+
+// rateLimitRulesFromProto converts the rules of a Polaris rate limit response.
+func rateLimitRulesFromProto(rules *namingpb.RateLimit) []RateLimitRule {
+	result := make([]RateLimitRule, 0, len(rules.GetRules()))
+	for _, rule := range rules.GetRules() {
+		converted := RateLimitRule{
+			ID:        rule.GetId().GetValue(),
+			Name:      rule.GetName().GetValue(),
+			Namespace: rule.GetNamespace().GetValue(),
+			Service:   rule.GetService().GetValue(),
+			Type:      strings.ToLower(rule.GetType().String()),
+			Resource:  strings.ToLower(rule.GetResource().String()),
+			Disabled:  rule.GetDisable().GetValue(),
+			Priority:  rule.GetPriority().GetValue(),
+			Action:    rule.GetAction().GetValue(),
+			Revision:  rule.GetRevision().GetValue(),
+			Amounts:   make([]RateLimitAmount, 0, len(rule.GetAmounts())),
+		}
+		if method := rule.GetMethod(); method != nil {
+			match := rateLimitMatchFromProto(method)
+			converted.Method = &match
+		}
+		if len(rule.GetLabels()) > 0 {
+			converted.Labels = make(map[string]RateLimitMatch, len(rule.GetLabels()))
+			for key, match := range rule.GetLabels() {
+				converted.Labels[key] = rateLimitMatchFromProto(match)
+			}
+		}
+		for _, argument := range rule.GetArguments() {
+			converted.Arguments = append(converted.Arguments, RateLimitArgument{
+				Type:  argument.GetType().String(),
+				Key:   argument.GetKey(),
+				Match: rateLimitMatchFromProto(argument.GetValue()),
+			})
+		}
+		for _, amount := range rule.GetAmounts() {
+			converted.Amounts = append(converted.Amounts, RateLimitAmount{
+				MaxAmount: amount.GetMaxAmount().GetValue(),
+				Window: time.Duration(amount.GetValidDuration().GetSeconds())*time.Second +
+					time.Duration(amount.GetValidDuration().GetNanos()),
+			})
+		}
+		result = append(result, converted)
+	}
+	return result
+}
+
+// rateLimitMatchFromProto converts a Polaris match expression.
+func rateLimitMatchFromProto(match *namingpb.MatchString) RateLimitMatch {
+	return RateLimitMatch{Type: match.GetType().String(), Value: match.GetValue().GetValue()}
+}
+
+// RateLimitRulesHandler returns an HTTP handler serving the rate limit rules of the
+// service named by the "service" query parameter, or of the running application, as JSON.
+// Mount it on an internal debug listener; it exposes the rule configuration.
+func (p *PlugPolaris) RateLimitRulesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serviceName := r.URL.Query().Get("service")
+		if serviceName == "" {
+			serviceName = currentLynxName()
+		}
+		rules, err := p.GetRateLimitRules(serviceName)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if IsInitError(err) {
+				status = http.StatusServiceUnavailable
+			} else if isErrorCode(err, ErrCodeServiceNotFound) {
+				status = http.StatusBadRequest
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		p.mu.RLock()
+		namespace := p.currentConf().GetNamespace()
+		p.mu.RUnlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Namespace string          `json:"namespace"`
+			Service   string          `json:"service"`
+			Rules     []RateLimitRule `json:"rules"`
+		}{namespace, serviceName, rules})
+	})
+}
+
+// startRateLimitPrefetch loads the rate limit rules of the running application in the
+// background, so that the first quota checks after startup do not wait for them. In local
+// fallback mode the local rate is seeded from the rules as well.
+func (p *PlugPolaris) startRateLimitPrefetch() {
+	serviceName := currentLynxName()
+	if serviceName == "" || !p.SubsystemEnabled(SubsystemRateLimit) {
+		return
+	}
+	go func() {
+		start := time.Now()
+		rules, err := p.fetchRateLimitRules(serviceName)
+		if err != nil {
+			log.Warnf("Failed to prefetch rate limit rules of service %s: %v", serviceName, err)
+			return
+		}
+		log.Infof("Prefetched %d rate limit rules of service %s in %v", len(rules.GetRules()), serviceName, time.Since(start))
+		p.mu.RLock()
+		mode := p.currentConf().GetRateLimitFallback().GetMode()
+		p.mu.RUnlock()
+		if mode == conf.RateLimitFallbackLocal && p.localLimiter.shouldSync(serviceName, time.Now()) {
+			rate, ok := localRateFromRules(rules)
+			p.localLimiter.setRate(serviceName, rate, ok)
+		}
+	}()
+}
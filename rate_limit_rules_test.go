@@ -0,0 +1,76 @@
+package polaris
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	namingpb "github.com/polarismesh/polaris-go/pkg/model/pb/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRateLimitRulesFromProto(t *testing.T) {
+	rules := &namingpb.RateLimit{Rules: []*namingpb.Rule{{
+		Id:        wrapperspb.String("rule-1"),
+		Name:      wrapperspb.String("orders-create"),
+		Namespace: wrapperspb.String("default"),
+		Service:   wrapperspb.String("orders"),
+		Type:      namingpb.Rule_LOCAL,
+		Priority:  wrapperspb.UInt32(1),
+		Action:    wrapperspb.String("REJECT"),
+		Revision:  wrapperspb.String("rev-7"),
+		Disable:   wrapperspb.Bool(true),
+		Method:    &namingpb.MatchString{Value: wrapperspb.String("Create")},
+		Labels: map[string]*namingpb.MatchString{
+			"tier": {Type: namingpb.MatchString_REGEX, Value: wrapperspb.String("gold|silver")},
+		},
+		Arguments: []*namingpb.MatchArgument{{
+			Type:  namingpb.MatchArgument_HEADER,
+			Key:   "X-Tenant",
+			Value: &namingpb.MatchString{Value: wrapperspb.String("acme")},
+		}},
+		Amounts: []*namingpb.Amount{{MaxAmount: wrapperspb.UInt32(100), ValidDuration: durationpb.New(time.Second)}},
+	}}}
+
+	converted := rateLimitRulesFromProto(rules)
+	require.Len(t, converted, 1)
+	rule := converted[0]
+	assert.Equal(t, "rule-1", rule.ID)
+	assert.Equal(t, "orders-create", rule.Name)
+	assert.Equal(t, "local", rule.Type)
+	assert.True(t, rule.Disabled)
+	assert.Equal(t, uint32(1), rule.Priority)
+	assert.Equal(t, &RateLimitMatch{Type: "EXACT", Value: "Create"}, rule.Method)
+	assert.Equal(t, RateLimitMatch{Type: "REGEX", Value: "gold|silver"}, rule.Labels["tier"])
+	assert.Equal(t, []RateLimitArgument{{Type: "HEADER", Key: "X-Tenant", Match: RateLimitMatch{Type: "EXACT", Value: "acme"}}}, rule.Arguments)
+	assert.Equal(t, []RateLimitAmount{{MaxAmount: 100, Window: time.Second}}, rule.Amounts)
+	assert.Equal(t, "REJECT", rule.Action)
+	assert.Equal(t, "rev-7", rule.Revision)
+
+	assert.Empty(t, rateLimitRulesFromProto(nil))
+}
+
+func TestRateLimitAmountJSON(t *testing.T) {
+	data, err := json.Marshal(RateLimitAmount{MaxAmount: 600, Window: time.Minute})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"max_amount":600,"window":"1m0s"}`, string(data))
+}
+
+func TestGetRateLimitRules_NotInitialized(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	rules, err := plugin.GetRateLimitRules("orders")
+	assert.Error(t, err)
+	assert.Nil(t, rules)
+}
+
+func TestRateLimitRulesHandler_NotInitialized(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	rec := httptest.NewRecorder()
+	plugin.RateLimitRulesHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?service=orders", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
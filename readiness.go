@@ -0,0 +1,117 @@
+package polaris
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// Readiness module
+// Responsibility: blocking startup until the Polaris wiring of the application is ready,
+// i.e. initialized, registered, with required configs loaded and discovery caches warm.
+
+// readinessPollInterval is the delay between checks of the pending readiness conditions.
+var readinessPollInterval = 500 * time.Millisecond
+
+// readinessTimeout returns readiness.timeout, or its default when unset.
+func readinessTimeout(cfg *conf.Readiness) time.Duration {
+	if timeout := cfg.GetTimeout().AsDuration(); timeout > 0 {
+		return timeout
+	}
+	return conf.DefaultReadinessTimeout
+}
+
+// WaitReady blocks until the plugin is initialized, the service is registered, the
+// required config files are loaded and the instances of readiness.services have been
+// discovered, so that applications accept traffic only once Polaris is wired up.
+// Registration is not awaited with WithoutRegistrationCheck or when the registration
+// subsystem is disabled. When readiness.timeout passes first, WaitReady fails, or with
+// readiness.on_timeout "degraded" logs the pending conditions, raises a degradation alert
+// and returns nil. It fails right away when ctx is done.
+func (p *PlugPolaris) WaitReady(ctx context.Context, opts ...ProbeOption) error {
+	return p.waitReady(ctx, func(serviceName string) error {
+		_, err := p.getServiceInstances(serviceName)
+		return err
+	}, opts...)
+}
+
+// waitReady implements WaitReady, discovering the instances of readiness.services with
+// discover.
+func (p *PlugPolaris) waitReady(ctx context.Context, discover func(serviceName string) error, opts ...ProbeOption) error {
+	var options probeOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	p.mu.RLock()
+	cfg := p.currentConf().GetReadiness()
+	p.mu.RUnlock()
+	timeout := readinessTimeout(cfg)
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	warmed := make(map[string]bool)
+	start := time.Now()
+	for {
+		pending := p.pendingReadiness(cfg, options, warmed, discover)
+		if len(pending) == 0 {
+			log.Infof("Polaris ready after %v", time.Since(start).Round(time.Millisecond))
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("polaris not ready, waiting for %s: %w", strings.Join(pending, ", "), ctx.Err())
+		case <-deadline.C:
+			message := fmt.Sprintf("not ready after %v, waiting for %s", timeout, strings.Join(pending, ", "))
+			if cfg.GetOnTimeout() != conf.ReadinessOnTimeoutDegraded {
+				return NewHealthCheckError("Polaris " + message)
+			}
+			log.Warnf("Polaris %s, continuing in degraded mode", message)
+			p.raiseAlert(Alert{
+				Type:     AlertDegradation,
+				Severity: AlertSeverityWarning,
+				Subject:  "readiness",
+				Message:  message + ", continuing in degraded mode",
+			})
+			return nil
+		case <-time.After(readinessPollInterval):
+		}
+	}
+}
+
+// pendingReadiness returns the readiness conditions that are not met yet. Services
+// discovered once are added to warmed and not queried again.
+func (p *PlugPolaris) pendingReadiness(
+	cfg *conf.Readiness, options probeOptions, warmed map[string]bool, discover func(serviceName string) error,
+) []string {
+	if !p.IsInitialized() {
+		return []string{"initialization"}
+	}
+	var pending []string
+	if !options.skipRegistration && p.SubsystemEnabled(SubsystemRegistration) && p.checkRegistered() != nil {
+		pending = append(pending, "registration")
+	}
+	if !p.requiredConfigsReady() {
+		pending = append(pending, "required configs")
+	}
+	if !p.SubsystemEnabled(SubsystemDiscovery) {
+		return pending
+	}
+	for _, serviceName := range cfg.GetServices() {
+		if warmed[serviceName] {
+			continue
+		}
+		if err := discover(serviceName); err != nil {
+			log.Debugf("Service %s not discovered yet: %v", serviceName, p.redactError(err))
+			pending = append(pending, "discovery of "+serviceName)
+			continue
+		}
+		warmed[serviceName] = true
+	}
+	return pending
+}
@@ -0,0 +1,88 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newReadinessPlugin(t *testing.T, readiness *conf.Readiness) *PlugPolaris {
+	t.Helper()
+	original := readinessPollInterval
+	readinessPollInterval = time.Millisecond
+	t.Cleanup(func() { readinessPollInterval = original })
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", Readiness: readiness})
+	plugin.setInitialized()
+	return plugin
+}
+
+func TestWaitReady_Discovery(t *testing.T) {
+	plugin := newReadinessPlugin(t, &conf.Readiness{Services: []string{"orders", "payments"}})
+	calls := map[string]int{}
+	discover := func(serviceName string) error {
+		calls[serviceName]++
+		if serviceName == "payments" && calls[serviceName] < 3 {
+			return errors.New("unavailable")
+		}
+		return nil
+	}
+
+	require.NoError(t, plugin.waitReady(context.Background(), discover, WithoutRegistrationCheck()))
+	assert.Equal(t, 1, calls["orders"], "discovered services are not queried again")
+	assert.Equal(t, 3, calls["payments"])
+}
+
+func TestWaitReady_Timeout(t *testing.T) {
+	plugin := newReadinessPlugin(t, &conf.Readiness{Timeout: durationpb.New(20 * time.Millisecond)})
+	err := plugin.waitReady(context.Background(), nil)
+	require.Error(t, err)
+	assert.True(t, isErrorCode(err, ErrCodeHealthCheckFailed))
+	assert.Contains(t, err.Error(), "registration")
+
+	plugin.currentConf().Subsystems = &conf.Subsystems{Discovery: true}
+	assert.NoError(t, plugin.waitReady(context.Background(), nil), "registration is not awaited when disabled")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	plugin.currentConf().Subsystems = nil
+	assert.ErrorIs(t, plugin.waitReady(ctx, nil), context.Canceled)
+}
+
+func TestWaitReady_Degraded(t *testing.T) {
+	plugin := newReadinessPlugin(t, &conf.Readiness{
+		Timeout:   durationpb.New(20 * time.Millisecond),
+		OnTimeout: conf.ReadinessOnTimeoutDegraded,
+		Services:  []string{"orders"},
+	})
+	alerter, alerts := alertChannel()
+	require.NoError(t, plugin.AddAlerter("test", alerter, ""))
+
+	err := plugin.waitReady(context.Background(), func(string) error { return errors.New("unavailable") },
+		WithoutRegistrationCheck())
+	require.NoError(t, err)
+	alert := receiveAlert(t, alerts)
+	assert.Equal(t, AlertDegradation, alert.Type)
+	assert.Contains(t, alert.Message, "discovery of orders")
+}
+
+func TestValidator_Readiness(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, Readiness: &conf.Readiness{
+		Timeout:   durationpb.New(-time.Second),
+		OnTimeout: "ignore",
+		Services:  []string{""},
+	}}
+	var fields []string
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.Contains(t, fields, "readiness.timeout")
+	assert.Contains(t, fields, "readiness.on_timeout")
+	assert.Contains(t, fields, "readiness.services[0]")
+}
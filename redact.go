@@ -0,0 +1,119 @@
+package polaris
+
+import (
+	"path"
+	"strings"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+)
+
+// redactedValue replaces secrets in validation errors, logs, events, audit events, alerts
+// and stats
+const redactedValue = "[REDACTED]"
+
+// minRedactedSecretLength keeps very short values, which would redact unrelated text, out
+// of the redacted secrets. Valid tokens are at least 8 characters long.
+const minRedactedSecretLength = 4
+
+// secrets returns the token values that must not appear in output: the token of config,
+// the token loaded from the token provider and the operation tokens.
+func (p *PlugPolaris) secrets() []string {
+	p.mu.RLock()
+	cfg := p.currentConf()
+	p.mu.RUnlock()
+	secrets := []string{cfg.GetToken()}
+	for _, token := range cfg.GetOperationTokens() {
+		secrets = append(secrets, token.GetToken())
+	}
+
+	p.tokenMutex.RLock()
+	secrets = append(secrets, p.token)
+	for _, token := range p.operationTokens {
+		secrets = append(secrets, token)
+	}
+	p.tokenMutex.RUnlock()
+
+	kept := secrets[:0]
+	for _, secret := range secrets {
+		if len(secret) >= minRedactedSecretLength {
+			kept = append(kept, secret)
+		}
+	}
+	return kept
+}
+
+// redact replaces every token in s with [REDACTED].
+func (p *PlugPolaris) redact(s string) string {
+	if s == "" {
+		return s
+	}
+	for _, secret := range p.secrets() {
+		s = strings.ReplaceAll(s, secret, redactedValue)
+	}
+	return s
+}
+
+// redactError returns the message of err with every token replaced by [REDACTED].
+func (p *PlugPolaris) redactError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return p.redact(err.Error())
+}
+
+// isSensitiveConfig reports whether fileName matches sensitive_config_files, or the default
+// patterns when none are configured.
+func (p *PlugPolaris) isSensitiveConfig(fileName string) bool {
+	p.mu.RLock()
+	patterns := p.currentConf().GetSensitiveConfigFiles()
+	p.mu.RUnlock()
+	if len(patterns) == 0 {
+		patterns = conf.DefaultSensitiveConfigFiles
+	}
+	name := strings.ToLower(path.Base(fileName))
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// redactEvent returns event with its tokens redacted and, for changes of sensitive config
+// files, without the diff. Events are copied, never modified in place.
+func (p *PlugPolaris) redactEvent(event Event) Event {
+	switch e := event.(type) {
+	case *ConfigChangedEvent:
+		redacted := *e
+		if p.isSensitiveConfig(e.FileName) {
+			redacted.Diff = nil
+			redacted.Redacted = true
+			return &redacted
+		}
+		redacted.Diff = make([]ConfigDiffLine, len(e.Diff))
+		for i, line := range e.Diff {
+			line.Text = p.redact(line.Text)
+			redacted.Diff[i] = line
+		}
+		return &redacted
+	case *ConfigReloadedEvent:
+		if len(e.Errors) == 0 {
+			return e
+		}
+		redacted := *e
+		redacted.Errors = make(map[string]string, len(e.Errors))
+		for handler, err := range e.Errors {
+			redacted.Errors[handler] = p.redact(err)
+		}
+		return &redacted
+	case *DegradationEvent:
+		redacted := *e
+		redacted.Error = p.redact(e.Error)
+		return &redacted
+	case *HealthChangedEvent:
+		redacted := *e
+		redacted.Error = p.redact(e.Error)
+		return &redacted
+	}
+	return event
+}
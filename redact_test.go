@@ -0,0 +1,104 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", Token: "config-token1", OperationTokens: map[string]*conf.OperationToken{
+		string(TokenOperationConfigWrite): {Token: "write-token1"},
+	}})
+	require.NoError(t, plugin.SetTokenProvider(TokenProviderFunc(func(ctx context.Context) (string, error) {
+		return "rotated-token1", nil
+	})))
+
+	assert.Equal(t, "token [REDACTED], [REDACTED] and [REDACTED] rejected",
+		plugin.redact("token config-token1, write-token1 and rotated-token1 rejected"))
+	assert.Equal(t, "401: bad token [REDACTED]", plugin.redactError(errors.New("401: bad token rotated-token1")))
+	assert.Empty(t, plugin.redactError(nil))
+
+	// Sensitive config files, matched case-insensitively on the base name
+	assert.True(t, plugin.isSensitiveConfig("db-Secrets.yaml"))
+	assert.True(t, plugin.isSensitiveConfig("certs/server.pem"))
+	assert.False(t, plugin.isSensitiveConfig("app.yaml"))
+	plugin.currentConf().SensitiveConfigFiles = []string{"payments-*.yaml"}
+	assert.True(t, plugin.isSensitiveConfig("payments-prod.yaml"))
+	assert.False(t, plugin.isSensitiveConfig("db-secrets.yaml"))
+}
+
+func TestRedactEvent(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", Token: "config-token1"})
+	events, _ := plugin.SubscribeEvents(EventFilter{})
+
+	diff := []ConfigDiffLine{{Kind: ConfigLineAdded, Text: "token: config-token1"}}
+	plugin.publishEvent(&ConfigChangedEvent{Kind: EventTypeConfigChanged, FileName: "app.yaml", Diff: diff})
+	plugin.publishEvent(&ConfigChangedEvent{Kind: EventTypeConfigChanged, FileName: "db-secret.yaml", Diff: diff})
+	plugin.publishEvent(&DegradationEvent{Kind: EventTypeDegradation, Error: "denied for config-token1"})
+
+	changed := receiveEvent(t, events).(*ConfigChangedEvent)
+	assert.Equal(t, "token: [REDACTED]", changed.Diff[0].Text)
+	assert.Equal(t, "token: config-token1", diff[0].Text, "published events are copied")
+	sensitive := receiveEvent(t, events).(*ConfigChangedEvent)
+	assert.Nil(t, sensitive.Diff)
+	assert.True(t, sensitive.Redacted)
+	assert.Equal(t, "denied for [REDACTED]", receiveEvent(t, events).(*DegradationEvent).Error)
+}
+
+func receiveEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("event not published")
+		return nil
+	}
+}
+
+func TestRedact_AuditAlertsAndStats(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", Token: "config-token1"})
+	sink := &recordingAuditSink{}
+	plugin.SetAuditSink(sink)
+	alerter, alerts := alertChannel()
+	require.NoError(t, plugin.AddAlerter("test", alerter, ""))
+
+	plugin.recordServiceWatchErrorAudit("orders", errors.New("token config-token1 rejected"))
+	require.Len(t, sink.events, 1)
+	assert.Equal(t, "token [REDACTED] rejected", sink.events[0].Error)
+
+	plugin.sendServiceWatchAlert("orders", errors.New("token config-token1 rejected"))
+	alert := receiveAlert(t, alerts)
+	assert.NotContains(t, alert.Message, "config-token1")
+	assert.Equal(t, "token [REDACTED] rejected", alert.Error)
+
+	plugin.recordConfigFetch("db-secret.yaml", "DEFAULT_GROUP", "password: hunter2")
+	plugin.recordConfigFetch("app.yaml", "DEFAULT_GROUP", "a: 1")
+	for _, freshness := range plugin.ConfigFreshness() {
+		if freshness.FileName == "db-secret.yaml" {
+			assert.Equal(t, redactedValue, freshness.Version)
+		} else {
+			assert.Equal(t, contentVersion("a: 1"), freshness.Version)
+		}
+	}
+}
+
+func TestValidator_SensitiveConfigFiles(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, SensitiveConfigFiles: []string{"*secret*", "[broken"}}
+	var invalid []any
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		if e.Field == "sensitive_config_files" {
+			invalid = append(invalid, e.Value)
+		}
+	}
+	assert.Equal(t, []any{"[broken"}, invalid)
+}
@@ -2,9 +2,11 @@ package polaris
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -26,28 +28,36 @@ func (p *PlugPolaris) NewServiceRegistry() registry.Registrar {
 		log.Warnf("Polaris plugin not initialized, returning nil registrar: %v", err)
 		return nil
 	}
+	if !p.SubsystemEnabled(SubsystemRegistration) {
+		log.Infof("Polaris registration subsystem disabled, returning nil registrar")
+		return nil
+	}
 
 	p.mu.RLock()
-	sdk := p.sdk
 	namespace := ""
-	if p.conf != nil {
-		namespace = p.conf.Namespace
+	if cfg := p.currentConf(); cfg != nil {
+		namespace = cfg.Namespace
 	}
+	metrics := p.metrics
 	p.mu.RUnlock()
-	if sdk == nil {
+	providerAPI := p.providerClient()
+	if providerAPI == nil {
 		log.Warnf("Polaris SDK is nil, returning nil registrar")
 		return nil
 	}
 
-	// Create Provider API client
-	providerAPI := api.NewProviderAPIByContext(sdk)
-	if providerAPI == nil {
-		log.Errorf("Failed to create provider API")
-		return nil
+	// Return Polaris-based service registrar, registering with the configured weight
+	registrar := NewPolarisRegistrar(providerAPI, namespace)
+	registrar.weight = p.warmUpWeight(p.instanceBaseWeight(), time.Time{}, time.Now())
+	registrar.hooks = p.registrationHooks()
+	registrar.advertiseHost = p.DetectHostIP
+	registrar.metrics = metrics
+	registrar.audit = p.registrationAudit()
+	registrar.serviceToken = p.currentToken
+	if cfg, ttl := p.heartbeatConfig(); cfg.GetEnabled() {
+		registrar.ttl = ttl
 	}
-
-	// Return Polaris-based service registrar
-	return NewPolarisRegistrar(providerAPI, namespace)
+	return registrar
 }
 
 // NewServiceDiscovery implements ServiceRegistry interface
@@ -56,29 +66,30 @@ func (p *PlugPolaris) NewServiceDiscovery() registry.Discovery {
 		log.Warnf("Polaris plugin not initialized, returning nil discovery: %v", err)
 		return nil
 	}
+	if !p.SubsystemEnabled(SubsystemDiscovery) {
+		log.Infof("Polaris discovery subsystem disabled, returning nil discovery")
+		return nil
+	}
 
 	p.mu.RLock()
-	sdk := p.sdk
-	cfg := p.conf
+	cfg := p.currentConf()
 	namespace := ""
-	if p.conf != nil {
-		namespace = p.conf.Namespace
+	if cfg := p.currentConf(); cfg != nil {
+		namespace = cfg.Namespace
 	}
 	p.mu.RUnlock()
-	if sdk == nil {
-		log.Warnf("Polaris SDK is nil, returning nil discovery")
-		return nil
-	}
-
-	// Create Consumer API client
-	consumerAPI := api.NewConsumerAPIByContext(sdk)
+	consumerAPI := p.consumerClient()
 	if consumerAPI == nil {
-		log.Errorf("Failed to create consumer API")
+		log.Warnf("Polaris SDK is nil, returning nil discovery")
 		return nil
 	}
 
 	// Return Polaris-based service discovery client with configurable watch interval and retry policy
-	return NewPolarisDiscovery(consumerAPI, namespace, cfg)
+	discovery := NewPolarisDiscovery(consumerAPI, namespace, cfg)
+	discovery.fallback = p.discoveryFallbackInstances
+	discovery.routeFallback = p.routeFallback
+	discovery.clock = p.clock
+	return discovery
 }
 
 // parseEndpoints parses endpoint information
@@ -122,22 +133,138 @@ func parseEndpoints(endpoints []string) (host string, port int, protocol string)
 // PolarisRegistrar Polaris-based service registrar
 // Implements Kratos registry.Registrar interface
 type PolarisRegistrar struct {
-	provider  api.ProviderAPI
+	provider  ProviderClient
 	namespace string
 	instances map[string]*registry.ServiceInstance
-	mu        sync.RWMutex
+	unhealthy map[string]bool // instance keys last registered as unhealthy
+	// pinned holds the TTL and weight set by RegisterOptions for instance keys, kept when
+	// the instances are registered again
+	pinned   map[string]registerOptions
+	weight   int
+	isolated bool // registrations are isolated from traffic
+	ttl      int  // heartbeat TTL in seconds registered with instances; zero disables health checks
+	hooks    *registrationHooks
+	metrics  *Metrics // records registration outcomes and latency; nil disables
+	// audit records the endpoints registered and deregistered; nil disables
+	audit func(AuditEventType, *registry.ServiceInstance)
+	// advertiseHost detects the host registered for empty or unspecified endpoint hosts
+	advertiseHost func() (string, error)
+	// serviceToken returns the token sent with registrations, deregistrations and heartbeats;
+	// nil sends none
+	serviceToken func() string
+	// registeredAt is when the registrar went from no instances to at least one
+	registeredAt time.Time
+	mu           sync.RWMutex
 }
 
 // NewPolarisRegistrar creates new Polaris registrar
-func NewPolarisRegistrar(provider api.ProviderAPI, namespace string) *PolarisRegistrar {
+func NewPolarisRegistrar(provider ProviderClient, namespace string) *PolarisRegistrar {
 	return &PolarisRegistrar{
 		provider:  provider,
 		namespace: namespace,
 		instances: make(map[string]*registry.ServiceInstance),
+		unhealthy: make(map[string]bool),
+		pinned:    make(map[string]registerOptions),
+		weight:    conf.DefaultWeight,
+	}
+}
+
+const (
+	// endpointProtocolMetadataKey is the instance metadata key carrying the protocol of an endpoint.
+	endpointProtocolMetadataKey = "protocol"
+	// instanceIDMetadataKey is the instance metadata key carrying the caller's instance ID.
+	// Polaris derives its own instance ID from namespace, service, host and port.
+	instanceIDMetadataKey = "instance_id"
+)
+
+// registrationEndpoints returns the distinct endpoints of a service instance. Each endpoint
+// is registered as its own Polaris instance. An instance without endpoints registers the
+// default endpoint, as before multi-endpoint support.
+func registrationEndpoints(endpoints []string) []string {
+	seen := make(map[string]struct{}, len(endpoints))
+	var result []string
+	for _, endpoint := range endpoints {
+		endpoint = strings.TrimSpace(endpoint)
+		if endpoint == "" {
+			continue
+		}
+		if _, ok := seen[endpoint]; ok {
+			continue
+		}
+		seen[endpoint] = struct{}{}
+		result = append(result, endpoint)
 	}
+	if len(result) == 0 {
+		return []string{""}
+	}
+	return result
 }
 
-// Register registers service instance
+// endpointInstance returns a copy of service narrowed to a single endpoint, with the
+// endpoint protocol and the service instance ID recorded in its metadata.
+func endpointInstance(service *registry.ServiceInstance, endpoint, protocol string) *registry.ServiceInstance {
+	clone := cloneRegistryServiceInstance(service)
+	if endpoint != "" {
+		clone.Endpoints = []string{endpoint}
+	}
+	if clone.Metadata == nil {
+		clone.Metadata = make(map[string]string, 2)
+	}
+	clone.Metadata[endpointProtocolMetadataKey] = protocol
+	if service.ID != "" {
+		clone.Metadata[instanceIDMetadataKey] = service.ID
+	}
+	return clone
+}
+
+// advertisedEndpoint replaces an empty or unspecified host (0.0.0.0, ::) in endpoint with the
+// detected advertise address. The endpoint is unchanged when no address can be detected.
+func (r *PolarisRegistrar) advertisedEndpoint(endpoint string) string {
+	if r.advertiseHost == nil {
+		return endpoint
+	}
+	raw := endpoint
+	if u, err := url.Parse(endpoint); err == nil && u.Scheme != "" {
+		raw = u.Host
+	}
+	if h, _, err := net.SplitHostPort(raw); err == nil {
+		raw = h
+	}
+	if endpoint != "" && !isUnspecifiedHost(raw) {
+		return endpoint
+	}
+	ip, err := r.advertiseHost()
+	if err != nil {
+		log.Warnf("Failed to detect advertise address for endpoint %q: %v", endpoint, err)
+		return endpoint
+	}
+	_, port, protocol := parseEndpoints([]string{endpoint})
+	return formatEndpoint(protocol, ip, int32(port))
+}
+
+// advertisedInstance returns service with every endpoint passed through advertisedEndpoint.
+func (r *PolarisRegistrar) advertisedInstance(service *registry.ServiceInstance) *registry.ServiceInstance {
+	if r.advertiseHost == nil {
+		return service
+	}
+	clone := cloneRegistryServiceInstance(service)
+	endpoints := clone.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = []string{""}
+	}
+	clone.Endpoints = make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		clone.Endpoints = append(clone.Endpoints, r.advertisedEndpoint(endpoint))
+	}
+	return clone
+}
+
+// Register registers service instance. Every endpoint (e.g. HTTP 8080 and gRPC 9090) is
+// registered as a separate Polaris instance of the same service with its own protocol, so
+// each one is health checked independently. If any endpoint fails, the endpoints registered
+// so far are rolled back. Registration hooks run around each endpoint; a failing
+// before-register hook aborts the registration in the same way. Options set on ctx by
+// WithRegisterOptions override the TTL, weight, metadata and protocol.
 func (r *PolarisRegistrar) Register(ctx context.Context, service *registry.ServiceInstance) error {
 	if service == nil {
 		return fmt.Errorf("service instance is nil")
@@ -147,39 +274,118 @@ func (r *PolarisRegistrar) Register(ctx context.Context, service *registry.Servi
 			return err
 		}
 	}
+	options := registerOptionsFromContext(ctx)
+	if err := options.validate(); err != nil {
+		return err
+	}
 
-	host, port, protocol := parseEndpoints(service.Endpoints)
+	service = r.advertisedInstance(options.apply(service))
+	var registered []*registry.ServiceInstance
+	rollback := func() {
+		for _, done := range registered {
+			if derr := r.deregisterEndpoint(ctx, done); derr != nil {
+				log.Warnf("Failed to roll back registration of service %s at %v: %v", done.Name, done.Endpoints, derr)
+			}
+		}
+	}
+	for _, endpoint := range registrationEndpoints(service.Endpoints) {
+		_, _, protocol := parseEndpoints([]string{endpoint})
+		if err := r.hooks.runBefore(ctx, endpointInstance(service, endpoint, protocol)); err != nil {
+			rollback()
+			return fmt.Errorf("before-register hook rejected service %s at %s: %w", service.Name, endpoint, err)
+		}
+		instance, err := r.registerEndpoint(ctx, service, endpoint, true)
+		if err != nil {
+			rollback()
+			return err
+		}
+		registered = append(registered, instance)
+		r.recordAudit(AuditServiceRegistered, instance)
+		r.hooks.runAfter(ctx, instance)
+	}
+	return nil
+}
+
+// registerEndpoint registers a single endpoint of service and tracks it.
+func (r *PolarisRegistrar) registerEndpoint(ctx context.Context, service *registry.ServiceInstance, endpoint string, healthy bool) (*registry.ServiceInstance, error) {
+	host, port, protocol := parseEndpoints([]string{endpoint})
+	instance := endpointInstance(service, endpoint, protocol)
+	priority, err := registrationPriority(instance.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	instanceKey := fmt.Sprintf("%s:%s:%d", service.Name, host, port)
+	options := registerOptionsFromContext(ctx)
+	r.mu.RLock()
+	pinned := options.pin(r.pinned[instanceKey])
+	weight := r.weight
+	if pinned.weight != nil {
+		weight = *pinned.weight
+	}
+	ttl := r.ttl
+	if pinned.ttl != nil {
+		ttl = *pinned.ttl
+	}
+	isolated := r.isolated
+	r.mu.RUnlock()
+	var ttlPtr *int
+	if ttl > 0 {
+		ttlPtr = &ttl
+	}
 
 	req := &api.InstanceRegisterRequest{
 		InstanceRegisterRequest: model.InstanceRegisterRequest{
-			Service:   service.Name,
-			Namespace: r.namespace,
-			Host:      host,
-			Port:      port,
-			Protocol:  &protocol,
-			Version:   &service.Version,
-			Metadata:  service.Metadata,
-			Weight:    &[]int{100}[0],
-			Healthy:   &[]bool{true}[0],
-			Isolate:   &[]bool{false}[0],
+			Service:      service.Name,
+			Namespace:    r.namespace,
+			Host:         host,
+			Port:         port,
+			Protocol:     &protocol,
+			Version:      &service.Version,
+			Metadata:     instance.Metadata,
+			Weight:       &weight,
+			Priority:     priority,
+			Healthy:      &healthy,
+			Isolate:      &isolated,
+			TTL:          ttlPtr,
+			ServiceToken: r.requestToken(ctx),
 		},
 	}
 
-	_, err := r.provider.Register(req)
+	start := time.Now()
+	_, err = r.providerAPI().Register(req)
+	if r.metrics != nil {
+		r.metrics.RecordServiceRegistrationDuration(service.Name, r.namespace, time.Since(start).Seconds())
+		if err != nil {
+			r.metrics.RecordServiceRegistration(service.Name, r.namespace, "error")
+			r.metrics.RecordOperationError("register", err)
+		} else {
+			r.metrics.RecordServiceRegistration(service.Name, r.namespace, "success")
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("failed to register service %s: %w", service.Name, err)
+		return nil, fmt.Errorf("failed to register service %s at %s:%d: %w", service.Name, host, port, TranslateSDKError(err))
 	}
 
-	instanceKey := fmt.Sprintf("%s:%s:%d", service.Name, host, port)
 	r.mu.Lock()
-	r.instances[instanceKey] = cloneRegistryServiceInstance(service)
+	if len(r.instances) == 0 {
+		r.registeredAt = time.Now()
+	}
+	r.instances[instanceKey] = instance
+	if pinned.weight != nil || pinned.ttl != nil {
+		r.pinned[instanceKey] = pinned
+	}
+	if healthy {
+		delete(r.unhealthy, instanceKey)
+	} else {
+		r.unhealthy[instanceKey] = true
+	}
 	r.mu.Unlock()
 
-	log.Infof("Successfully registered service %s at %s:%d", service.Name, host, port)
-	return nil
+	log.Infof("Successfully registered service %s at %s:%d (%s)", service.Name, host, port, protocol)
+	return instance, nil
 }
 
-// Deregister deregisters service instance
+// Deregister deregisters service instance, including every endpoint registered for it.
 func (r *PolarisRegistrar) Deregister(ctx context.Context, service *registry.ServiceInstance) error {
 	if service == nil {
 		return fmt.Errorf("service instance is nil")
@@ -190,31 +396,200 @@ func (r *PolarisRegistrar) Deregister(ctx context.Context, service *registry.Ser
 		}
 	}
 
-	host, port, _ := parseEndpoints(service.Endpoints)
+	service = r.advertisedInstance(service)
+	var errs []error
+	for _, endpoint := range registrationEndpoints(service.Endpoints) {
+		instance := endpointInstance(service, endpoint, "")
+		if err := r.deregisterEndpoint(ctx, instance); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deregisterEndpoint deregisters a single-endpoint instance, stops tracking it and runs the
+// deregister hooks.
+func (r *PolarisRegistrar) deregisterEndpoint(ctx context.Context, instance *registry.ServiceInstance) error {
+	host, port, _ := parseEndpoints(instance.Endpoints)
 
 	req := &api.InstanceDeRegisterRequest{
 		InstanceDeRegisterRequest: model.InstanceDeRegisterRequest{
-			Service:   service.Name,
-			Namespace: r.namespace,
-			Host:      host,
-			Port:      port,
+			Service:      instance.Name,
+			Namespace:    r.namespace,
+			Host:         host,
+			Port:         port,
+			ServiceToken: r.requestToken(ctx),
 		},
 	}
 
-	err := r.provider.Deregister(req)
+	err := r.providerAPI().Deregister(req)
 	if err != nil {
-		return fmt.Errorf("failed to deregister service %s: %w", service.Name, err)
+		return fmt.Errorf("failed to deregister service %s at %s:%d: %w", instance.Name, host, port, TranslateSDKError(err))
 	}
 
-	instanceKey := fmt.Sprintf("%s:%s:%d", service.Name, host, port)
+	instanceKey := fmt.Sprintf("%s:%s:%d", instance.Name, host, port)
 	r.mu.Lock()
 	delete(r.instances, instanceKey)
+	delete(r.unhealthy, instanceKey)
+	delete(r.pinned, instanceKey)
+	if len(r.instances) == 0 {
+		r.registeredAt = time.Time{}
+	}
 	r.mu.Unlock()
 
-	log.Infof("Successfully deregistered service %s at %s:%d", service.Name, host, port)
+	log.Infof("Successfully deregistered service %s at %s:%d", instance.Name, host, port)
+	r.recordAudit(AuditServiceDeregistered, instance)
+	r.hooks.runDeregister(ctx, instance)
 	return nil
 }
 
+// recordAudit records an audit event of eventType for instance
+func (r *PolarisRegistrar) recordAudit(eventType AuditEventType, instance *registry.ServiceInstance) {
+	if r.audit != nil {
+		r.audit(eventType, instance)
+	}
+}
+
+// SetEndpointHealthy reports the health of a single registered endpoint of service by
+// re-registering it with the given health status. Other endpoints are unaffected.
+func (r *PolarisRegistrar) SetEndpointHealthy(ctx context.Context, service *registry.ServiceInstance, endpoint string, healthy bool) error {
+	if service == nil {
+		return fmt.Errorf("service instance is nil")
+	}
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	endpoint = r.advertisedEndpoint(endpoint)
+	host, port, _ := parseEndpoints([]string{endpoint})
+	instanceKey := fmt.Sprintf("%s:%s:%d", service.Name, host, port)
+	r.mu.RLock()
+	_, ok := r.instances[instanceKey]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("endpoint %s of service %s is not registered", endpoint, service.Name)
+	}
+	_, err := r.registerEndpoint(ctx, service, endpoint, healthy)
+	return err
+}
+
+// registeredSince returns when the registrar started tracking instances, or the zero
+// time when it tracks none.
+func (r *PolarisRegistrar) registeredSince() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.registeredAt
+}
+
+// Weight returns the weight used for registrations.
+func (r *PolarisRegistrar) Weight() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.weight
+}
+
+// SetWeight changes the weight used for registrations and re-registers every tracked
+// instance with it, keeping each instance's last reported health.
+func (r *PolarisRegistrar) SetWeight(ctx context.Context, weight int) error {
+	if weight < 0 || weight > conf.MaxWeight {
+		return NewConfigError(fmt.Sprintf("weight must be between 0 and %d", conf.MaxWeight))
+	}
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	if r.weight == weight {
+		r.mu.Unlock()
+		return nil
+	}
+	r.weight = weight
+	r.mu.Unlock()
+	return r.reregister(ctx)
+}
+
+// setTTL changes the heartbeat TTL used for registrations and re-registers every tracked
+// instance with it. Zero disables health checks.
+func (r *PolarisRegistrar) setTTL(ctx context.Context, ttl int) error {
+	r.mu.Lock()
+	if r.ttl == ttl {
+		r.mu.Unlock()
+		return nil
+	}
+	r.ttl = ttl
+	r.mu.Unlock()
+	return r.reregister(ctx)
+}
+
+// Isolated reports whether registrations are isolated from traffic.
+func (r *PolarisRegistrar) Isolated() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.isolated
+}
+
+// SetIsolated changes the isolated flag used for registrations and re-registers every
+// tracked instance with it. Isolated instances stay registered and keep heartbeating but
+// receive no traffic.
+func (r *PolarisRegistrar) SetIsolated(ctx context.Context, isolated bool) error {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	if r.isolated == isolated {
+		r.mu.Unlock()
+		return nil
+	}
+	r.isolated = isolated
+	r.mu.Unlock()
+	return r.reregister(ctx)
+}
+
+// reregister registers every tracked instance again with the current weight and isolated
+// flag, keeping each instance's last reported health. ctx carries the token override of
+// WithToken.
+func (r *PolarisRegistrar) reregister(ctx context.Context) error {
+	return r.registerAll(ctx, r.tracked())
+}
+
+// trackedInstance is an instance tracked by a registrar with its last reported health
+type trackedInstance struct {
+	instance *registry.ServiceInstance
+	healthy  bool
+}
+
+// tracked returns the instances tracked by the registrar.
+func (r *PolarisRegistrar) tracked() []trackedInstance {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	instances := make([]trackedInstance, 0, len(r.instances))
+	for key, instance := range r.instances {
+		instances = append(instances, trackedInstance{instance: instance, healthy: !r.unhealthy[key]})
+	}
+	return instances
+}
+
+// registerAll registers instances at their endpoints with their health, tracking them.
+func (r *PolarisRegistrar) registerAll(ctx context.Context, instances []trackedInstance) error {
+	var errs []error
+	for _, t := range instances {
+		endpoint := ""
+		if len(t.instance.Endpoints) > 0 {
+			endpoint = t.instance.Endpoints[0]
+		}
+		if _, err := r.registerEndpoint(ctx, t.instance, endpoint, t.healthy); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Close deregisters all instances tracked by this registrar.
 // It must be called before the underlying SDK context is destroyed so that
 // in-flight Kratos shutdown does not deregister through a destroyed SDK.
@@ -222,9 +597,12 @@ func (r *PolarisRegistrar) Close(ctx context.Context) {
 	r.mu.Lock()
 	instances := r.instances
 	r.instances = make(map[string]*registry.ServiceInstance)
+	r.unhealthy = make(map[string]bool)
+	r.registeredAt = time.Time{}
 	r.mu.Unlock()
 
-	if r.provider == nil {
+	provider := r.providerAPI()
+	if provider == nil {
 		return
 	}
 	for _, instance := range instances {
@@ -234,20 +612,156 @@ func (r *PolarisRegistrar) Close(ctx context.Context) {
 		host, port, _ := parseEndpoints(instance.Endpoints)
 		req := &api.InstanceDeRegisterRequest{
 			InstanceDeRegisterRequest: model.InstanceDeRegisterRequest{
-				Service:   instance.Name,
-				Namespace: r.namespace,
-				Host:      host,
-				Port:      port,
+				Service:      instance.Name,
+				Namespace:    r.namespace,
+				Host:         host,
+				Port:         port,
+				ServiceToken: r.requestToken(ctx),
 			},
 		}
-		if err := r.provider.Deregister(req); err != nil {
+		if err := provider.Deregister(req); err != nil {
 			log.Warnf("Failed to deregister service %s at %s:%d during shutdown: %v", instance.Name, host, port, err)
 			continue
 		}
 		log.Infof("Deregistered service %s at %s:%d during shutdown", instance.Name, host, port)
+		r.recordAudit(AuditServiceDeregistered, instance)
+		r.hooks.runDeregister(ctx, instance)
 	}
 }
 
+// instanceLookup returns the instances the registry currently knows for a service.
+type instanceLookup func(service string) ([]model.Instance, error)
+
+// reregisterMissing looks up every tracked instance through lookup and registers the ones
+// the registry no longer knows again, with their last reported health. Services whose
+// lookup fails are skipped, since the registry is likely unreachable. It returns the
+// re-registered instances.
+func (r *PolarisRegistrar) reregisterMissing(lookup instanceLookup) ([]*registry.ServiceInstance, error) {
+	type tracked struct {
+		instance *registry.ServiceInstance
+		healthy  bool
+	}
+	r.mu.RLock()
+	byService := make(map[string]map[string]tracked)
+	for key, instance := range r.instances {
+		if byService[instance.Name] == nil {
+			byService[instance.Name] = make(map[string]tracked)
+		}
+		byService[instance.Name][key] = tracked{instance: instance, healthy: !r.unhealthy[key]}
+	}
+	r.mu.RUnlock()
+
+	var errs []error
+	var reregistered []*registry.ServiceInstance
+	for service, instances := range byService {
+		known, err := lookup(service)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to look up service %s: %w", service, err))
+			continue
+		}
+		present := make(map[string]struct{}, len(known))
+		for _, inst := range known {
+			if inst != nil {
+				present[fmt.Sprintf("%s:%s:%d", service, inst.GetHost(), inst.GetPort())] = struct{}{}
+			}
+		}
+		for key, t := range instances {
+			if _, ok := present[key]; ok {
+				continue
+			}
+			r.mu.RLock()
+			_, stillTracked := r.instances[key]
+			r.mu.RUnlock()
+			if !stillTracked {
+				continue
+			}
+			endpoint := ""
+			if len(t.instance.Endpoints) > 0 {
+				endpoint = t.instance.Endpoints[0]
+			}
+			log.Warnf("Instance %s is missing from the registry, registering it again", key)
+			instance, err := r.registerEndpoint(context.Background(), t.instance, endpoint, t.healthy)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			reregistered = append(reregistered, instance)
+		}
+	}
+	return reregistered, errors.Join(errs...)
+}
+
+// heartbeatTarget is a tracked instance a heartbeat is sent for.
+type heartbeatTarget struct {
+	key     string
+	service string
+	host    string
+	port    int
+}
+
+// heartbeatTargets returns the tracked instances, sorted by key.
+func (r *PolarisRegistrar) heartbeatTargets() []heartbeatTarget {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	targets := make([]heartbeatTarget, 0, len(r.instances))
+	for key, instance := range r.instances {
+		host, port, _ := parseEndpoints(instance.Endpoints)
+		targets = append(targets, heartbeatTarget{key: key, service: instance.Name, host: host, port: port})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].key < targets[j].key })
+	return targets
+}
+
+// heartbeat sends a heartbeat for target.
+func (r *PolarisRegistrar) heartbeat(target heartbeatTarget) error {
+	return r.providerAPI().Heartbeat(&api.InstanceHeartbeatRequest{
+		InstanceHeartbeatRequest: model.InstanceHeartbeatRequest{
+			Service:      target.service,
+			Namespace:    r.namespace,
+			Host:         target.host,
+			Port:         target.port,
+			ServiceToken: r.token(),
+		},
+	})
+}
+
+// token returns the token sent with registrar requests.
+func (r *PolarisRegistrar) token() string {
+	if r.serviceToken == nil {
+		return ""
+	}
+	return r.serviceToken()
+}
+
+// requestToken returns the token set on ctx by WithToken, falling back to the registrar token.
+func (r *PolarisRegistrar) requestToken(ctx context.Context) string {
+	if token, ok := tokenFromContext(ctx); ok {
+		return token
+	}
+	return r.token()
+}
+
+// providerAPI returns the provider API registrations go through.
+func (r *PolarisRegistrar) providerAPI() ProviderClient {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.provider
+}
+
+// setProvider switches the registrar to provider, e.g. after the SDK context is rebuilt.
+func (r *PolarisRegistrar) setProvider(provider ProviderClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.provider = provider
+}
+
+// hasInstances reports whether the registrar tracks any registered instance.
+func (r *PolarisRegistrar) hasInstances() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.instances) > 0
+}
+
 // GetService gets service information (implements Discovery interface)
 func (r *PolarisRegistrar) GetService(ctx context.Context, name string) ([]*registry.ServiceInstance, error) {
 	r.mu.RLock()
@@ -289,19 +803,27 @@ func (r *PolarisRegistrar) Watch(ctx context.Context, name string) (registry.Wat
 // PolarisDiscovery Polaris-based service discovery client
 // Implements Kratos registry.Discovery interface
 type PolarisDiscovery struct {
-	consumer      api.ConsumerAPI
+	consumer      ConsumerClient
+	consumerMu    sync.RWMutex
 	namespace     string
 	watchInterval time.Duration
 	enableRetry   bool
 	maxRetryTimes int
 	baseRetry     time.Duration
+	// fallback returns cached or static instances when GetInstances fails (optional)
+	fallback func(name string) []model.Instance
+	// routeFallback returns the fallback target instances when name has no healthy instances (optional)
+	routeFallback func(name string) []model.Instance
+	// clock drives the polling and retry backoff of watchers
+	clock Clock
 }
 
 // NewPolarisDiscovery creates new Polaris discovery client
-func NewPolarisDiscovery(consumer api.ConsumerAPI, namespace string, cfg *conf.Polaris) *PolarisDiscovery {
+func NewPolarisDiscovery(consumer ConsumerClient, namespace string, cfg *conf.Polaris) *PolarisDiscovery {
 	pd := &PolarisDiscovery{
 		consumer:  consumer,
 		namespace: namespace,
+		clock:     SystemClock,
 	}
 	// Configure watch interval and retry policy from cfg (with sane defaults)
 	if cfg != nil {
@@ -331,9 +853,25 @@ func NewPolarisDiscovery(consumer api.ConsumerAPI, namespace string, cfg *conf.P
 	return pd
 }
 
+// consumerAPI returns the consumer API lookups go through.
+func (d *PolarisDiscovery) consumerAPI() ConsumerClient {
+	d.consumerMu.RLock()
+	defer d.consumerMu.RUnlock()
+	return d.consumer
+}
+
+// setConsumer switches the discovery to consumer, e.g. after the SDK context is rebuilt.
+// Watchers opened before keep their consumer.
+func (d *PolarisDiscovery) setConsumer(consumer ConsumerClient) {
+	d.consumerMu.Lock()
+	defer d.consumerMu.Unlock()
+	d.consumer = consumer
+}
+
 // GetService gets service instance list
 func (d *PolarisDiscovery) GetService(ctx context.Context, name string) ([]*registry.ServiceInstance, error) {
-	if d.consumer == nil {
+	consumer := d.consumerAPI()
+	if consumer == nil {
 		return nil, fmt.Errorf("polaris consumer API is not initialized")
 	}
 	req := &api.GetInstancesRequest{
@@ -343,33 +881,65 @@ func (d *PolarisDiscovery) GetService(ctx context.Context, name string) ([]*regi
 		},
 	}
 
-	resp, err := d.consumer.GetInstances(req)
+	resp, err := consumer.GetInstances(req)
 	if err != nil {
+		if d.fallback != nil {
+			if fallback := d.fallback(name); len(fallback) > 0 {
+				return toRegistryServiceInstances(name, fallback), nil
+			}
+		}
+		if fallback := d.routeFallbackInstances(name); len(fallback) > 0 {
+			return toRegistryServiceInstances(name, fallback), nil
+		}
 		return nil, fmt.Errorf("failed to get service instances for %s: %w", name, err)
 	}
 
+	if len(healthyInstances(resp.Instances)) == 0 {
+		if fallback := d.routeFallbackInstances(name); len(fallback) > 0 {
+			return toRegistryServiceInstances(name, fallback), nil
+		}
+	}
+	return toRegistryServiceInstances(name, resp.Instances), nil
+}
+
+// routeFallbackInstances returns the instances of the route fallback target of name, if any.
+func (d *PolarisDiscovery) routeFallbackInstances(name string) []model.Instance {
+	if d.routeFallback == nil {
+		return nil
+	}
+	return d.routeFallback(name)
+}
+
+// toRegistryServiceInstances converts Polaris instances into Kratos registry instances.
+func toRegistryServiceInstances(name string, polarisInstances []model.Instance) []*registry.ServiceInstance {
 	var instances []*registry.ServiceInstance
-	for _, instance := range resp.Instances {
+	for _, instance := range polarisInstances {
 		if instance == nil {
 			continue
 		}
-		endpoint := fmt.Sprintf("%s://%s:%d", instance.GetProtocol(), instance.GetHost(), instance.GetPort())
-
-		instances = append(instances, &registry.ServiceInstance{
-			ID:        instance.GetId(),
-			Name:      name,
-			Version:   instance.GetVersion(),
-			Metadata:  instance.GetMetadata(),
-			Endpoints: []string{endpoint},
-		})
+		instances = append(instances, toRegistryServiceInstance(name, instance))
 	}
 
-	return instances, nil
+	return instances
+}
+
+// toRegistryServiceInstance converts a single Polaris instance into a Kratos registry instance.
+func toRegistryServiceInstance(name string, instance model.Instance) *registry.ServiceInstance {
+	endpoint := fmt.Sprintf("%s://%s:%d", instance.GetProtocol(), instance.GetHost(), instance.GetPort())
+
+	return &registry.ServiceInstance{
+		ID:        instance.GetId(),
+		Name:      name,
+		Version:   instance.GetVersion(),
+		Metadata:  instanceMetadata(instance),
+		Endpoints: []string{endpoint},
+	}
 }
 
 // Watch watches service changes
 func (d *PolarisDiscovery) Watch(ctx context.Context, name string) (registry.Watcher, error) {
-	if d.consumer == nil {
+	consumer := d.consumerAPI()
+	if consumer == nil {
 		return nil, fmt.Errorf("polaris consumer API is not initialized")
 	}
 	req := &api.WatchServiceRequest{
@@ -381,7 +951,7 @@ func (d *PolarisDiscovery) Watch(ctx context.Context, name string) (registry.Wat
 		},
 	}
 
-	resp, err := d.consumer.WatchService(req)
+	resp, err := consumer.WatchService(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to watch service %s: %w", name, err)
 	}
@@ -392,12 +962,13 @@ func (d *PolarisDiscovery) Watch(ctx context.Context, name string) (registry.Wat
 		cancel:       cancel,
 		name:         name,
 		response:     resp,
-		consumer:     d.consumer,
+		consumer:     consumer,
 		namespace:    d.namespace,
 		pollInterval: d.watchInterval,
 		enableRetry:  d.enableRetry,
 		maxRetries:   d.maxRetryTimes,
 		baseRetry:    d.baseRetry,
+		clock:        d.clock,
 	}, nil
 }
 
@@ -408,13 +979,14 @@ type PolarisWatcher struct {
 	cancel       context.CancelFunc
 	name         string
 	response     *model.WatchServiceResponse
-	consumer     api.ConsumerAPI
+	consumer     ConsumerClient
 	namespace    string
 	lastKey      string
 	pollInterval time.Duration
 	enableRetry  bool
 	maxRetries   int
 	baseRetry    time.Duration
+	clock        Clock
 }
 
 // Next gets next service change event
@@ -424,7 +996,8 @@ func (w *PolarisWatcher) Next() ([]*registry.ServiceInstance, error) {
 	if interval <= 0 {
 		interval = 5 * time.Second
 	}
-	ticker := time.NewTicker(interval)
+	clock := clockOrSystem(w.clock)
+	ticker := clock.NewTicker(interval)
 	defer ticker.Stop()
 
 	attempt := 0
@@ -433,7 +1006,7 @@ func (w *PolarisWatcher) Next() ([]*registry.ServiceInstance, error) {
 		select {
 		case <-w.ctx.Done():
 			return nil, w.ctx.Err()
-		case <-ticker.C:
+		case <-ticker.C():
 			if w.consumer == nil {
 				log.Warnf("polaris watcher consumer is nil, service=%s", w.name)
 				return []*registry.ServiceInstance{}, nil
@@ -465,10 +1038,12 @@ func (w *PolarisWatcher) Next() ([]*registry.ServiceInstance, error) {
 						return nil, fmt.Errorf("watch get instances failed after retries: %w", err)
 					}
 					log.Warnf("polaris watcher get instances failed, retrying in %s: service=%s, attempt=%d, err=%v", backoff, w.name, attempt, err)
+					timer := clock.NewTimer(backoff)
 					select {
 					case <-w.ctx.Done():
+						timer.Stop()
 						return nil, w.ctx.Err()
-					case <-time.After(backoff):
+					case <-timer.C():
 						continue
 					}
 				}
@@ -505,7 +1080,7 @@ func (w *PolarisWatcher) Next() ([]*registry.ServiceInstance, error) {
 						ID:        instance.GetId(),
 						Name:      w.name,
 						Version:   instance.GetVersion(),
-						Metadata:  instance.GetMetadata(),
+						Metadata:  instanceMetadata(instance),
 						Endpoints: []string{endpoint},
 					})
 				}
@@ -0,0 +1,325 @@
+package polaris
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// Registry builder module
+// Responsibility: Kratos registrars and discoveries bound to the plugin lifecycle, with the
+// plugin's retries, metrics and circuit breaking around every call, and per-registration
+// TTL, weight, metadata and protocol.
+
+// RegisterOption customizes registrations, either of every call of a registrar built by
+// BuildRegistrar or of a single call through WithRegisterOptions
+type RegisterOption func(*registerOptions)
+
+// registerOptions are the registration settings overridden by RegisterOptions
+type registerOptions struct {
+	ttl       *int
+	weight    *int
+	metadata  map[string]string
+	protocol  string
+	contracts []ServiceContract
+}
+
+// WithRegisterTTL registers instances with a heartbeat TTL of ttl seconds instead of the
+// plugin's. Zero registers them without health checks.
+func WithRegisterTTL(ttl int) RegisterOption {
+	return func(o *registerOptions) { o.ttl = &ttl }
+}
+
+// WithRegisterWeight registers instances with weight instead of the registrar weight. The
+// weight is kept when the instances are registered again, e.g. by SetWeight or warm-up.
+func WithRegisterWeight(weight int) RegisterOption {
+	return func(o *registerOptions) { o.weight = &weight }
+}
+
+// WithRegisterMetadata adds metadata to the registered instances. Entries of the instance
+// itself take precedence.
+func WithRegisterMetadata(metadata map[string]string) RegisterOption {
+	return func(o *registerOptions) {
+		if o.metadata == nil {
+			o.metadata = make(map[string]string, len(metadata))
+		}
+		maps.Copy(o.metadata, metadata)
+	}
+}
+
+// WithRegisterProtocol registers the endpoints without a scheme, e.g. "10.0.0.1:9000", with
+// protocol instead of http
+func WithRegisterProtocol(protocol string) RegisterOption {
+	return func(o *registerOptions) { o.protocol = protocol }
+}
+
+// WithRegisterContract reports contracts with ReportServiceContract after each successful
+// registration. Their service and version default to those of the registered instance. A
+// failed report is logged and does not fail the registration.
+func WithRegisterContract(contracts ...ServiceContract) RegisterOption {
+	return func(o *registerOptions) { o.contracts = append(o.contracts, contracts...) }
+}
+
+type registerOptionsContextKey struct{}
+
+// WithRegisterOptions returns a context making the registrations of the plugin's registrars
+// called with it apply opts, after those already set on ctx
+func WithRegisterOptions(ctx context.Context, opts ...RegisterOption) context.Context {
+	if len(opts) == 0 {
+		return ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	previous, _ := ctx.Value(registerOptionsContextKey{}).([]RegisterOption)
+	return context.WithValue(ctx, registerOptionsContextKey{}, append(slices.Clip(previous), opts...))
+}
+
+// registerOptionsFromContext returns the options set on ctx by WithRegisterOptions
+func registerOptionsFromContext(ctx context.Context) registerOptions {
+	var options registerOptions
+	if ctx == nil {
+		return options
+	}
+	opts, _ := ctx.Value(registerOptionsContextKey{}).([]RegisterOption)
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	return options
+}
+
+// validate checks the TTL and weight of the options
+func (o registerOptions) validate() error {
+	if o.weight != nil && (*o.weight < 0 || *o.weight > conf.MaxWeight) {
+		return NewConfigError(fmt.Sprintf("weight must be between 0 and %d", conf.MaxWeight))
+	}
+	if o.ttl != nil && *o.ttl < 0 {
+		return NewConfigError("ttl must not be negative")
+	}
+	return nil
+}
+
+// apply returns service with the metadata and protocol of the options, or service itself
+// when they set neither
+func (o registerOptions) apply(service *registry.ServiceInstance) *registry.ServiceInstance {
+	if len(o.metadata) == 0 && o.protocol == "" {
+		return service
+	}
+	clone := cloneRegistryServiceInstance(service)
+	if len(o.metadata) > 0 {
+		metadata := maps.Clone(o.metadata)
+		maps.Copy(metadata, clone.Metadata)
+		clone.Metadata = metadata
+	}
+	if o.protocol != "" {
+		for i, endpoint := range clone.Endpoints {
+			if endpoint = strings.TrimSpace(endpoint); endpoint != "" && !strings.Contains(endpoint, "://") {
+				clone.Endpoints[i] = o.protocol + "://" + endpoint
+			}
+		}
+	}
+	return clone
+}
+
+// pin returns the settings pinned for an instance after registering it with o on top of
+// the previously pinned ones
+func (o registerOptions) pin(previous registerOptions) registerOptions {
+	if o.ttl != nil {
+		previous.ttl = o.ttl
+	}
+	if o.weight != nil {
+		previous.weight = o.weight
+	}
+	return registerOptions{ttl: previous.ttl, weight: previous.weight}
+}
+
+// BuildRegistrar returns a Kratos registrar backed by the plugin's registrar, registering
+// with opts. Unlike NewServiceRegistry it can be built before the plugin starts and is never
+// nil: calls fail until the plugin is initialized, and go through the plugin's circuit
+// breaker and retries. Its instances are deregistered on shutdown and registered again
+// after self-healing and restarts, like those of the plugin's registrar.
+func (p *PlugPolaris) BuildRegistrar(opts ...RegisterOption) registry.Registrar {
+	return &pluginRegistrar{plugin: p, opts: slices.Clone(opts)}
+}
+
+// BuildDiscovery returns a Kratos discovery backed by the plugin. Unlike NewServiceDiscovery
+// it can be built before the plugin starts and is never nil: calls fail until the plugin is
+// initialized. GetService goes through GetServiceInstances, with its metrics, circuit
+// breaker, retries and fallbacks, and Watch through the plugin's discovery, which follows
+// self-healing and restarts.
+func (p *PlugPolaris) BuildDiscovery() registry.Discovery {
+	return &pluginDiscovery{plugin: p}
+}
+
+// pluginRegistrar is the registrar returned by BuildRegistrar
+type pluginRegistrar struct {
+	plugin *PlugPolaris
+	opts   []RegisterOption
+}
+
+// Register registers service through the plugin's registrar
+func (r *pluginRegistrar) Register(ctx context.Context, service *registry.ServiceInstance) error {
+	registrar, err := r.plugin.sharedRegistrar()
+	if err != nil {
+		return err
+	}
+	ctx = r.withOptions(ctx)
+	options := registerOptionsFromContext(ctx)
+	if err := options.validate(); err != nil {
+		return err
+	}
+	if err := r.plugin.protectRegistryCall(ctx, "register", func() error { return registrar.Register(ctx, service) }); err != nil {
+		return err
+	}
+	for _, contract := range options.contracts {
+		if contract.Version == "" {
+			contract.Version = service.Version
+		}
+		if err := r.plugin.reportServiceContract(ctx, contract, service.Name); err != nil {
+			log.Warnf("Failed to report %s contract of service %s: %v", contract.Protocol, service.Name, err)
+		}
+	}
+	return nil
+}
+
+// Deregister deregisters service through the plugin's registrar
+func (r *pluginRegistrar) Deregister(ctx context.Context, service *registry.ServiceInstance) error {
+	registrar, err := r.plugin.sharedRegistrar()
+	if err != nil {
+		return err
+	}
+	ctx = r.withOptions(ctx)
+	return r.plugin.protectRegistryCall(ctx, "deregister", func() error { return registrar.Deregister(ctx, service) })
+}
+
+// withOptions returns ctx with the registrar options applied before those set on ctx
+func (r *pluginRegistrar) withOptions(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(r.opts) == 0 {
+		return ctx
+	}
+	previous, _ := ctx.Value(registerOptionsContextKey{}).([]RegisterOption)
+	return context.WithValue(ctx, registerOptionsContextKey{}, append(slices.Clone(r.opts), previous...))
+}
+
+// pluginDiscovery is the discovery returned by BuildDiscovery
+type pluginDiscovery struct {
+	plugin *PlugPolaris
+}
+
+// GetService returns the instances of name from GetServiceInstances
+func (d *pluginDiscovery) GetService(ctx context.Context, name string) ([]*registry.ServiceInstance, error) {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	instances, err := d.plugin.GetServiceInstances(name)
+	if err != nil {
+		return nil, err
+	}
+	return toRegistryServiceInstances(name, instances), nil
+}
+
+// Watch watches name through the plugin's discovery
+func (d *pluginDiscovery) Watch(ctx context.Context, name string) (registry.Watcher, error) {
+	discovery, err := d.plugin.sharedDiscovery()
+	if err != nil {
+		return nil, err
+	}
+	var watcher registry.Watcher
+	err = d.plugin.protectRegistryCall(ctx, "watch", func() error {
+		var err error
+		watcher, err = discovery.Watch(ctx, name)
+		return err
+	})
+	return watcher, err
+}
+
+// sharedRegistrar returns the plugin's registrar, creating it on first use
+func (p *PlugPolaris) sharedRegistrar() (*PolarisRegistrar, error) {
+	if err := p.checkInitialized(); err != nil {
+		return nil, err
+	}
+	if err := p.checkSubsystem(SubsystemRegistration); err != nil {
+		return nil, err
+	}
+	p.mu.RLock()
+	registrar := p.registrar
+	p.mu.RUnlock()
+	if registrar != nil {
+		return registrar, nil
+	}
+	created, ok := p.NewServiceRegistry().(*PolarisRegistrar)
+	if !ok || created == nil {
+		return nil, NewInitError("Polaris provider API is not available")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.registrar == nil {
+		p.registrar = created
+	}
+	return p.registrar, nil
+}
+
+// sharedDiscovery returns the plugin's discovery, creating it on first use
+func (p *PlugPolaris) sharedDiscovery() (*PolarisDiscovery, error) {
+	if err := p.checkInitialized(); err != nil {
+		return nil, err
+	}
+	if err := p.checkSubsystem(SubsystemDiscovery); err != nil {
+		return nil, err
+	}
+	p.mu.RLock()
+	discovery := p.discovery
+	p.mu.RUnlock()
+	if discovery != nil {
+		return discovery, nil
+	}
+	created, ok := p.NewServiceDiscovery().(*PolarisDiscovery)
+	if !ok || created == nil {
+		return nil, NewInitError("Polaris consumer API is not available")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery == nil {
+		p.discovery = created
+	}
+	return p.discovery, nil
+}
+
+// protectRegistryCall runs operation through the plugin's circuit breaker and retries and
+// records its outcome as the SDK operation named operation
+func (p *PlugPolaris) protectRegistryCall(ctx context.Context, operation string, call func() error) error {
+	p.mu.RLock()
+	circuitBreaker, retryManager, metrics := p.circuitBreaker, p.retryManager, p.metrics
+	p.mu.RUnlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var err error
+	if circuitBreaker == nil || retryManager == nil {
+		err = call()
+	} else {
+		err = circuitBreaker.Do(func() error { return retryManager.DoWithRetryContext(ctx, call) })
+	}
+	if metrics != nil {
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		metrics.RecordSDKOperation(operation, status)
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package polaris
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// newBuilderTestPlugin returns an initialized plugin calling provider and consumer
+func newBuilderTestPlugin(t *testing.T, provider ProviderClient, consumer ConsumerClient) *PlugPolaris {
+	t.Helper()
+	return newTestPlugin(t, &conf.Polaris{Namespace: "default", RetryInterval: durationpb.New(time.Millisecond)},
+		WithProviderClient(provider), WithConsumerClient(consumer))
+}
+
+func TestBuildRegistrar_BeforeInitialization(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	registrar := plugin.BuildRegistrar()
+	require.NotNil(t, registrar)
+	err := registrar.Register(context.Background(), &registry.ServiceInstance{Name: "orders"})
+	assert.Error(t, err)
+	_, err = plugin.BuildDiscovery().GetService(context.Background(), "orders")
+	assert.Error(t, err)
+}
+
+func TestBuildRegistrar_Options(t *testing.T) {
+	provider := &fakeClients{registerFailures: 1}
+	plugin := newBuilderTestPlugin(t, provider, &fakeClients{})
+	registrar := plugin.BuildRegistrar(WithRegisterTTL(15), WithRegisterWeight(40),
+		WithRegisterMetadata(map[string]string{"zone": "a", "env": "test"}), WithRegisterProtocol("grpc"))
+
+	svc := &registry.ServiceInstance{Name: "orders", Endpoints: []string{"10.0.0.1:9000"}, Metadata: map[string]string{"env": "prod"}}
+	require.NoError(t, registrar.Register(context.Background(), svc), "the failed registration is retried")
+	require.Len(t, provider.registered, 1)
+	req := provider.registered[0]
+	assert.Equal(t, 15, *req.TTL)
+	assert.Equal(t, 40, *req.Weight)
+	assert.Equal(t, "grpc", *req.Protocol)
+	assert.Equal(t, "a", req.Metadata["zone"])
+	assert.Equal(t, "prod", req.Metadata["env"], "instance metadata takes precedence")
+	require.NotNil(t, plugin.registrar, "registrations go through the plugin's registrar")
+
+	require.NoError(t, plugin.registrar.SetWeight(context.Background(), 80))
+	assert.Equal(t, 40, *provider.registered[len(provider.registered)-1].Weight, "the registered weight is kept")
+
+	ctx := WithRegisterOptions(context.Background(), WithRegisterWeight(60))
+	require.NoError(t, registrar.Register(ctx, svc))
+	assert.Equal(t, 60, *provider.registered[len(provider.registered)-1].Weight, "call options override the registrar's")
+	assert.Error(t, registrar.Register(WithRegisterOptions(context.Background(), WithRegisterWeight(-1)), svc))
+
+	require.NoError(t, registrar.Deregister(context.Background(), svc))
+	require.Len(t, provider.deregistered, 1)
+	assert.Equal(t, 9000, provider.deregistered[0].Port)
+	assert.Empty(t, plugin.registrar.pinned)
+}
+
+func TestBuildDiscovery_GetService(t *testing.T) {
+	instance := NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})
+	consumer := &fakeClients{instances: []model.Instance{instance}}
+	plugin := newBuilderTestPlugin(t, &fakeClients{}, consumer)
+
+	instances, err := plugin.BuildDiscovery().GetService(context.Background(), "orders")
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "orders", instances[0].Name)
+	assert.Equal(t, "orders", consumer.last.Service)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = plugin.BuildDiscovery().GetService(ctx, "orders")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -298,3 +298,94 @@ func TestCloneRegistryServiceInstance_DefensiveCopy(t *testing.T) {
 	assert.Equal(t, "http://localhost:8080", clone.Endpoints[0])
 	assert.Equal(t, "prod", clone.Metadata["env"])
 }
+
+// ---------------------------------------------------------------------------
+// PolarisRegistrar — multi-endpoint registration
+// ---------------------------------------------------------------------------
+
+func multiEndpointService() *registry.ServiceInstance {
+	return &registry.ServiceInstance{
+		Name:      "svc",
+		Version:   "v1",
+		Endpoints: []string{"http://10.0.0.1:8080", "grpc://10.0.0.1:9090", "http://10.0.0.1:8080"},
+		Metadata:  map[string]string{"env": "prod"},
+	}
+}
+
+func TestPolarisRegistrar_Register_MultiEndpoint(t *testing.T) {
+	provider := &fakeClients{}
+	reg := NewPolarisRegistrar(provider, "default")
+
+	require.NoError(t, reg.Register(context.Background(), multiEndpointService()))
+	require.Len(t, provider.registered, 2)
+	assert.Equal(t, 8080, provider.registered[0].Port)
+	assert.Equal(t, "http", *provider.registered[0].Protocol)
+	assert.Equal(t, "http", provider.registered[0].Metadata["protocol"])
+	assert.Equal(t, 9090, provider.registered[1].Port)
+	assert.Equal(t, "grpc", *provider.registered[1].Protocol)
+	assert.Equal(t, "prod", provider.registered[1].Metadata["env"])
+
+	got, err := reg.GetService(context.Background(), "svc")
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	require.NoError(t, reg.Deregister(context.Background(), multiEndpointService()))
+	assert.Len(t, provider.deregistered, 2)
+	got, _ = reg.GetService(context.Background(), "svc")
+	assert.Empty(t, got)
+}
+
+func TestPolarisRegistrar_Register_RollsBackOnFailure(t *testing.T) {
+	provider := &fakeClients{failPort: 9090}
+	reg := NewPolarisRegistrar(provider, "default")
+
+	err := reg.Register(context.Background(), multiEndpointService())
+	require.Error(t, err)
+	require.Len(t, provider.deregistered, 1)
+	assert.Equal(t, 8080, provider.deregistered[0].Port)
+	got, _ := reg.GetService(context.Background(), "svc")
+	assert.Empty(t, got)
+}
+
+func TestPolarisRegistrar_Register_RecordsMetrics(t *testing.T) {
+	provider := &fakeClients{failPort: 9090}
+	reg := NewPolarisRegistrar(provider, "metrics-ns")
+	reg.metrics = NewPolarisMetrics()
+
+	require.Error(t, reg.Register(context.Background(), multiEndpointService()))
+	assert.Equal(t, 1.0, gatheredValue(t, "lynx_polaris_service_registration_total", map[string]string{"service": "svc", "namespace": "metrics-ns", "status": "success"}))
+	assert.Equal(t, 1.0, gatheredValue(t, "lynx_polaris_service_registration_total", map[string]string{"service": "svc", "namespace": "metrics-ns", "status": "error"}))
+	assert.Equal(t, 1.0, gatheredValue(t, "lynx_polaris_sdk_errors_total", map[string]string{"operation": "register", "error_code": "unknown"}))
+}
+
+func TestPolarisRegistrar_SetEndpointHealthy(t *testing.T) {
+	provider := &fakeClients{}
+	reg := NewPolarisRegistrar(provider, "default")
+	svc := multiEndpointService()
+	require.NoError(t, reg.Register(context.Background(), svc))
+
+	require.NoError(t, reg.SetEndpointHealthy(context.Background(), svc, "grpc://10.0.0.1:9090", false))
+	last := provider.registered[len(provider.registered)-1]
+	assert.Equal(t, 9090, last.Port)
+	assert.False(t, *last.Healthy)
+
+	assert.Error(t, reg.SetEndpointHealthy(context.Background(), svc, "grpc://10.0.0.1:7000", true))
+}
+
+func TestServiceInfo_ServiceInstance(t *testing.T) {
+	info := &ServiceInfo{
+		Service:  "svc",
+		Host:     "10.0.0.1",
+		Port:     8080,
+		Version:  "v1",
+		Metadata: map[string]string{"env": "prod"},
+		Endpoints: []ServiceEndpoint{
+			{Host: "10.0.0.1", Port: 9090, Protocol: "grpc"},
+			{Port: 7000},
+		},
+	}
+	instance := info.ServiceInstance()
+	assert.Equal(t, "svc", instance.Name)
+	assert.Equal(t, []string{"http://10.0.0.1:8080", "grpc://10.0.0.1:9090"}, instance.Endpoints)
+	assert.Nil(t, (*ServiceInfo)(nil).ServiceInstance())
+}
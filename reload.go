@@ -0,0 +1,148 @@
+package polaris
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/go-lynx/lynx/log"
+)
+
+// reloadHandler is a config reload callback registered by an application component.
+type reloadHandler struct {
+	name string
+	fn   func(content string) error
+}
+
+// reloadKey returns the key of the reload handlers of a config file.
+func reloadKey(fileName, group string) string {
+	return fileName + ":" + group
+}
+
+// reloadHandlerName names a handler after its function and registration sequence.
+func reloadHandlerName(fn func(content string) error, seq int) string {
+	name := "handler"
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		name = f.Name()
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			name = name[i+1:]
+		}
+	}
+	return fmt.Sprintf("%s#%d", name, seq)
+}
+
+// RegisterReloadable registers fn to be called with the new content of fileName in group
+// whenever it changes, and starts watching the file. Encrypted values are decrypted
+// before fn is called. Handlers run in registration order; a failing or panicking handler
+// does not stop the others. Results are counted in config_reloads_total and published as
+// a ConfigReloadedEvent.
+func (p *PlugPolaris) RegisterReloadable(fileName, group string, fn func(content string) error) error {
+	if err := p.checkInitialized(); err != nil {
+		return err
+	}
+	name, err := p.addReloadHandler(fileName, group, fn)
+	if err != nil {
+		return err
+	}
+	if _, err := p.WatchConfig(fileName, group); err != nil {
+		p.removeReloadHandler(fileName, group, name)
+		return err
+	}
+	log.Infof("Registered config reload handler %s for %s:%s", name, fileName, group)
+	return nil
+}
+
+// addReloadHandler stores fn and returns its name.
+func (p *PlugPolaris) addReloadHandler(fileName, group string, fn func(content string) error) (string, error) {
+	if fn == nil {
+		return "", NewConfigError("reload handler must not be nil")
+	}
+	if fileName == "" {
+		return "", NewConfigError("config file name is required")
+	}
+	p.reloadMutex.Lock()
+	defer p.reloadMutex.Unlock()
+	if p.reloadHandlers == nil {
+		p.reloadHandlers = make(map[string][]reloadHandler)
+	}
+	p.reloadSeq++
+	handler := reloadHandler{name: reloadHandlerName(fn, p.reloadSeq), fn: fn}
+	key := reloadKey(fileName, group)
+	p.reloadHandlers[key] = append(p.reloadHandlers[key], handler)
+	return handler.name, nil
+}
+
+// removeReloadHandler drops the handler called name.
+func (p *PlugPolaris) removeReloadHandler(fileName, group, name string) {
+	p.reloadMutex.Lock()
+	defer p.reloadMutex.Unlock()
+	key := reloadKey(fileName, group)
+	handlers := p.reloadHandlers[key]
+	for i, handler := range handlers {
+		if handler.name == name {
+			p.reloadHandlers[key] = append(handlers[:i:i], handlers[i+1:]...)
+			return
+		}
+	}
+}
+
+// reloadHandlersFor returns a copy of the reload handlers of a config file.
+func (p *PlugPolaris) reloadHandlersFor(fileName, group string) []reloadHandler {
+	p.reloadMutex.Lock()
+	defer p.reloadMutex.Unlock()
+	return append([]reloadHandler(nil), p.reloadHandlers[reloadKey(fileName, group)]...)
+}
+
+// runReloadHandler calls a handler, turning a panic into an error.
+func runReloadHandler(handler reloadHandler, content string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("reload handler panicked: %v", r)
+		}
+	}()
+	return handler.fn(content)
+}
+
+// dispatchConfigReload runs handlers with the decrypted content and reports each result.
+// It returns the errors by handler name.
+func (p *PlugPolaris) dispatchConfigReload(fileName, group, content string, handlers []reloadHandler) map[string]string {
+	p.mu.RLock()
+	metrics := p.metrics
+	namespace := p.currentConf().GetNamespace()
+	p.mu.RUnlock()
+
+	decrypted, decryptErr := p.decryptConfigContent(fileName, group, content)
+	failures := make(map[string]string)
+	for _, handler := range handlers {
+		err := decryptErr
+		if err == nil {
+			err = runReloadHandler(handler, decrypted)
+		}
+		result := "success"
+		if err != nil {
+			result = "error"
+			failures[handler.name] = err.Error()
+			log.Errorf("Config reload handler %s failed for %s:%s: %v", handler.name, fileName, group, p.redactError(err))
+		}
+		if metrics != nil {
+			metrics.RecordConfigReload(fileName, group, result)
+		}
+	}
+	log.Infof("Config %s:%s reloaded by %d handler(s), %d failed", fileName, group, len(handlers), len(failures))
+
+	event := &ConfigReloadedEvent{
+		Kind:      EventTypeConfigReloaded,
+		FileName:  fileName,
+		Group:     group,
+		Namespace: namespace,
+		Handlers:  len(handlers),
+		Timestamp: time.Now(),
+	}
+	if len(failures) > 0 {
+		event.Errors = failures
+	}
+	p.publishEvent(event)
+	return failures
+}
@@ -0,0 +1,73 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigReload_DispatchesToHandlers(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := plugin.Subscribe(ctx, EventTypeConfigReloaded)
+	require.NoError(t, err)
+
+	var got []string
+	_, err = plugin.addReloadHandler("app.yaml", "orders", func(content string) error {
+		got = append(got, content)
+		return nil
+	})
+	require.NoError(t, err)
+	failing, err := plugin.addReloadHandler("app.yaml", "orders", func(string) error {
+		return errors.New("pool size must be positive")
+	})
+	require.NoError(t, err)
+	panicking, err := plugin.addReloadHandler("app.yaml", "orders", func(string) error {
+		panic("boom")
+	})
+	require.NoError(t, err)
+	_, err = plugin.addReloadHandler("other.yaml", "orders", func(string) error {
+		t.Fatal("handler of another file must not run")
+		return nil
+	})
+	require.NoError(t, err)
+
+	plugin.triggerConfigReload("app.yaml", "orders", &fakeConfigFile{content: "pool: 0\n"})
+
+	assert.Equal(t, []string{"pool: 0\n"}, got)
+	select {
+	case ev := <-events:
+		reloaded := ev.(*ConfigReloadedEvent)
+		assert.Equal(t, 3, reloaded.Handlers)
+		assert.Equal(t, map[string]string{
+			failing:   "pool size must be positive",
+			panicking: "reload handler panicked: boom",
+		}, reloaded.Errors)
+	case <-time.After(time.Second):
+		t.Fatal("expected config reloaded event")
+	}
+}
+
+func TestConfigReload_RegistrationErrors(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+
+	_, err := plugin.addReloadHandler("app.yaml", "orders", nil)
+	assert.True(t, IsConfigError(err))
+	_, err = plugin.addReloadHandler("", "orders", func(string) error { return nil })
+	assert.True(t, IsConfigError(err))
+	assert.Error(t, plugin.RegisterReloadable("app.yaml", "orders", func(string) error { return nil }))
+
+	name, err := plugin.addReloadHandler("app.yaml", "orders", func(string) error { return nil })
+	require.NoError(t, err)
+	assert.Contains(t, name, "#")
+	plugin.removeReloadHandler("app.yaml", "orders", name)
+	assert.Empty(t, plugin.reloadHandlersFor("app.yaml", "orders"))
+}
@@ -0,0 +1,169 @@
+package polaris
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+// RemoteConfig module
+// Responsibility: loading the settings of the plugin from a Polaris config file at startup
+// and applying them again whenever the file changes.
+
+// parseRemoteSettings decodes the YAML or JSON content of the remote_config file. The
+// settings are read at the top level, or under lynx.polaris when the file has it. It also
+// returns the names of the settings present in the file, sorted.
+func parseRemoteSettings(content string) (*conf.Polaris, []string, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse remote settings: %w", err)
+	}
+	if lynx, ok := doc["lynx"].(map[string]any); ok {
+		if settings, ok := lynx["polaris"].(map[string]any); ok {
+			doc = settings
+		}
+	}
+	settings := &conf.Polaris{}
+	if len(doc) == 0 {
+		return settings, nil, nil
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse remote settings: %w", err)
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, settings); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse remote settings: %w", err)
+	}
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return settings, keys, nil
+}
+
+// overlayRemoteSettings returns a copy of local with the settings named by keys replaced
+// by those of remote, including settings remote sets to their zero value. It also returns
+// the keys that are immutable or unknown, which are left unchanged.
+func overlayRemoteSettings(local, remote *conf.Polaris, keys []string) (*conf.Polaris, []string) {
+	merged := proto.Clone(local).(*conf.Polaris)
+	target, source := merged.ProtoReflect(), remote.ProtoReflect()
+	fields := target.Descriptor().Fields()
+	var ignored []string
+	for _, key := range keys {
+		field := fields.ByName(protoreflect.Name(key))
+		if field == nil {
+			field = fields.ByJSONName(key)
+		}
+		if field == nil || slices.Contains(immutableSettings, field.Name()) {
+			ignored = append(ignored, key)
+			continue
+		}
+		if source.Has(field) {
+			target.Set(field, source.Get(field))
+		} else {
+			target.Clear(field)
+		}
+	}
+	return merged, ignored
+}
+
+// remoteConfigFile returns the file and group of remote_config, or an empty file name when
+// it is not set.
+func remoteConfigFile(cfg *conf.RemoteConfig) (fileName, group string) {
+	group = cfg.GetGroup()
+	if group == "" {
+		group = conf.DefaultRemoteConfigGroup
+	}
+	return cfg.GetFilename(), group
+}
+
+// loadRemoteConfig applies the settings of the remote_config file and watches it, so
+// that they are applied again whenever it changes. When the file cannot be loaded, the
+// local settings are kept, or startup fails if remote_config.required is set.
+func (p *PlugPolaris) loadRemoteConfig() error {
+	p.mu.RLock()
+	cfg := p.currentConf().GetRemoteConfig()
+	p.mu.RUnlock()
+	fileName, group := remoteConfigFile(cfg)
+	if fileName == "" {
+		return nil
+	}
+
+	content, err := p.getConfigContent("", fileName, group)
+	if err == nil {
+		err = p.applyRemoteConfig(content)
+	}
+	if err != nil {
+		if cfg.GetRequired() {
+			return WrapInitError(err, fmt.Sprintf("failed to load remote config %s:%s", group, fileName))
+		}
+		log.Warnf("Failed to load remote config %s:%s, using the local settings: %v", group, fileName, p.redactError(err))
+	}
+
+	p.mu.Lock()
+	previous := p.remoteConfigHandler
+	p.remoteConfigHandler = ""
+	p.mu.Unlock()
+	if previous != "" {
+		p.removeReloadHandler(fileName, group, previous)
+	}
+	name, err := p.addReloadHandler(fileName, group, p.applyRemoteConfig)
+	if err != nil {
+		return err
+	}
+	if _, err := p.WatchConfig(fileName, group); err != nil {
+		p.removeReloadHandler(fileName, group, name)
+		if cfg.GetRequired() {
+			return WrapInitError(err, fmt.Sprintf("failed to watch remote config %s:%s", group, fileName))
+		}
+		log.Warnf("Failed to watch remote config %s:%s, its changes are not applied: %v", group, fileName, p.redactError(err))
+		return nil
+	}
+	p.mu.Lock()
+	p.remoteConfigHandler = name
+	p.mu.Unlock()
+	return nil
+}
+
+// applyRemoteConfig applies the settings of content, the content of the remote_config
+// file, onto the local configuration. Invalid settings are rejected and the current
+// configuration is kept.
+func (p *PlugPolaris) applyRemoteConfig(content string) error {
+	remote, keys, err := parseRemoteSettings(content)
+	if err != nil {
+		return WrapConfigError(err, "invalid remote config")
+	}
+
+	p.mu.Lock()
+	if p.localConf == nil {
+		p.localConf = proto.Clone(p.currentConf()).(*conf.Polaris)
+	}
+	local := p.localConf
+	p.mu.Unlock()
+
+	merged, ignored := overlayRemoteSettings(local, remote, keys)
+	if len(ignored) > 0 {
+		log.Warnf("Ignoring remote settings that are immutable or unknown: %s", strings.Join(ignored, ", "))
+	}
+	setConfigDefaults(merged)
+	if result := NewValidator(merged).Validate(); !result.IsValid {
+		return NewConfigError("invalid remote config: " + p.redact(result.Errors[0].Error()))
+	}
+
+	p.mu.Lock()
+	previous := p.currentConf()
+	p.setConf(merged)
+	p.mu.Unlock()
+	p.applySettingsChange(previous, merged)
+	log.Infof("Applied %d remote settings", len(keys)-len(ignored))
+	return nil
+}
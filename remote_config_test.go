@@ -0,0 +1,104 @@
+package polaris
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRemoteSettings(t *testing.T) {
+	settings, keys, err := parseRemoteSettings("lynx:\n  polaris:\n    weight: 50\n    retry_interval: 2s\n    unknown_setting: 1\n")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"retry_interval", "unknown_setting", "weight"}, keys)
+	assert.Equal(t, int32(50), settings.Weight)
+	assert.Equal(t, "2s", settings.RetryInterval.AsDuration().String())
+
+	settings, keys, err = parseRemoteSettings(`{"maxRetryTimes": 4}`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"maxRetryTimes"}, keys)
+	assert.Equal(t, int32(4), settings.MaxRetryTimes)
+
+	_, _, err = parseRemoteSettings("weight: [")
+	assert.Error(t, err)
+	_, _, err = parseRemoteSettings("weight: heavy")
+	assert.Error(t, err)
+}
+
+func TestApplyRemoteConfig(t *testing.T) {
+	plugin := newTestPlugin(t, &conf.Polaris{Namespace: "default", Token: "local-token", MaxRetryTimes: 2, EnableHealthCheck: true})
+	retryManager, circuitBreaker := plugin.retryManager, plugin.circuitBreaker
+
+	require.NoError(t, plugin.applyRemoteConfig(`
+weight: 50
+max_retry_times: 5
+enable_health_check: false
+token: remote-token
+alerting:
+  webhooks:
+    - url: https://hooks.example.com/polaris
+`))
+	cfg := plugin.GetPolarisConfig()
+	assert.Equal(t, int32(50), cfg.Weight)
+	assert.False(t, cfg.EnableHealthCheck, "settings set to their zero value replace the local ones")
+	assert.Equal(t, "local-token", cfg.Token, "local only settings are kept")
+	assert.Equal(t, 5, plugin.retryManager.maxRetries)
+	assert.NotSame(t, retryManager, plugin.retryManager)
+	assert.NotSame(t, circuitBreaker, plugin.circuitBreaker)
+	assert.True(t, plugin.RemoveAlerter("alerting-0"))
+
+	// Settings removed from the file fall back to the local ones
+	circuitBreaker = plugin.circuitBreaker
+	require.NoError(t, plugin.applyRemoteConfig("max_retry_times: 5\n"))
+	cfg = plugin.GetPolarisConfig()
+	assert.Equal(t, int32(conf.DefaultWeight), cfg.Weight)
+	assert.True(t, cfg.EnableHealthCheck)
+	assert.Same(t, circuitBreaker, plugin.circuitBreaker, "unchanged resilience settings keep the circuit breaker")
+
+	// Invalid settings keep the current configuration
+	assert.Error(t, plugin.applyRemoteConfig("weight: 5000\n"))
+	assert.Equal(t, int32(conf.DefaultWeight), plugin.GetPolarisConfig().Weight)
+}
+
+func TestValidator_RemoteConfig(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, RemoteConfig: &conf.RemoteConfig{Required: true}}
+	var fields []string
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.Contains(t, fields, "remote_config.filename")
+}
+
+func TestApplyRemoteConfig_ConcurrentReads(t *testing.T) {
+	plugin, err := NewPluginWithClients(&conf.Polaris{Namespace: "default"}, WithConsumerClient(&fakeClients{}))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = plugin.CleanupTasks() })
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				plugin.updateServiceInstanceCache("orders", nil)
+				_, _ = plugin.GetServiceInstances("orders")
+				_ = plugin.cacheSettings()
+			}
+		}()
+	}
+	for i := range 50 {
+		require.NoError(t, plugin.applyRemoteConfig(fmt.Sprintf("weight: %d\nmax_retry_times: %d\n", 10+i, i%3)))
+	}
+	close(done)
+	wg.Wait()
+	assert.Equal(t, int32(59), plugin.GetPolarisConfig().GetWeight())
+}
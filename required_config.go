@@ -0,0 +1,70 @@
+package polaris
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-lynx/lynx/log"
+)
+
+// requiredConfigRetryInterval is the delay between attempts to fetch missing required configs.
+const requiredConfigRetryInterval = time.Second
+
+// GetConfigValueOrDefault returns the content of a config file, or defaultValue when it
+// can be fetched neither from Polaris nor from a local snapshot.
+func (p *PlugPolaris) GetConfigValueOrDefault(fileName, group, defaultValue string) string {
+	content, err := p.GetConfigValue(fileName, group)
+	if err != nil {
+		log.Warnf("Using default value for config %s:%s: %v", fileName, group, err)
+		return defaultValue
+	}
+	return content
+}
+
+// hasRequiredConfigs reports whether required_configs lists any file.
+func (p *PlugPolaris) hasRequiredConfigs() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.currentConf().GetRequiredConfigs().GetFiles()) > 0
+}
+
+// requiredConfigsReady reports whether the required config files have been loaded.
+func (p *PlugPolaris) requiredConfigsReady() bool {
+	return !p.hasRequiredConfigs() || atomic.LoadInt32(&p.requiredConfigsLoaded) == 1
+}
+
+// loadRequiredConfigs fetches the required config files with fetch, retrying missing
+// ones for up to required_configs.wait. It fails when any file is still missing.
+func (p *PlugPolaris) loadRequiredConfigs(ctx context.Context, fetch func(namespace, fileName, group string) (string, error)) error {
+	p.mu.RLock()
+	cfg := p.currentConf().GetRequiredConfigs()
+	p.mu.RUnlock()
+	files := cfg.GetFiles()
+	if len(files) == 0 {
+		return nil
+	}
+	deadline := time.Now().Add(cfg.GetWait().AsDuration())
+	for attempt := 1; ; attempt++ {
+		var missing []string
+		for _, file := range files {
+			if _, err := fetch(file.GetNamespace(), file.GetFilename(), file.GetGroup()); err != nil {
+				missing = append(missing, fmt.Sprintf("%s:%s (%v)", file.GetGroup(), file.GetFilename(), err))
+			}
+		}
+		if len(missing) == 0 {
+			atomic.StoreInt32(&p.requiredConfigsLoaded, 1)
+			log.Infof("Loaded %d required config files", len(files))
+			return nil
+		}
+		if time.Now().Add(requiredConfigRetryInterval).After(deadline) {
+			return NewInitError(fmt.Sprintf("required config files are unavailable: %s", strings.Join(missing, "; ")))
+		}
+		log.Warnf("Required config files unavailable (attempt %d), retrying: %s", attempt, strings.Join(missing, "; "))
+		if p.waitForRetryDelay(ctx, requiredConfigRetryInterval) {
+			return NewInitError("startup stopped while waiting for required config files")
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newRequiredConfigPlugin(wait time.Duration) *PlugPolaris {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", RequiredConfigs: &conf.RequiredConfigs{
+		Files: []*conf.ConfigFile{{Filename: "db.yaml", Group: "orders"}, {Filename: "app.yaml", Group: "orders"}},
+		Wait:  durationpb.New(wait),
+	}})
+	return plugin
+}
+
+func TestLoadRequiredConfigs_RetriesUntilAvailable(t *testing.T) {
+	plugin := newRequiredConfigPlugin(5 * time.Second)
+	assert.False(t, plugin.requiredConfigsReady())
+
+	var calls int32
+	fetch := func(_, fileName, _ string) (string, error) {
+		if fileName == "db.yaml" && atomic.AddInt32(&calls, 1) == 1 {
+			return "", errors.New("polaris unreachable")
+		}
+		return "ok", nil
+	}
+	require.NoError(t, plugin.loadRequiredConfigs(context.Background(), fetch))
+	assert.True(t, plugin.requiredConfigsReady())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestLoadRequiredConfigs_FailsWhenMissing(t *testing.T) {
+	plugin := newRequiredConfigPlugin(0)
+	fetch := func(_, fileName, _ string) (string, error) {
+		if fileName == "app.yaml" {
+			return "", NewServiceError(ErrCodeConfigNotFound, "configFile not found")
+		}
+		return "ok", nil
+	}
+	err := plugin.loadRequiredConfigs(context.Background(), fetch)
+	require.Error(t, err)
+	assert.True(t, IsInitError(err))
+	assert.Contains(t, err.Error(), "orders:app.yaml")
+	assert.NotContains(t, err.Error(), "db.yaml")
+	assert.False(t, plugin.requiredConfigsReady())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	plugin = newRequiredConfigPlugin(time.Minute)
+	assert.Error(t, plugin.loadRequiredConfigs(ctx, fetch))
+}
+
+func TestRequiredConfigsReady_WithoutRequiredConfigs(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	assert.True(t, plugin.requiredConfigsReady())
+	assert.NoError(t, plugin.loadRequiredConfigs(context.Background(), nil))
+}
+
+func TestGetConfigValueOrDefault(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	assert.Equal(t, "fallback", plugin.GetConfigValueOrDefault("app.yaml", "orders", "fallback"))
+}
@@ -4,62 +4,290 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand/v2"
 	"sync"
 	"time"
 
+	"github.com/go-lynx/lynx-polaris/conf"
 	"github.com/go-lynx/lynx/log"
 )
 
 // RetryManager retry manager
-// Provides exponential backoff retry mechanism
+// Provides retries with pluggable backoff strategies
 type RetryManager struct {
 	maxRetries    int
 	retryInterval time.Duration
-	backoffFactor float64
+	maxDelay      time.Duration
+	backoff       BackoffStrategy
+	classifier    ErrorClassifier
+	hedgeDelay    time.Duration
+	clock         Clock
+	hooks         *retryHooks
+}
+
+// retryHooks holds the hooks of a retry manager, shared with the copies made for per-call options
+type retryHooks struct {
+	mu         sync.RWMutex
+	onRetry    []func(attempt int, err error)
+	onComplete []func(attempts int, err error)
+}
+
+// BackoffStrategy computes the delay before retry number attempt (0 for the first retry)
+// from the base retry interval, the max delay and the delay before the previous retry.
+// The retry manager caps the result at maxDelay.
+type BackoffStrategy func(attempt int, base, maxDelay, previous time.Duration) time.Duration
+
+// FixedBackoff waits the base interval before every retry
+func FixedBackoff(_ int, base, _, _ time.Duration) time.Duration {
+	return base
+}
+
+// ExponentialBackoff doubles the delay with every retry: base * 2^attempt
+func ExponentialBackoff(attempt int, base, maxDelay, _ time.Duration) time.Duration {
+	return exponentialDelay(attempt, base, maxDelay)
+}
+
+// FullJitterBackoff waits a random delay between zero and the exponential delay, so that
+// clients which failed at the same moment do not retry at the same moment
+func FullJitterBackoff(attempt int, base, maxDelay, _ time.Duration) time.Duration {
+	return randomDuration(nil, 0, exponentialDelay(attempt, base, maxDelay))
+}
+
+// NewFullJitterBackoff returns FullJitterBackoff drawing its delays from src, so that a
+// seeded source makes them reproducible. A nil src draws from the global source.
+func NewFullJitterBackoff(src rand.Source) BackoffStrategy {
+	return fullJitterBackoff(newJitterRand(src))
+}
+
+func fullJitterBackoff(rng *jitterRand) BackoffStrategy {
+	return func(attempt int, base, maxDelay, _ time.Duration) time.Duration {
+		return randomDuration(rng, 0, exponentialDelay(attempt, base, maxDelay))
+	}
+}
+
+// DecorrelatedJitterBackoff waits a random delay between the base interval and three times
+// the previous delay, growing about as fast as exponential backoff while staying spread out
+func DecorrelatedJitterBackoff(_ int, base, maxDelay, previous time.Duration) time.Duration {
+	return randomDuration(nil, base, min(max(previous, base)*3, maxDelay))
+}
+
+// NewDecorrelatedJitterBackoff returns DecorrelatedJitterBackoff drawing its delays from
+// src. A nil src draws from the global source.
+func NewDecorrelatedJitterBackoff(src rand.Source) BackoffStrategy {
+	return decorrelatedJitterBackoff(newJitterRand(src))
+}
+
+func decorrelatedJitterBackoff(rng *jitterRand) BackoffStrategy {
+	return func(_ int, base, maxDelay, previous time.Duration) time.Duration {
+		return randomDuration(rng, base, min(max(previous, base)*3, maxDelay))
+	}
+}
+
+// exponentialDelay returns base * 2^attempt, capped at maxDelay
+func exponentialDelay(attempt int, base, maxDelay time.Duration) time.Duration {
+	delay := float64(base) * math.Pow(2, float64(attempt))
+	if delay >= float64(maxDelay) {
+		return maxDelay
+	}
+	return time.Duration(delay)
+}
+
+// randomDuration returns a random duration in [lo, hi] drawn from rng, or lo when hi is
+// not above it
+func randomDuration(rng *jitterRand, lo, hi time.Duration) time.Duration {
+	if hi <= lo {
+		return lo
+	}
+	return lo + time.Duration(rng.Int64N(int64(hi-lo)+1))
+}
+
+// BackoffStrategyByName returns the strategy named by a retry_backoff config value. An
+// empty name selects exponential backoff.
+func BackoffStrategyByName(name string) (BackoffStrategy, bool) {
+	return backoffStrategyByName(name, nil)
+}
+
+// backoffStrategyByName is BackoffStrategyByName with the jittered strategies drawing
+// from rng
+func backoffStrategyByName(name string, rng *jitterRand) (BackoffStrategy, bool) {
+	switch name {
+	case conf.RetryBackoffFixed:
+		return FixedBackoff, true
+	case "", conf.RetryBackoffExponential:
+		return ExponentialBackoff, true
+	case conf.RetryBackoffFullJitter:
+		if rng != nil {
+			return fullJitterBackoff(rng), true
+		}
+		return FullJitterBackoff, true
+	case conf.RetryBackoffDecorrelatedJitter:
+		if rng != nil {
+			return decorrelatedJitterBackoff(rng), true
+		}
+		return DecorrelatedJitterBackoff, true
+	default:
+		return nil, false
+	}
+}
+
+// RetryOption customizes a retry manager, or a single call of DoWithRetry and DoWithRetryContext.
+type RetryOption func(*RetryManager)
+
+// WithRetryBackoff sets the backoff strategy. A nil strategy keeps ExponentialBackoff,
+// which is the default.
+func WithRetryBackoff(strategy BackoffStrategy) RetryOption {
+	return func(r *RetryManager) {
+		if strategy != nil {
+			r.backoff = strategy
+		}
+	}
+}
+
+// WithRetryMaxDelay caps the delay between retries. Non-positive values keep the default of 30s.
+func WithRetryMaxDelay(maxDelay time.Duration) RetryOption {
+	return func(r *RetryManager) {
+		if maxDelay > 0 {
+			r.maxDelay = maxDelay
+		}
+	}
+}
+
+// WithRetryErrorClassifier sets the classifier deciding which errors are retried. Only
+// ErrorClassRetryable errors are; others are returned right away. A nil classifier keeps
+// DefaultErrorClassifier.
+func WithRetryErrorClassifier(classifier ErrorClassifier) RetryOption {
+	return func(r *RetryManager) {
+		if classifier != nil {
+			r.classifier = classifier
+		}
+	}
+}
+
+// WithHedging makes DoWithHedging start a second, hedged run of an attempt that has not
+// completed after delay, taking whichever run succeeds first. Only use it for idempotent,
+// read-only operations. Zero, the default, disables hedging.
+func WithHedging(delay time.Duration) RetryOption {
+	return func(r *RetryManager) {
+		r.hedgeDelay = max(0, delay)
+	}
+}
+
+// WithRetryClock makes the retry manager wait on clock instead of SystemClock
+func WithRetryClock(clock Clock) RetryOption {
+	return func(r *RetryManager) {
+		r.clock = clockOrSystem(clock)
+	}
 }
 
 // NewRetryManager creates new retry manager
-func NewRetryManager(maxRetries int, retryInterval time.Duration) *RetryManager {
-	return &RetryManager{
+func NewRetryManager(maxRetries int, retryInterval time.Duration, opts ...RetryOption) *RetryManager {
+	r := &RetryManager{
 		maxRetries:    maxRetries,
 		retryInterval: retryInterval,
-		backoffFactor: 2.0, // Exponential backoff factor
+		maxDelay:      conf.DefaultRetryMaxDelay,
+		backoff:       ExponentialBackoff,
+		classifier:    DefaultErrorClassifier,
+		clock:         SystemClock,
+		hooks:         &retryHooks{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// OnRetry registers hook to run before every retry, with the number of the attempt that
+// failed (starting at 1) and its error
+func (r *RetryManager) OnRetry(hook func(attempt int, err error)) {
+	r.hooks.mu.Lock()
+	defer r.hooks.mu.Unlock()
+	r.hooks.onRetry = append(r.hooks.onRetry, hook)
+}
+
+// OnComplete registers hook to run when an operation completes, with the number of
+// attempts made and the final error, which is nil on success
+func (r *RetryManager) OnComplete(hook func(attempts int, err error)) {
+	r.hooks.mu.Lock()
+	defer r.hooks.mu.Unlock()
+	r.hooks.onComplete = append(r.hooks.onComplete, hook)
+}
+
+// notifyRetry runs the retry hooks
+func (r *RetryManager) notifyRetry(attempt int, err error) {
+	r.hooks.mu.RLock()
+	hooks := r.hooks.onRetry
+	r.hooks.mu.RUnlock()
+	for _, hook := range hooks {
+		hook(attempt, err)
+	}
+}
+
+// complete runs the completion hooks and returns err
+func (r *RetryManager) complete(attempts int, err error) error {
+	r.hooks.mu.RLock()
+	hooks := r.hooks.onComplete
+	r.hooks.mu.RUnlock()
+	for _, hook := range hooks {
+		hook(attempts, err)
+	}
+	return err
+}
+
+// withOptions returns r, or a copy of r with opts applied when there are any
+func (r *RetryManager) withOptions(opts []RetryOption) *RetryManager {
+	if len(opts) == 0 {
+		return r
+	}
+	call := *r
+	for _, opt := range opts {
+		opt(&call)
 	}
+	return &call
 }
 
-// DoWithRetry executes operation with retry
-func (r *RetryManager) DoWithRetry(operation func() error) error {
+// DoWithRetry executes operation with retry. opts override the manager's settings for this call.
+func (r *RetryManager) DoWithRetry(operation func() error, opts ...RetryOption) error {
+	r = r.withOptions(opts)
 	var lastErr error
+	var backoffTime time.Duration
 
 	for attempt := 0; attempt <= r.maxRetries; attempt++ {
 		if err := operation(); err == nil {
 			if attempt > 0 {
 				log.Infof("Operation succeeded after %d retries", attempt)
 			}
-			return nil
+			return r.complete(attempt+1, nil)
 		} else {
 			lastErr = err
+			if r.classifier(err) != ErrorClassRetryable {
+				return r.complete(attempt+1, err)
+			}
 			if attempt < r.maxRetries {
 				// Calculate backoff time
-				backoffTime := r.calculateBackoff(attempt)
+				backoffTime = r.calculateBackoff(attempt, backoffTime)
 				log.Warnf("Operation failed (attempt %d/%d): %v, retrying in %v",
 					attempt+1, r.maxRetries+1, err, backoffTime)
-				time.Sleep(backoffTime)
+				r.notifyRetry(attempt+1, err)
+				timer := r.clock.NewTimer(backoffTime)
+				<-timer.C()
 			}
 		}
 	}
 
-	return fmt.Errorf("operation failed after %d attempts, last error: %w", r.maxRetries+1, lastErr)
+	return r.complete(r.maxRetries+1, fmt.Errorf("operation failed after %d attempts, last error: %w", r.maxRetries+1, lastErr))
 }
 
-// DoWithRetryContext executes operation with retry (supports context)
-func (r *RetryManager) DoWithRetryContext(ctx context.Context, operation func() error) error {
+// DoWithRetryContext executes operation with retry (supports context). opts override the
+// manager's settings for this call.
+func (r *RetryManager) DoWithRetryContext(ctx context.Context, operation func() error, opts ...RetryOption) error {
+	r = r.withOptions(opts)
 	var lastErr error
+	var backoffTime time.Duration
 
 	for attempt := 0; attempt <= r.maxRetries; attempt++ {
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("operation cancelled: %w", ctx.Err())
+			return r.complete(attempt, fmt.Errorf("operation cancelled: %w", ctx.Err()))
 		default:
 		}
 
@@ -67,61 +295,174 @@ func (r *RetryManager) DoWithRetryContext(ctx context.Context, operation func()
 			if attempt > 0 {
 				log.Infof("Operation succeeded after %d retries", attempt)
 			}
-			return nil
+			return r.complete(attempt+1, nil)
 		} else {
 			lastErr = err
+			if r.classifier(err) != ErrorClassRetryable {
+				return r.complete(attempt+1, err)
+			}
 			if attempt < r.maxRetries {
-				backoffTime := r.calculateBackoff(attempt)
+				backoffTime = r.calculateBackoff(attempt, backoffTime)
 				log.Warnf("Operation failed (attempt %d/%d): %v, retrying in %v",
 					attempt+1, r.maxRetries+1, err, backoffTime)
+				r.notifyRetry(attempt+1, err)
 
+				timer := r.clock.NewTimer(backoffTime)
 				select {
-				case <-time.After(backoffTime):
+				case <-timer.C():
 				case <-ctx.Done():
-					return fmt.Errorf("operation cancelled during retry: %w", ctx.Err())
+					timer.Stop()
+					return r.complete(attempt+1, fmt.Errorf("operation cancelled during retry: %w", ctx.Err()))
 				}
 			}
 		}
 	}
 
-	return fmt.Errorf("operation failed after %d attempts, last error: %w", r.maxRetries+1, lastErr)
+	return r.complete(r.maxRetries+1, fmt.Errorf("operation failed after %d attempts, last error: %w", r.maxRetries+1, lastErr))
+}
+
+// DoWithRetryResult executes operation with the retries of r and returns the value of its
+// successful attempt, so that callers need not capture results in the closure. On failure
+// it returns the zero value of T along with the error of DoWithRetryContext.
+func DoWithRetryResult[T any](ctx context.Context, r *RetryManager, operation func() (T, error), opts ...RetryOption) (T, error) {
+	var result T
+	err := r.DoWithRetryContext(ctx, func() error {
+		value, err := operation()
+		if err != nil {
+			return err
+		}
+		result = value
+		return nil
+	}, opts...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
+
+// DoWithHedging is DoWithRetryResult for idempotent operations that are hedged when the
+// manager has a hedge delay (see WithHedging): an attempt still running after the delay is
+// run a second time, the first success wins and the context of the other run is cancelled.
+// An attempt fails once all of its runs failed, and is then retried as usual.
+func DoWithHedging[T any](ctx context.Context, r *RetryManager, operation func(ctx context.Context) (T, error), opts ...RetryOption) (T, error) {
+	r = r.withOptions(opts)
+	return DoWithRetryResult(ctx, r, func() (T, error) {
+		if r.hedgeDelay <= 0 {
+			return operation(ctx)
+		}
+		return hedge(ctx, r.clock, r.hedgeDelay, operation)
+	})
 }
 
-// calculateBackoff calculates backoff time
-func (r *RetryManager) calculateBackoff(attempt int) time.Duration {
-	// Exponential backoff: base * factor^attempt
-	backoffSeconds := float64(r.retryInterval) * math.Pow(r.backoffFactor, float64(attempt))
+// hedge runs operation, and runs it again when the first run has not completed after delay.
+// It returns the first success or, once both runs failed, the last error.
+func hedge[T any](ctx context.Context, clock Clock, delay time.Duration, operation func(ctx context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	// Limit maximum backoff time to 30 seconds
-	maxBackoff := 30 * time.Second
-	if time.Duration(backoffSeconds) > maxBackoff {
-		return maxBackoff
+	type outcome struct {
+		value T
+		err   error
+	}
+	// Buffered so that the losing run never blocks after hedge returned
+	outcomes := make(chan outcome, 2)
+	run := func() {
+		value, err := operation(ctx)
+		outcomes <- outcome{value: value, err: err}
 	}
+	go run()
+	pending := 1
 
-	return time.Duration(backoffSeconds)
+	timer := clock.NewTimer(delay)
+	defer timer.Stop()
+	var zero T
+	for {
+		select {
+		case <-timer.C():
+			log.Debugf("Operation still running after %v, starting hedged request", delay)
+			pending++
+			go run()
+		case o := <-outcomes:
+			pending--
+			if o.err == nil {
+				return o.value, nil
+			}
+			if pending == 0 {
+				return zero, o.err
+			}
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// calculateBackoff calculates the backoff time before retry number attempt, given the
+// previous backoff time, capped at the max delay
+func (r *RetryManager) calculateBackoff(attempt int, previous time.Duration) time.Duration {
+	return max(0, min(r.backoff(attempt, r.retryInterval, r.maxDelay, previous), r.maxDelay))
 }
 
 // CircuitBreaker circuit breaker
 // Implements simple circuit breaker protection mechanism
 type CircuitBreaker struct {
-	threshold        float64
-	halfOpenTimeout  time.Duration
-	failureCount     int
-	successCount     int
-	lastFailure      time.Time
-	state            CircuitState
-	halfOpenInFlight bool
-	mu               sync.Mutex
-
-	// Rolling window for the closed state: counters are evaluated over the most
-	// recent window only, so the failure rate reflects current health rather than
-	// the entire process lifetime.
+	threshold       float64
+	halfOpenTimeout time.Duration
+	lastFailure     time.Time
+	state           CircuitState
+	mu              sync.Mutex
+
+	// Half-open probing: up to halfOpenProbes calls are let through after the open
+	// duration; the breaker closes once all of them succeed and reopens on any failure.
+	halfOpenProbes  int
+	probesInFlight  int
+	probesSucceeded int
+
+	// Sliding window for the closed state: outcomes are counted in time buckets and
+	// only the buckets of the most recent window are evaluated, so the failure rate
+	// reflects current health rather than the entire process lifetime. The breaker
+	// does not trip before the window holds minRequests outcomes.
 	rollingWindow time.Duration
-	windowStart   time.Time
+	buckets       []windowBucket
+	minRequests   int
+
+	// Successful calls slower than slowCallThreshold count as failures; zero disables
+	slowCallThreshold time.Duration
+
+	// Errors classified as ErrorClassIgnore are not counted
+	classifier ErrorClassifier
+
+	// clock times the open state, the sliding window and slow calls
+	clock Clock
+
+	// Set by ForceOpen and ForceClose: the state is held until Reset
+	forced bool
+
+	// Hooks, and the state transitions queued for them while cb.mu is held
+	onStateChange []func(from, to CircuitState)
+	onReject      []func(state CircuitState)
+	transitions   []stateTransition
+}
+
+// stateTransition is a change of circuit breaker state awaiting its hooks
+type stateTransition struct {
+	from, to CircuitState
 }
 
-// defaultRollingWindow is the time span over which closed-state failure rate is computed.
-const defaultRollingWindow = 60 * time.Second
+// windowBucket counts the outcomes of one slice of the sliding window.
+type windowBucket struct {
+	start     time.Time
+	failures  int
+	successes int
+}
+
+const (
+	// defaultRollingWindow is the time span over which closed-state failure rate is computed.
+	defaultRollingWindow = 60 * time.Second
+
+	// windowBuckets is the number of buckets the sliding window is divided into.
+	windowBuckets = 10
+)
 
 // CircuitState circuit breaker state
 type CircuitState int
@@ -132,82 +473,257 @@ const (
 	CircuitStateHalfOpen                     // Half-open state: attempting recovery
 )
 
-// NewCircuitBreaker creates new circuit breaker with configurable threshold and half-open timeout
-func NewCircuitBreaker(threshold float64, halfOpenTimeout time.Duration) *CircuitBreaker {
+// String returns the name of the circuit state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitStateClosed:
+		return "closed"
+	case CircuitStateOpen:
+		return "open"
+	case CircuitStateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerOption customizes a circuit breaker.
+type CircuitBreakerOption func(*CircuitBreaker)
+
+// WithHalfOpenProbes sets how many probe calls the half-open state lets through. The
+// breaker closes once all of them succeed. Values below 1 are treated as 1.
+func WithHalfOpenProbes(probes int) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.halfOpenProbes = max(1, probes)
+	}
+}
+
+// WithRollingWindow sets the span of the sliding window over which the failure rate is
+// computed. Non-positive values keep the default of 60s.
+func WithRollingWindow(window time.Duration) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		if window > 0 {
+			cb.rollingWindow = window
+		}
+	}
+}
+
+// WithMinRequests sets how many outcomes the sliding window must hold before the failure
+// rate can trip the breaker. Values below 1 are treated as 1, which is the default.
+func WithMinRequests(minRequests int) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.minRequests = max(1, minRequests)
+	}
+}
+
+// WithSlowCallThreshold counts successful calls slower than threshold as failures, so a
+// dependency that hangs instead of failing still opens the breaker. Zero, the default,
+// disables slow-call detection.
+func WithSlowCallThreshold(threshold time.Duration) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.slowCallThreshold = max(0, threshold)
+	}
+}
+
+// WithErrorClassifier sets the classifier deciding which errors count as failures. Errors
+// classified as ErrorClassIgnore are not counted, and an ignored half-open probe frees its
+// slot for another probe. A nil classifier keeps DefaultErrorClassifier.
+func WithErrorClassifier(classifier ErrorClassifier) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		if classifier != nil {
+			cb.classifier = classifier
+		}
+	}
+}
+
+// WithCircuitBreakerClock makes the breaker time its open state, sliding window and slow
+// calls with clock instead of SystemClock
+func WithCircuitBreakerClock(clock Clock) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.clock = clockOrSystem(clock)
+	}
+}
+
+// NewCircuitBreaker creates new circuit breaker with configurable threshold and half-open timeout,
+// which is how long the breaker stays open before it probes for recovery
+func NewCircuitBreaker(threshold float64, halfOpenTimeout time.Duration, opts ...CircuitBreakerOption) *CircuitBreaker {
 	if halfOpenTimeout <= 0 {
 		halfOpenTimeout = 30 * time.Second
 	}
-	return &CircuitBreaker{
+	cb := &CircuitBreaker{
 		threshold:       threshold,
 		halfOpenTimeout: halfOpenTimeout,
+		halfOpenProbes:  1,
 		state:           CircuitStateClosed,
 		rollingWindow:   defaultRollingWindow,
-		windowStart:     time.Now(),
+		buckets:         make([]windowBucket, windowBuckets),
+		minRequests:     1,
+		classifier:      DefaultErrorClassifier,
+		clock:           SystemClock,
 	}
+	for _, opt := range opts {
+		opt(cb)
+	}
+	return cb
 }
 
-// rollWindowLocked resets the closed-state counters when the rolling window has
-// elapsed, so the failure rate is always evaluated over recent activity only.
-// Must be called with cb.mu held.
-func (cb *CircuitBreaker) rollWindowLocked(now time.Time) {
-	if cb.rollingWindow <= 0 {
-		return
+// recordOutcomeLocked counts an outcome in the bucket of now, recycling the bucket when
+// it belongs to an earlier window. Must be called with cb.mu held.
+func (cb *CircuitBreaker) recordOutcomeLocked(now time.Time, failed bool) {
+	width := max(cb.rollingWindow/windowBuckets, time.Nanosecond)
+	slot := now.UnixNano() / int64(width)
+	bucket := &cb.buckets[slot%windowBuckets]
+	if start := time.Unix(0, slot*int64(width)); !bucket.start.Equal(start) {
+		*bucket = windowBucket{start: start}
 	}
-	if cb.windowStart.IsZero() {
-		cb.windowStart = now
-		return
+	if failed {
+		bucket.failures++
+	} else {
+		bucket.successes++
 	}
-	if now.Sub(cb.windowStart) >= cb.rollingWindow {
-		cb.failureCount = 0
-		cb.successCount = 0
-		cb.windowStart = now
+}
+
+// windowCountsLocked sums the outcomes of the buckets within the window ending at now.
+// Must be called with cb.mu held.
+func (cb *CircuitBreaker) windowCountsLocked(now time.Time) (failures, total int) {
+	for _, bucket := range cb.buckets {
+		if !bucket.start.IsZero() && now.Sub(bucket.start) < cb.rollingWindow {
+			failures += bucket.failures
+			total += bucket.failures + bucket.successes
+		}
 	}
+	return failures, total
 }
 
-// Do executes operation with circuit breaker protection
+// Do executes operation with circuit breaker protection. Calls that return an error, or
+// that succeed slower than the slow-call threshold, count as failures, except for errors
+// the classifier ignores, which are not counted at all.
 func (cb *CircuitBreaker) Do(operation func() error) error {
-	if err := cb.beforeRequest(); err != nil {
+	probe, err := cb.beforeRequest()
+	if err != nil {
+		cb.notifyRejected()
 		return err
 	}
 
-	err := operation()
-	cb.afterRequest(err)
+	start := cb.clock.Now()
+	err = operation()
+	if err != nil && cb.classifier(err) == ErrorClassIgnore {
+		cb.cancelRequest(probe)
+		return err
+	}
+	cb.afterRequest(err != nil || cb.isSlowCall(cb.clock.Now().Sub(start)), probe)
 	return err
 }
 
-func (cb *CircuitBreaker) beforeRequest() error {
+// OnStateChange registers hook to run after every state transition of the breaker.
+// Hooks run outside the breaker's lock, in registration order.
+func (cb *CircuitBreaker) OnStateChange(hook func(from, to CircuitState)) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.onStateChange = append(cb.onStateChange, hook)
+}
+
+// OnReject registers hook to run for every call the breaker rejects, with the state
+// that rejected it
+func (cb *CircuitBreaker) OnReject(hook func(state CircuitState)) {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
+	cb.onReject = append(cb.onReject, hook)
+}
+
+// setStateLocked moves the breaker to state, queueing the transition for the state change
+// hooks. Must be called with cb.mu held.
+func (cb *CircuitBreaker) setStateLocked(state CircuitState) {
+	if cb.state == state {
+		return
+	}
+	cb.transitions = append(cb.transitions, stateTransition{from: cb.state, to: state})
+	cb.state = state
+}
+
+// unlock releases cb.mu and runs the state change hooks of the transitions queued while
+// it was held
+func (cb *CircuitBreaker) unlock() {
+	transitions := cb.transitions
+	cb.transitions = nil
+	hooks := cb.onStateChange
+	cb.mu.Unlock()
+	for _, t := range transitions {
+		for _, hook := range hooks {
+			hook(t.from, t.to)
+		}
+	}
+}
+
+// notifyRejected runs the reject hooks for a call rejected by the breaker
+func (cb *CircuitBreaker) notifyRejected() {
+	cb.mu.Lock()
+	state := cb.state
+	hooks := cb.onReject
+	cb.mu.Unlock()
+	for _, hook := range hooks {
+		hook(state)
+	}
+}
+
+// cancelRequest completes a call without counting its outcome, freeing its probe slot
+func (cb *CircuitBreaker) cancelRequest(probe bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if probe && cb.state == CircuitStateHalfOpen {
+		cb.probesInFlight--
+	}
+}
+
+// isSlowCall reports whether a call that took elapsed exceeds the slow-call threshold
+func (cb *CircuitBreaker) isSlowCall(elapsed time.Duration) bool {
+	return cb.slowCallThreshold > 0 && elapsed > cb.slowCallThreshold
+}
+
+// beforeRequest admits a call, reporting whether it is a half-open probe
+func (cb *CircuitBreaker) beforeRequest() (bool, error) {
+	cb.mu.Lock()
+	defer cb.unlock()
 
 	switch cb.state {
 	case CircuitStateOpen:
-		if time.Since(cb.lastFailure) > cb.halfOpenTimeout {
-			cb.state = CircuitStateHalfOpen
-			cb.halfOpenInFlight = true
-			log.Infof("Circuit breaker transitioning to half-open state")
-		} else {
-			return fmt.Errorf("circuit breaker is open")
+		if cb.forced {
+			return false, fmt.Errorf("circuit breaker is forced open")
+		}
+		if cb.clock.Now().Sub(cb.lastFailure) <= cb.halfOpenTimeout {
+			return false, fmt.Errorf("circuit breaker is open")
 		}
+		cb.setStateLocked(CircuitStateHalfOpen)
+		cb.probesInFlight = 0
+		cb.probesSucceeded = 0
+		log.Infof("Circuit breaker transitioning to half-open state, allowing %d probe(s)", cb.halfOpenProbes)
+		fallthrough
 	case CircuitStateHalfOpen:
-		if cb.halfOpenInFlight {
-			return fmt.Errorf("circuit breaker is half-open")
+		if cb.probesInFlight+cb.probesSucceeded >= cb.halfOpenProbes {
+			return false, fmt.Errorf("circuit breaker is half-open")
 		}
-		cb.halfOpenInFlight = true
-		log.Infof("Circuit breaker in half-open state, allowing one attempt")
+		cb.probesInFlight++
+		return true, nil
 	case CircuitStateClosed:
+		return false, nil
 	default:
-		return fmt.Errorf("invalid circuit breaker state: %v", cb.state)
+		return false, fmt.Errorf("invalid circuit breaker state: %v", cb.state)
 	}
-	return nil
 }
 
-func (cb *CircuitBreaker) afterRequest(err error) {
+func (cb *CircuitBreaker) afterRequest(failed bool, probe bool) {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
-	if cb.state == CircuitStateHalfOpen {
-		cb.halfOpenInFlight = false
+	defer cb.unlock()
+	if probe && cb.state == CircuitStateHalfOpen {
+		cb.probesInFlight--
+		if !failed {
+			cb.probesSucceeded++
+		}
+	} else if cb.state == CircuitStateHalfOpen {
+		// Calls admitted before the breaker opened do not decide recovery
+		return
 	}
-	if err != nil {
+	if failed {
 		cb.recordFailure()
 	} else {
 		cb.recordSuccess()
@@ -216,46 +732,45 @@ func (cb *CircuitBreaker) afterRequest(err error) {
 
 // recordFailure records failure
 func (cb *CircuitBreaker) recordFailure() {
-	now := time.Now()
-	// Roll the window before counting so the failure rate reflects recent activity.
-	cb.rollWindowLocked(now)
-	cb.failureCount++
+	now := cb.clock.Now()
+	cb.recordOutcomeLocked(now, true)
 	cb.lastFailure = now
+	if cb.forced {
+		return
+	}
 
-	// Calculate failure rate over the current rolling window
-	failureRate := float64(cb.failureCount) / float64(cb.failureCount+cb.successCount)
+	// Calculate failure rate over the sliding window
+	failures, total := cb.windowCountsLocked(now)
+	failureRate := float64(failures) / float64(total)
 
-	if cb.state == CircuitStateClosed && failureRate >= cb.threshold {
-		cb.state = CircuitStateOpen
+	if cb.state == CircuitStateClosed && total >= cb.minRequests && failureRate >= cb.threshold {
+		cb.setStateLocked(CircuitStateOpen)
 		// Reset counters on transition so a fresh window is used after recovery.
 		cb.resetCounters()
 		log.Warnf("Circuit breaker opened: failure rate %.2f >= threshold %.2f",
 			failureRate, cb.threshold)
 	} else if cb.state == CircuitStateHalfOpen {
-		cb.state = CircuitStateOpen
+		cb.setStateLocked(CircuitStateOpen)
 		cb.resetCounters()
-		log.Warnf("Circuit breaker reopened after failed attempt")
+		log.Warnf("Circuit breaker reopened after failed probe")
 	}
 }
 
 // recordSuccess records success
 func (cb *CircuitBreaker) recordSuccess() {
-	cb.rollWindowLocked(time.Now())
-	cb.successCount++
+	cb.recordOutcomeLocked(cb.clock.Now(), false)
 
-	if cb.state == CircuitStateHalfOpen {
-		// Success in half-open state, reset to closed state
-		cb.state = CircuitStateClosed
+	if cb.state == CircuitStateHalfOpen && cb.probesSucceeded >= cb.halfOpenProbes {
+		// All probes succeeded in half-open state, reset to closed state
+		cb.setStateLocked(CircuitStateClosed)
 		cb.resetCounters()
-		log.Infof("Circuit breaker closed after successful attempt")
+		log.Infof("Circuit breaker closed after %d successful probe(s)", cb.probesSucceeded)
 	}
 }
 
-// resetCounters resets counters and starts a fresh rolling window
+// resetCounters clears the sliding window
 func (cb *CircuitBreaker) resetCounters() {
-	cb.failureCount = 0
-	cb.successCount = 0
-	cb.windowStart = time.Now()
+	clear(cb.buckets)
 }
 
 // GetState gets circuit breaker state
@@ -270,28 +785,49 @@ func (cb *CircuitBreaker) GetFailureRate() float64 {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
-	total := cb.failureCount + cb.successCount
+	failures, total := cb.windowCountsLocked(cb.clock.Now())
 	if total == 0 {
 		return 0
 	}
-	return float64(cb.failureCount) / float64(total)
+	return float64(failures) / float64(total)
 }
 
-// ForceOpen forces circuit breaker to open
-func (cb *CircuitBreaker) ForceOpen() {
+// IsForced reports whether the state is held by ForceOpen or ForceClose
+func (cb *CircuitBreaker) IsForced() bool {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
-	cb.state = CircuitStateOpen
-	cb.halfOpenInFlight = false
+	return cb.forced
+}
+
+// ForceOpen forces circuit breaker to open and rejects every call until ForceClose or Reset
+func (cb *CircuitBreaker) ForceOpen() {
+	cb.mu.Lock()
+	defer cb.unlock()
+	cb.setStateLocked(CircuitStateOpen)
+	cb.forced = true
 	log.Warnf("Circuit breaker forced open")
 }
 
-// ForceClose forces circuit breaker to close
+// ForceClose forces circuit breaker to close and lets every call through until Reset,
+// however many of them fail
 func (cb *CircuitBreaker) ForceClose() {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
-	cb.state = CircuitStateClosed
-	cb.halfOpenInFlight = false
+	defer cb.unlock()
+	cb.setStateLocked(CircuitStateClosed)
+	cb.forced = true
 	cb.resetCounters()
 	log.Infof("Circuit breaker forced closed")
 }
+
+// Reset closes circuit breaker, clears its counters and releases a forced state, so that
+// failures can open it again
+func (cb *CircuitBreaker) Reset() {
+	cb.mu.Lock()
+	defer cb.unlock()
+	cb.setStateLocked(CircuitStateClosed)
+	cb.forced = false
+	cb.probesInFlight = 0
+	cb.probesSucceeded = 0
+	cb.resetCounters()
+	log.Infof("Circuit breaker reset")
+}
@@ -0,0 +1,85 @@
+package polaris
+
+import (
+	"context"
+	"maps"
+	"sync/atomic"
+
+	"github.com/go-lynx/lynx/log"
+)
+
+// Restart module
+// Responsibility: restarting the plugin in place, so that integration tests and operator
+// tooling can reinitialize it without ending the process.
+
+// Restart runs CleanupTasks and then initializes the plugin again from its current
+// configuration. The service info is kept, the instances registered through the plugin's
+// registrar are registered again, and the service and config watchers are recreated from
+// their last instances and config. The registrar and discovery handed out before the
+// restart keep working through the new SDK context. Event subscriptions and notifiers end
+// with the cleanup, and dependent plugins are not loaded again.
+func (p *PlugPolaris) Restart() error {
+	if err := p.checkLifecycle(); err != nil {
+		return err
+	}
+	p.recoveryMutex.Lock()
+	defer p.recoveryMutex.Unlock()
+	ctx := context.Background()
+
+	p.mu.RLock()
+	info := cloneServiceInfo(p.serviceInfo)
+	registrar, discovery := p.registrar, p.discovery
+	p.mu.RUnlock()
+	var instances []trackedInstance
+	if registrar != nil {
+		instances = registrar.tracked()
+	}
+	p.watcherMutex.RLock()
+	serviceWatchers := maps.Clone(p.activeWatchers)
+	configWatchers := maps.Clone(p.configWatchers)
+	p.watcherMutex.RUnlock()
+
+	log.Warnf("Restarting Polaris plugin")
+	if err := p.cleanupTasksContext(ctx); err != nil {
+		log.Warnf("Polaris cleanup did not finish before the restart: %v", p.redactError(err))
+	}
+
+	atomic.StoreInt32(&p.destroyed, 0)
+	p.events.reopen()
+	p.mu.Lock()
+	p.serviceInfo = info
+	p.mu.Unlock()
+	if err := p.initComponents(); err != nil {
+		return WrapInitError(err, "failed to initialize components")
+	}
+	if err := p.startup(ctx, true); err != nil {
+		return err
+	}
+
+	if registrar != nil || discovery != nil {
+		if err := p.ensureConnected(); err != nil {
+			return err
+		}
+	}
+	p.mu.RLock()
+	current := p.registrar
+	p.mu.RUnlock()
+	if registrar != nil {
+		registrar.setProvider(p.providerClient())
+		if current != nil && len(instances) > 0 {
+			if err := current.registerAll(ctx, instances); err != nil {
+				return WrapServiceError(err, ErrCodeServiceUnavailable, "failed to register instances again")
+			}
+		}
+	}
+	if discovery != nil {
+		discovery.setConsumer(p.consumerClient())
+	}
+	services, configs, err := p.reattachWatchers(serviceWatchers, configWatchers)
+	if err != nil {
+		return WrapServiceError(err, ErrCodeServiceUnavailable, "Polaris plugin restarted with errors")
+	}
+	log.Infof("Restarted Polaris plugin, registered %d instances and recreated %d service watchers and %d config watchers",
+		len(instances), services, configs)
+	return nil
+}
@@ -0,0 +1,68 @@
+package polaris
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestart_NotInitialized(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	assert.True(t, IsInitError(plugin.Restart()))
+}
+
+func TestLazyInit_Unconnected(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", LazyInit: true})
+	plugin.setInitialized()
+
+	assert.True(t, plugin.lazilyUnconnected())
+	assert.NoError(t, plugin.CheckLiveness(), "liveness passes before the first use")
+	report, err := plugin.CheckHealthReport(context.Background())
+	require.NoError(t, err, "health checks do not connect")
+	sdk, ok := report.Component(HealthComponentSDK)
+	require.True(t, ok)
+	assert.Equal(t, HealthStatusSkipped, sdk.Status)
+	assert.True(t, plugin.lazilyUnconnected())
+
+	plugin.currentConf().LazyInit = false
+	assert.False(t, plugin.lazilyUnconnected())
+	assert.Error(t, plugin.CheckLiveness())
+}
+
+func TestPolarisRegistrar_RegisterAll(t *testing.T) {
+	provider := &fakeClients{}
+	previous := NewPolarisRegistrar(provider, "default")
+	svc := &registry.ServiceInstance{Name: "svc", Endpoints: []string{"http://10.0.0.1:8080", "grpc://10.0.0.1:9090"}}
+	require.NoError(t, previous.Register(context.Background(), svc))
+	require.NoError(t, previous.SetEndpointHealthy(context.Background(), svc, "grpc://10.0.0.1:9090", false))
+	instances := previous.tracked()
+	require.Len(t, instances, 2)
+	previous.Close(context.Background())
+	provider.registered = nil
+
+	current := NewPolarisRegistrar(provider, "default")
+	require.NoError(t, current.registerAll(context.Background(), instances))
+	require.Len(t, provider.registered, 2)
+	assert.True(t, current.hasInstances())
+	assert.False(t, current.registeredSince().IsZero())
+	for _, req := range provider.registered {
+		assert.Equal(t, req.Port != 9090, *req.Healthy, "keeps the last reported health")
+	}
+}
+
+func TestEventBus_Reopen(t *testing.T) {
+	bus := newEventBus()
+	bus.close()
+	bus.reopen()
+	assert.False(t, bus.closed)
+	select {
+	case <-bus.done:
+		t.Fatal("reopened bus is done")
+	default:
+	}
+}
@@ -0,0 +1,153 @@
+package polaris
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRetryTestPlugin returns an initialized plugin with a live lifecycle context and no SDK.
+// Its watch dispatcher, which runs the retries and lives as long as the lifecycle, is
+// already started.
+func newRetryTestPlugin() *PlugPolaris {
+	p := NewPolarisControlPlane()
+	p.setConf(&conf.Polaris{Namespace: "default"})
+	p.setInitialized()
+	p.mu.Lock()
+	p.ensureLifecycleContextLocked()
+	p.mu.Unlock()
+	p.watchDispatcher()
+	return p
+}
+
+// waitRetries waits for all retry goroutines of p to exit.
+func waitRetries(t *testing.T, p *PlugPolaris) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		p.retryWg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("retry goroutine outlived its watcher")
+	}
+}
+
+// assertNoLeakedGoroutines waits until the goroutine count drops back to baseline.
+func assertNoLeakedGoroutines(t *testing.T, baseline int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if runtime.NumGoroutine() <= baseline {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), baseline, "leaked goroutines after Stop()")
+}
+
+func TestServiceWatchRetry_EndsWhenWatcherStopped(t *testing.T) {
+	p := newRetryTestPlugin()
+	baseline := runtime.NumGoroutine()
+
+	watcher := NewServiceWatcherWithContext(p.watcherContext(), nil, "svc", "default")
+	p.watcherMutex.Lock()
+	p.activeWatchers["svc"] = watcher
+	p.watcherMutex.Unlock()
+	watcher.Start()
+
+	p.handleServiceWatchError("svc", errors.New("boom"))
+	p.retryMutex.Lock()
+	require.Len(t, p.retryingServiceWatchers, 1)
+	p.retryMutex.Unlock()
+
+	watcher.Stop()
+	waitRetries(t, p)
+	assertNoLeakedGoroutines(t, baseline)
+
+	p.retryMutex.Lock()
+	assert.Empty(t, p.retryingServiceWatchers)
+	p.retryMutex.Unlock()
+}
+
+func TestConfigWatchRetry_EndsWhenWatcherStopped(t *testing.T) {
+	p := newRetryTestPlugin()
+	baseline := runtime.NumGoroutine()
+
+	watcher := NewConfigWatcherWithContext(p.watcherContext(), nil, "app.yaml", "grp", "default")
+	p.watcherMutex.Lock()
+	p.configWatchers["app.yaml:grp"] = watcher
+	p.watcherMutex.Unlock()
+	watcher.Start()
+
+	p.handleConfigWatchError("app.yaml", "grp", errors.New("boom"))
+	watcher.Stop()
+	waitRetries(t, p)
+	assertNoLeakedGoroutines(t, baseline)
+}
+
+func TestWatchRetry_EndsOnCleanupWatchers(t *testing.T) {
+	p := newRetryTestPlugin()
+	baseline := runtime.NumGoroutine()
+
+	watcher := NewServiceWatcherWithContext(p.watcherContext(), nil, "svc", "default")
+	p.watcherMutex.Lock()
+	p.activeWatchers["svc"] = watcher
+	p.watcherMutex.Unlock()
+	watcher.Start()
+
+	p.handleServiceWatchError("svc", errors.New("boom"))
+	p.cleanupWatchers()
+	waitRetries(t, p)
+	assertNoLeakedGoroutines(t, baseline)
+}
+
+func TestWatchRetry_Deduplicated(t *testing.T) {
+	p := newRetryTestPlugin()
+	ctx, retry, ok := p.tryStartServiceWatchRetry(p.watcherContext(), "svc")
+	require.True(t, ok)
+	_, _, ok = p.tryStartServiceWatchRetry(p.watcherContext(), "svc")
+	assert.False(t, ok)
+
+	p.finishServiceWatchRetry("svc", retry)
+	p.retryWg.Done()
+	assert.Error(t, ctx.Err())
+}
+
+func TestWatchRetry_StaleFinishKeepsNewerRetry(t *testing.T) {
+	p := newRetryTestPlugin()
+	_, stale, ok := p.tryStartServiceWatchRetry(p.watcherContext(), "svc")
+	require.True(t, ok)
+	_, staleConfig, ok := p.tryStartConfigWatchRetry(p.watcherContext(), "app.yaml:orders")
+	require.True(t, ok)
+
+	// cleanupWatchers resets the retrying sets, then newer retries start
+	p.retryMutex.Lock()
+	p.retryingServiceWatchers = make(map[string]*watchRetryEntry)
+	p.retryingConfigWatchers = make(map[string]*watchRetryEntry)
+	p.retryMutex.Unlock()
+	ctx, newer, ok := p.tryStartServiceWatchRetry(p.watcherContext(), "svc")
+	require.True(t, ok)
+	configCtx, newerConfig, ok := p.tryStartConfigWatchRetry(p.watcherContext(), "app.yaml:orders")
+	require.True(t, ok)
+
+	p.finishServiceWatchRetry("svc", stale)
+	p.finishConfigWatchRetry("app.yaml", "orders", staleConfig)
+	assert.NoError(t, ctx.Err(), "the stale retry does not cancel the newer one")
+	assert.NoError(t, configCtx.Err())
+	_, _, ok = p.tryStartServiceWatchRetry(p.watcherContext(), "svc")
+	assert.False(t, ok, "the newer retry is still deduplicating")
+
+	p.finishServiceWatchRetry("svc", newer)
+	p.finishConfigWatchRetry("app.yaml", "orders", newerConfig)
+	for range 4 {
+		p.retryWg.Done()
+	}
+}
@@ -0,0 +1,159 @@
+package polaris
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// RouteFallback module
+// Responsibility: per-service fallback targets (another service or static endpoints) that
+// receive traffic when the primary service has zero healthy instances.
+
+// routeFallbackTarget is the resolved fallback target of a single service.
+type routeFallbackTarget struct {
+	service   string
+	instances []model.Instance
+}
+
+// SetRouteFallback sets the fallback target of serviceName. When targetService is non-empty,
+// traffic goes to its healthy instances; otherwise the given static instances are used.
+// Programmatic values take precedence over route_fallbacks from configuration.
+// Passing an empty target removes the fallback.
+func (p *PlugPolaris) SetRouteFallback(serviceName, targetService string, instances []model.Instance) error {
+	if serviceName == "" {
+		return NewConfigError("service name cannot be empty")
+	}
+	if targetService == serviceName {
+		return NewConfigError("route fallback target must differ from the primary service")
+	}
+	target := routeFallbackTarget{service: targetService}
+	for _, inst := range instances {
+		if inst != nil {
+			target.instances = append(target.instances, inst)
+		}
+	}
+
+	p.fallbackMutex.Lock()
+	defer p.fallbackMutex.Unlock()
+	if target.service == "" && len(target.instances) == 0 {
+		delete(p.routeFallbacks, serviceName)
+		log.Infof("Removed route fallback for %s", serviceName)
+		return nil
+	}
+	if p.routeFallbacks == nil {
+		p.routeFallbacks = make(map[string]routeFallbackTarget)
+	}
+	p.routeFallbacks[serviceName] = target
+	log.Infof("Set route fallback for %s: target_service=%q static_instances=%d",
+		serviceName, target.service, len(target.instances))
+	return nil
+}
+
+// loadConfiguredRouteFallbacks registers route_fallbacks from configuration. Services that
+// already have programmatic route fallbacks are left untouched.
+func (p *PlugPolaris) loadConfiguredRouteFallbacks() {
+	cfg := p.currentConf()
+	if cfg == nil {
+		return
+	}
+	p.fallbackMutex.Lock()
+	defer p.fallbackMutex.Unlock()
+	for _, rf := range cfg.GetRouteFallbacks() {
+		if rf == nil || rf.GetService() == "" || rf.GetTargetService() == rf.GetService() {
+			continue
+		}
+		if _, exists := p.routeFallbacks[rf.GetService()]; exists {
+			continue
+		}
+		target := routeFallbackTarget{service: rf.GetTargetService()}
+		if target.service == "" {
+			for _, fi := range rf.GetTargetInstances() {
+				if inst := NewStaticInstance(cfg.Namespace, rf.GetService(), fi); inst != nil {
+					target.instances = append(target.instances, inst)
+				}
+			}
+		}
+		if target.service == "" && len(target.instances) == 0 {
+			continue
+		}
+		if p.routeFallbacks == nil {
+			p.routeFallbacks = make(map[string]routeFallbackTarget)
+		}
+		p.routeFallbacks[rf.GetService()] = target
+		log.Infof("Loaded route fallback for %s from configuration: target_service=%q static_instances=%d",
+			rf.GetService(), target.service, len(target.instances))
+	}
+}
+
+// routeFallback returns the healthy instances of the fallback target of serviceName, or nil
+// when none is configured. A target service is resolved with a single hop: its own route
+// fallback is not followed, so misconfigured cycles cannot recurse.
+func (p *PlugPolaris) routeFallback(serviceName string) []model.Instance {
+	p.fallbackMutex.RLock()
+	target, ok := p.routeFallbacks[serviceName]
+	p.fallbackMutex.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	if target.service == "" {
+		log.Warnf("Service %s has no healthy instances, routing to %d static fallback instances",
+			serviceName, len(target.instances))
+		return append([]model.Instance(nil), target.instances...)
+	}
+
+	instances, err := p.getServiceInstances(target.service)
+	if err != nil {
+		log.Errorf("Route fallback for %s failed: target service %s unavailable: %v", serviceName, target.service, err)
+		return nil
+	}
+	healthy := healthyInstances(instances)
+	if len(healthy) == 0 {
+		log.Errorf("Route fallback for %s failed: target service %s has no healthy instances", serviceName, target.service)
+		return nil
+	}
+	log.Warnf("Service %s has no healthy instances, routing to fallback service %s (%d instances)",
+		serviceName, target.service, len(healthy))
+	return healthy
+}
+
+// healthyInstances returns the instances that are healthy and not isolated.
+func healthyInstances(instances []model.Instance) []model.Instance {
+	var healthy []model.Instance
+	for _, inst := range instances {
+		if inst != nil && inst.IsHealthy() && !inst.IsIsolated() {
+			healthy = append(healthy, inst)
+		}
+	}
+	return healthy
+}
+
+// routeFallbackNodeFilter wraps a node filter so that, when every node of a service is
+// filtered out, the nodes of the service's route fallback target are returned instead.
+func (p *PlugPolaris) routeFallbackNodeFilter(next selector.NodeFilter) selector.NodeFilter {
+	return func(ctx context.Context, nodes []selector.Node) []selector.Node {
+		filtered := nodes
+		if next != nil {
+			filtered = next(ctx, nodes)
+		}
+		if len(filtered) > 0 || len(nodes) == 0 {
+			return filtered
+		}
+		serviceName := nodes[0].ServiceName()
+		fallback := p.routeFallback(serviceName)
+		if len(fallback) == 0 {
+			return filtered
+		}
+		fallbackNodes := make([]selector.Node, 0, len(fallback))
+		for _, inst := range fallback {
+			addr := net.JoinHostPort(inst.GetHost(), strconv.FormatUint(uint64(inst.GetPort()), 10))
+			fallbackNodes = append(fallbackNodes, selector.NewNode(inst.GetProtocol(), addr, toRegistryServiceInstance(serviceName, inst)))
+		}
+		return fallbackNodes
+	}
+}
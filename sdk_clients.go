@@ -0,0 +1,267 @@
+package polaris
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"google.golang.org/protobuf/proto"
+)
+
+// SDK clients module
+// Responsibility: the parts of the polaris-go APIs the plugin calls, so that tests and
+// embedders can replace them with their own implementations.
+
+// ConsumerClient is the part of the polaris-go consumer API used for discovery, watches and
+// call results. api.ConsumerAPI implements it.
+type ConsumerClient interface {
+	GetOneInstance(req *api.GetOneInstanceRequest) (*model.OneInstanceResponse, error)
+	GetInstances(req *api.GetInstancesRequest) (*model.InstancesResponse, error)
+	GetAllInstances(req *api.GetAllInstancesRequest) (*model.InstancesResponse, error)
+	WatchService(req *api.WatchServiceRequest) (*model.WatchServiceResponse, error)
+	UpdateServiceCallResult(req *api.ServiceCallResult) error
+}
+
+// ProviderClient is the part of the polaris-go provider API used for registration.
+// api.ProviderAPI implements it.
+type ProviderClient interface {
+	RegisterInstance(req *api.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	Register(req *api.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	Deregister(req *api.InstanceDeRegisterRequest) error
+	Heartbeat(req *api.InstanceHeartbeatRequest) error
+}
+
+// ConfigClient is the part of the polaris-go config file API used to read config files.
+// api.ConfigFileAPI implements it.
+type ConfigClient interface {
+	GetConfigFile(namespace, fileGroup, fileName string) (model.ConfigFile, error)
+}
+
+// LimitClient is the part of the polaris-go limit API used for quota requests.
+// api.LimitAPI implements it.
+type LimitClient interface {
+	GetQuota(req api.QuotaRequest) (api.QuotaFuture, error)
+}
+
+// sdkClients are clients replacing those of the SDK context
+type sdkClients struct {
+	consumer ConsumerClient
+	provider ProviderClient
+	config   ConfigClient
+	limit    LimitClient
+}
+
+// Option configures a PlugPolaris created by NewPolarisControlPlane
+type Option func(*PlugPolaris)
+
+// WithConsumerClient makes the plugin use client instead of the consumer API of its SDK
+// context.
+func WithConsumerClient(client ConsumerClient) Option {
+	return func(p *PlugPolaris) { p.clientOverrides.consumer = client }
+}
+
+// WithProviderClient makes the plugin use client instead of the provider API of its SDK
+// context. dry_run still applies to it.
+func WithProviderClient(client ProviderClient) Option {
+	return func(p *PlugPolaris) { p.clientOverrides.provider = client }
+}
+
+// WithConfigClient makes the plugin use client instead of the config file API of its SDK
+// context.
+func WithConfigClient(client ConfigClient) Option {
+	return func(p *PlugPolaris) { p.clientOverrides.config = client }
+}
+
+// WithLimitClient makes the plugin use client instead of the limit API of its SDK context.
+func WithLimitClient(client LimitClient) Option {
+	return func(p *PlugPolaris) { p.clientOverrides.limit = client }
+}
+
+// NewPluginWithClients creates a plugin with the configuration cfg and starts it on the
+// clients of opts, without a Lynx application or an SDK context, e.g. to benchmark or test
+// code built on the plugin with polaristest. Calls that need a client opts do not replace
+// fail as if the plugin were not connected, lazy_init is ignored and no background task is
+// started. CleanupTasks stops the plugin.
+func NewPluginWithClients(cfg *conf.Polaris, opts ...Option) (*PlugPolaris, error) {
+	p := NewPolarisControlPlane(opts...)
+	p.setConf(&conf.Polaris{})
+	if cfg != nil {
+		p.setConf(proto.Clone(cfg).(*conf.Polaris))
+	}
+	p.currentConf().LazyInit = false
+	p.setDefaultConfig()
+	if err := p.validateConfig(); err != nil {
+		return nil, WrapInitError(err, "configuration validation failed")
+	}
+	if err := p.initComponents(); err != nil {
+		return nil, WrapInitError(err, "failed to initialize components")
+	}
+	p.mu.Lock()
+	p.ensureLifecycleContextLocked()
+	p.startTime = time.Now()
+	p.setInitialized()
+	p.mu.Unlock()
+	return p, nil
+}
+
+// sdkAPIs are the polaris-go APIs of an SDK context. Each API is created on first use and
+// reused afterwards, and only the created ones are destroyed with the context.
+type sdkAPIs struct {
+	sdk      api.SDKContext
+	mu       sync.Mutex
+	consumer api.ConsumerAPI
+	provider api.ProviderAPI
+	config   api.ConfigFileAPI
+	limit    api.LimitAPI
+}
+
+// newSDKAPIs returns the APIs of sdk, or nil when sdk is nil
+func newSDKAPIs(sdk api.SDKContext) *sdkAPIs {
+	if sdk == nil {
+		return nil
+	}
+	return &sdkAPIs{sdk: sdk}
+}
+
+// consumerAPI returns the consumer API, nil when a is nil
+func (a *sdkAPIs) consumerAPI() api.ConsumerAPI {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.consumer == nil {
+		a.consumer = api.NewConsumerAPIByContext(a.sdk)
+	}
+	return a.consumer
+}
+
+// providerAPI returns the provider API, nil when a is nil
+func (a *sdkAPIs) providerAPI() api.ProviderAPI {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.provider == nil {
+		a.provider = api.NewProviderAPIByContext(a.sdk)
+	}
+	return a.provider
+}
+
+// configAPI returns the config file API, nil when a is nil
+func (a *sdkAPIs) configAPI() api.ConfigFileAPI {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.config == nil {
+		a.config = api.NewConfigFileAPIBySDKContext(a.sdk)
+	}
+	return a.config
+}
+
+// limitAPI returns the limit API, nil when a is nil
+func (a *sdkAPIs) limitAPI() api.LimitAPI {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.limit == nil {
+		a.limit = api.NewLimitAPIByContext(a.sdk)
+	}
+	return a.limit
+}
+
+// setSDKLocked switches the plugin to sdk, whose APIs are created on first use. p.mu must
+// be held.
+func (p *PlugPolaris) setSDKLocked(sdk api.SDKContext) {
+	p.sdk = sdk
+	p.apis = newSDKAPIs(sdk)
+}
+
+// consumerLocked returns the consumer client, nil when the plugin is not connected. p.mu
+// must be held, for reading at least.
+func (p *PlugPolaris) consumerLocked() ConsumerClient {
+	if p.clientOverrides.consumer != nil {
+		return p.clientOverrides.consumer
+	}
+	if consumer := p.apis.consumerAPI(); consumer != nil {
+		return consumer
+	}
+	return nil
+}
+
+// providerLocked returns the provider client, nil when the plugin is not connected. p.mu
+// must be held, for reading at least.
+func (p *PlugPolaris) providerLocked() ProviderClient {
+	if p.clientOverrides.provider != nil {
+		return p.clientOverrides.provider
+	}
+	if provider := p.apis.providerAPI(); provider != nil {
+		return provider
+	}
+	return nil
+}
+
+// configLocked returns the config client, nil when the plugin is not connected. p.mu must
+// be held, for reading at least.
+func (p *PlugPolaris) configLocked() ConfigClient {
+	if p.clientOverrides.config != nil {
+		return p.clientOverrides.config
+	}
+	if config := p.apis.configAPI(); config != nil {
+		return config
+	}
+	return nil
+}
+
+// limitLocked returns the limit client, nil when the plugin is not connected. p.mu must be
+// held, for reading at least.
+func (p *PlugPolaris) limitLocked() LimitClient {
+	if p.clientOverrides.limit != nil {
+		return p.clientOverrides.limit
+	}
+	if limit := p.apis.limitAPI(); limit != nil {
+		return limit
+	}
+	return nil
+}
+
+// consumerClient returns the consumer client, nil when the plugin is not connected
+func (p *PlugPolaris) consumerClient() ConsumerClient {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.consumerLocked()
+}
+
+// providerClient returns the provider client, which drops the mutating calls in dry-run
+// mode, or nil when the plugin is not connected
+func (p *PlugPolaris) providerClient() ProviderClient {
+	p.mu.RLock()
+	provider := p.providerLocked()
+	dryRun := p.currentConf().GetDryRun()
+	p.mu.RUnlock()
+	if provider == nil || !dryRun {
+		return provider
+	}
+	return dryRunProvider{ProviderClient: provider}
+}
+
+// configClient returns the config client, nil when the plugin is not connected
+func (p *PlugPolaris) configClient() ConfigClient {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.configLocked()
+}
+
+// limitClient returns the limit client, nil when the plugin is not connected
+func (p *PlugPolaris) limitClient() LimitClient {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.limitLocked()
+}
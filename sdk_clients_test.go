@@ -0,0 +1,99 @@
+package polaris
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPolarisControlPlane_Clients(t *testing.T) {
+	instance := NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})
+	consumer := &fakeClients{instances: []model.Instance{instance}}
+	provider := &fakeClients{}
+	plugin := newTestPlugin(t, nil,
+		WithConsumerClient(consumer),
+		WithProviderClient(provider),
+		WithConfigClient(&fakeClients{config: "workers: 4\n"}),
+		nil,
+	)
+
+	instances, err := plugin.GetServiceInstances("orders")
+	require.NoError(t, err)
+	assert.Equal(t, []model.Instance{instance}, instances)
+	assert.Equal(t, "orders", consumer.last.Service)
+
+	content, err := plugin.getConfigContent("", "app.yaml", "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "workers: 4\n", content)
+
+	registrar := plugin.NewServiceRegistry()
+	require.NotNil(t, registrar)
+	require.NoError(t, registrar.Register(context.Background(), &registry.ServiceInstance{
+		Name: "orders", Endpoints: []string{"grpc://10.0.0.1:9000"},
+	}))
+	assert.Len(t, provider.registered, 1)
+	assert.NotNil(t, plugin.NewServiceDiscovery())
+	assert.Nil(t, plugin.limitClient())
+}
+
+func TestSetSDKLocked_KeepsOverrides(t *testing.T) {
+	consumer := &fakeClients{}
+	plugin := NewPolarisControlPlane(WithConsumerClient(consumer))
+	plugin.mu.Lock()
+	plugin.setSDKLocked(nil)
+	plugin.mu.Unlock()
+	assert.Equal(t, ConsumerClient(consumer), plugin.consumerClient())
+	assert.Nil(t, plugin.providerClient())
+}
+
+// countingSDK is an SDK context counting its destructions.
+type countingSDK struct {
+	api.SDKContext
+	destroys int
+}
+
+func (s *countingSDK) Destroy() { s.destroys++ }
+
+func TestSDKAPIs_CreatedOnceOnDemand(t *testing.T) {
+	sdk := &countingSDK{}
+	plugin := NewPolarisControlPlane()
+	plugin.mu.Lock()
+	plugin.setSDKLocked(sdk)
+	apis := plugin.apis
+	plugin.mu.Unlock()
+	assert.Nil(t, apis.consumer, "no API is created with the SDK context")
+
+	consumer := plugin.consumerClient()
+	require.NotNil(t, consumer)
+	assert.Same(t, consumer, plugin.consumerClient(), "the consumer API is reused")
+	assert.Nil(t, apis.provider)
+	assert.Nil(t, apis.limit)
+
+	plugin.closeSDKConnection()
+	assert.Equal(t, 2, sdk.destroys, "only the consumer API and the context are destroyed")
+	assert.Nil(t, plugin.consumerClient())
+}
+
+func TestNewPluginWithClients(t *testing.T) {
+	instance := NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})
+	consumer := &fakeClients{instances: []model.Instance{instance}}
+	plugin, err := NewPluginWithClients(&conf.Polaris{Namespace: "default", LazyInit: true}, WithConsumerClient(consumer))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = plugin.CleanupTasks() })
+
+	assert.True(t, plugin.IsInitialized())
+	instances, err := plugin.GetServiceInstances("orders")
+	require.NoError(t, err)
+	assert.Equal(t, []model.Instance{instance}, instances)
+	assert.False(t, plugin.lazilyUnconnected(), "lazy_init is ignored")
+	assert.Nil(t, plugin.sdk, "without an SDK context")
+
+	_, err = NewPluginWithClients(&conf.Polaris{Namespace: "bad namespace"})
+	assert.True(t, IsInitError(err))
+}
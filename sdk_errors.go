@@ -0,0 +1,124 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// SDK error translation
+// Responsibility: mapping polaris-go SDK errors and Polaris server response codes to the
+// plugin's error codes, so that the retry manager, the circuit breaker and callers can tell
+// transient failures from authentication problems and missing resources.
+
+// Polaris server response code classes, the response code divided by 1000
+const (
+	polarisCodeClassBadRequest   = 400
+	polarisCodeClassUnauthorized = 401
+	polarisCodeClassForbidden    = 403
+	polarisCodeClassServerError  = 500
+)
+
+// TranslateSDKError returns err as a *PolarisError with the code that its polaris-go SDK
+// error maps to, wrapping it. Errors that already contain a *PolarisError and errors without
+// an SDK error are returned unchanged.
+func TranslateSDKError(err error) error {
+	var polarisErr *PolarisError
+	if err == nil || errors.As(err, &polarisErr) {
+		return err
+	}
+	var sdkErr model.SDKError
+	if !errors.As(err, &sdkErr) {
+		return err
+	}
+	return WrapError(err, sdkErrorCode(sdkErr), "polaris-go request failed")
+}
+
+// sdkErrorCode returns the error code of a polaris-go SDK error: the code of the Polaris
+// server response when it is a known one, else the code of the SDK error.
+func sdkErrorCode(sdkErr model.SDKError) ErrorCode {
+	if code, ok := serverErrorCode(sdkErr.ServerCode()); ok {
+		return code
+	}
+	switch sdkErr.ErrorCode() {
+	case model.ErrCodeAPITimeoutError, model.ErrorCodeRpcTimeout:
+		return ErrCodeTimeout
+	case model.ErrCodeUnauthorized:
+		return ErrCodeUnauthorized
+	case model.ErrCodeServiceNotFound, model.ErrCodeAPIInstanceNotFound:
+		return ErrCodeServiceNotFound
+	case model.ErrCodeLocationNotFound, model.ErrCodeMeshConfigNotFound, model.ErrCodeCmdbNotFound:
+		return ErrCodeResourceNotFound
+	case model.ErrCodeCircuitBreakerError:
+		return ErrCodeCircuitBreakerOpen
+	case model.ErrCodeConnectError, model.ErrCodeNetworkError, model.ErrCodeServerError,
+		model.ErrCodeServerException, model.ErrorCodeRpcError, model.ErrCodeInvalidServerResponse,
+		model.ErrCodeRequestLimit, model.ErrCodeUnknownServerError, model.ErrCodeInvalidStateError:
+		return ErrCodeSDKUnavailable
+	case model.ErrCodeAPIInvalidArgument, model.ErrCodeAPIInvalidConfig, model.ErrCodeInvalidRequest,
+		model.ErrCodeServerUserError, model.ErrCodeRouteRuleNotMatch, model.ErrCodeDstMetaMismatch:
+		return ErrCodeInvalidRequest
+	default:
+		return ErrCodeSDKError
+	}
+}
+
+// serverErrorCode maps a Polaris server response code, from the SDK or the OpenAPI, to an
+// error code. It reports false for success and for codes it does not know.
+func serverErrorCode(code uint32) (ErrorCode, bool) {
+	if code == polarisCodeNotFound {
+		return ErrCodeResourceNotFound, true
+	}
+	switch code / 1000 {
+	case polarisCodeClassUnauthorized, polarisCodeClassForbidden:
+		return ErrCodeUnauthorized, true
+	case polarisCodeClassBadRequest:
+		return ErrCodeInvalidRequest, true
+	case polarisCodeClassServerError:
+		return ErrCodeSDKUnavailable, true
+	default:
+		return "", false
+	}
+}
+
+// newServerResponseError creates the error of a failed Polaris OpenAPI response.
+func newServerResponseError(code int, info string) *PolarisError {
+	errCode, ok := serverErrorCode(uint32(code))
+	if !ok {
+		errCode = ErrCodeServiceUnavailable
+	}
+	return NewPolarisError(errCode, fmt.Sprintf("polaris returned code %d: %s", code, info))
+}
+
+// causeErrorCode maps a polaris-go SDK error or a context error to an error code.
+func causeErrorCode(err error) (ErrorCode, bool) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrCodeTimeout, true
+	}
+	var sdkErr model.SDKError
+	if !errors.As(err, &sdkErr) {
+		return "", false
+	}
+	return sdkErrorCode(sdkErr), true
+}
+
+// IsRetryable reports whether retrying the call that failed with err may succeed, as decided
+// by DefaultErrorClassifier. Authentication failures, missing resources and invalid requests
+// are not retryable.
+func IsRetryable(err error) bool {
+	return err != nil && DefaultErrorClassifier(err) == ErrorClassRetryable
+}
+
+// IsAuthError reports whether err comes from a token or permission that Polaris rejected.
+func IsAuthError(err error) bool {
+	return errors.Is(TranslateSDKError(err), ErrUnauthorized)
+}
+
+// IsNotFoundError reports whether err comes from a service, config file or other resource
+// that Polaris does not know.
+func IsNotFoundError(err error) bool {
+	err = TranslateSDKError(err)
+	return errors.Is(err, ErrServiceNotFound) || errors.Is(err, ErrConfigNotFound) || errors.Is(err, ErrResourceNotFound)
+}
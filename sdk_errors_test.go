@@ -0,0 +1,126 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestTranslateSDKError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code ErrorCode
+	}{
+		{model.NewSDKError(model.ErrCodeAPITimeoutError, nil, "timeout"), ErrCodeTimeout},
+		{model.NewSDKError(model.ErrCodeUnauthorized, nil, "token rejected"), ErrCodeUnauthorized},
+		{model.NewServerSDKError(401003, "token not existed", nil, "get config"), ErrCodeUnauthorized},
+		{model.NewServerSDKError(polarisCodeNotFound, "not found resource", nil, "get config"), ErrCodeResourceNotFound},
+		{model.NewServerSDKError(400110, "invalid service name", nil, "register"), ErrCodeInvalidRequest},
+		{model.NewServerSDKError(500000, "execute exception", nil, "register"), ErrCodeSDKUnavailable},
+		{model.NewSDKError(model.ErrCodeServiceNotFound, nil, "no orders"), ErrCodeServiceNotFound},
+		{model.NewSDKError(model.ErrCodeConnectError, nil, "dial"), ErrCodeSDKUnavailable},
+		{model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "empty service"), ErrCodeInvalidRequest},
+		{model.NewSDKError(model.ErrCodeInternalError, nil, "internal"), ErrCodeSDKError},
+	}
+	for _, tt := range tests {
+		err := TranslateSDKError(tt.err)
+		var polarisErr *PolarisError
+		require.ErrorAs(t, err, &polarisErr, "%v", tt.err)
+		assert.Equal(t, tt.code, polarisErr.Code, "%v", tt.err)
+		assert.ErrorIs(t, err, tt.err)
+	}
+
+	assert.NoError(t, TranslateSDKError(nil))
+	plain := errors.New("boom")
+	assert.Same(t, plain, TranslateSDKError(plain))
+	wrapped := WrapServiceError(model.NewSDKError(model.ErrCodeNetworkError, nil, "reset"), ErrCodeServiceUnavailable, "failed")
+	assert.Same(t, wrapped, TranslateSDKError(wrapped), "plugin errors are kept")
+}
+
+func TestErrorPredicates(t *testing.T) {
+	forbidden := model.NewServerSDKError(403001, "token forbidden", nil, "get instances")
+	assert.True(t, IsAuthError(forbidden))
+	assert.True(t, IsAuthError(WrapServiceError(forbidden, ErrCodeServiceUnavailable, "failed")))
+	assert.False(t, IsRetryable(forbidden))
+
+	unavailable := model.NewSDKError(model.ErrCodeNetworkError, nil, "reset")
+	assert.True(t, IsRetryable(unavailable))
+	assert.False(t, IsAuthError(unavailable))
+	assert.False(t, IsNotFoundError(unavailable))
+
+	assert.True(t, IsNotFoundError(model.NewSDKError(model.ErrCodeServiceNotFound, nil, "no orders")))
+	assert.True(t, IsNotFoundError(model.NewServerSDKError(polarisCodeNotFound, "not found resource", nil, "get config")))
+	assert.True(t, IsNotFoundError(NewServiceError(ErrCodeConfigNotFound, "configFile not found")))
+	assert.False(t, IsRetryable(NewServiceError(ErrCodeConfigNotFound, "configFile not found")))
+	assert.False(t, IsRetryable(nil))
+}
+
+// sdkErrorClients are SDK clients whose calls all fail with err
+type sdkErrorClients struct {
+	api.ConsumerAPI
+	api.ProviderAPI
+	err error
+}
+
+func (c *sdkErrorClients) GetInstances(*api.GetInstancesRequest) (*model.InstancesResponse, error) {
+	return nil, c.err
+}
+
+func (c *sdkErrorClients) Register(*api.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	return nil, c.err
+}
+
+func (c *sdkErrorClients) Deregister(*api.InstanceDeRegisterRequest) error {
+	return c.err
+}
+
+func (c *sdkErrorClients) GetConfigFile(string, string, string) (model.ConfigFile, error) {
+	return nil, c.err
+}
+
+func (c *sdkErrorClients) GetQuota(api.QuotaRequest) (api.QuotaFuture, error) {
+	return nil, c.err
+}
+
+func TestPublicAPIsTranslateSDKErrors(t *testing.T) {
+	tests := []struct {
+		err      error
+		sentinel error
+	}{
+		{model.NewSDKError(model.ErrCodeUnauthorized, nil, "token rejected"), ErrUnauthorized},
+		{model.NewServerSDKError(401003, "token not existed", nil, "request"), ErrUnauthorized},
+		{model.NewSDKError(model.ErrCodeServiceNotFound, nil, "no orders"), ErrServiceNotFound},
+		{model.NewSDKError(model.ErrCodeAPITimeoutError, nil, "timeout"), ErrTimeout},
+		{model.NewSDKError(model.ErrCodeConnectError, nil, "connection refused"), ErrSDKUnavailable},
+	}
+	for _, tt := range tests {
+		clients := &sdkErrorClients{err: tt.err}
+		plugin, err := NewPluginWithClients(
+			&conf.Polaris{MaxRetryTimes: 1, RetryInterval: durationpb.New(time.Millisecond)},
+			WithConsumerClient(clients), WithProviderClient(clients), WithConfigClient(clients), WithLimitClient(clients),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = plugin.CleanupTasks() })
+
+		_, err = plugin.GetServiceInstances("orders")
+		assert.ErrorIs(t, err, tt.sentinel, "GetServiceInstances: %v", tt.err)
+		_, err = plugin.GetConfigValue("app.yaml", "orders")
+		assert.ErrorIs(t, err, tt.sentinel, "GetConfigValue: %v", tt.err)
+		_, err = plugin.CheckRateLimit("orders", nil)
+		assert.ErrorIs(t, err, tt.sentinel, "CheckRateLimit: %v", tt.err)
+
+		service := &registry.ServiceInstance{Name: "orders", Endpoints: []string{"grpc://10.0.0.1:9000"}}
+		registrar := plugin.BuildRegistrar()
+		assert.ErrorIs(t, registrar.Register(context.Background(), service), tt.sentinel, "Register: %v", tt.err)
+		assert.ErrorIs(t, registrar.Deregister(context.Background(), service), tt.sentinel, "Deregister: %v", tt.err)
+	}
+}
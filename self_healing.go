@@ -0,0 +1,199 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	kratospolaris "github.com/go-kratos/kratos/contrib/polaris/v2"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// selfHealingSettings returns the failure duration and cooldown with defaults applied.
+func selfHealingSettings(cfg *conf.SelfHealing) (failureDuration, cooldown time.Duration) {
+	failureDuration, cooldown = conf.DefaultSelfHealingFailureDuration, conf.DefaultSelfHealingCooldown
+	if cfg.GetFailureDuration() != nil && cfg.GetFailureDuration().AsDuration() > 0 {
+		failureDuration = cfg.GetFailureDuration().AsDuration()
+	}
+	if cfg.GetCooldown() != nil && cfg.GetCooldown().AsDuration() > 0 {
+		cooldown = cfg.GetCooldown().AsDuration()
+	}
+	return failureDuration, cooldown
+}
+
+// evaluateSelfHealing tracks how long health checks have been failing, given the result
+// err of the check at now, and rebuilds the SDK context once the SDK context is destroyed or
+// the failures outlast self_healing.failure_duration. Rebuilds are at least
+// self_healing.cooldown apart.
+func (p *PlugPolaris) evaluateSelfHealing(err error, now time.Time) {
+	p.mu.RLock()
+	cfg := p.currentConf().GetSelfHealing()
+	sdk := p.sdk
+	p.mu.RUnlock()
+	if !cfg.GetEnabled() {
+		return
+	}
+	failureDuration, cooldown := selfHealingSettings(cfg)
+
+	p.selfHealMutex.Lock()
+	if err == nil {
+		p.failingSince = time.Time{}
+		p.selfHealMutex.Unlock()
+		return
+	}
+	if p.failingSince.IsZero() {
+		p.failingSince = now
+	}
+	reason := ""
+	switch {
+	case sdk != nil && sdk.IsDestroyed():
+		reason = "SDK context is destroyed"
+	case now.Sub(p.failingSince) >= failureDuration:
+		reason = fmt.Sprintf("health checks failing for %v", now.Sub(p.failingSince).Round(time.Second))
+	}
+	if reason == "" || (!p.lastRecovery.IsZero() && now.Sub(p.lastRecovery) < cooldown) {
+		p.selfHealMutex.Unlock()
+		return
+	}
+	p.lastRecovery = now
+	p.selfHealMutex.Unlock()
+
+	p.raiseAlert(Alert{
+		Type:     AlertSDKRecovery,
+		Severity: AlertSeverityCritical,
+		Subject:  "sdk",
+		Message:  "rebuilding the Polaris SDK context: " + reason,
+		Error:    err.Error(),
+	})
+	if err := p.RecoverSDK(); err != nil {
+		log.Errorf("Self-healing failed to recover the Polaris SDK: %v", p.redactError(err))
+		return
+	}
+	p.selfHealMutex.Lock()
+	p.failingSince = time.Time{}
+	p.selfHealMutex.Unlock()
+}
+
+// RecoverSDK rebuilds the SDK context from the plugin config without restarting the
+// process: it switches the registrar to the new context and registers its instances again,
+// switches the handed-out discovery, recreates the service and config watchers, and then
+// destroys the previous context. Self-healing calls it when health checks keep failing; it
+// can also be called directly. Kratos watchers opened through the discovery before the
+// rebuild keep the previous context and should be reopened.
+func (p *PlugPolaris) RecoverSDK() error {
+	if err := p.checkInitialized(); err != nil {
+		return err
+	}
+	p.recoveryMutex.Lock()
+	defer p.recoveryMutex.Unlock()
+
+	p.mu.RLock()
+	metrics := p.metrics
+	namespace := p.currentConf().GetNamespace()
+	p.mu.RUnlock()
+	if metrics != nil {
+		metrics.RecordSDKOperation("recover_sdk", "start")
+	}
+
+	log.Warnf("Rebuilding Polaris SDK context")
+	sdk, err := p.loadPolarisConfiguration()
+	if err != nil {
+		if metrics != nil {
+			metrics.RecordSDKOperation("recover_sdk", "error")
+		}
+		return WrapInitError(err, "failed to rebuild Polaris SDK")
+	}
+	pol := kratospolaris.New(
+		sdk,
+		kratospolaris.WithService(currentLynxName()),
+		kratospolaris.WithNamespace(namespace),
+	)
+
+	p.mu.Lock()
+	previousAPIs, previousPolaris := p.apis, p.polaris
+	p.setSDKLocked(sdk)
+	p.polaris = &pol
+	registrar, discovery := p.registrar, p.discovery
+	p.mu.Unlock()
+
+	var errs []error
+	if registrar != nil {
+		registrar.setProvider(p.providerClient())
+		if err := registrar.reregister(context.Background()); err != nil {
+			errs = append(errs, fmt.Errorf("failed to register instances again: %w", err))
+		}
+	}
+	if discovery != nil {
+		discovery.setConsumer(p.consumerClient())
+	}
+	services, configs, err := p.recreateWatchers()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	destroyPolarisClient(previousPolaris, namespace)
+	destroySDKResources(previousAPIs, namespace)
+
+	if err := errors.Join(errs...); err != nil {
+		if metrics != nil {
+			metrics.RecordSDKOperation("recover_sdk", "error")
+		}
+		return WrapServiceError(err, ErrCodeServiceUnavailable, "Polaris SDK rebuilt with errors")
+	}
+	if metrics != nil {
+		metrics.RecordSDKOperation("recover_sdk", "success")
+	}
+	log.Infof("Rebuilt Polaris SDK context, recreated %d service watchers and %d config watchers", services, configs)
+	return nil
+}
+
+// recreateWatchers stops the service and config watchers and starts them again through the
+// current SDK context. The new watchers start from the last instances and config of the
+// previous ones, so the rebuild alone does not report changes.
+func (p *PlugPolaris) recreateWatchers() (services, configs int, err error) {
+	p.watcherMutex.Lock()
+	serviceWatchers := p.activeWatchers
+	p.activeWatchers = make(map[string]*ServiceWatcher)
+	configWatchers := p.configWatchers
+	p.configWatchers = make(map[string]*ConfigWatcher)
+	p.watcherMutex.Unlock()
+	return p.reattachWatchers(serviceWatchers, configWatchers)
+}
+
+// reattachWatchers stops the given service and config watchers and watches their services
+// and config files again, starting from their last instances and config.
+func (p *PlugPolaris) reattachWatchers(
+	serviceWatchers map[string]*ServiceWatcher, configWatchers map[string]*ConfigWatcher,
+) (services, configs int, err error) {
+	var errs []error
+	for serviceName, previous := range serviceWatchers {
+		previous.Stop()
+		watcher, err := p.WatchService(serviceName)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to recreate service watcher for %s: %w", serviceName, err))
+			continue
+		}
+		if instances := previous.GetLastInstances(); len(instances) > 0 {
+			watcher.updateInstances(instances)
+		}
+		p.recordWatcherRestart(watcherTypeService, serviceName)
+		services++
+	}
+	for _, previous := range configWatchers {
+		previous.Stop()
+		watcher, err := p.WatchConfig(previous.fileName, previous.group)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to recreate config watcher for %s: %w",
+				configWatcherName(previous.fileName, previous.group), err))
+			continue
+		}
+		if config := previous.GetLastConfig(); config != nil {
+			watcher.updateConfig(config)
+		}
+		p.recordWatcherRestart(watcherTypeConfig, configWatcherName(previous.fileName, previous.group))
+		configs++
+	}
+	return services, configs, errors.Join(errs...)
+}
@@ -0,0 +1,63 @@
+package polaris
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestSelfHealingSettings(t *testing.T) {
+	failureDuration, cooldown := selfHealingSettings(nil)
+	assert.Equal(t, conf.DefaultSelfHealingFailureDuration, failureDuration)
+	assert.Equal(t, conf.DefaultSelfHealingCooldown, cooldown)
+
+	failureDuration, cooldown = selfHealingSettings(&conf.SelfHealing{
+		FailureDuration: durationpb.New(time.Minute), Cooldown: durationpb.New(10 * time.Minute),
+	})
+	assert.Equal(t, time.Minute, failureDuration)
+	assert.Equal(t, 10*time.Minute, cooldown)
+}
+
+func TestEvaluateSelfHealing(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", SelfHealing: &conf.SelfHealing{
+		Enabled: true, FailureDuration: durationpb.New(time.Minute),
+	}})
+	alerter, alerts := alertChannel()
+	require.NoError(t, plugin.AddAlerter("test", alerter, ""))
+	failure := errors.New("unavailable")
+	start := time.Now()
+
+	// Failures shorter than failure_duration, or followed by a success, do not rebuild
+	plugin.evaluateSelfHealing(failure, start)
+	plugin.evaluateSelfHealing(failure, start.Add(30*time.Second))
+	plugin.evaluateSelfHealing(nil, start.Add(40*time.Second))
+	plugin.evaluateSelfHealing(failure, start.Add(50*time.Second))
+	assert.True(t, plugin.lastRecovery.IsZero())
+
+	// The plugin is not initialized, so the rebuild fails after the alert
+	plugin.evaluateSelfHealing(failure, start.Add(2*time.Minute))
+	alert := receiveAlert(t, alerts)
+	assert.Equal(t, AlertSDKRecovery, alert.Type)
+	assert.Contains(t, alert.Message, "health checks failing")
+	assert.Equal(t, start.Add(2*time.Minute), plugin.lastRecovery)
+
+	// Within the cooldown nothing is rebuilt, even with a destroyed SDK context
+	plugin.sdk = &aliveSDK{destroyed: true}
+	plugin.evaluateSelfHealing(failure, start.Add(3*time.Minute))
+	assert.Equal(t, start.Add(2*time.Minute), plugin.lastRecovery)
+
+	plugin.SetAlertDedupWindow(time.Nanosecond)
+	plugin.evaluateSelfHealing(failure, start.Add(8*time.Minute))
+	assert.Contains(t, receiveAlert(t, alerts).Message, "SDK context is destroyed")
+	assert.Equal(t, start.Add(8*time.Minute), plugin.lastRecovery)
+}
+
+func TestRecoverSDK_NotInitialized(t *testing.T) {
+	assert.True(t, IsInitError(NewPolarisControlPlane().RecoverSDK()))
+}
@@ -0,0 +1,253 @@
+package polaris
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/pkg/config"
+)
+
+// lookupSRV resolves SRV records; replaced in tests.
+var lookupSRV = net.DefaultResolver.LookupSRV
+
+// lookupHost resolves the A/AAAA records of a host name; replaced in tests.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// rebuildSDK rebuilds the SDK context of p on refreshed server addresses; replaced in tests.
+var rebuildSDK = (*PlugPolaris).RecoverSDK
+
+// serverAddresses is a resolved set of naming and config server addresses.
+type serverAddresses struct {
+	naming []string
+	config []string
+	// hosts are the sorted A/AAAA records of the host names of naming and config, resolved
+	// when refresh_interval is set
+	hosts map[string][]string
+}
+
+func (a serverAddresses) equal(b serverAddresses) bool {
+	return slices.Equal(a.naming, b.naming) && slices.Equal(a.config, b.config) &&
+		maps.EqualFunc(a.hosts, b.hosts, slices.Equal[[]string])
+}
+
+// resolveHosts resolves the A/AAAA records of the host names of the naming and config
+// addresses. Addresses with an IP literal are skipped.
+func (a serverAddresses) resolveHosts(ctx context.Context) (map[string][]string, error) {
+	hosts := make(map[string][]string)
+	for _, address := range slices.Concat(a.naming, a.config) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil || net.ParseIP(host) != nil {
+			continue
+		}
+		if _, ok := hosts[host]; ok {
+			continue
+		}
+		ips, err := lookupHost(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", host, err)
+		}
+		slices.Sort(ips)
+		hosts[host] = slices.Compact(ips)
+	}
+	return hosts, nil
+}
+
+// hasServerBootstrap reports whether cfg overrides any server address or the limiter
+// service.
+func hasServerBootstrap(cfg *conf.ServerBootstrap) bool {
+	return len(cfg.GetAddresses()) > 0 || len(cfg.GetConfigAddresses()) > 0 || len(cfg.GetHosts()) > 0 ||
+		cfg.GetSrvRecord() != "" || cfg.GetConfigSrvRecord() != "" ||
+		cfg.GetLimiterNamespace() != "" || cfg.GetLimiterService() != ""
+}
+
+// hostAddresses joins hosts with port, or defaultPort when port is zero.
+func hostAddresses(hosts []string, port, defaultPort uint32) []string {
+	if port == 0 {
+		port = defaultPort
+	}
+	addresses := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if host = strings.TrimSpace(host); host != "" {
+			addresses = append(addresses, net.JoinHostPort(host, strconv.Itoa(int(port))))
+		}
+	}
+	return addresses
+}
+
+// resolveServerAddresses combines static addresses with the targets of an SRV record.
+// SRV targets are ordered by priority and then by descending weight.
+func resolveServerAddresses(ctx context.Context, static []string, srvRecord string) ([]string, error) {
+	var addresses []string
+	seen := make(map[string]struct{})
+	add := func(address string) {
+		address = strings.TrimSpace(address)
+		if address == "" {
+			return
+		}
+		if _, ok := seen[address]; ok {
+			return
+		}
+		seen[address] = struct{}{}
+		addresses = append(addresses, address)
+	}
+	for _, address := range static {
+		add(address)
+	}
+	if srvRecord == "" {
+		return addresses, nil
+	}
+
+	_, records, err := lookupSRV(ctx, "", "", srvRecord)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve SRV record %s: %w", srvRecord, err)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Priority != records[j].Priority {
+			return records[i].Priority < records[j].Priority
+		}
+		return records[i].Weight > records[j].Weight
+	})
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+		add(net.JoinHostPort(host, strconv.Itoa(int(record.Port))))
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("SRV record %s has no targets", srvRecord)
+	}
+	return addresses, nil
+}
+
+// resolveServerBootstrap resolves the naming and config server addresses of cfg: the
+// static addresses, then the hosts with their port, then the SRV record targets.
+func resolveServerBootstrap(ctx context.Context, cfg *conf.ServerBootstrap) (serverAddresses, error) {
+	static := append(slices.Clone(cfg.GetAddresses()), hostAddresses(cfg.GetHosts(), cfg.GetDiscoverPort(), conf.DefaultDiscoverPort)...)
+	naming, err := resolveServerAddresses(ctx, static, cfg.GetSrvRecord())
+	if err != nil {
+		return serverAddresses{}, err
+	}
+	static = append(slices.Clone(cfg.GetConfigAddresses()), hostAddresses(cfg.GetHosts(), cfg.GetConfigPort(), conf.DefaultConfigPort)...)
+	configAddresses, err := resolveServerAddresses(ctx, static, cfg.GetConfigSrvRecord())
+	if err != nil {
+		return serverAddresses{}, err
+	}
+	return serverAddresses{naming: naming, config: configAddresses}, nil
+}
+
+// applyServerBootstrap overrides the server addresses of the SDK configuration with the
+// resolved bootstrap addresses, reachable servers first, and sets the limiter service.
+// Lists that resolve to nothing leave the SDK defaults.
+func (p *PlugPolaris) applyServerBootstrap(ctx context.Context, sdkConfig config.Configuration) error {
+	bootstrap := p.activeServerBootstrap()
+	addresses, err := resolveServerBootstrap(ctx, bootstrap)
+	if err != nil {
+		return err
+	}
+	if len(addresses.naming) > 0 {
+		naming := orderByReachability(ctx, addresses.naming)
+		log.Infof("Using Polaris naming server addresses: %v", naming)
+		sdkConfig.GetGlobal().GetServerConnector().SetAddresses(naming)
+	}
+	if len(addresses.config) > 0 {
+		configAddresses := orderByReachability(ctx, addresses.config)
+		log.Infof("Using Polaris config server addresses: %v", configAddresses)
+		sdkConfig.GetConfigFile().GetConfigConnectorConfig().SetAddresses(configAddresses)
+	}
+	if namespace := bootstrap.GetLimiterNamespace(); namespace != "" {
+		sdkConfig.GetProvider().GetRateLimit().SetLimiterNamespace(namespace)
+	}
+	if service := bootstrap.GetLimiterService(); service != "" {
+		sdkConfig.GetProvider().GetRateLimit().SetLimiterService(service)
+	}
+	if serverRefreshInterval(bootstrap) > 0 {
+		// Hosts that do not resolve now are compared as changed by the first refresh
+		addresses.hosts, _ = addresses.resolveHosts(ctx)
+	}
+	p.mu.Lock()
+	p.serverAddresses = addresses
+	p.mu.Unlock()
+	return nil
+}
+
+// serverRefreshInterval returns the server address refresh interval, or zero when refresh
+// is off or no server address is configured.
+func serverRefreshInterval(cfg *conf.ServerBootstrap) time.Duration {
+	if len(cfg.GetAddresses()) == 0 && len(cfg.GetConfigAddresses()) == 0 && len(cfg.GetHosts()) == 0 &&
+		cfg.GetSrvRecord() == "" && cfg.GetConfigSrvRecord() == "" {
+		return 0
+	}
+	if cfg.GetRefreshInterval() == nil || cfg.GetRefreshInterval().AsDuration() <= 0 {
+		return 0
+	}
+	return max(cfg.GetRefreshInterval().AsDuration(), conf.MinServerRefreshInterval)
+}
+
+// refreshServerAddresses re-resolves the bootstrap SRV records and the A/AAAA records of
+// the server host names, and rebuilds the SDK context when they changed, as the SDK builds
+// its server list once. It reports whether the addresses changed. On failure the previous
+// addresses are kept, and the next refresh tries again.
+func (p *PlugPolaris) refreshServerAddresses(ctx context.Context) (bool, error) {
+	p.mu.RLock()
+	bootstrap := p.activeServerBootstrap()
+	current := p.serverAddresses
+	p.mu.RUnlock()
+
+	addresses, err := resolveServerBootstrap(ctx, bootstrap)
+	if err != nil {
+		return false, err
+	}
+	if addresses.hosts, err = addresses.resolveHosts(ctx); err != nil {
+		return false, err
+	}
+	if addresses.equal(current) {
+		return false, nil
+	}
+	log.Warnf("Polaris server addresses changed: naming %v -> %v, config %v -> %v, hosts %v -> %v; "+
+		"rebuilding the SDK context", current.naming, addresses.naming, current.config, addresses.config,
+		current.hosts, addresses.hosts)
+	return true, rebuildSDK(p)
+}
+
+// startServerRefresh starts re-resolving the bootstrap server addresses when a refresh
+// interval is configured. The loop stops with the plugin lifecycle.
+func (p *PlugPolaris) startServerRefresh() {
+	p.mu.RLock()
+	interval := serverRefreshInterval(p.activeServerBootstrap())
+	p.mu.RUnlock()
+	if interval <= 0 {
+		return
+	}
+	ctx := p.watcherContext()
+	log.Infof("Re-resolving Polaris server addresses every %v", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := p.refreshServerAddresses(ctx); err != nil {
+					log.Warnf("Failed to refresh Polaris server addresses, keeping %v: %v",
+						p.ServerAddresses(), p.redactError(err))
+				}
+			}
+		}
+	}()
+}
+
+// ServerAddresses returns the naming server addresses most recently resolved from the
+// server bootstrap, or nil when no bootstrap is configured.
+func (p *PlugPolaris) ServerAddresses() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Clone(p.serverAddresses.naming)
+}
@@ -0,0 +1,221 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// stubLookupSRV replaces lookupSRV for the duration of the test.
+func stubLookupSRV(t *testing.T, records map[string][]*net.SRV) {
+	t.Helper()
+	original := lookupSRV
+	lookupSRV = func(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+		srv, ok := records[name]
+		if !ok {
+			return "", nil, errors.New("no such host")
+		}
+		return name, srv, nil
+	}
+	t.Cleanup(func() { lookupSRV = original })
+}
+
+func TestResolveServerAddresses(t *testing.T) {
+	stubLookupSRV(t, map[string][]*net.SRV{
+		"_polaris._tcp.example.com": {
+			{Target: "b.example.com.", Port: 8091, Priority: 10, Weight: 5},
+			{Target: "a.example.com.", Port: 8091, Priority: 10, Weight: 50},
+			{Target: "backup.example.com.", Port: 8091, Priority: 20},
+		},
+	})
+
+	addresses, err := resolveServerAddresses(context.Background(),
+		[]string{"polaris.example.com:8091", " ", "polaris.example.com:8091"}, "_polaris._tcp.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"polaris.example.com:8091",
+		"a.example.com:8091",
+		"b.example.com:8091",
+		"backup.example.com:8091",
+	}, addresses)
+
+	_, err = resolveServerAddresses(context.Background(), nil, "_missing._tcp.example.com")
+	assert.Error(t, err)
+}
+
+func TestApplyServerBootstrap(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{ServerBootstrap: &conf.ServerBootstrap{
+		Addresses:       []string{"naming.example.com:8091"},
+		ConfigAddresses: []string{"config.example.com:8093"},
+	}})
+	sdkConfig := api.NewConfiguration()
+	require.NoError(t, plugin.applyServerBootstrap(context.Background(), sdkConfig))
+	assert.Equal(t, []string{"naming.example.com:8091"}, sdkConfig.GetGlobal().GetServerConnector().GetAddresses())
+	assert.Equal(t, []string{"config.example.com:8093"}, sdkConfig.GetConfigFile().GetConfigConnectorConfig().GetAddresses())
+	assert.Equal(t, []string{"naming.example.com:8091"}, plugin.ServerAddresses())
+}
+
+// stubLookupHost replaces lookupHost for the duration of the test.
+func stubLookupHost(t *testing.T, hosts map[string][]string) {
+	t.Helper()
+	original := lookupHost
+	lookupHost = func(_ context.Context, host string) ([]string, error) {
+		ips, ok := hosts[host]
+		if !ok {
+			return nil, errors.New("no such host")
+		}
+		return slices.Clone(ips), nil
+	}
+	t.Cleanup(func() { lookupHost = original })
+}
+
+// stubRebuildSDK replaces rebuildSDK for the duration of the test, applying the server
+// bootstrap again like the rebuilt SDK context does, and counts the rebuilds.
+func stubRebuildSDK(t *testing.T) *int {
+	t.Helper()
+	original := rebuildSDK
+	rebuilds := 0
+	rebuildSDK = func(p *PlugPolaris) error {
+		rebuilds++
+		return p.applyServerBootstrap(context.Background(), api.NewConfiguration())
+	}
+	t.Cleanup(func() { rebuildSDK = original })
+	return &rebuilds
+}
+
+func TestRefreshServerAddresses(t *testing.T) {
+	records := map[string][]*net.SRV{
+		"_polaris._tcp.example.com": {{Target: "a.example.com.", Port: 8091}},
+	}
+	stubLookupSRV(t, records)
+	stubLookupHost(t, map[string][]string{"a.example.com": {"10.0.0.1"}, "b.example.com": {"10.0.0.2"}})
+	rebuilds := stubRebuildSDK(t)
+
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{ServerBootstrap: &conf.ServerBootstrap{
+		SrvRecord:       "_polaris._tcp.example.com",
+		RefreshInterval: durationpb.New(time.Minute),
+	}})
+	require.NoError(t, plugin.applyServerBootstrap(context.Background(), api.NewConfiguration()))
+
+	changed, err := plugin.refreshServerAddresses(context.Background())
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Zero(t, *rebuilds)
+
+	records["_polaris._tcp.example.com"] = append(records["_polaris._tcp.example.com"], &net.SRV{Target: "b.example.com.", Port: 8091})
+	changed, err = plugin.refreshServerAddresses(context.Background())
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 1, *rebuilds)
+	assert.Equal(t, []string{"a.example.com:8091", "b.example.com:8091"}, plugin.ServerAddresses())
+
+	// A failed lookup keeps the last good addresses.
+	delete(records, "_polaris._tcp.example.com")
+	_, err = plugin.refreshServerAddresses(context.Background())
+	assert.Error(t, err)
+	assert.Len(t, plugin.ServerAddresses(), 2)
+	assert.Equal(t, 1, *rebuilds)
+}
+
+func TestRefreshServerAddresses_HostRecords(t *testing.T) {
+	hosts := map[string][]string{"polaris.example.com": {"10.0.0.1"}}
+	stubLookupHost(t, hosts)
+	rebuilds := stubRebuildSDK(t)
+
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{ServerBootstrap: &conf.ServerBootstrap{
+		Addresses:       []string{"polaris.example.com:8091", "10.0.0.9:8091"},
+		RefreshInterval: durationpb.New(time.Minute),
+	}})
+	require.NoError(t, plugin.applyServerBootstrap(context.Background(), api.NewConfiguration()))
+
+	changed, err := plugin.refreshServerAddresses(context.Background())
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// The A/AAAA records of a host name changed
+	hosts["polaris.example.com"] = []string{"10.0.0.2", "10.0.0.1"}
+	changed, err = plugin.refreshServerAddresses(context.Background())
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 1, *rebuilds)
+
+	changed, err = plugin.refreshServerAddresses(context.Background())
+	require.NoError(t, err)
+	assert.False(t, changed, "the rebuild records the new records")
+
+	delete(hosts, "polaris.example.com")
+	_, err = plugin.refreshServerAddresses(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, *rebuilds)
+}
+
+func TestServerRefreshInterval(t *testing.T) {
+	assert.Zero(t, serverRefreshInterval(nil))
+	assert.Zero(t, serverRefreshInterval(&conf.ServerBootstrap{RefreshInterval: durationpb.New(time.Minute)}))
+	assert.Zero(t, serverRefreshInterval(&conf.ServerBootstrap{Addresses: []string{"polaris.example.com:8091"}}))
+	assert.Equal(t, time.Minute, serverRefreshInterval(&conf.ServerBootstrap{
+		Addresses: []string{"polaris.example.com:8091"}, RefreshInterval: durationpb.New(time.Minute),
+	}))
+	assert.Equal(t, conf.MinServerRefreshInterval, serverRefreshInterval(&conf.ServerBootstrap{
+		SrvRecord: "_polaris._tcp.example.com", RefreshInterval: durationpb.New(time.Second),
+	}))
+}
+
+func TestValidator_ServerBootstrap(t *testing.T) {
+	cfg := &conf.Polaris{
+		Namespace: "default",
+		Weight:    100,
+		Ttl:       30,
+		ServerBootstrap: &conf.ServerBootstrap{
+			Addresses:       []string{"polaris.example.com:8091", "polaris.example.com"},
+			ConfigAddresses: []string{":8093"},
+		},
+	}
+	result := NewValidator(cfg).Validate()
+	assert.False(t, result.IsValid)
+	assert.Len(t, result.Errors, 2)
+}
+
+func TestApplyServerBootstrap_Hosts(t *testing.T) {
+	original := probeServer
+	probeServer = func(context.Context, string) error { return nil }
+	t.Cleanup(func() { probeServer = original })
+
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{ServerBootstrap: &conf.ServerBootstrap{
+		Addresses:      []string{"naming.example.com:8091"},
+		Hosts:          []string{"polaris-0.example.com", "polaris-1.example.com"},
+		ConfigPort:     9093,
+		LimiterService: "polaris.limiter.ha",
+	}})
+	sdkConfig := api.NewConfiguration()
+	require.NoError(t, plugin.applyServerBootstrap(context.Background(), sdkConfig))
+	assert.Equal(t, []string{"naming.example.com:8091", "polaris-0.example.com:8091", "polaris-1.example.com:8091"},
+		sdkConfig.GetGlobal().GetServerConnector().GetAddresses())
+	assert.Equal(t, []string{"polaris-0.example.com:9093", "polaris-1.example.com:9093"},
+		sdkConfig.GetConfigFile().GetConfigConnectorConfig().GetAddresses())
+	assert.Equal(t, "polaris.limiter.ha", sdkConfig.GetProvider().GetRateLimit().GetLimiterService())
+
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, ServerBootstrap: &conf.ServerBootstrap{
+		Hosts: []string{"polaris:8091"}, DiscoverPort: 70000, FailoverCooldown: durationpb.New(-time.Second),
+	}}
+	var fields []string
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.Contains(t, fields, "server_bootstrap.hosts[0]")
+	assert.Contains(t, fields, "server_bootstrap.discover_port")
+	assert.Contains(t, fields, "server_bootstrap.failover_cooldown")
+}
@@ -0,0 +1,442 @@
+package polaris
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+	"unsafe"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/network"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+	grpcconnector "github.com/polarismesh/polaris-go/plugin/serverconnector/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/stats"
+)
+
+// Server clusters of ServerStatus
+const (
+	ServerClusterNaming = "naming"
+	ServerClusterConfig = "config"
+)
+
+// ServerStatus is the connection state of a Polaris server
+type ServerStatus struct {
+	Cluster string `json:"cluster"`
+	Address string `json:"address"`
+	// Active is set on the server of the latest connection of the cluster
+	Active bool `json:"active"`
+	// Healthy is false while the server is skipped after a failed connection
+	Healthy     bool      `json:"healthy"`
+	LastFailure time.Time `json:"last_failure,omitzero"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// serverCluster is the connection state of the servers of a cluster
+type serverCluster struct {
+	addresses []string
+	active    string
+	// lastFailed is the server of the latest connection attempt when it failed
+	lastFailed string
+	failures   map[string]serverFailure
+}
+
+// serverFailure is the latest failed connection to a server
+type serverFailure struct {
+	at  time.Time
+	err string
+}
+
+// probeServer checks that address accepts TCP connections; replaced in tests.
+var probeServer = func(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// probeServers probes addresses concurrently, returning whether each accepts TCP
+// connections within the probe timeout.
+func probeServers(ctx context.Context, addresses []string) []bool {
+	ctx, cancel := context.WithTimeout(ctx, conf.DefaultServerProbeTimeout)
+	defer cancel()
+	reachable := make([]bool, len(addresses))
+	done := make(chan struct{}, len(addresses))
+	for i, address := range addresses {
+		go func() {
+			reachable[i] = probeServer(ctx, address) == nil
+			done <- struct{}{}
+		}()
+	}
+	for range addresses {
+		<-done
+	}
+	return reachable
+}
+
+// orderByReachability moves the addresses accepting TCP connections before the others,
+// keeping their order, so the SDK, which walks its server list in order, connects to a
+// reachable server first. The order is kept when no address or a single one is configured.
+func orderByReachability(ctx context.Context, addresses []string) []string {
+	if len(addresses) < 2 {
+		return addresses
+	}
+	reachable := probeServers(ctx, addresses)
+	var ordered, down []string
+	for i, address := range addresses {
+		if reachable[i] {
+			ordered = append(ordered, address)
+		} else {
+			down = append(down, address)
+		}
+	}
+	if len(ordered) > 0 && len(down) > 0 {
+		log.Warnf("Polaris servers %v are unreachable, connecting to %v first", down, ordered)
+	}
+	return append(ordered, down...)
+}
+
+// serverFailoverCooldown returns server_bootstrap.failover_cooldown with the default applied.
+func serverFailoverCooldown(cfg *conf.ServerBootstrap) time.Duration {
+	if cfg.GetFailoverCooldown() == nil || cfg.GetFailoverCooldown().AsDuration() <= 0 {
+		return conf.DefaultServerFailoverCooldown
+	}
+	return cfg.GetFailoverCooldown().AsDuration()
+}
+
+// trackServers sets the configured server addresses of cluster, resetting its state.
+func (p *PlugPolaris) trackServers(cluster string, addresses []string) {
+	p.serverMutex.Lock()
+	defer p.serverMutex.Unlock()
+	if p.servers == nil {
+		p.servers = make(map[string]*serverCluster)
+	}
+	p.servers[cluster] = &serverCluster{
+		addresses: slices.Clone(addresses),
+		failures:  make(map[string]serverFailure),
+	}
+}
+
+// skipServer reports whether connecting to address should be skipped: it failed within
+// failover_cooldown and another configured server of cluster has not. When every server
+// failed, all are tried again.
+func (p *PlugPolaris) skipServer(cluster, address string) bool {
+	p.mu.RLock()
+	cooldown := serverFailoverCooldown(p.activeServerBootstrap())
+	p.mu.RUnlock()
+	p.serverMutex.Lock()
+	defer p.serverMutex.Unlock()
+	state := p.servers[cluster]
+	if state == nil {
+		return false
+	}
+	coolingDown := func(address string) bool {
+		failure, ok := state.failures[address]
+		return ok && time.Since(failure.at) < cooldown
+	}
+	if !coolingDown(address) {
+		return false
+	}
+	return slices.ContainsFunc(state.addresses, func(other string) bool {
+		return other != address && !coolingDown(other)
+	})
+}
+
+// recordServerConnection records the result of a connection to address of cluster.
+func (p *PlugPolaris) recordServerConnection(cluster, address string, err error) {
+	metrics := p.metrics
+	p.serverMutex.Lock()
+	if p.servers == nil {
+		p.servers = make(map[string]*serverCluster)
+	}
+	state := p.servers[cluster]
+	if state == nil {
+		state = &serverCluster{failures: make(map[string]serverFailure)}
+		p.servers[cluster] = state
+	}
+	if err != nil {
+		state.failures[address] = serverFailure{at: time.Now(), err: p.redactError(err)}
+		state.lastFailed = address
+		p.serverMutex.Unlock()
+		if metrics != nil {
+			metrics.RecordConnectionError(cluster, "connect")
+		}
+		return
+	}
+	previous := state.active
+	failedOver := state.lastFailed != "" && state.lastFailed != address
+	state.active = address
+	state.lastFailed = ""
+	delete(state.failures, address)
+	p.serverMutex.Unlock()
+
+	if failedOver {
+		log.Warnf("Polaris %s connection failed over to %s", cluster, address)
+	}
+	if metrics != nil {
+		if previous != address {
+			metrics.RecordServerSwitch(cluster, previous, address)
+		}
+		if failedOver {
+			metrics.RecordServerFailover(cluster)
+		}
+	}
+}
+
+// ServerStatus returns the connection state of the configured Polaris servers and of the
+// servers the SDK connected to, ordered by cluster and address.
+func (p *PlugPolaris) ServerStatus() []ServerStatus {
+	p.mu.RLock()
+	cooldown := serverFailoverCooldown(p.activeServerBootstrap())
+	p.mu.RUnlock()
+	p.serverMutex.Lock()
+	defer p.serverMutex.Unlock()
+	var statuses []ServerStatus
+	for cluster, state := range p.servers {
+		addresses := slices.Clone(state.addresses)
+		if state.active != "" && !slices.Contains(addresses, state.active) {
+			addresses = append(addresses, state.active)
+		}
+		for address := range state.failures {
+			if !slices.Contains(addresses, address) {
+				addresses = append(addresses, address)
+			}
+		}
+		for _, address := range addresses {
+			status := ServerStatus{Cluster: cluster, Address: address, Active: address == state.active, Healthy: true}
+			if failure, ok := state.failures[address]; ok {
+				status.LastFailure = failure.at
+				status.LastError = failure.err
+				status.Healthy = time.Since(failure.at) >= cooldown
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Cluster != statuses[j].Cluster {
+			return statuses[i].Cluster < statuses[j].Cluster
+		}
+		return statuses[i].Address < statuses[j].Address
+	})
+	return statuses
+}
+
+// applyServerConnections replaces the connection creators of the naming and config
+// connectors of sdk with ones tracking the active server and skipping failed servers, and
+// dialing over TLS when tls.enabled is set. polaris-go always dials its servers in
+// plaintext, so TLS requires the creators to be replaced. Connectors with a single server
+// keep the polaris-go creator unless TLS is enabled, as there is nothing to fail over to.
+// On failure, sdk is destroyed.
+func (p *PlugPolaris) applyServerConnections(sdk api.SDKContext) (api.SDKContext, error) {
+	tlsConfig, err := newTLSConfig(p.currentConf().GetTls())
+	if err != nil {
+		sdk.Destroy()
+		return nil, err
+	}
+	sdkConfig := sdk.GetConfig()
+	serverConnector := sdkConfig.GetGlobal().GetServerConnector()
+	configConnector := sdkConfig.GetConfigFile().GetConfigConnectorConfig()
+	connectors := []struct {
+		typ       common.Type
+		name      string
+		cluster   string
+		addresses []string
+		config    any
+	}{
+		{common.TypeServerConnector, "grpc", ServerClusterNaming, serverConnector.GetAddresses(), serverConnector.GetPluginConfig("grpc")},
+		{common.TypeConfigConnector, "polaris", ServerClusterConfig, configConnector.GetAddresses(), configConnector.GetPluginConfig("polaris")},
+	}
+	applied := 0
+	for _, c := range connectors {
+		p.trackServers(c.cluster, c.addresses)
+		if !needsServerConnCreator(tlsConfig, c.addresses) {
+			continue
+		}
+		connector, err := sdk.GetPlugins().GetPlugin(c.typ, c.name)
+		if err != nil {
+			continue
+		}
+		manager := connectionManagerOf(connector)
+		if manager == nil {
+			if tlsConfig != nil {
+				sdk.Destroy()
+				return nil, NewConfigError(fmt.Sprintf(
+					"tls.enabled is set but the connection manager of the Polaris %s connector is not accessible", c.cluster))
+			}
+			continue
+		}
+		manager.SetConnCreator(&serverConnCreator{
+			plugin:             p,
+			cluster:            c.cluster,
+			tlsConfig:          tlsConfig,
+			clientInfo:         manager.GetClientInfo(),
+			maxCallRecvMsgSize: maxCallRecvMsgSize(c.config),
+		})
+		applied++
+	}
+	if tlsConfig == nil {
+		return sdk, nil
+	}
+	if applied == 0 {
+		sdk.Destroy()
+		return nil, NewConfigError("tls.enabled is set but the Polaris SDK has no gRPC connector to secure")
+	}
+	log.Infof("Polaris server connections use TLS (server name %q, client certificate %t)",
+		tlsConfig.ServerName, len(tlsConfig.Certificates) > 0)
+	return sdk, nil
+}
+
+// needsServerConnCreator reports whether the connections to addresses need a
+// serverConnCreator: to dial over TLS, or to fail over between several servers.
+func needsServerConnCreator(tlsConfig *tls.Config, addresses []string) bool {
+	return tlsConfig != nil || len(addresses) > 1
+}
+
+// maxCallRecvMsgSize returns the maxCallRecvMsgSize of the plugin config of a polaris-go
+// gRPC connector, or the polaris-go default when it is not set. The config types are
+// unexported, so the field is read through reflection.
+func maxCallRecvMsgSize(pluginConfig any) int {
+	v := reflect.ValueOf(pluginConfig)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		if field := v.FieldByName("MaxCallRecvMsgSize"); field.IsValid() && field.CanInt() && field.Int() > 0 {
+			return int(field.Int())
+		}
+	}
+	return grpcconnector.DefaultMaxCallRecvMsgSize
+}
+
+// connectionManagerOf returns the connection manager of a polaris-go connector plugin,
+// unwrapping the proxies the plugin manager returns. The config connector does not expose
+// it, so its connManager field is read through reflection; TestConnManagerField_PolarisGo
+// fails when a polaris-go upgrade drops the field, and applyServerConnections refuses to
+// start with TLS rather than dial the config servers in plaintext.
+func connectionManagerOf(connector any) network.ConnectionManager {
+	switch proxy := connector.(type) {
+	case *serverconnector.Proxy:
+		return connectionManagerOf(proxy.ServerConnector)
+	case *configconnector.Proxy:
+		return connectionManagerOf(proxy.ConfigConnector)
+	}
+	if c, ok := connector.(interface {
+		GetConnectionManager() network.ConnectionManager
+	}); ok {
+		return c.GetConnectionManager()
+	}
+	v := reflect.ValueOf(connector)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	field, ok := connManagerField(v.Elem().Type())
+	if !ok {
+		return nil
+	}
+	value := v.Elem().FieldByIndex(field.Index)
+	manager, _ := reflect.NewAt(value.Type(), unsafe.Pointer(value.UnsafeAddr())).Elem().Interface().(network.ConnectionManager)
+	return manager
+}
+
+// connManagerField returns the connManager field of a connector struct type.
+func connManagerField(typ reflect.Type) (reflect.StructField, bool) {
+	field, ok := typ.FieldByName("connManager")
+	if !ok || field.Type != reflect.TypeFor[network.ConnectionManager]() {
+		return reflect.StructField{}, false
+	}
+	return field, true
+}
+
+// serverConnCreator dials the Polaris servers like the polaris-go gRPC connectors, over
+// TLS when tlsConfig is set, recording the connections of cluster on plugin
+type serverConnCreator struct {
+	plugin     *PlugPolaris
+	cluster    string
+	tlsConfig  *tls.Config
+	clientInfo *network.ClientInfo
+	// maxCallRecvMsgSize is the configured maxCallRecvMsgSize of the connector; 0 uses
+	// the polaris-go default
+	maxCallRecvMsgSize int
+}
+
+// Name is the protocol of the connections
+func (c *serverConnCreator) Name() string {
+	return "grpc"
+}
+
+// CreateConnection dials address, blocking until the connection is up or timeout. Servers
+// that failed within the failover cooldown are skipped while others are available, so the
+// SDK moves on to its next server without waiting for the timeout.
+func (c *serverConnCreator) CreateConnection(
+	address string, timeout time.Duration, clientInfo *network.ClientInfo,
+) (network.ClosableConn, error) {
+	if c.plugin != nil && c.plugin.skipServer(c.cluster, address) {
+		return nil, fmt.Errorf("skipping Polaris server %s after a failed connection", address)
+	}
+	if clientInfo == nil {
+		clientInfo = c.clientInfo
+	}
+	recvMsgSize := c.maxCallRecvMsgSize
+	if recvMsgSize <= 0 {
+		recvMsgSize = grpcconnector.DefaultMaxCallRecvMsgSize
+	}
+	creds := insecure.NewCredentials()
+	if c.tlsConfig != nil {
+		creds = credentials.NewTLS(c.tlsConfig.Clone())
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithBlock(),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(recvMsgSize)),
+	}
+	if clientInfo != nil && len(clientInfo.GetIPString()) == 0 {
+		opts = append(opts, grpc.WithStatsHandler(&clientAddressHandler{clientInfo: clientInfo}))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	//nolint:staticcheck // WithBlock and DialContext match the polaris-go connectors
+	conn, err := grpc.DialContext(ctx, address, opts...)
+	if c.plugin != nil {
+		c.plugin.recordServerConnection(c.cluster, address, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// clientAddressHandler records the local address of the connections as the client IP
+// reported to Polaris, as the polaris-go gRPC connectors do when no IP is configured
+type clientAddressHandler struct {
+	clientInfo *network.ClientInfo
+}
+
+func (h *clientAddressHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	localAddr := info.LocalAddr.String()
+	if host, _, err := net.SplitHostPort(localAddr); err == nil {
+		h.clientInfo.IP.Store(host)
+	}
+	h.clientInfo.HashKey.Store([]byte(localAddr))
+	return ctx
+}
+
+func (h *clientAddressHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h *clientAddressHandler) HandleRPC(context.Context, stats.RPCStats) {}
+
+func (h *clientAddressHandler) HandleConn(context.Context, stats.ConnStats) {}
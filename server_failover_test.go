@@ -0,0 +1,145 @@
+package polaris
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/network"
+	configconnector "github.com/polarismesh/polaris-go/plugin/configconnector/polaris"
+	grpcconnector "github.com/polarismesh/polaris-go/plugin/serverconnector/grpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type fakeConnManager struct {
+	network.ConnectionManager
+}
+
+// configConnector mimics the polaris-go config connector, which keeps its connection
+// manager unexported
+type configConnector struct {
+	connManager network.ConnectionManager
+}
+
+// serverConnector mimics the polaris-go gRPC server connector
+type serverConnector struct {
+	manager network.ConnectionManager
+}
+
+func (c *serverConnector) GetConnectionManager() network.ConnectionManager {
+	return c.manager
+}
+
+func TestConnectionManagerOf(t *testing.T) {
+	manager := &fakeConnManager{}
+	assert.Same(t, manager, connectionManagerOf(&serverConnector{manager: manager}))
+	assert.Same(t, manager, connectionManagerOf(&configConnector{connManager: manager}))
+	assert.Nil(t, connectionManagerOf(&configConnector{}))
+	assert.Nil(t, connectionManagerOf(struct{}{}))
+}
+
+// TestConnManagerField_PolarisGo guards the reflection in connectionManagerOf: TLS and
+// failover of the config connections need the connManager field of the polaris-go config
+// connector, and the gRPC server connector to expose its connection manager.
+func TestConnManagerField_PolarisGo(t *testing.T) {
+	_, ok := connManagerField(reflect.TypeFor[configconnector.Connector]())
+	require.True(t, ok, "polaris-go config connector no longer has a connManager field")
+	var connector any = &grpcconnector.Connector{}
+	_, ok = connector.(interface {
+		GetConnectionManager() network.ConnectionManager
+	})
+	require.True(t, ok, "polaris-go gRPC server connector no longer exposes GetConnectionManager")
+}
+
+func TestOrderByReachability(t *testing.T) {
+	original := probeServer
+	probeServer = func(_ context.Context, address string) error {
+		if address == "a.example.com:8091" {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	t.Cleanup(func() { probeServer = original })
+
+	assert.Equal(t, []string{"b.example.com:8091", "c.example.com:8091", "a.example.com:8091"},
+		orderByReachability(context.Background(), []string{"a.example.com:8091", "b.example.com:8091", "c.example.com:8091"}))
+	assert.Equal(t, []string{"a.example.com:8091"}, orderByReachability(context.Background(), []string{"a.example.com:8091"}))
+}
+
+func TestServerConnCreator_Failover(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	down := closed.Addr().String()
+	require.NoError(t, closed.Close())
+	up := listener.Addr().String()
+
+	meter := newRecordingMeter()
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", ServerBootstrap: &conf.ServerBootstrap{FailoverCooldown: durationpb.New(time.Minute)}})
+	plugin.metrics = NewMetrics(NewOTelSink(meter))
+	plugin.trackServers(ServerClusterNaming, []string{down, up})
+	creator := &serverConnCreator{plugin: plugin, cluster: ServerClusterNaming, clientInfo: &network.ClientInfo{}}
+
+	_, err = creator.CreateConnection(down, 200*time.Millisecond, nil)
+	require.Error(t, err)
+	conn, err := creator.CreateConnection(up, 5*time.Second, nil)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	// The failed server is skipped while the other one is healthy
+	_, err = creator.CreateConnection(down, 5*time.Second, nil)
+	assert.ErrorContains(t, err, "skipping")
+	assert.False(t, plugin.skipServer(ServerClusterNaming, up))
+
+	statuses := plugin.ServerStatus()
+	require.Len(t, statuses, 2)
+	byAddress := map[string]ServerStatus{statuses[0].Address: statuses[0], statuses[1].Address: statuses[1]}
+	assert.True(t, byAddress[up].Active)
+	assert.True(t, byAddress[up].Healthy)
+	assert.False(t, byAddress[down].Healthy)
+	assert.NotEmpty(t, byAddress[down].LastError)
+
+	meter.collect()
+	assert.Equal(t, 1.0, meter.value("lynx.polaris.server_active{address="+up+",cluster=naming}"))
+	assert.Equal(t, 1.0, meter.value("lynx.polaris.server_failovers_total{cluster=naming}"))
+}
+
+func TestSkipServer_AllFailed(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	plugin.trackServers(ServerClusterConfig, []string{"a:8093", "b:8093"})
+	plugin.recordServerConnection(ServerClusterConfig, "a:8093", errors.New("refused"))
+	assert.True(t, plugin.skipServer(ServerClusterConfig, "a:8093"))
+	plugin.recordServerConnection(ServerClusterConfig, "b:8093", errors.New("refused"))
+	assert.False(t, plugin.skipServer(ServerClusterConfig, "a:8093"), "every server is tried again once all failed")
+	assert.False(t, plugin.skipServer(ServerClusterNaming, "a:8093"))
+}
+
+func TestMaxCallRecvMsgSize(t *testing.T) {
+	type networkConfig struct {
+		MaxCallRecvMsgSize int
+	}
+	assert.Equal(t, 8<<20, maxCallRecvMsgSize(&networkConfig{MaxCallRecvMsgSize: 8 << 20}))
+	assert.Equal(t, grpcconnector.DefaultMaxCallRecvMsgSize, maxCallRecvMsgSize(&networkConfig{}))
+	assert.Equal(t, grpcconnector.DefaultMaxCallRecvMsgSize, maxCallRecvMsgSize((*networkConfig)(nil)))
+	assert.Equal(t, grpcconnector.DefaultMaxCallRecvMsgSize, maxCallRecvMsgSize(nil))
+}
+
+func TestNeedsServerConnCreator(t *testing.T) {
+	assert.False(t, needsServerConnCreator(nil, []string{"a:8091"}))
+	assert.True(t, needsServerConnCreator(nil, []string{"a:8091", "b:8091"}))
+	assert.True(t, needsServerConnCreator(&tls.Config{}, []string{"a:8091"}))
+}
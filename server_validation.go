@@ -0,0 +1,247 @@
+package polaris
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/polarismesh/polaris-go/api"
+	polarisconfig "github.com/polarismesh/polaris-go/pkg/config"
+)
+
+// Server validation
+// Responsibility: checking a configuration against the Polaris server, beyond the static
+// checks of Validate: that the servers are reachable, the token is accepted and the namespace
+// exists. Misconfigured addresses and tokens otherwise surface as runtime errors much later.
+
+// Checks of a ServerValidationResult
+const (
+	ServerCheckStatic    = "static"
+	ServerCheckReachable = "server_reachable"
+	ServerCheckToken     = "token"
+	ServerCheckNamespace = "namespace"
+)
+
+// Statuses of a ServerCheck
+const (
+	ServerCheckPassed = "passed"
+	ServerCheckFailed = "failed"
+	// ServerCheckSkipped marks a check that could not run, e.g. the token check without
+	// config_admin.address
+	ServerCheckSkipped = "skipped"
+)
+
+// ServerCheck is the result of one check of ValidateWithServer.
+type ServerCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// ServerValidationResult is the per-check result of ValidateWithServer.
+type ServerValidationResult struct {
+	Checks []ServerCheck `json:"checks"`
+	// Static is the result of the static validation
+	Static *ValidationResult `json:"static"`
+}
+
+// OK reports whether no check failed.
+func (r *ServerValidationResult) OK() bool {
+	return !slices.ContainsFunc(r.Checks, func(c ServerCheck) bool { return c.Status == ServerCheckFailed })
+}
+
+// Check returns the result of the named check.
+func (r *ServerValidationResult) Check(name string) (ServerCheck, bool) {
+	for _, c := range r.Checks {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return ServerCheck{}, false
+}
+
+// Error returns the messages of the failed checks, or "" when none failed.
+func (r *ServerValidationResult) Error() string {
+	var messages []string
+	for _, c := range r.Checks {
+		if c.Status == ServerCheckFailed {
+			messages = append(messages, fmt.Sprintf("%s: %s", c.Name, c.Message))
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (r *ServerValidationResult) add(name, status, message string) {
+	r.Checks = append(r.Checks, ServerCheck{Name: name, Status: status, Message: message})
+}
+
+// ValidateWithServer runs the static validation, then checks against the Polaris server that:
+//   - one of the naming servers, from server_bootstrap, config_path or the SDK defaults,
+//     accepts TCP connections
+//   - the token is accepted, through the OpenAPI at config_admin.address
+//   - the namespace exists, through the same OpenAPI
+//
+// The server checks are skipped when the static validation fails, and the token and
+// namespace checks when config_admin.address is not set, which adds a CHECK_SKIPPED warning
+// to the static result. ctx bounds the whole validation.
+func (v *Validator) ValidateWithServer(ctx context.Context) *ServerValidationResult {
+	result := &ServerValidationResult{Static: v.Validate()}
+	if !result.Static.IsValid {
+		result.add(ServerCheckStatic, ServerCheckFailed, result.Static.Error())
+		for _, name := range []string{ServerCheckReachable, ServerCheckToken, ServerCheckNamespace} {
+			result.add(name, ServerCheckSkipped, "the configuration is invalid")
+		}
+		return result
+	}
+	result.add(ServerCheckStatic, ServerCheckPassed, "")
+	v.checkServersReachable(ctx, result)
+	v.checkTokenAndNamespace(ctx, result)
+	return result
+}
+
+// checkServersReachable probes the naming server addresses of the configuration.
+func (v *Validator) checkServersReachable(ctx context.Context, result *ServerValidationResult) {
+	addresses, err := v.namingServerAddresses(ctx)
+	if err != nil {
+		result.add(ServerCheckReachable, ServerCheckFailed, fmt.Sprintf("failed to resolve the server addresses: %v", err))
+		return
+	}
+	if len(addresses) == 0 {
+		result.add(ServerCheckReachable, ServerCheckFailed, "no server address is configured")
+		return
+	}
+	var reachable, unreachable []string
+	for i, ok := range probeServers(ctx, addresses) {
+		if ok {
+			reachable = append(reachable, addresses[i])
+		} else {
+			unreachable = append(unreachable, addresses[i])
+		}
+	}
+	switch {
+	case len(reachable) == 0:
+		result.add(ServerCheckReachable, ServerCheckFailed, fmt.Sprintf("no server is reachable: %v", unreachable))
+	case len(unreachable) > 0:
+		result.add(ServerCheckReachable, ServerCheckPassed, fmt.Sprintf("%v reachable, %v unreachable", reachable, unreachable))
+	default:
+		result.add(ServerCheckReachable, ServerCheckPassed, fmt.Sprintf("%v reachable", reachable))
+	}
+}
+
+// namingServerAddresses returns the naming server addresses the SDK would connect to: the
+// ones of server_bootstrap, else of the config_path file, else the SDK defaults.
+func (v *Validator) namingServerAddresses(ctx context.Context) ([]string, error) {
+	if hasServerBootstrap(v.config.GetServerBootstrap()) {
+		addresses, err := resolveServerBootstrap(ctx, v.config.GetServerBootstrap())
+		if err != nil {
+			return nil, err
+		}
+		if len(addresses.naming) > 0 {
+			return addresses.naming, nil
+		}
+	}
+	if path := v.config.GetConfigPath(); path != "" {
+		configuration, err := polarisconfig.LoadConfigurationByFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return configuration.GetGlobal().GetServerConnector().GetAddresses(), nil
+	}
+	return api.NewConfiguration().GetGlobal().GetServerConnector().GetAddresses(), nil
+}
+
+// namespacesPath is the namespace resource of the Polaris OpenAPI.
+const namespacesPath = "/naming/v1/namespaces"
+
+// namespaceResponse is a namespace lookup in the Polaris OpenAPI.
+type namespaceResponse struct {
+	Namespaces []struct {
+		Name string `json:"name"`
+	} `json:"namespaces"`
+}
+
+// has reports whether the response contains the namespace name.
+func (r namespaceResponse) has(name string) bool {
+	for _, ns := range r.Namespaces {
+		if ns.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTokenAndNamespace looks the namespace up through the OpenAPI with the token.
+func (v *Validator) checkTokenAndNamespace(ctx context.Context, result *ServerValidationResult) {
+	if v.config.GetConfigAdmin().GetAddress() == "" {
+		result.Static.AddCodedWarning(ValidationCodeCheckSkipped, "config_admin.address",
+			"token and namespace not checked against the server (set config_admin.address to check them)", "")
+		result.add(ServerCheckToken, ServerCheckSkipped, "config_admin.address is not set")
+		result.add(ServerCheckNamespace, ServerCheckSkipped, "config_admin.address is not set")
+		return
+	}
+	token, err := v.token(ctx)
+	if err != nil {
+		result.add(ServerCheckToken, ServerCheckFailed, fmt.Sprintf("failed to read the token: %v", err))
+		result.add(ServerCheckNamespace, ServerCheckSkipped, "the token could not be read")
+		return
+	}
+	httpClient, err := newOpenAPIHTTPClient(v.config)
+	if err != nil {
+		result.add(ServerCheckToken, ServerCheckFailed, err.Error())
+		result.add(ServerCheckNamespace, ServerCheckSkipped, "the OpenAPI client could not be created")
+		return
+	}
+	client := newOpenAPIClient(v.config, token, httpClient)
+
+	namespace := v.config.GetNamespace()
+	var response namespaceResponse
+	_, err = client.do(ctx, http.MethodGet, namespacesPath, url.Values{"name": {namespace}}, nil, &response)
+	switch {
+	case IsAuthError(err):
+		result.add(ServerCheckToken, ServerCheckFailed, fmt.Sprintf("the token was rejected: %v", err))
+		result.add(ServerCheckNamespace, ServerCheckSkipped, "the token was rejected")
+		return
+	case err != nil:
+		result.add(ServerCheckToken, ServerCheckFailed, fmt.Sprintf("failed to call the OpenAPI: %v", err))
+		result.add(ServerCheckNamespace, ServerCheckSkipped, "the OpenAPI could not be called")
+		return
+	case token == "":
+		result.add(ServerCheckToken, ServerCheckSkipped, "no token is configured")
+	default:
+		result.add(ServerCheckToken, ServerCheckPassed, "")
+	}
+	if response.has(namespace) {
+		result.add(ServerCheckNamespace, ServerCheckPassed, "")
+		return
+	}
+	result.add(ServerCheckNamespace, ServerCheckFailed, fmt.Sprintf("namespace %s does not exist", namespace))
+}
+
+// token returns the token of the validated configuration: the one ValidateWithServer was
+// given by the plugin, else the inline token, else the one of token_source.
+func (v *Validator) token(ctx context.Context) (string, error) {
+	if v.serverToken != "" {
+		return v.serverToken, nil
+	}
+	if v.config.GetToken() != "" {
+		return v.config.GetToken(), nil
+	}
+	if provider := tokenProviderFromConfig(v.config.GetTokenSource()); provider != nil {
+		return provider.Token(ctx)
+	}
+	return "", nil
+}
+
+// ValidateWithServer validates the plugin configuration against the Polaris server, like
+// Validator.ValidateWithServer, with the token the plugin currently uses.
+func (p *PlugPolaris) ValidateWithServer(ctx context.Context) *ServerValidationResult {
+	p.mu.RLock()
+	cfg := p.currentConf()
+	p.mu.RUnlock()
+	validator := NewValidator(cfg)
+	validator.serverToken = p.currentToken()
+	return validator.ValidateWithServer(ctx)
+}
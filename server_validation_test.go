@@ -0,0 +1,101 @@
+package polaris
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newNamespaceServer serves the namespace lookups of the Polaris OpenAPI, rejecting every
+// token but polaris-token-1.
+func newNamespaceServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, namespacesPath, r.URL.Path)
+		if r.Header.Get("X-Polaris-Token") != "polaris-token-1" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 401000, "info": "access is not approved"})
+			return
+		}
+		var namespaces []map[string]string
+		if name := r.URL.Query().Get("name"); name == "default" {
+			namespaces = append(namespaces, map[string]string{"name": name})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": polarisCodeSuccess, "namespaces": namespaces})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func closedAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return address
+}
+
+func TestValidateWithServer(t *testing.T) {
+	server := newNamespaceServer(t)
+	newConfig := func(namespace, token string) *conf.Polaris {
+		cfg := &conf.Polaris{
+			Namespace:       namespace,
+			Token:           token,
+			ServerBootstrap: &conf.ServerBootstrap{Addresses: []string{closedAddress(t), server.Listener.Addr().String()}},
+			ConfigAdmin:     &conf.ConfigAdmin{Address: server.URL},
+		}
+		setConfigDefaults(cfg)
+		return cfg
+	}
+
+	result := NewValidator(newConfig("default", "polaris-token-1")).ValidateWithServer(context.Background())
+	assert.True(t, result.OK(), result.Error())
+	for _, name := range []string{ServerCheckStatic, ServerCheckReachable, ServerCheckToken, ServerCheckNamespace} {
+		check, ok := result.Check(name)
+		require.True(t, ok, name)
+		assert.Equal(t, ServerCheckPassed, check.Status, name)
+	}
+	reachable, _ := result.Check(ServerCheckReachable)
+	assert.Contains(t, reachable.Message, "unreachable", "unreachable servers are reported")
+
+	result = NewValidator(newConfig("orders", "polaris-token-1")).ValidateWithServer(context.Background())
+	assert.False(t, result.OK())
+	namespace, _ := result.Check(ServerCheckNamespace)
+	assert.Equal(t, ServerCheckFailed, namespace.Status)
+	assert.Contains(t, result.Error(), "namespace orders does not exist")
+
+	result = NewValidator(newConfig("default", "rejected-token")).ValidateWithServer(context.Background())
+	token, _ := result.Check(ServerCheckToken)
+	assert.Equal(t, ServerCheckFailed, token.Status)
+	assert.Contains(t, token.Message, "the token was rejected")
+	namespace, _ = result.Check(ServerCheckNamespace)
+	assert.Equal(t, ServerCheckSkipped, namespace.Status)
+}
+
+func TestValidateWithServer_Unreachable(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", ServerBootstrap: &conf.ServerBootstrap{Addresses: []string{closedAddress(t)}}}
+	setConfigDefaults(cfg)
+
+	result := NewValidator(cfg).ValidateWithServer(context.Background())
+	assert.False(t, result.OK())
+	reachable, _ := result.Check(ServerCheckReachable)
+	assert.Equal(t, ServerCheckFailed, reachable.Status)
+	token, _ := result.Check(ServerCheckToken)
+	assert.Equal(t, ServerCheckSkipped, token.Status, "without config_admin.address")
+	require.NotEmpty(t, result.Static.Warnings)
+	skipped := result.Static.Warnings[len(result.Static.Warnings)-1]
+	assert.Equal(t, ValidationCodeCheckSkipped, skipped.Code)
+	assert.Equal(t, "config_admin.address", skipped.Field)
+
+	cfg.Ttl = 1
+	result = NewValidator(cfg).ValidateWithServer(context.Background())
+	static, _ := result.Check(ServerCheckStatic)
+	assert.Equal(t, ServerCheckFailed, static.Status)
+	reachable, _ = result.Check(ServerCheckReachable)
+	assert.Equal(t, ServerCheckSkipped, reachable.Status, "the server is not checked when the configuration is invalid")
+}
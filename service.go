@@ -1,6 +1,9 @@
 package polaris
 
 import (
+	"context"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/polarismesh/polaris-go/api"
@@ -9,27 +12,76 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
-// GetServiceInstances gets service instances
-func (p *PlugPolaris) GetServiceInstances(serviceName string) ([]model.Instance, error) {
+// GetServiceInstances gets service instances. Instances isolated by Polaris circuit breaking
+// are left out unless all of them are. When the service has no healthy instances and a
+// route fallback is configured, the instances of the fallback target are returned.
+// Options such as WithPriorityPreference narrow the result further.
+func (p *PlugPolaris) GetServiceInstances(serviceName string, opts ...InstanceOption) ([]model.Instance, error) {
+	var options instanceOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+
+	instances, err := p.getServiceInstances(serviceName, options.namespaces...)
+	if err != nil && !IsServiceError(err) {
+		return nil, err
+	}
+	instances = withoutOpenCircuits(instances)
+	if len(healthyInstances(instances)) == 0 {
+		if fallback := p.routeFallback(serviceName); len(fallback) > 0 {
+			instances, err = fallback, nil
+		}
+	}
+	if options.preferPriority {
+		instances = preferredInstances(instances)
+	}
+	return instances, err
+}
+
+// getServiceInstances gets service instances through discovery, falling back to cached and
+// static instances when discovery fails. Route fallbacks are not applied. The instances of
+// namespaces, or of discovery_aggregation when none are given, are merged with those of the
+// plugin namespace. Concurrent calls for the same service share one discovery call.
+func (p *PlugPolaris) getServiceInstances(serviceName string, namespaces ...string) ([]model.Instance, error) {
+	key := "instances\x00" + serviceName + "\x00" + strings.Join(namespaces, ",")
+	v, err, shared := p.fetches.Do(key, func() (any, error) {
+		return p.fetchServiceInstances(serviceName, true, namespaces...)
+	})
+	instances, _ := v.([]model.Instance)
+	if shared {
+		// Callers may reorder or filter the slice they get
+		instances = slices.Clone(instances)
+	}
+	return instances, err
+}
+
+// fetchServiceInstances gets service instances for getServiceInstances, falling back to
+// cached and static instances when discovery fails only with fallback.
+func (p *PlugPolaris) fetchServiceInstances(serviceName string, fallback bool, namespaces ...string) ([]model.Instance, error) {
 	if err := p.checkInitialized(); err != nil {
 		return nil, err
 	}
+	if err := p.checkSubsystem(SubsystemDiscovery); err != nil {
+		return nil, err
+	}
 
-	// Snapshot sdk/namespace/metrics/breaker under the lock to avoid a data race
+	// Snapshot consumer/namespace/metrics/breaker under the lock to avoid a data race
 	// and nil-pointer panic if cleanup runs concurrently with this request.
 	p.mu.RLock()
-	sdk := p.sdk
+	consumer := p.consumerLocked()
 	namespace := ""
-	if p.conf != nil {
-		namespace = p.conf.Namespace
+	if cfg := p.currentConf(); cfg != nil {
+		namespace = cfg.Namespace
 	}
 	metrics := p.metrics
 	circuitBreaker := p.circuitBreaker
 	retryManager := p.retryManager
 	p.mu.RUnlock()
 
-	if sdk == nil || circuitBreaker == nil || retryManager == nil {
-		return nil, NewInitError("Polaris plugin has been destroyed")
+	if consumer == nil || circuitBreaker == nil || retryManager == nil {
+		return nil, newDestroyedError()
 	}
 
 	// Record service discovery operation metrics
@@ -43,20 +95,58 @@ func (p *PlugPolaris) GetServiceInstances(serviceName string) ([]model.Instance,
 	}
 
 	log.Infof("Getting service instances for: %s", serviceName)
+	start := time.Now()
 
-	// Execute operation with circuit breaker and retry mechanism
+	if len(namespaces) == 0 {
+		namespaces = p.aggregatedNamespaces(serviceName)
+	}
+	var instances []model.Instance
+	var err error
+	if len(namespaces) > 0 {
+		instances, err = p.aggregateInstances(serviceName, aggregationNamespaces(namespace, namespaces),
+			func(namespace string) ([]model.Instance, error) {
+				return p.queryInstances(consumer, circuitBreaker, retryManager, serviceName, namespace)
+			})
+	} else {
+		instances, err = p.queryInstances(consumer, circuitBreaker, retryManager, serviceName, namespace)
+	}
+	if metrics != nil {
+		metrics.RecordServiceDiscoveryDuration(serviceName, namespace, time.Since(start).Seconds())
+	}
+	if err != nil {
+		log.Errorf("Failed to get instances for service %s after retries: %v", serviceName, p.redactError(err))
+		if metrics != nil {
+			metrics.RecordServiceDiscovery(serviceName, namespace, "error")
+			metrics.RecordOperationError("get_instances", err)
+		}
+
+		// Fall back to cached, then static instances
+		if instances, source := p.discoveryFallback(serviceName); fallback && len(instances) > 0 {
+			log.Warnf("Serving %d %s fallback instances for service %s", len(instances), source, serviceName)
+			if metrics != nil {
+				metrics.RecordServiceDiscovery(serviceName, namespace, "fallback_"+source)
+			}
+			return instances, nil
+		}
+
+		return nil, WrapServiceError(TranslateSDKError(err), ErrCodeServiceUnavailable, "failed to get service instances")
+	}
+
+	log.Infof("Successfully got %d instances for service %s", len(instances), serviceName)
+	return instances, nil
+}
+
+// queryInstances gets the instances of serviceName in namespace through the circuit breaker
+// and retry manager, hedging slow discovery calls.
+func (p *PlugPolaris) queryInstances(
+	consumer ConsumerClient, circuitBreaker *CircuitBreaker, retryManager *RetryManager, serviceName, namespace string,
+) ([]model.Instance, error) {
 	var instances []model.Instance
 	var lastErr error
 
 	// Wrap retry operation with circuit breaker
 	err := circuitBreaker.Do(func() error {
-		return retryManager.DoWithRetry(func() error {
-			// Create Consumer API client
-			consumerAPI := api.NewConsumerAPIByContext(sdk)
-			if consumerAPI == nil {
-				return NewInitError("failed to create consumer API")
-			}
-
+		result, err := DoWithHedging(context.Background(), retryManager, func(context.Context) ([]model.Instance, error) {
 			// Build service discovery request
 			req := &api.GetInstancesRequest{
 				GetInstancesRequest: model.GetInstancesRequest{
@@ -66,27 +156,22 @@ func (p *PlugPolaris) GetServiceInstances(serviceName string) ([]model.Instance,
 			}
 
 			// Call SDK API to get service instances
-			resp, err := consumerAPI.GetInstances(req)
+			resp, err := consumer.GetInstances(req)
 			if err != nil {
-				lastErr = err
-				return err
+				return nil, err
 			}
-
-			instances = resp.Instances
-			return nil
+			return resp.Instances, nil
 		})
+		if err != nil {
+			lastErr = err
+			return err
+		}
+		instances = result
+		return nil
 	})
-
 	if err != nil {
-		log.Errorf("Failed to get instances for service %s after retries: %v", serviceName, err)
-		if metrics != nil {
-			metrics.RecordServiceDiscovery(serviceName, namespace, "error")
-		}
-
-		return nil, WrapServiceError(lastErr, ErrCodeServiceUnavailable, "failed to get service instances")
+		return nil, lastErr
 	}
-
-	log.Infof("Successfully got %d instances for service %s", len(instances), serviceName)
 	return instances, nil
 }
 
@@ -95,6 +180,9 @@ func (p *PlugPolaris) WatchService(serviceName string) (*ServiceWatcher, error)
 	if err := p.checkInitialized(); err != nil {
 		return nil, err
 	}
+	if err := p.checkSubsystem(SubsystemDiscovery); err != nil {
+		return nil, err
+	}
 
 	// Record service watch operation metrics
 	if p.metrics != nil {
@@ -111,15 +199,16 @@ func (p *PlugPolaris) WatchService(serviceName string) (*ServiceWatcher, error)
 	// Snapshot mutable plugin state under the lock to avoid a data race / nil
 	// dereference if cleanup runs concurrently.
 	p.mu.RLock()
-	sdk := p.sdk
+	consumer := p.consumerLocked()
 	namespace := ""
-	if p.conf != nil {
-		namespace = p.conf.Namespace
+	if cfg := p.currentConf(); cfg != nil {
+		namespace = cfg.Namespace
 	}
+	metrics := p.metrics
 	p.mu.RUnlock()
 
-	if sdk == nil {
-		return nil, NewInitError("Polaris plugin has been destroyed")
+	if consumer == nil {
+		return nil, newDestroyedError()
 	}
 
 	// First check (read lock)
@@ -131,15 +220,16 @@ func (p *PlugPolaris) WatchService(serviceName string) (*ServiceWatcher, error)
 	}
 	p.watcherMutex.RUnlock()
 
-	// Create Consumer API client
-	consumerAPI := api.NewConsumerAPIByContext(sdk)
-	if consumerAPI == nil {
-		return nil, NewInitError("failed to create consumer API")
+	// Create service watcher and connect to SDK, restricted to the local partition if configured
+	watcher := NewServiceWatcherWithContext(p.watcherContext(), consumer, serviceName, namespace)
+	watcher.metrics = metrics
+	watcher.SetClock(p.clock)
+	watcher.setDispatcher(p.watchDispatcher())
+	if partition := p.watchPartitionFor(serviceName); partition != nil {
+		watcher.setPartition(partition)
+		log.Infof("Watching partition %s of service %s", partition, serviceName)
 	}
 
-	// Create service watcher and connect to SDK
-	watcher := NewServiceWatcherWithContext(p.watcherContext(), consumerAPI, serviceName, namespace)
-
 	// Second check (write lock) - double-checked locking pattern
 	p.watcherMutex.Lock()
 	defer p.watcherMutex.Unlock()
@@ -208,37 +298,61 @@ func (p *PlugPolaris) checkServiceHealth(serviceName string, instances []model.I
 	}
 }
 
-// tryStartServiceWatchRetry marks service as retrying and returns true if this goroutine should run the retry.
-// Returns false if a retry is already in progress for this service (deduplication).
-func (p *PlugPolaris) tryStartServiceWatchRetry(serviceName string) bool {
+// serviceWatcherContext returns the context of the registered service watcher, or the
+// plugin lifecycle context when no watcher is registered for the service.
+func (p *PlugPolaris) serviceWatcherContext(serviceName string) context.Context {
+	p.watcherMutex.RLock()
+	watcher := p.activeWatchers[serviceName]
+	p.watcherMutex.RUnlock()
+	if watcher != nil && watcher.ctx != nil {
+		return watcher.ctx
+	}
+	return p.watcherContext()
+}
+
+// watchRetryEntry is an in-flight watch retry in the retrying sets. Retries finish by entry,
+// so that a retry finishing after cleanup never removes a later retry of the same watcher.
+type watchRetryEntry struct {
+	cancel context.CancelFunc
+}
+
+// tryStartServiceWatchRetry marks service as retrying and returns a retry context derived
+// from parent. Returns false if a retry is already in progress for this service (deduplication).
+func (p *PlugPolaris) tryStartServiceWatchRetry(parent context.Context, serviceName string) (context.Context, *watchRetryEntry, bool) {
 	p.retryMutex.Lock()
 	defer p.retryMutex.Unlock()
 	if p.retryingServiceWatchers == nil {
-		return false // plugin destroyed or not initialized
+		return nil, nil, false // plugin destroyed or not initialized
 	}
 	if _, exists := p.retryingServiceWatchers[serviceName]; exists {
-		return false
+		return nil, nil, false
 	}
-	p.retryingServiceWatchers[serviceName] = struct{}{}
-	return true
+	ctx, cancel := context.WithCancel(parent)
+	retry := &watchRetryEntry{cancel: cancel}
+	p.retryingServiceWatchers[serviceName] = retry
+	p.retryWg.Add(1)
+	return ctx, retry, true
 }
 
-// finishServiceWatchRetry removes service from retrying set (call when retry completes).
-func (p *PlugPolaris) finishServiceWatchRetry(serviceName string) {
+// finishServiceWatchRetry releases the context of retry and removes it from the retrying set,
+// unless a later retry of the service replaced it.
+func (p *PlugPolaris) finishServiceWatchRetry(serviceName string, retry *watchRetryEntry) {
+	retry.cancel()
 	p.retryMutex.Lock()
 	defer p.retryMutex.Unlock()
-	if p.retryingServiceWatchers != nil {
+	if p.retryingServiceWatchers[serviceName] == retry {
 		delete(p.retryingServiceWatchers, serviceName)
 	}
 }
 
-// retryServiceWatch retries service watch
-func (p *PlugPolaris) retryServiceWatch(serviceName string) {
-	defer p.finishServiceWatchRetry(serviceName)
-	log.Infof("Retrying service watch for %s", serviceName)
+// retryServiceWatch recreates the watcher of serviceName once its retry is due, unless the
+// retry was canceled.
+func (p *PlugPolaris) retryServiceWatch(serviceName string, retry *watchRetryEntry, canceled bool) {
+	defer p.retryWg.Done()
+	defer p.finishServiceWatchRetry(serviceName, retry)
 
-	if p.waitForRetryDelay(5 * time.Second) {
-		log.Infof("Service watch retry canceled due to plugin shutdown: %s", serviceName)
+	if canceled {
+		log.Infof("Service watch retry canceled (watcher stopped or plugin shutdown): %s", serviceName)
 		return
 	}
 
@@ -248,9 +362,10 @@ func (p *PlugPolaris) retryServiceWatch(serviceName string) {
 
 	// Recreate watcher
 	if _, err := p.WatchService(serviceName); err == nil {
+		p.recordWatcherRestart(watcherTypeService, serviceName)
 		log.Infof("Successfully recreated service watcher for %s", serviceName)
 	} else {
-		log.Errorf("Failed to recreate service watcher for %s: %v", serviceName, err)
+		log.Errorf("Failed to recreate service watcher for %s: %v", serviceName, p.redactError(err))
 	}
 }
 
@@ -260,10 +375,15 @@ func (p *PlugPolaris) useCachedServiceInstances(serviceName string) {
 	// Here you can implement logic to get service instances from cache
 }
 
-// switchToBackupDiscovery switches to backup service discovery
+// switchToBackupDiscovery reports whether static fallback instances can back discovery for the service.
+// GetServiceInstances and PolarisDiscovery consult the fallback chain on every failed lookup.
 func (p *PlugPolaris) switchToBackupDiscovery(serviceName string) {
-	log.Infof("Switching to backup discovery for %s", serviceName)
-	// Here you can implement logic to switch to backup service discovery
+	instances := p.GetFallbackInstances(serviceName)
+	if len(instances) == 0 {
+		log.Warnf("No static fallback instances configured for %s, discovery relies on cache only", serviceName)
+		return
+	}
+	log.Infof("Switching to backup discovery for %s: %d static fallback instances available", serviceName, len(instances))
 }
 
 // notifyDegradationMode logs a degradation-mode activation for the given service.
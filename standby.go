@@ -0,0 +1,201 @@
+package polaris
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// Polaris clusters of ActiveCluster
+const (
+	ClusterPrimary = "primary"
+	ClusterStandby = "standby"
+)
+
+// standbySettings returns standby.failover_after and standby.switchback_after with defaults
+// applied.
+func standbySettings(cfg *conf.Standby) (failoverAfter, switchbackAfter time.Duration) {
+	failoverAfter, switchbackAfter = conf.DefaultStandbyFailoverAfter, conf.DefaultStandbySwitchbackAfter
+	if cfg.GetFailoverAfter() != nil && cfg.GetFailoverAfter().AsDuration() > 0 {
+		failoverAfter = cfg.GetFailoverAfter().AsDuration()
+	}
+	if cfg.GetSwitchbackAfter() != nil && cfg.GetSwitchbackAfter().AsDuration() > 0 {
+		switchbackAfter = cfg.GetSwitchbackAfter().AsDuration()
+	}
+	return failoverAfter, switchbackAfter
+}
+
+// hasStandby reports whether cfg sets a standby cluster.
+func hasStandby(cfg *conf.Standby) bool {
+	return hasServerBootstrap(cfg.GetServerBootstrap())
+}
+
+// ActiveCluster returns the Polaris cluster the plugin is connected to: ClusterPrimary or
+// ClusterStandby.
+func (p *PlugPolaris) ActiveCluster() string {
+	if atomic.LoadInt32(&p.standbyActive) == 1 {
+		return ClusterStandby
+	}
+	return ClusterPrimary
+}
+
+// activeServerBootstrap returns the server bootstrap of the active cluster.
+func (p *PlugPolaris) activeServerBootstrap() *conf.ServerBootstrap {
+	cfg := p.currentConf()
+	if p.ActiveCluster() == ClusterStandby {
+		return cfg.GetStandby().GetServerBootstrap()
+	}
+	return cfg.GetServerBootstrap()
+}
+
+// evaluateStandby switches to the standby cluster once health checks, given the result err
+// of the check at now, have been failing on the primary cluster for standby.failover_after,
+// and back once the primary cluster has been reachable for standby.switchback_after. It
+// reports whether it attempted a switch, so self-healing does not also rebuild the SDK
+// context.
+func (p *PlugPolaris) evaluateStandby(ctx context.Context, err error, now time.Time) bool {
+	p.mu.RLock()
+	cfg := p.currentConf().GetStandby()
+	sdk := p.sdk
+	p.mu.RUnlock()
+	if !hasStandby(cfg) {
+		return false
+	}
+	failoverAfter, switchbackAfter := standbySettings(cfg)
+
+	if p.ActiveCluster() == ClusterPrimary {
+		p.standbyMutex.Lock()
+		if err == nil {
+			p.primaryFailingSince = time.Time{}
+			p.standbyMutex.Unlock()
+			return false
+		}
+		if p.primaryFailingSince.IsZero() {
+			p.primaryFailingSince = now
+		}
+		failing := now.Sub(p.primaryFailingSince)
+		if failing < failoverAfter {
+			p.standbyMutex.Unlock()
+			return false
+		}
+		p.primaryFailingSince = time.Time{}
+		if sdk != nil {
+			p.primaryAddresses = slices.Clone(sdk.GetConfig().GetGlobal().GetServerConnector().GetAddresses())
+		}
+		p.standbyMutex.Unlock()
+		reason := fmt.Sprintf("health checks failing on the primary cluster for %v: %s",
+			failing.Round(time.Second), p.redactError(err))
+		_ = p.switchCluster(ClusterStandby, reason)
+		return true
+	}
+
+	reachable := p.primaryReachable(ctx)
+	p.standbyMutex.Lock()
+	if !reachable {
+		p.primaryHealthySince = time.Time{}
+		p.standbyMutex.Unlock()
+		return false
+	}
+	if p.primaryHealthySince.IsZero() {
+		p.primaryHealthySince = now
+	}
+	healthy := now.Sub(p.primaryHealthySince)
+	if healthy < switchbackAfter {
+		p.standbyMutex.Unlock()
+		return false
+	}
+	p.primaryHealthySince = time.Time{}
+	p.standbyMutex.Unlock()
+	reason := fmt.Sprintf("primary cluster reachable for %v", healthy.Round(time.Second))
+	_ = p.switchCluster(ClusterPrimary, reason)
+	return true
+}
+
+// primaryReachable reports whether a naming server of the primary cluster accepts TCP
+// connections. The addresses come from server_bootstrap, or from the SDK context used on
+// the primary cluster before the switch.
+func (p *PlugPolaris) primaryReachable(ctx context.Context) bool {
+	p.mu.RLock()
+	bootstrap := p.currentConf().GetServerBootstrap()
+	p.mu.RUnlock()
+	var addresses []string
+	if hasServerBootstrap(bootstrap) {
+		resolved, err := resolveServerBootstrap(ctx, bootstrap)
+		if err != nil {
+			log.Warnf("Failed to resolve the primary Polaris cluster: %v", err)
+			return false
+		}
+		addresses = resolved.naming
+	} else {
+		p.standbyMutex.Lock()
+		addresses = p.primaryAddresses
+		p.standbyMutex.Unlock()
+	}
+	return slices.Contains(probeServers(ctx, addresses), true)
+}
+
+// SwitchCluster connects the plugin to cluster, ClusterPrimary or ClusterStandby, now: it
+// rebuilds the SDK context on the servers of the cluster, registers the instances of the
+// registrar there and recreates the watchers, like RecoverSDK. Instances stay registered on
+// the previous cluster until their heartbeat TTL expires. When the SDK context cannot be
+// built on the cluster, the plugin stays on the previous one.
+func (p *PlugPolaris) SwitchCluster(cluster string) error {
+	if cluster != ClusterPrimary && cluster != ClusterStandby {
+		return NewConfigError(fmt.Sprintf("unknown Polaris cluster %q", cluster))
+	}
+	p.mu.RLock()
+	standby := p.currentConf().GetStandby()
+	p.mu.RUnlock()
+	if cluster == ClusterStandby && !hasStandby(standby) {
+		return NewConfigError("no standby Polaris cluster is configured")
+	}
+	return p.switchCluster(cluster, "requested")
+}
+
+// switchCluster makes cluster the active cluster and rebuilds the SDK context on it,
+// raising a cluster_switchover alert.
+func (p *PlugPolaris) switchCluster(cluster, reason string) error {
+	previous := p.ActiveCluster()
+	if cluster == previous {
+		return nil
+	}
+	severity := AlertSeverityCritical
+	if cluster == ClusterPrimary {
+		severity = AlertSeverityWarning
+	}
+	p.raiseAlert(Alert{
+		Type:     AlertClusterSwitchover,
+		Severity: severity,
+		Subject:  cluster,
+		Message:  fmt.Sprintf("switching from the %s to the %s Polaris cluster: %s", previous, cluster, reason),
+	})
+	log.Warnf("Switching from the %s to the %s Polaris cluster: %s", previous, cluster, reason)
+
+	p.setActiveCluster(cluster)
+	if err := p.RecoverSDK(); err != nil {
+		if IsInitError(err) {
+			p.setActiveCluster(previous)
+			log.Errorf("Failed to switch to the %s Polaris cluster, staying on the %s cluster: %v",
+				cluster, previous, p.redactError(err))
+			return err
+		}
+		log.Errorf("Switched to the %s Polaris cluster with errors: %v", cluster, p.redactError(err))
+		return err
+	}
+	log.Infof("Switched to the %s Polaris cluster", cluster)
+	return nil
+}
+
+// setActiveCluster sets the cluster returned by ActiveCluster.
+func (p *PlugPolaris) setActiveCluster(cluster string) {
+	var standby int32
+	if cluster == ClusterStandby {
+		standby = 1
+	}
+	atomic.StoreInt32(&p.standbyActive, standby)
+}
@@ -0,0 +1,109 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newStandbyPlugin(t *testing.T) (*PlugPolaris, chan Alert) {
+	t.Helper()
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{
+		Namespace:       "default",
+		ServerBootstrap: &conf.ServerBootstrap{Addresses: []string{"primary.example.com:8091"}},
+		Standby: &conf.Standby{
+			ServerBootstrap: &conf.ServerBootstrap{Addresses: []string{"standby.example.com:8091"}},
+			FailoverAfter:   durationpb.New(time.Minute),
+			SwitchbackAfter: durationpb.New(5 * time.Minute),
+		},
+	})
+	alerter, alerts := alertChannel()
+	require.NoError(t, plugin.AddAlerter("test", alerter, ""))
+	plugin.SetAlertDedupWindow(time.Nanosecond)
+	return plugin, alerts
+}
+
+func TestEvaluateStandby_Failover(t *testing.T) {
+	plugin, alerts := newStandbyPlugin(t)
+	failure := errors.New("unavailable")
+	start := time.Now()
+
+	// Failures shorter than failover_after, or followed by a success, do not switch
+	assert.False(t, plugin.evaluateStandby(context.Background(), failure, start))
+	assert.False(t, plugin.evaluateStandby(context.Background(), nil, start.Add(50*time.Second)))
+	assert.False(t, plugin.evaluateStandby(context.Background(), failure, start.Add(time.Minute)))
+	assert.Equal(t, ClusterPrimary, plugin.ActiveCluster())
+
+	// The plugin is not initialized, so the switch fails after the alert and the plugin
+	// stays on the primary cluster
+	assert.True(t, plugin.evaluateStandby(context.Background(), failure, start.Add(2*time.Minute)))
+	alert := receiveAlert(t, alerts)
+	assert.Equal(t, AlertClusterSwitchover, alert.Type)
+	assert.Equal(t, AlertSeverityCritical, alert.Severity)
+	assert.Equal(t, ClusterStandby, alert.Subject)
+	assert.Contains(t, alert.Message, "health checks failing on the primary cluster")
+	assert.Equal(t, ClusterPrimary, plugin.ActiveCluster())
+}
+
+func TestEvaluateStandby_Switchback(t *testing.T) {
+	plugin, alerts := newStandbyPlugin(t)
+	plugin.setActiveCluster(ClusterStandby)
+	reachable := true
+	original := probeServer
+	probeServer = func(_ context.Context, address string) error {
+		if address == "primary.example.com:8091" && reachable {
+			return nil
+		}
+		return errors.New("connection refused")
+	}
+	t.Cleanup(func() { probeServer = original })
+	start := time.Now()
+
+	// The primary must stay reachable for switchback_after
+	assert.False(t, plugin.evaluateStandby(context.Background(), nil, start))
+	reachable = false
+	assert.False(t, plugin.evaluateStandby(context.Background(), nil, start.Add(3*time.Minute)))
+	reachable = true
+	assert.False(t, plugin.evaluateStandby(context.Background(), nil, start.Add(4*time.Minute)))
+	assert.False(t, plugin.evaluateStandby(context.Background(), nil, start.Add(8*time.Minute)))
+
+	assert.True(t, plugin.evaluateStandby(context.Background(), nil, start.Add(9*time.Minute)))
+	alert := receiveAlert(t, alerts)
+	assert.Equal(t, AlertSeverityWarning, alert.Severity)
+	assert.Equal(t, ClusterPrimary, alert.Subject)
+	assert.Equal(t, ClusterStandby, plugin.ActiveCluster(), "the failed switch keeps the standby cluster")
+}
+
+func TestActiveServerBootstrap(t *testing.T) {
+	plugin, _ := newStandbyPlugin(t)
+	plugin.setActiveCluster(ClusterStandby)
+	sdkConfig := api.NewConfiguration()
+	require.NoError(t, plugin.applyServerBootstrap(context.Background(), sdkConfig))
+	assert.Equal(t, []string{"standby.example.com:8091"}, sdkConfig.GetGlobal().GetServerConnector().GetAddresses())
+	assert.Equal(t, ClusterStandby, plugin.GetStats().Connection.Cluster)
+
+	assert.Error(t, plugin.SwitchCluster("dr"))
+	plugin.currentConf().Standby = nil
+	assert.Error(t, plugin.SwitchCluster(ClusterStandby))
+	assert.False(t, plugin.evaluateStandby(context.Background(), errors.New("unavailable"), time.Now().Add(time.Hour)))
+}
+
+func TestValidator_Standby(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, Standby: &conf.Standby{
+		FailoverAfter: durationpb.New(-time.Second),
+	}}
+	var fields []string
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.Contains(t, fields, "standby.server_bootstrap")
+	assert.Contains(t, fields, "standby.failover_after")
+}
@@ -0,0 +1,183 @@
+package polaris
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/api"
+)
+
+// PluginStats is a snapshot of the runtime state of the plugin
+type PluginStats struct {
+	Initialized bool          `json:"initialized"`
+	Destroyed   bool          `json:"destroyed"`
+	Namespace   string        `json:"namespace"`
+	StartTime   time.Time     `json:"start_time"`
+	Uptime      time.Duration `json:"uptime"`
+	Timestamp   time.Time     `json:"timestamp"`
+
+	Connection      ConnectionStats        `json:"connection"`
+	Watchers        []WatcherStats         `json:"watchers"`
+	ServiceCache    CacheStats             `json:"service_cache"`
+	ConfigCache     CacheStats             `json:"config_cache"`
+	CircuitBreakers []CircuitBreakerStatus `json:"circuit_breakers"`
+	Retries         RetryStats             `json:"retries"`
+	Health          HealthStats            `json:"health"`
+
+	// LastHeartbeat is the time of the last successful heartbeat, zero if none succeeded yet
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+}
+
+// ConnectionStats describes the connection of the SDK to the Polaris servers
+type ConnectionStats struct {
+	Connected bool `json:"connected"`
+	// Cluster is the active Polaris cluster, primary or standby
+	Cluster         string   `json:"cluster"`
+	Addresses       []string `json:"addresses"`
+	ConfigAddresses []string `json:"config_addresses"`
+	// Servers is the connection state of the servers, see ServerStatus
+	Servers []ServerStatus `json:"servers,omitempty"`
+}
+
+// WatcherStats describes a service or config watcher
+type WatcherStats struct {
+	// Type is "service" or "config"
+	Type string `json:"type"`
+	// Name is the service name, or file:group for config watchers
+	Name      string    `json:"name"`
+	Running   bool      `json:"running"`
+	LastEvent time.Time `json:"last_event"`
+}
+
+// CacheStats describes a cache of the plugin and its lookups since the plugin was created
+type CacheStats struct {
+	Size      int     `json:"size"`
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	Evictions int64   `json:"evictions"`
+	HitRatio  float64 `json:"hit_ratio"`
+}
+
+// RetryStats counts the operations of the plugin's retry manager
+type RetryStats struct {
+	Operations int64 `json:"operations"`
+	Retries    int64 `json:"retries"`
+	Failures   int64 `json:"failures"`
+}
+
+// runtimeCounters counts the plugin activity reported by GetStats
+type runtimeCounters struct {
+	retryOperations atomic.Int64
+	retries         atomic.Int64
+	retryFailures   atomic.Int64
+}
+
+// instrumentRetryManager counts the operations and retries of r
+func (c *runtimeCounters) instrumentRetryManager(r *RetryManager) {
+	r.OnRetry(func(int, error) { c.retries.Add(1) })
+	r.OnComplete(func(_ int, err error) {
+		c.retryOperations.Add(1)
+		if err != nil {
+			c.retryFailures.Add(1)
+		}
+	})
+}
+
+// newCacheStats returns the stats of a cache of size with hits and misses
+func newCacheStats(size int, hits, misses int64) CacheStats {
+	stats := CacheStats{Size: size, Hits: hits, Misses: misses}
+	if total := hits + misses; total > 0 {
+		stats.HitRatio = float64(hits) / float64(total)
+	}
+	return stats
+}
+
+// GetStats returns a snapshot of the runtime state of the plugin: its watchers, caches,
+// circuit breakers, retries, health state history, SDK connection and last heartbeat. It
+// can be called at any time, including before initialization and after destruction.
+func (p *PlugPolaris) GetStats() *PluginStats {
+	p.mu.RLock()
+	sdk := p.sdk
+	namespace := p.currentConf().GetNamespace()
+	startTime := p.startTime
+	addresses := p.serverAddresses
+	p.mu.RUnlock()
+
+	now := time.Now()
+	stats := &PluginStats{
+		Initialized: p.IsInitialized(),
+		Destroyed:   p.IsDestroyed(),
+		Namespace:   namespace,
+		Timestamp:   now,
+		Connection:  connectionStats(sdk, addresses, p.ActiveCluster(), p.ServerStatus()),
+		Watchers:    p.watcherStats(),
+		Health:      p.healthStats(),
+		Retries: RetryStats{
+			Operations: p.counters.retryOperations.Load(),
+			Retries:    p.counters.retries.Load(),
+			Failures:   p.counters.retryFailures.Load(),
+		},
+	}
+	if stats.Initialized && !startTime.IsZero() {
+		stats.StartTime = startTime
+		stats.Uptime = now.Sub(startTime)
+	}
+
+	stats.ServiceCache = p.serviceCache.stats()
+	stats.ConfigCache = p.configCache.stats()
+
+	if p.circuitBreakers != nil {
+		stats.CircuitBreakers = p.circuitBreakers.Statuses()
+	}
+
+	p.heartbeatMutex.Lock()
+	stats.LastHeartbeat = p.lastHeartbeat
+	p.heartbeatMutex.Unlock()
+	return stats
+}
+
+// connectionStats returns the server addresses the SDK connects to, falling back to the
+// addresses resolved from the server bootstrap when the SDK is not created
+func connectionStats(sdk api.SDKContext, addresses serverAddresses, cluster string, servers []ServerStatus) ConnectionStats {
+	if sdk == nil {
+		return ConnectionStats{
+			Cluster:         cluster,
+			Addresses:       append([]string(nil), addresses.naming...),
+			ConfigAddresses: append([]string(nil), addresses.config...),
+			Servers:         servers,
+		}
+	}
+	sdkConfig := sdk.GetConfig()
+	return ConnectionStats{
+		Connected:       true,
+		Cluster:         cluster,
+		Addresses:       append([]string(nil), sdkConfig.GetGlobal().GetServerConnector().GetAddresses()...),
+		ConfigAddresses: append([]string(nil), sdkConfig.GetConfigFile().GetConfigConnectorConfig().GetAddresses()...),
+		Servers:         servers,
+	}
+}
+
+// watcherStats returns the stats of the active watchers, ordered by type and name
+func (p *PlugPolaris) watcherStats() []WatcherStats {
+	p.watcherMutex.RLock()
+	stats := make([]WatcherStats, 0, len(p.activeWatchers)+len(p.configWatchers))
+	for name, watcher := range p.activeWatchers {
+		stats = append(stats, WatcherStats{
+			Type: watcherTypeService, Name: name, Running: watcher.IsRunning(), LastEvent: watcher.LastEventTime(),
+		})
+	}
+	for name, watcher := range p.configWatchers {
+		stats = append(stats, WatcherStats{
+			Type: watcherTypeConfig, Name: name, Running: watcher.IsRunning(), LastEvent: watcher.LastEventTime(),
+		})
+	}
+	p.watcherMutex.RUnlock()
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Type != stats[j].Type {
+			return stats[i].Type < stats[j].Type
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
@@ -0,0 +1,62 @@
+package polaris
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStats_NotInitialized(t *testing.T) {
+	stats := NewPolarisControlPlane().GetStats()
+	assert.False(t, stats.Initialized)
+	assert.Zero(t, stats.Uptime)
+	assert.False(t, stats.Connection.Connected)
+	assert.Empty(t, stats.Watchers)
+	assert.Zero(t, stats.ServiceCache.HitRatio)
+}
+
+func TestGetStats(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	plugin.startTime = time.Now().Add(-time.Minute)
+	plugin.setInitialized()
+	plugin.serverAddresses = serverAddresses{naming: []string{"10.0.0.1:8091"}}
+
+	instance := NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})
+	plugin.updateServiceInstanceCache("orders", []model.Instance{instance})
+	plugin.cachedServiceInstances("orders")
+	plugin.cachedServiceInstances("orders")
+	plugin.cachedServiceInstances("payments")
+	plugin.cachedConfigContent("app.yaml", "orders")
+
+	plugin.activeWatchers["orders"] = NewServiceWatcher(nil, "orders", "default")
+	plugin.configWatchers["app.yaml:orders"] = NewConfigWatcher(nil, "app.yaml", "orders", "default")
+	plugin.circuitBreakers.Register(PluginCircuitBreakerKey, NewCircuitBreaker(0.5, time.Second))
+
+	retry := NewRetryManager(2, time.Millisecond)
+	plugin.counters.instrumentRetryManager(retry)
+	_ = retry.DoWithRetry(func() error { return errors.New("unavailable") })
+
+	stats := plugin.GetStats()
+	assert.True(t, stats.Initialized)
+	assert.Equal(t, "default", stats.Namespace)
+	assert.GreaterOrEqual(t, stats.Uptime, time.Minute)
+	assert.Equal(t, []string{"10.0.0.1:8091"}, stats.Connection.Addresses)
+
+	require.Len(t, stats.Watchers, 2)
+	assert.Equal(t, WatcherStats{Type: "config", Name: "app.yaml:orders"}, stats.Watchers[0])
+	assert.Equal(t, "orders", stats.Watchers[1].Name)
+
+	assert.Equal(t, CacheStats{Size: 1, Hits: 2, Misses: 1, HitRatio: 2.0 / 3}, stats.ServiceCache)
+	assert.Equal(t, CacheStats{Misses: 1}, stats.ConfigCache)
+
+	require.Len(t, stats.CircuitBreakers, 1)
+	assert.Equal(t, "closed", stats.CircuitBreakers[0].State)
+	assert.Equal(t, RetryStats{Operations: 1, Retries: 2, Failures: 1}, stats.Retries)
+	assert.True(t, stats.LastHeartbeat.IsZero())
+}
@@ -0,0 +1,70 @@
+package polaris
+
+import (
+	"fmt"
+
+	"github.com/go-lynx/lynx"
+	"github.com/go-lynx/lynx-polaris/conf"
+)
+
+// Subsystem is a part of the plugin that subsystems enables independently.
+type Subsystem string
+
+// Subsystems of the plugin
+const (
+	SubsystemRegistration Subsystem = "registration"
+	SubsystemDiscovery    Subsystem = "discovery"
+	SubsystemConfig       Subsystem = "config"
+	SubsystemRateLimit    Subsystem = "rate_limit"
+	SubsystemRouting      Subsystem = "routing"
+)
+
+// subsystemEnabled reports whether cfg enables subsystem: every subsystem when cfg is nil,
+// otherwise those set to true.
+func subsystemEnabled(cfg *conf.Subsystems, subsystem Subsystem) bool {
+	if cfg == nil {
+		return true
+	}
+	switch subsystem {
+	case SubsystemRegistration:
+		return cfg.GetRegistration()
+	case SubsystemDiscovery:
+		return cfg.GetDiscovery()
+	case SubsystemConfig:
+		return cfg.GetConfig()
+	case SubsystemRateLimit:
+		return cfg.GetRateLimit()
+	case SubsystemRouting:
+		return cfg.GetRouting()
+	}
+	return false
+}
+
+// SubsystemEnabled reports whether subsystem is enabled by the subsystems config. All
+// subsystems are enabled when it is not set.
+func (p *PlugPolaris) SubsystemEnabled(subsystem Subsystem) bool {
+	p.mu.RLock()
+	cfg := p.currentConf().GetSubsystems()
+	p.mu.RUnlock()
+	return subsystemEnabled(cfg, subsystem)
+}
+
+// checkSubsystem returns a SUBSYSTEM_DISABLED error when subsystem is disabled.
+func (p *PlugPolaris) checkSubsystem(subsystem Subsystem) error {
+	if p.SubsystemEnabled(subsystem) {
+		return nil
+	}
+	return NewServiceError(ErrCodeSubsystemDisabled, fmt.Sprintf("the %s subsystem is disabled", subsystem))
+}
+
+// subsystemCapabilities maps the subsystems to the control plane capabilities they provide.
+var subsystemCapabilities = []struct {
+	subsystem    Subsystem
+	capabilities []lynx.ControlPlaneCapability
+}{
+	{SubsystemConfig, []lynx.ControlPlaneCapability{lynx.ControlPlaneCapabilityConfig, lynx.ControlPlaneCapabilityWatcher}},
+	{SubsystemRegistration, []lynx.ControlPlaneCapability{lynx.ControlPlaneCapabilityRegistry}},
+	{SubsystemDiscovery, []lynx.ControlPlaneCapability{lynx.ControlPlaneCapabilityDiscovery}},
+	{SubsystemRouting, []lynx.ControlPlaneCapability{lynx.ControlPlaneCapabilityRouter}},
+	{SubsystemRateLimit, []lynx.ControlPlaneCapability{lynx.ControlPlaneCapabilityRateLimit}},
+}
@@ -0,0 +1,92 @@
+package polaris
+
+import (
+	"testing"
+
+	"github.com/go-lynx/lynx"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubsystemEnabled(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	for _, sc := range subsystemCapabilities {
+		assert.True(t, plugin.SubsystemEnabled(sc.subsystem), "all subsystems are enabled without subsystems")
+	}
+	assert.Len(t, plugin.ControlPlaneCapabilities(), 6)
+
+	plugin.currentConf().Subsystems = &conf.Subsystems{Config: true, Discovery: true}
+	assert.True(t, plugin.SubsystemEnabled(SubsystemConfig))
+	assert.True(t, plugin.SubsystemEnabled(SubsystemDiscovery))
+	assert.False(t, plugin.SubsystemEnabled(SubsystemRegistration))
+	assert.False(t, plugin.SubsystemEnabled(SubsystemRateLimit))
+	assert.False(t, plugin.SubsystemEnabled(SubsystemRouting))
+	assert.Equal(t, []lynx.ControlPlaneCapability{
+		lynx.ControlPlaneCapabilityConfig, lynx.ControlPlaneCapabilityWatcher, lynx.ControlPlaneCapabilityDiscovery,
+	}, plugin.ControlPlaneCapabilities())
+
+	assert.NoError(t, plugin.checkSubsystem(SubsystemConfig))
+	assert.True(t, IsSubsystemDisabled(plugin.checkSubsystem(SubsystemRateLimit)))
+}
+
+func TestSubsystems_Disabled(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", Subsystems: &conf.Subsystems{Registration: true}})
+	plugin.setInitialized()
+
+	assert.Nil(t, plugin.NewServiceDiscovery())
+	assert.Nil(t, plugin.NewNodeRouter("orders"))
+	assert.Nil(t, plugin.HTTPRateLimit())
+	assert.Nil(t, plugin.GRPCRateLimit())
+
+	_, err := plugin.GetServiceInstances("orders")
+	assert.True(t, IsSubsystemDisabled(err))
+	_, err = plugin.WatchService("orders")
+	assert.True(t, IsSubsystemDisabled(err))
+	_, err = plugin.GetConfig("orders.yaml", "orders")
+	assert.True(t, IsSubsystemDisabled(err))
+	_, err = plugin.AcquireQuota("orders", nil)
+	assert.True(t, IsSubsystemDisabled(err))
+
+	sources, err := plugin.GetConfigSources()
+	require.NoError(t, err)
+	assert.Empty(t, sources)
+	targets, err := plugin.GetConfigWatchTargets("orders")
+	require.NoError(t, err)
+	assert.Empty(t, targets)
+
+	gate := &quotaGate{plugin: plugin, service: "orders", getQuota: func(string, string, map[string]string) (*model.QuotaResponse, error) {
+		t.Fatal("quota requested while the rate limit subsystem is disabled")
+		return nil, nil
+	}}
+	result, _, err := gate.acquire("/orders.Get", nil)
+	require.NoError(t, err)
+	assert.Equal(t, model.QuotaResultOk, result.Code)
+}
+
+func TestValidator_Subsystems(t *testing.T) {
+	cfg := &conf.Polaris{
+		Namespace:       "default",
+		Weight:          100,
+		Subsystems:      &conf.Subsystems{Discovery: true},
+		RemoteConfig:    &conf.RemoteConfig{Filename: "polaris.yaml"},
+		RequiredConfigs: &conf.RequiredConfigs{Files: []*conf.ConfigFile{{Filename: "orders.yaml"}}},
+	}
+	var fields []string
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.Contains(t, fields, "remote_config")
+	assert.Contains(t, fields, "required_configs")
+
+	cfg.Subsystems.Config = true
+	fields = nil
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.NotContains(t, fields, "remote_config")
+	assert.NotContains(t, fields, "required_configs")
+}
@@ -0,0 +1,56 @@
+package polaris
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+)
+
+// newTLSConfig builds the client TLS config of the Polaris server connections from cfg, or
+// returns nil when TLS is not enabled.
+func newTLSConfig(cfg *conf.Tls) (*tls.Config, error) {
+	if !cfg.GetEnabled() {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.GetServerName(),
+		InsecureSkipVerify: cfg.GetInsecureSkipVerify(), //nolint:gosec // opt-in, for testing only
+	}
+	if cfg.GetCaFile() != "" {
+		pem, err := os.ReadFile(cfg.GetCaFile())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls.ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls.ca_file %s has no PEM certificate", cfg.GetCaFile())
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.GetCertFile() != "" || cfg.GetKeyFile() != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.GetCertFile(), cfg.GetKeyFile())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// tlsHTTPTransport returns the transport of the plugin HTTP calls to the Polaris servers,
+// such as the config admin OpenAPI, or nil for the default transport when TLS is not
+// enabled.
+func tlsHTTPTransport(cfg *conf.Tls) (http.RoundTripper, error) {
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil || tlsConfig == nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
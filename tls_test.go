@@ -0,0 +1,113 @@
+package polaris
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// writeTestCert writes a self-signed certificate for polaris.local and its key to dir
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "polaris.local"},
+		DNSNames:              []string{"polaris.local"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	certFile, keyFile := writeTestCert(t, t.TempDir())
+
+	tlsConfig, err := newTLSConfig(&conf.Tls{CaFile: certFile})
+	require.NoError(t, err)
+	assert.Nil(t, tlsConfig, "TLS is off unless enabled")
+
+	tlsConfig, err = newTLSConfig(&conf.Tls{Enabled: true, CaFile: certFile, CertFile: certFile, KeyFile: keyFile, ServerName: "polaris.local"})
+	require.NoError(t, err)
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.Len(t, tlsConfig.Certificates, 1)
+	assert.Equal(t, "polaris.local", tlsConfig.ServerName)
+
+	_, err = newTLSConfig(&conf.Tls{Enabled: true, CaFile: keyFile})
+	assert.Error(t, err, "a key is not a CA bundle")
+	_, err = newTLSConfig(&conf.Tls{Enabled: true, CertFile: certFile})
+	assert.Error(t, err, "a client certificate needs its key")
+}
+
+func TestTLSConnCreator_MutualTLS(t *testing.T) {
+	certFile, keyFile := writeTestCert(t, t.TempDir())
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pemData, err := os.ReadFile(certFile)
+	require.NoError(t, err)
+	require.True(t, pool.AppendCertsFromPEM(pemData))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	tlsConfig, err := newTLSConfig(&conf.Tls{Enabled: true, CaFile: certFile, CertFile: certFile, KeyFile: keyFile, ServerName: "polaris.local"})
+	require.NoError(t, err)
+	creator := &serverConnCreator{tlsConfig: tlsConfig, clientInfo: &network.ClientInfo{}}
+	conn, err := creator.CreateConnection(listener.Addr().String(), 5*time.Second, nil)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	assert.Equal(t, "127.0.0.1", creator.clientInfo.GetIPString())
+}
+
+func TestValidator_TLS(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, Tls: &conf.Tls{Enabled: true, CertFile: "/missing/cert.pem"}}
+	var fields []string
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.Contains(t, fields, "tls.cert_file")
+
+	certFile, keyFile := writeTestCert(t, t.TempDir())
+	cfg.Tls = &conf.Tls{Enabled: true, CaFile: certFile, CertFile: certFile, KeyFile: keyFile}
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		assert.NotContains(t, e.Field, "tls")
+	}
+}
@@ -0,0 +1,313 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// TokenOperation is a write operation that can use its own token, e.g. when Polaris RBAC
+// grants writes to a different principal than reads
+type TokenOperation string
+
+const (
+	// TokenOperationWrite is the token of every write operation without its own token
+	TokenOperationWrite TokenOperation = "write"
+	// TokenOperationConfigWrite covers config updates, releases, publishes and deletes
+	TokenOperationConfigWrite TokenOperation = "config_write"
+	// TokenOperationIsolation covers Isolate and Unisolate
+	TokenOperationIsolation TokenOperation = "isolation"
+	// TokenOperationWeight covers instance weight changes
+	TokenOperationWeight TokenOperation = "weight"
+)
+
+// tokenOperations are the operations accepted in operation_tokens
+var tokenOperations = []TokenOperation{TokenOperationWrite, TokenOperationConfigWrite, TokenOperationIsolation, TokenOperationWeight}
+
+// TokenProvider returns the current Polaris token, e.g. read from Vault or a Kubernetes secret
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenProviderFunc adapts a function to a TokenProvider
+type TokenProviderFunc func(ctx context.Context) (string, error)
+
+// Token calls f(ctx)
+func (f TokenProviderFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// NewFileTokenProvider returns a provider reading the token from path on every call, with
+// surrounding whitespace trimmed, so rotated Kubernetes secrets and Vault agent sinks are
+// picked up
+func NewFileTokenProvider(path string) TokenProvider {
+	return TokenProviderFunc(func(context.Context) (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	})
+}
+
+// NewEnvTokenProvider returns a provider reading the token from the environment variable name
+func NewEnvTokenProvider(name string) TokenProvider {
+	return TokenProviderFunc(func(context.Context) (string, error) {
+		return strings.TrimSpace(os.Getenv(name)), nil
+	})
+}
+
+// tokenProviderFromConfig returns the provider of token_source, or nil when the token is
+// only set inline.
+func tokenProviderFromConfig(cfg *conf.TokenSource) TokenProvider {
+	switch {
+	case cfg.GetFile() != "":
+		return NewFileTokenProvider(cfg.GetFile())
+	case cfg.GetEnv() != "":
+		return NewEnvTokenProvider(cfg.GetEnv())
+	}
+	return nil
+}
+
+// operationTokenProvider returns the provider of an operation_tokens entry, or nil when it
+// sets no token.
+func operationTokenProvider(cfg *conf.OperationToken) TokenProvider {
+	switch {
+	case cfg.GetToken() != "":
+		token := cfg.GetToken()
+		return TokenProviderFunc(func(context.Context) (string, error) { return token, nil })
+	case cfg.GetFile() != "":
+		return NewFileTokenProvider(cfg.GetFile())
+	case cfg.GetEnv() != "":
+		return NewEnvTokenProvider(cfg.GetEnv())
+	}
+	return nil
+}
+
+type tokenContextKey struct{}
+
+// WithToken returns a context making the registrations and deregistrations of the plugin's
+// registrar called with it send token instead of the plugin token. An empty token leaves ctx
+// unchanged.
+func WithToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tokenContextKey{}, token)
+}
+
+// tokenFromContext returns the token set on ctx by WithToken.
+func tokenFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	token, ok := ctx.Value(tokenContextKey{}).(string)
+	return token, ok
+}
+
+// tokenRefreshInterval returns token_source.refresh_interval with the default and minimum
+// applied.
+func tokenRefreshInterval(cfg *conf.TokenSource) time.Duration {
+	interval := conf.DefaultTokenRefreshInterval
+	if cfg.GetRefreshInterval() != nil && cfg.GetRefreshInterval().AsDuration() > 0 {
+		interval = cfg.GetRefreshInterval().AsDuration()
+	}
+	return max(interval, conf.MinTokenRefreshInterval)
+}
+
+// currentToken returns the token from the token provider, falling back to the token set in
+// config. Registrations, heartbeats and config admin requests read it on every call, so a
+// rotated token takes effect without recreating the plugin.
+func (p *PlugPolaris) currentToken() string {
+	p.tokenMutex.RLock()
+	token := p.token
+	p.tokenMutex.RUnlock()
+	if token != "" {
+		return token
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.currentConf().GetToken()
+}
+
+// operationToken returns the token of operation, falling back to the write token and then
+// to the plugin token. An empty operation is a read and uses the plugin token.
+func (p *PlugPolaris) operationToken(operation TokenOperation) string {
+	if operation != "" {
+		p.tokenMutex.RLock()
+		token := p.operationTokens[operation]
+		if token == "" {
+			token = p.operationTokens[TokenOperationWrite]
+		}
+		p.tokenMutex.RUnlock()
+		if token != "" {
+			return token
+		}
+	}
+	return p.currentToken()
+}
+
+// SetOperationTokenProvider loads the token of operation from provider now and then at every
+// token_source.refresh_interval, replacing its token from operation_tokens. A nil provider
+// goes back to the write token, or the plugin token.
+func (p *PlugPolaris) SetOperationTokenProvider(operation TokenOperation, provider TokenProvider) error {
+	if !slices.Contains(tokenOperations, operation) {
+		return NewConfigError(fmt.Sprintf("unknown token operation %q", operation))
+	}
+	p.tokenMutex.Lock()
+	if provider == nil {
+		delete(p.operationTokenProviders, operation)
+		delete(p.operationTokens, operation)
+		p.tokenMutex.Unlock()
+		return nil
+	}
+	if p.operationTokenProviders == nil {
+		p.operationTokenProviders = make(map[TokenOperation]TokenProvider)
+		p.operationTokens = make(map[TokenOperation]string)
+	}
+	p.operationTokenProviders[operation] = provider
+	p.tokenMutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), conf.DefaultTimeoutSeconds*time.Second)
+	defer cancel()
+	return p.refreshOperationToken(ctx, operation, provider)
+}
+
+// refreshOperationTokens loads the operation tokens from their providers. Failing operations
+// keep their previous token.
+func (p *PlugPolaris) refreshOperationTokens(ctx context.Context) error {
+	p.tokenMutex.RLock()
+	providers := maps.Clone(p.operationTokenProviders)
+	p.tokenMutex.RUnlock()
+	var errs []error
+	for operation, provider := range providers {
+		errs = append(errs, p.refreshOperationToken(ctx, operation, provider))
+	}
+	return errors.Join(errs...)
+}
+
+// refreshOperationToken loads the token of operation from provider.
+func (p *PlugPolaris) refreshOperationToken(ctx context.Context, operation TokenOperation, provider TokenProvider) error {
+	token, err := provider.Token(ctx)
+	if err != nil {
+		return WrapConfigError(err, fmt.Sprintf("failed to load the %s token", operation))
+	}
+	if token == "" {
+		return NewConfigError(fmt.Sprintf("token provider of %s returned an empty token", operation))
+	}
+	p.tokenMutex.Lock()
+	defer p.tokenMutex.Unlock()
+	// Skip providers replaced or removed while loading
+	if p.operationTokenProviders[operation] == nil {
+		return nil
+	}
+	rotated := p.operationTokens[operation] != "" && p.operationTokens[operation] != token
+	p.operationTokens[operation] = token
+	if rotated {
+		log.Infof("Polaris %s token rotated", operation)
+	}
+	return nil
+}
+
+// SetTokenProvider loads the token from provider now and then at every
+// token_source.refresh_interval, replacing the token set in config or token_source. A nil
+// provider goes back to the configured token.
+func (p *PlugPolaris) SetTokenProvider(provider TokenProvider) error {
+	p.tokenMutex.Lock()
+	p.tokenProvider = provider
+	if provider == nil {
+		p.token = ""
+	}
+	p.tokenMutex.Unlock()
+	if provider == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), conf.DefaultTimeoutSeconds*time.Second)
+	defer cancel()
+	return p.RefreshToken(ctx)
+}
+
+// RefreshToken loads the token from the token provider now. The previous token is kept when
+// the provider fails or returns an empty token.
+func (p *PlugPolaris) RefreshToken(ctx context.Context) error {
+	p.tokenMutex.RLock()
+	provider := p.tokenProvider
+	p.tokenMutex.RUnlock()
+	if provider == nil {
+		return NewConfigError("no token provider is set")
+	}
+	token, err := provider.Token(ctx)
+	if err != nil {
+		return WrapConfigError(err, "failed to load Polaris token")
+	}
+	if token == "" {
+		return NewConfigError("token provider returned an empty token")
+	}
+
+	p.tokenMutex.Lock()
+	rotated := p.token != "" && p.token != token
+	p.token = token
+	p.tokenMutex.Unlock()
+	if rotated {
+		log.Infof("Polaris token rotated")
+	}
+	return nil
+}
+
+// initTokenSource sets the token providers of token_source and operation_tokens, loading
+// the tokens before the SDK context is created.
+func (p *PlugPolaris) initTokenSource() error {
+	if provider := tokenProviderFromConfig(p.currentConf().GetTokenSource()); provider != nil {
+		if err := p.SetTokenProvider(provider); err != nil {
+			return err
+		}
+	}
+	for operation, cfg := range p.currentConf().GetOperationTokens() {
+		if provider := operationTokenProvider(cfg); provider != nil {
+			if err := p.SetOperationTokenProvider(TokenOperation(operation), provider); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// startTokenRefresh reloads the token and the operation tokens at every
+// token_source.refresh_interval while token providers are set. Failures keep the previous
+// tokens.
+func (p *PlugPolaris) startTokenRefresh() {
+	p.mu.RLock()
+	interval := tokenRefreshInterval(p.currentConf().GetTokenSource())
+	p.mu.RUnlock()
+	ctx := p.watcherContext()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.tokenMutex.RLock()
+				provider := p.tokenProvider
+				p.tokenMutex.RUnlock()
+				if provider != nil {
+					if err := p.RefreshToken(ctx); err != nil {
+						log.Warnf("Failed to refresh Polaris token, keeping the previous token: %v", err)
+					}
+				}
+				if err := p.refreshOperationTokens(ctx); err != nil {
+					log.Warnf("Failed to refresh Polaris operation tokens, keeping the previous tokens: %v", err)
+				}
+			}
+		}
+	}()
+}
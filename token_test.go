@@ -0,0 +1,133 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestFileTokenProvider_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("first-token1\n"), 0o600))
+
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default", Token: "config-token1", TokenSource: &conf.TokenSource{File: path}})
+	assert.Equal(t, "config-token1", plugin.currentToken())
+	require.NoError(t, plugin.initTokenSource())
+	assert.Equal(t, "first-token1", plugin.currentToken())
+
+	require.NoError(t, os.WriteFile(path, []byte("second-token2"), 0o600))
+	require.NoError(t, plugin.RefreshToken(context.Background()))
+	assert.Equal(t, "second-token2", plugin.currentToken())
+
+	// A failing or empty load keeps the previous token
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	assert.Error(t, plugin.RefreshToken(context.Background()))
+	require.NoError(t, os.Remove(path))
+	assert.Error(t, plugin.RefreshToken(context.Background()))
+	assert.Equal(t, "second-token2", plugin.currentToken())
+
+	require.NoError(t, plugin.SetTokenProvider(nil))
+	assert.Equal(t, "config-token1", plugin.currentToken())
+}
+
+func TestSetTokenProvider(t *testing.T) {
+	t.Setenv("POLARIS_TEST_TOKEN", "env-token1")
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	require.NoError(t, plugin.SetTokenProvider(NewEnvTokenProvider("POLARIS_TEST_TOKEN")))
+	assert.Equal(t, "env-token1", plugin.currentToken())
+
+	err := plugin.SetTokenProvider(TokenProviderFunc(func(context.Context) (string, error) {
+		return "", errors.New("vault sealed")
+	}))
+	assert.ErrorContains(t, err, "vault sealed")
+}
+
+func TestRegistrar_ServiceToken(t *testing.T) {
+	provider := &fakeClients{}
+	registrar := NewPolarisRegistrar(provider, "default")
+	token := "first-token1"
+	registrar.serviceToken = func() string { return token }
+
+	service := &registry.ServiceInstance{Name: "svc", Endpoints: []string{"http://10.0.0.1:8080"}}
+	require.NoError(t, registrar.Register(context.Background(), service))
+	token = "second-token2"
+	require.NoError(t, registrar.Deregister(context.Background(), service))
+
+	require.Len(t, provider.registered, 1)
+	require.Len(t, provider.deregistered, 1)
+	assert.Equal(t, "first-token1", provider.registered[0].ServiceToken)
+	assert.Equal(t, "second-token2", provider.deregistered[0].ServiceToken)
+}
+
+func TestValidator_TokenSource(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, TokenSource: &conf.TokenSource{
+		File: "/var/run/secrets/polaris/token", Env: "POLARIS_TOKEN", RefreshInterval: durationpb.New(-1),
+	}}
+	var fields []string
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.Contains(t, fields, "token_source")
+	assert.Contains(t, fields, "token_source.refresh_interval")
+	assert.Equal(t, conf.MinTokenRefreshInterval, tokenRefreshInterval(&conf.TokenSource{RefreshInterval: durationpb.New(1)}))
+}
+
+func TestOperationTokens(t *testing.T) {
+	plugin, fake := newConfigAdminPlugin(t, "read-token1")
+	plugin.currentConf().OperationTokens = map[string]*conf.OperationToken{
+		string(TokenOperationConfigWrite): {Token: "secret-token"},
+		string(TokenOperationWrite):       {Token: "write-token1"},
+	}
+	require.NoError(t, plugin.initTokenSource())
+	assert.Equal(t, "read-token1", plugin.operationToken(""))
+	assert.Equal(t, "secret-token", plugin.operationToken(TokenOperationConfigWrite))
+	assert.Equal(t, "write-token1", plugin.operationToken(TokenOperationIsolation))
+
+	// Config writes use their token, reads the plugin token
+	require.NoError(t, plugin.PublishConfig(context.Background(), "app.yaml", "orders", "workers: 4"))
+	assert.Equal(t, "workers: 4", fake.released["orders/app.yaml"])
+	_, err := plugin.ListConfigFiles("orders")
+	assert.Error(t, err, "the read token is not accepted by the fake server")
+
+	// Isolation re-registers with the write token, registrations keep the plugin token
+	provider := &fakeClients{}
+	plugin.registrar = NewPolarisRegistrar(provider, "default")
+	plugin.registrar.serviceToken = plugin.currentToken
+	require.NoError(t, plugin.registrar.Register(context.Background(), &registry.ServiceInstance{
+		Name: "svc", Endpoints: []string{"http://10.0.0.1:8080"},
+	}))
+	require.NoError(t, plugin.Isolate())
+	require.Len(t, provider.registered, 2)
+	assert.Equal(t, "read-token1", provider.registered[0].ServiceToken)
+	assert.Equal(t, "write-token1", provider.registered[1].ServiceToken)
+
+	require.NoError(t, plugin.SetOperationTokenProvider(TokenOperationWrite, nil))
+	assert.Equal(t, "read-token1", plugin.operationToken(TokenOperationIsolation))
+	assert.Error(t, plugin.SetOperationTokenProvider("delete_namespace", NewEnvTokenProvider("X")))
+}
+
+func TestValidator_OperationTokens(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Weight: 100, OperationTokens: map[string]*conf.OperationToken{
+		"config_write": {Token: "short"},
+		"isolation":    {File: "/var/run/secrets/isolation", Env: "ISOLATION_TOKEN"},
+		"admin":        {Env: "ADMIN_TOKEN"},
+	}}
+	var fields []string
+	for _, e := range NewValidator(cfg).Validate().Errors {
+		fields = append(fields, e.Field)
+		assert.NotEqual(t, "short", e.Value)
+	}
+	assert.Contains(t, fields, "operation_tokens.config_write.token")
+	assert.Contains(t, fields, "operation_tokens.isolation")
+	assert.Contains(t, fields, "operation_tokens.admin")
+}
@@ -1,50 +1,162 @@
 package polaris
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/go-lynx/lynx-polaris/conf"
+	"google.golang.org/protobuf/proto"
 )
 
-// ValidationError configuration validation error
+// ValidationSeverity severity of a validation finding
+type ValidationSeverity string
+
+const (
+	// SeverityError is an invalid setting: the configuration is rejected
+	SeverityError ValidationSeverity = "error"
+	// SeverityWarning is a valid but risky setting: it is logged and the configuration is used
+	SeverityWarning ValidationSeverity = "warning"
+)
+
+// ValidationCode is the stable code of a validation finding. Together with the field, it
+// identifies the finding regardless of the wording of its message.
+type ValidationCode string
+
+// Validation error codes
+const (
+	// ValidationCodeInvalid is the code of errors added without a more specific one
+	ValidationCodeInvalid          ValidationCode = "INVALID"
+	ValidationCodeRequired         ValidationCode = "REQUIRED"
+	ValidationCodeOutOfRange       ValidationCode = "OUT_OF_RANGE"
+	ValidationCodeUnsupportedValue ValidationCode = "UNSUPPORTED_VALUE"
+	ValidationCodeInvalidFormat    ValidationCode = "INVALID_FORMAT"
+	ValidationCodeConflict         ValidationCode = "CONFLICT"
+	ValidationCodeDependency       ValidationCode = "DEPENDENCY"
+	ValidationCodeUnreadableFile   ValidationCode = "UNREADABLE_FILE"
+	ValidationCodeWeakToken        ValidationCode = "WEAK_TOKEN"
+	ValidationCodeSensitiveWord    ValidationCode = "SENSITIVE_WORD"
+)
+
+// Validation warning codes
+const (
+	// ValidationCodeRisky is the code of warnings added without a more specific one
+	ValidationCodeRisky                 ValidationCode = "RISKY"
+	ValidationCodeAggressiveTTL         ValidationCode = "AGGRESSIVE_TTL"
+	ValidationCodeCheckDisabled         ValidationCode = "CHECK_DISABLED"
+	ValidationCodeCheckSkipped          ValidationCode = "CHECK_SKIPPED"
+	ValidationCodeDryRunEnabled         ValidationCode = "DRY_RUN_ENABLED"
+	ValidationCodeFaultInjectionEnabled ValidationCode = "FAULT_INJECTION_ENABLED"
+)
+
+// ValidationError configuration validation error or warning
 type ValidationError struct {
-	Field   string
-	Message string
-	Value   any
+	Field    string
+	Code     ValidationCode
+	Message  string
+	Value    any
+	Severity ValidationSeverity
 }
 
 func (e *ValidationError) Error() string {
+	if e.Severity == SeverityWarning {
+		return fmt.Sprintf("validation warning for field '%s': %s (value: %v)", e.Field, e.Message, e.Value)
+	}
 	return fmt.Sprintf("validation error for field '%s': %s (value: %v)", e.Field, e.Message, e.Value)
 }
 
-// ValidationResult validation result
+// MarshalJSON encodes the finding with its value formatted as a string, omitted when nil
+func (e *ValidationError) MarshalJSON() ([]byte, error) {
+	finding := struct {
+		Field    string             `json:"field"`
+		Code     ValidationCode     `json:"code"`
+		Severity ValidationSeverity `json:"severity"`
+		Message  string             `json:"message"`
+		Value    *string            `json:"value,omitempty"`
+	}{Field: e.Field, Code: e.Code, Severity: e.Severity, Message: e.Message}
+	if e.Value != nil {
+		value := fmt.Sprint(e.Value)
+		finding.Value = &value
+	}
+	return json.Marshal(finding)
+}
+
+// ValidationResult validation result. Only errors make it invalid; warnings are reported
+// along with a valid configuration.
 type ValidationResult struct {
-	IsValid bool
-	Errors  []*ValidationError
+	IsValid  bool
+	Errors   []*ValidationError
+	Warnings []*ValidationError
 }
 
 // NewValidationResult creates validation result
 func NewValidationResult() *ValidationResult {
 	return &ValidationResult{
-		IsValid: true,
-		Errors:  make([]*ValidationError, 0),
+		IsValid:  true,
+		Errors:   make([]*ValidationError, 0),
+		Warnings: make([]*ValidationError, 0),
 	}
 }
 
-// AddError adds error
+// AddError adds an error with the ValidationCodeInvalid code
 func (r *ValidationResult) AddError(field, message string, value any) {
+	r.AddCodedError(ValidationCodeInvalid, field, message, value)
+}
+
+// AddCodedError adds an error with code
+func (r *ValidationResult) AddCodedError(code ValidationCode, field, message string, value any) {
 	r.IsValid = false
 	r.Errors = append(r.Errors, &ValidationError{
-		Field:   field,
-		Message: message,
-		Value:   value,
+		Field:    field,
+		Code:     code,
+		Message:  message,
+		Value:    value,
+		Severity: SeverityError,
 	})
 }
 
+// AddWarning adds a warning with the ValidationCodeRisky code, which does not make the
+// result invalid
+func (r *ValidationResult) AddWarning(field, message string, value any) {
+	r.AddCodedWarning(ValidationCodeRisky, field, message, value)
+}
+
+// AddCodedWarning adds a warning with code, which does not make the result invalid
+func (r *ValidationResult) AddCodedWarning(code ValidationCode, field, message string, value any) {
+	r.Warnings = append(r.Warnings, &ValidationError{
+		Field:    field,
+		Code:     code,
+		Message:  message,
+		Value:    value,
+		Severity: SeverityWarning,
+	})
+}
+
+// MarshalJSON encodes the result for CI pipelines and admission controllers, e.g.
+// {"valid":false,"errors":[{"field":"ttl","code":"OUT_OF_RANGE",...}],"warnings":[]}
+func (r *ValidationResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Valid    bool               `json:"valid"`
+		Errors   []*ValidationError `json:"errors"`
+		Warnings []*ValidationError `json:"warnings"`
+	}{Valid: r.IsValid, Errors: nonNilFindings(r.Errors), Warnings: nonNilFindings(r.Warnings)})
+}
+
+// nonNilFindings returns findings, or an empty list when it is nil, so that JSON has []
+func nonNilFindings(findings []*ValidationError) []*ValidationError {
+	if findings == nil {
+		return []*ValidationError{}
+	}
+	return findings
+}
+
 // Error returns error message
 func (r *ValidationResult) Error() string {
 	if r.IsValid {
@@ -61,6 +173,8 @@ func (r *ValidationResult) Error() string {
 // Validator configuration validator
 type Validator struct {
 	config *conf.Polaris
+	// serverToken is the token ValidateWithServer checks instead of the configured one
+	serverToken string
 }
 
 // NewValidator creates new validator
@@ -74,7 +188,7 @@ func NewValidator(config *conf.Polaris) *Validator {
 func (v *Validator) Validate() *ValidationResult {
 	result := NewValidationResult()
 	if v.config == nil {
-		result.AddError("config", "configuration is required", nil)
+		result.AddCodedError(ValidationCodeRequired, "config", "configuration is required", nil)
 		return result
 	}
 
@@ -109,23 +223,23 @@ func (v *Validator) Validate() *ValidationResult {
 func (v *Validator) validateBasicFields(result *ValidationResult) {
 	// Validate namespace
 	if v.config.Namespace == "" {
-		result.AddError("namespace", "namespace cannot be empty", v.config.Namespace)
+		result.AddCodedError(ValidationCodeRequired, "namespace", "namespace cannot be empty", v.config.Namespace)
 	} else if len(v.config.Namespace) > 64 {
-		result.AddError("namespace", "namespace length must not exceed 64 characters", v.config.Namespace)
+		result.AddCodedError(ValidationCodeOutOfRange, "namespace", "namespace length must not exceed 64 characters", v.config.Namespace)
 	} else {
 		// Validate namespace format (only letters, numbers, underscores, and hyphens allowed)
 		namespaceRegex := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 		if !namespaceRegex.MatchString(v.config.Namespace) {
-			result.AddError("namespace", "namespace can only contain letters, numbers, underscores, and hyphens", v.config.Namespace)
+			result.AddCodedError(ValidationCodeInvalidFormat, "namespace", "namespace can only contain letters, numbers, underscores, and hyphens", v.config.Namespace)
 		}
 	}
 
 	// Validate Token (if provided). Never put token value in result (security).
 	if v.config.Token != "" && len(v.config.Token) > 1024 {
-		result.AddError("token", "token length must not exceed 1024 characters", "[REDACTED]")
+		result.AddCodedError(ValidationCodeOutOfRange, "token", "token length must not exceed 1024 characters", redactedValue)
 	}
 	if v.config.Token != "" && len(v.config.Token) < 8 {
-		result.AddError("token", "token must be at least 8 characters long", "[REDACTED]")
+		result.AddCodedError(ValidationCodeOutOfRange, "token", "token must be at least 8 characters long", redactedValue)
 	}
 }
 
@@ -133,23 +247,372 @@ func (v *Validator) validateBasicFields(result *ValidationResult) {
 func (v *Validator) validateNumericRanges(result *ValidationResult) {
 	// Validate weight
 	if v.config.Weight < conf.MinWeight || v.config.Weight > conf.MaxWeight {
-		result.AddError("weight", fmt.Sprintf("weight must be between %d and %d", conf.MinWeight, conf.MaxWeight), v.config.Weight)
+		result.AddCodedError(ValidationCodeOutOfRange, "weight", fmt.Sprintf("weight must be between %d and %d", conf.MinWeight, conf.MaxWeight), v.config.Weight)
 	}
 
 	// Validate TTL (MinTTL=5, MaxTTL=300)
 	if v.config.Ttl < conf.MinTTL || v.config.Ttl > conf.MaxTTL {
-		result.AddError("ttl", fmt.Sprintf("ttl must be between %d and %d seconds", conf.MinTTL, conf.MaxTTL), v.config.Ttl)
+		result.AddCodedError(ValidationCodeOutOfRange, "ttl", fmt.Sprintf("ttl must be between %d and %d seconds", conf.MinTTL, conf.MaxTTL), v.config.Ttl)
+	} else if v.config.Ttl < conf.AggressiveTTL {
+		result.AddCodedWarning(ValidationCodeAggressiveTTL, "ttl", fmt.Sprintf("a ttl of %ds is aggressive: a few slow heartbeats take the instance offline", v.config.Ttl), v.config.Ttl)
 	}
 
 	// Validate retry configuration
 	if v.config.MaxRetryTimes < conf.MinRetryTimes || v.config.MaxRetryTimes > conf.MaxRetryTimes {
-		result.AddError("max_retry_times", fmt.Sprintf("max_retry_times must be between %d and %d", conf.MinRetryTimes, conf.MaxRetryTimes), v.config.MaxRetryTimes)
+		result.AddCodedError(ValidationCodeOutOfRange, "max_retry_times", fmt.Sprintf("max_retry_times must be between %d and %d", conf.MinRetryTimes, conf.MaxRetryTimes), v.config.MaxRetryTimes)
+	}
+
+	// Validate retry backoff strategy
+	if v.config.RetryBackoff != "" && !slices.Contains(conf.SupportedRetryBackoffs, v.config.RetryBackoff) {
+		result.AddCodedError(ValidationCodeUnsupportedValue, "retry_backoff", fmt.Sprintf("retry_backoff must be one of %v", conf.SupportedRetryBackoffs), v.config.RetryBackoff)
+	}
+
+	// Validate metrics backend
+	if v.config.MetricsBackend != "" && !slices.Contains(conf.SupportedMetricsBackends, v.config.MetricsBackend) {
+		result.AddCodedError(ValidationCodeUnsupportedValue, "metrics_backend", fmt.Sprintf("metrics_backend must be one of %v", conf.SupportedMetricsBackends), v.config.MetricsBackend)
+	}
+
+	// Validate audit sink and sampling
+	if audit := v.config.Audit; audit != nil {
+		switch {
+		case audit.Sink != "" && !slices.Contains(conf.SupportedAuditSinks, audit.Sink):
+			result.AddCodedError(ValidationCodeUnsupportedValue, "audit.sink", fmt.Sprintf("audit.sink must be one of %v", conf.SupportedAuditSinks), audit.Sink)
+		case audit.Sink == conf.AuditSinkFile && audit.Path == "":
+			result.AddCodedError(ValidationCodeRequired, "audit.path", "audit.path is required by the file sink", audit.Path)
+		case audit.Sink == conf.AuditSinkWebhook && audit.Url == "":
+			result.AddCodedError(ValidationCodeRequired, "audit.url", "audit.url is required by the webhook sink", audit.Url)
+		}
+		if audit.SampleRate < 0 || audit.SampleRate > 1 {
+			result.AddCodedError(ValidationCodeOutOfRange, "audit.sample_rate", "audit.sample_rate must be between 0 and 1", audit.SampleRate)
+		}
+		for eventType, rate := range audit.SampleRates {
+			if rate < 0 || rate > 1 {
+				result.AddCodedError(ValidationCodeOutOfRange, "audit.sample_rates."+eventType, "audit sample rates must be between 0 and 1", rate)
+			}
+		}
+	}
+
+	// Validate alert webhooks
+	for i, webhook := range v.config.GetAlerting().GetWebhooks() {
+		field := fmt.Sprintf("alerting.webhooks[%d]", i)
+		if webhook.Type != "" && !slices.Contains(conf.SupportedAlertWebhooks, webhook.Type) {
+			result.AddCodedError(ValidationCodeUnsupportedValue, field+".type", fmt.Sprintf("alert webhook type must be one of %v", conf.SupportedAlertWebhooks), webhook.Type)
+		}
+		if webhook.Url == "" {
+			result.AddCodedError(ValidationCodeRequired, field+".url", "alert webhook url is required", webhook.Url)
+		}
+		if webhook.MinSeverity != "" && !slices.Contains(conf.SupportedAlertSeverities, webhook.MinSeverity) {
+			result.AddCodedError(ValidationCodeUnsupportedValue, field+".min_severity", fmt.Sprintf("alert min_severity must be one of %v", conf.SupportedAlertSeverities), webhook.MinSeverity)
+		}
+	}
+
+	// Validate warm-up
+	if wu := v.config.WarmUp; wu != nil && wu.Enabled {
+		if wu.Duration == nil || wu.Duration.AsDuration() <= 0 || wu.Duration.AsDuration() > conf.MaxWarmUpDuration {
+			result.AddCodedError(ValidationCodeOutOfRange, "warm_up.duration", fmt.Sprintf("warm_up.duration must be positive and at most %v", conf.MaxWarmUpDuration), wu.Duration)
+		}
+		if wu.InitialWeight < 0 || wu.InitialWeight > v.config.Weight {
+			result.AddCodedError(ValidationCodeOutOfRange, "warm_up.initial_weight", "warm_up.initial_weight must be between 0 and weight", wu.InitialWeight)
+		}
+	}
+
+	// Validate load-based weighting
+	if aw := v.config.AutoWeight; aw != nil {
+		if aw.MinWeight < 0 || aw.MinWeight > conf.MaxWeight {
+			result.AddCodedError(ValidationCodeOutOfRange, "auto_weight.min_weight", fmt.Sprintf("auto_weight.min_weight must be between 0 and %d", conf.MaxWeight), aw.MinWeight)
+		}
+		if aw.Interval != nil && aw.Interval.AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "auto_weight.interval", "auto_weight.interval must not be negative", aw.Interval.AsDuration())
+		}
+	}
+
+	// Validate heartbeat
+	if hb := v.config.Heartbeat; hb != nil {
+		if hb.Jitter < 0 || hb.Jitter > conf.MaxHeartbeatJitter {
+			result.AddCodedError(ValidationCodeOutOfRange, "heartbeat.jitter", fmt.Sprintf("heartbeat.jitter must be between 0 and %v", conf.MaxHeartbeatJitter), hb.Jitter)
+		}
+		if hb.FailureThreshold < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "heartbeat.failure_threshold", "heartbeat.failure_threshold must not be negative", hb.FailureThreshold)
+		}
+		if hb.Interval != nil && v.config.Ttl > 0 && hb.Interval.AsDuration() >= time.Duration(v.config.Ttl)*time.Second {
+			result.AddCodedError(ValidationCodeConflict, "heartbeat.interval", "heartbeat.interval must be less than ttl", hb.Interval.AsDuration())
+		}
+	}
+
+	// Validate registration watchdog
+	if rw := v.config.RegistrationWatchdog; rw != nil && rw.Interval != nil && rw.Interval.AsDuration() < 0 {
+		result.AddCodedError(ValidationCodeOutOfRange, "registration_watchdog.interval", "registration_watchdog.interval must not be negative", rw.Interval.AsDuration())
+	}
+
+	// Validate self-healing
+	if sh := v.config.SelfHealing; sh != nil {
+		if sh.FailureDuration != nil && sh.FailureDuration.AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "self_healing.failure_duration", "self_healing.failure_duration must not be negative", sh.FailureDuration.AsDuration())
+		}
+		if sh.Cooldown != nil && sh.Cooldown.AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "self_healing.cooldown", "self_healing.cooldown must not be negative", sh.Cooldown.AsDuration())
+		}
+	}
+
+	// Validate the standby cluster
+	if sb := v.config.Standby; sb != nil {
+		if !hasStandby(sb) {
+			result.AddCodedError(ValidationCodeRequired, "standby.server_bootstrap", "standby.server_bootstrap must set the addresses of the standby cluster", nil)
+		}
+		for i, address := range sb.GetServerBootstrap().GetAddresses() {
+			v.validateServerAddress(result, fmt.Sprintf("standby.server_bootstrap.addresses[%d]", i), address)
+		}
+		for i, address := range sb.GetServerBootstrap().GetConfigAddresses() {
+			v.validateServerAddress(result, fmt.Sprintf("standby.server_bootstrap.config_addresses[%d]", i), address)
+		}
+		if sb.FailoverAfter != nil && sb.FailoverAfter.AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "standby.failover_after", "standby.failover_after must not be negative", sb.FailoverAfter.AsDuration())
+		}
+		if sb.SwitchbackAfter != nil && sb.SwitchbackAfter.AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "standby.switchback_after", "standby.switchback_after must not be negative", sb.SwitchbackAfter.AsDuration())
+		}
+	}
+
+	// Validate discovery aggregation
+	if da := v.config.DiscoveryAggregation; da != nil {
+		for i, namespace := range da.Namespaces {
+			if strings.TrimSpace(namespace) == "" {
+				result.AddCodedError(ValidationCodeRequired, fmt.Sprintf("discovery_aggregation.namespaces[%d]", i), "discovery_aggregation namespaces must not be empty", namespace)
+			}
+		}
+		for i, service := range da.Services {
+			if strings.TrimSpace(service) == "" {
+				result.AddCodedError(ValidationCodeRequired, fmt.Sprintf("discovery_aggregation.services[%d]", i), "discovery_aggregation services must not be empty", service)
+			}
+		}
+	}
+
+	// Validate remote config
+	if rc := v.config.RemoteConfig; rc != nil && rc.Filename == "" && (rc.Group != "" || rc.Required) {
+		result.AddCodedError(ValidationCodeRequired, "remote_config.filename", "remote_config.filename is required", nil)
+	}
+
+	// Validate readiness
+	if rd := v.config.Readiness; rd != nil {
+		if rd.Timeout != nil && rd.Timeout.AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "readiness.timeout", "readiness.timeout must not be negative", rd.Timeout.AsDuration())
+		}
+		for i, service := range rd.Services {
+			if strings.TrimSpace(service) == "" {
+				result.AddCodedError(ValidationCodeRequired, fmt.Sprintf("readiness.services[%d]", i), "readiness services must not be empty", service)
+			}
+		}
+	}
+
+	// Validate reported health state
+	if hs := v.config.HealthState; hs != nil {
+		if hs.UnhealthyThreshold < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "health_state.unhealthy_threshold", "health_state.unhealthy_threshold must not be negative", hs.UnhealthyThreshold)
+		}
+		if hs.HealthyThreshold < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "health_state.healthy_threshold", "health_state.healthy_threshold must not be negative", hs.HealthyThreshold)
+		}
+		if hs.HistorySize < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "health_state.history_size", "health_state.history_size must not be negative", hs.HistorySize)
+		}
+	}
+
+	// Validate TLS of the Polaris server connections
+	if t := v.config.Tls; t.GetEnabled() {
+		if (t.CertFile == "") != (t.KeyFile == "") {
+			result.AddCodedError(ValidationCodeConflict, "tls.cert_file", "tls.cert_file and tls.key_file must be set together", t.CertFile)
+		}
+		for _, file := range []struct{ field, path string }{
+			{"tls.ca_file", t.CaFile}, {"tls.cert_file", t.CertFile}, {"tls.key_file", t.KeyFile},
+		} {
+			if file.path == "" {
+				continue
+			}
+			if _, err := os.Stat(file.path); err != nil {
+				result.AddCodedError(ValidationCodeUnreadableFile, file.field, fmt.Sprintf("%s is not readable: %v", file.field, err), file.path)
+			}
+		}
+	}
+
+	// Validate the token source
+	if ts := v.config.TokenSource; ts != nil {
+		if ts.File != "" && ts.Env != "" {
+			result.AddCodedError(ValidationCodeConflict, "token_source", "only one of token_source.file and token_source.env can be set", ts.Env)
+		}
+		if ts.RefreshInterval != nil && ts.RefreshInterval.AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "token_source.refresh_interval", "token_source.refresh_interval must not be negative", ts.RefreshInterval.AsDuration())
+		}
+	}
+
+	// Validate the tokens of write operations
+	for operation, token := range v.config.OperationTokens {
+		field := "operation_tokens." + operation
+		if !slices.Contains(tokenOperations, TokenOperation(operation)) {
+			result.AddCodedError(ValidationCodeUnsupportedValue, field, "operation must be one of write, config_write, isolation or weight", operation)
+			continue
+		}
+		sources := 0
+		for _, source := range []string{token.GetToken(), token.GetFile(), token.GetEnv()} {
+			if source != "" {
+				sources++
+			}
+		}
+		if sources != 1 {
+			result.AddCodedError(ValidationCodeConflict, field, "exactly one of token, file and env must be set", redactedValue)
+		}
+		if t := token.GetToken(); t != "" && (len(t) < 8 || len(t) > 1024) {
+			result.AddCodedError(ValidationCodeOutOfRange, field+".token", "token must be between 8 and 1024 characters long", redactedValue)
+		}
+	}
+
+	// Validate the sensitive config file patterns
+	for _, pattern := range v.config.SensitiveConfigFiles {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			result.AddCodedError(ValidationCodeInvalidFormat, "sensitive_config_files", "pattern must be a valid file name glob", pattern)
+		}
+	}
+
+	// Validate rate limit label normalization
+	if rl := v.config.RateLimitLabels; rl != nil {
+		if rl.HashBuckets < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "rate_limit_labels.hash_buckets", "rate_limit_labels.hash_buckets must not be negative", rl.HashBuckets)
+		}
+		if rl.MaxLabels < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "rate_limit_labels.max_labels", "rate_limit_labels.max_labels must not be negative", rl.MaxLabels)
+		}
+		if rl.MaxValueLength < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "rate_limit_labels.max_value_length", "rate_limit_labels.max_value_length must not be negative", rl.MaxValueLength)
+		}
+	}
+
+	// Validate circuit breaker half-open probes
+	if probes := v.config.CircuitBreakerHalfOpenProbes; probes < 0 || probes > conf.MaxCircuitBreakerHalfOpenProbes {
+		result.AddCodedError(ValidationCodeOutOfRange, "circuit_breaker_half_open_probes", fmt.Sprintf("circuit_breaker_half_open_probes must be between 0 and %d", conf.MaxCircuitBreakerHalfOpenProbes), probes)
+	}
+
+	// Validate circuit breaker minimum request volume
+	if v.config.CircuitBreakerMinRequests < 0 {
+		result.AddCodedError(ValidationCodeOutOfRange, "circuit_breaker_min_requests", "circuit_breaker_min_requests must not be negative", v.config.CircuitBreakerMinRequests)
+	}
+
+	// Validate rate limit fallback
+	if fb := v.config.RateLimitFallback; fb != nil {
+		if fb.Mode == conf.RateLimitFallbackLocal && fb.LocalQps <= 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "rate_limit_fallback.local_qps", "rate_limit_fallback.local_qps must be positive in local mode", fb.LocalQps)
+		}
+		if fb.LocalBurst < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "rate_limit_fallback.local_burst", "rate_limit_fallback.local_burst must not be negative", fb.LocalBurst)
+		}
+	}
+
+	// Validate concurrency limit
+	if cl := v.config.ConcurrencyLimit; cl != nil {
+		if cl.MaxInFlight < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "concurrency_limit.max_in_flight", "concurrency_limit.max_in_flight must not be negative", cl.MaxInFlight)
+		}
+		if cl.MaxWait != nil && cl.MaxWait.AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "concurrency_limit.max_wait", "concurrency_limit.max_wait must not be negative", cl.MaxWait.AsDuration())
+		}
+	}
+
+	// Validate watch workers
+	if v.config.WatchWorkers < 0 {
+		result.AddCodedError(ValidationCodeOutOfRange, "watch_workers", "watch_workers must not be negative", v.config.WatchWorkers)
+	}
+
+	// Validate hot services
+	if hot := v.config.GetHotServices(); hot != nil {
+		for i, service := range hot.GetServices() {
+			if service == "" {
+				result.AddCodedError(ValidationCodeRequired, fmt.Sprintf("hot_services.services[%d]", i), "service name is required", nil)
+			}
+		}
+		if hot.GetWait().AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "hot_services.wait", "hot_services.wait must not be negative", hot.GetWait().AsDuration())
+		}
+	}
+
+	// Validate cache settings
+	if c := v.config.Cache; c != nil {
+		if c.Ttl != nil && c.Ttl.AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "cache.ttl", "cache.ttl must not be negative", c.Ttl.AsDuration())
+		}
+		if c.MaxStale != nil && c.MaxStale.AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "cache.max_stale", "cache.max_stale must not be negative", c.MaxStale.AsDuration())
+		}
+		if c.MaxEntries < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "cache.max_entries", "cache.max_entries must not be negative", c.MaxEntries)
+		}
+	}
+
+	// Validate fault injection rules
+	if rules := v.config.GetFaultInjection().GetRules(); len(rules) > 0 {
+		result.AddCodedWarning(ValidationCodeFaultInjectionEnabled, "fault_injection.rules", "fault injection is enabled: matching calls are delayed or fail on purpose", len(rules))
+	}
+	validateFaultInjectionRuleList(result, "fault_injection.rules", v.config.GetFaultInjection().GetRules())
+	if fi := v.config.GetFaultInjection(); fi.GetRulesPath() != "" {
+		if !strings.HasPrefix(fi.GetRulesPath(), "/") {
+			result.AddCodedError(ValidationCodeInvalidFormat, "fault_injection.rules_path", "fault_injection.rules_path must start with /", fi.GetRulesPath())
+		}
+		if v.config.GetConfigAdmin().GetAddress() == "" {
+			result.AddCodedError(ValidationCodeDependency, "fault_injection.rules_path", "fault_injection.rules_path requires config_admin.address", nil)
+		}
+		if fi.RefreshInterval != nil && fi.RefreshInterval.AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "fault_injection.refresh_interval", "fault_injection.refresh_interval must not be negative", fi.RefreshInterval.AsDuration())
+		}
+	}
+}
+
+// validateFaultInjectionRuleList validates fault injection rules, reported under field.
+func validateFaultInjectionRuleList(result *ValidationResult, field string, rules []*conf.FaultInjectionRule) {
+	for i, rule := range rules {
+		field := fmt.Sprintf("%s[%d]", field, i)
+		if rule.GetDelay() == nil && rule.GetAbort() == nil {
+			result.AddCodedError(ValidationCodeRequired, field, "a fault injection rule needs a delay or an abort", rule.GetName())
+		}
+		if delay := rule.GetDelay(); delay != nil {
+			if delay.Duration == nil || delay.Duration.AsDuration() <= 0 {
+				result.AddCodedError(ValidationCodeOutOfRange, field+".delay.duration", "delay.duration must be positive", delay.Duration)
+			}
+			if delay.Percentage < 0 || delay.Percentage > 100 {
+				result.AddCodedError(ValidationCodeOutOfRange, field+".delay.percentage", "delay.percentage must be between 0 and 100", delay.Percentage)
+			}
+		}
+		if abort := rule.GetAbort(); abort != nil {
+			if abort.Code < 400 || abort.Code > 599 {
+				result.AddCodedError(ValidationCodeOutOfRange, field+".abort.code", "abort.code must be between 400 and 599", abort.Code)
+			}
+			if abort.Percentage < 0 || abort.Percentage > 100 {
+				result.AddCodedError(ValidationCodeOutOfRange, field+".abort.percentage", "abort.percentage must be between 0 and 100", abort.Percentage)
+			}
+		}
 	}
 }
 
 // validateEnumValues validates enum values
 func (v *Validator) validateEnumValues(result *ValidationResult) {
-	// No enum value fields in current configuration, skip validation
+	// Validate watch partition level
+	if wp := v.config.WatchPartition; wp != nil && wp.Level != "" && !slices.Contains(conf.SupportedPartitionLevels, wp.Level) {
+		result.AddCodedError(ValidationCodeUnsupportedValue, "watch_partition.level", fmt.Sprintf("watch_partition.level must be one of %v", conf.SupportedPartitionLevels), wp.Level)
+	}
+
+	// Validate rate limit fallback mode
+	if fb := v.config.RateLimitFallback; fb != nil && fb.Mode != "" && !slices.Contains(conf.SupportedRateLimitFallbackModes, fb.Mode) {
+		result.AddCodedError(ValidationCodeUnsupportedValue, "rate_limit_fallback.mode", fmt.Sprintf("rate_limit_fallback.mode must be one of %v", conf.SupportedRateLimitFallbackModes), fb.Mode)
+	}
+
+	// Validate readiness timeout policy
+	if rd := v.config.Readiness; rd != nil && rd.OnTimeout != "" && !slices.Contains(conf.SupportedReadinessOnTimeout, rd.OnTimeout) {
+		result.AddCodedError(ValidationCodeUnsupportedValue, "readiness.on_timeout", fmt.Sprintf("readiness.on_timeout must be one of %v", conf.SupportedReadinessOnTimeout), rd.OnTimeout)
+	}
+
+	// Validate additional config merge strategies
+	for i, cfg := range v.config.GetServiceConfig().GetAdditionalConfigs() {
+		if strategy := cfg.GetMergeStrategy(); strategy != "" && !slices.Contains(conf.SupportedMergeStrategies, strategy) {
+			field := fmt.Sprintf("service_config.additional_configs[%d].merge_strategy", i)
+			result.AddCodedError(ValidationCodeUnsupportedValue, field, fmt.Sprintf("merge_strategy must be one of %v", conf.SupportedMergeStrategies), strategy)
+		}
+	}
 }
 
 // validateTimeConfigs validates time-related configurations (single source of truth from conf constants)
@@ -160,7 +623,54 @@ func (v *Validator) validateTimeConfigs(result *ValidationResult) {
 		minTimeout := time.Duration(conf.MinTimeoutSeconds) * time.Second
 		maxTimeout := time.Duration(conf.MaxTimeoutSeconds) * time.Second
 		if timeout < minTimeout || timeout > maxTimeout {
-			result.AddError("timeout", fmt.Sprintf("timeout must be between %d and %d seconds", conf.MinTimeoutSeconds, conf.MaxTimeoutSeconds), timeout)
+			result.AddCodedError(ValidationCodeOutOfRange, "timeout", fmt.Sprintf("timeout must be between %d and %d seconds", conf.MinTimeoutSeconds, conf.MaxTimeoutSeconds), timeout)
+		}
+	}
+
+	if v.config.DrainDelay != nil {
+		drainDelay := v.config.DrainDelay.AsDuration()
+		if drainDelay < 0 || drainDelay > conf.MaxDrainDelay {
+			result.AddCodedError(ValidationCodeOutOfRange, "drain_delay", fmt.Sprintf("drain_delay must be between 0 and %v", conf.MaxDrainDelay), drainDelay)
+		}
+	}
+
+	if v.config.CircuitBreakerOpenDuration != nil {
+		openDuration := v.config.CircuitBreakerOpenDuration.AsDuration()
+		if openDuration < conf.MinCircuitBreakerHalfOpenTimeout || openDuration > conf.MaxCircuitBreakerHalfOpenTimeout {
+			result.AddCodedError(ValidationCodeOutOfRange, "circuit_breaker_open_duration", fmt.Sprintf("circuit_breaker_open_duration must be between %v and %v", conf.MinCircuitBreakerHalfOpenTimeout, conf.MaxCircuitBreakerHalfOpenTimeout), openDuration)
+		}
+	}
+
+	if v.config.CircuitBreakerWindow != nil {
+		window := v.config.CircuitBreakerWindow.AsDuration()
+		if window < conf.MinCircuitBreakerWindow || window > conf.MaxCircuitBreakerWindow {
+			result.AddCodedError(ValidationCodeOutOfRange, "circuit_breaker_window", fmt.Sprintf("circuit_breaker_window must be between %v and %v", conf.MinCircuitBreakerWindow, conf.MaxCircuitBreakerWindow), window)
+		}
+	}
+
+	if v.config.RetryMaxDelay != nil {
+		maxDelay := v.config.RetryMaxDelay.AsDuration()
+		if maxDelay <= 0 || maxDelay > conf.MaxRetryMaxDelay {
+			result.AddCodedError(ValidationCodeOutOfRange, "retry_max_delay", fmt.Sprintf("retry_max_delay must be positive and at most %v", conf.MaxRetryMaxDelay), maxDelay)
+		}
+	}
+
+	if v.config.HedgeDelay != nil && v.config.HedgeDelay.AsDuration() < 0 {
+		result.AddCodedError(ValidationCodeOutOfRange, "hedge_delay", "hedge_delay must not be negative", v.config.HedgeDelay.AsDuration())
+	}
+
+	if v.config.CircuitBreakerSlowCallThreshold != nil && v.config.CircuitBreakerSlowCallThreshold.AsDuration() < 0 {
+		result.AddCodedError(ValidationCodeOutOfRange, "circuit_breaker_slow_call_threshold", "circuit_breaker_slow_call_threshold must not be negative", v.config.CircuitBreakerSlowCallThreshold.AsDuration())
+	}
+
+	for i, s := range v.config.ConfigStaleness {
+		field := fmt.Sprintf("config_staleness[%d]", i)
+		if s == nil || s.FileName == "" {
+			result.AddCodedError(ValidationCodeRequired, field+".file_name", "file_name is required", nil)
+			continue
+		}
+		if s.MaxAge == nil || s.MaxAge.AsDuration() <= 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, field+".max_age", "max_age must be positive", s.MaxAge)
 		}
 	}
 }
@@ -172,16 +682,36 @@ func (v *Validator) validateDependencies(result *ValidationResult) {
 		timeout := v.config.Timeout.AsDuration()
 		ttlDuration := time.Duration(v.config.Ttl) * time.Second
 		if timeout >= ttlDuration {
-			result.AddError("timeout", "timeout should be less than TTL for proper service registration", timeout)
+			result.AddCodedError(ValidationCodeConflict, "timeout", "timeout should be less than TTL for proper service registration", timeout)
+		}
+	}
+
+	// Dry run keeps writes from reaching Polaris, which is easy to leave on by mistake
+	if v.config.DryRun {
+		result.AddCodedWarning(ValidationCodeDryRunEnabled, "dry_run", "dry_run is enabled: registrations and config writes are not sent to Polaris", v.config.DryRun)
+	}
+
+	// Config files are only loaded when the config subsystem is enabled
+	if !subsystemEnabled(v.config.Subsystems, SubsystemConfig) {
+		if v.config.RemoteConfig.GetFilename() != "" {
+			result.AddCodedError(ValidationCodeDependency, "remote_config", "remote_config requires the config subsystem", nil)
+		}
+		if len(v.config.RequiredConfigs.GetFiles()) > 0 {
+			result.AddCodedError(ValidationCodeDependency, "required_configs", "required_configs requires the config subsystem", nil)
 		}
 	}
 }
 
 // validateSecurityConfigs validates security-related configurations
 func (v *Validator) validateSecurityConfigs(result *ValidationResult) {
+	settings := v.validationSettings()
+
 	// Token complexity check: optional, disabled by default for Polaris compatibility.
-	// Enable via POLARIS_ENABLE_TOKEN_COMPLEXITY_CHECK=1 to require letters+digits.
-	if v.config.Token != "" && os.Getenv("POLARIS_ENABLE_TOKEN_COMPLEXITY_CHECK") == "1" {
+	// Enable with validation.token_complexity_check to require letters+digits.
+	if v.config.Token != "" && !settings.GetTokenComplexityCheck() {
+		result.AddCodedWarning(ValidationCodeCheckDisabled, "token", "token complexity check disabled (set validation.token_complexity_check to enable it)", redactedValue)
+	}
+	if v.config.Token != "" && settings.GetTokenComplexityCheck() {
 		hasLetter := false
 		hasDigit := false
 		for _, char := range v.config.Token {
@@ -193,44 +723,195 @@ func (v *Validator) validateSecurityConfigs(result *ValidationResult) {
 			}
 		}
 		if !hasLetter || !hasDigit {
-			result.AddError("token", "token must contain both letters and numbers (POLARIS_ENABLE_TOKEN_COMPLEXITY_CHECK=1)", "[REDACTED]")
+			result.AddCodedError(ValidationCodeWeakToken, "token", "token must contain both letters and numbers (validation.token_complexity_check)", redactedValue)
 		}
 	}
 
-	// Validate namespace security (optional; disable via POLARIS_DISABLE_NAMESPACE_SENSITIVE_CHECK=1 or override list via POLARIS_NAMESPACE_SENSITIVE_WORDS)
+	// Validate namespace security (optional; disable with validation.skip_namespace_sensitive_check
+	// or override the list with validation.namespace_sensitive_words)
 	if v.config.Namespace == "" {
 		return
 	}
-	if os.Getenv("POLARIS_DISABLE_NAMESPACE_SENSITIVE_CHECK") == "1" || os.Getenv("POLARIS_DISABLE_NAMESPACE_SENSITIVE_CHECK") == "true" {
+	if settings.GetSkipNamespaceSensitiveCheck() {
+		result.AddCodedWarning(ValidationCodeCheckDisabled, "namespace", "namespace sensitive word check disabled (validation.skip_namespace_sensitive_check)", v.config.Namespace)
 		return
 	}
-	sensitiveChars := defaultNamespaceSensitiveWords()
-	if override := os.Getenv("POLARIS_NAMESPACE_SENSITIVE_WORDS"); override != "" {
-		sensitiveChars = strings.Split(override, ",")
-		for i, s := range sensitiveChars {
-			sensitiveChars[i] = strings.TrimSpace(strings.ToLower(s))
+	sensitiveWords := defaultNamespaceSensitiveWords()
+	if words := settings.GetNamespaceSensitiveWords(); len(words) > 0 {
+		sensitiveWords = make([]string, len(words))
+		for i, word := range words {
+			sensitiveWords[i] = strings.TrimSpace(strings.ToLower(word))
 		}
 	}
 	namespaceLower := strings.ToLower(v.config.Namespace)
-	for _, sensitive := range sensitiveChars {
+	for _, sensitive := range sensitiveWords {
 		if sensitive == "" {
 			continue
 		}
 		if strings.Contains(namespaceLower, sensitive) {
-			result.AddError("namespace", fmt.Sprintf("namespace should not contain sensitive word: %s", sensitive), v.config.Namespace)
+			result.AddCodedError(ValidationCodeSensitiveWord, "namespace", fmt.Sprintf("namespace should not contain sensitive word: %s", sensitive), v.config.Namespace)
 			return
 		}
 	}
 }
 
-// defaultNamespaceSensitiveWords returns the default list when not overridden by env
+// validationSettings returns the validation settings of the config, each field that is not
+// set read from its environment variable: POLARIS_ENABLE_TOKEN_COMPLEXITY_CHECK,
+// POLARIS_DISABLE_NAMESPACE_SENSITIVE_CHECK or POLARIS_NAMESPACE_SENSITIVE_WORDS
+func (v *Validator) validationSettings() *conf.Validation {
+	settings := &conf.Validation{}
+	if configured := v.config.GetValidation(); configured != nil {
+		settings.TokenComplexityCheck = configured.TokenComplexityCheck
+		settings.SkipNamespaceSensitiveCheck = configured.SkipNamespaceSensitiveCheck
+		settings.NamespaceSensitiveWords = configured.NamespaceSensitiveWords
+	}
+	if settings.TokenComplexityCheck == nil {
+		settings.TokenComplexityCheck = proto.Bool(os.Getenv("POLARIS_ENABLE_TOKEN_COMPLEXITY_CHECK") == "1")
+	}
+	if settings.SkipNamespaceSensitiveCheck == nil {
+		skip := os.Getenv("POLARIS_DISABLE_NAMESPACE_SENSITIVE_CHECK")
+		settings.SkipNamespaceSensitiveCheck = proto.Bool(skip == "1" || skip == "true")
+	}
+	if words := os.Getenv("POLARIS_NAMESPACE_SENSITIVE_WORDS"); len(settings.NamespaceSensitiveWords) == 0 && words != "" {
+		settings.NamespaceSensitiveWords = strings.Split(words, ",")
+	}
+	return settings
+}
+
+// defaultNamespaceSensitiveWords returns the default list when not overridden by config or env
 func defaultNamespaceSensitiveWords() []string {
 	return []string{"admin", "root", "system", "internal"}
 }
 
 // validateNetworkConfigs validates network-related configurations (retry covered by validateNumericRanges)
 func (v *Validator) validateNetworkConfigs(result *ValidationResult) {
-	// No additional network validations; timeout and retry use conf constants
+	// Validate static fallback endpoints
+	for i, svc := range v.config.FallbackServices {
+		field := fmt.Sprintf("fallback_services[%d]", i)
+		if svc == nil || svc.Service == "" {
+			result.AddCodedError(ValidationCodeRequired, field+".service", "fallback service name cannot be empty", nil)
+			continue
+		}
+		for j, inst := range svc.Instances {
+			v.validateFallbackInstance(result, fmt.Sprintf("%s.instances[%d]", field, j), inst)
+		}
+	}
+
+	// Validate route fallback targets
+	for i, rf := range v.config.RouteFallbacks {
+		field := fmt.Sprintf("route_fallbacks[%d]", i)
+		if rf == nil || rf.Service == "" {
+			result.AddCodedError(ValidationCodeRequired, field+".service", "route fallback service name cannot be empty", nil)
+			continue
+		}
+		if rf.TargetService == rf.Service {
+			result.AddCodedError(ValidationCodeConflict, field+".target_service", "route fallback target must differ from the primary service", rf.TargetService)
+		}
+		if rf.TargetService == "" && len(rf.TargetInstances) == 0 {
+			result.AddCodedError(ValidationCodeRequired, field, "route fallback requires target_service or target_instances", rf.Service)
+		}
+		for j, inst := range rf.TargetInstances {
+			v.validateFallbackInstance(result, fmt.Sprintf("%s.target_instances[%d]", field, j), inst)
+		}
+	}
+
+	// Validate host detection networks
+	for i, cidr := range v.config.GetHostDetection().GetCidrs() {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			result.AddCodedError(ValidationCodeInvalidFormat, fmt.Sprintf("host_detection.cidrs[%d]", i), "host_detection.cidrs entries must be valid CIDRs", cidr)
+		}
+	}
+
+	// Validate config snapshot age
+	if maxAge := v.config.GetConfigSnapshot().GetMaxAge(); maxAge != nil && maxAge.AsDuration() < 0 {
+		result.AddCodedError(ValidationCodeOutOfRange, "config_snapshot.max_age", "config_snapshot.max_age must not be negative", maxAge.AsDuration())
+	}
+
+	// Validate config debounce
+	if debounce := v.config.GetConfigDebounce(); debounce != nil {
+		window, maxWait := debounceSettings(debounce)
+		if window < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "config_debounce.window", "config_debounce.window must not be negative", window)
+		}
+		if debounce.GetMaxWait() != nil && maxWait < window {
+			result.AddCodedError(ValidationCodeConflict, "config_debounce.max_wait", "config_debounce.max_wait must not be less than window", maxWait)
+		}
+	}
+
+	// Validate required configs
+	if required := v.config.GetRequiredConfigs(); required != nil {
+		for i, file := range required.GetFiles() {
+			if file.GetFilename() == "" {
+				result.AddCodedError(ValidationCodeRequired, fmt.Sprintf("required_configs.files[%d].filename", i), "filename is required", nil)
+			}
+		}
+		if required.GetWait() != nil && required.GetWait().AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "required_configs.wait", "required_configs.wait must not be negative", required.GetWait().AsDuration())
+		}
+	}
+
+	// Validate config labels
+	for key := range v.config.GetConfigLabels() {
+		if strings.TrimSpace(key) == "" {
+			result.AddCodedError(ValidationCodeRequired, "config_labels", "config_labels keys must not be empty", key)
+		}
+	}
+
+	// Validate config admin address
+	if address := v.config.GetConfigAdmin().GetAddress(); address != "" {
+		if u, err := url.Parse(address); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			result.AddCodedError(ValidationCodeInvalidFormat, "config_admin.address", "config_admin.address must be an http or https URL", address)
+		}
+	}
+
+	// Validate server bootstrap addresses
+	if sb := v.config.ServerBootstrap; sb != nil {
+		for i, address := range sb.Addresses {
+			v.validateServerAddress(result, fmt.Sprintf("server_bootstrap.addresses[%d]", i), address)
+		}
+		for i, address := range sb.ConfigAddresses {
+			v.validateServerAddress(result, fmt.Sprintf("server_bootstrap.config_addresses[%d]", i), address)
+		}
+		if sb.RefreshInterval != nil && sb.RefreshInterval.AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "server_bootstrap.refresh_interval", "refresh_interval must not be negative", sb.RefreshInterval.AsDuration())
+		}
+		for i, host := range sb.Hosts {
+			if strings.TrimSpace(host) == "" || (strings.ContainsAny(host, ":/") && net.ParseIP(host) == nil) {
+				result.AddCodedError(ValidationCodeInvalidFormat, fmt.Sprintf("server_bootstrap.hosts[%d]", i), "host must be a DNS name or IP address without port", host)
+			}
+		}
+		if sb.DiscoverPort > 65535 {
+			result.AddCodedError(ValidationCodeOutOfRange, "server_bootstrap.discover_port", "discover_port must be between 1 and 65535", sb.DiscoverPort)
+		}
+		if sb.ConfigPort > 65535 {
+			result.AddCodedError(ValidationCodeOutOfRange, "server_bootstrap.config_port", "config_port must be between 1 and 65535", sb.ConfigPort)
+		}
+		if sb.FailoverCooldown != nil && sb.FailoverCooldown.AsDuration() < 0 {
+			result.AddCodedError(ValidationCodeOutOfRange, "server_bootstrap.failover_cooldown", "failover_cooldown must not be negative", sb.FailoverCooldown.AsDuration())
+		}
+	}
+}
+
+// validateServerAddress validates a host:port server address
+func (v *Validator) validateServerAddress(result *ValidationResult, field, address string) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" || port == "" {
+		result.AddCodedError(ValidationCodeInvalidFormat, field, "server address must be in host:port format", address)
+	}
+}
+
+// validateFallbackInstance validates a single static fallback endpoint
+func (v *Validator) validateFallbackInstance(result *ValidationResult, field string, inst *conf.FallbackInstance) {
+	if inst == nil || inst.Host == "" {
+		result.AddCodedError(ValidationCodeRequired, field+".host", "fallback instance host cannot be empty", nil)
+		return
+	}
+	if inst.Port == 0 || inst.Port > 65535 {
+		result.AddCodedError(ValidationCodeOutOfRange, field+".port", "fallback instance port must be between 1 and 65535", inst.Port)
+	}
+	if inst.Weight < 0 || inst.Weight > conf.MaxWeight {
+		result.AddCodedError(ValidationCodeOutOfRange, field+".weight", fmt.Sprintf("fallback instance weight must be between 0 and %d", conf.MaxWeight), inst.Weight)
+	}
 }
 
 // validatePerformanceConfigs validates performance-related configurations
@@ -0,0 +1,125 @@
+package polaris
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestValidator_Warnings(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default", Token: "polaris-token-1", Ttl: 8, Timeout: durationpb.New(2 * time.Second), DryRun: true}
+	setConfigDefaults(cfg)
+
+	result := NewValidator(cfg).Validate()
+	assert.True(t, result.IsValid, "warnings do not make the configuration invalid")
+	assert.Empty(t, result.Errors)
+	assert.Empty(t, result.Error())
+	fields := map[string]*ValidationError{}
+	for _, warning := range result.Warnings {
+		assert.Equal(t, SeverityWarning, warning.Severity)
+		fields[warning.Field] = warning
+	}
+	require.Contains(t, fields, "ttl")
+	assert.Contains(t, fields["ttl"].Error(), "validation warning for field 'ttl'")
+	assert.Equal(t, ValidationCodeAggressiveTTL, fields["ttl"].Code)
+	assert.Contains(t, fields, "token")
+	assert.Contains(t, fields, "dry_run")
+	assert.NotContains(t, fields["token"].Error(), "polaris-token-1")
+
+	cfg.Validation = &conf.Validation{TokenComplexityCheck: proto.Bool(true)}
+	cfg.Ttl = conf.DefaultTTL
+	cfg.DryRun = false
+	result = NewValidator(cfg).Validate()
+	assert.True(t, result.IsValid)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestValidator_ErrorsHaveErrorSeverity(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "default"}
+	setConfigDefaults(cfg)
+	cfg.Ttl = 1
+
+	result := NewValidator(cfg).Validate()
+	require.False(t, result.IsValid)
+	assert.Equal(t, SeverityError, result.Errors[0].Severity)
+	assert.Equal(t, ValidationCodeOutOfRange, result.Errors[0].Code)
+	for _, warning := range result.Warnings {
+		assert.NotEqual(t, "ttl", warning.Field, "an invalid ttl is not also aggressive")
+	}
+}
+
+func TestValidator_ValidationSettings(t *testing.T) {
+	newConfig := func(namespace, token string, validation *conf.Validation) *conf.Polaris {
+		cfg := &conf.Polaris{Namespace: namespace, Token: token, Validation: validation}
+		setConfigDefaults(cfg)
+		return cfg
+	}
+
+	assert.False(t, NewValidator(newConfig("admin-tools", "", nil)).Validate().IsValid)
+	assert.True(t, NewValidator(newConfig("admin-tools", "", &conf.Validation{SkipNamespaceSensitiveCheck: proto.Bool(true)})).Validate().IsValid)
+	assert.True(t, NewValidator(newConfig("admin-tools", "", &conf.Validation{NamespaceSensitiveWords: []string{"Secret"}})).Validate().IsValid)
+	assert.False(t, NewValidator(newConfig("team-secret", "", &conf.Validation{NamespaceSensitiveWords: []string{"Secret"}})).Validate().IsValid)
+	assert.False(t, NewValidator(newConfig("default", "lettersonly", &conf.Validation{TokenComplexityCheck: proto.Bool(true)})).Validate().IsValid)
+	assert.True(t, NewValidator(newConfig("default", "lettersonly", nil)).Validate().IsValid)
+
+	t.Setenv("POLARIS_ENABLE_TOKEN_COMPLEXITY_CHECK", "1")
+	t.Setenv("POLARIS_DISABLE_NAMESPACE_SENSITIVE_CHECK", "true")
+	assert.False(t, NewValidator(newConfig("default", "lettersonly", nil)).Validate().IsValid, "environment variables are read without validation settings")
+	assert.True(t, NewValidator(newConfig("admin-tools", "", nil)).Validate().IsValid)
+	assert.False(t, NewValidator(newConfig("default", "lettersonly", &conf.Validation{})).Validate().IsValid, "unset fields read the environment")
+	assert.True(t, NewValidator(newConfig("default", "lettersonly", &conf.Validation{TokenComplexityCheck: proto.Bool(false)})).Validate().IsValid, "set fields take precedence")
+	assert.False(t, NewValidator(newConfig("admin-tools", "", &conf.Validation{SkipNamespaceSensitiveCheck: proto.Bool(false)})).Validate().IsValid)
+}
+
+func TestValidator_ValidationSettingsPerField(t *testing.T) {
+	t.Setenv("POLARIS_DISABLE_NAMESPACE_SENSITIVE_CHECK", "1")
+	t.Setenv("POLARIS_NAMESPACE_SENSITIVE_WORDS", "secret")
+	cfg := &conf.Polaris{Namespace: "admin-tools", Token: "lettersonly", Validation: &conf.Validation{TokenComplexityCheck: proto.Bool(true)}}
+	setConfigDefaults(cfg)
+
+	settings := NewValidator(cfg).validationSettings()
+	assert.True(t, settings.GetTokenComplexityCheck())
+	assert.True(t, settings.GetSkipNamespaceSensitiveCheck(), "the environment fills the fields the config leaves unset")
+	assert.Equal(t, []string{"secret"}, settings.GetNamespaceSensitiveWords())
+
+	result := NewValidator(cfg).Validate()
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "token", result.Errors[0].Field)
+	cfg.Token = "token123"
+	assert.True(t, NewValidator(cfg).Validate().IsValid)
+}
+
+func TestValidationResult_MarshalJSON(t *testing.T) {
+	cfg := &conf.Polaris{Namespace: "bad namespace", Token: "polaris-token-1"}
+	setConfigDefaults(cfg)
+
+	data, err := json.Marshal(NewValidator(cfg).Validate())
+	require.NoError(t, err)
+	var decoded struct {
+		Valid    bool                `json:"valid"`
+		Errors   []map[string]string `json:"errors"`
+		Warnings []map[string]string `json:"warnings"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.False(t, decoded.Valid)
+	assert.Equal(t, []map[string]string{{
+		"field":    "namespace",
+		"code":     "INVALID_FORMAT",
+		"severity": "error",
+		"message":  "namespace can only contain letters, numbers, underscores, and hyphens",
+		"value":    "bad namespace",
+	}}, decoded.Errors)
+	require.Len(t, decoded.Warnings, 1)
+	assert.Equal(t, "CHECK_DISABLED", decoded.Warnings[0]["code"])
+	assert.Equal(t, redactedValue, decoded.Warnings[0]["value"])
+
+	data, err = json.Marshal(NewValidationResult())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"valid":true,"errors":[],"warnings":[]}`, string(data))
+}
@@ -0,0 +1,100 @@
+package polaris
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// warmUpConfig returns the warm-up config snapshot.
+func (p *PlugPolaris) warmUpConfig() *conf.WarmUp {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.currentConf().GetWarmUp()
+}
+
+// warmUpInitialWeight returns the weight an instance registers with during warm-up.
+func warmUpInitialWeight(cfg *conf.WarmUp) int {
+	if cfg.GetInitialWeight() > 0 {
+		return int(cfg.GetInitialWeight())
+	}
+	return conf.MinWeight
+}
+
+// warmUpStepInterval returns how often the weight is raised during warm-up.
+func warmUpStepInterval(cfg *conf.WarmUp) time.Duration {
+	if cfg.GetStepInterval() == nil || cfg.GetStepInterval().AsDuration() <= 0 {
+		return conf.DefaultWarmUpStepInterval
+	}
+	return max(cfg.GetStepInterval().AsDuration(), conf.MinWarmUpStepInterval)
+}
+
+// warmUpWeight returns the weight to register at now for an instance registered at
+// registeredAt, ramping linearly from the initial weight to base over the warm-up
+// duration. A zero registeredAt means the instance is not registered yet. Once the
+// warm-up has completed, base is returned unchanged.
+func (p *PlugPolaris) warmUpWeight(base int, registeredAt, now time.Time) int {
+	cfg := p.warmUpConfig()
+	if !cfg.GetEnabled() || atomic.LoadInt32(&p.warmedUp) == 1 {
+		return base
+	}
+	duration := cfg.GetDuration().AsDuration()
+	initial := min(warmUpInitialWeight(cfg), base)
+	if duration <= 0 {
+		return base
+	}
+	if registeredAt.IsZero() {
+		return initial
+	}
+	elapsed := now.Sub(registeredAt)
+	if elapsed >= duration {
+		return base
+	}
+	return initial + int(float64(base-initial)*float64(elapsed)/float64(duration))
+}
+
+// startWarmUp raises the registered weight step by step until the warm-up duration has
+// passed since registration. The loop stops early with the plugin lifecycle.
+func (p *PlugPolaris) startWarmUp() {
+	cfg := p.warmUpConfig()
+	if !cfg.GetEnabled() || cfg.GetDuration().AsDuration() <= 0 {
+		return
+	}
+	interval := warmUpStepInterval(cfg)
+	duration := cfg.GetDuration().AsDuration()
+	ctx := p.watcherContext()
+	log.Infof("Warm-up enabled: ramping weight from %d over %v (step: %v)", warmUpInitialWeight(cfg), duration, interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.mu.RLock()
+				registrar := p.registrar
+				p.mu.RUnlock()
+				if registrar == nil {
+					return
+				}
+				registeredAt := registrar.registeredSince()
+				if registeredAt.IsZero() {
+					continue
+				}
+				done := time.Since(registeredAt) >= duration
+				if err := p.applyInstanceWeight(ctx); err != nil {
+					log.Warnf("Failed to raise warm-up instance weight: %v", err)
+					continue
+				}
+				if done {
+					atomic.StoreInt32(&p.warmedUp, 1)
+					log.Infof("Warm-up complete, instance weight is %d", registrar.Weight())
+					return
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,56 @@
+package polaris
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestWarmUpWeight(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Weight: 100})
+	start := time.Now()
+	assert.Equal(t, 100, plugin.warmUpWeight(100, start, start), "disabled")
+
+	plugin.currentConf().WarmUp = &conf.WarmUp{Enabled: true, Duration: durationpb.New(time.Minute), InitialWeight: 10}
+	assert.Equal(t, 10, plugin.warmUpWeight(100, time.Time{}, start), "not registered yet")
+	assert.Equal(t, 10, plugin.warmUpWeight(100, start, start))
+	assert.Equal(t, 55, plugin.warmUpWeight(100, start, start.Add(30*time.Second)))
+	assert.Equal(t, 100, plugin.warmUpWeight(100, start, start.Add(time.Minute)))
+	// The initial weight never exceeds the target weight.
+	assert.Equal(t, 5, plugin.warmUpWeight(5, start, start))
+
+	plugin.warmedUp = 1
+	assert.Equal(t, 100, plugin.warmUpWeight(100, start, start), "completed")
+}
+
+func TestPolarisRegistrar_RegisteredSince(t *testing.T) {
+	reg := NewPolarisRegistrar(&fakeClients{}, "default")
+	assert.True(t, reg.registeredSince().IsZero())
+
+	svc := &registry.ServiceInstance{Name: "svc", Endpoints: []string{"http://10.0.0.1:8080"}}
+	require.NoError(t, reg.Register(context.Background(), svc))
+	assert.False(t, reg.registeredSince().IsZero())
+
+	require.NoError(t, reg.Deregister(context.Background(), svc))
+	assert.True(t, reg.registeredSince().IsZero())
+}
+
+func TestApplyInstanceWeight_WarmUp(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Weight: 200, WarmUp: &conf.WarmUp{Enabled: true, Duration: durationpb.New(time.Hour), InitialWeight: 20}})
+	provider := &fakeClients{}
+	plugin.registrar = NewPolarisRegistrar(provider, "default")
+	require.NoError(t, plugin.registrar.Register(context.Background(), &registry.ServiceInstance{
+		Name: "svc", Endpoints: []string{"http://10.0.0.1:8080"},
+	}))
+
+	require.NoError(t, plugin.applyInstanceWeight(context.Background()))
+	assert.InDelta(t, 20, plugin.GetInstanceWeight(), 1)
+}
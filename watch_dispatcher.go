@@ -0,0 +1,412 @@
+package polaris
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// Watch dispatch module
+// Responsibility: polling the watchers of the plugin, and running their retries, from one
+// run loop and a bounded pool of workers, instead of a goroutine per watcher. A watcher is
+// polled by one worker at a time: while its previous poll, callbacks included, is still
+// running, its next polls are skipped. The run loop never waits for a worker: polls are
+// queued, and a job that holds its worker for watchJobTimeout, e.g. in a blocked callback,
+// is left to finish while a replacement worker takes over, so slow consumers delay only
+// their own watchers.
+
+const (
+	// watchQueueSize bounds the polls and retries waiting for a worker. Polls that do not
+	// fit are dropped until the next tick, retries are postponed.
+	watchQueueSize = 1024
+	// watchJobTimeout is how long a job may hold its worker before a replacement worker is
+	// started
+	watchJobTimeout = watchPollInterval
+	// watchRetryDelay is how long the watch retry loop waits before recreating a watcher
+	watchRetryDelay = 5 * time.Second
+)
+
+// watchDispatcher polls the registered watchers every watchPollInterval until ctx ends
+type watchDispatcher struct {
+	ctx   context.Context
+	clock Clock
+	jobs  chan watchJob // sent to without blocking
+	wake  chan struct{} // makes the run loop look at the scheduled retries
+
+	mu      sync.Mutex
+	entries map[any]*watchEntry
+	workers map[*watchWorker]struct{}
+	retries []*watchRetry
+	closed  bool
+}
+
+// watchEntry is a watcher registered with a dispatcher
+type watchEntry struct {
+	kind    string // watcherTypeService or watcherTypeConfig
+	name    string
+	ctx     context.Context
+	wg      *sync.WaitGroup // counts the polls in flight, waited for by Stop
+	poll    func()
+	metrics *Metrics
+	busy    bool
+}
+
+// watchJob is a poll of entry, or a retry when entry is nil
+type watchJob struct {
+	entry *watchEntry
+	run   func()
+}
+
+// watchWorker is a worker of a dispatcher
+type watchWorker struct {
+	started  time.Time   // start of the job in flight, zero while idle
+	entry    *watchEntry // entry of the job in flight
+	detached bool        // replaced because of a slow job, exits once the job returns
+}
+
+// watchRetry is a retry scheduled on a dispatcher
+type watchRetry struct {
+	ctx   context.Context
+	due   time.Time
+	retry func(canceled bool)
+	stop  func() bool // stops waking the run loop when ctx ends
+}
+
+// newWatchDispatcher starts the run loop and workers of a dispatcher, which exit with ctx.
+func newWatchDispatcher(ctx context.Context, clock Clock, workers int) *watchDispatcher {
+	d := &watchDispatcher{
+		ctx:     ctx,
+		clock:   clockOrSystem(clock),
+		jobs:    make(chan watchJob, watchQueueSize),
+		wake:    make(chan struct{}, 1),
+		entries: make(map[any]*watchEntry),
+		workers: make(map[*watchWorker]struct{}),
+	}
+	d.mu.Lock()
+	for range workers {
+		d.startWorkerLocked()
+	}
+	d.mu.Unlock()
+	go d.run()
+	return d
+}
+
+// watchDispatcher returns the dispatcher of the current lifecycle, or nil before the plugin
+// has started, when watchers run their own loop.
+func (p *PlugPolaris) watchDispatcher() *watchDispatcher {
+	p.mu.RLock()
+	ctx := p.lifecycleCtx
+	workers := int(p.currentConf().GetWatchWorkers())
+	p.mu.RUnlock()
+	if ctx == nil || ctx.Err() != nil {
+		return nil
+	}
+	if workers <= 0 {
+		workers = conf.DefaultWatchWorkers
+	}
+
+	p.dispatcherMutex.Lock()
+	defer p.dispatcherMutex.Unlock()
+	if p.dispatcher == nil || p.dispatcher.ctx != ctx {
+		p.dispatcher = newWatchDispatcher(ctx, p.clock, workers)
+	}
+	return p.dispatcher
+}
+
+// scheduleWatchRetry runs retry after watchRetryDelay on a worker of the watch dispatcher,
+// or on a goroutine of its own before the plugin has started. retry runs exactly once and is
+// told whether ctx ended, or the plugin shut down, first.
+func (p *PlugPolaris) scheduleWatchRetry(ctx context.Context, retry func(canceled bool)) {
+	if d := p.watchDispatcher(); d != nil {
+		d.schedule(ctx, watchRetryDelay, retry)
+		return
+	}
+	go func() {
+		retry(p.waitForRetryDelay(ctx, watchRetryDelay))
+	}()
+}
+
+// add registers a watcher under key, polled from the next tick on.
+func (d *watchDispatcher) add(key any, entry *watchEntry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[key] = entry
+}
+
+// remove unregisters the watcher under key. A poll already in flight completes.
+func (d *watchDispatcher) remove(key any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.entries, key)
+}
+
+// schedule runs retry on a worker once delay has passed, with canceled false, or right
+// away with canceled true when ctx or the dispatcher ends first.
+func (d *watchDispatcher) schedule(ctx context.Context, delay time.Duration, retry func(canceled bool)) {
+	r := &watchRetry{ctx: ctx, due: d.clock.Now().Add(delay), retry: retry}
+	r.stop = context.AfterFunc(ctx, d.wakeup)
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		r.stop()
+		retry(true)
+		return
+	}
+	d.retries = append(d.retries, r)
+	d.mu.Unlock()
+	d.wakeup()
+}
+
+// wakeup makes the run loop look at the scheduled retries
+func (d *watchDispatcher) wakeup() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *watchDispatcher) run() {
+	ticker := d.clock.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var timer Timer
+		var timerC <-chan time.Time
+		if delay, ok := d.nextRetryDelay(); ok {
+			timer = d.clock.NewTimer(delay)
+			timerC = timer.C()
+		}
+		select {
+		case <-d.ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			d.close()
+			return
+		case <-ticker.C():
+			d.replaceSlowWorkers()
+			d.dispatch()
+			d.dispatchRetries()
+		case <-d.wake:
+			d.dispatchRetries()
+		case <-timerC:
+			d.dispatchRetries()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// close releases the scheduled retries and the queued jobs once the workers are gone:
+// the retries are canceled and the polls skip polling.
+func (d *watchDispatcher) close() {
+	d.mu.Lock()
+	d.closed = true
+	retries := d.retries
+	d.retries = nil
+	d.mu.Unlock()
+	for _, r := range retries {
+		r.stop()
+		r.retry(true)
+	}
+	for {
+		select {
+		case job := <-d.jobs:
+			job.run()
+		default:
+			return
+		}
+	}
+}
+
+// startWorkerLocked adds a worker to the pool. d.mu must be held.
+func (d *watchDispatcher) startWorkerLocked() {
+	w := &watchWorker{}
+	d.workers[w] = struct{}{}
+	go d.work(w)
+}
+
+func (d *watchDispatcher) work(w *watchWorker) {
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case job := <-d.jobs:
+			if !d.runJob(w, job) {
+				return
+			}
+		}
+	}
+}
+
+// runJob runs job on w, isolated by a recover, and reports whether w is still part of the
+// pool afterwards.
+func (d *watchDispatcher) runJob(w *watchWorker, job watchJob) bool {
+	d.mu.Lock()
+	w.started, w.entry = d.clock.Now(), job.entry
+	d.mu.Unlock()
+
+	func() {
+		defer func() {
+			r := recover()
+			switch {
+			case r == nil:
+			case job.entry != nil:
+				log.Errorf("polaris %s watcher panic for %s: %v", job.entry.kind, job.entry.name, r)
+			default:
+				log.Errorf("polaris watch retry panic: %v", r)
+			}
+		}()
+		job.run()
+	}()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	w.started, w.entry = time.Time{}, nil
+	if w.detached {
+		delete(d.workers, w)
+		return false
+	}
+	return true
+}
+
+// replaceSlowWorkers detaches the workers whose job has run for watchJobTimeout and starts
+// a replacement for each, so that slow callbacks never take the whole pool.
+func (d *watchDispatcher) replaceSlowWorkers() {
+	now := d.clock.Now()
+	d.mu.Lock()
+	var slow []*watchEntry
+	for w := range d.workers {
+		if w.detached || w.started.IsZero() || now.Sub(w.started) < watchJobTimeout {
+			continue
+		}
+		w.detached = true
+		slow = append(slow, w.entry)
+	}
+	for range slow {
+		d.startWorkerLocked()
+	}
+	d.mu.Unlock()
+
+	for _, entry := range slow {
+		if entry == nil {
+			log.Warnf("Watch retry still running after %v, starting a replacement worker", watchJobTimeout)
+			continue
+		}
+		log.Warnf("Poll of %s watcher %s still running after %v, starting a replacement worker",
+			entry.kind, entry.name, watchJobTimeout)
+		if entry.metrics != nil {
+			entry.metrics.RecordWatcherPollTimeout(entry.kind, entry.name)
+		}
+	}
+}
+
+// dispatch queues a poll of every idle watcher. Polls that do not fit in the queue are
+// dropped until the next tick.
+func (d *watchDispatcher) dispatch() {
+	d.mu.Lock()
+	var dropped []*watchEntry
+	for _, entry := range d.entries {
+		if entry.busy {
+			log.Debugf("Skipping poll of %s watcher %s: previous poll still running", entry.kind, entry.name)
+			if entry.metrics != nil {
+				entry.metrics.RecordWatcherPollSkipped(entry.kind, entry.name)
+			}
+			continue
+		}
+		entry.busy = true
+		entry.wg.Add(1)
+		select {
+		case d.jobs <- watchJob{entry: entry, run: d.pollJob(entry)}:
+		default:
+			entry.busy = false
+			entry.wg.Done()
+			dropped = append(dropped, entry)
+		}
+	}
+	d.mu.Unlock()
+
+	for _, entry := range dropped {
+		log.Warnf("Dropping poll of %s watcher %s: watch queue full", entry.kind, entry.name)
+		if entry.metrics != nil {
+			entry.metrics.RecordWatcherPollDropped(entry.kind, entry.name)
+		}
+	}
+}
+
+// pollJob returns the job polling entry
+func (d *watchDispatcher) pollJob(entry *watchEntry) func() {
+	return func() {
+		defer entry.wg.Done()
+		defer func() {
+			d.mu.Lock()
+			entry.busy = false
+			d.mu.Unlock()
+		}()
+		if d.ctx.Err() != nil || entry.ctx.Err() != nil {
+			return
+		}
+		entry.poll()
+	}
+}
+
+// nextRetryDelay returns the time until the next scheduled retry is due
+func (d *watchDispatcher) nextRetryDelay() (time.Duration, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.retries) == 0 {
+		return 0, false
+	}
+	next := d.retries[0].due
+	for _, r := range d.retries[1:] {
+		if r.due.Before(next) {
+			next = r.due
+		}
+	}
+	return max(next.Sub(d.clock.Now()), 0), true
+}
+
+// dispatchRetries queues the retries that are due and cancels those whose context ended.
+// Due retries that do not fit in the queue are postponed by watchRetryDelay.
+func (d *watchDispatcher) dispatchRetries() {
+	now := d.clock.Now()
+	d.mu.Lock()
+	var canceled []*watchRetry
+	pending := d.retries[:0]
+	for _, r := range d.retries {
+		switch {
+		case r.ctx.Err() != nil:
+			canceled = append(canceled, r)
+		case r.due.After(now):
+			pending = append(pending, r)
+		default:
+			select {
+			case d.jobs <- watchJob{run: d.retryJob(r)}:
+			default:
+				log.Warnf("Postponing watch retry: watch queue full")
+				r.due = now.Add(watchRetryDelay)
+				pending = append(pending, r)
+			}
+		}
+	}
+	clear(d.retries[len(pending):])
+	d.retries = pending
+	d.mu.Unlock()
+
+	for _, r := range canceled {
+		r.stop()
+		r.retry(true)
+	}
+}
+
+// retryJob returns the job running r
+func (d *watchDispatcher) retryJob(r *watchRetry) func() {
+	return func() {
+		r.stop()
+		r.retry(r.ctx.Err() != nil || d.ctx.Err() != nil)
+	}
+}
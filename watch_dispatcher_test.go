@@ -0,0 +1,184 @@
+package polaris
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchDispatcher_SlowWatcherDoesNotStarveOthers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := newManualClock()
+	d := newWatchDispatcher(ctx, clock, 2)
+	clock.waitForTimers(t, 1)
+
+	release := make(chan struct{})
+	var slowPolls, fastPolls atomic.Int32
+	var slowWg, fastWg sync.WaitGroup
+	d.add("slow", &watchEntry{kind: watcherTypeService, name: "slow", ctx: ctx, wg: &slowWg, poll: func() {
+		slowPolls.Add(1)
+		<-release
+	}})
+	d.add("fast", &watchEntry{kind: watcherTypeService, name: "fast", ctx: ctx, wg: &fastWg, poll: func() {
+		fastPolls.Add(1)
+	}})
+
+	for i := range int32(3) {
+		clock.Advance(watchPollInterval)
+		require.Eventually(t, func() bool { return fastPolls.Load() == i+1 }, time.Second, time.Millisecond)
+	}
+	assert.Equal(t, int32(1), slowPolls.Load(), "a watcher is not polled again while its poll runs")
+
+	close(release)
+	d.remove("slow")
+	slowWg.Wait()
+}
+
+func TestWatchDispatcher_ReplacesBlockedWorker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := newManualClock()
+	d := newWatchDispatcher(ctx, clock, 1)
+	clock.waitForTimers(t, 1)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	var fastPolls atomic.Int32
+	var slowWg, fastWg sync.WaitGroup
+	d.add("slow", &watchEntry{kind: watcherTypeService, name: "slow", ctx: ctx, wg: &slowWg, poll: func() {
+		close(started)
+		<-release
+	}})
+	clock.Advance(watchPollInterval)
+	<-started
+
+	d.add("fast", &watchEntry{kind: watcherTypeService, name: "fast", ctx: ctx, wg: &fastWg, poll: func() {
+		fastPolls.Add(1)
+	}})
+	for want := int32(1); want <= 2; want++ {
+		clock.Advance(watchPollInterval)
+		require.Eventually(t, func() bool { return fastPolls.Load() == want }, time.Second, time.Millisecond,
+			"the only worker is blocked, a replacement polls the other watchers")
+	}
+	d.mu.Lock()
+	assert.Len(t, d.workers, 2, "the blocked worker and its replacement")
+	d.mu.Unlock()
+
+	close(release)
+	slowWg.Wait()
+	require.Eventually(t, func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return len(d.workers) == 1
+	}, time.Second, time.Millisecond, "the replaced worker exits once its poll returns")
+}
+
+func TestWatchDispatcher_DropsPollsWhenQueueFull(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	clock := newManualClock()
+	d := newWatchDispatcher(ctx, clock, 0)
+	clock.waitForTimers(t, 1)
+
+	var wg sync.WaitGroup
+	entries := make([]*watchEntry, watchQueueSize+1)
+	for i := range entries {
+		entries[i] = &watchEntry{kind: watcherTypeConfig, name: fmt.Sprint(i), ctx: ctx, wg: &wg, poll: func() {}}
+		d.add(i, entries[i])
+	}
+	clock.Advance(watchPollInterval)
+	require.Eventually(t, func() bool { return len(d.jobs) == watchQueueSize }, time.Second, time.Millisecond)
+	d.mu.Lock()
+	idle := 0
+	for _, entry := range entries {
+		if !entry.busy {
+			idle++
+		}
+	}
+	d.mu.Unlock()
+	assert.Equal(t, 1, idle, "the poll that does not fit is dropped, not waited for")
+
+	cancel()
+	wg.Wait()
+}
+
+func TestWatchDispatcher_ScheduledRetries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := newManualClock()
+	d := newWatchDispatcher(ctx, clock, 1)
+	clock.waitForTimers(t, 1)
+	baseline := runtime.NumGoroutine()
+
+	results := make(chan bool, 2)
+	retryCtx, cancelRetry := context.WithCancel(ctx)
+	d.schedule(ctx, watchRetryDelay, func(canceled bool) { results <- canceled })
+	d.schedule(retryCtx, watchRetryDelay, func(canceled bool) { results <- canceled })
+	assert.LessOrEqual(t, runtime.NumGoroutine(), baseline, "retries wait in the run loop, not in goroutines")
+
+	cancelRetry()
+	assert.True(t, <-results, "a canceled retry is released right away")
+	clock.waitForTimers(t, 2)
+	clock.Advance(watchRetryDelay)
+	assert.False(t, <-results, "a due retry runs on a worker")
+
+	d.schedule(ctx, watchRetryDelay, func(canceled bool) { results <- canceled })
+	cancel()
+	assert.True(t, <-results, "pending retries are released when the dispatcher stops")
+}
+
+func TestWatchDispatcher_PanicIsIsolated(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := newManualClock()
+	d := newWatchDispatcher(ctx, clock, 1)
+	clock.waitForTimers(t, 1)
+
+	var polls atomic.Int32
+	var wg sync.WaitGroup
+	d.add("panics", &watchEntry{kind: watcherTypeConfig, name: "panics", ctx: ctx, wg: &wg, poll: func() {
+		polls.Add(1)
+		panic("callback failed")
+	}})
+	for i := range int32(2) {
+		clock.Advance(watchPollInterval)
+		require.Eventually(t, func() bool { return polls.Load() == i+1 }, time.Second, time.Millisecond)
+	}
+	wg.Wait()
+}
+
+func TestWatchService_SharesDispatcher(t *testing.T) {
+	p := NewPolarisControlPlane(WithConsumerClient(&fakeClients{}))
+	p.setConf(&conf.Polaris{Namespace: "default", WatchWorkers: 2})
+	p.setInitialized()
+	p.mu.Lock()
+	p.ensureLifecycleContextLocked()
+	p.mu.Unlock()
+	baseline := runtime.NumGoroutine()
+
+	var watchers []*ServiceWatcher
+	for i := range 20 {
+		watcher, err := p.WatchService(fmt.Sprintf("svc-%d", i))
+		require.NoError(t, err)
+		watchers = append(watchers, watcher)
+	}
+	for _, watcher := range watchers {
+		assert.Same(t, p.dispatcher, watcher.dispatcher)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), baseline+3, "one run loop and two workers")
+
+	for _, watcher := range watchers {
+		watcher.Stop()
+	}
+	p.mu.Lock()
+	p.lifecycleStop()
+	p.mu.Unlock()
+	assertNoLeakedGoroutines(t, baseline)
+}
@@ -0,0 +1,97 @@
+package polaris
+
+import (
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// registrationWatchdogInterval returns the watchdog interval with defaults and bounds applied.
+func registrationWatchdogInterval(cfg *conf.RegistrationWatchdog) time.Duration {
+	if cfg.GetInterval() == nil || cfg.GetInterval().AsDuration() <= 0 {
+		return conf.DefaultRegistrationWatchdogInterval
+	}
+	return max(cfg.GetInterval().AsDuration(), conf.MinRegistrationWatchdogInterval)
+}
+
+// registryLookup returns an instance lookup through the SDK that includes unhealthy and
+// isolated instances, so only instances missing from the registry are reported missing.
+func registryLookup(consumer ConsumerClient, namespace string) instanceLookup {
+	return func(service string) ([]model.Instance, error) {
+		resp, err := consumer.GetInstances(&api.GetInstancesRequest{
+			GetInstancesRequest: model.GetInstancesRequest{
+				Service:         service,
+				Namespace:       namespace,
+				SkipRouteFilter: true,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Instances, nil
+	}
+}
+
+// checkRegistrations re-registers the plugin registrar's instances that the registry no
+// longer knows.
+func (p *PlugPolaris) checkRegistrations(lookup instanceLookup) {
+	p.mu.RLock()
+	registrar := p.registrar
+	metrics := p.metrics
+	namespace := p.currentConf().GetNamespace()
+	p.mu.RUnlock()
+	if registrar == nil || !registrar.hasInstances() {
+		return
+	}
+
+	reregistered, err := registrar.reregisterMissing(lookup)
+	if err != nil {
+		log.Warnf("Registration watchdog check incomplete: %v", err)
+	}
+	if len(reregistered) > 0 {
+		log.Infof("Registration watchdog re-registered %d instances", len(reregistered))
+	}
+	if metrics != nil {
+		for _, instance := range reregistered {
+			metrics.RecordServiceRegistration(instance.Name, namespace, "reregistered")
+		}
+	}
+}
+
+// startRegistrationWatchdog starts checking that registered instances still exist when the
+// watchdog is enabled. Each check looks up instances through the current SDK context, so it
+// follows SDK rebuilds. The loop stops with the plugin lifecycle.
+func (p *PlugPolaris) startRegistrationWatchdog() {
+	p.mu.RLock()
+	cfg := p.currentConf().GetRegistrationWatchdog()
+	consumer := p.consumerLocked()
+	p.mu.RUnlock()
+	if !cfg.GetEnabled() || consumer == nil {
+		return
+	}
+	interval := registrationWatchdogInterval(cfg)
+	ctx := p.watcherContext()
+	log.Infof("Starting registration watchdog (interval: %v)", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.mu.RLock()
+				consumer := p.consumerLocked()
+				namespace := p.currentConf().GetNamespace()
+				p.mu.RUnlock()
+				if consumer == nil {
+					continue
+				}
+				p.checkRegistrations(registryLookup(consumer, namespace))
+			}
+		}
+	}()
+}
@@ -0,0 +1,63 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestRegistrationWatchdogInterval(t *testing.T) {
+	assert.Equal(t, conf.DefaultRegistrationWatchdogInterval, registrationWatchdogInterval(nil))
+	assert.Equal(t, conf.MinRegistrationWatchdogInterval, registrationWatchdogInterval(&conf.RegistrationWatchdog{Interval: durationpb.New(time.Second)}))
+	assert.Equal(t, time.Minute, registrationWatchdogInterval(&conf.RegistrationWatchdog{Interval: durationpb.New(time.Minute)}))
+}
+
+func TestPolarisRegistrar_ReregisterMissing(t *testing.T) {
+	provider := &fakeClients{}
+	reg := NewPolarisRegistrar(provider, "default")
+	svc := &registry.ServiceInstance{Name: "svc", Endpoints: []string{"http://10.0.0.1:8080", "grpc://10.0.0.1:9090"}}
+	require.NoError(t, reg.Register(context.Background(), svc))
+	require.NoError(t, reg.SetEndpointHealthy(context.Background(), svc, "grpc://10.0.0.1:9090", false))
+	provider.registered = nil
+
+	// Only the HTTP endpoint survived the outage.
+	known := []model.Instance{NewStaticInstance("default", "svc", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})}
+	reregistered, err := reg.reregisterMissing(func(string) ([]model.Instance, error) { return known, nil })
+	require.NoError(t, err)
+	require.Len(t, reregistered, 1)
+	require.Len(t, provider.registered, 1)
+	assert.Equal(t, 9090, provider.registered[0].Port)
+	assert.False(t, *provider.registered[0].Healthy, "keeps the last reported health")
+
+	// An unreachable registry re-registers nothing.
+	provider.registered = nil
+	reregistered, err = reg.reregisterMissing(func(string) ([]model.Instance, error) { return nil, errors.New("unreachable") })
+	assert.Error(t, err)
+	assert.Empty(t, reregistered)
+	assert.Empty(t, provider.registered)
+}
+
+func TestCheckRegistrations(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	provider := &fakeClients{}
+	plugin.registrar = NewPolarisRegistrar(provider, "default")
+	plugin.checkRegistrations(func(string) ([]model.Instance, error) { return nil, nil })
+	assert.Empty(t, provider.registered, "nothing registered yet")
+
+	require.NoError(t, plugin.registrar.Register(context.Background(), &registry.ServiceInstance{
+		Name: "svc", Endpoints: []string{"http://10.0.0.1:8080"},
+	}))
+	provider.registered = nil
+	plugin.checkRegistrations(func(string) ([]model.Instance, error) { return nil, nil })
+	require.Len(t, provider.registered, 1)
+	assert.Equal(t, "svc", provider.registered[0].Service)
+}
@@ -16,10 +16,19 @@ import (
 // - watchers.go: underlying monitoring capabilities, directly interacts with Polaris SDK
 // - registry_impl.go: Kratos framework adaptation, implements registry interface
 
+// Watcher types labeling the watcher metrics
+const (
+	watcherTypeService = "service"
+	watcherTypeConfig  = "config"
+)
+
+// watchPollInterval is how often watchers poll Polaris for changes
+const watchPollInterval = 10 * time.Second
+
 // ServiceWatcher service watcher
 // Monitors service instance changes
 type ServiceWatcher struct {
-	consumer    api.ConsumerAPI
+	consumer    ConsumerClient
 	serviceName string
 	namespace   string
 
@@ -36,18 +45,28 @@ type ServiceWatcher struct {
 	// State
 	isRunning     bool
 	lastInstances []model.Instance
+	lastEvent     time.Time
+
+	// partition restricts the watched instances; nil watches the full service
+	partition *watchPartition
+
+	// clock drives the polling and stamps the events
+	clock Clock
+
+	// dispatcher polls the watcher from the shared run loop of the plugin; nil runs its own
+	dispatcher *watchDispatcher
 
 	// Monitoring metrics
 	metrics *Metrics
 }
 
 // NewServiceWatcher creates new service watcher
-func NewServiceWatcher(consumer api.ConsumerAPI, serviceName, namespace string) *ServiceWatcher {
+func NewServiceWatcher(consumer ConsumerClient, serviceName, namespace string) *ServiceWatcher {
 	return NewServiceWatcherWithContext(context.Background(), consumer, serviceName, namespace)
 }
 
 // NewServiceWatcherWithContext creates a service watcher bound to a parent lifecycle context.
-func NewServiceWatcherWithContext(parent context.Context, consumer api.ConsumerAPI, serviceName, namespace string) *ServiceWatcher {
+func NewServiceWatcherWithContext(parent context.Context, consumer ConsumerClient, serviceName, namespace string) *ServiceWatcher {
 	if parent == nil {
 		parent = context.Background()
 	}
@@ -58,6 +77,7 @@ func NewServiceWatcherWithContext(parent context.Context, consumer api.ConsumerA
 		namespace:   namespace,
 		ctx:         ctx,
 		cancel:      cancel,
+		clock:       SystemClock,
 		metrics:     nil, // Will be set when used
 	}
 }
@@ -76,6 +96,22 @@ func (sw *ServiceWatcher) SetOnError(callback func(error)) {
 	sw.onError = callback
 }
 
+// SetClock makes the watcher poll and stamp its events with clock instead of SystemClock.
+// It must be called before Start.
+func (sw *ServiceWatcher) SetClock(clock Clock) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	sw.clock = clockOrSystem(clock)
+}
+
+// setDispatcher makes the watcher polled by dispatcher instead of its own loop. It must be
+// called before Start.
+func (sw *ServiceWatcher) setDispatcher(dispatcher *watchDispatcher) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	sw.dispatcher = dispatcher
+}
+
 // Start starts monitoring
 func (sw *ServiceWatcher) Start() {
 	sw.mu.Lock()
@@ -91,6 +127,18 @@ func (sw *ServiceWatcher) Start() {
 	}
 
 	sw.isRunning = true
+	if sw.dispatcher != nil {
+		sw.dispatcher.add(sw, &watchEntry{
+			kind:    watcherTypeService,
+			name:    sw.serviceName,
+			ctx:     sw.ctx,
+			wg:      &sw.wg,
+			poll:    sw.checkInstances,
+			metrics: sw.metrics,
+		})
+		log.Infof("Started watching service: %s in namespace: %s", sw.serviceName, sw.namespace)
+		return
+	}
 	sw.wg.Add(1) // Increment WaitGroup count
 	go func() {
 		defer sw.wg.Done()
@@ -120,9 +168,13 @@ func (sw *ServiceWatcher) Stop() {
 
 	sw.cancel()
 	sw.isRunning = false
+	dispatcher := sw.dispatcher
 	sw.mu.Unlock()
+	if dispatcher != nil {
+		dispatcher.remove(sw)
+	}
 
-	// Wait for goroutine to completely exit
+	// Wait for goroutine, or the poll in flight, to completely exit
 	sw.wg.Wait()
 
 	log.Infof("Stopped watching service: %s", sw.serviceName)
@@ -130,7 +182,7 @@ func (sw *ServiceWatcher) Stop() {
 
 // watchLoop monitoring loop
 func (sw *ServiceWatcher) watchLoop() {
-	ticker := time.NewTicker(10 * time.Second) // Check every 10 seconds
+	ticker := sw.clock.NewTicker(watchPollInterval)
 	defer ticker.Stop()
 
 	for {
@@ -138,7 +190,7 @@ func (sw *ServiceWatcher) watchLoop() {
 		case <-sw.ctx.Done():
 			log.Infof("Watch loop for service %s stopped due to context cancellation", sw.serviceName)
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			sw.checkInstances()
 		}
 	}
@@ -155,23 +207,54 @@ func (sw *ServiceWatcher) checkInstances() {
 			Namespace: sw.namespace,
 		},
 	}
+	if sw.partition != nil {
+		req.Metadata = sw.partition.metadata
+	}
 
 	resp, err := sw.consumer.GetInstances(req)
 	if err != nil {
 		log.Errorf("Failed to get instances for service %s: %v", sw.serviceName, err)
-		sw.notifyError(err)
+		sw.notifyError(TranslateSDKError(err))
 		return
 	}
+	sw.markEvent()
+	instances := sw.partition.filter(resp.Instances)
 
 	// Check if instances have changed
-	if sw.updateInstances(resp.Instances) {
-		sw.notifyInstancesChanged(resp.Instances)
+	if sw.updateInstances(instances) {
+		sw.notifyInstancesChanged(instances)
 
 		log.Infof("Service %s instances changed: %d instances",
-			sw.serviceName, len(resp.Instances))
+			sw.serviceName, len(instances))
 	}
 }
 
+// setPartition restricts the watcher to a partition of the service. It must be called
+// before Start.
+func (sw *ServiceWatcher) setPartition(partition *watchPartition) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	sw.partition = partition
+}
+
+// FullInstances fetches every instance of the watched service, ignoring the watch
+// partition. Use it when a partitioned watcher needs the global view on demand.
+func (sw *ServiceWatcher) FullInstances() ([]model.Instance, error) {
+	if sw.consumer == nil {
+		return nil, NewInitError("consumer API is not available")
+	}
+	resp, err := sw.consumer.GetInstances(&api.GetInstancesRequest{
+		GetInstancesRequest: model.GetInstancesRequest{
+			Service:   sw.serviceName,
+			Namespace: sw.namespace,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Instances, nil
+}
+
 // hasInstancesChanged checks if instances have changed
 func (sw *ServiceWatcher) hasInstancesChangedLocked(newInstances []model.Instance) bool {
 	// If instance count is different, consider it changed
@@ -252,10 +335,34 @@ func (sw *ServiceWatcher) notifyInstancesChanged(instances []model.Instance) {
 	sw.mu.RUnlock()
 
 	if callback != nil {
+		start := time.Now()
 		callback(append([]model.Instance(nil), instances...))
+		if sw.metrics != nil {
+			sw.metrics.RecordWatcherCallbackDuration(watcherTypeService, sw.serviceName, time.Since(start).Seconds())
+		}
+	}
+}
+
+// markEvent records that the watch loop received an answer from Polaris
+func (sw *ServiceWatcher) markEvent() {
+	now := sw.clock.Now()
+	sw.mu.Lock()
+	sw.lastEvent = now
+	sw.mu.Unlock()
+	if sw.metrics != nil {
+		sw.metrics.RecordWatcherEvent(watcherTypeService, sw.serviceName, now)
 	}
 }
 
+// LastEventTime returns when the watcher last received an answer from Polaris, or the
+// zero time if it never did. A watcher whose last event is older than a few polling
+// intervals has stopped working.
+func (sw *ServiceWatcher) LastEventTime() time.Time {
+	sw.mu.RLock()
+	defer sw.mu.RUnlock()
+	return sw.lastEvent
+}
+
 // notifyError notifies error
 func (sw *ServiceWatcher) notifyError(err error) {
 	sw.mu.RLock()
@@ -281,10 +388,16 @@ func (sw *ServiceWatcher) IsRunning() bool {
 	return sw.isRunning
 }
 
+// Refresh checks the instances of the service now instead of waiting for the next poll.
+// Changes are reported to the callbacks as usual.
+func (sw *ServiceWatcher) Refresh() {
+	sw.checkInstances()
+}
+
 // ConfigWatcher configuration watcher
 // Monitors configuration changes
 type ConfigWatcher struct {
-	configAPI api.ConfigFileAPI
+	configAPI ConfigClient
 	fileName  string
 	group     string
 	namespace string
@@ -297,23 +410,40 @@ type ConfigWatcher struct {
 
 	// Callback functions
 	onConfigChanged func(config model.ConfigFile)
+	onConfigChange  func(change ConfigChange)
 	onError         func(error)
 
 	// State
 	isRunning  bool
 	lastConfig model.ConfigFile
+	lastEvent  time.Time
+
+	// clock drives the polling and stamps the events
+	clock Clock
+
+	// dispatcher polls the watcher from the shared run loop of the plugin; nil runs its own
+	dispatcher *watchDispatcher
+
+	// Change debouncing: callbacks run once changes have been quiet for debounce
+	debounce        time.Duration
+	debounceMaxWait time.Duration
+	debounceTimer   *time.Timer
+	pending         int
+	pendingSince    time.Time
+	pendingPrevious model.ConfigFile
+	pendingConfig   model.ConfigFile
 
 	// Monitoring metrics
 	metrics *Metrics
 }
 
 // NewConfigWatcher creates new configuration watcher
-func NewConfigWatcher(configAPI api.ConfigFileAPI, fileName, group, namespace string) *ConfigWatcher {
+func NewConfigWatcher(configAPI ConfigClient, fileName, group, namespace string) *ConfigWatcher {
 	return NewConfigWatcherWithContext(context.Background(), configAPI, fileName, group, namespace)
 }
 
 // NewConfigWatcherWithContext creates a config watcher bound to a parent lifecycle context.
-func NewConfigWatcherWithContext(parent context.Context, configAPI api.ConfigFileAPI, fileName, group, namespace string) *ConfigWatcher {
+func NewConfigWatcherWithContext(parent context.Context, configAPI ConfigClient, fileName, group, namespace string) *ConfigWatcher {
 	if parent == nil {
 		parent = context.Background()
 	}
@@ -325,6 +455,7 @@ func NewConfigWatcherWithContext(parent context.Context, configAPI api.ConfigFil
 		namespace: namespace,
 		ctx:       ctx,
 		cancel:    cancel,
+		clock:     SystemClock,
 		metrics:   nil, // Will be set when used
 	}
 }
@@ -336,6 +467,14 @@ func (cw *ConfigWatcher) SetOnConfigChanged(callback func(config model.ConfigFil
 	cw.onConfigChanged = callback
 }
 
+// SetOnConfigChange sets a callback that receives the previous content and the
+// line diff along with the new content. It runs alongside SetOnConfigChanged.
+func (cw *ConfigWatcher) SetOnConfigChange(callback func(change ConfigChange)) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	cw.onConfigChange = callback
+}
+
 // SetOnError sets error callback
 func (cw *ConfigWatcher) SetOnError(callback func(error)) {
 	cw.mu.Lock()
@@ -343,6 +482,22 @@ func (cw *ConfigWatcher) SetOnError(callback func(error)) {
 	cw.onError = callback
 }
 
+// SetClock makes the watcher poll and stamp its events with clock instead of SystemClock.
+// It must be called before Start.
+func (cw *ConfigWatcher) SetClock(clock Clock) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	cw.clock = clockOrSystem(clock)
+}
+
+// setDispatcher makes the watcher polled by dispatcher instead of its own loop. It must be
+// called before Start.
+func (cw *ConfigWatcher) setDispatcher(dispatcher *watchDispatcher) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	cw.dispatcher = dispatcher
+}
+
 // Start starts monitoring
 func (cw *ConfigWatcher) Start() {
 	cw.mu.Lock()
@@ -358,6 +513,18 @@ func (cw *ConfigWatcher) Start() {
 	}
 
 	cw.isRunning = true
+	if cw.dispatcher != nil {
+		cw.dispatcher.add(cw, &watchEntry{
+			kind:    watcherTypeConfig,
+			name:    configWatcherName(cw.fileName, cw.group),
+			ctx:     cw.ctx,
+			wg:      &cw.wg,
+			poll:    cw.checkConfig,
+			metrics: cw.metrics,
+		})
+		log.Infof("Started watching config: %s:%s in namespace: %s", cw.fileName, cw.group, cw.namespace)
+		return
+	}
 	cw.wg.Add(1) // Increment WaitGroup count
 	go func() {
 		defer cw.wg.Done()
@@ -387,9 +554,14 @@ func (cw *ConfigWatcher) Stop() {
 
 	cw.cancel()
 	cw.isRunning = false
+	cw.clearPendingLocked()
+	dispatcher := cw.dispatcher
 	cw.mu.Unlock()
+	if dispatcher != nil {
+		dispatcher.remove(cw)
+	}
 
-	// Wait for goroutine to completely exit
+	// Wait for goroutine, or the poll in flight, to completely exit
 	cw.wg.Wait()
 
 	log.Infof("Stopped watching config: %s:%s", cw.fileName, cw.group)
@@ -397,7 +569,7 @@ func (cw *ConfigWatcher) Stop() {
 
 // watchLoop monitoring loop
 func (cw *ConfigWatcher) watchLoop() {
-	ticker := time.NewTicker(10 * time.Second) // Check every 10 seconds
+	ticker := cw.clock.NewTicker(watchPollInterval)
 	defer ticker.Stop()
 
 	for {
@@ -405,7 +577,7 @@ func (cw *ConfigWatcher) watchLoop() {
 		case <-cw.ctx.Done():
 			log.Infof("Watch loop for config %s:%s stopped due to context cancellation", cw.fileName, cw.group)
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			cw.checkConfig()
 		}
 	}
@@ -432,13 +604,15 @@ func (cw *ConfigWatcher) checkConfig() {
 		if cw.metrics != nil {
 			cw.metrics.RecordConfigOperation("check", cw.fileName, cw.group, "error")
 		}
-		cw.notifyError(err)
+		cw.notifyError(TranslateSDKError(err))
 		return
 	}
 
+	cw.markEvent()
+
 	// Check if configuration has changed
-	if cw.updateConfig(config) {
-		cw.notifyConfigChanged(config)
+	if previous, changed := cw.updateConfig(config); changed {
+		cw.scheduleConfigChanged(previous, config)
 
 		log.Infof("Config %s:%s changed",
 			cw.group, cw.fileName)
@@ -490,18 +664,21 @@ func (cw *ConfigWatcher) hasConfigChangedLocked(newConfig model.ConfigFile) bool
 	return false
 }
 
-func (cw *ConfigWatcher) updateConfig(newConfig model.ConfigFile) bool {
+// updateConfig stores newConfig if it differs from the last one and returns the
+// config it replaced.
+func (cw *ConfigWatcher) updateConfig(newConfig model.ConfigFile) (model.ConfigFile, bool) {
 	cw.mu.Lock()
 	defer cw.mu.Unlock()
 	if !cw.hasConfigChangedLocked(newConfig) {
-		return false
+		return nil, false
 	}
+	previous := cw.lastConfig
 	cw.lastConfig = newConfig
-	return true
+	return previous, true
 }
 
 // notifyConfigChanged notifies configuration changes
-func (cw *ConfigWatcher) notifyConfigChanged(config model.ConfigFile) {
+func (cw *ConfigWatcher) notifyConfigChanged(previous, config model.ConfigFile) {
 	// Record configuration change metrics
 	if cw.metrics != nil {
 		cw.metrics.RecordConfigChange(cw.fileName, cw.group)
@@ -509,11 +686,31 @@ func (cw *ConfigWatcher) notifyConfigChanged(config model.ConfigFile) {
 
 	cw.mu.RLock()
 	callback := cw.onConfigChanged
+	changeCallback := cw.onConfigChange
 	cw.mu.RUnlock()
 
+	if callback == nil && changeCallback == nil {
+		return
+	}
+	start := time.Now()
+	if cw.metrics != nil {
+		defer func() {
+			cw.metrics.RecordWatcherCallbackDuration(watcherTypeConfig, configWatcherName(cw.fileName, cw.group), time.Since(start).Seconds())
+		}()
+	}
 	if callback != nil {
 		callback(config)
 	}
+	if changeCallback != nil {
+		oldContent, newContent := "", ""
+		if previous != nil {
+			oldContent = previous.GetContent()
+		}
+		if config != nil {
+			newContent = config.GetContent()
+		}
+		changeCallback(newConfigChange(cw.fileName, cw.group, cw.namespace, oldContent, newContent))
+	}
 }
 
 // notifyError notifies error
@@ -527,6 +724,30 @@ func (cw *ConfigWatcher) notifyError(err error) {
 	}
 }
 
+// markEvent records that the watch loop received an answer from Polaris
+func (cw *ConfigWatcher) markEvent() {
+	now := cw.clock.Now()
+	cw.mu.Lock()
+	cw.lastEvent = now
+	cw.mu.Unlock()
+	if cw.metrics != nil {
+		cw.metrics.RecordWatcherEvent(watcherTypeConfig, configWatcherName(cw.fileName, cw.group), now)
+	}
+}
+
+// LastEventTime returns when the watcher last received an answer from Polaris, or the
+// zero time if it never did
+func (cw *ConfigWatcher) LastEventTime() time.Time {
+	cw.mu.RLock()
+	defer cw.mu.RUnlock()
+	return cw.lastEvent
+}
+
+// configWatcherName returns the name of the watcher of a config file in watcher metrics
+func configWatcherName(fileName, group string) string {
+	return fileName + ":" + group
+}
+
 // GetLastConfig gets the last configuration
 func (cw *ConfigWatcher) GetLastConfig() model.ConfigFile {
 	cw.mu.RLock()
@@ -541,6 +762,12 @@ func (cw *ConfigWatcher) IsRunning() bool {
 	return cw.isRunning
 }
 
+// Refresh checks the config file now instead of waiting for the next poll. Changes are
+// reported to the callbacks as usual.
+func (cw *ConfigWatcher) Refresh() {
+	cw.checkConfig()
+}
+
 // compareInstance compares if two instances are the same
 func (sw *ServiceWatcher) compareInstance(instance1, instance2 model.Instance) bool {
 	if instance1 == nil || instance2 == nil {
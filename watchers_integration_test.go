@@ -3,6 +3,8 @@ package polaris
 import (
 	"testing"
 
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -58,6 +60,42 @@ func TestWatchersWithMetrics(t *testing.T) {
 	assert.NotNil(t, configWatcher.metrics)
 }
 
+// TestWatcherMetrics tests the last event, callback duration and retry metrics of watchers
+func TestWatcherMetrics(t *testing.T) {
+	meter := newRecordingMeter()
+	metrics := NewMetrics(NewOTelSink(meter))
+
+	instance := NewStaticInstance("default", "orders", &conf.FallbackInstance{Host: "10.0.0.1", Port: 8080})
+	serviceWatcher := NewServiceWatcher(&fakeClients{instances: []model.Instance{instance}}, "orders", "default")
+	serviceWatcher.metrics = metrics
+	serviceWatcher.SetOnInstancesChanged(func([]model.Instance) {})
+	assert.True(t, serviceWatcher.LastEventTime().IsZero())
+	serviceWatcher.checkInstances()
+	assert.False(t, serviceWatcher.LastEventTime().IsZero())
+
+	configWatcher := NewConfigWatcher(&fakeClients{config: "workers: 4\n"}, "app.yaml", "orders", "default")
+	configWatcher.metrics = metrics
+	configWatcher.SetOnConfigChanged(func(model.ConfigFile) {})
+	configWatcher.checkConfig()
+	configWatcher.checkConfig()
+	assert.False(t, configWatcher.LastEventTime().IsZero())
+
+	meter.collect()
+	assert.Equal(t, float64(serviceWatcher.LastEventTime().Unix()),
+		meter.value("lynx.polaris.watcher_last_event_timestamp_seconds{name=orders,type=service}"))
+	assert.Equal(t, float64(configWatcher.LastEventTime().Unix()),
+		meter.value("lynx.polaris.watcher_last_event_timestamp_seconds{name=app.yaml:orders,type=config}"))
+	assert.Equal(t, 1.0, meter.value("lynx.polaris.watcher_callback_duration_seconds_count{name=orders,type=service}"))
+	assert.Equal(t, 1.0, meter.value("lynx.polaris.watcher_callback_duration_seconds_count{name=app.yaml:orders,type=config}"),
+		"unchanged config does not run the callbacks")
+
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Namespace: "default"})
+	plugin.metrics = metrics
+	plugin.recordWatcherRestart(watcherTypeService, "orders")
+	assert.Equal(t, 1.0, meter.value("lynx.polaris.watcher_restarts_total{name=orders,type=service}"))
+}
+
 // TestWatchersLifecycle tests watcher lifecycle
 func TestWatchersLifecycle(t *testing.T) {
 	t.Skip("Skipping lifecycle test to avoid log initialization issues")
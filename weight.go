@@ -0,0 +1,173 @@
+package polaris
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// LoadSource reports the current host utilization in [0, 1], where 1 means fully loaded.
+type LoadSource func() (float64, error)
+
+// SetInstanceWeight sets the weight of the instances registered through the plugin's
+// registrar and re-registers them with it. With auto weighting enabled, weight is the
+// base weight that load-based adjustment scales down from.
+func (p *PlugPolaris) SetInstanceWeight(weight int) error {
+	if weight < conf.MinWeight || weight > conf.MaxWeight {
+		return NewConfigError(fmt.Sprintf("weight must be between %d and %d", conf.MinWeight, conf.MaxWeight))
+	}
+	p.mu.Lock()
+	p.baseWeight = weight
+	p.mu.Unlock()
+	return p.applyInstanceWeight(context.Background())
+}
+
+// GetInstanceWeight returns the weight currently registered for the plugin's instances.
+func (p *PlugPolaris) GetInstanceWeight() int {
+	p.mu.RLock()
+	registrar := p.registrar
+	p.mu.RUnlock()
+	if registrar == nil {
+		return p.instanceBaseWeight()
+	}
+	return registrar.Weight()
+}
+
+// SetLoadSource replaces the load source used by auto weighting. A nil source restores
+// the default, which reads the 1-minute load average normalized by the CPU count.
+func (p *PlugPolaris) SetLoadSource(source LoadSource) {
+	p.mu.Lock()
+	p.loadSource = source
+	p.mu.Unlock()
+}
+
+// instanceBaseWeight returns the weight set through SetInstanceWeight, or the configured
+// weight when none was set.
+func (p *PlugPolaris) instanceBaseWeight() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.baseWeight > 0 {
+		return p.baseWeight
+	}
+	if p.currentConf() != nil && p.currentConf().Weight > 0 {
+		return int(p.currentConf().Weight)
+	}
+	return conf.DefaultWeight
+}
+
+// applyInstanceWeight registers the base weight, ramped during warm-up and scaled by the
+// current load when auto weighting is enabled.
+func (p *PlugPolaris) applyInstanceWeight(ctx context.Context) error {
+	p.mu.RLock()
+	registrar := p.registrar
+	p.mu.RUnlock()
+	if registrar == nil {
+		return NewInitError("Polaris registrar is not available")
+	}
+
+	weight := p.warmUpWeight(p.instanceBaseWeight(), registrar.registeredSince(), time.Now())
+	if cfg := p.autoWeightConfig(); cfg.GetEnabled() {
+		utilization, err := p.currentLoad()
+		if err != nil {
+			log.Warnf("Failed to read host load, keeping base weight %d: %v", weight, err)
+		} else {
+			weight = loadAdjustedWeight(weight, utilization, autoWeightMin(cfg))
+		}
+	}
+	return registrar.SetWeight(WithToken(ctx, p.operationToken(TokenOperationWeight)), weight)
+}
+
+// autoWeightConfig returns the auto weighting config snapshot.
+func (p *PlugPolaris) autoWeightConfig() *conf.AutoWeight {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.currentConf().GetAutoWeight()
+}
+
+// autoWeightInterval returns the load sampling interval with defaults and bounds applied.
+func autoWeightInterval(cfg *conf.AutoWeight) time.Duration {
+	if cfg.GetInterval() == nil || cfg.GetInterval().AsDuration() <= 0 {
+		return conf.DefaultAutoWeightInterval
+	}
+	return max(cfg.GetInterval().AsDuration(), conf.MinAutoWeightInterval)
+}
+
+// autoWeightMin returns the weight floor applied under full load.
+func autoWeightMin(cfg *conf.AutoWeight) int {
+	if cfg.GetMinWeight() > 0 {
+		return int(cfg.GetMinWeight())
+	}
+	return conf.MinWeight
+}
+
+// loadAdjustedWeight scales base by the remaining headroom, never going below minWeight
+// or above base.
+func loadAdjustedWeight(base int, utilization float64, minWeight int) int {
+	utilization = math.Min(math.Max(utilization, 0), 1)
+	weight := int(math.Round(float64(base) * (1 - utilization)))
+	minWeight = min(minWeight, base)
+	return max(weight, minWeight)
+}
+
+// currentLoad reads the host utilization from the configured load source.
+func (p *PlugPolaris) currentLoad() (float64, error) {
+	p.mu.RLock()
+	source := p.loadSource
+	p.mu.RUnlock()
+	if source == nil {
+		source = systemLoad
+	}
+	return source()
+}
+
+// systemLoad returns the 1-minute load average divided by the number of CPUs. It is only
+// available where /proc/loadavg exists.
+func systemLoad() (float64, error) {
+	data, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unexpected /proc/loadavg content %q", data)
+	}
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, err
+	}
+	return load / float64(runtime.NumCPU()), nil
+}
+
+// startAutoWeight starts the load-based weighting loop when it is enabled. The loop stops
+// with the plugin lifecycle.
+func (p *PlugPolaris) startAutoWeight() {
+	cfg := p.autoWeightConfig()
+	if !cfg.GetEnabled() {
+		return
+	}
+	interval := autoWeightInterval(cfg)
+	ctx := p.watcherContext()
+	log.Infof("Starting load-based instance weighting (interval: %v, min weight: %d)", interval, autoWeightMin(cfg))
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := p.applyInstanceWeight(ctx); err != nil {
+					log.Warnf("Failed to apply load-based instance weight: %v", err)
+				}
+			}
+		}
+	}()
+}
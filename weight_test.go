@@ -0,0 +1,86 @@
+package polaris
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx-polaris/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAdjustedWeight(t *testing.T) {
+	assert.Equal(t, 100, loadAdjustedWeight(100, 0, 1))
+	assert.Equal(t, 50, loadAdjustedWeight(100, 0.5, 1))
+	assert.Equal(t, 10, loadAdjustedWeight(100, 1, 10))
+	assert.Equal(t, 10, loadAdjustedWeight(100, 3.5, 10))
+	assert.Equal(t, 100, loadAdjustedWeight(100, -1, 10))
+	// The floor never exceeds the base weight.
+	assert.Equal(t, 5, loadAdjustedWeight(5, 1, 10))
+}
+
+func TestPolarisRegistrar_SetWeight(t *testing.T) {
+	provider := &fakeClients{}
+	reg := NewPolarisRegistrar(provider, "default")
+	assert.Equal(t, conf.DefaultWeight, reg.Weight())
+
+	svc := &registry.ServiceInstance{Name: "svc", Endpoints: []string{"http://10.0.0.1:8080", "grpc://10.0.0.1:9090"}}
+	require.NoError(t, reg.Register(context.Background(), svc))
+	require.NoError(t, reg.SetEndpointHealthy(context.Background(), svc, "grpc://10.0.0.1:9090", false))
+	provider.registered = nil
+
+	require.NoError(t, reg.SetWeight(context.Background(), 250))
+	assert.Equal(t, 250, reg.Weight())
+	require.Len(t, provider.registered, 2)
+	for _, req := range provider.registered {
+		assert.Equal(t, 250, *req.Weight)
+		// Each endpoint keeps its last reported health.
+		assert.Equal(t, req.Port == 8080, *req.Healthy)
+	}
+
+	// Unchanged weight does not re-register.
+	provider.registered = nil
+	require.NoError(t, reg.SetWeight(context.Background(), 250))
+	assert.Empty(t, provider.registered)
+
+	assert.Error(t, reg.SetWeight(context.Background(), conf.MaxWeight+1))
+}
+
+func TestSetInstanceWeight(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Weight: 100})
+	assert.Error(t, plugin.SetInstanceWeight(0))
+	assert.Error(t, plugin.SetInstanceWeight(300), "no registrar yet")
+
+	provider := &fakeClients{}
+	plugin.registrar = NewPolarisRegistrar(provider, "default")
+	require.NoError(t, plugin.registrar.Register(context.Background(), &registry.ServiceInstance{
+		Name: "svc", Endpoints: []string{"http://10.0.0.1:8080"},
+	}))
+
+	require.NoError(t, plugin.SetInstanceWeight(300))
+	assert.Equal(t, 300, plugin.GetInstanceWeight())
+	assert.Equal(t, 300, *provider.registered[len(provider.registered)-1].Weight)
+}
+
+func TestApplyInstanceWeight_AutoWeight(t *testing.T) {
+	plugin := NewPolarisControlPlane()
+	plugin.setConf(&conf.Polaris{Weight: 200, AutoWeight: &conf.AutoWeight{Enabled: true, MinWeight: 20}})
+	provider := &fakeClients{}
+	plugin.registrar = NewPolarisRegistrar(provider, "default")
+
+	plugin.SetLoadSource(func() (float64, error) { return 0.75, nil })
+	require.NoError(t, plugin.applyInstanceWeight(context.Background()))
+	assert.Equal(t, 50, plugin.GetInstanceWeight())
+
+	plugin.SetLoadSource(func() (float64, error) { return 1, nil })
+	require.NoError(t, plugin.applyInstanceWeight(context.Background()))
+	assert.Equal(t, 20, plugin.GetInstanceWeight())
+
+	// A failing load source falls back to the base weight.
+	plugin.SetLoadSource(func() (float64, error) { return 0, errors.New("no load") })
+	require.NoError(t, plugin.applyInstanceWeight(context.Background()))
+	assert.Equal(t, 200, plugin.GetInstanceWeight())
+}